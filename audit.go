@@ -0,0 +1,107 @@
+package pricing_db
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+)
+
+// ProviderAudit summarizes how completely a provider's config is filled in,
+// to drive config completeness over time. Counts are of the provider's own
+// (bare-named) entries; self-hosted models are excluded from the caching and
+// batch counts, which do not apply to them.
+type ProviderAudit struct {
+	Provider string
+	File     string // Config file name, e.g. "openai_pricing.json"
+
+	Models            int // Token-priced models
+	WithTiers         int // Models with volume/context tiers
+	WithCaching       int // Models with a cache_read_multiplier (their own or the provider default)
+	WithBatch         int // Models with a batch_multiplier
+	WithContextWindow int // Models with context_window metadata
+	ImageModels       int
+	RerankModels      int
+	InstanceTypes     int
+
+	// Gaps lists missing fields, provider-level gaps first, then by model name.
+	// Not every gap is an error (a provider may have no batch API), but each is
+	// worth confirming against the provider's pricing page.
+	Gaps []AuditGap
+}
+
+// AuditGap is a field missing from a provider's config. Model is empty for
+// provider-level fields such as "metadata.updated".
+type AuditGap struct {
+	Model string
+	Field string
+}
+
+// String formats the gap as "<model>: missing <field>", or "missing <field>"
+// for provider-level gaps.
+func (g AuditGap) String() string {
+	if g.Model == "" {
+		return "missing " + g.Field
+	}
+	return fmt.Sprintf("%s: missing %s", g.Model, g.Field)
+}
+
+// Audit reports, per provider and sorted by provider name, how many models set
+// tiers, caching, batch multipliers and metadata, and which fields are missing.
+func (p *Pricer) Audit() []ProviderAudit {
+	c := p.load()
+
+	audits := make([]ProviderAudit, 0, len(c.providers))
+	for name, pp := range c.providers {
+		audits = append(audits, auditProvider(name, pp))
+	}
+	sort.Slice(audits, func(i, j int) bool {
+		return audits[i].Provider < audits[j].Provider
+	})
+	return audits
+}
+
+// auditProvider builds the ProviderAudit for one provider.
+func auditProvider(name string, pp ProviderPricing) ProviderAudit {
+	a := ProviderAudit{
+		Provider:      name,
+		File:          pp.SourceFile,
+		Models:        len(pp.Models),
+		ImageModels:   len(pp.ImageModels),
+		RerankModels:  len(pp.RerankModels),
+		InstanceTypes: len(pp.InstanceTypes),
+	}
+
+	if _, ok := pp.Metadata.UpdatedTime(); !ok {
+		a.Gaps = append(a.Gaps, AuditGap{Field: "metadata.updated"})
+	}
+	if len(pp.Metadata.SourceURLs) == 0 && pp.Metadata.Source == "" {
+		a.Gaps = append(a.Gaps, AuditGap{Field: "metadata.source_urls"})
+	}
+
+	for _, model := range slices.Sorted(maps.Keys(pp.Models)) {
+		pricing := pp.Models[model]
+		if len(pricing.Tiers) > 0 {
+			a.WithTiers++
+		}
+		if pricing.ContextWindow > 0 {
+			a.WithContextWindow++
+		} else {
+			a.Gaps = append(a.Gaps, AuditGap{model, "context_window"})
+		}
+		if _, selfHosted := pp.SelfHostedModels[model]; selfHosted {
+			continue
+		}
+		if pricing.CacheReadMultiplier > 0 {
+			a.WithCaching++
+		} else {
+			a.Gaps = append(a.Gaps, AuditGap{model, "cache_read_multiplier"})
+		}
+		if pricing.BatchMultiplier > 0 {
+			a.WithBatch++
+		} else {
+			a.Gaps = append(a.Gaps, AuditGap{model, "batch_multiplier"})
+		}
+	}
+	return a
+}
@@ -0,0 +1,89 @@
+package pricing_db
+
+import (
+	"slices"
+	"testing"
+	"testing/fstest"
+)
+
+// =============================================================================
+// Config Completeness Audit Tests
+// =============================================================================
+
+func TestAudit(t *testing.T) {
+	fsys := fstest.MapFS{
+		"configs/acme_pricing.json": &fstest.MapFile{Data: []byte(`{
+			"provider": "acme",
+			"models": {
+				"acme-large": {
+					"input_per_million": 2, "output_per_million": 8,
+					"cache_read_multiplier": 0.1, "batch_multiplier": 0.5, "context_window": 200000,
+					"tiers": [{"threshold_tokens": 128000, "input_per_million": 4, "output_per_million": 16}]
+				},
+				"acme-small": {"input_per_million": 0.2, "output_per_million": 0.8}
+			},
+			"image_models": {"acme-img": {"price_per_image": 0.02}},
+			"metadata": {"updated": "2026-09-01", "source_urls": ["https://acme.example/pricing"]}
+		}`)},
+		"configs/bare_pricing.json": &fstest.MapFile{Data: []byte(`{
+			"provider": "bare",
+			"default_cache_read_multiplier": 0.25,
+			"models": {"bare-model": {"input_per_million": 1, "output_per_million": 1, "context_window": 32000}}
+		}`)},
+	}
+	p, err := NewPricerFromFS(fsys, "configs")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	audits := p.Audit()
+	if len(audits) != 2 || audits[0].Provider != "acme" || audits[1].Provider != "bare" {
+		t.Fatalf("expected audits for acme and bare in order, got %+v", audits)
+	}
+
+	acme := audits[0]
+	if acme.File != "acme_pricing.json" || acme.Models != 2 || acme.WithTiers != 1 || acme.WithCaching != 1 ||
+		acme.WithBatch != 1 || acme.WithContextWindow != 1 || acme.ImageModels != 1 {
+		t.Errorf("unexpected acme counts: %+v", acme)
+	}
+	wantGaps := []AuditGap{
+		{"acme-small", "context_window"},
+		{"acme-small", "cache_read_multiplier"},
+		{"acme-small", "batch_multiplier"},
+	}
+	if !slices.Equal(acme.Gaps, wantGaps) {
+		t.Errorf("expected acme gaps %v, got %v", wantGaps, acme.Gaps)
+	}
+
+	// The provider default cache multiplier counts as caching
+	bare := audits[1]
+	if bare.WithCaching != 1 {
+		t.Errorf("expected provider default to count as caching, got %+v", bare)
+	}
+	wantGaps = []AuditGap{
+		{Field: "metadata.updated"},
+		{Field: "metadata.source_urls"},
+		{"bare-model", "batch_multiplier"},
+	}
+	if !slices.Equal(bare.Gaps, wantGaps) {
+		t.Errorf("expected bare gaps %v, got %v", wantGaps, bare.Gaps)
+	}
+	if got := bare.Gaps[0].String(); got != "missing metadata.updated" {
+		t.Errorf("unexpected provider gap string %q", got)
+	}
+	if got := bare.Gaps[2].String(); got != "bare-model: missing batch_multiplier" {
+		t.Errorf("unexpected model gap string %q", got)
+	}
+}
+
+func TestAudit_EmbeddedConfigs(t *testing.T) {
+	audits := defaultPricer().Audit()
+	if len(audits) != len(defaultPricer().ProviderSources()) {
+		t.Fatalf("expected one audit per provider, got %d", len(audits))
+	}
+	for _, a := range audits {
+		if a.WithCaching > a.Models || a.WithBatch > a.Models || a.WithTiers > a.Models {
+			t.Errorf("%s: counts exceed model count: %+v", a.Provider, a)
+		}
+	}
+}
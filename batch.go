@@ -0,0 +1,82 @@
+package pricing_db
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// UsageRecord is one request to price with CalculateBatchUsage.
+type UsageRecord struct {
+	Model   string
+	Usage   TokenUsage
+	Options *CalculateOptions // nil for standard pricing
+	At      time.Time         // When the request was made; zero prices at current rates
+}
+
+// CalculateBatchUsage prices records in order, returning one CostDetails per
+// record (identical to calling CalculateUsage for each). The whole batch is
+// priced against a single catalog, even if Reload runs concurrently, and each
+// distinct model name is resolved once, so backfills dominated by a handful of
+// versioned names skip repeated prefix matching.
+func (p *Pricer) CalculateBatchUsage(records []UsageRecord) []CostDetails {
+	c := p.load()
+	return c.calculateBatchTraced(context.Background(), records)
+}
+
+// calculateBatchTraced prices records inside a "pricing.CalculateBatchUsage"
+// span parented by ctx.
+func (c *catalog) calculateBatchTraced(ctx context.Context, records []UsageRecord) []CostDetails {
+	results := make([]CostDetails, len(records))
+	span := c.startBatchSpan(ctx, len(records))
+	c.calculateBatch(records, results)
+	endBatchSpan(span, results)
+	return results
+}
+
+// CalculateBatchUsageParallel is CalculateBatchUsage split across workers
+// goroutines (runtime.GOMAXPROCS when workers <= 0). Results keep the
+// order of records.
+func (p *Pricer) CalculateBatchUsageParallel(records []UsageRecord, workers int) []CostDetails {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	workers = min(workers, len(records))
+
+	results := make([]CostDetails, len(records))
+	c := p.load()
+	span := c.startBatchSpan(context.Background(), len(records))
+	defer endBatchSpan(span, results)
+	if workers <= 1 {
+		c.calculateBatch(records, results)
+		return results
+	}
+
+	chunk := (len(records) + workers - 1) / workers
+	var wg sync.WaitGroup
+	for start := 0; start < len(records); start += chunk {
+		end := min(start+chunk, len(records))
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c.calculateBatch(records[start:end], results[start:end])
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+// calculateBatch prices records into results (same length), caching
+// model resolution for the duration of the call.
+func (c *catalog) calculateBatch(records []UsageRecord, results []CostDetails) {
+	resolved := make(map[string]*modelRates)
+	for i, rec := range records {
+		rates, seen := resolved[rec.Model]
+		if !seen {
+			rates, _ = c.lookupRates(rec.Model)
+			resolved[rec.Model] = rates
+		}
+		c.calculateUsageInto(&results[i], rates.at(rec.At), rec.Model, rec.Usage, rec.Options)
+	}
+}
@@ -0,0 +1,86 @@
+package pricing_db
+
+import (
+	"fmt"
+	"testing"
+)
+
+// =============================================================================
+// Batch Calculation Tests
+// =============================================================================
+
+func batchRecords(n int) []UsageRecord {
+	models := []string{"gpt-4o-2024-08-06", "gemini-2.5-pro", "claude-sonnet-4-20250514", "unknown-model"}
+	records := make([]UsageRecord, n)
+	for i := range records {
+		records[i] = UsageRecord{
+			Model: models[i%len(models)],
+			Usage: TokenUsage{
+				PromptTokens:     int64(1000 * (i + 1)),
+				CompletionTokens: int64(100 * (i + 1)),
+				CachedTokens:     int64(500 * i),
+			},
+		}
+		if i%3 == 0 {
+			records[i].Options = &CalculateOptions{BatchMode: true}
+		}
+	}
+	return records
+}
+
+func TestCalculateBatchUsage_MatchesCalculateUsage(t *testing.T) {
+	p, err := NewPricer()
+	if err != nil {
+		t.Fatalf("NewPricer failed: %v", err)
+	}
+
+	records := batchRecords(50)
+	results := p.CalculateBatchUsage(records)
+	if len(results) != len(records) {
+		t.Fatalf("expected %d results, got %d", len(records), len(results))
+	}
+	for i, rec := range records {
+		want := p.CalculateUsage(rec.Model, rec.Usage, rec.Options)
+		got := results[i]
+		if got.TotalCost != want.TotalCost || got.Unknown != want.Unknown || got.TierApplied != want.TierApplied || len(got.Warnings) != len(want.Warnings) {
+			t.Errorf("record %d (%s): got %+v, want %+v", i, rec.Model, got, want)
+		}
+	}
+}
+
+func TestCalculateBatchUsageParallel(t *testing.T) {
+	p, err := NewPricer()
+	if err != nil {
+		t.Fatalf("NewPricer failed: %v", err)
+	}
+
+	records := batchRecords(101)
+	want := p.CalculateBatchUsage(records)
+	for _, workers := range []int{0, 1, 3, 8, 500} {
+		t.Run(fmt.Sprintf("workers=%d", workers), func(t *testing.T) {
+			got := p.CalculateBatchUsageParallel(records, workers)
+			if len(got) != len(want) {
+				t.Fatalf("expected %d results, got %d", len(want), len(got))
+			}
+			for i := range want {
+				if got[i].TotalCost != want[i].TotalCost || got[i].Unknown != want[i].Unknown {
+					t.Errorf("record %d: got $%f, want $%f", i, got[i].TotalCost, want[i].TotalCost)
+				}
+			}
+		})
+	}
+}
+
+func TestCalculateBatchUsage_Empty(t *testing.T) {
+	p, err := NewPricer()
+	if err != nil {
+		t.Fatalf("NewPricer failed: %v", err)
+	}
+
+	if got := p.CalculateBatchUsage(nil); len(got) != 0 {
+		t.Errorf("expected no results, got %d", len(got))
+	}
+	if got := p.CalculateBatchUsageParallel(nil, 4); len(got) != 0 {
+		t.Errorf("expected no results, got %d", len(got))
+	}
+}
@@ -112,8 +112,66 @@ func BenchmarkCalculateGeminiUsage(b *testing.B) {
 	}
 }
 
+// BenchmarkCalculateWithOptions_Tiered measures a long-context request that
+// lands in a higher pricing tier (tier name is precompiled, not formatted per call).
+func BenchmarkCalculateWithOptions_Tiered(b *testing.B) {
+	p, err := NewPricer()
+	if err != nil {
+		b.Fatalf("NewPricer failed: %v", err)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = p.CalculateWithOptions("gemini-2.5-pro", 300000, 5000, 0, nil)
+	}
+}
+
+// BenchmarkCalculateUsageInto measures the allocation-free path with a reused result.
+func BenchmarkCalculateUsageInto(b *testing.B) {
+	p, err := NewPricer()
+	if err != nil {
+		b.Fatalf("NewPricer failed: %v", err)
+	}
+
+	usage := TokenUsage{
+		PromptTokens:     50000,
+		CompletionTokens: 10000,
+		CachedTokens:     20000,
+		ToolUseTokens:    5000,
+		ThinkingTokens:   3000,
+		GroundingQueries: 10,
+	}
+	opts := &CalculateOptions{BatchMode: true}
+	var dst CostDetails
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		p.CalculateUsageInto(&dst, "gemini-2.5-flash", usage, opts)
+	}
+}
+
+// BenchmarkCalculateBatchUsage measures pricing 1,000 records that share a few
+// versioned (prefix-matched) model names.
+func BenchmarkCalculateBatchUsage(b *testing.B) {
+	p, err := NewPricer()
+	if err != nil {
+		b.Fatalf("NewPricer failed: %v", err)
+	}
+
+	records := make([]UsageRecord, 1000)
+	for i := range records {
+		records[i] = UsageRecord{Model: "gpt-4o-2024-08-06", Usage: TokenUsage{PromptTokens: 1000, CompletionTokens: 500}}
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = p.CalculateBatchUsage(records)
+	}
+}
+
 // BenchmarkCalculate_Parallel measures concurrent read performance.
-// The Pricer uses RWMutex so reads should scale well.
+// Reads are lock-free, so they should scale with GOMAXPROCS.
 func BenchmarkCalculate_Parallel(b *testing.B) {
 	p, err := NewPricer()
 	if err != nil {
@@ -0,0 +1,106 @@
+package pricing_db
+
+import (
+	"sort"
+	"time"
+)
+
+// BillingPeriod is a provider invoice period: a calendar month in UTC, so
+// monthly totals line up with provider invoices whatever the local time zone.
+type BillingPeriod struct {
+	Start time.Time // First instant of the month, UTC
+	End   time.Time // First instant of the next month, UTC (exclusive)
+}
+
+// BillingPeriodOf returns the billing period containing t.
+func BillingPeriodOf(t time.Time) BillingPeriod {
+	t = t.UTC()
+	start := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	return BillingPeriod{Start: start, End: start.AddDate(0, 1, 0)}
+}
+
+// Contains reports whether t falls within the period.
+func (bp BillingPeriod) Contains(t time.Time) bool {
+	return !t.Before(bp.Start) && t.Before(bp.End)
+}
+
+// Next returns the following billing period.
+func (bp BillingPeriod) Next() BillingPeriod {
+	return BillingPeriodOf(bp.End)
+}
+
+// String returns the period as "YYYY-MM".
+func (bp BillingPeriod) String() string {
+	return bp.Start.Format("2006-01")
+}
+
+// CalculateAt is CalculateUsage using the model's prices in effect at the given
+// time, per its price_history. Timestamps are compared as instants, so callers
+// may pass times in any location.
+func (p *Pricer) CalculateAt(model string, at time.Time, usage TokenUsage, opts *CalculateOptions) CostDetails {
+	c := p.load()
+	rates, _ := c.lookupRates(model)
+
+	var details CostDetails
+	c.calculateUsageInto(&details, rates.at(at), model, usage, opts)
+	return details
+}
+
+// PeriodCost is the total cost of the requests in one billing period.
+type PeriodCost struct {
+	Period    BillingPeriod
+	Requests  int
+	RawTotal  float64 // Unrounded sum of the requests' RawTotals
+	TotalCost float64 // RawTotal rounded once by the Pricer's RoundingPolicy
+	Unknown   int     // Requests for models not in the pricing data
+
+	Markup      float64 // Unrounded sum of the requests' markups (WithMarkup)
+	BilledTotal float64 // RawTotal + Markup, rounded once
+	Tax         float64 // Unrounded sum of the requests' tax (WithTaxRate)
+	GrossTotal  float64 // RawTotal + Markup + Tax, rounded once
+}
+
+// CostsByBillingPeriod prices records at their own timestamps (UsageRecord.At)
+// and sums them per billing period, oldest first. Records without a timestamp
+// cannot be placed in a period and are skipped.
+func (p *Pricer) CostsByBillingPeriod(records []UsageRecord) []PeriodCost {
+	c := p.load()
+
+	dated := make([]UsageRecord, 0, len(records))
+	for _, rec := range records {
+		if !rec.At.IsZero() {
+			dated = append(dated, rec)
+		}
+	}
+	results := make([]CostDetails, len(dated))
+	c.calculateBatch(dated, results)
+
+	byPeriod := make(map[time.Time]*PeriodCost)
+	for i, rec := range dated {
+		period := BillingPeriodOf(rec.At)
+		pc, ok := byPeriod[period.Start]
+		if !ok {
+			pc = &PeriodCost{Period: period}
+			byPeriod[period.Start] = pc
+		}
+		pc.Requests++
+		pc.RawTotal += results[i].RawTotal
+		pc.Markup += results[i].Markup
+		pc.Tax += results[i].Tax
+		if results[i].Unknown {
+			pc.Unknown++
+		}
+	}
+
+	periods := make([]PeriodCost, 0, len(byPeriod))
+	for _, pc := range byPeriod {
+		pc.TotalCost = c.rounding.round(pc.RawTotal)
+		pc.BilledTotal = c.rounding.round(pc.RawTotal + pc.Markup)
+		pc.GrossTotal = c.rounding.round(pc.RawTotal + pc.Markup + pc.Tax)
+		periods = append(periods, *pc)
+	}
+	sort.Slice(periods, func(i, j int) bool {
+		return periods[i].Period.Start.Before(periods[j].Period.Start)
+	})
+	return periods
+}
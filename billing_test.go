@@ -0,0 +1,149 @@
+package pricing_db
+
+import (
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+// =============================================================================
+// Billing Period and CalculateAt Tests
+// =============================================================================
+
+var historyTestConfigs = map[string]string{
+	"history_pricing.json": `{
+		"provider": "history",
+		"models": {
+			"hist-model": {
+				"input_per_million": 2.0,
+				"output_per_million": 4.0,
+				"price_history": [
+					{"until": "2026-01-01", "input_per_million": 4.0, "output_per_million": 8.0},
+					{"until": "2026-06-01", "input_per_million": 3.0, "output_per_million": 6.0}
+				]
+			}
+		}
+	}`,
+}
+
+func TestBillingPeriodOf(t *testing.T) {
+	// 23:30 on Jan 31 in New York is already February in UTC
+	ny := time.FixedZone("EST", -5*60*60)
+	bp := BillingPeriodOf(time.Date(2026, 1, 31, 23, 30, 0, 0, ny))
+	if bp.String() != "2026-02" {
+		t.Errorf("expected 2026-02, got %s", bp)
+	}
+	if !bp.Start.Equal(time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)) || !bp.End.Equal(time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected bounds %v - %v", bp.Start, bp.End)
+	}
+	if !bp.Contains(bp.Start) || bp.Contains(bp.End) {
+		t.Error("period should include Start and exclude End")
+	}
+	if next := bp.Next(); next.String() != "2026-03" {
+		t.Errorf("expected next period 2026-03, got %s", next)
+	}
+	if dec := BillingPeriodOf(time.Date(2025, 12, 15, 0, 0, 0, 0, time.UTC)).Next(); dec.String() != "2026-01" {
+		t.Errorf("expected December to roll over to 2026-01, got %s", dec)
+	}
+}
+
+func TestCalculateAt_PriceHistory(t *testing.T) {
+	p := newTestPricer(t, historyTestConfigs)
+	usage := TokenUsage{PromptTokens: 1_000_000}
+
+	tests := []struct {
+		at   time.Time
+		want float64
+	}{
+		{time.Date(2025, 12, 31, 23, 59, 0, 0, time.UTC), 4.0},
+		{time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), 3.0},
+		{time.Date(2026, 5, 31, 20, 0, 0, 0, time.FixedZone("PDT", -7*60*60)), 2.0}, // June 1 03:00 UTC
+		{time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC), 2.0},
+		{time.Time{}, 2.0},
+	}
+	for _, tc := range tests {
+		if got := p.CalculateAt("hist-model", tc.at, usage, nil); !floatEquals(got.TotalCost, tc.want) {
+			t.Errorf("at %v: expected $%.2f, got $%f", tc.at, tc.want, got.TotalCost)
+		}
+	}
+
+	if got := p.CalculateAt("unknown-model", time.Now(), usage, nil); !got.Unknown {
+		t.Error("expected Unknown for an unknown model")
+	}
+}
+
+func TestCostsByBillingPeriod(t *testing.T) {
+	p := newTestPricer(t, historyTestConfigs)
+	usage := TokenUsage{PromptTokens: 1_000_000}
+
+	records := []UsageRecord{
+		{Model: "hist-model", Usage: usage, At: time.Date(2026, 2, 10, 0, 0, 0, 0, time.UTC)},
+		{Model: "hist-model", Usage: usage, At: time.Date(2025, 12, 5, 0, 0, 0, 0, time.UTC)},
+		{Model: "hist-model", Usage: usage, At: time.Date(2026, 2, 20, 0, 0, 0, 0, time.UTC)},
+		{Model: "missing-model", Usage: usage, At: time.Date(2026, 2, 21, 0, 0, 0, 0, time.UTC)},
+		{Model: "hist-model", Usage: usage}, // no timestamp: skipped
+	}
+	periods := p.CostsByBillingPeriod(records)
+	if len(periods) != 2 {
+		t.Fatalf("expected 2 periods, got %d: %+v", len(periods), periods)
+	}
+	if periods[0].Period.String() != "2025-12" || periods[0].Requests != 1 || !floatEquals(periods[0].TotalCost, 4.0) {
+		t.Errorf("unexpected December totals: %+v", periods[0])
+	}
+	if periods[1].Period.String() != "2026-02" || periods[1].Requests != 3 || periods[1].Unknown != 1 || !floatEquals(periods[1].TotalCost, 6.0) {
+		t.Errorf("unexpected February totals: %+v", periods[1])
+	}
+}
+
+func TestCostsByBillingPeriod_MarkupAndTax(t *testing.T) {
+	p, err := NewPricerFromFS(overlayFS(), "configs", WithMarkup(Markup{Percent: 20}), WithTaxRate(0.1))
+	if err != nil {
+		t.Fatalf("NewPricerFromFS failed: %v", err)
+	}
+	at := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	records := []UsageRecord{
+		{Model: "large", Usage: TokenUsage{PromptTokens: 1_000_000}, At: at},
+		{Model: "large", Usage: TokenUsage{PromptTokens: 1_000_000}, At: at.Add(time.Hour)},
+	}
+	periods := p.CostsByBillingPeriod(records)
+	if len(periods) != 1 {
+		t.Fatalf("expected 1 period, got %+v", periods)
+	}
+	// $20 raw, 20% markup, 10% tax on the marked-up $24
+	pc := periods[0]
+	if !floatEquals(pc.TotalCost, 20) || !floatEquals(pc.Markup, 4) || !floatEquals(pc.BilledTotal, 24) ||
+		!floatEquals(pc.Tax, 2.4) || !floatEquals(pc.GrossTotal, 26.4) {
+		t.Errorf("unexpected totals: %+v", pc)
+	}
+}
+
+func TestCalculateBatchUsage_At(t *testing.T) {
+	p := newTestPricer(t, historyTestConfigs)
+	results := p.CalculateBatchUsage([]UsageRecord{
+		{Model: "hist-model", Usage: TokenUsage{CompletionTokens: 1_000_000}, At: time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)},
+		{Model: "hist-model", Usage: TokenUsage{CompletionTokens: 1_000_000}},
+	})
+	if !floatEquals(results[0].TotalCost, 8.0) || !floatEquals(results[1].TotalCost, 4.0) {
+		t.Errorf("expected $8 historical and $4 current, got $%f and $%f", results[0].TotalCost, results[1].TotalCost)
+	}
+}
+
+func TestNewPricerFromFS_InvalidPriceHistory(t *testing.T) {
+	tests := map[string]string{
+		"bad date":       `[{"until": "2026-13-01", "input_per_million": 1, "output_per_million": 1}]`,
+		"not ascending":  `[{"until": "2026-06-01", "input_per_million": 1, "output_per_million": 1}, {"until": "2026-01-01", "input_per_million": 1, "output_per_million": 1}]`,
+		"negative price": `[{"until": "2026-01-01", "input_per_million": -1, "output_per_million": 1}]`,
+		"tier input":     `[{"until": "2026-01-01", "input_per_million": 1, "output_per_million": 1, "tiers": [{"threshold_tokens": 1000, "input_per_million": 20000, "output_per_million": 1}]}]`,
+		"tier output":    `[{"until": "2026-01-01", "input_per_million": 1, "output_per_million": 1, "tiers": [{"threshold_tokens": 1000, "input_per_million": 1, "output_per_million": 20000}]}]`,
+	}
+	for name, history := range tests {
+		fsys := fstest.MapFS{
+			"configs/bad_pricing.json": &fstest.MapFile{Data: []byte(`{
+				"models": {"bad-model": {"input_per_million": 1.0, "output_per_million": 1.0, "price_history": ` + history + `}}
+			}`)},
+		}
+		if _, err := NewPricerFromFS(fsys, "configs"); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
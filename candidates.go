@@ -0,0 +1,88 @@
+package pricing_db
+
+// GeminiResponseCost is the cost of a Gemini response with a per-candidate breakdown,
+// for requests with candidateCount > 1.
+type GeminiResponseCost struct {
+	Total      CostDetails   // Same as CalculateGeminiResponse
+	Candidates []CostDetails // One per candidate, in response order; RawTotals sum to Total.RawTotal
+}
+
+// CalculateGeminiResponseCandidates calculates a Gemini response's cost and
+// attributes it to each candidate so N-sample experiments can price each sample:
+//   - Output cost is split by each candidate's tokenCount. Older responses that
+//     omit tokenCount split output evenly.
+//   - Grounding cost is split by each candidate's own web search queries.
+//   - Prompt (input, cached, image) and thinking costs, the batch discount and any
+//     minimum charge are shared evenly, since the prompt is billed once per request.
+//
+// Warnings are reported on Total only. If modelOverride is non-empty, it is used
+// instead of resp.ModelVersion.
+func (p *Pricer) CalculateGeminiResponseCandidates(resp GeminiResponse, modelOverride string, opts *CalculateOptions) GeminiResponseCost {
+	c := p.load()
+	model := resp.model(modelOverride)
+
+	var tokenCounts int64
+	groundingQueries := 0
+	allCounted := true
+	for _, candidate := range resp.Candidates {
+		tokenCounts += max(candidate.TokenCount, 0)
+		groundingQueries += candidate.webSearchQueryCount()
+		if candidate.TokenCount <= 0 {
+			allCounted = false
+		}
+	}
+
+	var result GeminiResponseCost
+	rates, _ := c.lookupRates(model)
+	c.calculateUsageInto(&result.Total, rates, model, geminiTokenUsage(resp.UsageMetadata, groundingQueries), opts)
+	if w, ok := resp.UsageMetadata.totalMismatchWarning(); ok {
+		addWarning(&result.Total, w.Code, w.Message)
+	}
+	if len(resp.Candidates) == 0 {
+		return result
+	}
+
+	total := result.Total
+	evenShare := 1 / float64(len(resp.Candidates))
+	result.Candidates = make([]CostDetails, len(resp.Candidates))
+	for i, candidate := range resp.Candidates {
+		outputShare := evenShare
+		if allCounted && tokenCounts > 0 {
+			outputShare = float64(candidate.TokenCount) / float64(tokenCounts)
+		}
+		var groundingShare float64
+		if groundingQueries > 0 {
+			groundingShare = float64(candidate.webSearchQueryCount()) / float64(groundingQueries)
+		}
+
+		d := CostDetails{
+			StandardInputCost: total.StandardInputCost * evenShare,
+			CachedInputCost:   total.CachedInputCost * evenShare,
+			CacheWriteCost:    total.CacheWriteCost * evenShare,
+			SurchargeCost:     total.SurchargeCost * evenShare,
+			ImageInputCost:    total.ImageInputCost * evenShare,
+			AudioInputCost:    total.AudioInputCost * evenShare,
+			OutputCost:        total.OutputCost * outputShare,
+			AudioOutputCost:   total.AudioOutputCost * outputShare,
+			ThinkingCost:      total.ThinkingCost * evenShare,
+			GroundingCost:     total.GroundingCost * groundingShare,
+			TierApplied:       total.TierApplied,
+			BatchDiscount:     total.BatchDiscount * evenShare,
+			MinimumCharge:     total.MinimumCharge * evenShare,
+			BatchMode:         total.BatchMode,
+			Unknown:           total.Unknown,
+			PricingVersion:    total.PricingVersion,
+			CalculatedAt:      total.CalculatedAt,
+		}
+		d.RawTotal = d.StandardInputCost + d.CachedInputCost + d.CacheWriteCost + d.ImageInputCost + d.AudioInputCost +
+			d.OutputCost + d.AudioOutputCost + d.ThinkingCost + d.GroundingCost + d.SurchargeCost + d.MinimumCharge
+		d.TotalCost = c.rounding.round(d.RawTotal)
+		if total.RawTotal > 0 {
+			d.Markup = total.Markup * d.RawTotal / total.RawTotal
+		}
+		d.BilledTotal = c.rounding.round(d.RawTotal + d.Markup)
+		c.applyTax(&d)
+		result.Candidates[i] = d
+	}
+	return result
+}
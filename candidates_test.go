@@ -0,0 +1,96 @@
+package pricing_db
+
+import "testing"
+
+// =============================================================================
+// Per-Candidate Gemini Cost Attribution Tests
+// =============================================================================
+
+const multiCandidateResponse = `{
+	"modelVersion": "gemini-2.5-flash",
+	"candidates": [
+		{"index": 0, "tokenCount": 300, "groundingMetadata": {"webSearchQueries": ["a", "b", "c"]}},
+		{"index": 1, "tokenCount": 100, "groundingMetadata": {"webSearchQueries": ["d"]}}
+	],
+	"usageMetadata": {
+		"promptTokenCount": 1000,
+		"candidatesTokenCount": 400,
+		"thoughtsTokenCount": 200
+	}
+}`
+
+func TestCalculateGeminiResponseCandidates(t *testing.T) {
+	got, err := ParseGeminiResponseCandidates([]byte(multiCandidateResponse), nil)
+	if err != nil {
+		t.Fatalf("ParseGeminiResponseCandidates failed: %v", err)
+	}
+	want, err := ParseGeminiResponse([]byte(multiCandidateResponse))
+	if err != nil {
+		t.Fatalf("ParseGeminiResponse failed: %v", err)
+	}
+
+	if !floatEquals(got.Total.TotalCost, want.TotalCost) {
+		t.Errorf("Total.TotalCost = %f, want %f (same as ParseGeminiResponse)", got.Total.TotalCost, want.TotalCost)
+	}
+	if len(got.Candidates) != 2 {
+		t.Fatalf("expected 2 candidates, got %d", len(got.Candidates))
+	}
+
+	first, second := got.Candidates[0], got.Candidates[1]
+	// Output split 3:1 by tokenCount
+	if !floatEquals(first.OutputCost, got.Total.OutputCost*0.75) || !floatEquals(second.OutputCost, got.Total.OutputCost*0.25) {
+		t.Errorf("output split = %f/%f, want 3:1 of %f", first.OutputCost, second.OutputCost, got.Total.OutputCost)
+	}
+	// Grounding split 3:1 by each candidate's queries
+	if !floatEquals(first.GroundingCost, got.Total.GroundingCost*0.75) {
+		t.Errorf("first GroundingCost = %f, want 3/4 of %f", first.GroundingCost, got.Total.GroundingCost)
+	}
+	// Prompt and thinking shared evenly
+	if !floatEquals(first.StandardInputCost, second.StandardInputCost) || !floatEquals(first.ThinkingCost, got.Total.ThinkingCost/2) {
+		t.Errorf("expected prompt and thinking costs split evenly, got %+v / %+v", first, second)
+	}
+	if !floatEquals(first.RawTotal+second.RawTotal, got.Total.RawTotal) {
+		t.Errorf("candidate RawTotals sum to %f, want %f", first.RawTotal+second.RawTotal, got.Total.RawTotal)
+	}
+}
+
+func TestCalculateGeminiResponseCandidates_NoTokenCounts(t *testing.T) {
+	p, err := NewPricer()
+	if err != nil {
+		t.Fatalf("NewPricer failed: %v", err)
+	}
+
+	resp := GeminiResponse{
+		ModelVersion:  "gemini-2.5-flash",
+		Candidates:    []GeminiCandidate{{}, {}, {}, {}},
+		UsageMetadata: GeminiUsageMetadata{PromptTokenCount: 1000, CandidatesTokenCount: 800},
+	}
+	got := p.CalculateGeminiResponseCandidates(resp, "", nil)
+	for i, d := range got.Candidates {
+		if !floatEquals(d.OutputCost, got.Total.OutputCost/4) {
+			t.Errorf("candidate %d OutputCost = %f, want even share %f", i, d.OutputCost, got.Total.OutputCost/4)
+		}
+	}
+}
+
+func TestCalculateGeminiResponseCandidates_NoCandidates(t *testing.T) {
+	p, err := NewPricer()
+	if err != nil {
+		t.Fatalf("NewPricer failed: %v", err)
+	}
+
+	resp := GeminiResponse{UsageMetadata: GeminiUsageMetadata{PromptTokenCount: 1000}}
+	got := p.CalculateGeminiResponseCandidates(resp, "gemini-2.5-flash", nil)
+	if got.Candidates != nil {
+		t.Errorf("expected no candidate breakdown, got %d entries", len(got.Candidates))
+	}
+	if got.Total.Unknown || got.Total.TotalCost <= 0 {
+		t.Errorf("expected priced total with model override, got %+v", got.Total)
+	}
+}
+
+func TestParseGeminiResponseCandidates_InvalidJSON(t *testing.T) {
+	if _, err := ParseGeminiResponseCandidates([]byte(`{invalid`), nil); err == nil {
+		t.Error("expected error for invalid JSON")
+	}
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// commandEnv is the environment a command runs in; tests substitute buffers.
+type commandEnv struct {
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+	cfg    CLIConfig
+}
+
+// command is a pricing-cli subcommand.
+type command struct {
+	name     string
+	synopsis string // Arguments after the command name, for usage
+	summary  string
+	// setup defines the command's flags on fs and returns the action to run with
+	// the remaining positional arguments once they are parsed. The action returns
+	// the process exit code; ctx bounds its network reads.
+	setup func(fs *flag.FlagSet, env *commandEnv) func(ctx context.Context, args []string) int
+}
+
+// defaultCommand runs when the first argument is a flag or absent, so
+// `pricing-cli -f response.json` keeps working.
+const defaultCommand = "cost"
+
+// commands lists the subcommands in usage order. It is populated in init because
+// the help and completion commands refer back to it.
+var commands []command
+
+func init() {
+	commands = []command{
+		{"cost", "[-f file | -dir dir | -ndjson] [options]", "Calculate costs for LLM API JSON responses (default command)", setupCost},
+		{"models", "[-provider p1,p2] [-json]", "List priced models with per-million token rates", setupModels},
+		{"compare", "-models m1,m2 [-input N] [-output N] [-json]", "Compare the cost of the same usage across models", setupCompare},
+		{"estimate", "-model M [-f prompt.txt] [-output-tokens N] [-json]", "Estimate a prompt's cost before sending it", setupEstimate},
+		{"report", "[-dir dir | -ndjson] [options]", "Aggregate response costs by model", setupReport},
+		{"reprice", "[-f usage.ndjson] [-from path] [-to path] [-json]", "Recompute recorded usage costs under another price sheet", setupReprice},
+		{"ingest", "[-f usage.csv] [-o priced.csv] [-columns field=col,...] [-json]", "Price usage records exported from a warehouse as CSV", setupIngest},
+		{"litellm", "[-import prices.json [-dir dir] [-missing]]", "Export the catalog in LiteLLM's price format, or import LiteLLM prices as configs", setupLiteLLM},
+		{"rates", "[-format csv|ndjson] [-pricing path]", "Export the catalog as a flat rate table for warehouse joins", setupRates},
+		{"serve", "[-addr :8080]", "Serve cost calculations over HTTP", setupServe},
+		{"validate", "[dir]", "Validate pricing config files (default dir: configs)", setupValidate},
+		{"freshness", "[-max-age-days N] [-json]", "List each provider's last-updated date and sources", setupFreshness},
+		{"schema", "", "Print the JSON Schema for *_pricing.json files", setupSchema},
+		{"completion", "bash|zsh|fish", "Print a shell completion script", setupCompletion},
+		{"help", "[command]", "Show help for a command", setupHelp},
+	}
+}
+
+// findCommand returns the command with the given name.
+func findCommand(name string) (command, bool) {
+	for _, c := range commands {
+		if c.name == name {
+			return c, true
+		}
+	}
+	return command{}, false
+}
+
+// newFlagSet returns c's flag set with its usage message written to w.
+func (c command) newFlagSet(env *commandEnv) (*flag.FlagSet, func(ctx context.Context, args []string) int) {
+	fs := flag.NewFlagSet("pricing-cli "+c.name, flag.ContinueOnError)
+	fs.SetOutput(env.stderr)
+	action := c.setup(fs, env)
+	fs.Usage = func() {
+		fmt.Fprintf(env.stderr, "Usage: pricing-cli %s %s\n\n%s.\n", c.name, c.synopsis, c.summary)
+		if hasFlags(fs) {
+			fmt.Fprintf(env.stderr, "\nOptions:\n")
+			fs.PrintDefaults()
+		}
+		if c.name == defaultCommand {
+			printCostHelp(env.stderr)
+		}
+	}
+	return fs, action
+}
+
+// hasFlags reports whether fs defines any flags.
+func hasFlags(fs *flag.FlagSet) bool {
+	found := false
+	fs.VisitAll(func(*flag.Flag) { found = true })
+	return found
+}
+
+// run dispatches args (without the program name) to a command and returns the exit code.
+func run(ctx context.Context, env *commandEnv, args []string) int {
+	name := defaultCommand
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		name, args = args[0], args[1:]
+	}
+	c, ok := findCommand(name)
+	if !ok {
+		fmt.Fprintf(env.stderr, "pricing-cli: unknown command %q\n\n", name)
+		printUsage(env.stderr)
+		return exitError
+	}
+
+	fs, action := c.newFlagSet(env)
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitError
+	}
+	return action(ctx, fs.Args())
+}
+
+// printUsage writes the top-level usage message listing all commands.
+func printUsage(w io.Writer) {
+	fmt.Fprintf(w, "Usage: pricing-cli <command> [options]\n")
+	fmt.Fprintf(w, "       pricing-cli [options]   (runs %q)\n\n", defaultCommand)
+	fmt.Fprintf(w, "Commands:\n")
+	for _, c := range commands {
+		fmt.Fprintf(w, "  %-11s %s\n", c.name, c.summary)
+	}
+	fmt.Fprintf(w, "\nRun 'pricing-cli help <command>' for a command's options.\n")
+}
+
+// commandError prints err prefixed with the command name and returns code.
+func commandError(env *commandEnv, name string, err error, code int) int {
+	fmt.Fprintf(env.stderr, "pricing-cli %s: %v\n", name, err)
+	return code
+}
+
+func setupHelp(fs *flag.FlagSet, env *commandEnv) func(ctx context.Context, args []string) int {
+	return func(ctx context.Context, args []string) int {
+		if len(args) == 0 {
+			printUsage(env.stdout)
+			return exitOK
+		}
+		c, ok := findCommand(args[0])
+		if !ok {
+			return commandError(env, "help", fmt.Errorf("unknown command %q", args[0]), exitError)
+		}
+		cfs, _ := c.newFlagSet(&commandEnv{stdout: env.stdout, stderr: env.stdout, cfg: env.cfg})
+		cfs.Usage()
+		return exitOK
+	}
+}
+
+func setupSchema(fs *flag.FlagSet, env *commandEnv) func(ctx context.Context, args []string) int {
+	return func(ctx context.Context, args []string) int {
+		if err := runSchema(env.stdout); err != nil {
+			return commandError(env, "schema", err, exitError)
+		}
+		return exitOK
+	}
+}
@@ -0,0 +1,568 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	pricing "github.com/ai8future/pricing_db"
+	"github.com/ai8future/pricing_db/parquetio"
+)
+
+// =============================================================================
+// Dispatch
+// =============================================================================
+
+func TestRun_DefaultCommand(t *testing.T) {
+	input := `{"modelVersion": "gemini-2.5-flash", "usageMetadata": {"promptTokenCount": 1000, "candidatesTokenCount": 500}}`
+	for _, args := range [][]string{{"-batch"}, {"cost", "-batch"}} {
+		output, stderr, code := runCLI(t, input, args...)
+		if code != exitOK {
+			t.Fatalf("%v exited %d: %s", args, code, stderr)
+		}
+		var result OutputJSON
+		if err := json.Unmarshal([]byte(output), &result); err != nil {
+			t.Fatalf("%v: output is not valid JSON: %v", args, err)
+		}
+		if !result.BatchMode || result.TotalCost <= 0 || result.PricingVersion != pricing.CatalogVersion() {
+			t.Errorf("%v: unexpected result %+v", args, result)
+		}
+	}
+}
+
+func TestRun_UnknownCommand(t *testing.T) {
+	_, stderr, code := runCLI(t, "", "bogus")
+	if code != exitError {
+		t.Errorf("expected exit %d, got %d", exitError, code)
+	}
+	if !strings.Contains(stderr, `unknown command "bogus"`) || !strings.Contains(stderr, "Commands:") {
+		t.Errorf("expected error and usage on stderr, got: %s", stderr)
+	}
+}
+
+func TestRun_Help(t *testing.T) {
+	output, _, code := runCLI(t, "", "help")
+	if code != exitOK {
+		t.Fatalf("help exited %d", code)
+	}
+	for _, c := range commands {
+		if !strings.Contains(output, c.name) {
+			t.Errorf("expected %q in usage, got: %s", c.name, output)
+		}
+	}
+
+	output, _, code = runCLI(t, "", "help", "compare")
+	if code != exitOK || !strings.Contains(output, "-models") {
+		t.Errorf("expected compare flags in help (exit %d), got: %s", code, output)
+	}
+
+	if _, stderr, code := runCLI(t, "", "models", "-h"); code != exitOK || !strings.Contains(stderr, "Usage: pricing-cli models") {
+		t.Errorf("expected models usage for -h (exit %d), got: %s", code, stderr)
+	}
+}
+
+// =============================================================================
+// models and compare
+// =============================================================================
+
+func TestModels(t *testing.T) {
+	output, stderr, code := runCLI(t, "", "models", "-provider", "openai", "-json")
+	if code != exitOK {
+		t.Fatalf("models exited %d: %s", code, stderr)
+	}
+	var entries []ModelJSON
+	if err := json.Unmarshal([]byte(output), &entries); err != nil {
+		t.Fatalf("output is not valid JSON: %v", err)
+	}
+	if len(entries) == 0 {
+		t.Fatal("expected openai models")
+	}
+	for _, e := range entries {
+		if e.Provider != "openai" {
+			t.Errorf("expected only openai models, got %+v", e)
+		}
+	}
+
+	output, _, _ = runCLI(t, "", "models")
+	if !strings.Contains(output, "PROVIDER") || !strings.Contains(output, "gpt-4o") {
+		t.Errorf("unexpected table output: %s", output)
+	}
+
+	if _, _, code := runCLI(t, "", "models", "-provider", "no-such-provider"); code != exitError {
+		t.Errorf("expected exit %d for unknown provider, got %d", exitError, code)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	output, stderr, code := runCLI(t, "", "compare", "-models", "gpt-4o,gpt-4o-mini", "-input", "1000", "-output", "500", "-json")
+	if code != exitOK {
+		t.Fatalf("compare exited %d: %s", code, stderr)
+	}
+	var entries []CompareJSON
+	if err := json.Unmarshal([]byte(output), &entries); err != nil {
+		t.Fatalf("output is not valid JSON: %v", err)
+	}
+	if len(entries) != 2 || entries[0].Model != "gpt-4o-mini" {
+		t.Fatalf("expected gpt-4o-mini first, got %+v", entries)
+	}
+	want := pricing.CalculateUsageCost("gpt-4o", pricing.TokenUsage{PromptTokens: 1000, CompletionTokens: 500}, nil)
+	if entries[1].Cost.TotalCost != want.TotalCost {
+		t.Errorf("expected gpt-4o total %f, got %f", want.TotalCost, entries[1].Cost.TotalCost)
+	}
+
+	output, _, code = runCLI(t, "", "compare", "-models", "gpt-4o,no-such-model")
+	if code != exitUnknownModel || !strings.Contains(output, "unknown model") {
+		t.Errorf("expected exit %d and unknown row, got %d: %s", exitUnknownModel, code, output)
+	}
+
+	if _, _, code := runCLI(t, "", "compare"); code != exitError {
+		t.Errorf("expected exit %d without -models, got %d", exitError, code)
+	}
+}
+
+// =============================================================================
+// validate
+// =============================================================================
+
+func TestValidate(t *testing.T) {
+	output, stderr, code := runCLI(t, "", "validate", filepath.Join("..", "..", "configs"))
+	if code != exitOK {
+		t.Fatalf("validate exited %d: %s", code, stderr)
+	}
+	if !strings.HasPrefix(output, "ok: ") {
+		t.Errorf("unexpected output: %s", output)
+	}
+
+	dir := writeResponses(t, map[string]string{
+		"bad_pricing.json": `{"models": {"m": {"input_per_million": -1}}}`,
+	})
+	if _, stderr, code := runCLI(t, "", "validate", dir); code != exitParseError || stderr == "" {
+		t.Errorf("expected exit %d with an error for invalid config, got %d: %s", exitParseError, code, stderr)
+	}
+
+	if _, _, code := runCLI(t, "", "validate", filepath.Join(dir, "missing")); code != exitParseError {
+		t.Errorf("expected exit %d for missing directory, got %d", exitParseError, code)
+	}
+}
+
+// =============================================================================
+// report
+// =============================================================================
+
+func TestReport(t *testing.T) {
+	dir := writeResponses(t, map[string]string{
+		"a.json": `{"model": "gpt-4o", "usage": {"prompt_tokens": 1000, "completion_tokens": 500}}`,
+		"b.json": `{"model": "gpt-4o", "usage": {"prompt_tokens": 2000, "completion_tokens": 500}}`,
+		"c.json": `{"modelVersion": "gemini-2.5-flash", "usageMetadata": {"promptTokenCount": 1000, "candidatesTokenCount": 500}}`,
+		"d.json": `{"model": "no-such-model", "usage": {"prompt_tokens": 1000}}`,
+	})
+
+	output, stderr, code := runCLI(t, "", "report", "-dir", dir, "-json")
+	if code != exitOK {
+		t.Fatalf("report exited %d: %s", code, stderr)
+	}
+	var out ReportJSON
+	if err := json.Unmarshal([]byte(output), &out); err != nil {
+		t.Fatalf("output is not valid JSON: %v", err)
+	}
+	if out.Requests != 4 || len(out.Models) != 3 {
+		t.Fatalf("expected 4 requests over 3 models, got %+v", out)
+	}
+	top := out.Models[0]
+	if top.Model != "gpt-4o" || top.Requests != 2 {
+		t.Errorf("expected gpt-4o (2 requests) first, got %+v", top)
+	}
+	last := out.Models[2]
+	if last.Model != "no-such-model" || last.Unknown != 1 || last.TotalCost != 0 {
+		t.Errorf("expected unknown model last, got %+v", last)
+	}
+	var sum float64
+	for _, m := range out.Models {
+		sum += m.TotalCost
+	}
+	if math.Abs(sum-out.TotalCost) > 1e-12 {
+		t.Errorf("model totals %f do not sum to total %f", sum, out.TotalCost)
+	}
+
+	ndjson := `{"model": "gpt-4o", "usage": {"prompt_tokens": 1000}}` + "\n" + `{broken` + "\n"
+	output, _, code = runCLI(t, ndjson, "report", "-ndjson")
+	if code != exitParseError || !strings.Contains(output, "gpt-4o") || !strings.Contains(output, "1 errors") {
+		t.Errorf("expected exit %d and gpt-4o row, got %d: %s", exitParseError, code, output)
+	}
+
+	if _, _, code := runCLI(t, "", "report"); code != exitError {
+		t.Errorf("expected exit %d without -dir or -ndjson, got %d", exitError, code)
+	}
+	if strings.Contains(output, "Billed:") {
+		t.Errorf("expected no billed lines without a markup, got: %s", output)
+	}
+}
+
+func TestReport_MarkupAndTax(t *testing.T) {
+	p, err := pricing.NewPricer(pricing.WithMarkup(pricing.Markup{Percent: 20}), pricing.WithTaxRate(0.1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	records := []pricing.UsageRecord{
+		{Model: "gpt-4o", Usage: pricing.TokenUsage{PromptTokens: 1_000_000}},
+		{Model: "gpt-4o", Usage: pricing.TokenUsage{PromptTokens: 1_000_000}},
+	}
+	out := toIngestReport(records, p.CalculateBatchUsage(records))
+
+	near := func(a, b float64) bool { return math.Abs(a-b) < 1e-9 }
+	m := out.Models[0]
+	want := 2 * p.CalculateUsage("gpt-4o", records[0].Usage, nil).GrossTotal
+	if !near(m.Markup, 0.2*out.TotalCost) || !near(m.BilledTotal, 1.2*out.TotalCost) || !near(m.GrossTotal, want) {
+		t.Errorf("unexpected model totals %+v (total %f)", m, out.TotalCost)
+	}
+	if out.billedTotalsJSON != m.billedTotalsJSON {
+		t.Errorf("report totals %+v differ from the only model's %+v", out.billedTotalsJSON, m.billedTotalsJSON)
+	}
+
+	var human bytes.Buffer
+	printReportHuman(&human, out, usdMoney)
+	for _, line := range []string{"Markup:", "Billed:", "Tax:", "Gross:"} {
+		if !strings.Contains(human.String(), line) {
+			t.Errorf("expected %q in report, got: %s", line, human.String())
+		}
+	}
+}
+
+// =============================================================================
+// serve
+// =============================================================================
+
+func TestServeMux(t *testing.T) {
+	p, err := pricing.NewPricer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(newServeMux(p, newCatalogWatcher(catalogEvent(p))))
+	defer srv.Close()
+
+	body := `{"model": "gpt-4o", "usage": {"PromptTokens": 1000, "CompletionTokens": 500}}`
+	resp, err := http.Post(srv.URL+"/v1/cost", "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var result pricing.JSONResult
+	json.NewDecoder(resp.Body).Decode(&result)
+	resp.Body.Close()
+	want := p.CalculateUsage("gpt-4o", pricing.TokenUsage{PromptTokens: 1000, CompletionTokens: 500}, nil)
+	if resp.StatusCode != http.StatusOK || result.TotalCost != want.TotalCost {
+		t.Errorf("expected 200 and total %f, got %d %+v", want.TotalCost, resp.StatusCode, result)
+	}
+
+	resp, err = http.Post(srv.URL+"/v1/cost", "application/json", strings.NewReader(`{broken`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected 400 for malformed request, got %d", resp.StatusCode)
+	}
+
+	resp, err = http.Get(srv.URL + "/v1/models?provider=anthropic")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var models []ModelJSON
+	json.NewDecoder(resp.Body).Decode(&models)
+	resp.Body.Close()
+	if len(models) == 0 || models[0].Provider != "anthropic" {
+		t.Errorf("expected anthropic models, got %+v", models)
+	}
+
+	resp, err = http.Get(srv.URL + "/healthz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected 200 from /healthz, got %d", resp.StatusCode)
+	}
+}
+
+// =============================================================================
+// completion
+// =============================================================================
+
+func TestCompletion(t *testing.T) {
+	for _, shell := range completionShells {
+		output, stderr, code := runCLI(t, "", "completion", shell)
+		if code != exitOK {
+			t.Fatalf("completion %s exited %d: %s", shell, code, stderr)
+		}
+		flagPrefix := "-"
+		if shell == "fish" {
+			flagPrefix = "-o " // fish declares single-dash flags as old-style options
+		}
+		for _, want := range []string{"compare", flagPrefix + "models", flagPrefix + "max-age-days"} {
+			if !strings.Contains(output, want) {
+				t.Errorf("%s: expected %q in script", shell, want)
+			}
+		}
+	}
+
+	if _, _, code := runCLI(t, "", "completion", "powershell"); code != exitError {
+		t.Errorf("expected exit %d for unsupported shell, got %d", exitError, code)
+	}
+}
+
+func TestCompletion_BashSyntax(t *testing.T) {
+	bash, err := exec.LookPath("bash")
+	if err != nil {
+		t.Skip("bash not installed")
+	}
+	output, _, _ := runCLI(t, "", "completion", "bash")
+	script := filepath.Join(t.TempDir(), "pricing-cli.bash")
+	if err := os.WriteFile(script, []byte(output), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if out, err := exec.Command(bash, "-n", script).CombinedOutput(); err != nil {
+		t.Errorf("bash rejected completion script: %v\n%s", err, out)
+	}
+}
+
+// =============================================================================
+// reprice
+// =============================================================================
+
+func TestReprice(t *testing.T) {
+	dir := writeResponses(t, map[string]string{
+		"acme_pricing.json": `{"models": {"acme-1": {"input_per_million": 1.0, "output_per_million": 4.0}}}`,
+	})
+	records := `{"model": "gpt-4o", "usage": {"PromptTokens": 1000000}}` + "\n" +
+		`{"model": "acme-1", "usage": {"PromptTokens": 1000000, "CompletionTokens": 1000000}}` + "\n"
+
+	output, stderr, code := runCLI(t, records, "reprice", "-to", dir, "-json")
+	if code != exitOK {
+		t.Fatalf("reprice exited %d: %s", code, stderr)
+	}
+	var out RepriceJSON
+	if err := json.Unmarshal([]byte(output), &out); err != nil {
+		t.Fatalf("output is not valid JSON: %v", err)
+	}
+	if out.Requests != 2 || len(out.Models) != 2 || out.FromVersion != pricing.CatalogVersion() {
+		t.Fatalf("unexpected report %+v", out)
+	}
+	for _, m := range out.Models {
+		switch m.Model {
+		case "gpt-4o":
+			if m.Unknown != 1 || m.RepricedCost != 0 || m.OriginalCost <= 0 {
+				t.Errorf("expected gpt-4o to be unknown in the new sheet, got %+v", m)
+			}
+		case "acme-1":
+			if m.RepricedCost != 5.0 {
+				t.Errorf("expected acme-1 repriced at $5, got %+v", m)
+			}
+		}
+	}
+
+	output, _, code = runCLI(t, records, "reprice")
+	if code != exitOK || !strings.Contains(output, "DELTA") || !strings.Contains(output, "+$0.000000") {
+		t.Errorf("expected table with no change at current rates (exit %d), got: %s", code, output)
+	}
+
+	if _, _, code := runCLI(t, `{broken`, "reprice"); code != exitParseError {
+		t.Errorf("expected exit %d for malformed input, got %d", exitParseError, code)
+	}
+	if _, _, code := runCLI(t, records, "reprice", "-to", filepath.Join(dir, "missing")); code != exitParseError {
+		t.Errorf("expected exit %d for missing pricing, got %d", exitParseError, code)
+	}
+}
+
+func TestIngest(t *testing.T) {
+	input := "model,input_tokens,output_tokens,timestamp\n" +
+		"gpt-4o,1000000,0,2026-01-15T10:00:00Z\n" +
+		"gpt-4o,0,1000000,2026-01-16T10:00:00Z\n" +
+		"no-such-model,10,10,\n"
+
+	output, stderr, code := runCLI(t, input, "ingest", "-json")
+	if code != exitOK {
+		t.Fatalf("ingest exited %d: %s", code, stderr)
+	}
+	var out ReportJSON
+	if err := json.Unmarshal([]byte(output), &out); err != nil {
+		t.Fatalf("output is not valid JSON: %v", err)
+	}
+	if out.Requests != 3 || len(out.Models) != 2 || out.Models[0].Model != "gpt-4o" || out.Models[0].Requests != 2 {
+		t.Fatalf("unexpected report %+v", out)
+	}
+	if out.Models[1].Unknown != 1 || out.TotalCost != out.Models[0].TotalCost {
+		t.Errorf("expected the unknown model to cost nothing, got %+v", out)
+	}
+
+	path := filepath.Join(t.TempDir(), "priced.csv")
+	if _, stderr, code := runCLI(t, "prompt,model_name\n100,gpt-4o\n", "ingest", "-columns", "model=model_name,input_tokens=prompt", "-o", path); code != exitOK {
+		t.Fatalf("ingest -o exited %d: %s", code, stderr)
+	}
+	priced, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(priced)), "\n")
+	if len(lines) != 2 || lines[0] != strings.Join(ingestColumns, ",") || !strings.HasPrefix(lines[1], "gpt-4o,,100,0,") {
+		t.Errorf("unexpected priced CSV:\n%s", priced)
+	}
+
+	if _, _, code := runCLI(t, "PAR1\x00\x00", "ingest"); code != exitParseError {
+		t.Errorf("expected exit %d for a corrupt Parquet file, got %d", exitParseError, code)
+	}
+	if _, _, code := runCLI(t, input, "ingest", "-columns", "cost=x"); code != exitError {
+		t.Errorf("expected exit %d for a bad -columns entry, got %d", exitError, code)
+	}
+}
+
+// ingestParquet is a snappy-compressed Parquet file with a required
+// model_name string, a required prompt int64 and an optional output_tokens
+// int64 column holding (gpt-4o, 1000000, 1000000), (gpt-4o, 2000000, null)
+// and (mystery-model, 10, 5).
+const ingestParquet = "UEFSMRUAFUoVTiwVBhUAFQYVBgAAJZAGAAAAZ3B0LTRvBgAAAGdwdC00bw0AAABteXN0ZXJ5LW1vZGVsFQAVMBU0LBUGFQAVBhUGAAAYXEBCDwAAAAAAgIQeAAAAAAAKAAAAAAAAABUAFTQVOCwVBhUAFQYVBgAAGmQGAAAAAgECAAIBQEIPAAAAAAAFAAAAAAAAABUCGUxIBnNjaGVtYRUGABUMJQAYCm1vZGVsX25hbWUlAAAVBCUAGAZwcm9tcHQAFQQlAhgNb3V0cHV0X3Rva2VucwAWBhkcGTwmCBwVDBkVABkYCm1vZGVsX25hbWUVAhYGFnAWcCYIAAAmeBwVBBkVABkYBnByb21wdBUCFgYWVhZWJngAACbOARwVBBkVABkYDW91dHB1dF90b2tlbnMVAhYGFloWWibOAQAAFgAWBgAoDnBhcnF1ZXRpbyB0ZXN0AMMAAABQQVIx"
+
+func TestIngest_Parquet(t *testing.T) {
+	data, err := base64.StdEncoding.DecodeString(ingestParquet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "usage.parquet")
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	output, stderr, code := runCLI(t, "", "ingest", "-json", "-f", path, "-columns", "model=model_name,input_tokens=prompt")
+	if code != exitOK {
+		t.Fatalf("ingest exited %d: %s", code, stderr)
+	}
+	var out ReportJSON
+	if err := json.Unmarshal([]byte(output), &out); err != nil {
+		t.Fatalf("output is not valid JSON: %v", err)
+	}
+	if out.Requests != 3 || len(out.Models) != 2 || out.Models[0].Model != "gpt-4o" || out.Models[0].Requests != 2 || out.Models[1].Unknown != 1 {
+		t.Fatalf("unexpected report %+v", out)
+	}
+
+	// The same rows as CSV must price identically.
+	csvInput := "model_name,prompt,output_tokens\n" +
+		"gpt-4o,1000000,1000000\n" +
+		"gpt-4o,2000000,\n" +
+		"mystery-model,10,5\n"
+	output, stderr, code = runCLI(t, csvInput, "ingest", "-json", "-columns", "model=model_name,input_tokens=prompt")
+	if code != exitOK {
+		t.Fatalf("ingest of the CSV equivalent exited %d: %s", code, stderr)
+	}
+	var want ReportJSON
+	if err := json.Unmarshal([]byte(output), &want); err != nil {
+		t.Fatalf("output is not valid JSON: %v", err)
+	}
+	if out.TotalCost == 0 || out.TotalCost != want.TotalCost {
+		t.Errorf("Parquet total %v, CSV total %v", out.TotalCost, want.TotalCost)
+	}
+
+	// Missing mapped columns are reported as for CSV.
+	if _, stderr, code := runCLI(t, "", "ingest", "-f", path); code != exitParseError || !strings.Contains(stderr, "model") {
+		t.Errorf("expected exit %d naming the missing model column, got %d: %s", exitParseError, code, stderr)
+	}
+}
+
+func TestRates(t *testing.T) {
+	output, stderr, code := runCLI(t, "", "rates")
+	if code != exitOK {
+		t.Fatalf("rates exited %d: %s", code, stderr)
+	}
+	if !strings.HasPrefix(output, "provider,model,unit,rate,tier,tier_mode,effective_date,effective_until\n") || !strings.Contains(output, ",gpt-4o,input_per_million,") {
+		t.Errorf("unexpected CSV output: %.300s", output)
+	}
+	csvOutput := output
+
+	output, _, code = runCLI(t, "", "rates", "-format", "ndjson")
+	var row pricing.RateRow
+	if code != exitOK || json.Unmarshal([]byte(strings.SplitN(output, "\n", 2)[0]), &row) != nil || row.Provider == "" {
+		t.Errorf("expected NDJSON rows (exit %d), got: %.300s", code, output)
+	}
+
+	csvRows := strings.Count(strings.TrimSpace(csvOutput), "\n")
+	output, stderr, code = runCLI(t, "", "rates", "-format", "parquet")
+	if code != exitOK {
+		t.Fatalf("rates -format parquet exited %d: %s", code, stderr)
+	}
+	table, err := parquetio.ReadTable(strings.NewReader(output), int64(len(output)))
+	if err != nil {
+		t.Fatalf("rates -format parquet wrote an unreadable file: %v", err)
+	}
+	if !slices.Equal(table.Columns, rateTableParquetColumns) || len(table.Rows) != csvRows {
+		t.Fatalf("expected %d rows of %v, got %d rows of %v", csvRows, rateTableParquetColumns, len(table.Rows), table.Columns)
+	}
+	if first := strings.Join(table.Rows[0], ","); !strings.Contains(csvOutput, "\n"+first+"\n") {
+		t.Errorf("Parquet row %q is not in the CSV export", first)
+	}
+
+	if _, _, code := runCLI(t, "", "rates", "-format", "xml"); code != exitError {
+		t.Errorf("expected exit %d for an unknown format, got %d", exitError, code)
+	}
+}
+
+func TestLiteLLM(t *testing.T) {
+	output, stderr, code := runCLI(t, "", "litellm")
+	if code != exitOK {
+		t.Fatalf("litellm exited %d: %s", code, stderr)
+	}
+	dir := t.TempDir()
+	exported := filepath.Join(dir, "litellm.json")
+	if err := os.WriteFile(exported, []byte(output), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Everything exported is already priced, so -missing imports nothing.
+	output, stderr, code = runCLI(t, "", "litellm", "-import", exported, "-dir", dir, "-missing")
+	if code != exitOK || output != "" {
+		t.Fatalf("expected no configs with -missing (exit %d): %s%s", code, output, stderr)
+	}
+
+	output, stderr, code = runCLI(t, "", "litellm", "-import", exported, "-dir", dir)
+	if code != exitOK || !strings.Contains(output, "openai_pricing.json") {
+		t.Fatalf("litellm -import exited %d: %s%s", code, output, stderr)
+	}
+	// The written configs load as a catalog.
+	if _, stderr, code := runCLI(t, "", "validate", dir); code != exitOK {
+		t.Errorf("imported configs failed validation: %s", stderr)
+	}
+}
+
+func TestCost_CompressedInput(t *testing.T) {
+	input := `{"modelVersion": "gemini-2.5-flash", "usageMetadata": {"promptTokenCount": 1000, "candidatesTokenCount": 500}}`
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	w.Write([]byte(input))
+	w.Close()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "response.json.gz")
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, args := range [][]string{{"cost"}, {"cost", "-f", path}, {"cost", "-dir", dir, "-glob", "*.gz"}} {
+		output, stderr, code := runCLI(t, buf.String(), args...)
+		if code != exitOK {
+			t.Fatalf("%v exited %d: %s", args, code, stderr)
+		}
+		if !strings.Contains(output, `"total_cost"`) || strings.Contains(output, `"error"`) {
+			t.Errorf("%v: expected a priced result, got %s", args, output)
+		}
+	}
+
+	if _, _, code := runCLI(t, buf.String(), "cost", "-no-decompress"); code == exitOK {
+		t.Error("expected -no-decompress to reject gzip input")
+	}
+}
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// completionShells are the shells `completion` can generate scripts for.
+var completionShells = []string{"bash", "zsh", "fish"}
+
+func setupCompletion(fs *flag.FlagSet, env *commandEnv) func(ctx context.Context, args []string) int {
+	return func(ctx context.Context, args []string) int {
+		if len(args) != 1 {
+			return commandError(env, "completion", errors.New("expected one of bash, zsh, fish"), exitError)
+		}
+		if err := writeCompletion(env.stdout, args[0]); err != nil {
+			return commandError(env, "completion", err, exitError)
+		}
+		return exitOK
+	}
+}
+
+// commandFlag is a flag as offered for completion.
+type commandFlag struct {
+	name, usage string
+}
+
+// commandFlags returns c's flags in name order.
+func commandFlags(c command) []commandFlag {
+	fs, _ := c.newFlagSet(&commandEnv{stdout: io.Discard, stderr: io.Discard})
+	var flags []commandFlag
+	fs.VisitAll(func(f *flag.Flag) {
+		flags = append(flags, commandFlag{f.Name, f.Usage})
+	})
+	return flags
+}
+
+// commandArgs returns the fixed positional arguments c accepts, if any.
+func commandArgs(c command) []string {
+	switch c.name {
+	case "completion":
+		return completionShells
+	case "help":
+		names := make([]string, len(commands))
+		for i, c := range commands {
+			names[i] = c.name
+		}
+		return names
+	}
+	return nil
+}
+
+// writeCompletion writes the completion script for shell, generated from the
+// command table so new commands and flags are completed without further changes.
+func writeCompletion(w io.Writer, shell string) error {
+	switch shell {
+	case "bash":
+		return writeBashCompletion(w)
+	case "zsh":
+		return writeZshCompletion(w)
+	case "fish":
+		return writeFishCompletion(w)
+	default:
+		return fmt.Errorf("unsupported shell %q (want bash, zsh, or fish)", shell)
+	}
+}
+
+// flagWords returns c's flags as "-name" words.
+func flagWords(c command) []string {
+	var words []string
+	for _, f := range commandFlags(c) {
+		words = append(words, "-"+f.name)
+	}
+	return words
+}
+
+// shellQuote single-quotes s for bash and zsh.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+func writeBashCompletion(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString("# bash completion for pricing-cli\n")
+	b.WriteString("# Install: source <(pricing-cli completion bash)\n\n")
+	b.WriteString("_pricing_cli() {\n")
+	b.WriteString("    local cur=${COMP_WORDS[COMP_CWORD]} cmd=${COMP_WORDS[1]} words=\n")
+	b.WriteString("    if [[ $COMP_CWORD -eq 1 && $cur != -* ]]; then\n")
+	fmt.Fprintf(&b, "        COMPREPLY=($(compgen -W %s -- \"$cur\"))\n",
+		shellQuote(strings.Join(commandArgs(command{name: "help"}), " ")))
+	b.WriteString("        return\n")
+	b.WriteString("    fi\n")
+	b.WriteString("    case $cmd in\n")
+	for _, c := range commands {
+		pattern := c.name
+		if c.name == defaultCommand {
+			pattern += "|-*"
+		}
+		if args := commandArgs(c); len(args) > 0 {
+			fmt.Fprintf(&b, "        %s) COMPREPLY=($(compgen -W %s -- \"$cur\")); return ;;\n",
+				pattern, shellQuote(strings.Join(args, " ")))
+		} else if words := flagWords(c); len(words) > 0 {
+			fmt.Fprintf(&b, "        %s) words=%s ;;\n", pattern, shellQuote(strings.Join(words, " ")))
+		}
+	}
+	b.WriteString("    esac\n")
+	b.WriteString("    if [[ $cur == -* ]]; then\n")
+	b.WriteString("        COMPREPLY=($(compgen -W \"$words\" -- \"$cur\"))\n")
+	b.WriteString("    fi\n")
+	b.WriteString("}\n")
+	b.WriteString("complete -o default -F _pricing_cli pricing-cli\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeZshCompletion(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString("#compdef pricing-cli\n")
+	b.WriteString("# zsh completion for pricing-cli\n")
+	b.WriteString("# Install: pricing-cli completion zsh > \"${fpath[1]}/_pricing-cli\"\n\n")
+	b.WriteString("_pricing_cli() {\n")
+	b.WriteString("    local -a commands\n")
+	b.WriteString("    commands=(\n")
+	for _, c := range commands {
+		fmt.Fprintf(&b, "        %s\n", shellQuote(c.name+":"+c.summary))
+	}
+	b.WriteString("    )\n")
+	b.WriteString("    if (( CURRENT == 2 )) && [[ $PREFIX != -* ]]; then\n")
+	b.WriteString("        _describe 'command' commands\n")
+	b.WriteString("        return\n")
+	b.WriteString("    fi\n")
+	b.WriteString("    case $words[2] in\n")
+	for _, c := range commands {
+		pattern := c.name
+		if c.name == defaultCommand {
+			pattern += "|-*"
+		}
+		if args := commandArgs(c); len(args) > 0 {
+			fmt.Fprintf(&b, "        %s) compadd -- %s ;;\n", pattern, strings.Join(args, " "))
+		} else if words := flagWords(c); len(words) > 0 {
+			fmt.Fprintf(&b, "        %s) if [[ $PREFIX == -* ]]; then compadd -- %s; else _files; fi ;;\n",
+				pattern, strings.Join(words, " "))
+		}
+	}
+	b.WriteString("        *) _files ;;\n")
+	b.WriteString("    esac\n")
+	b.WriteString("}\n\n")
+	b.WriteString("compdef _pricing_cli pricing-cli\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// fishQuote single-quotes s for fish, which escapes quotes with a backslash.
+func fishQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return "'" + strings.ReplaceAll(s, "'", `\'`) + "'"
+}
+
+func writeFishCompletion(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString("# fish completion for pricing-cli\n")
+	b.WriteString("# Install: pricing-cli completion fish > ~/.config/fish/completions/pricing-cli.fish\n\n")
+	for _, c := range commands {
+		fmt.Fprintf(&b, "complete -c pricing-cli -n __fish_use_subcommand -a %s -d %s\n",
+			c.name, fishQuote(c.summary))
+	}
+	for _, c := range commands {
+		cond := fishQuote("__fish_seen_subcommand_from " + c.name)
+		if c.name == defaultCommand {
+			// Flags given without a command run the default command.
+			cond = "'__fish_use_subcommand; or __fish_seen_subcommand_from " + c.name + "'"
+		}
+		for _, f := range commandFlags(c) {
+			fmt.Fprintf(&b, "complete -c pricing-cli -n %s -o %s -d %s\n", cond, f.name, fishQuote(f.usage))
+		}
+		if args := commandArgs(c); len(args) > 0 {
+			fmt.Fprintf(&b, "complete -c pricing-cli -n %s -f -a %s\n", cond, fishQuote(strings.Join(args, " ")))
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
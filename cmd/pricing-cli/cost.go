@@ -0,0 +1,317 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"runtime"
+	"strings"
+	"text/template"
+
+	"github.com/ai8future/chassis-go/v11/logz"
+	"github.com/ai8future/chassis-go/v11/secval"
+	pricing "github.com/ai8future/pricing_db"
+	"github.com/ai8future/pricing_db/decompress"
+)
+
+// costFlags are the response-pricing flags shared by the cost and report commands.
+type costFlags struct {
+	file, dir, glob, model, provider              *string
+	ndjson, batch, ordered, verbose, noDecompress *bool
+	workers                                       *int
+}
+
+func defineCostFlags(fs *flag.FlagSet) costFlags {
+	return costFlags{
+		file:         fs.String("f", "", "Read JSON from file (default: stdin)"),
+		dir:          fs.String("dir", "", "Process every file in this directory matching -glob"),
+		glob:         fs.String("glob", "*.json", "File name pattern for -dir"),
+		ndjson:       fs.Bool("ndjson", false, "Input is newline-delimited responses; output one JSON result per line"),
+		workers:      fs.Int("workers", runtime.NumCPU(), "Concurrent workers for -dir and -ndjson"),
+		ordered:      fs.Bool("ordered", true, "Emit -dir and -ndjson results in input order (false: as completed)"),
+		batch:        fs.Bool("batch", false, "Apply batch mode pricing (50% discount)"),
+		model:        fs.String("model", "", "Override model name (when modelVersion missing)"),
+		provider:     fs.String("provider", "", "Response format, e.g. gemini, openai, anthropic (default: auto-detect)"),
+		verbose:      fs.Bool("v", false, "Verbose output (debug logging)"),
+		noDecompress: fs.Bool("no-decompress", false, "Read gzip/zstd-compressed inputs as is instead of decompressing them"),
+		// --version is handled by chassis.RequireMajor via SetAppVersion
+	}
+}
+
+// resolve applies env config under the flags and returns the logger and batch settings.
+func (f costFlags) resolve(cfg CLIConfig) (*slog.Logger, batchConfig, error) {
+	// Resolve log level: -v flag overrides env config
+	logLevel := cfg.LogLevel
+	if *f.verbose {
+		logLevel = "debug"
+	}
+	logger := logz.New(logLevel)
+
+	// Resolve model: flag overrides env config
+	model := cfg.DefaultModel
+	if *f.model != "" {
+		model = *f.model
+	}
+
+	// Resolve batch mode: flag overrides env config
+	batchMode := cfg.BatchMode
+	if *f.batch {
+		batchMode = true
+	}
+
+	var opts *pricing.CalculateOptions
+	if batchMode {
+		opts = &pricing.CalculateOptions{BatchMode: true}
+	}
+
+	logger.Debug("configuration resolved",
+		"model", model,
+		"batch_mode", batchMode,
+		"log_level", logLevel,
+	)
+
+	if *f.workers < 1 {
+		return logger, batchConfig{}, fmt.Errorf("-workers must be at least 1, got %d", *f.workers)
+	}
+	return logger, batchConfig{
+		provider: *f.provider,
+		model:    model,
+		opts:     opts,
+		workers:  *f.workers,
+		ordered:  *f.ordered,
+		raw:      *f.noDecompress,
+	}, nil
+}
+
+// openInput returns the -f file, or stdin if -f is unset, decompressed
+// unless -no-decompress is set.
+func (f costFlags) openInput(ctx context.Context, env *commandEnv) (io.ReadCloser, error) {
+	return openInput(ctx, *f.file, env.stdin, *f.noDecompress)
+}
+
+// openInput returns the named file or bucket object (s3://, gs://), or stdin
+// if name is empty. Gzip and zstd input is decompressed unless raw is set.
+func openInput(ctx context.Context, name string, stdin io.Reader, raw bool) (io.ReadCloser, error) {
+	var in io.ReadCloser
+	var err error
+	switch {
+	case name == "":
+		in = io.NopCloser(stdin)
+	case isObjectURI(name):
+		in, err = openObject(ctx, name)
+	default:
+		in, err = os.Open(name)
+	}
+	if err != nil || raw {
+		return in, err
+	}
+	return decompress.NewReadCloser(in)
+}
+
+// readInputFile reads the named file or bucket object, decompressing gzip and
+// zstd content unless raw is set.
+func readInputFile(ctx context.Context, name string, raw bool) ([]byte, error) {
+	in, err := openInput(ctx, name, nil, raw)
+	if err != nil {
+		return nil, err
+	}
+	defer in.Close()
+	return io.ReadAll(in)
+}
+
+func setupCost(fs *flag.FlagSet, env *commandEnv) func(ctx context.Context, args []string) int {
+	flags := defineCostFlags(fs)
+	failUnknown := fs.Bool("fail-on-unknown", false, "Exit with status 2 if any model is not in the pricing data")
+	human := fs.Bool("human", false, "Human-readable output (default: JSON)")
+	details := fs.Bool("details", false, "Include line items, warning codes, and the matched model in JSON output")
+	nearMatches := fs.Bool("near-matches", false, "For unknown models, list the closest known models; with -dir/-ndjson, also summarize unknown_models")
+	tmplFlag := fs.String("template", "", "Go text/template for a single result, or @file to read it from a file (implies -human)")
+	localeFlags := defineLocaleFlags(fs)
+
+	return func(ctx context.Context, args []string) int {
+		logger, batch, err := flags.resolve(env.cfg)
+		if err != nil {
+			return commandError(env, "cost", err, exitError)
+		}
+		batch.details = *details
+		batch.nearMatches = *nearMatches
+		tmplText := env.cfg.Template
+		if *tmplFlag != "" {
+			tmplText = *tmplFlag
+		}
+		money, err := localeFlags.resolve(env.cfg)
+		if err != nil {
+			return commandError(env, "cost", err, exitError)
+		}
+		tmpl, err := loadTemplate(tmplText)
+		if err == nil {
+			tmpl, err = withMoney(tmpl, money)
+		}
+		if err != nil {
+			return commandError(env, "cost", err, exitError)
+		}
+
+		if *flags.dir != "" {
+			out, err := processDir(ctx, *flags.dir, *flags.glob, batch)
+			if err != nil {
+				logger.Error("failed to process directory", "dir", *flags.dir, "error", err)
+				return exitError
+			}
+			logger.Debug("directory processed",
+				"files", out.FileCount,
+				"errors", out.ErrorCount,
+				"total_cost", out.TotalCost,
+			)
+			if *human {
+				printDirHuman(env.stdout, out, money)
+			} else {
+				printDirJSON(env.stdout, out)
+			}
+			return exitCode(out.ErrorCount, out.UnknownCount, *failUnknown)
+		}
+
+		if *flags.ndjson {
+			in, err := flags.openInput(ctx, env)
+			if err != nil {
+				logger.Error("failed to open file", "path", *flags.file, "error", err)
+				return exitError
+			}
+			defer in.Close()
+			totals, err := processNDJSON(in, env.stdout, batch)
+			if err != nil {
+				logger.Error("failed to read NDJSON input", "error", err)
+				return exitError
+			}
+			logger.Debug("ndjson processed",
+				"records", totals.FileCount,
+				"errors", totals.ErrorCount,
+				"total_cost", totals.TotalCost,
+			)
+			return exitCode(totals.ErrorCount, totals.UnknownCount, *failUnknown)
+		}
+
+		// Read input
+		if *flags.file == "" {
+			// Check if stdin is a terminal (no piped input)
+			if f, ok := env.stdin.(*os.File); ok {
+				if stat, err := f.Stat(); err == nil && stat.Mode()&os.ModeCharDevice != 0 {
+					printUsage(env.stderr)
+					return exitOK
+				}
+			}
+			logger.Debug("reading input from stdin")
+		} else {
+			logger.Debug("reading input from file", "path", *flags.file)
+		}
+		in, err := flags.openInput(ctx, env)
+		if err != nil {
+			logger.Error("failed to read file", "path", *flags.file, "error", err)
+			return exitError
+		}
+		input, err := io.ReadAll(in)
+		in.Close()
+		if err != nil {
+			logger.Error("failed to read input", "error", err)
+			return exitError
+		}
+
+		if len(input) == 0 {
+			logger.Error("no input provided")
+			printUsage(env.stderr)
+			return exitError
+		}
+
+		logger.Debug("input read", "bytes", len(input))
+
+		// Security: reject dangerous JSON keys before parsing
+		if err := secval.ValidateJSON(input); err != nil {
+			logger.Error("JSON security validation failed", "error", err)
+			return exitParseError
+		}
+
+		// Parse and calculate
+		if batch.model != "" {
+			logger.Debug("using model override", "model", batch.model)
+		}
+		costDetails, format, err := calculate(input, batch.provider, batch.model, batch.opts)
+		if err != nil {
+			logger.Error("failed to parse response", "format", format, "error", err)
+			return exitParseError
+		}
+		logger.Debug("response format", "format", format)
+
+		logger.Debug("calculation complete",
+			"total_cost", costDetails.TotalCost,
+			"unknown", costDetails.Unknown,
+		)
+
+		// Output results
+		if *human || tmplText != "" {
+			if err := tmpl.Execute(env.stdout, costDetails); err != nil {
+				return commandError(env, "cost", err, exitError)
+			}
+		} else {
+			model := responseModel(input, batch.model)
+			output := toOutputJSON(costDetails)
+			if batch.details {
+				output.Details = toDetailsJSON(costDetails, model)
+			}
+			if batch.nearMatches && costDetails.Unknown {
+				output.NearMatches = pricing.NearMatches(model, nearMatchCount)
+			}
+			printJSON(env.stdout, output)
+		}
+
+		if costDetails.Unknown && *failUnknown {
+			logger.Error("model not found in pricing data")
+			return exitUnknownModel
+		}
+		return exitOK
+	}
+}
+
+// printCostHelp writes the cost command's environment, exit status, and examples help.
+func printCostHelp(w io.Writer) {
+	fmt.Fprintf(w, "\nThe response format is detected from its fields unless -provider is set.\n")
+	fmt.Fprintf(w, "\nEnvironment variables:\n")
+	fmt.Fprintf(w, "  PRICING_DEFAULT_MODEL   Default model name\n")
+	fmt.Fprintf(w, "  PRICING_BATCH_MODE      Enable batch mode (true/false)\n")
+	fmt.Fprintf(w, "  PRICING_LOG_LEVEL       Log level (debug, info, warn, error)\n")
+	fmt.Fprintf(w, "  PRICING_TEMPLATE        Default -template\n")
+	fmt.Fprintf(w, "  PRICING_LOCALE          Default -locale\n")
+	fmt.Fprintf(w, "  PRICING_CURRENCY        Default -currency\n")
+	fmt.Fprintf(w, "\nExit status:\n")
+	fmt.Fprintf(w, "  0 success, 1 usage or I/O error, 2 unknown model (with -fail-on-unknown),\n")
+	fmt.Fprintf(w, "  3 input could not be parsed (with -dir/-ndjson: any input)\n")
+	fmt.Fprintf(w, "\nExamples:\n")
+	fmt.Fprintf(w, "  cat response.json | pricing-cli\n")
+	fmt.Fprintf(w, "  pricing-cli -f response.json\n")
+	fmt.Fprintf(w, "  pricing-cli -batch -human -f response.json\n")
+	fmt.Fprintf(w, "  pricing-cli -details -f response.json\n")
+	fmt.Fprintf(w, "  pricing-cli -model gemini-2.5-flash -f response.json\n")
+	fmt.Fprintf(w, "  pricing-cli -template '{{usd .TotalCost 4}} ({{.TierApplied}})' -f response.json\n")
+	fmt.Fprintf(w, "  pricing-cli -human -locale de-DE -currency EUR=0.92 -f response.json\n")
+	fmt.Fprintf(w, "  pricing-cli -provider cohere -model command-r -f response.json\n")
+	fmt.Fprintf(w, "  pricing-cli -dir ./responses -glob '*.json'\n")
+	fmt.Fprintf(w, "  pricing-cli -ndjson -near-matches -f responses.ndjson\n")
+	fmt.Fprintf(w, "  pricing-cli -ndjson -workers 16 -ordered=false -f responses.ndjson\n")
+}
+
+// loadTemplate parses text, or the file it names as @path, as the template for
+// single-result human output. Empty text selects the default breakdown.
+func loadTemplate(text string) (*template.Template, error) {
+	if text == "" {
+		return defaultHumanTemplate, nil
+	}
+	if path, ok := strings.CutPrefix(text, "@"); ok {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		text = string(data)
+	}
+	return pricing.ParseDetailsTemplate(text)
+}
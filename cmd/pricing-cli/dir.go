@@ -0,0 +1,243 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/ai8future/chassis-go/v11/secval"
+	pricing "github.com/ai8future/pricing_db"
+)
+
+// FileResultJSON is one file's entry in -dir output, or one line's in -ndjson output.
+type FileResultJSON struct {
+	File   string      `json:"file,omitempty"`
+	Line   int         `json:"line,omitempty"`   // 1-based input line (-ndjson only)
+	Model  string      `json:"model,omitempty"`  // Model named by the response or -model
+	Format string      `json:"format,omitempty"` // Detected or -provider response format
+	Result *OutputJSON `json:"result,omitempty"` // nil if the file could not be priced
+	Error  string      `json:"error,omitempty"`
+}
+
+// DirOutputJSON is the -dir output: per-file results (in file name order unless
+// -ordered=false) plus totals.
+type DirOutputJSON struct {
+	Schema       string           `json:"schema"` // outputSchema
+	Files        []FileResultJSON `json:"files"`
+	FileCount    int              `json:"file_count"`
+	ErrorCount   int              `json:"error_count"`   // Files that could not be read or parsed
+	UnknownCount int              `json:"unknown_count"` // Files priced as Unknown (model not found)
+	TotalCost    float64          `json:"total_cost"`    // Sum of per-file total costs
+	// UnknownModels lists the models priced as Unknown, most frequent first, with -near-matches.
+	UnknownModels []UnknownModelJSON `json:"unknown_models,omitempty"`
+
+	unknown map[string]int // Results priced as Unknown per model
+}
+
+// UnknownModelJSON is one entry of the -near-matches unknown_models summary: a
+// model missing from the pricing data and the known models closest to it.
+type UnknownModelJSON struct {
+	Model       string   `json:"model"`
+	Count       int      `json:"count"` // Results priced as Unknown
+	NearMatches []string `json:"near_matches"`
+}
+
+// nearMatchCount is the number of near matches reported per unknown model.
+const nearMatchCount = 3
+
+// add counts f in the totals.
+func (out *DirOutputJSON) add(f FileResultJSON) {
+	out.FileCount++
+	switch {
+	case f.Result == nil:
+		out.ErrorCount++
+	case f.Result.Unknown:
+		out.UnknownCount++
+		if out.unknown == nil {
+			out.unknown = make(map[string]int)
+		}
+		out.unknown[f.Model]++
+	default:
+		out.TotalCost += f.Result.TotalCost
+	}
+}
+
+// unknownModels returns the unknown_models summary of the results added so far.
+func (out *DirOutputJSON) unknownModels() []UnknownModelJSON {
+	models := make([]UnknownModelJSON, 0, len(out.unknown))
+	for model, count := range out.unknown {
+		nearMatches := pricing.NearMatches(model, nearMatchCount)
+		if nearMatches == nil {
+			nearMatches = []string{}
+		}
+		models = append(models, UnknownModelJSON{Model: model, Count: count, NearMatches: nearMatches})
+	}
+	slices.SortFunc(models, func(a, b UnknownModelJSON) int {
+		if c := cmp.Compare(b.Count, a.Count); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Model, b.Model)
+	})
+	return models
+}
+
+// batchConfig holds the settings shared by -dir and -ndjson processing.
+type batchConfig struct {
+	provider string
+	model    string
+	opts     *pricing.CalculateOptions
+	workers  int
+	ordered  bool // Emit results in input order rather than as they complete
+	raw      bool // Read compressed files as is (-no-decompress)
+	details  bool // Include DetailsJSON in each result (-details)
+	// nearMatches adds near matches to Unknown results and an unknown_models summary (-near-matches)
+	nearMatches bool
+}
+
+// processDir prices every file in dir whose name matches pattern, concurrently.
+// Per-file read and parse failures are recorded in the output rather than
+// aborting the run; only an invalid pattern or unreadable directory is an error.
+func processDir(ctx context.Context, dir, pattern string, cfg batchConfig) (DirOutputJSON, error) {
+	out := DirOutputJSON{Schema: outputSchema}
+	err := forEachDirFile(ctx, dir, pattern, cfg, func(f FileResultJSON) {
+		out.Files = append(out.Files, f)
+		out.add(f)
+	})
+	if cfg.nearMatches {
+		out.UnknownModels = out.unknownModels()
+	}
+	return out, err
+}
+
+// forEachDirFile prices every file in dir whose name matches pattern on cfg's
+// worker pool and passes each result to emit.
+func forEachDirFile(ctx context.Context, dir, pattern string, cfg batchConfig, emit func(FileResultJSON)) error {
+	if _, err := filepath.Match(pattern, ""); err != nil {
+		return fmt.Errorf("invalid -glob %q: %w", pattern, err)
+	}
+	paths, err := listInputFiles(ctx, dir, pattern)
+	if err != nil {
+		return err
+	}
+
+	runPool(slices.Values(paths), cfg.workers, cfg.ordered, func(path string) FileResultJSON {
+		result := FileResultJSON{File: path}
+		input, err := readInputFile(ctx, path, cfg.raw)
+		if err != nil {
+			result.Error = err.Error()
+			return result
+		}
+		return priceInput(result, input, cfg)
+	}, emit)
+	return nil
+}
+
+// listInputFiles returns the files in dir, a local directory or bucket URI
+// prefix, whose names match pattern.
+func listInputFiles(ctx context.Context, dir, pattern string) ([]string, error) {
+	if isObjectURI(dir) {
+		return listObjects(ctx, dir, pattern)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var paths []string
+	for _, e := range entries {
+		if matched, _ := filepath.Match(pattern, e.Name()); matched && e.Type().IsRegular() {
+			paths = append(paths, filepath.Join(dir, e.Name()))
+		}
+	}
+	return paths, nil
+}
+
+// priceInput validates and prices one response, filling in result.
+func priceInput(result FileResultJSON, input []byte, cfg batchConfig) FileResultJSON {
+	if err := secval.ValidateJSON(input); err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	result.Model = responseModel(input, cfg.model)
+	details, format, err := calculate(input, cfg.provider, cfg.model, cfg.opts)
+	result.Format = format
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	output := toOutputJSON(details)
+	if cfg.details {
+		output.Details = toDetailsJSON(details, result.Model)
+	}
+	if cfg.nearMatches && details.Unknown {
+		output.NearMatches = pricing.NearMatches(result.Model, nearMatchCount)
+	}
+	result.Result = &output
+	return result
+}
+
+// responseModel returns override if set, otherwise the model a response names
+// in "model" or Gemini's "modelVersion".
+func responseModel(input []byte, override string) string {
+	if override != "" {
+		return override
+	}
+	var resp struct {
+		Model        string `json:"model"`
+		ModelVersion string `json:"modelVersion"`
+	}
+	json.Unmarshal(input, &resp) // Best effort; parse errors are reported by calculate
+	if resp.Model != "" {
+		return resp.Model
+	}
+	return resp.ModelVersion
+}
+
+func printDirJSON(w io.Writer, out DirOutputJSON) {
+	// Ensure files is never null in JSON
+	if out.Files == nil {
+		out.Files = []FileResultJSON{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.Encode(out)
+}
+
+func printDirHuman(w io.Writer, out DirOutputJSON, money moneyFunc) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "FILE\tFORMAT\tTOTAL")
+	for _, f := range out.Files {
+		total := "error: " + f.Error
+		switch {
+		case f.Result == nil:
+		case f.Result.Unknown:
+			total = "unknown model"
+		default:
+			total = money(f.Result.TotalCost, 6)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", f.File, f.Format, total)
+	}
+	tw.Flush()
+
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "Files:       %d (%d errors, %d unknown)\n", out.FileCount, out.ErrorCount, out.UnknownCount)
+	fmt.Fprintf(w, "Total:       %s\n", money(out.TotalCost, 6))
+	if len(out.UnknownModels) > 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "Unknown models:")
+		for _, m := range out.UnknownModels {
+			fmt.Fprintf(w, "  %s (%d)", m.Model, m.Count)
+			if len(m.NearMatches) > 0 {
+				fmt.Fprintf(w, ": did you mean %s?", strings.Join(m.NearMatches, ", "))
+			}
+			fmt.Fprintln(w)
+		}
+	}
+}
@@ -0,0 +1,204 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// testBatch is the batch configuration used by tests: concurrent, in input order.
+var testBatch = batchConfig{workers: 4, ordered: true}
+
+func writeResponses(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestProcessDir(t *testing.T) {
+	dir := writeResponses(t, map[string]string{
+		"a_gemini.json":  `{"modelVersion": "gemini-2.5-flash", "usageMetadata": {"promptTokenCount": 1000, "candidatesTokenCount": 500}}`,
+		"b_openai.json":  `{"model": "gpt-4o", "usage": {"prompt_tokens": 1000, "completion_tokens": 500}}`,
+		"c_unknown.json": `{"model": "no-such-model", "usage": {"prompt_tokens": 1000}}`,
+		"d_broken.json":  `{not json`,
+		"notes.txt":      `ignored`,
+	})
+
+	out, err := processDir(context.Background(), dir, "*.json", testBatch)
+	if err != nil {
+		t.Fatalf("processDir failed: %v", err)
+	}
+	if out.FileCount != 4 || out.ErrorCount != 1 || out.UnknownCount != 1 {
+		t.Fatalf("expected 4 files, 1 error, 1 unknown; got %+v", out)
+	}
+
+	// Results are in file name order regardless of completion order
+	wantNames := []string{"a_gemini.json", "b_openai.json", "c_unknown.json", "d_broken.json"}
+	wantFormats := []string{"gemini", "openai", "openai", ""} // malformed JSON fails validation before detection
+	var sum float64
+	for i, f := range out.Files {
+		if filepath.Base(f.File) != wantNames[i] {
+			t.Errorf("file %d: expected %s, got %s", i, wantNames[i], f.File)
+		}
+		if f.Format != wantFormats[i] {
+			t.Errorf("%s: expected format %q, got %q", f.File, wantFormats[i], f.Format)
+		}
+		if f.Result != nil {
+			sum += f.Result.TotalCost
+		}
+	}
+	if out.Files[3].Error == "" || out.Files[3].Result != nil {
+		t.Errorf("expected parse error for broken file, got %+v", out.Files[3])
+	}
+	if out.TotalCost <= 0 || out.TotalCost != sum {
+		t.Errorf("expected total %f, got %f", sum, out.TotalCost)
+	}
+}
+
+func TestProcessDir_Errors(t *testing.T) {
+	if _, err := processDir(context.Background(), filepath.Join(t.TempDir(), "missing"), "*.json", testBatch); err == nil {
+		t.Error("expected error for missing directory")
+	}
+	if _, err := processDir(context.Background(), t.TempDir(), "[", testBatch); err == nil {
+		t.Error("expected error for invalid glob")
+	}
+}
+
+func TestPrintDirOutput(t *testing.T) {
+	dir := writeResponses(t, map[string]string{
+		"a.json": `{"model": "gpt-4o", "usage": {"prompt_tokens": 1000, "completion_tokens": 500}}`,
+	})
+	out, err := processDir(context.Background(), dir, "*.json", testBatch)
+	if err != nil {
+		t.Fatalf("processDir failed: %v", err)
+	}
+
+	var buf bytes.Buffer
+	printDirJSON(&buf, out)
+	var decoded DirOutputJSON
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("output is not valid JSON: %v", err)
+	}
+	if decoded.Schema != outputSchema || decoded.FileCount != 1 || decoded.TotalCost != out.TotalCost {
+		t.Errorf("round-trip mismatch: %+v", decoded)
+	}
+
+	buf.Reset()
+	printDirHuman(&buf, out, usdMoney)
+	if !strings.Contains(buf.String(), "a.json") || !strings.Contains(buf.String(), "Total:") {
+		t.Errorf("unexpected human output: %s", buf.String())
+	}
+
+	// An empty directory still encodes files as []
+	buf.Reset()
+	printDirJSON(&buf, DirOutputJSON{})
+	if !strings.Contains(buf.String(), `"files": []`) {
+		t.Errorf("files should be [] not null, got %s", buf.String())
+	}
+}
+
+func TestProcessNDJSON(t *testing.T) {
+	input := strings.Join([]string{
+		`{"model": "gpt-4o", "usage": {"prompt_tokens": 1000, "completion_tokens": 500}}`,
+		``,
+		`{"modelVersion": "gemini-2.5-flash", "usageMetadata": {"promptTokenCount": 1000}}`,
+		`{not json`,
+	}, "\n")
+
+	var buf bytes.Buffer
+	totals, err := processNDJSON(strings.NewReader(input), &buf, testBatch)
+	if err != nil {
+		t.Fatalf("processNDJSON failed: %v", err)
+	}
+	if totals.FileCount != 3 || totals.ErrorCount != 1 {
+		t.Errorf("expected 3 records with 1 error, got %+v", totals)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("expected 3 results and a summary, got %d lines:\n%s", len(lines), buf.String())
+	}
+	// Blank lines are skipped but still counted in line numbers
+	for i, wantLine := range []int{1, 3, 4} {
+		var f FileResultJSON
+		if err := json.Unmarshal([]byte(lines[i]), &f); err != nil {
+			t.Fatalf("line %d is not valid JSON: %v", i, err)
+		}
+		if f.Line != wantLine {
+			t.Errorf("result %d: expected line %d, got %d", i, wantLine, f.Line)
+		}
+	}
+
+	var summary SummaryJSON
+	if err := json.Unmarshal([]byte(lines[3]), &summary); err != nil {
+		t.Fatalf("summary is not valid JSON: %v", err)
+	}
+	if summary.Summary.RecordCount != 3 || summary.Summary.TotalCost != totals.TotalCost || totals.TotalCost <= 0 {
+		t.Errorf("unexpected summary %+v for totals %+v", summary.Summary, totals)
+	}
+}
+
+func TestProcessNDJSON_LineTooLong(t *testing.T) {
+	input := strings.Repeat("x", maxNDJSONLine+1)
+	if _, err := processNDJSON(strings.NewReader(input), io.Discard, testBatch); err == nil {
+		t.Error("expected error for oversized line")
+	}
+}
+
+func TestProcessNDJSON_NearMatches(t *testing.T) {
+	input := strings.Join([]string{
+		`{"model": "gtp-4o", "usage": {"prompt_tokens": 1000}}`,
+		`{"model": "zzzz-no-such-model", "usage": {"prompt_tokens": 1000}}`,
+		`{"model": "gtp-4o", "usage": {"prompt_tokens": 1000}}`,
+		`{"model": "gpt-4o", "usage": {"prompt_tokens": 1000}}`,
+	}, "\n")
+	cfg := testBatch
+	cfg.nearMatches = true
+
+	var buf bytes.Buffer
+	if _, err := processNDJSON(strings.NewReader(input), &buf, cfg); err != nil {
+		t.Fatalf("processNDJSON failed: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 5 {
+		t.Fatalf("expected 4 results and a summary, got %d lines:\n%s", len(lines), buf.String())
+	}
+
+	var typo, known FileResultJSON
+	json.Unmarshal([]byte(lines[0]), &typo)
+	json.Unmarshal([]byte(lines[3]), &known)
+	if typo.Result == nil || !slices.Contains(typo.Result.NearMatches, "gpt-4o") {
+		t.Errorf("expected gpt-4o as a near match for gtp-4o, got %+v", typo.Result)
+	}
+	if known.Result == nil || known.Result.NearMatches != nil {
+		t.Errorf("expected no near matches for a known model, got %+v", known.Result)
+	}
+
+	// Most frequent first; models with no near match still appear
+	var summary SummaryJSON
+	if err := json.Unmarshal([]byte(lines[4]), &summary); err != nil {
+		t.Fatalf("summary is not valid JSON: %v", err)
+	}
+	unknown := summary.Summary.UnknownModels
+	if len(unknown) != 2 || unknown[0].Model != "gtp-4o" || unknown[0].Count != 2 || unknown[1].Model != "zzzz-no-such-model" || len(unknown[1].NearMatches) != 0 {
+		t.Errorf("unexpected unknown_models %+v", unknown)
+	}
+
+	// Off by default
+	buf.Reset()
+	processNDJSON(strings.NewReader(input), &buf, testBatch)
+	if strings.Contains(buf.String(), "near_matches") || strings.Contains(buf.String(), "unknown_models") {
+		t.Errorf("expected no near matches without -near-matches, got:\n%s", buf.String())
+	}
+}
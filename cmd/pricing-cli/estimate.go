@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	pricing "github.com/ai8future/pricing_db"
+)
+
+// errUnknownModel reports that the estimate subcommand's model is not in the pricing data.
+var errUnknownModel = errors.New("model not found in pricing data")
+
+// EstimateJSON is the `estimate -json` output.
+type EstimateJSON struct {
+	Model        string     `json:"model"`
+	PromptTokens int64      `json:"prompt_tokens"` // Approximate; see pricing_db.ApproxTokenCount
+	OutputTokens int64      `json:"output_tokens"`
+	Cost         OutputJSON `json:"cost"`
+}
+
+func setupEstimate(fs *flag.FlagSet, env *commandEnv) func(ctx context.Context, args []string) int {
+	var p estimateParams
+	fs.StringVar(&p.model, "model", "", "Model to price (required)")
+	fs.StringVar(&p.file, "f", "", "Read prompt text from file (default: stdin)")
+	fs.Int64Var(&p.outputTokens, "output-tokens", 0, "Expected output length in tokens")
+	fs.BoolVar(&p.batch, "batch", false, "Apply batch mode pricing")
+	fs.BoolVar(&p.asJSON, "json", false, "JSON output (default: human-readable)")
+	return func(ctx context.Context, args []string) int {
+		if err := runEstimate(env.stdout, env.stdin, p); err != nil {
+			if errors.Is(err, errUnknownModel) {
+				return commandError(env, "estimate", err, exitUnknownModel)
+			}
+			return commandError(env, "estimate", err, exitError)
+		}
+		return exitOK
+	}
+}
+
+// estimateParams are the estimate command's flags.
+type estimateParams struct {
+	model        string
+	file         string
+	outputTokens int64
+	batch        bool
+	asJSON       bool
+}
+
+// runEstimate estimates the cost of a prompt read from p.file (or stdin) plus an
+// expected output length. It returns errUnknownModel if the model has no pricing.
+func runEstimate(w io.Writer, stdin io.Reader, p estimateParams) error {
+	if p.model == "" {
+		return errors.New("-model is required")
+	}
+	if p.outputTokens < 0 {
+		return errors.New("-output-tokens must not be negative")
+	}
+
+	var prompt []byte
+	var err error
+	if p.file != "" {
+		prompt, err = os.ReadFile(p.file)
+	} else {
+		prompt, err = io.ReadAll(stdin)
+	}
+	if err != nil {
+		return err
+	}
+
+	var opts *pricing.CalculateOptions
+	if p.batch {
+		opts = &pricing.CalculateOptions{BatchMode: true}
+	}
+	details := pricing.EstimateTextCost(p.model, string(prompt), p.outputTokens, opts)
+	if details.Unknown {
+		return fmt.Errorf("%w: %q", errUnknownModel, p.model)
+	}
+
+	out := EstimateJSON{
+		Model:        p.model,
+		PromptTokens: pricing.CountTokens(p.model, string(prompt)),
+		OutputTokens: p.outputTokens,
+		Cost:         toOutputJSON(details),
+	}
+	if p.asJSON {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(out)
+	}
+
+	fmt.Fprintf(w, "Model:          %s\n", out.Model)
+	fmt.Fprintf(w, "Prompt tokens:  ~%d (estimated)\n", out.PromptTokens)
+	fmt.Fprintf(w, "Output tokens:  %d\n", out.OutputTokens)
+	if p.batch {
+		fmt.Fprintln(w, "Batch Mode:     enabled")
+	}
+	fmt.Fprintf(w, "Estimated cost: $%.6f\n", details.TotalCost)
+	for _, warning := range details.Warnings {
+		fmt.Fprintf(w, "  - %s\n", warning)
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	pricing "github.com/ai8future/pricing_db"
+)
+
+// FreshnessJSON is one provider's entry in `freshness -json` output.
+type FreshnessJSON struct {
+	Provider   string   `json:"provider"`
+	File       string   `json:"file"`
+	Updated    string   `json:"updated"`
+	AgeDays    *int     `json:"age_days"` // nil if updated is missing or invalid
+	Stale      bool     `json:"stale"`
+	SourceURLs []string `json:"source_urls"`
+}
+
+func setupFreshness(fs *flag.FlagSet, env *commandEnv) func(ctx context.Context, args []string) int {
+	maxAgeDays := fs.Int("max-age-days", 90, "Flag providers updated more than this many days ago")
+	jsonFlag := fs.Bool("json", false, "JSON output (default: table)")
+	return func(ctx context.Context, args []string) int {
+		if *maxAgeDays < 0 {
+			return commandError(env, "freshness", errors.New("-max-age-days must not be negative"), exitError)
+		}
+		p, err := pricing.NewPricer()
+		if err != nil {
+			return commandError(env, "freshness", err, exitError)
+		}
+		if err := runFreshness(env.stdout, p, *maxAgeDays, *jsonFlag); err != nil {
+			return commandError(env, "freshness", err, exitError)
+		}
+		return exitOK
+	}
+}
+
+// runFreshness writes each provider's metadata.updated date and source URLs to
+// w, flagging providers older than maxAgeDays (or with no valid date).
+func runFreshness(w io.Writer, p *pricing.Pricer, maxAgeDays int, asJSON bool) error {
+	maxAge := time.Duration(maxAgeDays) * 24 * time.Hour
+	stale := make(map[string]bool)
+	for _, src := range p.StaleProviders(maxAge) {
+		stale[src.Provider] = true
+	}
+
+	now := time.Now()
+	sources := p.ProviderSources()
+	entries := make([]FreshnessJSON, len(sources))
+	for i, src := range sources {
+		entries[i] = FreshnessJSON{
+			Provider:   src.Provider,
+			File:       src.File,
+			Updated:    src.Metadata.Updated,
+			Stale:      stale[src.Provider],
+			SourceURLs: src.Metadata.SourceURLs,
+		}
+		if entries[i].SourceURLs == nil {
+			entries[i].SourceURLs = []string{}
+		}
+		if updated, ok := src.Metadata.UpdatedTime(); ok {
+			days := int(now.Sub(updated).Hours() / 24)
+			entries[i].AgeDays = &days
+		}
+	}
+
+	if asJSON {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(entries)
+	}
+
+	var old, undated int
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "PROVIDER\tUPDATED\tAGE\tSTATUS\tSOURCES")
+	for _, e := range entries {
+		updated, age, status := e.Updated, "-", "ok"
+		if updated == "" {
+			updated = "-"
+		}
+		if e.AgeDays != nil {
+			age = fmt.Sprintf("%dd", *e.AgeDays)
+		}
+		switch {
+		case e.Stale && e.AgeDays == nil:
+			status = "UNDATED"
+			undated++
+		case e.Stale:
+			status = "STALE"
+			old++
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", e.Provider, updated, age, status, strings.Join(e.SourceURLs, " "))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "\n%d of %d providers older than %d days", old, len(entries), maxAgeDays)
+	if undated > 0 {
+		fmt.Fprintf(w, ", %d with no valid updated date", undated)
+	}
+	_, err := fmt.Fprintln(w)
+	return err
+}
@@ -0,0 +1,204 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	pricing "github.com/ai8future/pricing_db"
+	"github.com/ai8future/pricing_db/parquetio"
+)
+
+// ingestColumns is the header of the priced CSV written by `ingest -o`.
+var ingestColumns = []string{
+	"model", "timestamp", "input_tokens", "output_tokens", "cached_tokens", "thinking_tokens", "batch_mode",
+	"input_cost", "cached_input_cost", "output_cost", "thinking_cost", "total_cost", "unknown",
+}
+
+func setupIngest(fs *flag.FlagSet, env *commandEnv) func(ctx context.Context, args []string) int {
+	file := fs.String("f", "", "Read usage records (CSV with a header row, or Parquet) from file or s3://, gs:// URI instead of stdin")
+	output := fs.String("o", "", "Write the priced records as CSV to this file (\"-\" for stdout)")
+	columns := fs.String("columns", "", "Map fields to header columns: model=col,input_tokens=col,output_tokens=col,cached_tokens=col,thinking_tokens=col,timestamp=col,batch_mode=col")
+	pricingPath := fs.String("pricing", "", "Pricing: a config directory or Export snapshot (default: embedded)")
+	jsonFlag := fs.Bool("json", false, "JSON output (default: table)")
+	noDecompress := fs.Bool("no-decompress", false, "Read a gzip/zstd-compressed input as is instead of decompressing it")
+	localeFlags := defineLocaleFlags(fs)
+	return func(ctx context.Context, args []string) int {
+		money, err := localeFlags.resolve(env.cfg)
+		if err != nil {
+			return commandError(env, "ingest", err, exitError)
+		}
+		cols, err := parseCSVColumns(*columns)
+		if err != nil {
+			return commandError(env, "ingest", err, exitError)
+		}
+		p, err := loadPricer(*pricingPath)
+		if err != nil {
+			return commandError(env, "ingest", err, exitParseError)
+		}
+
+		in, err := openInput(ctx, *file, env.stdin, *noDecompress)
+		if err != nil {
+			return commandError(env, "ingest", err, exitError)
+		}
+		records, err := readIngestRecords(in, cols)
+		in.Close()
+		if err != nil {
+			return commandError(env, "ingest", err, exitParseError)
+		}
+
+		results := p.CalculateBatchUsage(records)
+		if *output != "" {
+			if err := writeIngestOutput(*output, env.stdout, records, results); err != nil {
+				return commandError(env, "ingest", err, exitError)
+			}
+		}
+
+		out := toIngestReport(records, results)
+		switch {
+		case *output == "-":
+			// The priced records went to stdout; keep it machine-readable.
+		case *jsonFlag:
+			enc := json.NewEncoder(env.stdout)
+			enc.SetIndent("", "  ")
+			enc.Encode(out)
+		default:
+			printReportHuman(env.stdout, out, money)
+		}
+		return exitOK
+	}
+}
+
+// parseCSVColumns parses a -columns mapping of field=header pairs.
+func parseCSVColumns(s string) (pricing.CSVColumns, error) {
+	var cols pricing.CSVColumns
+	if s == "" {
+		return cols, nil
+	}
+	fields := map[string]*string{
+		"model":           &cols.Model,
+		"input_tokens":    &cols.InputTokens,
+		"output_tokens":   &cols.OutputTokens,
+		"cached_tokens":   &cols.CachedTokens,
+		"thinking_tokens": &cols.ThinkingTokens,
+		"timestamp":       &cols.Timestamp,
+		"batch_mode":      &cols.BatchMode,
+	}
+	for _, pair := range strings.Split(s, ",") {
+		key, value, ok := strings.Cut(pair, "=")
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		dst := fields[key]
+		if !ok || dst == nil || value == "" {
+			return cols, fmt.Errorf("invalid -columns entry %q (want field=column)", pair)
+		}
+		*dst = value
+	}
+	return cols, nil
+}
+
+// readIngestRecords reads usage records from r, as CSV or, when r starts with
+// the Parquet magic, as a Parquet file.
+func readIngestRecords(r io.Reader, cols pricing.CSVColumns) ([]pricing.UsageRecord, error) {
+	br := bufio.NewReader(r)
+	if head, _ := br.Peek(len(parquetio.Magic)); bytes.Equal(head, parquetio.Magic) {
+		return readParquetRecords(br, cols)
+	}
+	return pricing.ReadUsageCSV(br, cols)
+}
+
+// readParquetRecords reads usage records from a Parquet file. The table is
+// re-encoded as CSV and parsed by ReadUsageCSV, so column mapping and value
+// parsing match CSV input exactly; errors number rows as CSV lines, with the
+// column names as line 1.
+func readParquetRecords(r io.Reader, cols pricing.CSVColumns) ([]pricing.UsageRecord, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	t, err := parquetio.ReadTable(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	cw := csv.NewWriter(&buf)
+	header := make([]string, len(t.Columns))
+	for i, c := range t.Columns {
+		header[i] = c.Name
+	}
+	cw.Write(header)
+	cw.WriteAll(t.Rows)
+	if err := cw.Error(); err != nil {
+		return nil, err
+	}
+	return pricing.ReadUsageCSV(&buf, cols)
+}
+
+// writeIngestOutput writes the priced records to name, or to stdout when name
+// is "-".
+func writeIngestOutput(name string, stdout io.Writer, records []pricing.UsageRecord, results []pricing.CostDetails) error {
+	if name == "-" {
+		return writeIngestCSV(stdout, records, results)
+	}
+	f, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	if err := writeIngestCSV(f, records, results); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// writeIngestCSV writes each record and its cost as a row of ingestColumns.
+func writeIngestCSV(w io.Writer, records []pricing.UsageRecord, results []pricing.CostDetails) error {
+	cw := csv.NewWriter(w)
+	cw.Write(ingestColumns)
+	for i, rec := range records {
+		cost := results[i]
+		var at string
+		if !rec.At.IsZero() {
+			at = rec.At.UTC().Format(time.RFC3339)
+		}
+		cw.Write([]string{
+			rec.Model,
+			at,
+			strconv.FormatInt(rec.Usage.PromptTokens, 10),
+			strconv.FormatInt(rec.Usage.CompletionTokens, 10),
+			strconv.FormatInt(rec.Usage.CachedTokens, 10),
+			strconv.FormatInt(rec.Usage.ThinkingTokens, 10),
+			strconv.FormatBool(cost.BatchMode),
+			formatCSVCost(cost.StandardInputCost),
+			formatCSVCost(cost.CachedInputCost),
+			formatCSVCost(cost.OutputCost),
+			formatCSVCost(cost.ThinkingCost),
+			formatCSVCost(cost.TotalCost),
+			strconv.FormatBool(cost.Unknown),
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func formatCSVCost(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+// toIngestReport aggregates priced records by model, like `report`.
+func toIngestReport(records []pricing.UsageRecord, results []pricing.CostDetails) ReportJSON {
+	b := reportBuilder{models: make(map[string]*ModelReportJSON)}
+	for i, rec := range records {
+		result := toOutputJSON(results[i])
+		b.add(FileResultJSON{Model: rec.Model, Result: &result})
+	}
+	return b.report()
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+
+	pricing "github.com/ai8future/pricing_db"
+)
+
+func setupLiteLLM(fs *flag.FlagSet, env *commandEnv) func(ctx context.Context, args []string) int {
+	importFile := fs.String("import", "", "Convert this LiteLLM model_prices_and_context_window.json (file or s3://, gs:// URI) into provider configs")
+	dir := fs.String("dir", ".", "Directory to write imported *_pricing.json configs to")
+	missing := fs.Bool("missing", false, "Import only models the embedded catalog does not price")
+	pricingPath := fs.String("pricing", "", "Pricing to export: a config directory or Export snapshot (default: embedded)")
+	return func(ctx context.Context, args []string) int {
+		p, err := loadPricer(*pricingPath)
+		if err != nil {
+			return commandError(env, "litellm", err, exitParseError)
+		}
+		if *importFile == "" {
+			if err := p.ExportLiteLLM(env.stdout); err != nil {
+				return commandError(env, "litellm", err, exitError)
+			}
+			return exitOK
+		}
+
+		in, err := openInput(ctx, *importFile, env.stdin, false)
+		if err != nil {
+			return commandError(env, "litellm", err, exitError)
+		}
+		imp, err := pricing.ImportLiteLLM(in)
+		in.Close()
+		if err != nil {
+			return commandError(env, "litellm", err, exitParseError)
+		}
+		for _, name := range slices.Sorted(maps.Keys(imp.Providers)) {
+			pp := imp.Providers[name]
+			if *missing {
+				for model := range pp.Models {
+					if _, ok := p.GetPricing(name + "/" + model); ok {
+						delete(pp.Models, model)
+					}
+				}
+				if len(pp.Models) == 0 {
+					continue
+				}
+			}
+			path := filepath.Join(*dir, name+"_pricing.json")
+			if err := writeProviderConfig(path, pp); err != nil {
+				return commandError(env, "litellm", err, exitError)
+			}
+			fmt.Fprintf(env.stdout, "%s: %d models\n", path, len(pp.Models))
+		}
+		if len(imp.Skipped) > 0 {
+			fmt.Fprintf(env.stdout, "Skipped %d entries:\n", len(imp.Skipped))
+			for _, s := range imp.Skipped {
+				fmt.Fprintf(env.stdout, "  %s\n", s)
+			}
+		}
+		return exitOK
+	}
+}
+
+// writeProviderConfig writes pp to path as an indented *_pricing.json config.
+func writeProviderConfig(path string, pp pricing.ProviderPricing) error {
+	data, err := json.MarshalIndent(pp, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
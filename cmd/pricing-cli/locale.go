@@ -0,0 +1,80 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/ai8future/pricing_db/currencyfmt"
+)
+
+// moneyFunc formats a USD amount with the given decimal places for human output.
+type moneyFunc func(usd float64, decimals int) string
+
+// usdMoney is the default moneyFunc: "$0.001234".
+func usdMoney(usd float64, decimals int) string {
+	return fmt.Sprintf("$%.*f", decimals, usd)
+}
+
+// localeFlags select locale-aware currency formatting for human output.
+type localeFlags struct {
+	locale, currency *string
+}
+
+func defineLocaleFlags(fs *flag.FlagSet) localeFlags {
+	return localeFlags{
+		locale:   fs.String("locale", "", "Format human-readable costs for this BCP 47 locale, e.g. de-DE (default: $1234.567890)"),
+		currency: fs.String("currency", "", "With -locale, show costs in this currency as CODE=RATE per USD, e.g. EUR=0.92"),
+	}
+}
+
+// resolve returns the moneyFunc for the flags, falling back to PRICING_LOCALE
+// and PRICING_CURRENCY.
+func (f localeFlags) resolve(cfg CLIConfig) (moneyFunc, error) {
+	locale, cur := cfg.Locale, cfg.Currency
+	if *f.locale != "" {
+		locale = *f.locale
+	}
+	if *f.currency != "" {
+		cur = *f.currency
+	}
+	if locale == "" {
+		if cur != "" {
+			return nil, fmt.Errorf("-currency requires -locale")
+		}
+		return usdMoney, nil
+	}
+	var opts []currencyfmt.Option
+	if cur != "" {
+		code, rate, ok := strings.Cut(cur, "=")
+		perUSD, err := strconv.ParseFloat(rate, 64)
+		if !ok || err != nil {
+			return nil, fmt.Errorf("-currency %q: expected CODE=RATE, e.g. EUR=0.92", cur)
+		}
+		opts = append(opts, currencyfmt.WithCurrency(code, perUSD))
+	}
+	formatter, err := currencyfmt.New(locale, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return formatter.FormatDecimals, nil
+}
+
+// withMoney returns a copy of tmpl whose usd function formats with money.
+func withMoney(tmpl *template.Template, money moneyFunc) (*template.Template, error) {
+	clone, err := tmpl.Clone()
+	if err != nil {
+		return nil, err
+	}
+	return clone.Funcs(template.FuncMap{
+		"usd": func(value float64, decimals ...int) string {
+			precision := 6
+			if len(decimals) > 0 {
+				precision = decimals[0]
+			}
+			return money(value, precision)
+		},
+	}), nil
+}
@@ -1,34 +1,45 @@
-// pricing-cli calculates costs for Gemini API JSON responses.
+// pricing-cli calculates costs for LLM API JSON responses (Gemini, OpenAI,
+// Anthropic, and other providers), detecting the response format.
 package main
 
 import (
+	"context"
 	"encoding/json"
-	"flag"
 	"fmt"
 	"io"
 	"log"
 	"os"
+	"strings"
+	"text/template"
 
 	chassis "github.com/ai8future/chassis-go/v11"
 	"github.com/ai8future/chassis-go/v11/config"
 	"github.com/ai8future/chassis-go/v11/deploy"
-	"github.com/ai8future/chassis-go/v11/logz"
 	"github.com/ai8future/chassis-go/v11/registry"
-	"github.com/ai8future/chassis-go/v11/secval"
 	pricing "github.com/ai8future/pricing_db"
 )
 
-
 // CLIConfig holds environment-based configuration overrides.
 // Flags take precedence over these values when explicitly set.
 type CLIConfig struct {
 	DefaultModel string `env:"PRICING_DEFAULT_MODEL" required:"false"`
 	BatchMode    bool   `env:"PRICING_BATCH_MODE" required:"false"`
 	LogLevel     string `env:"PRICING_LOG_LEVEL" default:"warn"`
+	Template     string `env:"PRICING_TEMPLATE" required:"false"` // -template default
+	Locale       string `env:"PRICING_LOCALE" required:"false"`   // -locale default
+	Currency     string `env:"PRICING_CURRENCY" required:"false"` // -currency default
 }
 
+// outputSchema is the version of the JSON output format, reported in every
+// OutputJSON and -dir output as "schema". The minor version increases when
+// fields are added; the major version when a field is removed, renamed, or
+// changes meaning. Parsers should check the major version and ignore unknown
+// fields.
+const outputSchema = "1.1"
+
 // OutputJSON represents the JSON output format
 type OutputJSON struct {
+	Schema            string   `json:"schema"` // outputSchema
 	StandardInputCost float64  `json:"standard_input_cost"`
 	CachedInputCost   float64  `json:"cached_input_cost"`
 	OutputCost        float64  `json:"output_cost"`
@@ -40,6 +51,55 @@ type OutputJSON struct {
 	BatchMode         bool     `json:"batch_mode"`
 	Warnings          []string `json:"warnings"`
 	Unknown           bool     `json:"unknown"`
+	PricingVersion    string   `json:"pricing_version"` // Catalog version that produced the figures
+	billedTotalsJSON
+	// NearMatches are the known models closest to an unknown model, with -near-matches.
+	NearMatches []string `json:"near_matches,omitempty"`
+	// Details is the full cost breakdown, included with -details.
+	Details *DetailsJSON `json:"details,omitempty"`
+}
+
+// DetailsJSON is the -details part of OutputJSON: every nonzero cost component,
+// structured warnings, and the pricing entry the model matched.
+type DetailsJSON struct {
+	MatchedModel string         `json:"matched_model,omitempty"` // Pricing key after prefix matching; empty if unknown
+	Provider     string         `json:"provider,omitempty"`
+	LineItems    []LineItemJSON `json:"line_items"`
+	RawTotal     float64        `json:"raw_total"` // Unrounded total
+	Warnings     []WarningJSON  `json:"warnings"`
+}
+
+// LineItemJSON is one cost component, e.g. {"item": "output", "cost": 0.005}.
+type LineItemJSON struct {
+	Item string  `json:"item"`
+	Cost float64 `json:"cost"`
+}
+
+// WarningJSON is a warning with its stable code, e.g. "deprecated_model".
+type WarningJSON struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// Exit codes, so CI jobs can gate on pricing coverage.
+const (
+	exitOK           = 0
+	exitError        = 1 // Usage or I/O error
+	exitUnknownModel = 2 // A result was priced as Unknown and -fail-on-unknown is set
+	exitParseError   = 3 // Input could not be validated or parsed
+)
+
+// exitCode returns the exit code for a run with the given number of inputs that
+// failed to parse and results priced as Unknown. Parse errors take precedence.
+func exitCode(parseErrors, unknown int, failOnUnknown bool) int {
+	switch {
+	case parseErrors > 0:
+		return exitParseError
+	case unknown > 0 && failOnUnknown:
+		return exitUnknownModel
+	default:
+		return exitOK
+	}
 }
 
 // loadConfig loads CLIConfig from environment variables via chassis config.
@@ -63,137 +123,59 @@ func main() {
 	}
 	defer registry.ShutdownCLI(0)
 
-	// Define flags
-	fileFlag := flag.String("f", "", "Read JSON from file (default: stdin)")
-	batchFlag := flag.Bool("batch", false, "Apply batch mode pricing (50% discount)")
-	humanFlag := flag.Bool("human", false, "Human-readable output (default: JSON)")
-	modelFlag := flag.String("model", "", "Override model name (when modelVersion missing)")
-	verboseFlag := flag.Bool("v", false, "Verbose output (debug logging)")
-	// --version is handled by chassis.RequireMajor via SetAppVersion
-
-	flag.Usage = func() {
-		fmt.Fprintf(os.Stderr, "Usage: pricing-cli [options]\n\n")
-		fmt.Fprintf(os.Stderr, "Calculate costs for Gemini API JSON responses.\n\n")
-		fmt.Fprintf(os.Stderr, "Options:\n")
-		flag.PrintDefaults()
-		fmt.Fprintf(os.Stderr, "\nEnvironment variables:\n")
-		fmt.Fprintf(os.Stderr, "  PRICING_DEFAULT_MODEL   Default model name\n")
-		fmt.Fprintf(os.Stderr, "  PRICING_BATCH_MODE      Enable batch mode (true/false)\n")
-		fmt.Fprintf(os.Stderr, "  PRICING_LOG_LEVEL       Log level (debug, info, warn, error)\n")
-		fmt.Fprintf(os.Stderr, "\nExamples:\n")
-		fmt.Fprintf(os.Stderr, "  cat response.json | pricing-cli\n")
-		fmt.Fprintf(os.Stderr, "  pricing-cli -f response.json\n")
-		fmt.Fprintf(os.Stderr, "  pricing-cli -batch -human -f response.json\n")
-		fmt.Fprintf(os.Stderr, "  pricing-cli -model gemini-2.5-flash -f response.json\n")
-	}
-
-	flag.Parse()
-
-	// Resolve log level: -v flag overrides env config
-	logLevel := cfg.LogLevel
-	if *verboseFlag {
-		logLevel = "debug"
-	}
-	logger := logz.New(logLevel)
-
-	// Resolve model: flag overrides env config
-	model := cfg.DefaultModel
-	if *modelFlag != "" {
-		model = *modelFlag
+	env := &commandEnv{stdin: os.Stdin, stdout: os.Stdout, stderr: os.Stderr, cfg: cfg}
+	if code := run(context.Background(), env, os.Args[1:]); code != exitOK {
+		os.Exit(code)
 	}
+}
 
-	// Resolve batch mode: flag overrides env config
-	batchMode := cfg.BatchMode
-	if *batchFlag {
-		batchMode = true
+// calculate prices input as a response in provider's format, detecting the format
+// when provider is empty, and returns the format used. Input matching no known
+// format is parsed as a Gemini response, the CLI's original input format.
+func calculate(input []byte, provider, model string, opts *pricing.CalculateOptions) (pricing.CostDetails, string, error) {
+	format := strings.ToLower(provider)
+	if format == "" {
+		format, _ = pricing.DetectResponseProvider(input)
 	}
 
-	logger.Debug("configuration resolved",
-		"model", model,
-		"batch_mode", batchMode,
-		"log_level", logLevel,
-	)
-
-	// Read input
-	var input []byte
-	var err error
-
-	if *fileFlag != "" {
-		logger.Debug("reading input from file", "path", *fileFlag)
-		input, err = os.ReadFile(*fileFlag)
-		if err != nil {
-			logger.Error("failed to read file", "path", *fileFlag, "error", err)
-			os.Exit(1)
+	switch format {
+	case "", "gemini", "google":
+		if model == "" {
+			details, err := pricing.ParseGeminiResponseWithOptions(input, opts)
+			return details, "gemini", err
 		}
-	} else {
-		// Check if stdin is a terminal (no piped input)
-		stat, _ := os.Stdin.Stat()
-		if (stat.Mode() & os.ModeCharDevice) != 0 {
-			flag.Usage()
-			os.Exit(0)
-		}
-		logger.Debug("reading input from stdin")
-		input, err = io.ReadAll(os.Stdin)
-		if err != nil {
-			logger.Error("failed to read stdin", "error", err)
-			os.Exit(1)
-		}
-	}
-
-	if len(input) == 0 {
-		logger.Error("no input provided")
-		flag.Usage()
-		os.Exit(1)
-	}
-
-	logger.Debug("input read", "bytes", len(input))
-
-	// Security: reject dangerous JSON keys before parsing
-	if err := secval.ValidateJSON(input); err != nil {
-		logger.Error("JSON security validation failed", "error", err)
-		os.Exit(1)
-	}
-
-	// Parse and calculate
-	var opts *pricing.CalculateOptions
-	if batchMode {
-		opts = &pricing.CalculateOptions{BatchMode: true}
-	}
-
-	var costDetails pricing.CostDetails
-
-	if model != "" {
-		logger.Debug("using model override", "model", model)
 		// Parse JSON manually to use model override
 		var resp pricing.GeminiResponse
 		if err := json.Unmarshal(input, &resp); err != nil {
-			logger.Error("failed to parse JSON", "error", err)
-			os.Exit(1)
-		}
-		costDetails = pricing.CalculateGeminiResponseCostWithModel(resp, model, opts)
-	} else {
-		costDetails, err = pricing.ParseGeminiResponseWithOptions(input, opts)
-		if err != nil {
-			logger.Error("failed to parse response", "error", err)
-			os.Exit(1)
+			return pricing.CostDetails{}, "gemini", err
 		}
+		return pricing.CalculateGeminiResponseCostWithModel(resp, model, opts), "gemini", nil
+	default:
+		details, err := pricing.CalculateResponseCost(format, input, model, opts)
+		return details, format, err
 	}
+}
 
-	logger.Debug("calculation complete",
-		"total_cost", costDetails.TotalCost,
-		"unknown", costDetails.Unknown,
-	)
-
-	// Output results
-	if *humanFlag {
-		printHuman(costDetails)
-	} else {
-		printJSON(costDetails)
+// runSchema writes the pricing file JSON Schema to w.
+func runSchema(w io.Writer) error {
+	schema, err := pricing.GenerateSchema()
+	if err != nil {
+		return err
 	}
+	_, err = fmt.Fprintln(w, string(schema))
+	return err
 }
 
-func printJSON(c pricing.CostDetails) {
+func printJSON(w io.Writer, output OutputJSON) {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.Encode(output)
+}
+
+// toOutputJSON converts c to the CLI's JSON output format.
+func toOutputJSON(c pricing.CostDetails) OutputJSON {
 	output := OutputJSON{
+		Schema:            outputSchema,
 		StandardInputCost: c.StandardInputCost,
 		CachedInputCost:   c.CachedInputCost,
 		OutputCost:        c.OutputCost,
@@ -205,60 +187,76 @@ func printJSON(c pricing.CostDetails) {
 		BatchMode:         c.BatchMode,
 		Warnings:          c.Warnings,
 		Unknown:           c.Unknown,
+		PricingVersion:    c.PricingVersion,
+	}
+	if c.Markup != 0 || c.Tax != 0 {
+		output.billedTotalsJSON = billedTotalsJSON{Markup: c.Markup, BilledTotal: c.BilledTotal, Tax: c.Tax, GrossTotal: c.GrossTotal}
 	}
 
 	// Ensure warnings is never null in JSON
 	if output.Warnings == nil {
 		output.Warnings = []string{}
 	}
-
-	enc := json.NewEncoder(os.Stdout)
-	enc.SetIndent("", "  ")
-	enc.Encode(output)
+	return output
 }
 
-func printHuman(c pricing.CostDetails) {
-	fmt.Println("Gemini Pricing Breakdown")
-	fmt.Println("========================")
-
-	if c.Unknown {
-		fmt.Println("WARNING: Model not found in pricing database")
-		fmt.Println()
-	}
-
-	tier := c.TierApplied
-	if tier == "" {
-		tier = "standard"
-	}
-	fmt.Printf("Tier: %s\n", tier)
-
-	if c.BatchMode {
-		fmt.Println("Batch Mode: enabled")
+// toDetailsJSON returns the -details breakdown of c, priced for model.
+func toDetailsJSON(c pricing.CostDetails, model string) *DetailsJSON {
+	details := &DetailsJSON{
+		LineItems: []LineItemJSON{},
+		RawTotal:  c.RawTotal,
+		Warnings:  []WarningJSON{},
 	}
-
-	fmt.Println()
-	fmt.Println("Input Costs:")
-	fmt.Printf("  Standard:  $%.6f\n", c.StandardInputCost)
-	fmt.Printf("  Cached:    $%.6f\n", c.CachedInputCost)
-
-	fmt.Println()
-	fmt.Println("Output Costs:")
-	fmt.Printf("  Output:    $%.6f\n", c.OutputCost)
-	fmt.Printf("  Thinking:  $%.6f\n", c.ThinkingCost)
-
-	if c.GroundingCost > 0 {
-		fmt.Println()
-		fmt.Printf("Grounding:   $%.6f\n", c.GroundingCost)
+	if info, ok := pricing.GetModelInfo(model); ok && !c.Unknown {
+		details.MatchedModel = info.Model
+		details.Provider = info.Provider
 	}
-
-	fmt.Println()
-	fmt.Printf("Total:       $%.6f\n", c.TotalCost)
-
-	if len(c.Warnings) > 0 {
-		fmt.Println()
-		fmt.Println("Warnings:")
-		for _, w := range c.Warnings {
-			fmt.Printf("  - %s\n", w)
+	for _, item := range []LineItemJSON{
+		{"standard_input", c.StandardInputCost},
+		{"cached_input", c.CachedInputCost},
+		{"cache_write", c.CacheWriteCost},
+		{"image_input", c.ImageInputCost},
+		{"audio_input", c.AudioInputCost},
+		{"output", c.OutputCost},
+		{"audio_output", c.AudioOutputCost},
+		{"thinking", c.ThinkingCost},
+		{"grounding", c.GroundingCost},
+		{"surcharges", c.SurchargeCost},
+		{"minimum_charge", c.MinimumCharge},
+	} {
+		if item.Cost != 0 {
+			details.LineItems = append(details.LineItems, item)
 		}
 	}
+	for _, w := range c.WarningDetails {
+		details.Warnings = append(details.Warnings, WarningJSON{Code: string(w.Code), Message: w.Message})
+	}
+	return details
 }
+
+// humanTemplate is the default -human output for a single result; -template
+// or PRICING_TEMPLATE replaces it.
+const humanTemplate = `Pricing Breakdown
+=================
+{{if .Unknown}}WARNING: Model not found in pricing database
+
+{{end}}Tier: {{or .TierApplied "standard"}}
+{{if .BatchMode}}Batch Mode: enabled
+{{end}}
+Input Costs:
+  Standard:  {{usd .StandardInputCost}}
+  Cached:    {{usd .CachedInputCost}}
+
+Output Costs:
+  Output:    {{usd .OutputCost}}
+  Thinking:  {{usd .ThinkingCost}}
+{{if gt .GroundingCost 0.0}}
+Grounding:   {{usd .GroundingCost}}
+{{end}}
+Total:       {{usd .TotalCost}}
+{{with .Warnings}}
+Warnings:
+{{range .}}  - {{.}}
+{{end}}{{end}}`
+
+var defaultHumanTemplate = template.Must(pricing.ParseDetailsTemplate(humanTemplate))
@@ -2,10 +2,15 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"fmt"
+	"io"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"testing/fstest"
 
 	chassis "github.com/ai8future/chassis-go/v11"
 	"github.com/ai8future/chassis-go/v11/secval"
@@ -116,17 +121,8 @@ func TestPrintJSON_Output(t *testing.T) {
 		Warnings:          []string{"test warning"},
 	}
 
-	old := os.Stdout
-	r, w, _ := os.Pipe()
-	os.Stdout = w
-
-	printJSON(c)
-
-	w.Close()
-	os.Stdout = old
-
 	var buf bytes.Buffer
-	buf.ReadFrom(r)
+	printJSON(&buf, toOutputJSON(c))
 	output := buf.String()
 
 	var result OutputJSON
@@ -148,17 +144,8 @@ func TestPrintJSON_Output(t *testing.T) {
 func TestPrintJSON_NilWarnings(t *testing.T) {
 	c := pricing.CostDetails{TotalCost: 0.01}
 
-	old := os.Stdout
-	r, w, _ := os.Pipe()
-	os.Stdout = w
-
-	printJSON(c)
-
-	w.Close()
-	os.Stdout = old
-
 	var buf bytes.Buffer
-	buf.ReadFrom(r)
+	printJSON(&buf, toOutputJSON(c))
 	output := buf.String()
 
 	// Verify warnings is [] not null
@@ -167,23 +154,319 @@ func TestPrintJSON_NilWarnings(t *testing.T) {
 	}
 }
 
-func TestPrintHuman_UnknownModel(t *testing.T) {
+func TestHumanTemplate_UnknownModel(t *testing.T) {
 	c := pricing.CostDetails{Unknown: true, TotalCost: 0}
 
-	old := os.Stdout
-	r, w, _ := os.Pipe()
-	os.Stdout = w
-
-	printHuman(c)
-
-	w.Close()
-	os.Stdout = old
-
 	var buf bytes.Buffer
-	buf.ReadFrom(r)
+	if err := defaultHumanTemplate.Execute(&buf, c); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
 	output := buf.String()
 
+	if !strings.HasPrefix(output, "Pricing Breakdown\n") {
+		t.Errorf("expected a provider-neutral header, got: %s", output)
+	}
 	if !strings.Contains(output, "WARNING: Model not found") {
 		t.Errorf("expected unknown model warning in human output, got: %s", output)
 	}
 }
+
+func TestRunSchema(t *testing.T) {
+	var buf bytes.Buffer
+	if err := runSchema(&buf); err != nil {
+		t.Fatalf("runSchema failed: %v", err)
+	}
+
+	var schema map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &schema); err != nil {
+		t.Fatalf("schema output is not valid JSON: %v", err)
+	}
+	if schema["$id"] != pricing.SchemaID {
+		t.Errorf("expected $id %q, got %v", pricing.SchemaID, schema["$id"])
+	}
+}
+
+// runCLI runs pricing-cli with args and stdin, returning its output and exit code.
+func runCLI(t *testing.T, stdin string, args ...string) (stdout, stderr string, code int) {
+	t.Helper()
+	var out, errOut bytes.Buffer
+	env := &commandEnv{stdin: strings.NewReader(stdin), stdout: &out, stderr: &errOut, cfg: CLIConfig{LogLevel: "error"}}
+	code = run(context.Background(), env, args)
+	return out.String(), errOut.String(), code
+}
+
+func TestRunFreshness_Table(t *testing.T) {
+	output, stderr, code := runCLI(t, "", "freshness")
+	if code != exitOK {
+		t.Fatalf("freshness exited %d: %s", code, stderr)
+	}
+
+	for _, want := range []string{"PROVIDER", "openai", "anthropic", "providers older than 90 days"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected %q in output, got: %s", want, output)
+		}
+	}
+}
+
+func TestRunFreshness_Undated(t *testing.T) {
+	p, err := pricing.NewPricerFromFS(fstest.MapFS{
+		"configs/dated_pricing.json": &fstest.MapFile{Data: []byte(`{
+			"models": {"d": {"input_per_million": 1.0, "output_per_million": 1.0}},
+			"metadata": {"updated": "2020-01-01"}
+		}`)},
+		"configs/undated_pricing.json": &fstest.MapFile{Data: []byte(`{
+			"models": {"u": {"input_per_million": 1.0, "output_per_million": 1.0}}
+		}`)},
+	}, "configs")
+	if err != nil {
+		t.Fatalf("NewPricerFromFS failed: %v", err)
+	}
+
+	var out bytes.Buffer
+	if err := runFreshness(&out, p, 90, false); err != nil {
+		t.Fatalf("runFreshness failed: %v", err)
+	}
+	for _, want := range []string{"UNDATED", "1 of 2 providers older than 90 days, 1 with no valid updated date"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("expected %q in output, got: %s", want, out.String())
+		}
+	}
+}
+
+func TestRunFreshness_JSON(t *testing.T) {
+	output, stderr, code := runCLI(t, "", "freshness", "-json", "-max-age-days", "0")
+	if code != exitOK {
+		t.Fatalf("freshness exited %d: %s", code, stderr)
+	}
+
+	var entries []FreshnessJSON
+	if err := json.Unmarshal([]byte(output), &entries); err != nil {
+		t.Fatalf("output is not valid JSON: %v", err)
+	}
+	if len(entries) != pricing.ProviderCount() {
+		t.Fatalf("expected %d providers, got %d", pricing.ProviderCount(), len(entries))
+	}
+	for _, e := range entries {
+		// Every config is dated in the past, so a zero-day threshold flags all
+		if !e.Stale {
+			t.Errorf("%s: expected stale with -max-age-days 0", e.Provider)
+		}
+		if e.AgeDays == nil {
+			t.Errorf("%s: expected age_days for updated %q", e.Provider, e.Updated)
+		}
+	}
+}
+
+func TestRunFreshness_InvalidFlags(t *testing.T) {
+	if _, _, code := runCLI(t, "", "freshness", "-max-age-days", "-1"); code != exitError {
+		t.Errorf("expected exit %d for negative -max-age-days, got %d", exitError, code)
+	}
+}
+
+func TestCalculate_DetectsFormat(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      string
+		provider   string
+		model      string
+		wantFormat string
+	}{
+		{"gemini", `{"modelVersion": "gemini-2.5-flash", "usageMetadata": {"promptTokenCount": 1000, "candidatesTokenCount": 500}}`, "", "", "gemini"},
+		{"openai", `{"model": "gpt-4o", "usage": {"prompt_tokens": 1000, "completion_tokens": 500}}`, "", "", "openai"},
+		{"anthropic", `{"model": "claude-sonnet-4-5", "usage": {"input_tokens": 1000, "output_tokens": 500}}`, "", "", "anthropic"},
+		{"explicit provider", `{"model": "gpt-4o", "usage": {"prompt_tokens": 1000, "completion_tokens": 500}}`, "OpenAI", "", "openai"},
+		{"gemini with model override", `{"usageMetadata": {"promptTokenCount": 1000}}`, "", "gemini-2.5-flash", "gemini"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			details, format, err := calculate([]byte(tt.input), tt.provider, tt.model, nil)
+			if err != nil {
+				t.Fatalf("calculate failed: %v", err)
+			}
+			if format != tt.wantFormat {
+				t.Errorf("expected format %q, got %q", tt.wantFormat, format)
+			}
+			if details.Unknown || details.TotalCost <= 0 {
+				t.Errorf("expected a priced result, got %+v", details)
+			}
+		})
+	}
+}
+
+func TestCalculate_UndetectedFallsBackToGemini(t *testing.T) {
+	details, format, err := calculate([]byte(`{"foo": 1}`), "", "", nil)
+	if err != nil {
+		t.Fatalf("calculate failed: %v", err)
+	}
+	if format != "gemini" || !details.Unknown {
+		t.Errorf("expected unknown gemini result, got format %q, %+v", format, details)
+	}
+}
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name                 string
+		parseErrors, unknown int
+		failOnUnknown        bool
+		want                 int
+	}{
+		{"ok", 0, 0, false, exitOK},
+		{"unknown tolerated", 0, 1, false, exitOK},
+		{"unknown fails", 0, 1, true, exitUnknownModel},
+		{"parse error", 1, 0, false, exitParseError},
+		{"parse error wins", 1, 1, true, exitParseError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := exitCode(tt.parseErrors, tt.unknown, tt.failOnUnknown); got != tt.want {
+				t.Errorf("expected %d, got %d", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestRunEstimate(t *testing.T) {
+	prompt := strings.Repeat("word ", 800) // 4000 bytes, ~1000 tokens
+
+	output, stderr, code := runCLI(t, prompt, "estimate", "-model", "gpt-4o", "-output-tokens", "500", "-json")
+	if code != exitOK {
+		t.Fatalf("estimate exited %d: %s", code, stderr)
+	}
+	var out EstimateJSON
+	if err := json.Unmarshal([]byte(output), &out); err != nil {
+		t.Fatalf("output is not valid JSON: %v", err)
+	}
+	want := pricing.CalculateUsageCost("gpt-4o", pricing.TokenUsage{PromptTokens: 1000, CompletionTokens: 500}, nil)
+	if out.PromptTokens != 1000 || out.OutputTokens != 500 || out.Cost.TotalCost != want.TotalCost {
+		t.Errorf("unexpected estimate %+v, want total %f", out, want.TotalCost)
+	}
+
+	output, _, _ = runCLI(t, prompt, "estimate", "-model", "gpt-4o")
+	if !strings.Contains(output, "Estimated cost:") {
+		t.Errorf("unexpected human output: %s", output)
+	}
+}
+
+func TestRunEstimate_Errors(t *testing.T) {
+	if _, _, code := runCLI(t, "hi", "estimate"); code != exitError {
+		t.Errorf("expected exit %d without -model, got %d", exitError, code)
+	}
+	if _, _, code := runCLI(t, "hi", "estimate", "-model", "gpt-4o", "-output-tokens", "-1"); code != exitError {
+		t.Errorf("expected exit %d for negative -output-tokens, got %d", exitError, code)
+	}
+	if _, _, code := runCLI(t, "hi", "estimate", "-model", "no-such-model"); code != exitUnknownModel {
+		t.Errorf("expected exit %d for unknown model, got %d", exitUnknownModel, code)
+	}
+}
+
+func TestCostTemplate(t *testing.T) {
+	input := `{"modelVersion": "gemini-2.5-flash", "usageMetadata": {"promptTokenCount": 1000, "candidatesTokenCount": 500}}`
+	want := pricing.CalculateUsageCost("gemini-2.5-flash", pricing.TokenUsage{PromptTokens: 1000, CompletionTokens: 500}, nil).TotalCost
+
+	output, stderr, code := runCLI(t, input, "-template", `{{usd .TotalCost 4}} {{.TierApplied}}`)
+	if code != exitOK {
+		t.Fatalf("exited %d: %s", code, stderr)
+	}
+	if expected := fmt.Sprintf("$%.4f standard", want); output != expected {
+		t.Errorf("expected %q, got %q", expected, output)
+	}
+
+	// @file reads the template from a file
+	path := filepath.Join(t.TempDir(), "cost.tmpl")
+	if err := os.WriteFile(path, []byte("total={{.TotalCost}}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if output, _, _ := runCLI(t, input, "-template", "@"+path); !strings.HasPrefix(output, "total=") {
+		t.Errorf("expected template from file, got %q", output)
+	}
+
+	// PRICING_TEMPLATE is the default; the flag overrides it
+	var out bytes.Buffer
+	env := &commandEnv{stdin: strings.NewReader(input), stdout: &out, stderr: io.Discard, cfg: CLIConfig{LogLevel: "error", Template: "env"}}
+	if code := run(context.Background(), env, nil); code != exitOK || out.String() != "env" {
+		t.Errorf("expected PRICING_TEMPLATE output, got %d %q", code, out.String())
+	}
+
+	if _, _, code := runCLI(t, input, "-template", "{{.Bogus"); code != exitError {
+		t.Errorf("expected exit %d for an invalid template, got %d", exitError, code)
+	}
+	if _, _, code := runCLI(t, input, "-template", "{{.NoSuchField}}"); code != exitError {
+		t.Errorf("expected exit %d for a failing template, got %d", exitError, code)
+	}
+}
+
+func TestCostLocale(t *testing.T) {
+	input := `{"modelVersion": "gemini-2.5-flash", "usageMetadata": {"promptTokenCount": 10000000, "candidatesTokenCount": 500}}`
+	want := pricing.CalculateUsageCost("gemini-2.5-flash", pricing.TokenUsage{PromptTokens: 10000000, CompletionTokens: 500}, nil).TotalCost
+
+	output, stderr, code := runCLI(t, input, "-locale", "de-DE", "-currency", "EUR=0.5", "-template", "{{usd .TotalCost 2}}")
+	if code != exitOK {
+		t.Fatalf("exited %d: %s", code, stderr)
+	}
+	expected := strings.Replace(fmt.Sprintf("%.2f\u00a0€", want*0.5), ".", ",", 1)
+	if output != expected {
+		t.Errorf("expected %q, got %q", expected, output)
+	}
+
+	// The default template uses the locale too
+	output, _, _ = runCLI(t, input, "-human", "-locale", "fr-FR")
+	if !strings.Contains(output, "Total:       "+strings.Replace(fmt.Sprintf("%.6f\u00a0$US", want), ".", ",", 1)) {
+		t.Errorf("expected fr-FR total, got: %s", output)
+	}
+
+	for _, args := range [][]string{
+		{"-locale", "not a locale!"},
+		{"-currency", "EUR=0.92"},
+		{"-locale", "de-DE", "-currency", "EUR"},
+	} {
+		if _, _, code := runCLI(t, input, args...); code != exitError {
+			t.Errorf("%v: expected exit %d, got %d", args, exitError, code)
+		}
+	}
+}
+
+func TestCostDetails(t *testing.T) {
+	input := `{"modelVersion": "gemini-2.5-flash", "usageMetadata": {"promptTokenCount": 1000, "candidatesTokenCount": 500}}`
+
+	output, stderr, code := runCLI(t, input)
+	if code != exitOK {
+		t.Fatalf("exited %d: %s", code, stderr)
+	}
+	var plain OutputJSON
+	if err := json.Unmarshal([]byte(output), &plain); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if plain.Schema != outputSchema || plain.Details != nil {
+		t.Errorf("expected schema %q and no details by default, got %+v", outputSchema, plain)
+	}
+
+	output, stderr, code = runCLI(t, input, "-details")
+	if code != exitOK {
+		t.Fatalf("exited %d: %s", code, stderr)
+	}
+	var result OutputJSON
+	if err := json.Unmarshal([]byte(output), &result); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	info, _ := pricing.GetModelInfo("gemini-2.5-flash")
+	d := result.Details
+	if d == nil || d.MatchedModel != info.Model || d.Provider != info.Provider {
+		t.Fatalf("expected details for %s/%s, got %+v", info.Provider, info.Model, d)
+	}
+	if len(d.LineItems) != 2 || d.LineItems[0].Item != "standard_input" || d.LineItems[1].Item != "output" {
+		t.Errorf("expected standard_input and output line items, got %+v", d.LineItems)
+	}
+	if !strings.Contains(output, `"warnings": []`) {
+		t.Errorf("expected empty warning details, got: %s", output)
+	}
+
+	// Unknown models have no matched model
+	output, _, _ = runCLI(t, `{"model": "no-such-model", "usage": {"prompt_tokens": 10, "completion_tokens": 5}}`, "-details", "-provider", "openai")
+	result = OutputJSON{}
+	if err := json.Unmarshal([]byte(output), &result); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if !result.Unknown || result.Details == nil || result.Details.MatchedModel != "" {
+		t.Errorf("expected unknown result without a matched model, got %+v", result)
+	}
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	pricing "github.com/ai8future/pricing_db"
+)
+
+// ModelJSON is one entry in `models -json` output.
+type ModelJSON struct {
+	Provider         string  `json:"provider"`
+	Model            string  `json:"model"`
+	InputPerMillion  float64 `json:"input_per_million"`
+	OutputPerMillion float64 `json:"output_per_million"`
+	ContextWindow    int64   `json:"context_window,omitempty"`
+}
+
+// CompareJSON is one entry in `compare -json` output, cheapest first.
+type CompareJSON struct {
+	Model      string     `json:"model"`
+	Provider   string     `json:"provider,omitempty"`
+	SelfHosted bool       `json:"self_hosted,omitempty"`
+	Cost       OutputJSON `json:"cost"`
+}
+
+func setupModels(fs *flag.FlagSet, env *commandEnv) func(ctx context.Context, args []string) int {
+	providers := fs.String("provider", "", "Comma-separated providers to list (default: all)")
+	jsonFlag := fs.Bool("json", false, "JSON output (default: table)")
+	return func(ctx context.Context, args []string) int {
+		filter := pricing.ModelFilter{Providers: splitList(*providers)}
+		if err := runModels(env.stdout, filter, *jsonFlag); err != nil {
+			return commandError(env, "models", err, exitError)
+		}
+		return exitOK
+	}
+}
+
+// runModels writes the token-priced models matching filter, cheapest input first.
+func runModels(w io.Writer, filter pricing.ModelFilter, asJSON bool) error {
+	infos := pricing.SearchModels(filter)
+	if len(infos) == 0 && len(filter.Providers) > 0 {
+		return fmt.Errorf("no models for provider %s", strings.Join(filter.Providers, ", "))
+	}
+
+	if asJSON {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(toModelsJSON(infos))
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "PROVIDER\tMODEL\tINPUT/M\tOUTPUT/M\tCONTEXT")
+	for _, info := range infos {
+		context := "-"
+		if info.ContextWindow > 0 {
+			context = fmt.Sprintf("%d", info.ContextWindow)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t$%.4f\t$%.4f\t%s\n",
+			info.Provider, info.Model, info.InputPerMillion, info.OutputPerMillion, context)
+	}
+	return tw.Flush()
+}
+
+// toModelsJSON converts SearchModels results to their JSON form; never nil.
+func toModelsJSON(infos []pricing.ModelInfo) []ModelJSON {
+	entries := make([]ModelJSON, len(infos))
+	for i, info := range infos {
+		entries[i] = ModelJSON{
+			Provider:         info.Provider,
+			Model:            info.Model,
+			InputPerMillion:  info.InputPerMillion,
+			OutputPerMillion: info.OutputPerMillion,
+			ContextWindow:    info.ContextWindow,
+		}
+	}
+	return entries
+}
+
+func setupCompare(fs *flag.FlagSet, env *commandEnv) func(ctx context.Context, args []string) int {
+	models := fs.String("models", "", "Comma-separated models to compare (required)")
+	var usage pricing.TokenUsage
+	fs.Int64Var(&usage.PromptTokens, "input", 1_000_000, "Input tokens")
+	fs.Int64Var(&usage.CompletionTokens, "output", 0, "Output tokens")
+	fs.Int64Var(&usage.CachedTokens, "cached", 0, "Cached input tokens (subset of -input)")
+	batch := fs.Bool("batch", false, "Apply batch mode pricing")
+	jsonFlag := fs.Bool("json", false, "JSON output (default: table)")
+	return func(ctx context.Context, args []string) int {
+		names := splitList(*models)
+		if len(names) == 0 {
+			return commandError(env, "compare", errors.New("-models is required"), exitError)
+		}
+		if usage.PromptTokens < 0 || usage.CompletionTokens < 0 || usage.CachedTokens < 0 {
+			return commandError(env, "compare", errors.New("token counts must not be negative"), exitError)
+		}
+		var opts *pricing.CalculateOptions
+		if *batch {
+			opts = &pricing.CalculateOptions{BatchMode: true}
+		}
+		unknown, err := runCompare(env.stdout, usage, names, opts, *jsonFlag)
+		if err != nil {
+			return commandError(env, "compare", err, exitError)
+		}
+		if unknown > 0 {
+			return exitUnknownModel
+		}
+		return exitOK
+	}
+}
+
+// runCompare writes the cost of usage on each model, cheapest first, and returns
+// how many models had no pricing.
+func runCompare(w io.Writer, usage pricing.TokenUsage, models []string, opts *pricing.CalculateOptions, asJSON bool) (int, error) {
+	p, err := pricing.NewPricer()
+	if err != nil {
+		return 0, err
+	}
+	results := p.CompareUsage(usage, models, opts)
+	unknown := 0
+	for _, r := range results {
+		if r.Cost.Unknown {
+			unknown++
+		}
+	}
+
+	if asJSON {
+		entries := make([]CompareJSON, len(results))
+		for i, r := range results {
+			entries[i] = CompareJSON{
+				Model:      r.Model,
+				Provider:   r.Provider,
+				SelfHosted: r.SelfHosted,
+				Cost:       toOutputJSON(r.Cost),
+			}
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return unknown, enc.Encode(entries)
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "MODEL\tPROVIDER\tINPUT\tOUTPUT\tTOTAL")
+	for _, r := range results {
+		if r.Cost.Unknown {
+			fmt.Fprintf(tw, "%s\t-\t-\t-\tunknown model\n", r.Model)
+			continue
+		}
+		provider := r.Provider
+		if r.SelfHosted {
+			provider += " (self-hosted)"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t$%.6f\t$%.6f\t$%.6f\n",
+			r.Model, provider,
+			r.Cost.StandardInputCost+r.Cost.CachedInputCost,
+			r.Cost.OutputCost+r.Cost.ThinkingCost,
+			r.Cost.TotalCost)
+	}
+	return unknown, tw.Flush()
+}
+
+// splitList splits a comma-separated flag value, dropping blank entries.
+func splitList(s string) []string {
+	var items []string
+	for item := range strings.SplitSeq(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
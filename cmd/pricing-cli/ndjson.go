@@ -0,0 +1,79 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+)
+
+// maxNDJSONLine is the longest accepted -ndjson input line.
+const maxNDJSONLine = 16 << 20
+
+// SummaryJSON is the last line of -ndjson output.
+type SummaryJSON struct {
+	Schema  string `json:"schema"` // outputSchema
+	Summary struct {
+		RecordCount  int     `json:"record_count"`
+		ErrorCount   int     `json:"error_count"`   // Lines that could not be parsed
+		UnknownCount int     `json:"unknown_count"` // Lines priced as Unknown (model not found)
+		TotalCost    float64 `json:"total_cost"`    // Sum of per-line total costs
+		// UnknownModels lists the models priced as Unknown, with -near-matches.
+		UnknownModels []UnknownModelJSON `json:"unknown_models,omitempty"`
+	} `json:"summary"`
+}
+
+// ndjsonLine is one non-blank input line and its 1-based line number.
+type ndjsonLine struct {
+	num  int
+	data []byte
+}
+
+// processNDJSON prices each non-blank line of r as a response and streams one
+// FileResultJSON per line to w as it is emitted, followed by a SummaryJSON line.
+// Lines are read as workers free up, so memory is bounded by -workers rather
+// than by input size. Returns the totals, or an error if r cannot be read.
+func processNDJSON(r io.Reader, w io.Writer, cfg batchConfig) (DirOutputJSON, error) {
+	enc := json.NewEncoder(w)
+	var totals DirOutputJSON
+	err := forEachNDJSON(r, cfg, func(f FileResultJSON) {
+		enc.Encode(f)
+		totals.add(f)
+	})
+	if err != nil {
+		return totals, err
+	}
+
+	summary := SummaryJSON{Schema: outputSchema}
+	summary.Summary.RecordCount = totals.FileCount
+	summary.Summary.ErrorCount = totals.ErrorCount
+	summary.Summary.UnknownCount = totals.UnknownCount
+	summary.Summary.TotalCost = totals.TotalCost
+	if cfg.nearMatches {
+		summary.Summary.UnknownModels = totals.unknownModels()
+	}
+	return totals, enc.Encode(summary)
+}
+
+// forEachNDJSON prices each non-blank line of r on cfg's worker pool and passes
+// each result to emit. Returns an error if r cannot be read.
+func forEachNDJSON(r io.Reader, cfg batchConfig, emit func(FileResultJSON)) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxNDJSONLine)
+	lines := func(yield func(ndjsonLine) bool) {
+		for num := 1; scanner.Scan(); num++ {
+			data := bytes.TrimSpace(scanner.Bytes())
+			if len(data) == 0 {
+				continue
+			}
+			if !yield(ndjsonLine{num, bytes.Clone(data)}) {
+				return
+			}
+		}
+	}
+
+	runPool(lines, cfg.workers, cfg.ordered, func(line ndjsonLine) FileResultJSON {
+		return priceInput(FileResultJSON{Line: line.num}, line.data, cfg)
+	}, emit)
+	return scanner.Err()
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"iter"
+	"sync"
+)
+
+// poolItem is a job or result tagged with its input position.
+type poolItem[T any] struct {
+	seq int
+	val T
+}
+
+// runPool applies work to each job using workers goroutines and calls emit with
+// each result from the calling goroutine: in input order if ordered, otherwise as
+// results complete. At most 2*workers jobs are in flight (including results held
+// back for ordering), so memory stays bounded however many jobs there are.
+func runPool[J, R any](jobs iter.Seq[J], workers int, ordered bool, work func(J) R, emit func(R)) {
+	workers = max(workers, 1)
+	slots := make(chan struct{}, 2*workers)
+	in := make(chan poolItem[J])
+	out := make(chan poolItem[R], workers)
+
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range in {
+				out <- poolItem[R]{job.seq, work(job.val)}
+			}
+		}()
+	}
+	go func() {
+		seq := 0
+		for job := range jobs {
+			slots <- struct{}{}
+			in <- poolItem[J]{seq, job}
+			seq++
+		}
+		close(in)
+		wg.Wait()
+		close(out)
+	}()
+
+	pending := make(map[int]R)
+	next := 0
+	for res := range out {
+		if !ordered {
+			emit(res.val)
+			<-slots
+			continue
+		}
+		pending[res.seq] = res.val
+		for r, ok := pending[next]; ok; r, ok = pending[next] {
+			delete(pending, next)
+			emit(r)
+			next++
+			<-slots
+		}
+	}
+}
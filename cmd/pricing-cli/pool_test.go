@@ -0,0 +1,80 @@
+package main
+
+import (
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRunPool_Ordered(t *testing.T) {
+	jobs := make([]int, 100)
+	for i := range jobs {
+		jobs[i] = i
+	}
+
+	var got []int
+	runPool(slices.Values(jobs), 8, true, func(j int) int {
+		// Finish out of order: later jobs are faster
+		time.Sleep(time.Duration(100-j) * 10 * time.Microsecond)
+		return j * 2
+	}, func(r int) {
+		got = append(got, r)
+	})
+
+	for i, r := range got {
+		if r != i*2 {
+			t.Fatalf("result %d: expected %d, got %d", i, i*2, r)
+		}
+	}
+	if len(got) != len(jobs) {
+		t.Errorf("expected %d results, got %d", len(jobs), len(got))
+	}
+}
+
+func TestRunPool_Unordered(t *testing.T) {
+	jobs := []int{1, 2, 3, 4, 5}
+	var sum int
+	runPool(slices.Values(jobs), 3, false, func(j int) int { return j }, func(r int) { sum += r })
+	if sum != 15 {
+		t.Errorf("expected sum 15, got %d", sum)
+	}
+}
+
+func TestRunPool_BoundsInFlight(t *testing.T) {
+	const workers = 4
+	var started, emitted, maxInFlight atomic.Int64
+	jobs := func(yield func(int) bool) {
+		for i := range 200 {
+			if !yield(i) {
+				return
+			}
+		}
+	}
+
+	runPool(jobs, workers, true, func(j int) int {
+		inFlight := started.Add(1) - emitted.Load()
+		for {
+			m := maxInFlight.Load()
+			if inFlight <= m || maxInFlight.CompareAndSwap(m, inFlight) {
+				break
+			}
+		}
+		if j%10 == 0 {
+			time.Sleep(time.Millisecond) // Hold up ordered emission
+		}
+		return j
+	}, func(int) {
+		emitted.Add(1)
+	})
+
+	if m := maxInFlight.Load(); m > 2*workers {
+		t.Errorf("expected at most %d jobs in flight, saw %d", 2*workers, m)
+	}
+}
+
+func TestRunPool_Empty(t *testing.T) {
+	runPool(slices.Values([]int(nil)), 0, true, func(j int) int { return j }, func(int) {
+		t.Error("unexpected result")
+	})
+}
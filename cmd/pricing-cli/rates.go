@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"io"
+	"strconv"
+
+	pricing "github.com/ai8future/pricing_db"
+	"github.com/ai8future/pricing_db/parquetio"
+)
+
+// rateTableParquetColumns are the columns of `rates -format parquet`, named
+// as in the CSV and NDJSON exports.
+var rateTableParquetColumns = []parquetio.Column{
+	{Name: "provider", Type: parquetio.String},
+	{Name: "model", Type: parquetio.String},
+	{Name: "unit", Type: parquetio.String},
+	{Name: "rate", Type: parquetio.Double},
+	{Name: "tier", Type: parquetio.String},
+	{Name: "tier_mode", Type: parquetio.String},
+	{Name: "effective_date", Type: parquetio.String},
+	{Name: "effective_until", Type: parquetio.String},
+}
+
+func setupRates(fs *flag.FlagSet, env *commandEnv) func(ctx context.Context, args []string) int {
+	format := fs.String("format", "csv", "Output format: csv, ndjson or parquet")
+	pricingPath := fs.String("pricing", "", "Pricing: a config directory or Export snapshot (default: embedded)")
+	return func(ctx context.Context, args []string) int {
+		p, err := loadPricer(*pricingPath)
+		if err != nil {
+			return commandError(env, "rates", err, exitParseError)
+		}
+		if *format == "parquet" {
+			err = writeRateTableParquet(env.stdout, p.RateTable())
+		} else {
+			err = p.ExportRateTable(env.stdout, pricing.RateTableFormat(*format))
+		}
+		if err != nil {
+			return commandError(env, "rates", err, exitError)
+		}
+		return exitOK
+	}
+}
+
+// writeRateTableParquet writes rows to w as a Parquet file. Empty text cells,
+// such as the tier mode of an untiered model, are written as nulls.
+func writeRateTableParquet(w io.Writer, rows []pricing.RateRow) error {
+	t := &parquetio.Table{Columns: rateTableParquetColumns}
+	for _, row := range rows {
+		t.Rows = append(t.Rows, []string{
+			row.Provider,
+			row.Model,
+			row.Unit,
+			strconv.FormatFloat(row.Rate, 'f', -1, 64),
+			row.Tier,
+			row.TierMode,
+			row.EffectiveDate,
+			row.EffectiveUntil,
+		})
+	}
+	return parquetio.WriteTable(w, t)
+}
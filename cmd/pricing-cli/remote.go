@@ -0,0 +1,396 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+// objectStore reads objects from a cloud storage bucket, so -f and -dir can
+// take bucket URIs such as s3://logs/2026/10/ or gs://logs/responses.jsonl.gz.
+type objectStore interface {
+	// Open returns the object's content.
+	Open(ctx context.Context, bucket, key string) (io.ReadCloser, error)
+	// List returns the keys directly under prefix (not in its
+	// subdirectories), in lexical order.
+	List(ctx context.Context, bucket, prefix string) ([]string, error)
+}
+
+// objectStores maps a URI scheme to the constructor of its store.
+// Credentials come from each cloud's standard environment variables.
+var objectStores = map[string]func() objectStore{
+	"s3": newS3Store,
+	"gs": newGCSStore,
+}
+
+// remoteHTTPClient fetches objects. Requests have no overall timeout, since
+// archives can be large; dial and header timeouts catch a dead endpoint.
+var remoteHTTPClient = &http.Client{
+	Transport: &http.Transport{
+		Proxy:                 http.ProxyFromEnvironment,
+		ResponseHeaderTimeout: 30 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+	},
+}
+
+// parseObjectURI splits a "scheme://bucket/key" URI with a registered scheme.
+// It reports false for local paths.
+func parseObjectURI(uri string) (store objectStore, bucket, key string, ok bool) {
+	scheme, rest, found := strings.Cut(uri, "://")
+	newStore, registered := objectStores[scheme]
+	if !found || !registered {
+		return nil, "", "", false
+	}
+	bucket, key, _ = strings.Cut(rest, "/")
+	return newStore(), bucket, key, true
+}
+
+// isObjectURI reports whether name is a bucket URI rather than a local path.
+func isObjectURI(name string) bool {
+	_, _, _, ok := parseObjectURI(name)
+	return ok
+}
+
+// openObject opens a bucket URI.
+func openObject(ctx context.Context, uri string) (io.ReadCloser, error) {
+	store, bucket, key, _ := parseObjectURI(uri)
+	if bucket == "" || key == "" || strings.HasSuffix(key, "/") {
+		return nil, fmt.Errorf("%s: expected scheme://bucket/object", uri)
+	}
+	return store.Open(ctx, bucket, key)
+}
+
+// listObjects returns the URIs of the objects directly under a bucket URI
+// prefix whose base names match pattern, as -dir does for a local directory.
+func listObjects(ctx context.Context, uri, pattern string) ([]string, error) {
+	store, bucket, prefix, _ := parseObjectURI(uri)
+	if bucket == "" {
+		return nil, fmt.Errorf("%s: expected scheme://bucket/prefix/", uri)
+	}
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+	keys, err := store.List(ctx, bucket, prefix)
+	if err != nil {
+		return nil, err
+	}
+	scheme, _, _ := strings.Cut(uri, "://")
+	var uris []string
+	for _, key := range keys {
+		if matched, _ := path.Match(pattern, strings.TrimPrefix(key, prefix)); matched {
+			uris = append(uris, scheme+"://"+bucket+"/"+key)
+		}
+	}
+	return uris, nil
+}
+
+// objectStatusError is a non-2xx response to an object request.
+type objectStatusError struct {
+	uri    string
+	status string // e.g. "404 Not Found"
+	code   int
+	header http.Header
+	body   string // Start of the response body
+}
+
+func (e *objectStatusError) Error() string {
+	return fmt.Sprintf("%s: %s: %s", e.uri, e.status, e.body)
+}
+
+// fetchObject issues req and returns the response body, or an
+// *objectStatusError naming uri for a non-2xx status.
+func fetchObject(req *http.Request, uri string) (io.ReadCloser, error) {
+	resp, err := remoteHTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode/100 != 2 {
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, &objectStatusError{
+			uri:    uri,
+			status: resp.Status,
+			code:   resp.StatusCode,
+			header: resp.Header,
+			body:   strings.TrimSpace(string(body)),
+		}
+	}
+	return resp.Body, nil
+}
+
+// =============================================================================
+// Amazon S3
+// =============================================================================
+
+// s3Store reads S3 (or S3-compatible) buckets. Requests are signed with
+// Signature Version 4 when AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY are
+// set, and sent unsigned (public buckets) otherwise.
+type s3Store struct {
+	accessKey, secretKey, sessionToken string
+	region                             string
+	endpoint                           string // AWS_ENDPOINT_URL for S3-compatible stores (path-style); empty = AWS
+}
+
+func newS3Store() objectStore {
+	region := cmp.Or(os.Getenv("AWS_REGION"), os.Getenv("AWS_DEFAULT_REGION"), "us-east-1")
+	return &s3Store{
+		accessKey:    os.Getenv("AWS_ACCESS_KEY_ID"),
+		secretKey:    os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		sessionToken: os.Getenv("AWS_SESSION_TOKEN"),
+		region:       region,
+		endpoint:     strings.TrimSuffix(cmp.Or(os.Getenv("AWS_ENDPOINT_URL_S3"), os.Getenv("AWS_ENDPOINT_URL")), "/"),
+	}
+}
+
+// objectURL returns the URL of key in bucket: virtual-hosted on AWS, except
+// for bucket names containing dots, which the *.s3 TLS certificate does not
+// cover, and path-style on those and on a custom endpoint.
+func (s *s3Store) objectURL(bucket, key string, query url.Values) *url.URL {
+	u := &url.URL{Scheme: "https", Host: bucket + ".s3." + s.region + ".amazonaws.com", Path: "/" + key}
+	if strings.Contains(bucket, ".") {
+		u.Host, u.Path = "s3."+s.region+".amazonaws.com", "/"+bucket+"/"+key
+	}
+	if s.endpoint != "" {
+		if base, err := url.Parse(s.endpoint); err == nil {
+			u.Scheme, u.Host, u.Path = base.Scheme, base.Host, base.Path+"/"+bucket+"/"+key
+		}
+	}
+	u.RawPath = s3EscapePath(u.Path)
+	u.RawQuery = s3CanonicalQuery(query)
+	return u
+}
+
+func (s *s3Store) get(ctx context.Context, bucket, key string, query url.Values) (io.ReadCloser, error) {
+	u := s.objectURL(bucket, key, query)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if s.accessKey != "" && s.secretKey != "" {
+		s.sign(req, time.Now())
+	}
+	uri := "s3://" + bucket + "/" + key
+	body, err := fetchObject(req, uri)
+	var statusErr *objectStatusError
+	if errors.As(err, &statusErr) && statusErr.code == http.StatusMovedPermanently {
+		// S3 answers requests to the wrong regional endpoint with a 301 and
+		// no Location header.
+		err = fmt.Errorf("%s: bucket is not in region %s (from AWS_REGION, default us-east-1)", uri, s.region)
+		if region := statusErr.header.Get("X-Amz-Bucket-Region"); region != "" {
+			err = fmt.Errorf("%s: bucket is in region %s, not %s; set AWS_REGION=%s", uri, region, s.region, region)
+		}
+	}
+	return body, err
+}
+
+func (s *s3Store) Open(ctx context.Context, bucket, key string) (io.ReadCloser, error) {
+	return s.get(ctx, bucket, key, nil)
+}
+
+func (s *s3Store) List(ctx context.Context, bucket, prefix string) ([]string, error) {
+	var keys []string
+	query := url.Values{"list-type": {"2"}, "prefix": {prefix}, "delimiter": {"/"}}
+	for {
+		body, err := s.get(ctx, bucket, "", query)
+		if err != nil {
+			return nil, err
+		}
+		var page struct {
+			Contents []struct {
+				Key string `xml:"Key"`
+			} `xml:"Contents"`
+			IsTruncated           bool   `xml:"IsTruncated"`
+			NextContinuationToken string `xml:"NextContinuationToken"`
+		}
+		err = xml.NewDecoder(body).Decode(&page)
+		body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("s3://%s/%s: list objects: %w", bucket, prefix, err)
+		}
+		for _, c := range page.Contents {
+			if c.Key != prefix {
+				keys = append(keys, c.Key)
+			}
+		}
+		if !page.IsTruncated || page.NextContinuationToken == "" {
+			sort.Strings(keys)
+			return keys, nil
+		}
+		query.Set("continuation-token", page.NextContinuationToken)
+	}
+}
+
+// emptyPayloadHash is the SHA-256 of an empty request body.
+const emptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+// sign adds Signature Version 4 headers for a body-less request at now.
+func (s *s3Store) sign(req *http.Request, now time.Time) {
+	amzDate := now.UTC().Format("20060102T150405Z")
+	date := amzDate[:8]
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", emptyPayloadHash)
+	if s.sessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", s.sessionToken)
+	}
+
+	signed := []string{"host", "x-amz-content-sha256", "x-amz-date"}
+	headers := "host:" + req.URL.Host + "\n" +
+		"x-amz-content-sha256:" + emptyPayloadHash + "\n" +
+		"x-amz-date:" + amzDate + "\n"
+	if s.sessionToken != "" {
+		signed = append(signed, "x-amz-security-token")
+		headers += "x-amz-security-token:" + s.sessionToken + "\n"
+	}
+	signedHeaders := strings.Join(signed, ";")
+
+	canonical := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		headers,
+		signedHeaders,
+		emptyPayloadHash,
+	}, "\n")
+	scope := date + "/" + s.region + "/s3/aws4_request"
+	canonicalHash := sha256.Sum256([]byte(canonical))
+	toSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(canonicalHash[:])
+
+	key := hmacSHA256([]byte("AWS4"+s.secretKey), date)
+	for _, part := range []string{s.region, "s3", "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	signature := hex.EncodeToString(hmacSHA256(key, toSign))
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.accessKey, scope, signedHeaders, signature))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+// s3Escape percent-encodes s as Signature Version 4 requires: everything
+// except unreserved characters, and "/" too unless keepSlash is set.
+func s3Escape(s string, keepSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9',
+			c == '-', c == '.', c == '_', c == '~', c == '/' && keepSlash:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+// s3EscapePath returns the canonical encoding of an object path.
+func s3EscapePath(path string) string {
+	return s3Escape(path, true)
+}
+
+// s3CanonicalQuery returns query encoded and sorted by key, as both the
+// request and its signature use it.
+func s3CanonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for k := range query {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var parts []string
+	for _, k := range keys {
+		for _, v := range query[k] {
+			parts = append(parts, s3Escape(k, false)+"="+s3Escape(v, false))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+// =============================================================================
+// Google Cloud Storage
+// =============================================================================
+
+// gcsStore reads Google Cloud Storage buckets through the JSON API. Requests
+// carry GOOGLE_OAUTH_ACCESS_TOKEN (e.g. from `gcloud auth print-access-token`)
+// when it is set, and are anonymous (public buckets) otherwise.
+// STORAGE_EMULATOR_HOST points them at an emulator.
+type gcsStore struct {
+	token    string
+	endpoint string
+}
+
+func newGCSStore() objectStore {
+	endpoint := "https://storage.googleapis.com"
+	if host := os.Getenv("STORAGE_EMULATOR_HOST"); host != "" {
+		endpoint = host
+		if !strings.Contains(host, "://") {
+			endpoint = "http://" + host
+		}
+	}
+	return &gcsStore{token: os.Getenv("GOOGLE_OAUTH_ACCESS_TOKEN"), endpoint: strings.TrimSuffix(endpoint, "/")}
+}
+
+func (g *gcsStore) get(ctx context.Context, uri, path string, query url.Values) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, g.endpoint+path+"?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if g.token != "" {
+		req.Header.Set("Authorization", "Bearer "+g.token)
+	}
+	return fetchObject(req, uri)
+}
+
+func (g *gcsStore) Open(ctx context.Context, bucket, key string) (io.ReadCloser, error) {
+	path := "/storage/v1/b/" + url.PathEscape(bucket) + "/o/" + url.PathEscape(key)
+	return g.get(ctx, "gs://"+bucket+"/"+key, path, url.Values{"alt": {"media"}})
+}
+
+func (g *gcsStore) List(ctx context.Context, bucket, prefix string) ([]string, error) {
+	var keys []string
+	query := url.Values{"prefix": {prefix}, "delimiter": {"/"}, "fields": {"items(name),nextPageToken"}}
+	for {
+		body, err := g.get(ctx, "gs://"+bucket+"/"+prefix, "/storage/v1/b/"+url.PathEscape(bucket)+"/o", query)
+		if err != nil {
+			return nil, err
+		}
+		var page struct {
+			Items []struct {
+				Name string `json:"name"`
+			} `json:"items"`
+			NextPageToken string `json:"nextPageToken"`
+		}
+		err = json.NewDecoder(body).Decode(&page)
+		body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("gs://%s/%s: list objects: %w", bucket, prefix, err)
+		}
+		for _, item := range page.Items {
+			if item.Name != prefix {
+				keys = append(keys, item.Name)
+			}
+		}
+		if page.NextPageToken == "" {
+			sort.Strings(keys)
+			return keys, nil
+		}
+		query.Set("pageToken", page.NextPageToken)
+	}
+}
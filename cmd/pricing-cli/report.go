@@ -0,0 +1,164 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"slices"
+	"text/tabwriter"
+)
+
+// ModelReportJSON is one model's line in `report` output.
+type ModelReportJSON struct {
+	Model     string  `json:"model"`
+	Requests  int     `json:"requests"`
+	Unknown   int     `json:"unknown"` // Requests priced as Unknown (model not found)
+	TotalCost float64 `json:"total_cost"`
+	billedTotalsJSON
+}
+
+// ReportJSON is the `report -json` output: per-model totals, most expensive first.
+type ReportJSON struct {
+	Models    []ModelReportJSON `json:"models"`
+	Requests  int               `json:"requests"`
+	Errors    int               `json:"errors"` // Inputs that could not be read or parsed
+	TotalCost float64           `json:"total_cost"`
+	billedTotalsJSON
+}
+
+// billedTotalsJSON holds the customer-price lines of a result or report: the
+// markup, the marked-up total, and the tax and tax-inclusive total. All zero,
+// and omitted, unless the Pricer has a markup or tax rate.
+type billedTotalsJSON struct {
+	Markup      float64 `json:"markup,omitempty"`
+	BilledTotal float64 `json:"billed_total,omitempty"`
+	Tax         float64 `json:"tax,omitempty"`
+	GrossTotal  float64 `json:"gross_total,omitempty"`
+}
+
+func (t *billedTotalsJSON) add(o billedTotalsJSON) {
+	t.Markup += o.Markup
+	t.BilledTotal += o.BilledTotal
+	t.Tax += o.Tax
+	t.GrossTotal += o.GrossTotal
+}
+
+// reportBuilder accumulates FileResultJSON results into a ReportJSON.
+type reportBuilder struct {
+	totals DirOutputJSON
+	models map[string]*ModelReportJSON
+}
+
+func (b *reportBuilder) add(f FileResultJSON) {
+	b.totals.add(f)
+	if f.Result == nil {
+		return
+	}
+	model := f.Model
+	if model == "" {
+		model = "(unknown)"
+	}
+	m := b.models[model]
+	if m == nil {
+		m = &ModelReportJSON{Model: model}
+		b.models[model] = m
+	}
+	m.Requests++
+	if f.Result.Unknown {
+		m.Unknown++
+	} else {
+		m.TotalCost += f.Result.TotalCost
+		m.add(f.Result.billedTotalsJSON)
+	}
+}
+
+// report returns the accumulated report, sorted by cost then model name.
+func (b *reportBuilder) report() ReportJSON {
+	out := ReportJSON{
+		Models:    make([]ModelReportJSON, 0, len(b.models)),
+		Requests:  b.totals.FileCount,
+		Errors:    b.totals.ErrorCount,
+		TotalCost: b.totals.TotalCost,
+	}
+	for _, m := range b.models {
+		out.Models = append(out.Models, *m)
+		out.add(m.billedTotalsJSON)
+	}
+	slices.SortFunc(out.Models, func(a, b ModelReportJSON) int {
+		if c := cmp.Compare(b.TotalCost, a.TotalCost); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Model, b.Model)
+	})
+	return out
+}
+
+func setupReport(fs *flag.FlagSet, env *commandEnv) func(ctx context.Context, args []string) int {
+	flags := defineCostFlags(fs)
+	jsonFlag := fs.Bool("json", false, "JSON output (default: table)")
+	localeFlags := defineLocaleFlags(fs)
+	return func(ctx context.Context, args []string) int {
+		logger, batch, err := flags.resolve(env.cfg)
+		if err != nil {
+			return commandError(env, "report", err, exitError)
+		}
+		money, err := localeFlags.resolve(env.cfg)
+		if err != nil {
+			return commandError(env, "report", err, exitError)
+		}
+		b := reportBuilder{models: make(map[string]*ModelReportJSON)}
+
+		switch {
+		case *flags.dir != "":
+			err = forEachDirFile(ctx, *flags.dir, *flags.glob, batch, b.add)
+		case *flags.ndjson:
+			in, openErr := flags.openInput(ctx, env)
+			if openErr != nil {
+				return commandError(env, "report", openErr, exitError)
+			}
+			err = forEachNDJSON(in, batch, b.add)
+			in.Close()
+		default:
+			return commandError(env, "report", errors.New("one of -dir or -ndjson is required"), exitError)
+		}
+		if err != nil {
+			return commandError(env, "report", err, exitError)
+		}
+
+		out := b.report()
+		logger.Debug("report complete", "requests", out.Requests, "errors", out.Errors, "total_cost", out.TotalCost)
+		if *jsonFlag {
+			enc := json.NewEncoder(env.stdout)
+			enc.SetIndent("", "  ")
+			enc.Encode(out)
+		} else {
+			printReportHuman(env.stdout, out, money)
+		}
+		return exitCode(out.Errors, 0, false)
+	}
+}
+
+func printReportHuman(w io.Writer, out ReportJSON, money moneyFunc) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "MODEL\tREQUESTS\tUNKNOWN\tTOTAL")
+	for _, m := range out.Models {
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%s\n", m.Model, m.Requests, m.Unknown, money(m.TotalCost, 6))
+	}
+	tw.Flush()
+
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "Requests:    %d (%d errors)\n", out.Requests, out.Errors)
+	fmt.Fprintf(w, "Total:       %s\n", money(out.TotalCost, 6))
+	if out.BilledTotal != 0 {
+		fmt.Fprintf(w, "Markup:      %s\n", money(out.Markup, 6))
+		fmt.Fprintf(w, "Billed:      %s\n", money(out.BilledTotal, 6))
+	}
+	if out.Tax != 0 {
+		fmt.Fprintf(w, "Tax:         %s\n", money(out.Tax, 6))
+		fmt.Fprintf(w, "Gross:       %s\n", money(out.GrossTotal, 6))
+	}
+}
@@ -0,0 +1,189 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	pricing "github.com/ai8future/pricing_db"
+	"github.com/ai8future/pricing_db/configfmt"
+)
+
+// UsageRecordJSON is one line of `reprice` input: usage recorded for a request.
+type UsageRecordJSON struct {
+	Model     string             `json:"model"`
+	Usage     pricing.TokenUsage `json:"usage"`
+	BatchMode bool               `json:"batch_mode,omitempty"`
+	At        time.Time          `json:"at,omitzero"` // When the request was made; prices it at that date's rates
+}
+
+// ModelRepriceJSON is one model's line in `reprice` output.
+type ModelRepriceJSON struct {
+	Model        string  `json:"model"`
+	Requests     int     `json:"requests"`
+	Unknown      int     `json:"unknown"` // Requests the new catalog could not price
+	OriginalCost float64 `json:"original_cost"`
+	RepricedCost float64 `json:"repriced_cost"`
+	Delta        float64 `json:"delta"`
+}
+
+// RepriceJSON is the `reprice -json` output: per-model totals, largest change first.
+type RepriceJSON struct {
+	FromVersion   string             `json:"from_version"`
+	ToVersion     string             `json:"to_version"`
+	Models        []ModelRepriceJSON `json:"models"`
+	Requests      int                `json:"requests"`
+	OriginalTotal float64            `json:"original_total"`
+	RepricedTotal float64            `json:"repriced_total"`
+	Delta         float64            `json:"delta"`
+}
+
+func setupReprice(fs *flag.FlagSet, env *commandEnv) func(ctx context.Context, args []string) int {
+	file := fs.String("f", "", "Read usage records (NDJSON) from file instead of stdin")
+	from := fs.String("from", "", "Original pricing: a config directory or Export snapshot (default: embedded)")
+	to := fs.String("to", "", "New pricing: a config directory or Export snapshot (default: -from at current rates)")
+	jsonFlag := fs.Bool("json", false, "JSON output (default: table)")
+	noDecompress := fs.Bool("no-decompress", false, "Read a gzip/zstd-compressed input as is instead of decompressing it")
+	localeFlags := defineLocaleFlags(fs)
+	return func(ctx context.Context, args []string) int {
+		money, err := localeFlags.resolve(env.cfg)
+		if err != nil {
+			return commandError(env, "reprice", err, exitError)
+		}
+		fromPricer, err := loadPricer(*from)
+		if err != nil {
+			return commandError(env, "reprice", err, exitParseError)
+		}
+		var toPricer *pricing.Pricer
+		if *to != "" {
+			if toPricer, err = loadPricer(*to); err != nil {
+				return commandError(env, "reprice", err, exitParseError)
+			}
+		}
+
+		in, err := openInput(ctx, *file, env.stdin, *noDecompress)
+		if err != nil {
+			return commandError(env, "reprice", err, exitError)
+		}
+		records, err := readUsageRecords(in)
+		in.Close()
+		if err != nil {
+			return commandError(env, "reprice", err, exitParseError)
+		}
+
+		out := toRepriceJSON(fromPricer.Reprice(records, toPricer), len(records))
+		if *jsonFlag {
+			enc := json.NewEncoder(env.stdout)
+			enc.SetIndent("", "  ")
+			enc.Encode(out)
+		} else {
+			printRepriceHuman(env.stdout, out, money)
+		}
+		return exitOK
+	}
+}
+
+// loadPricer loads pricing from path: a directory of JSON, YAML, or TOML
+// configs, or a snapshot file written by Pricer.Export. An empty path loads
+// the embedded configs.
+func loadPricer(path string) (*pricing.Pricer, error) {
+	if path == "" {
+		return pricing.NewPricer()
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		return pricing.NewPricerFromFS(os.DirFS(path), ".", configfmt.YAML(), configfmt.TOML())
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	p, err := pricing.NewPricerFromSnapshot(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return p, nil
+}
+
+// readUsageRecords parses each non-blank line of r as a UsageRecordJSON.
+func readUsageRecords(r io.Reader) ([]pricing.UsageRecord, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxNDJSONLine)
+	var records []pricing.UsageRecord
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		var rec UsageRecordJSON
+		if err := json.Unmarshal([]byte(text), &rec); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		if rec.Model == "" {
+			return nil, fmt.Errorf("line %d: model is required", line)
+		}
+		record := pricing.UsageRecord{Model: rec.Model, Usage: rec.Usage, At: rec.At}
+		if rec.BatchMode {
+			record.Options = &pricing.CalculateOptions{BatchMode: true}
+		}
+		records = append(records, record)
+	}
+	return records, scanner.Err()
+}
+
+func toRepriceJSON(report pricing.RepriceReport, requests int) RepriceJSON {
+	out := RepriceJSON{
+		FromVersion:   report.FromVersion,
+		ToVersion:     report.ToVersion,
+		Models:        make([]ModelRepriceJSON, 0, len(report.Models)),
+		Requests:      requests,
+		OriginalTotal: report.OriginalTotal,
+		RepricedTotal: report.RepricedTotal,
+		Delta:         report.Delta,
+	}
+	for _, m := range report.Models {
+		out.Models = append(out.Models, ModelRepriceJSON{
+			Model:        m.Model,
+			Requests:     m.Requests,
+			Unknown:      m.Unknown,
+			OriginalCost: m.OriginalCost,
+			RepricedCost: m.RepricedCost,
+			Delta:        m.Delta,
+		})
+	}
+	return out
+}
+
+func printRepriceHuman(w io.Writer, out RepriceJSON, money moneyFunc) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "MODEL\tREQUESTS\tUNKNOWN\tORIGINAL\tREPRICED\tDELTA")
+	for _, m := range out.Models {
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%s\t%s\t%s\n", m.Model, m.Requests, m.Unknown, money(m.OriginalCost, 6), money(m.RepricedCost, 6), signedMoney(money, m.Delta))
+	}
+	tw.Flush()
+
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "Requests:    %d\n", out.Requests)
+	fmt.Fprintf(w, "Original:    %s (catalog %s)\n", money(out.OriginalTotal, 6), out.FromVersion)
+	fmt.Fprintf(w, "Repriced:    %s (catalog %s)\n", money(out.RepricedTotal, 6), out.ToVersion)
+	fmt.Fprintf(w, "Delta:       %s\n", signedMoney(money, out.Delta))
+}
+
+// signedMoney formats a change in cost with an explicit sign: "+$0.001000".
+func signedMoney(money moneyFunc, delta float64) string {
+	if delta >= 0 {
+		return "+" + money(delta, 6)
+	}
+	return money(delta, 6)
+}
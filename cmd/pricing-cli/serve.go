@@ -0,0 +1,161 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	pricing "github.com/ai8future/pricing_db"
+	"github.com/ai8future/pricing_db/configfmt"
+)
+
+// maxRequestBody caps a /v1/cost request body, matching the -ndjson line limit.
+const maxRequestBody = maxNDJSONLine
+
+func setupServe(fs *flag.FlagSet, env *commandEnv) func(ctx context.Context, args []string) int {
+	addr := fs.String("addr", ":8080", "Address to listen on")
+	configs := fs.String("configs", "", "Serve pricing configs from this directory instead of the embedded data")
+	poll := fs.Duration("poll", 0, "Reload -configs at this interval, publishing when prices change (0: only on SIGHUP)")
+	return func(ctx context.Context, args []string) int {
+		src := catalogSource{fsys: pricing.EmbeddedConfigFS(), dir: "configs"}
+		if *configs != "" {
+			src = catalogSource{
+				fsys: os.DirFS(*configs),
+				dir:  ".",
+				opts: []pricing.Option{configfmt.YAML(), configfmt.TOML()},
+			}
+		}
+		ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		p, err := pricing.LoadContext(ctx, src.fsys, src.dir, src.opts...)
+		if err != nil {
+			return commandError(env, "serve", err, exitParseError)
+		}
+		watcher := newCatalogWatcher(catalogEvent(p))
+		hup := make(chan os.Signal, 1)
+		signal.Notify(hup, syscall.SIGHUP)
+		defer signal.Stop(hup)
+		go watchCatalog(ctx, env.stderr, p, src, watcher, hup, *poll)
+
+		if err := serve(ctx, env.stderr, *addr, newServeMux(p, watcher)); err != nil {
+			return commandError(env, "serve", err, exitError)
+		}
+		return exitOK
+	}
+}
+
+// serve runs handler on addr until ctx is done, then shuts down gracefully.
+// Request contexts are canceled with ctx so open watch streams end.
+func serve(ctx context.Context, log io.Writer, addr string, handler http.Handler) error {
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+		BaseContext:       func(net.Listener) context.Context { return ctx },
+	}
+	errc := make(chan error, 1)
+	go func() { errc <- srv.ListenAndServe() }()
+	fmt.Fprintf(log, "pricing-cli serve: listening on %s\n", addr)
+
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+	}
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return err
+	}
+	if err := <-errc; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// newServeMux returns the HTTP API backed by p, whose catalog watcher tracks:
+//
+//	POST /v1/cost            price a pricing_db.JSONRequest body; 400 if it is invalid
+//	GET  /v1/models          list token-priced models (?provider=a,b to filter)
+//	GET  /v1/catalog         describe the served catalog (CatalogEventJSON)
+//	GET  /v1/catalog/watch   server-sent "catalog" events: now and after each reload
+//	GET  /healthz            liveness check
+//
+// The catalog and model listings carry the catalog version as their ETag and
+// answer If-None-Match with 304 Not Modified.
+func newServeMux(p *pricing.Pricer, watcher *catalogWatcher) *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v1/cost", func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxRequestBody))
+		if err != nil {
+			writeJSON(w, http.StatusRequestEntityTooLarge, pricing.JSONResult{Error: err.Error()})
+			return
+		}
+		out := p.CalculateJSON(body)
+		var result pricing.JSONResult
+		status := http.StatusOK
+		if json.Unmarshal(out, &result) != nil || result.Error != "" {
+			status = http.StatusBadRequest
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		w.Write(out)
+	})
+	mux.HandleFunc("GET /v1/models", func(w http.ResponseWriter, r *http.Request) {
+		if notModified(w, r, p.Version()) {
+			return
+		}
+		filter := pricing.ModelFilter{Providers: splitList(r.URL.Query().Get("provider"))}
+		writeJSON(w, http.StatusOK, toModelsJSON(p.SearchModels(filter)))
+	})
+	mux.HandleFunc("GET /v1/catalog", func(w http.ResponseWriter, r *http.Request) {
+		current := watcher.latest()
+		if notModified(w, r, current.Version) {
+			return
+		}
+		writeJSON(w, http.StatusOK, current)
+	})
+	mux.HandleFunc("GET /v1/catalog/watch", func(w http.ResponseWriter, r *http.Request) {
+		serveWatch(w, r, watcher)
+	})
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]any{
+			"status":    "ok",
+			"version":   watcher.latest().Version,
+			"providers": p.ProviderCount(),
+			"models":    p.ModelCount(),
+		})
+	})
+	return mux
+}
+
+// notModified sets the ETag for a response derived from catalog version and,
+// if the request's If-None-Match already names it, writes 304 and returns true.
+func notModified(w http.ResponseWriter, r *http.Request, version string) bool {
+	etag := `"` + version + `"`
+	w.Header().Set("ETag", etag)
+	for tag := range strings.SplitSeq(r.Header.Get("If-None-Match"), ",") {
+		if tag = strings.TrimSpace(tag); tag == etag || tag == "*" {
+			w.WriteHeader(http.StatusNotModified)
+			return true
+		}
+	}
+	return false
+}
+
+// writeJSON writes v as the JSON response body with the given status.
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	pricing "github.com/ai8future/pricing_db"
+	"github.com/ai8future/pricing_db/configfmt"
+)
+
+func setupValidate(fs *flag.FlagSet, env *commandEnv) func(ctx context.Context, args []string) int {
+	return func(ctx context.Context, args []string) int {
+		dir := "configs"
+		switch len(args) {
+		case 0:
+		case 1:
+			dir = args[0]
+		default:
+			return commandError(env, "validate", errors.New("expected at most one directory"), exitError)
+		}
+		if err := runValidate(env.stdout, dir); err != nil {
+			return commandError(env, "validate", err, exitParseError)
+		}
+		return exitOK
+	}
+}
+
+// runValidate loads the JSON, YAML, and TOML pricing configs in dir with the same
+// validation as NewPricer and reports what they define.
+func runValidate(w io.Writer, dir string) error {
+	if info, err := os.Stat(dir); err != nil {
+		return err
+	} else if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", dir)
+	}
+	p, err := pricing.NewPricerFromFS(os.DirFS(dir), ".", configfmt.YAML(), configfmt.TOML())
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "ok: %d providers, %d models, version %s\n", p.ProviderCount(), p.ModelCount(), p.Version())
+	return err
+}
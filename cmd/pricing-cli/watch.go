@@ -0,0 +1,168 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	pricing "github.com/ai8future/pricing_db"
+)
+
+// watchKeepalive is how often an idle watch stream sends an SSE comment, so
+// proxies do not time out the connection.
+const watchKeepalive = 30 * time.Second
+
+// CatalogEventJSON describes the catalog being served. It is the body of
+// GET /v1/catalog and the data of each watch stream "catalog" event.
+type CatalogEventJSON struct {
+	Version   string `json:"version"` // Pricer.Version: changes when prices do
+	Providers int    `json:"providers"`
+	Models    int    `json:"models"`
+	LoadedAt  string `json:"loaded_at"` // RFC 3339
+}
+
+// catalogSource is where serve loads pricing configs from, so it can reload them.
+type catalogSource struct {
+	fsys fs.FS
+	dir  string
+	opts []pricing.Option
+}
+
+// catalogWatcher tracks the served catalog and fans out changes to watch streams.
+type catalogWatcher struct {
+	mu      sync.Mutex
+	current CatalogEventJSON
+	subs    map[chan CatalogEventJSON]struct{}
+}
+
+func newCatalogWatcher(current CatalogEventJSON) *catalogWatcher {
+	return &catalogWatcher{current: current, subs: make(map[chan CatalogEventJSON]struct{})}
+}
+
+// latest returns the catalog being served.
+func (w *catalogWatcher) latest() CatalogEventJSON {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.current
+}
+
+// publish records ev as the current catalog and notifies subscribers. A
+// subscriber that has not consumed its previous event gets only the newest.
+func (w *catalogWatcher) publish(ev CatalogEventJSON) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.current = ev
+	for ch := range w.subs {
+		select {
+		case <-ch:
+		default:
+		}
+		ch <- ev
+	}
+}
+
+// subscribe returns a channel receiving each published event and a function
+// to unsubscribe.
+func (w *catalogWatcher) subscribe() (<-chan CatalogEventJSON, func()) {
+	ch := make(chan CatalogEventJSON, 1)
+	w.mu.Lock()
+	w.subs[ch] = struct{}{}
+	w.mu.Unlock()
+	return ch, func() {
+		w.mu.Lock()
+		delete(w.subs, ch)
+		w.mu.Unlock()
+	}
+}
+
+// catalogEvent describes p's catalog as loaded now.
+func catalogEvent(p *pricing.Pricer) CatalogEventJSON {
+	return CatalogEventJSON{
+		Version:   p.Version(),
+		Providers: p.ProviderCount(),
+		Models:    p.ModelCount(),
+		LoadedAt:  time.Now().UTC().Format(time.RFC3339),
+	}
+}
+
+// reloadCatalog reloads p from src and, if its version changed from the one w
+// is serving, publishes the new catalog. On error p keeps its catalog.
+func reloadCatalog(ctx context.Context, p *pricing.Pricer, src catalogSource, w *catalogWatcher) (changed bool, err error) {
+	if err := p.ReloadContext(ctx, src.fsys, src.dir, src.opts...); err != nil {
+		return false, err
+	}
+	if p.Version() == w.latest().Version {
+		return false, nil
+	}
+	w.publish(catalogEvent(p))
+	return true, nil
+}
+
+// watchCatalog reloads on each signal from hup and, if poll is positive, at
+// that interval, until ctx is done. Outcomes are logged to log.
+func watchCatalog(ctx context.Context, log io.Writer, p *pricing.Pricer, src catalogSource, w *catalogWatcher, hup <-chan os.Signal, poll time.Duration) {
+	var tick <-chan time.Time
+	if poll > 0 {
+		ticker := time.NewTicker(poll)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+		case <-tick:
+		}
+		changed, err := reloadCatalog(ctx, p, src, w)
+		switch {
+		case err != nil:
+			fmt.Fprintf(log, "pricing-cli serve: reload failed, keeping catalog %s: %v\n", w.latest().Version, err)
+		case changed:
+			fmt.Fprintf(log, "pricing-cli serve: reloaded catalog %s\n", w.latest().Version)
+		}
+	}
+}
+
+// serveWatch streams catalog events to the client as server-sent events: the
+// current catalog on connect, then one event per reload.
+func serveWatch(w http.ResponseWriter, r *http.Request, watcher *catalogWatcher) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+	events, cancel := watcher.subscribe()
+	defer cancel()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	writeCatalogEvent(w, watcher.latest())
+	flusher.Flush()
+
+	keepalive := time.NewTicker(watchKeepalive)
+	defer keepalive.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case ev := <-events:
+			writeCatalogEvent(w, ev)
+		case <-keepalive.C:
+			io.WriteString(w, ": keepalive\n\n")
+		}
+		flusher.Flush()
+	}
+}
+
+// writeCatalogEvent writes ev as an SSE "catalog" event with the version as its id.
+func writeCatalogEvent(w io.Writer, ev CatalogEventJSON) {
+	data, _ := json.Marshal(ev)
+	fmt.Fprintf(w, "event: catalog\nid: %s\ndata: %s\n\n", ev.Version, data)
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	pricing "github.com/ai8future/pricing_db"
+)
+
+const watchTestConfig = `{"provider": "acme", "models": {"acme-1": {"input_per_million": 1.0, "output_per_million": 2.0}}}`
+
+// newWatchTestServer serves a catalog loaded from a temp config directory.
+func newWatchTestServer(t *testing.T) (dir string, p *pricing.Pricer, src catalogSource, watcher *catalogWatcher, srv *httptest.Server) {
+	t.Helper()
+	dir = writeResponses(t, map[string]string{"acme_pricing.json": watchTestConfig})
+	src = catalogSource{fsys: os.DirFS(dir), dir: "."}
+	p, err := pricing.NewPricerFromFS(src.fsys, src.dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	watcher = newCatalogWatcher(catalogEvent(p))
+	srv = httptest.NewServer(newServeMux(p, watcher))
+	t.Cleanup(srv.Close)
+	return dir, p, src, watcher, srv
+}
+
+// readCatalogEvent reads the next "catalog" event from an SSE stream.
+func readCatalogEvent(t *testing.T, r *bufio.Reader) CatalogEventJSON {
+	t.Helper()
+	var event string
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatalf("reading watch stream: %v", err)
+		}
+		line = strings.TrimSuffix(line, "\n")
+		switch {
+		case strings.HasPrefix(line, "event: "):
+			event = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: ") && event == "catalog":
+			var ev CatalogEventJSON
+			if err := json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &ev); err != nil {
+				t.Fatalf("invalid event data %q: %v", line, err)
+			}
+			return ev
+		}
+	}
+}
+
+func TestServeETag(t *testing.T) {
+	_, p, _, _, srv := newWatchTestServer(t)
+	etag := `"` + p.Version() + `"`
+
+	for _, path := range []string{"/v1/catalog", "/v1/models"} {
+		resp, err := http.Get(srv.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if got := resp.Header.Get("ETag"); got != etag {
+			t.Errorf("%s: expected ETag %s, got %q", path, etag, got)
+		}
+
+		req, _ := http.NewRequest(http.MethodGet, srv.URL+path, nil)
+		req.Header.Set("If-None-Match", `"stale", `+etag)
+		resp, err = http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusNotModified {
+			t.Errorf("%s: expected 304 for matching If-None-Match, got %d", path, resp.StatusCode)
+		}
+	}
+}
+
+func TestReloadCatalog(t *testing.T) {
+	dir, p, src, watcher, _ := newWatchTestServer(t)
+	initial := watcher.latest()
+
+	if changed, err := reloadCatalog(context.Background(), p, src, watcher); changed || err != nil {
+		t.Errorf("expected no reload for unchanged configs, got %v, %v", changed, err)
+	}
+
+	os.WriteFile(filepath.Join(dir, "acme_pricing.json"), []byte(`{broken`), 0o644)
+	if changed, err := reloadCatalog(context.Background(), p, src, watcher); changed || err == nil {
+		t.Errorf("expected an error for a broken config, got %v, %v", changed, err)
+	}
+	if watcher.latest() != initial || p.Calculate("acme-1", 0, 1_000_000).TotalCost != 2.0 {
+		t.Error("failed reload should keep the current catalog")
+	}
+
+	os.WriteFile(filepath.Join(dir, "acme_pricing.json"), []byte(strings.Replace(watchTestConfig, "2.0", "3.0", 1)), 0o644)
+	if changed, err := reloadCatalog(context.Background(), p, src, watcher); !changed || err != nil {
+		t.Fatalf("expected reload, got %v, %v", changed, err)
+	}
+	if watcher.latest().Version == initial.Version || p.Calculate("acme-1", 0, 1_000_000).TotalCost != 3.0 {
+		t.Errorf("expected new version and prices, got %+v", watcher.latest())
+	}
+}
+
+func TestServeWatch(t *testing.T) {
+	dir, p, src, watcher, srv := newWatchTestServer(t)
+
+	resp, err := http.Get(srv.URL + "/v1/catalog/watch")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("expected text/event-stream, got %q", ct)
+	}
+	stream := bufio.NewReader(resp.Body)
+	first := readCatalogEvent(t, stream)
+	if first.Version != watcher.latest().Version || first.Models != p.ModelCount() {
+		t.Errorf("expected the current catalog first, got %+v", first)
+	}
+
+	os.WriteFile(filepath.Join(dir, "acme_pricing.json"), []byte(strings.Replace(watchTestConfig, "2.0", "3.0", 1)), 0o644)
+	if _, err := reloadCatalog(context.Background(), p, src, watcher); err != nil {
+		t.Fatal(err)
+	}
+	done := make(chan CatalogEventJSON)
+	go func() { done <- readCatalogEvent(t, stream) }()
+	select {
+	case next := <-done:
+		if next.Version == first.Version || next.Version != watcher.latest().Version {
+			t.Errorf("expected the reloaded version %s, got %+v", watcher.latest().Version, next)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no event after reload")
+	}
+
+	catalog, err := http.Get(srv.URL + "/v1/catalog")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(catalog.Body)
+	catalog.Body.Close()
+	if !strings.Contains(string(body), watcher.latest().Version) {
+		t.Errorf("expected /v1/catalog to report %s, got %s", watcher.latest().Version, body)
+	}
+}
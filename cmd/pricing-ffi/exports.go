@@ -0,0 +1,43 @@
+//go:build cgo
+
+package main
+
+// #include <stdlib.h>
+import "C"
+
+import (
+	"unsafe"
+
+	pricing "github.com/ai8future/pricing_db"
+)
+
+// PricingCalculateCost returns the USD cost of a model's input and output tokens,
+// or 0 for an unknown model.
+//
+//export PricingCalculateCost
+func PricingCalculateCost(model *C.char, inputTokens, outputTokens C.longlong) C.double {
+	return C.double(pricing.CalculateCost(C.GoString(model), int(inputTokens), int(outputTokens)))
+}
+
+// PricingParseGeminiResponse prices a Gemini API response. The returned JSON
+// string must be released with PricingFree.
+//
+//export PricingParseGeminiResponse
+func PricingParseGeminiResponse(data *C.char) *C.char {
+	return C.CString(string(geminiResultJSON([]byte(C.GoString(data)))))
+}
+
+// PricingCalculateJSON prices a JSON-encoded pricing_db.JSONRequest. The returned
+// JSON string must be released with PricingFree.
+//
+//export PricingCalculateJSON
+func PricingCalculateJSON(request *C.char) *C.char {
+	return C.CString(string(pricing.CalculateJSON([]byte(C.GoString(request)))))
+}
+
+// PricingFree releases a string returned by this library.
+//
+//export PricingFree
+func PricingFree(s *C.char) {
+	C.free(unsafe.Pointer(s))
+}
@@ -0,0 +1,41 @@
+// pricing-ffi builds pricing_db as a C shared library, so services in other
+// languages (Python via ctypes/cffi, Rust via extern "C") call the same pricing
+// logic as Go services. Build with cgo enabled:
+//
+//	go build -buildmode=c-shared -o libpricing.so ./cmd/pricing-ffi
+//
+// This writes libpricing.so and libpricing.h, which declares:
+//
+//	double PricingCalculateCost(char* model, long long int inputTokens, long long int outputTokens);
+//	char* PricingParseGeminiResponse(char* data);
+//	char* PricingCalculateJSON(char* request);
+//	void PricingFree(char* s);
+//
+// Functions returning char* return a JSON-encoded pricing_db.JSONResult, with
+// failures in its "error" field; release it with PricingFree.
+package main
+
+import (
+	"encoding/json"
+
+	pricing "github.com/ai8future/pricing_db"
+)
+
+// main is required by -buildmode=c-shared but never runs.
+func main() {}
+
+// geminiResultJSON prices a Gemini response and encodes the outcome as a JSONResult.
+func geminiResultJSON(data []byte) []byte {
+	var result pricing.JSONResult
+	details, err := pricing.ParseGeminiResponse(data)
+	if err != nil {
+		result.Error = err.Error()
+	} else {
+		result.CostDetails = details
+	}
+	out, err := json.Marshal(result)
+	if err != nil {
+		out, _ = json.Marshal(pricing.JSONResult{Error: err.Error()})
+	}
+	return out
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+
+	pricing "github.com/ai8future/pricing_db"
+)
+
+func TestGeminiResultJSON(t *testing.T) {
+	resp := `{"modelVersion": "gemini-2.5-flash", "usageMetadata": {"promptTokenCount": 1000, "candidatesTokenCount": 500}}`
+
+	var result pricing.JSONResult
+	if err := json.Unmarshal(geminiResultJSON([]byte(resp)), &result); err != nil {
+		t.Fatalf("result is not valid JSON: %v", err)
+	}
+	want, err := pricing.ParseGeminiResponse([]byte(resp))
+	if err != nil {
+		t.Fatalf("ParseGeminiResponse failed: %v", err)
+	}
+	if result.Error != "" || result.TotalCost != want.TotalCost {
+		t.Errorf("expected total %f, got %f (error %q)", want.TotalCost, result.TotalCost, result.Error)
+	}
+}
+
+func TestGeminiResultJSON_InvalidJSON(t *testing.T) {
+	var result pricing.JSONResult
+	if err := json.Unmarshal(geminiResultJSON([]byte(`{`)), &result); err != nil {
+		t.Fatalf("result is not valid JSON: %v", err)
+	}
+	if result.Error == "" {
+		t.Error("expected error for invalid JSON")
+	}
+}
@@ -0,0 +1,31 @@
+//go:build js && wasm
+
+// pricing-wasm exposes pricing_db's CalculateJSON to JavaScript, so web clients
+// price usage with the same Go logic as the backend.
+//
+// Build:
+//
+//	GOOS=js GOARCH=wasm go build -o pricing.wasm ./cmd/pricing-wasm
+//
+// After loading pricing.wasm with Go's wasm_exec.js, call:
+//
+//	const result = JSON.parse(pricingCalculateJSON(JSON.stringify({
+//	  model: "gpt-4o", usage: {PromptTokens: 1000, CompletionTokens: 500},
+//	})));
+package main
+
+import (
+	"syscall/js"
+
+	pricing "github.com/ai8future/pricing_db"
+)
+
+func main() {
+	js.Global().Set("pricingCalculateJSON", js.FuncOf(func(this js.Value, args []js.Value) any {
+		if len(args) != 1 || args[0].Type() != js.TypeString {
+			return `{"error":"pricingCalculateJSON expects one JSON string argument"}`
+		}
+		return string(pricing.CalculateJSON([]byte(args[0].String())))
+	}))
+	select {} // Keep the exported function alive
+}
@@ -0,0 +1,158 @@
+package pricing_db
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// CollisionPolicy decides which provider a bare model name resolves to when
+// several providers define it. Namespaced keys ("provider/model") are
+// unaffected and always resolve to their own provider.
+type CollisionPolicy string
+
+const (
+	// CollisionKeepFirst keeps the provider whose config file sorts first (default).
+	CollisionKeepFirst CollisionPolicy = "keep_first"
+
+	// CollisionKeepCheapest keeps the provider with the lowest combined
+	// base input + output rate. Ties keep the first provider.
+	CollisionKeepCheapest CollisionPolicy = "keep_cheapest"
+
+	// CollisionProviderPriority keeps the first provider in the caller's
+	// priority list that defines the model, falling back to keep-first
+	// when none of the listed providers do.
+	CollisionProviderPriority CollisionPolicy = "provider_priority"
+
+	// CollisionNamespacedOnly drops ambiguous bare names entirely, so
+	// callers must use "provider/model". Unambiguous names still resolve.
+	CollisionNamespacedOnly CollisionPolicy = "namespaced_only"
+)
+
+// ModelCollision describes a bare model name defined by more than one provider.
+// Every provider's pricing stays reachable through its namespaced key
+// ("together/llama-3.3-70b"); the bare name resolves to Kept only.
+type ModelCollision struct {
+	Model     string   // Bare model name, e.g. "llama-3.3-70b"
+	Providers []string // All providers defining the model, sorted
+	Kept      string   // Provider the bare name resolves to ("" under CollisionNamespacedOnly)
+	Shadowed  []string // Providers reachable only via their namespaced key, sorted
+}
+
+// Collisions reports bare model names defined by more than one provider,
+// sorted by model name. Use it to audit which provider an ambiguous
+// name like "llama-3.3-70b" resolves to.
+func (p *Pricer) Collisions() []ModelCollision {
+	c := p.load()
+
+	return findCollisions(c.providers, c.modelProviders)
+}
+
+// findCollisions groups bare model names by the providers defining them.
+func findCollisions(providers map[string]ProviderPricing, modelProviders map[string]string) []ModelCollision {
+	definedBy := make(map[string][]string)
+	for name, provider := range providers {
+		for model := range provider.Models {
+			definedBy[model] = append(definedBy[model], name)
+		}
+	}
+
+	var collisions []ModelCollision
+	for model, names := range definedBy {
+		if len(names) < 2 {
+			continue
+		}
+		sort.Strings(names)
+		kept := modelProviders[model]
+		var shadowed []string
+		for _, name := range names {
+			if name != kept {
+				shadowed = append(shadowed, name)
+			}
+		}
+		collisions = append(collisions, ModelCollision{
+			Model:     model,
+			Providers: names,
+			Kept:      kept,
+			Shadowed:  shadowed,
+		})
+	}
+
+	sort.Slice(collisions, func(i, j int) bool {
+		return collisions[i].Model < collisions[j].Model
+	})
+	return collisions
+}
+
+// resolveCollisions re-points ambiguous bare model keys according to policy.
+// The loader has already applied keep-first; other policies override it.
+func resolveCollisions(
+	models map[string]ModelPricing,
+	modelProviders map[string]string,
+	providers map[string]ProviderPricing,
+	policy CollisionPolicy,
+	priority []string,
+) {
+	if policy == CollisionKeepFirst {
+		return
+	}
+
+	for _, c := range findCollisions(providers, modelProviders) {
+		winner := c.Kept
+		switch policy {
+		case CollisionKeepCheapest:
+			best := combinedRate(providers[winner].Models[c.Model])
+			for _, name := range c.Providers {
+				if rate := combinedRate(providers[name].Models[c.Model]); rate < best {
+					winner, best = name, rate
+				}
+			}
+		case CollisionProviderPriority:
+			for _, name := range priority {
+				if _, ok := providers[name].Models[c.Model]; ok {
+					winner = name
+					break
+				}
+			}
+		case CollisionNamespacedOnly:
+			delete(models, c.Model)
+			delete(modelProviders, c.Model)
+			continue
+		}
+		models[c.Model] = providers[winner].Models[c.Model]
+		modelProviders[c.Model] = winner
+	}
+}
+
+// preferImageProviders re-points bare image model keys to the first provider
+// in priority that defines them. Iterating in reverse lets earlier entries win.
+func preferImageProviders(imageModels map[string]ImageModelPricing, providers map[string]ProviderPricing, priority []string) {
+	for i := len(priority) - 1; i >= 0; i-- {
+		for model, pricing := range providers[priority[i]].ImageModels {
+			imageModels[model] = pricing
+		}
+	}
+}
+
+// combinedRate is the base input + output rate used by CollisionKeepCheapest.
+func combinedRate(pricing ModelPricing) float64 {
+	return pricing.InputPerMillion + pricing.OutputPerMillion
+}
+
+// validCollisionPolicy reports whether policy is one of the defined policies.
+func validCollisionPolicy(policy CollisionPolicy) bool {
+	switch policy {
+	case CollisionKeepFirst, CollisionKeepCheapest, CollisionProviderPriority, CollisionNamespacedOnly:
+		return true
+	}
+	return false
+}
+
+// collisionError summarizes collisions for WithFailOnCollision.
+func collisionError(collisions []ModelCollision) error {
+	details := make([]string, len(collisions))
+	for i, c := range collisions {
+		details[i] = fmt.Sprintf("%q (%s)", c.Model, strings.Join(c.Providers, ", "))
+	}
+	return fmt.Errorf("%d model name collision(s) across providers: %s", len(collisions), strings.Join(details, "; "))
+}
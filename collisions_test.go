@@ -0,0 +1,200 @@
+package pricing_db
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// =============================================================================
+// Model Collision Tests
+// =============================================================================
+
+// collisionFS defines "shared-model" under two providers and "solo" under one.
+func collisionFS() fstest.MapFS {
+	return fstest.MapFS{
+		"configs/alpha_pricing.json": &fstest.MapFile{Data: []byte(`{
+			"models": {"shared-model": {"input_per_million": 2.0, "output_per_million": 4.0}}
+		}`)},
+		"configs/beta_pricing.json": &fstest.MapFile{Data: []byte(`{
+			"models": {
+				"shared-model": {"input_per_million": 1.0, "output_per_million": 3.0},
+				"solo": {"input_per_million": 1.0, "output_per_million": 1.0}
+			}
+		}`)},
+	}
+}
+
+func TestCollisions_Report(t *testing.T) {
+	p, err := NewPricerFromFS(collisionFS(), "configs")
+	if err != nil {
+		t.Fatalf("NewPricerFromFS failed: %v", err)
+	}
+
+	collisions := p.Collisions()
+	if len(collisions) != 1 {
+		t.Fatalf("expected 1 collision, got %d: %+v", len(collisions), collisions)
+	}
+	c := collisions[0]
+	if c.Model != "shared-model" || c.Kept != "alpha" {
+		t.Errorf("expected shared-model kept by alpha, got %+v", c)
+	}
+	if !slices.Equal(c.Providers, []string{"alpha", "beta"}) || !slices.Equal(c.Shadowed, []string{"beta"}) {
+		t.Errorf("unexpected providers/shadowed: %+v", c)
+	}
+}
+
+func TestCollisions_EmbeddedConfigs(t *testing.T) {
+	p, err := NewPricer()
+	if err != nil {
+		t.Fatalf("NewPricer failed: %v", err)
+	}
+
+	for _, c := range p.Collisions() {
+		if len(c.Providers) < 2 || len(c.Shadowed) != len(c.Providers)-1 {
+			t.Errorf("%s: inconsistent collision %+v", c.Model, c)
+		}
+		if info, ok := p.GetModelInfo(c.Model); !ok || info.Provider != c.Kept {
+			t.Errorf("%s: bare lookup resolves to %q, report says %q", c.Model, info.Provider, c.Kept)
+		}
+		for _, provider := range c.Shadowed {
+			if _, ok := p.GetPricing(provider + "/" + c.Model); !ok {
+				t.Errorf("%s: shadowed provider %s not reachable via namespaced key", c.Model, provider)
+			}
+		}
+	}
+}
+
+func TestWithFailOnCollision(t *testing.T) {
+	_, err := NewPricerFromFS(collisionFS(), "configs", WithFailOnCollision())
+	if err == nil || !strings.Contains(err.Error(), `"shared-model" (alpha, beta)`) {
+		t.Errorf("expected collision error naming shared-model, got %v", err)
+	}
+
+	fsys := collisionFS()
+	delete(fsys, "configs/alpha_pricing.json")
+	if _, err := NewPricerFromFS(fsys, "configs", WithFailOnCollision()); err != nil {
+		t.Errorf("expected no error without collisions, got %v", err)
+	}
+}
+
+// =============================================================================
+// Collision Policy Tests
+// =============================================================================
+
+func TestCollisionPolicy(t *testing.T) {
+	tests := []struct {
+		name         string
+		opt          Option
+		wantProvider string // "" means the bare name must not resolve
+	}{
+		{"default keeps first", nil, "alpha"},
+		{"keep first", WithCollisionPolicy(CollisionKeepFirst), "alpha"},
+		{"keep cheapest", WithCollisionPolicy(CollisionKeepCheapest), "beta"},
+		{"priority", WithCollisionPolicy(CollisionProviderPriority, "gamma", "beta", "alpha"), "beta"},
+		{"priority without match falls back", WithCollisionPolicy(CollisionProviderPriority, "gamma"), "alpha"},
+		{"namespaced only", WithCollisionPolicy(CollisionNamespacedOnly), ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := NewPricerFromFS(collisionFS(), "configs", tt.opt)
+			if err != nil {
+				t.Fatalf("NewPricerFromFS failed: %v", err)
+			}
+
+			info, ok := p.GetModelInfo("shared-model")
+			if tt.wantProvider == "" {
+				if ok {
+					t.Errorf("expected bare shared-model to be unresolved, got provider %q", info.Provider)
+				}
+			} else if !ok || info.Provider != tt.wantProvider {
+				t.Errorf("expected shared-model from %q, got %q (ok=%v)", tt.wantProvider, info.Provider, ok)
+			}
+
+			// Collision report reflects the policy's choice
+			if c := p.Collisions(); len(c) != 1 || c[0].Kept != tt.wantProvider {
+				t.Errorf("expected report to keep %q, got %+v", tt.wantProvider, c)
+			}
+			// Namespaced keys and unambiguous names are unaffected by policy
+			if cost := p.Calculate("alpha/shared-model", 1_000_000, 0); !floatEquals(cost.InputCost, 2.0) {
+				t.Errorf("expected alpha/shared-model input $2.00, got $%f", cost.InputCost)
+			}
+			if _, ok := p.GetPricing("solo"); !ok {
+				t.Error("expected unambiguous model solo to resolve")
+			}
+		})
+	}
+}
+
+func TestCollisionPolicy_CalculateUsesWinner(t *testing.T) {
+	p, err := NewPricerFromFS(collisionFS(), "configs", WithCollisionPolicy(CollisionKeepCheapest))
+	if err != nil {
+		t.Fatalf("NewPricerFromFS failed: %v", err)
+	}
+	cost := p.Calculate("shared-model", 1_000_000, 1_000_000)
+	if !floatEquals(cost.TotalCost, 4.0) {
+		t.Errorf("expected cheapest pricing total $4.00, got $%f", cost.TotalCost)
+	}
+}
+
+func TestCollisionPolicy_Unknown(t *testing.T) {
+	_, err := NewPricerFromFS(collisionFS(), "configs", WithCollisionPolicy("random"))
+	if err == nil || !strings.Contains(err.Error(), "unknown collision policy") {
+		t.Errorf("expected unknown policy error, got %v", err)
+	}
+}
+
+// =============================================================================
+// Provider Priority Tests
+// =============================================================================
+
+func TestWithProviderPriority(t *testing.T) {
+	fsys := collisionFS()
+	fsys["configs/gamma_pricing.json"] = &fstest.MapFile{Data: []byte(`{
+		"models": {"shared-model": {"input_per_million": 5.0, "output_per_million": 5.0}},
+		"image_models": {"shared-image": {"price_per_image": 0.05}}
+	}`)}
+	fsys["configs/alpha_pricing.json"] = &fstest.MapFile{Data: []byte(`{
+		"models": {"shared-model": {"input_per_million": 2.0, "output_per_million": 4.0}},
+		"image_models": {"shared-image": {"price_per_image": 0.02}}
+	}`)}
+
+	p, err := NewPricerFromFS(fsys, "configs", WithProviderPriority([]string{"gamma", "beta"}))
+	if err != nil {
+		t.Fatalf("NewPricerFromFS failed: %v", err)
+	}
+
+	if info, _ := p.GetModelInfo("shared-model"); info.Provider != "gamma" {
+		t.Errorf("expected shared-model from gamma, got %q", info.Provider)
+	}
+	if cost, _ := p.CalculateImage("shared-image", 1); !floatEquals(cost, 0.05) {
+		t.Errorf("expected shared-image priced by gamma ($0.05), got $%f", cost)
+	}
+	// Namespaced image keys still reach every provider
+	if cost, _ := p.CalculateImage("alpha/shared-image", 1); !floatEquals(cost, 0.02) {
+		t.Errorf("expected alpha/shared-image $0.02, got $%f", cost)
+	}
+}
+
+func TestWithProviderPriority_UnknownProviderIgnored(t *testing.T) {
+	// Routing lists may name providers without pricing configs
+	p, err := NewPricerFromFS(collisionFS(), "configs", WithProviderPriority([]string{"azure", "beta"}))
+	if err != nil {
+		t.Fatalf("NewPricerFromFS failed: %v", err)
+	}
+	if info, _ := p.GetModelInfo("shared-model"); info.Provider != "beta" {
+		t.Errorf("expected shared-model from beta, got %q", info.Provider)
+	}
+}
+
+func TestWithProviderPriority_EmbeddedConfigs(t *testing.T) {
+	p, err := NewPricer(WithProviderPriority([]string{"together", "deepinfra"}))
+	if err != nil {
+		t.Fatalf("NewPricer failed: %v", err)
+	}
+	if info, _ := p.GetModelInfo("deepseek-ai/DeepSeek-R1"); info.Provider != "together" {
+		t.Errorf("expected deepseek-ai/DeepSeek-R1 from together, got %q", info.Provider)
+	}
+}
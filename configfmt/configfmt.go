@@ -0,0 +1,27 @@
+// Package configfmt provides YAML and TOML decoders for pricing_db configs.
+//
+// The core pricing_db package depends only on the standard library and loads
+// JSON configs. Importing this package opts in to the extra formats:
+//
+//	pricer, err := pricing_db.NewPricerFromFS(fsys, "configs", configfmt.YAML(), configfmt.TOML())
+//
+// YAML and TOML files use the same field names as the JSON configs and must be
+// named "<provider>_pricing.yaml", "<provider>_pricing.yml", or "<provider>_pricing.toml".
+package configfmt
+
+import (
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+
+	pricing_db "github.com/ai8future/pricing_db"
+)
+
+// YAML enables loading "*_pricing.yaml" and "*_pricing.yml" config files.
+func YAML() pricing_db.Option {
+	return pricing_db.WithDecoder(yaml.Unmarshal, ".yaml", ".yml")
+}
+
+// TOML enables loading "*_pricing.toml" config files.
+func TOML() pricing_db.Option {
+	return pricing_db.WithDecoder(toml.Unmarshal, ".toml")
+}
@@ -0,0 +1,68 @@
+package configfmt
+
+import (
+	"testing"
+	"testing/fstest"
+
+	pricing_db "github.com/ai8future/pricing_db"
+)
+
+const yamlConfig = `
+provider: acme
+billing_type: token
+models:
+  acme-large:
+    input_per_million: 3.0
+    output_per_million: 15.0
+    cache_read_multiplier: 0.1
+    tiers:
+      - threshold_tokens: 200000
+        input_per_million: 6.0
+        output_per_million: 22.5
+`
+
+const tomlConfig = `
+provider = "widget"
+
+[models.widget-mini]
+input_per_million = 0.25
+output_per_million = 1.25
+batch_multiplier = 0.5
+`
+
+func TestYAMLAndTOML(t *testing.T) {
+	fsys := fstest.MapFS{
+		"configs/acme_pricing.yaml":   &fstest.MapFile{Data: []byte(yamlConfig)},
+		"configs/widget_pricing.toml": &fstest.MapFile{Data: []byte(tomlConfig)},
+	}
+
+	p, err := pricing_db.NewPricerFromFS(fsys, "configs", YAML(), TOML())
+	if err != nil {
+		t.Fatalf("NewPricerFromFS failed: %v", err)
+	}
+
+	pricing, ok := p.GetPricing("acme-large")
+	if !ok {
+		t.Fatal("expected acme-large from YAML config")
+	}
+	if pricing.InputPerMillion != 3.0 || pricing.CacheReadMultiplier != 0.1 || len(pricing.Tiers) != 1 {
+		t.Errorf("unexpected YAML pricing: %+v", pricing)
+	}
+
+	cost := p.Calculate("widget-mini", 1_000_000, 1_000_000)
+	if cost.TotalCost != 1.5 {
+		t.Errorf("expected widget-mini total $1.50, got $%f", cost.TotalCost)
+	}
+	if _, ok := p.GetPricing("widget/widget-mini"); !ok {
+		t.Error("expected namespaced key from TOML provider name")
+	}
+}
+
+func TestYAML_InvalidDocument(t *testing.T) {
+	fsys := fstest.MapFS{
+		"configs/acme_pricing.yml": &fstest.MapFile{Data: []byte("models: [unclosed")},
+	}
+	if _, err := pricing_db.NewPricerFromFS(fsys, "configs", YAML()); err == nil {
+		t.Error("expected error for malformed YAML")
+	}
+}
@@ -0,0 +1,226 @@
+package pricing_db
+
+import (
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"sync"
+	"time"
+)
+
+// CreditPack is a prepaid block of provider credit (e.g. xAI or Mistral
+// credits), denominated in USD.
+type CreditPack struct {
+	Name       string    // Unique within a tracker, e.g. "xai-2026-q1"
+	Provider   string    // Provider the credit is spent with, e.g. "xai"
+	BalanceUSD float64   // Credit purchased
+	ExpiresAt  time.Time // Zero = never expires
+}
+
+// CreditAlerts sets when a CreditTracker warns. Zero values disable the alert.
+type CreditAlerts struct {
+	LowBalanceUSD float64       // Warn when a provider's unexpired balance falls below this
+	ExpiryWindow  time.Duration // Warn when a pack with remaining credit expires within this window
+}
+
+// CreditPackStatus is a pack's remaining credit, as reported by CreditTracker.Packs.
+type CreditPackStatus struct {
+	CreditPack
+	RemainingUSD float64
+	Expired      bool
+}
+
+// CreditDraw is the result of recording a cost against a provider's packs.
+type CreditDraw struct {
+	DrawnUSD     float64   // Taken from credit packs
+	UncoveredUSD float64   // Left over once the provider's packs are exhausted; billed as normal spend
+	Warnings     []Warning // Exhaustion, low balance and expiry warnings after the draw
+}
+
+// CreditTracker decrements prepaid credit packs as costs are recorded and
+// warns when a provider's credit runs low or is about to expire. Costs draw
+// from the pack expiring soonest; expired packs are never drawn from.
+// A CreditTracker is safe for concurrent use.
+type CreditTracker struct {
+	mu     sync.Mutex
+	alerts CreditAlerts
+	packs  []*CreditPackStatus // sorted by expiry, never-expiring last
+}
+
+// NewCreditTracker returns a tracker for packs. Pack names must be unique and
+// balances finite and non-negative.
+func NewCreditTracker(alerts CreditAlerts, packs ...CreditPack) (*CreditTracker, error) {
+	if !isFiniteNonNegative(alerts.LowBalanceUSD) || alerts.ExpiryWindow < 0 {
+		return nil, fmt.Errorf("credit alerts must be finite and not negative")
+	}
+	t := &CreditTracker{alerts: alerts}
+	for _, pack := range packs {
+		if err := t.AddPack(pack); err != nil {
+			return nil, err
+		}
+	}
+	return t, nil
+}
+
+// AddPack adds a pack, e.g. a top-up purchased after the tracker was created.
+func (t *CreditTracker) AddPack(pack CreditPack) error {
+	if pack.Name == "" || pack.Provider == "" {
+		return fmt.Errorf("credit pack needs a name and provider")
+	}
+	if !isFiniteNonNegative(pack.BalanceUSD) {
+		return fmt.Errorf("credit pack %q: balance %f must be finite and not negative", pack.Name, pack.BalanceUSD)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, s := range t.packs {
+		if s.Name == pack.Name {
+			return fmt.Errorf("credit pack %q already added", pack.Name)
+		}
+	}
+	t.packs = append(t.packs, &CreditPackStatus{CreditPack: pack, RemainingUSD: pack.BalanceUSD})
+	slices.SortStableFunc(t.packs, func(a, b *CreditPackStatus) int {
+		x, y := a.ExpiresAt, b.ExpiresAt
+		if x.IsZero() != y.IsZero() {
+			if x.IsZero() {
+				return 1
+			}
+			return -1
+		}
+		return x.Compare(y)
+	})
+	return nil
+}
+
+// isFiniteNonNegative reports whether v is a usable USD amount: not NaN,
+// not ±Inf and not negative.
+func isFiniteNonNegative(v float64) bool {
+	return !math.IsNaN(v) && !math.IsInf(v, 0) && v >= 0
+}
+
+// Record draws costUSD from the provider's unexpired packs, soonest expiry
+// first. Non-positive and non-finite (NaN, ±Inf) costs draw nothing but still
+// report warnings, so a bad cost cannot corrupt the balances.
+func (t *CreditTracker) Record(provider string, costUSD float64) CreditDraw {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := timeNow()
+	var draw CreditDraw
+	var remaining float64
+	if costUSD > 0 && !math.IsInf(costUSD, 1) {
+		remaining = costUSD
+	}
+	for _, s := range t.packs {
+		if remaining <= 0 {
+			break
+		}
+		if s.Provider != provider || s.expiredAt(now) || s.RemainingUSD <= 0 {
+			continue
+		}
+		take := min(remaining, s.RemainingUSD)
+		s.RemainingUSD -= take
+		draw.DrawnUSD += take
+		remaining -= take
+	}
+	draw.UncoveredUSD = remaining
+	if remaining > 0 {
+		draw.Warnings = append(draw.Warnings, Warning{
+			Code:    WarningCreditExhausted,
+			Message: fmt.Sprintf("provider %q credit exhausted - $%.6f not covered by credit packs", provider, remaining),
+		})
+	}
+	draw.Warnings = append(draw.Warnings, t.providerWarnings(provider, now)...)
+	return draw
+}
+
+// RecordCost draws a calculated cost's TotalCost (the provider cost, before
+// any markup or tax) from the provider's packs.
+func (t *CreditTracker) RecordCost(provider string, cost CostDetails) CreditDraw {
+	return t.Record(provider, cost.TotalCost)
+}
+
+// Balance returns the provider's remaining unexpired credit.
+func (t *CreditTracker) Balance(provider string) float64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.balance(provider, timeNow())
+}
+
+// Packs returns the status of every pack, soonest expiry first.
+func (t *CreditTracker) Packs() []CreditPackStatus {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := timeNow()
+	out := make([]CreditPackStatus, len(t.packs))
+	for i, s := range t.packs {
+		out[i] = *s
+		out[i].Expired = s.expiredAt(now)
+	}
+	return out
+}
+
+// Warnings returns the current low-balance and expiry warnings for every
+// provider with packs, in provider order, without recording a cost.
+func (t *CreditTracker) Warnings() []Warning {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	providers := make(map[string]bool)
+	for _, s := range t.packs {
+		providers[s.Provider] = true
+	}
+
+	now := timeNow()
+	var warnings []Warning
+	for _, name := range slices.Sorted(maps.Keys(providers)) {
+		warnings = append(warnings, t.providerWarnings(name, now)...)
+	}
+	return warnings
+}
+
+// balance sums the provider's unexpired credit. t.mu must be held.
+func (t *CreditTracker) balance(provider string, now time.Time) float64 {
+	var total float64
+	for _, s := range t.packs {
+		if s.Provider == provider && !s.expiredAt(now) {
+			total += s.RemainingUSD
+		}
+	}
+	return total
+}
+
+// providerWarnings returns the provider's low-balance and expiry warnings.
+// t.mu must be held.
+func (t *CreditTracker) providerWarnings(provider string, now time.Time) []Warning {
+	var warnings []Warning
+	if t.alerts.LowBalanceUSD > 0 {
+		if balance := t.balance(provider, now); balance < t.alerts.LowBalanceUSD {
+			warnings = append(warnings, Warning{
+				Code:    WarningCreditLow,
+				Message: fmt.Sprintf("provider %q credit balance $%.2f is below $%.2f", provider, balance, t.alerts.LowBalanceUSD),
+			})
+		}
+	}
+	if t.alerts.ExpiryWindow > 0 {
+		for _, s := range t.packs {
+			if s.Provider != provider || s.ExpiresAt.IsZero() || s.expiredAt(now) || s.RemainingUSD <= 0 {
+				continue
+			}
+			if left := s.ExpiresAt.Sub(now); left <= t.alerts.ExpiryWindow {
+				warnings = append(warnings, Warning{
+					Code:    WarningCreditExpiring,
+					Message: fmt.Sprintf("credit pack %q expires %s with $%.2f unused", s.Name, s.ExpiresAt.UTC().Format("2006-01-02"), s.RemainingUSD),
+				})
+			}
+		}
+	}
+	return warnings
+}
+
+// expiredAt reports whether the pack has expired by now.
+func (s *CreditPackStatus) expiredAt(now time.Time) bool {
+	return !s.ExpiresAt.IsZero() && !now.Before(s.ExpiresAt)
+}
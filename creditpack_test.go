@@ -0,0 +1,152 @@
+package pricing_db
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// =============================================================================
+// Credit Pack Tests
+// =============================================================================
+
+func TestCreditTracker_DrawsSoonestExpiryFirst(t *testing.T) {
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	stubNow(t, now)
+
+	tr, err := NewCreditTracker(CreditAlerts{},
+		CreditPack{Name: "open", Provider: "xai", BalanceUSD: 100},
+		CreditPack{Name: "q3", Provider: "xai", BalanceUSD: 10, ExpiresAt: now.AddDate(0, 3, 0)},
+		CreditPack{Name: "q2", Provider: "xai", BalanceUSD: 5, ExpiresAt: now.AddDate(0, 1, 0)},
+	)
+	if err != nil {
+		t.Fatalf("NewCreditTracker failed: %v", err)
+	}
+
+	draw := tr.Record("xai", 12)
+	if !floatEquals(draw.DrawnUSD, 12) || draw.UncoveredUSD != 0 {
+		t.Errorf("expected $12 drawn and nothing uncovered, got %+v", draw)
+	}
+	want := map[string]float64{"q2": 0, "q3": 3, "open": 100}
+	for _, s := range tr.Packs() {
+		if !floatEquals(s.RemainingUSD, want[s.Name]) {
+			t.Errorf("pack %s: expected $%.2f remaining, got $%.2f", s.Name, want[s.Name], s.RemainingUSD)
+		}
+	}
+	if got := tr.Balance("xai"); !floatEquals(got, 103) {
+		t.Errorf("expected balance $103, got $%f", got)
+	}
+}
+
+func TestCreditTracker_Exhausted(t *testing.T) {
+	tr, err := NewCreditTracker(CreditAlerts{}, CreditPack{Name: "p", Provider: "mistral", BalanceUSD: 1})
+	if err != nil {
+		t.Fatalf("NewCreditTracker failed: %v", err)
+	}
+
+	draw := tr.Record("mistral", 1.5)
+	if !floatEquals(draw.DrawnUSD, 1) || !floatEquals(draw.UncoveredUSD, 0.5) {
+		t.Errorf("expected $1 drawn and $0.50 uncovered, got %+v", draw)
+	}
+	if !hasWarningCode(draw.Warnings, WarningCreditExhausted) {
+		t.Errorf("expected credit_exhausted warning, got %v", draw.Warnings)
+	}
+
+	// Other providers' spend is not covered
+	if draw := tr.Record("xai", 1); draw.DrawnUSD != 0 || !floatEquals(draw.UncoveredUSD, 1) {
+		t.Errorf("expected xai spend to be uncovered, got %+v", draw)
+	}
+}
+
+func TestCreditTracker_NonFiniteCost(t *testing.T) {
+	tr, err := NewCreditTracker(CreditAlerts{}, CreditPack{Name: "p", Provider: "mistral", BalanceUSD: 1})
+	if err != nil {
+		t.Fatalf("NewCreditTracker failed: %v", err)
+	}
+
+	for _, cost := range []float64{math.NaN(), math.Inf(1), math.Inf(-1)} {
+		if draw := tr.Record("mistral", cost); draw.DrawnUSD != 0 || draw.UncoveredUSD != 0 {
+			t.Errorf("Record(%v): expected nothing drawn, got %+v", cost, draw)
+		}
+	}
+	if got := tr.Balance("mistral"); !floatEquals(got, 1) {
+		t.Errorf("expected balance $1 after non-finite costs, got $%f", got)
+	}
+}
+
+func TestCreditTracker_LowBalanceAndExpiryWarnings(t *testing.T) {
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	stubNow(t, now)
+
+	tr, err := NewCreditTracker(CreditAlerts{LowBalanceUSD: 10, ExpiryWindow: 7 * 24 * time.Hour},
+		CreditPack{Name: "soon", Provider: "xai", BalanceUSD: 20, ExpiresAt: now.AddDate(0, 0, 3)},
+		CreditPack{Name: "gone", Provider: "xai", BalanceUSD: 50, ExpiresAt: now.AddDate(0, 0, -1)},
+	)
+	if err != nil {
+		t.Fatalf("NewCreditTracker failed: %v", err)
+	}
+
+	draw := tr.Record("xai", 5)
+	if hasWarningCode(draw.Warnings, WarningCreditLow) {
+		t.Errorf("unexpected low balance warning at $15: %v", draw.Warnings)
+	}
+	if !hasWarningCode(draw.Warnings, WarningCreditExpiring) {
+		t.Errorf("expected credit_expiring warning, got %v", draw.Warnings)
+	}
+
+	draw = tr.Record("xai", 6)
+	if !hasWarningCode(draw.Warnings, WarningCreditLow) {
+		t.Errorf("expected credit_low warning at $9, got %v", draw.Warnings)
+	}
+	if !hasWarningCode(tr.Warnings(), WarningCreditLow) {
+		t.Error("expected Warnings to report the low balance")
+	}
+
+	// The expired pack is never drawn from
+	for _, s := range tr.Packs() {
+		if s.Name == "gone" && (!s.Expired || !floatEquals(s.RemainingUSD, 50)) {
+			t.Errorf("expected untouched expired pack, got %+v", s)
+		}
+	}
+}
+
+func TestCreditTracker_RecordCost(t *testing.T) {
+	p, err := NewPricerFromFS(overlayFS(), "configs", WithMarkup(Markup{Percent: 50}))
+	if err != nil {
+		t.Fatalf("NewPricerFromFS failed: %v", err)
+	}
+	tr, err := NewCreditTracker(CreditAlerts{}, CreditPack{Name: "p", Provider: "acme", BalanceUSD: 100})
+	if err != nil {
+		t.Fatalf("NewCreditTracker failed: %v", err)
+	}
+
+	// Draws the $10 provider cost, not the $15 billed price
+	cost := p.CalculateUsage("large", TokenUsage{PromptTokens: 1_000_000}, nil)
+	if draw := tr.RecordCost("acme", cost); !floatEquals(draw.DrawnUSD, 10) {
+		t.Errorf("expected $10 drawn, got $%f", draw.DrawnUSD)
+	}
+}
+
+func TestNewCreditTracker_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		alerts CreditAlerts
+		packs  []CreditPack
+	}{
+		{"negative balance", CreditAlerts{}, []CreditPack{{Name: "p", Provider: "xai", BalanceUSD: -1}}},
+		{"NaN balance", CreditAlerts{}, []CreditPack{{Name: "p", Provider: "xai", BalanceUSD: math.NaN()}}},
+		{"infinite balance", CreditAlerts{}, []CreditPack{{Name: "p", Provider: "xai", BalanceUSD: math.Inf(1)}}},
+		{"duplicate name", CreditAlerts{}, []CreditPack{{Name: "p", Provider: "xai"}, {Name: "p", Provider: "mistral"}}},
+		{"missing provider", CreditAlerts{}, []CreditPack{{Name: "p"}}},
+		{"negative alert", CreditAlerts{LowBalanceUSD: -1}, nil},
+		{"NaN alert", CreditAlerts{LowBalanceUSD: math.NaN()}, nil},
+		{"infinite alert", CreditAlerts{LowBalanceUSD: math.Inf(1)}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewCreditTracker(tt.alerts, tt.packs...); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
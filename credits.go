@@ -0,0 +1,150 @@
+package pricing_db
+
+import (
+	"math"
+	"sort"
+)
+
+// GetCreditPricing returns the credit scheme (base cost per request and
+// feature multipliers) of a credit-based provider, such as "scrapedo".
+// Returns a deep copy to prevent mutation of internal state.
+func (p *Pricer) GetCreditPricing(provider string) (*CreditPricing, bool) {
+	c := p.load()
+
+	credit, ok := c.credits[provider]
+	if !ok {
+		return nil, false
+	}
+	return copyCreditPricing(credit), true
+}
+
+// ListCreditProviders returns the credit-based providers in alphabetical order.
+func (p *Pricer) ListCreditProviders() []string {
+	c := p.load()
+	names := make([]string, 0, len(c.credits))
+	for name := range c.credits {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// CreditCostUSD converts the credit cost of one request into USD using the
+// effective price per credit of a subscription tier (tier price / included credits).
+// This lets credit-billed usage (e.g., scraping) be summed with token spend.
+// Returns false if the provider is not credit-based, the tier is unknown,
+// or the tier includes no credits.
+func (p *Pricer) CreditCostUSD(provider, multiplier, tier string) (float64, bool) {
+	c := p.load()
+
+	credit, ok := c.credits[provider]
+	if !ok {
+		return 0, false
+	}
+	perCredit, ok := c.usdPerCredit(provider, tier)
+	if !ok {
+		return 0, false
+	}
+	cost := float64(creditsPerRequest(credit, multiplier)) * perCredit
+	return c.rounding.round(cost), true
+}
+
+// usdPerCredit returns the effective USD price of one credit on a subscription tier.
+func (c *catalog) usdPerCredit(provider, tier string) (float64, bool) {
+	t, ok := c.providers[provider].SubscriptionTiers[tier]
+	if !ok || t.Credits <= 0 || t.PriceUSD < 0 {
+		return 0, false
+	}
+	return t.PriceUSD / float64(t.Credits), true
+}
+
+// SubscriptionUtilization summarizes how a month of credit usage fits a subscription tier.
+type SubscriptionUtilization struct {
+	Provider        string  `json:"provider"`
+	Tier            string  `json:"tier"`
+	Requests        int     `json:"requests"`
+	CreditsUsed     int     `json:"credits_used"`
+	CreditsIncluded int     `json:"credits_included"`
+	OverageCredits  int     `json:"overage_credits"`
+	Utilization     float64 `json:"utilization"` // CreditsUsed / CreditsIncluded
+	PlanCostUSD     float64 `json:"plan_cost_usd"`
+	OverageCostUSD  float64 `json:"overage_cost_usd"`
+	TotalCostUSD    float64 `json:"total_cost_usd"`
+	// EffectivePerRequestUSD is TotalCostUSD spread over Requests, so an
+	// underused plan shows its real per-request price.
+	EffectivePerRequestUSD float64 `json:"effective_per_request_usd"`
+	// Warnings reports counts that overflowed int and were clamped.
+	Warnings []Warning `json:"warnings,omitempty"`
+}
+
+// addIntSafe adds two non-negative counts, clamping at math.MaxInt.
+func addIntSafe(a, b int) (int, bool) {
+	if a > math.MaxInt-b {
+		return math.MaxInt, true
+	}
+	return a + b, false
+}
+
+// mulIntSafe multiplies two non-negative counts, clamping at math.MaxInt.
+func mulIntSafe(a, b int) (int, bool) {
+	if b != 0 && a > math.MaxInt/b {
+		return math.MaxInt, true
+	}
+	return a * b, false
+}
+
+// EstimateSubscriptionUtilization amortizes a subscription tier over a month of usage.
+// monthlyUsage maps multiplier names (e.g. "base", "js_rendering") to request counts;
+// non-positive counts are ignored. Credits beyond the tier's allowance are priced at
+// the tier's effective price per credit, since providers do not publish a uniform
+// overage rate. Returns false if the provider is not credit-based or the tier is
+// unknown or includes no credits.
+func (p *Pricer) EstimateSubscriptionUtilization(provider, tier string, monthlyUsage map[string]int) (SubscriptionUtilization, bool) {
+	c := p.load()
+
+	credit, ok := c.credits[provider]
+	if !ok {
+		return SubscriptionUtilization{}, false
+	}
+	perCredit, ok := c.usdPerCredit(provider, tier)
+	if !ok {
+		return SubscriptionUtilization{}, false
+	}
+	plan := c.providers[provider].SubscriptionTiers[tier]
+
+	u := SubscriptionUtilization{
+		Provider:        provider,
+		Tier:            tier,
+		CreditsIncluded: plan.Credits,
+		PlanCostUSD:     plan.PriceUSD,
+	}
+	var overflowed bool
+	for multiplier, count := range monthlyUsage {
+		if count <= 0 {
+			continue
+		}
+		credits, over := mulIntSafe(count, creditsPerRequest(credit, multiplier))
+		overflowed = overflowed || over
+		u.Requests, over = addIntSafe(u.Requests, count)
+		overflowed = overflowed || over
+		u.CreditsUsed, over = addIntSafe(u.CreditsUsed, credits)
+		overflowed = overflowed || over
+	}
+	if overflowed {
+		u.Warnings = append(u.Warnings, Warning{
+			Code:    WarningTokenOverflow,
+			Message: "credit count overflow detected - using clamped value",
+		})
+	}
+
+	u.Utilization = float64(u.CreditsUsed) / float64(u.CreditsIncluded)
+	if u.CreditsUsed > u.CreditsIncluded {
+		u.OverageCredits = u.CreditsUsed - u.CreditsIncluded
+		u.OverageCostUSD = c.rounding.round(float64(u.OverageCredits) * perCredit)
+	}
+	u.TotalCostUSD = c.rounding.round(u.PlanCostUSD + u.OverageCostUSD)
+	if u.Requests > 0 {
+		u.EffectivePerRequestUSD = c.rounding.round(u.TotalCostUSD / float64(u.Requests))
+	}
+	return u, true
+}
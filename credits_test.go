@@ -0,0 +1,233 @@
+package pricing_db
+
+import (
+	"maps"
+	"math"
+	"slices"
+	"testing"
+	"testing/fstest"
+)
+
+// =============================================================================
+// Credit Pricing Lookup Tests
+// =============================================================================
+
+func TestGetCreditPricing(t *testing.T) {
+	p, err := NewPricer()
+	if err != nil {
+		t.Fatalf("NewPricer failed: %v", err)
+	}
+
+	credit, ok := p.GetCreditPricing("scrapedo")
+	if !ok {
+		t.Fatal("expected credit pricing for scrapedo")
+	}
+	if credit.BaseCostPerRequest != 1 || credit.Multipliers["js_rendering"] != 5 {
+		t.Errorf("expected base 1 and js_rendering 5, got %+v", credit)
+	}
+
+	// The result is a copy
+	credit.Multipliers["js_rendering"] = 99
+	if got := p.CalculateCredit("scrapedo", "js_rendering"); got != 5 {
+		t.Errorf("expected mutation not to affect the catalog, got %d credits", got)
+	}
+
+	if _, ok := p.GetCreditPricing("openai"); ok {
+		t.Error("expected no credit pricing for openai")
+	}
+}
+
+func TestCreditMultiplier_Map(t *testing.T) {
+	got := CreditMultiplier{JSRendering: 5, JSPremium: 25}.Map()
+	want := map[string]int{"js_rendering": 5, "js_premium": 25}
+	if !maps.Equal(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+
+}
+
+func TestListCreditProviders(t *testing.T) {
+	p, err := NewPricer()
+	if err != nil {
+		t.Fatalf("NewPricer failed: %v", err)
+	}
+
+	providers := p.ListCreditProviders()
+	if !slices.Contains(providers, "scrapedo") || slices.Contains(providers, "openai") {
+		t.Errorf("expected scrapedo but not openai, got %v", providers)
+	}
+	if !slices.IsSorted(providers) {
+		t.Errorf("expected alphabetical order, got %v", providers)
+	}
+}
+
+// =============================================================================
+// Credit-to-USD Conversion Tests
+// =============================================================================
+
+func TestCreditCostUSD(t *testing.T) {
+	p, err := NewPricer()
+	if err != nil {
+		t.Fatalf("NewPricer failed: %v", err)
+	}
+
+	tests := []struct {
+		provider   string
+		multiplier string
+		tier       string
+		wantCost   float64
+		wantFound  bool
+	}{
+		{"scrapedo", "base", "hobby", 0.000116, true},        // $29 / 250k credits
+		{"scrapedo", "js_rendering", "hobby", 0.00058, true}, // 5 credits
+		{"scrapedo", "premium_proxy", "pro", 0.000792, true}, // 10 credits at $99 / 1.25M
+		{"scrapedo", "js_premium", "free", 0, true},
+		{"scrapedo", "base", "enterprise", 0, false},
+		{"unknown", "base", "hobby", 0, false},
+		{"openai", "base", "hobby", 0, false},
+	}
+	for _, tt := range tests {
+		cost, found := p.CreditCostUSD(tt.provider, tt.multiplier, tt.tier)
+		if found != tt.wantFound || !floatEquals(cost, tt.wantCost) {
+			t.Errorf("CreditCostUSD(%q, %q, %q) = $%f, %v; want $%f, %v",
+				tt.provider, tt.multiplier, tt.tier, cost, found, tt.wantCost, tt.wantFound)
+		}
+	}
+}
+
+func TestCreditCostUSD_ZeroCreditTier(t *testing.T) {
+	fsys := fstest.MapFS{
+		"configs/test_pricing.json": &fstest.MapFile{Data: []byte(`{
+			"provider": "test",
+			"billing_type": "credit",
+			"credit_pricing": {"base_cost_per_request": 1},
+			"subscription_tiers": {"empty": {"credits": 0, "price_usd": 10}}
+		}`)},
+	}
+	p, err := NewPricerFromFS(fsys, "configs")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if cost, found := p.CreditCostUSD("test", "base", "empty"); found || cost != 0 {
+		t.Errorf("expected (0, false) for a tier with no credits, got (%f, %v)", cost, found)
+	}
+}
+
+func TestCreditCostUSD_PackageLevel(t *testing.T) {
+	if cost := CreditCostUSD("scrapedo", "js_rendering", "hobby"); !floatEquals(cost, 0.00058) {
+		t.Errorf("expected $0.00058, got $%f", cost)
+	}
+	if cost := CreditCostUSD("scrapedo", "js_rendering", "nonexistent"); cost != 0 {
+		t.Errorf("expected 0 for unknown tier, got %f", cost)
+	}
+}
+
+// =============================================================================
+// Subscription Utilization Tests
+// =============================================================================
+
+func TestEstimateSubscriptionUtilization(t *testing.T) {
+	p, err := NewPricer()
+	if err != nil {
+		t.Fatalf("NewPricer failed: %v", err)
+	}
+
+	// hobby: 250k credits for $29
+	u, ok := p.EstimateSubscriptionUtilization("scrapedo", "hobby", map[string]int{
+		"base":         50000,
+		"js_rendering": 10000, // 5 credits each
+	})
+	if !ok {
+		t.Fatal("expected scrapedo hobby tier to be found")
+	}
+	if u.Requests != 60000 {
+		t.Errorf("Requests = %d, want 60000", u.Requests)
+	}
+	if u.CreditsUsed != 100000 {
+		t.Errorf("CreditsUsed = %d, want 100000", u.CreditsUsed)
+	}
+	if u.CreditsIncluded != 250000 || u.OverageCredits != 0 {
+		t.Errorf("CreditsIncluded/OverageCredits = %d/%d, want 250000/0", u.CreditsIncluded, u.OverageCredits)
+	}
+	if !floatEquals(u.Utilization, 0.4) {
+		t.Errorf("Utilization = %f, want 0.4", u.Utilization)
+	}
+	if !floatEquals(u.TotalCostUSD, 29) || u.OverageCostUSD != 0 {
+		t.Errorf("TotalCostUSD/OverageCostUSD = %f/%f, want 29/0", u.TotalCostUSD, u.OverageCostUSD)
+	}
+	if !floatEquals(u.EffectivePerRequestUSD, 29.0/60000) {
+		t.Errorf("EffectivePerRequestUSD = %f, want %f", u.EffectivePerRequestUSD, 29.0/60000)
+	}
+}
+
+func TestEstimateSubscriptionUtilization_Overage(t *testing.T) {
+	p, err := NewPricer()
+	if err != nil {
+		t.Fatalf("NewPricer failed: %v", err)
+	}
+
+	// 300k credits on hobby: 50k over at $29/250k per credit
+	u, ok := p.EstimateSubscriptionUtilization("scrapedo", "hobby", map[string]int{
+		"premium_proxy": 30000,
+		"js_premium":    -5, // ignored
+	})
+	if !ok {
+		t.Fatal("expected scrapedo hobby tier to be found")
+	}
+	if u.OverageCredits != 50000 {
+		t.Errorf("OverageCredits = %d, want 50000", u.OverageCredits)
+	}
+	if !floatEquals(u.OverageCostUSD, 5.8) {
+		t.Errorf("OverageCostUSD = %f, want 5.8", u.OverageCostUSD)
+	}
+	if !floatEquals(u.TotalCostUSD, 34.8) {
+		t.Errorf("TotalCostUSD = %f, want 34.8", u.TotalCostUSD)
+	}
+	if !floatEquals(u.Utilization, 1.2) {
+		t.Errorf("Utilization = %f, want 1.2", u.Utilization)
+	}
+}
+
+func TestEstimateSubscriptionUtilization_Overflow(t *testing.T) {
+	p, err := NewPricer()
+	if err != nil {
+		t.Fatalf("NewPricer failed: %v", err)
+	}
+
+	u, ok := p.EstimateSubscriptionUtilization("scrapedo", "hobby", map[string]int{
+		"base":          math.MaxInt - 1,
+		"premium_proxy": math.MaxInt / 2,
+	})
+	if !ok {
+		t.Fatal("expected scrapedo hobby tier to be found")
+	}
+	if u.CreditsUsed != math.MaxInt || u.Requests != math.MaxInt || u.OverageCredits <= 0 {
+		t.Errorf("expected clamped counts, got %+v", u)
+	}
+	if len(u.Warnings) != 1 || u.Warnings[0].Code != WarningTokenOverflow {
+		t.Errorf("expected an overflow warning, got %+v", u.Warnings)
+	}
+}
+
+func TestEstimateSubscriptionUtilization_NoUsage(t *testing.T) {
+	p, err := NewPricer()
+	if err != nil {
+		t.Fatalf("NewPricer failed: %v", err)
+	}
+
+	u, ok := p.EstimateSubscriptionUtilization("scrapedo", "pro", nil)
+	if !ok {
+		t.Fatal("expected scrapedo pro tier to be found")
+	}
+	if u.Requests != 0 || u.EffectivePerRequestUSD != 0 || !floatEquals(u.TotalCostUSD, 99) {
+		t.Errorf("unexpected utilization for no usage: %+v", u)
+	}
+
+	if _, ok := p.EstimateSubscriptionUtilization("scrapedo", "enterprise", nil); ok {
+		t.Error("expected unknown tier to return false")
+	}
+	if _, ok := p.EstimateSubscriptionUtilization("openai", "hobby", nil); ok {
+		t.Error("expected non-credit provider to return false")
+	}
+}
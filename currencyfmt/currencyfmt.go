@@ -0,0 +1,175 @@
+// Package currencyfmt formats pricing_db costs with locale-specific currency
+// rules, e.g. "1.234,56 €" for de-DE instead of "$1234.56".
+//
+// The core pricing_db package depends only on the standard library and reports
+// costs as USD float64 values. Importing this package opts in to
+// golang.org/x/text for locale-aware digits, separators, and symbols:
+//
+//	f, err := currencyfmt.New("de-DE", currencyfmt.WithCurrency("EUR", 0.92), currencyfmt.WithDecimals(2))
+//	fmt.Println(f.Format(details.TotalCost)) // 1.234,56 €
+//
+// Separators and currency symbols come from CLDR via golang.org/x/text. The
+// symbol follows the amount for the European locales that write it that way
+// (see suffixLanguages) and precedes it elsewhere.
+package currencyfmt
+
+import (
+	"fmt"
+	"math"
+	"unicode"
+
+	"golang.org/x/text/currency"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"golang.org/x/text/number"
+)
+
+// maxDecimals is the largest supported number of decimal places.
+const maxDecimals = 12
+
+// nbsp separates the symbol from the amount, as in CLDR, so the two never wrap apart.
+const nbsp = "\u00a0"
+
+// Formatter formats USD costs for one locale and currency. It is safe for
+// concurrent use.
+type Formatter struct {
+	printer  *message.Printer
+	symbol   string
+	suffix   bool    // Symbol follows the amount
+	spaced   bool    // Space between a leading symbol and the amount
+	perUSD   float64 // Units of the display currency per USD
+	decimals int
+}
+
+type options struct {
+	currency string
+	perUSD   float64
+	decimals int
+}
+
+// Option configures a Formatter.
+type Option func(*options)
+
+// WithCurrency displays costs in the currency with ISO 4217 code, converted at
+// perUSD units per US dollar (e.g. "EUR", 0.92). The default is USD at 1.
+func WithCurrency(code string, perUSD float64) Option {
+	return func(o *options) {
+		o.currency = code
+		o.perUSD = perUSD
+	}
+}
+
+// WithDecimals sets the number of decimal places (0-12). The default is 6,
+// matching pricing-cli, since single requests often cost fractions of a cent.
+func WithDecimals(n int) Option {
+	return func(o *options) { o.decimals = n }
+}
+
+// New returns a Formatter for locale, a BCP 47 tag such as "de-DE" or "fr-CH".
+func New(locale string, opts ...Option) (*Formatter, error) {
+	o := options{currency: "USD", perUSD: 1, decimals: 6}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	tag, err := language.Parse(locale)
+	if err != nil {
+		return nil, fmt.Errorf("locale %q: %w", locale, err)
+	}
+	unit, err := currency.ParseISO(o.currency)
+	if err != nil {
+		return nil, fmt.Errorf("currency %q: %w", o.currency, err)
+	}
+	if o.perUSD <= 0 || math.IsInf(o.perUSD, 0) || math.IsNaN(o.perUSD) {
+		return nil, fmt.Errorf("currency %s: rate %g per USD must be positive", unit, o.perUSD)
+	}
+	if o.decimals < 0 || o.decimals > maxDecimals {
+		return nil, fmt.Errorf("decimals %d out of range (0-%d)", o.decimals, maxDecimals)
+	}
+
+	printer := message.NewPrinter(tag)
+	f := &Formatter{
+		printer:  printer,
+		symbol:   printer.Sprint(currency.Symbol(unit)),
+		perUSD:   o.perUSD,
+		decimals: o.decimals,
+	}
+	f.suffix, f.spaced = placement(tag)
+	if !f.suffix && !f.spaced {
+		// Alphabetic symbols ("CHF", "EUR") need a space before the amount
+		last := []rune(f.symbol)
+		f.spaced = len(last) > 0 && unicode.IsLetter(last[len(last)-1])
+	}
+	return f, nil
+}
+
+// Format converts usd to the display currency and formats it with the
+// Formatter's decimal places.
+func (f *Formatter) Format(usd float64) string {
+	return f.FormatDecimals(usd, f.decimals)
+}
+
+// FormatDecimals is Format with the given number of decimal places, clamped to 0-12.
+func (f *Formatter) FormatDecimals(usd float64, decimals int) string {
+	decimals = min(max(decimals, 0), maxDecimals)
+	amount := usd * f.perUSD
+	sign := ""
+	if amount < 0 && math.Round(amount*math.Pow10(decimals)) != 0 {
+		sign = "-"
+	}
+	digits := f.printer.Sprint(number.Decimal(math.Abs(amount), number.Scale(decimals)))
+	switch {
+	case f.suffix:
+		return sign + digits + nbsp + f.symbol
+	case f.spaced:
+		return sign + f.symbol + nbsp + digits
+	default:
+		return sign + f.symbol + digits
+	}
+}
+
+// Format formats usd for locale with the given options. Callers formatting
+// many values should create a Formatter once with New.
+func Format(usd float64, locale string, opts ...Option) (string, error) {
+	f, err := New(locale, opts...)
+	if err != nil {
+		return "", err
+	}
+	return f.Format(usd), nil
+}
+
+// suffixLanguages are the languages whose CLDR currency pattern puts the symbol
+// after the amount ("1.234,56 €"), with the regions that put it first instead.
+var suffixLanguages = map[string][]string{
+	"bg": nil, "ca": nil, "cs": nil, "da": nil, "el": nil, "et": nil,
+	"fi": nil, "fr": nil, "hr": nil, "hu": nil, "is": nil, "lt": nil,
+	"lv": nil, "nb": nil, "no": nil, "pl": nil, "ro": nil, "ru": nil,
+	"sk": nil, "sl": nil, "sr": nil, "sv": nil, "uk": nil, "vi": nil,
+	"de": {"AT", "CH", "LI"},
+	"it": {"CH"},
+	"es": {"419", "AR", "BO", "CL", "CO", "CR", "DO", "EC", "GT", "HN", "MX", "NI", "PA", "PE", "PR", "PY", "SV", "US", "UY", "VE"},
+}
+
+// spacedPrefixLanguages put a space between a leading symbol and the amount ("€ 1.234,56").
+var spacedPrefixLanguages = map[string]bool{"nl": true, "de": true, "it": true}
+
+// placement returns where tag writes the currency symbol: after the amount, or
+// before it and whether separated by a space. Portuguese puts it after only in
+// Portugal ("1234,56 €"); Brazil and the default write "R$ 1.234,56".
+func placement(tag language.Tag) (suffix, spaced bool) {
+	base, _ := tag.Base()
+	region, _ := tag.Region()
+	lang := base.String()
+	if lang == "pt" {
+		return region.String() == "PT", true
+	}
+	prefixRegions, ok := suffixLanguages[lang]
+	if !ok {
+		return false, spacedPrefixLanguages[lang]
+	}
+	for _, r := range prefixRegions {
+		if region.String() == r {
+			return false, spacedPrefixLanguages[lang]
+		}
+	}
+	return true, false
+}
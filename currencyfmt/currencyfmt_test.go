@@ -0,0 +1,68 @@
+package currencyfmt
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		locale string
+		opts   []Option
+		usd    float64
+		want   string
+	}{
+		{"en-US", nil, 1234.5, "$1,234.500000"},
+		{"en-US", []Option{WithDecimals(2)}, 1234.567, "$1,234.57"},
+		{"de-DE", []Option{WithCurrency("EUR", 1), WithDecimals(2)}, 1234.56, "1.234,56\u00a0€"},
+		{"de-DE", []Option{WithDecimals(2)}, 1234.56, "1.234,56\u00a0$"},
+		{"fr-FR", []Option{WithCurrency("EUR", 0.5), WithDecimals(2)}, 2469.12, "1\u00a0234,56\u00a0€"},
+		{"de-CH", []Option{WithCurrency("CHF", 1), WithDecimals(2)}, 1234.56, "CHF\u00a01’234.56"},
+		{"nl-NL", []Option{WithCurrency("EUR", 1), WithDecimals(2)}, 1234.56, "€\u00a01.234,56"},
+		{"pt-BR", []Option{WithCurrency("BRL", 1), WithDecimals(2)}, 1234.56, "R$\u00a01.234,56"},
+		{"es-MX", []Option{WithCurrency("MXN", 1), WithDecimals(2)}, 1234.56, "$1,234.56"},
+		{"en-US", []Option{WithDecimals(2)}, -0.5, "-$0.50"},
+		{"en-US", []Option{WithDecimals(2)}, -0.001, "$0.00"}, // No negative zero
+	}
+	for _, tc := range tests {
+		got, err := Format(tc.usd, tc.locale, tc.opts...)
+		if err != nil {
+			t.Errorf("%s: %v", tc.locale, err)
+			continue
+		}
+		if got != tc.want {
+			t.Errorf("%s %v: expected %q, got %q", tc.locale, tc.usd, tc.want, got)
+		}
+	}
+}
+
+func TestFormatter_FormatDecimals(t *testing.T) {
+	f, err := New("de-DE", WithCurrency("EUR", 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := f.Format(0.0001234); got != "0,000123\u00a0€" {
+		t.Errorf("expected 6 decimals by default, got %q", got)
+	}
+	if got := f.FormatDecimals(1234, 0); got != "1.234\u00a0€" {
+		t.Errorf("expected 0 decimals, got %q", got)
+	}
+}
+
+func TestNew_Errors(t *testing.T) {
+	tests := []struct {
+		locale string
+		opts   []Option
+		want   string
+	}{
+		{"not a locale!", nil, "locale"},
+		{"en-US", []Option{WithCurrency("EURO", 1)}, "currency"},
+		{"en-US", []Option{WithCurrency("EUR", 0)}, "must be positive"},
+		{"en-US", []Option{WithDecimals(13)}, "out of range"},
+	}
+	for _, tc := range tests {
+		if _, err := New(tc.locale, tc.opts...); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s %d options: expected error containing %q, got %v", tc.locale, len(tc.opts), tc.want, err)
+		}
+	}
+}
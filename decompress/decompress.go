@@ -0,0 +1,95 @@
+// Package decompress transparently decompresses gzip and zstd response
+// archives for pricing_db's streaming APIs and CLI.
+//
+// Compression is detected from the input's magic bytes, not its file name, so
+// plain JSON passes through unchanged:
+//
+//	f, _ := os.Open("responses.jsonl.zst")
+//	r, err := decompress.NewReader(f)
+//	err = pricer.PriceStream(r, pricing_db.FormatGemini, fn)
+//
+// The core pricing_db package depends only on the standard library; PriceStream
+// detects gzip on its own, and this package adds zstd.
+package decompress
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+var (
+	gzipMagic = []byte{0x1f, 0x8b}
+	zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+)
+
+// NewReader returns a reader of r's decompressed content if r starts with a
+// gzip or zstd header, and of r unchanged otherwise. Close releases the
+// decompressor; it does not close r.
+func NewReader(r io.Reader) (io.ReadCloser, error) {
+	br := bufio.NewReader(r)
+	// A short or empty input cannot be compressed; Peek's error only means that
+	head, _ := br.Peek(len(zstdMagic))
+	switch {
+	case bytes.HasPrefix(head, gzipMagic):
+		return gzip.NewReader(br)
+	case bytes.HasPrefix(head, zstdMagic):
+		dec, err := zstd.NewReader(br)
+		if err != nil {
+			return nil, err
+		}
+		return dec.IOReadCloser(), nil
+	}
+	return io.NopCloser(br), nil
+}
+
+// NewReadCloser is NewReader for a reader the caller hands over, such as a
+// file or an HTTP response body: Close closes rc as well. rc is closed if
+// NewReadCloser fails.
+func NewReadCloser(rc io.ReadCloser) (io.ReadCloser, error) {
+	r, err := NewReader(rc)
+	if err != nil {
+		rc.Close()
+		return nil, err
+	}
+	return stackedReader{ReadCloser: r, under: rc}, nil
+}
+
+// Open opens the named file for reading, decompressing it if it is gzip or
+// zstd compressed. Close closes the file.
+func Open(name string) (io.ReadCloser, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	return NewReadCloser(f)
+}
+
+// ReadFile reads the named file, decompressing it if it is gzip or zstd
+// compressed.
+func ReadFile(name string) ([]byte, error) {
+	r, err := Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return io.ReadAll(r)
+}
+
+// stackedReader closes both the decompressor and the reader under it.
+type stackedReader struct {
+	io.ReadCloser
+	under io.Closer
+}
+
+func (r stackedReader) Close() error {
+	err := r.ReadCloser.Close()
+	if uerr := r.under.Close(); err == nil {
+		err = uerr
+	}
+	return err
+}
@@ -0,0 +1,98 @@
+package decompress
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+const payload = `{"modelVersion":"gemini-2.5-flash","usageMetadata":{"promptTokenCount":1000}}`
+
+func gzipped(t *testing.T, data string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	w.Write([]byte(data))
+	if err := w.Close(); err != nil {
+		t.Fatalf("gzip: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func zstded(t *testing.T, data string) []byte {
+	t.Helper()
+	enc, err := zstd.NewWriter(nil)
+	if err != nil {
+		t.Fatalf("zstd: %v", err)
+	}
+	defer enc.Close()
+	return enc.EncodeAll([]byte(data), nil)
+}
+
+func TestNewReader(t *testing.T) {
+	tests := []struct {
+		name  string
+		input []byte
+		want  string
+	}{
+		{"plain", []byte(payload), payload},
+		{"gzip", gzipped(t, payload), payload},
+		{"zstd", zstded(t, payload), payload},
+		{"empty", nil, ""},
+		{"short", []byte("{"), "{"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := NewReader(bytes.NewReader(tt.input))
+			if err != nil {
+				t.Fatalf("NewReader failed: %v", err)
+			}
+			defer r.Close()
+			got, err := io.ReadAll(r)
+			if err != nil {
+				t.Fatalf("read failed: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestNewReader_CorruptGzip(t *testing.T) {
+	if _, err := NewReader(strings.NewReader("\x1f\x8bnot gzip")); err == nil {
+		t.Error("expected error for a corrupt gzip header")
+	}
+}
+
+func TestReadFile(t *testing.T) {
+	dir := t.TempDir()
+	for name, data := range map[string][]byte{
+		"plain.json":    []byte(payload),
+		"a.json.gz":     gzipped(t, payload),
+		"b.json.zst":    zstded(t, payload),
+		"misnamed.json": zstded(t, payload), // detection uses content, not the name
+	} {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		got, err := ReadFile(path)
+		if err != nil {
+			t.Fatalf("%s: ReadFile failed: %v", name, err)
+		}
+		if string(got) != payload {
+			t.Errorf("%s: expected %q, got %q", name, payload, got)
+		}
+	}
+
+	if _, err := ReadFile(filepath.Join(dir, "missing.json")); err == nil {
+		t.Error("expected error for a missing file")
+	}
+}
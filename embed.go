@@ -1,12 +1,12 @@
+//go:build !pricing_slim
+
 package pricing_db
 
-import (
-	"embed"
-	"io/fs"
-)
+import "embed"
 
 // ConfigFS contains the embedded pricing configuration files.
-// These are compiled into the binary for portability.
+// These are compiled into the binary for portability. Builds with the
+// pricing_slim tag embed a subset instead (see embed_slim.go).
 //
 // Note: ConfigFS is exported for backward compatibility. New code should prefer
 // EmbeddedConfigFS() which provides read-only access. The embedded configs are
@@ -15,10 +15,3 @@ import (
 //
 //go:embed configs/*.json
 var ConfigFS embed.FS
-
-// EmbeddedConfigFS returns the embedded pricing configuration filesystem.
-// This provides a read-only accessor that cannot be reassigned.
-// Prefer this over direct ConfigFS access in new code.
-func EmbeddedConfigFS() fs.FS {
-	return ConfigFS
-}
@@ -0,0 +1,80 @@
+package pricing_db
+
+import (
+	"bytes"
+	"testing"
+	"testing/fstest"
+)
+
+// =============================================================================
+// Dedicated Instance (HF Inference Endpoints) Pricing Tests
+// =============================================================================
+
+func TestCalculateEndpointHours(t *testing.T) {
+	p, err := NewPricer()
+	if err != nil {
+		t.Fatalf("NewPricer failed: %v", err)
+	}
+
+	tests := []struct {
+		instanceType string
+		hours        float64
+		wantCost     float64
+		wantFound    bool
+	}{
+		{"nvidia-a10g-x1", 720, 720.0, true},
+		{"nvidia-a100-x4", 0.5, 5.0, true},
+		{"hf-endpoints/nvidia-l4-x1", 10, 8.0, true},
+		{"nvidia-a10g-x1", 0, 0, true},
+		{"nvidia-a10g-x1", -3, 0, true},
+		{"nvidia-a10g", 1, 0, false}, // instance types match exactly
+		{"unknown-instance", 1, 0, false},
+	}
+	for _, tt := range tests {
+		cost, found := p.CalculateEndpointHours(tt.instanceType, tt.hours)
+		if found != tt.wantFound || !floatEquals(cost, tt.wantCost) {
+			t.Errorf("CalculateEndpointHours(%q, %v) = $%f, %v; want $%f, %v", tt.instanceType, tt.hours, cost, found, tt.wantCost, tt.wantFound)
+		}
+	}
+
+	info, ok := p.GetInstancePricing("nvidia-h100-x8")
+	if !ok || info.Accelerator != "nvidia-h100" || info.AcceleratorCount != 8 || info.Vendor != "aws" {
+		t.Errorf("unexpected instance pricing: %+v, %v", info, ok)
+	}
+	if got := CalculateEndpointCost("nvidia-t4-x1", 2); !floatEquals(got, 1.0) {
+		t.Errorf("CalculateEndpointCost: expected $1.00, got $%f", got)
+	}
+}
+
+func TestNewPricerFromFS_InvalidInstancePricing(t *testing.T) {
+	for _, body := range []string{
+		`{"hourly_usd": -1}`,
+		`{"hourly_usd": 5000}`,
+		`{"hourly_usd": 1, "accelerator_count": -1}`,
+	} {
+		fsys := fstest.MapFS{
+			"configs/bad_pricing.json": &fstest.MapFile{Data: []byte(`{"instance_types": {"bad-x1": ` + body + `}}`)},
+		}
+		if _, err := NewPricerFromFS(fsys, "configs"); err == nil {
+			t.Errorf("expected error for %s", body)
+		}
+	}
+}
+
+func TestSnapshot_InstanceTypesRoundTrip(t *testing.T) {
+	p, err := NewPricer()
+	if err != nil {
+		t.Fatalf("NewPricer failed: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := p.Export(&buf); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	restored, err := NewPricerFromSnapshot(&buf)
+	if err != nil {
+		t.Fatalf("NewPricerFromSnapshot failed: %v", err)
+	}
+	if cost, ok := restored.CalculateEndpointHours("nvidia-a10g-x1", 1); !ok || !floatEquals(cost, 1.0) {
+		t.Errorf("expected instance pricing to survive a snapshot, got $%f, %v", cost, ok)
+	}
+}
@@ -28,7 +28,7 @@ func ExampleNewPricer() {
 	fmt.Printf("Loaded %d providers\n", p.ProviderCount())
 	fmt.Printf("Loaded %d+ models\n", p.ModelCount()/100*100) // Round down to nearest 100
 	// Output:
-	// Loaded 27 providers
+	// Loaded 29 providers
 	// Loaded 300+ models
 }
 
@@ -183,10 +183,10 @@ func ExamplePricer_ListProviders() {
 		fmt.Println(name)
 	}
 	// Output:
-	// Provider count: 27
+	// Provider count: 29
+	// ai21
 	// anthropic
 	// baseten
-	// bedrock
 }
 
 // ExamplePricer_GetProviderMetadata demonstrates retrieving provider details.
@@ -203,8 +203,8 @@ func ExamplePricer_GetProviderMetadata() {
 	fmt.Printf("Image models: %d\n", len(meta.ImageModels))
 	// Output:
 	// Provider: openai
-	// Models: 17
-	// Image models: 7
+	// Models: 23
+	// Image models: 9
 }
 
 // ExampleCost_Format demonstrates the human-readable cost format.
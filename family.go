@@ -0,0 +1,138 @@
+package pricing_db
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// familyMember is a model key split into its family pattern and version, e.g.
+// "gemini-2.5-flash" is pattern ["gemini", "*", "flash"] at version [2 5].
+type familyMember struct {
+	key     string
+	pattern []string
+	version []int
+}
+
+// versionWildcard stands for the version run in a family pattern.
+const versionWildcard = "*"
+
+// parseFamily splits model on "-" and replaces its first run of version
+// segments ("3", "2.5", or "4-5" as in claude-sonnet-4-5) with versionWildcard.
+// Segments of more than three digits, such as date suffixes, are not versions.
+// It returns false for names without a version.
+func parseFamily(model string) (pattern []string, version []int, ok bool) {
+	segments := strings.Split(model, "-")
+	start := slices.IndexFunc(segments, isVersionSegment)
+	if start < 0 {
+		return nil, nil, false
+	}
+	end := start
+	for end < len(segments) && isVersionSegment(segments[end]) {
+		for _, part := range strings.Split(strings.TrimPrefix(segments[end], "v"), ".") {
+			n, _ := strconv.Atoi(part)
+			version = append(version, n)
+		}
+		end++
+	}
+	pattern = append(slices.Clone(segments[:start]), versionWildcard)
+	return append(pattern, segments[end:]...), version, true
+}
+
+// isVersionSegment reports whether s is a version such as "3", "2.5", or "v4".
+func isVersionSegment(s string) bool {
+	s = strings.TrimPrefix(s, "v")
+	if s == "" {
+		return false
+	}
+	for _, part := range strings.Split(s, ".") {
+		if part == "" || len(part) > 3 || strings.Trim(part, "0123456789") != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// indexFamilies parses every versioned model key for WithFamilyFallback.
+func indexFamilies(models map[string]ModelPricing) []familyMember {
+	var members []familyMember
+	for key := range models {
+		if pattern, version, ok := parseFamily(key); ok {
+			members = append(members, familyMember{key, pattern, version})
+		}
+	}
+	// Sorted so equal versions resolve deterministically
+	slices.SortFunc(members, func(a, b familyMember) int { return strings.Compare(a.key, b.key) })
+	return members
+}
+
+// familyMatch returns the known family member closest to an unknown model:
+// among members whose pattern is the longest leading part of the model's
+// pattern, the newest version not above the model's, or else the oldest above
+// it. "gemini-3.1-flash-exp" matches gemini-3-flash (pattern gemini-*-flash)
+// rather than gemini-2.5-flash or gemini-2.5-flash-lite.
+func (c *catalog) familyMatch(model string) (string, bool) {
+	pattern, version, ok := parseFamily(model)
+	if !ok {
+		return "", false
+	}
+	qualified := strings.Contains(model, "/")
+
+	var best *familyMember
+	bestLen := 0
+	for i := range c.families {
+		m := &c.families[i]
+		if strings.Contains(m.key, "/") != qualified || len(m.pattern) < bestLen || !isPatternPrefix(m.pattern, pattern) {
+			continue
+		}
+		if len(m.pattern) > bestLen || closerVersion(m.version, best.version, version) {
+			best, bestLen = m, len(m.pattern)
+		}
+	}
+	if best == nil {
+		return "", false
+	}
+	return best.key, true
+}
+
+// isPatternPrefix reports whether prefix equals the leading segments of pattern.
+func isPatternPrefix(prefix, pattern []string) bool {
+	return len(prefix) <= len(pattern) && slices.Equal(prefix, pattern[:len(prefix)])
+}
+
+// closerVersion reports whether candidate is closer to target than current:
+// versions at or below target beat those above it; below, newer is closer;
+// above, older is closer.
+func closerVersion(candidate, current, target []int) bool {
+	candidateBelow := slices.Compare(candidate, target) <= 0
+	currentBelow := slices.Compare(current, target) <= 0
+	if candidateBelow != currentBelow {
+		return candidateBelow
+	}
+	if candidateBelow {
+		return slices.Compare(candidate, current) > 0
+	}
+	return slices.Compare(candidate, current) < 0
+}
+
+// fallbackRates returns the rates for an unknown model under WithFamilyFallback
+// or WithFallbackPricing, with the warning to attach. A family match takes
+// precedence over fallback pricing.
+func (c *catalog) fallbackRates(model string) (*modelRates, Warning, bool) {
+	if c.familyFallback {
+		if key, ok := c.familyMatch(model); ok {
+			return c.rates[key], Warning{
+				Code:    WarningFallbackApplied,
+				Message: fmt.Sprintf("model %q not in pricing data - priced as family member %q", model, key),
+			}, true
+		}
+	}
+	if c.fallback != nil {
+		return c.fallback, Warning{
+			Code:    WarningFallbackPricing,
+			Message: fmt.Sprintf("model %q not in pricing data - priced at fallback rates", model),
+		}, true
+	}
+	return nil, Warning{}, false
+}
@@ -0,0 +1,110 @@
+package pricing_db
+
+import (
+	"slices"
+	"testing"
+	"testing/fstest"
+)
+
+// =============================================================================
+// Family Fallback Tests
+// =============================================================================
+
+func familyTestFS() fstest.MapFS {
+	return fstest.MapFS{
+		"configs/google_pricing.json": &fstest.MapFile{Data: []byte(`{
+			"provider": "google",
+			"models": {
+				"gemini-2.0-flash": {"input_per_million": 0.1, "output_per_million": 0.4},
+				"gemini-2.5-flash": {"input_per_million": 0.3, "output_per_million": 2.5},
+				"gemini-2.5-flash-lite": {"input_per_million": 0.1, "output_per_million": 0.4},
+				"gemini-3-flash": {"input_per_million": 0.5, "output_per_million": 3.0},
+				"gemini-2.5-pro": {"input_per_million": 1.25, "output_per_million": 10.0}
+			}
+		}`)},
+		"configs/anthropic_pricing.json": &fstest.MapFile{Data: []byte(`{
+			"provider": "anthropic",
+			"models": {
+				"claude-sonnet-4-5": {"input_per_million": 3.0, "output_per_million": 15.0},
+				"claude-sonnet-4": {"input_per_million": 3.0, "output_per_million": 15.0}
+			}
+		}`)},
+	}
+}
+
+func TestWithFamilyFallback(t *testing.T) {
+	p, err := NewPricerFromFS(familyTestFS(), "configs", WithFamilyFallback())
+	if err != nil {
+		t.Fatalf("NewPricerFromFS failed: %v", err)
+	}
+
+	tests := []struct {
+		model, member string
+	}{
+		{"gemini-3.1-flash-unknown", "gemini-3-flash"},         // Newest at or below 3.1, not the -lite family
+		{"gemini-2.7-flash-lite-exp", "gemini-2.5-flash-lite"}, // Longest matching pattern wins
+		{"gemini-1.5-flash", "gemini-2.0-flash"},               // Nothing older: oldest newer member
+		{"gemini-3-pro", "gemini-2.5-pro"},
+		{"claude-sonnet-5", "claude-sonnet-4-5"},             // claude-sonnet-4-7 would prefix-match claude-sonnet-4
+		{"google/gemini-3.5-flash", "google/gemini-3-flash"}, // Qualified names match qualified keys
+	}
+	for _, tt := range tests {
+		details := p.CalculateUsage(tt.model, TokenUsage{PromptTokens: 1_000_000}, nil)
+		member := p.CalculateUsage(tt.member, TokenUsage{PromptTokens: 1_000_000}, nil)
+		if !details.Unknown || !floatEquals(details.TotalCost, member.TotalCost) {
+			t.Errorf("%s: expected Unknown at %s rates, got %+v", tt.model, tt.member, details)
+		}
+		if len(details.WarningDetails) != 1 || details.WarningDetails[0].Code != WarningFallbackApplied {
+			t.Errorf("%s: expected a fallback_applied warning, got %+v", tt.model, details.WarningDetails)
+		}
+	}
+
+	// No versioned family: still unpriced
+	if details := p.CalculateUsage("mystery-model", TokenUsage{PromptTokens: 1000}, nil); details.TotalCost != 0 || len(details.Warnings) != 0 {
+		t.Errorf("expected no family for an unversioned name, got %+v", details)
+	}
+	if cost := p.Calculate("gemini-9-flash", 1_000_000, 0); !cost.Unknown || !floatEquals(cost.TotalCost, 0.5) {
+		t.Errorf("expected Calculate to price gemini-9-flash as gemini-3-flash, got %+v", cost)
+	}
+}
+
+func TestWithFamilyFallback_Precedence(t *testing.T) {
+	p, err := NewPricerFromFS(familyTestFS(), "configs", WithFamilyFallback(),
+		WithFallbackPricing(ModelPricing{InputPerMillion: 50, OutputPerMillion: 50}))
+	if err != nil {
+		t.Fatalf("NewPricerFromFS failed: %v", err)
+	}
+	if d := p.CalculateUsage("gemini-4-flash", TokenUsage{PromptTokens: 1_000_000}, nil); !floatEquals(d.TotalCost, 0.5) {
+		t.Errorf("expected the family match to win over fallback pricing, got %f", d.TotalCost)
+	}
+	if d := p.CalculateUsage("mystery-model", TokenUsage{PromptTokens: 1_000_000}, nil); !floatEquals(d.TotalCost, 50) || d.WarningDetails[0].Code != WarningFallbackPricing {
+		t.Errorf("expected fallback pricing without a family, got %+v", d)
+	}
+
+	// Disabled by default
+	plain, _ := NewPricerFromFS(familyTestFS(), "configs")
+	if d := plain.CalculateUsage("gemini-4-flash", TokenUsage{PromptTokens: 1_000_000}, nil); d.TotalCost != 0 {
+		t.Errorf("expected no family fallback by default, got %f", d.TotalCost)
+	}
+}
+
+func TestParseFamily(t *testing.T) {
+	tests := []struct {
+		model   string
+		pattern []string
+		version []int
+		ok      bool
+	}{
+		{"gemini-2.5-flash", []string{"gemini", "*", "flash"}, []int{2, 5}, true},
+		{"claude-sonnet-4-5-20250929", []string{"claude", "sonnet", "*", "20250929"}, []int{4, 5}, true},
+		{"llama-v3-70b", []string{"llama", "*", "70b"}, []int{3}, true},
+		{"gpt-4o", nil, nil, false},
+		{"mystery-model", nil, nil, false},
+	}
+	for _, tt := range tests {
+		pattern, version, ok := parseFamily(tt.model)
+		if ok != tt.ok || !slices.Equal(pattern, tt.pattern) || !slices.Equal(version, tt.version) {
+			t.Errorf("parseFamily(%q) = %v, %v, %v; want %v, %v, %v", tt.model, pattern, version, ok, tt.pattern, tt.version, tt.ok)
+		}
+	}
+}
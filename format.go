@@ -0,0 +1,46 @@
+package pricing_db
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// detailsTemplateFuncs are the functions available to cost detail templates.
+var detailsTemplateFuncs = template.FuncMap{
+	// usd formats a cost as dollars: usd .TotalCost is "$0.001234" (6 decimals);
+	// usd .TotalCost 2 is "$0.00".
+	"usd": func(value float64, decimals ...int) string {
+		precision := 6
+		if len(decimals) > 0 {
+			precision = decimals[0]
+		}
+		return fmt.Sprintf("$%.*f", precision, value)
+	},
+	"join": strings.Join,
+}
+
+// ParseDetailsTemplate parses a text/template for rendering CostDetails. The
+// template executes with a CostDetails as its data (e.g. {{.TotalCost}}) and can
+// use two functions besides the text/template builtins:
+//
+//	usd   format a cost in dollars: {{usd .TotalCost}} → $0.001234, {{usd .TotalCost 2}} → $0.00
+//	join  join strings: {{join .Warnings "; "}}
+func ParseDetailsTemplate(text string) (*template.Template, error) {
+	return template.New("details").Funcs(detailsTemplateFuncs).Parse(text)
+}
+
+// FormatDetails renders d with a ParseDetailsTemplate template, so teams can
+// tailor cost output (runbooks, chat notifications, logs) without code changes.
+// Callers rendering many results should parse the template once instead.
+func FormatDetails(d CostDetails, text string) (string, error) {
+	t, err := ParseDetailsTemplate(text)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	if err := t.Execute(&b, d); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,31 @@
+package pricing_db
+
+import (
+	"strings"
+	"testing"
+)
+
+// =============================================================================
+// FormatDetails Tests
+// =============================================================================
+
+func TestFormatDetails(t *testing.T) {
+	d := CostDetails{TotalCost: 0.0123456, TierApplied: ">200K", Warnings: []string{"a", "b"}}
+
+	out, err := FormatDetails(d, `{{.TierApplied}}: {{usd .TotalCost}} ({{usd .TotalCost 2}}){{with .Warnings}} [{{join . "; "}}]{{end}}`)
+	if err != nil {
+		t.Fatalf("FormatDetails failed: %v", err)
+	}
+	if want := ">200K: $0.012346 ($0.01) [a; b]"; out != want {
+		t.Errorf("expected %q, got %q", want, out)
+	}
+}
+
+func TestFormatDetails_Errors(t *testing.T) {
+	if _, err := FormatDetails(CostDetails{}, `{{.TotalCost`); err == nil {
+		t.Error("expected parse error for an unterminated action")
+	}
+	if _, err := FormatDetails(CostDetails{}, `{{.NoSuchField}}`); err == nil || !strings.Contains(err.Error(), "NoSuchField") {
+		t.Errorf("expected execution error naming the field, got %v", err)
+	}
+}
@@ -2,12 +2,18 @@ module github.com/ai8future/pricing_db
 
 go 1.25.5
 
-require github.com/ai8future/chassis-go/v11 v11.1.3
+require (
+	github.com/BurntSushi/toml v1.6.0
+	github.com/ai8future/chassis-go/v11 v11.1.3
+	github.com/klauspost/compress v1.18.0
+	go.opentelemetry.io/otel v1.40.0
+	go.opentelemetry.io/otel/trace v1.40.0
+	golang.org/x/text v0.40.0
+	gopkg.in/yaml.v3 v3.0.1
+)
 
 require (
 	github.com/cespare/xxhash/v2 v2.3.0 // indirect
-	go.opentelemetry.io/otel v1.40.0 // indirect
-	go.opentelemetry.io/otel/trace v1.40.0 // indirect
 )
 
 replace github.com/ai8future/chassis-go/v11 => ../../chassis_suite/chassis-go
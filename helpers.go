@@ -1,45 +1,93 @@
 package pricing_db
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"sync"
+	"sync/atomic"
 )
 
-// Package-level pricer instance (initialized lazily)
+// Package-level pricer instance (initialized lazily unless set via Init or SetDefaultPricer)
 var (
-	defaultPricer *Pricer
-	initOnce      sync.Once
-	initErr       error
+	defaultState atomic.Pointer[defaultInstance]
+	initOnce     sync.Once
 )
 
-// ensureInitialized lazily initializes the default pricer from embedded configs.
-func ensureInitialized() {
+// defaultInstance pairs the package-level pricer with the error from loading it.
+type defaultInstance struct {
+	pricer *Pricer
+	err    error
+}
+
+// ensureInitialized lazily initializes the default pricer from embedded configs,
+// unless one was already injected via SetDefaultPricer.
+func ensureInitialized() *defaultInstance {
 	initOnce.Do(func() {
-		defaultPricer, initErr = NewPricer()
-		if initErr != nil {
+		if defaultState.Load() != nil {
+			return
+		}
+		p, err := NewPricer()
+		if err != nil {
 			// Create empty pricer for graceful degradation.
 			// Callers should check InitError() to detect this condition.
-			defaultPricer = &Pricer{
-				models:               make(map[string]ModelPricing),
-				modelKeysSorted:      []string{},
-				imageModels:          make(map[string]ImageModelPricing),
-				imageModelKeysSorted: []string{},
-				grounding:            make(map[string]GroundingPricing),
-				groundingKeys:        []string{},
-				credits:              make(map[string]*CreditPricing),
-				providers:            make(map[string]ProviderPricing),
-			}
+			p = buildPricer(&catalog{
+				models:         make(map[string]ModelPricing),
+				modelProviders: make(map[string]string),
+				imageModels:    make(map[string]ImageModelPricing),
+				grounding:      make(map[string]GroundingPricing),
+				credits:        make(map[string]*CreditPricing),
+				rerankModels:   make(map[string]RerankPricing),
+				instances:      make(map[string]InstancePricing),
+				providers:      make(map[string]ProviderPricing),
+			})
 		}
+		// A concurrent SetDefaultPricer wins over the embedded load.
+		defaultState.CompareAndSwap(nil, &defaultInstance{pricer: p, err: err})
 	})
+	return defaultState.Load()
+}
+
+// defaultPricer returns the current package-level pricer, initializing it if needed.
+func defaultPricer() *Pricer {
+	return ensureInitialized().pricer
+}
+
+// Init eagerly loads the package-level pricer so load failures surface at startup
+// rather than on first use. It returns the initialization error, or ctx.Err() if
+// ctx is done before loading finishes (loading then continues in the background).
+// Init is a no-op returning nil if a pricer was set with SetDefaultPricer.
+func Init(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	done := make(chan *defaultInstance, 1)
+	go func() { done <- ensureInitialized() }()
+	select {
+	case inst := <-done:
+		return inst.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// SetDefaultPricer replaces the pricer used by the package-level functions, e.g.
+// with one built from custom configs or overrides. If called before first use, the
+// embedded configs are never loaded. InitError returns nil afterwards.
+// It panics if p is nil.
+func SetDefaultPricer(p *Pricer) {
+	if p == nil {
+		panic("pricing_db: SetDefaultPricer called with nil Pricer")
+	}
+	defaultState.Store(&defaultInstance{pricer: p})
 }
 
 // CalculateCost calculates the USD cost for a token-based completion.
 // Returns 0 for unknown models (graceful degradation).
 // This is a convenience function using the package-level pricer.
 func CalculateCost(model string, inputTokens, outputTokens int) float64 {
-	ensureInitialized()
-	cost := defaultPricer.Calculate(model, int64(inputTokens), int64(outputTokens))
+	cost := defaultPricer().Calculate(model, int64(inputTokens), int64(outputTokens))
 	return cost.TotalCost
 }
 
@@ -47,83 +95,195 @@ func CalculateCost(model string, inputTokens, outputTokens int) float64 {
 // Returns 0 for unknown models.
 // This is a convenience function using the package-level pricer.
 func CalculateGroundingCost(model string, queryCount int) float64 {
-	ensureInitialized()
-	return defaultPricer.CalculateGrounding(model, queryCount)
+	return defaultPricer().CalculateGrounding(model, queryCount)
+}
+
+// CalculateSearchCost calculates the USD cost of live web search billed per source
+// (e.g., xAI Live Search). Returns 0 for providers without a search surcharge.
+// This is a convenience function using the package-level pricer.
+func CalculateSearchCost(provider string, sources int) float64 {
+	cost, _ := defaultPricer().CalculateSearch(provider, sources)
+	return cost
+}
+
+// CalculateSurchargeCost calculates the USD cost of units of a model's named
+// surcharge (e.g. SurchargeSearch, SurchargeWebSearch). Returns 0 if the model
+// is unknown or has no surcharge with that name.
+// This is a convenience function using the package-level pricer.
+func CalculateSurchargeCost(model, name string, units int64) float64 {
+	cost, _ := defaultPricer().CalculateSurcharge(model, name, units)
+	return cost
 }
 
 // CalculateCreditCost calculates the credit cost for a credit-based provider request.
 // Returns 0 for unknown providers.
 // This is a convenience function using the package-level pricer.
 func CalculateCreditCost(provider, multiplier string) int {
-	ensureInitialized()
-	return defaultPricer.CalculateCredit(provider, multiplier)
+	return defaultPricer().CalculateCredit(provider, multiplier)
+}
+
+// CreditCostUSD converts the credit cost of a request into USD at a subscription tier's
+// effective price per credit. Returns 0 for unknown providers or tiers.
+// This is a convenience function using the package-level pricer.
+func CreditCostUSD(provider, multiplier, tier string) float64 {
+	cost, _ := defaultPricer().CreditCostUSD(provider, multiplier, tier)
+	return cost
 }
 
 // CalculateImageCost calculates the USD cost for image generation.
 // Returns (cost, true) if the model is found, (0, false) if unknown.
 // This is a convenience function using the package-level pricer.
 func CalculateImageCost(model string, imageCount int) (float64, bool) {
-	ensureInitialized()
-	return defaultPricer.CalculateImage(model, imageCount)
+	return defaultPricer().CalculateImage(model, imageCount)
+}
+
+// CalculateImageCostWithOptions calculates the USD cost for image generation using
+// the request's width, height, and quality to select a resolution tier.
+// Returns (cost, true) if the model is found, (0, false) if unknown.
+// This is a convenience function using the package-level pricer.
+func CalculateImageCostWithOptions(model string, opts ImageOptions) (float64, bool) {
+	return defaultPricer().CalculateImageWithOptions(model, opts)
 }
 
 // GetImagePricing returns the pricing for an image model, if known.
 // This is a convenience function using the package-level pricer.
 func GetImagePricing(model string) (ImageModelPricing, bool) {
-	ensureInitialized()
-	return defaultPricer.GetImagePricing(model)
+	return defaultPricer().GetImagePricing(model)
+}
+
+// GetGroundingPricing returns the grounding pricing that applies to a model, if any.
+// This is a convenience function using the package-level pricer.
+func GetGroundingPricing(model string) (GroundingPricing, bool) {
+	return defaultPricer().GetGroundingPricing(model)
+}
+
+// ListGroundingPrefixes returns the model prefixes with grounding pricing.
+// This is a convenience function using the package-level pricer.
+func ListGroundingPrefixes() []string {
+	return defaultPricer().ListGroundingPrefixes()
+}
+
+// GetCreditPricing returns the credit scheme of a credit-based provider, if any.
+// This is a convenience function using the package-level pricer.
+func GetCreditPricing(provider string) (*CreditPricing, bool) {
+	return defaultPricer().GetCreditPricing(provider)
+}
+
+// ListCreditProviders returns the credit-based providers.
+// This is a convenience function using the package-level pricer.
+func ListCreditProviders() []string {
+	return defaultPricer().ListCreditProviders()
+}
+
+// ListImageModels returns every provider's image models, sorted by model name.
+// This is a convenience function using the package-level pricer.
+func ListImageModels() []ImageModelInfo {
+	return defaultPricer().ListImageModels()
+}
+
+// ListImageModelsByProvider returns the image models of each provider, keyed by provider.
+// This is a convenience function using the package-level pricer.
+func ListImageModelsByProvider() map[string][]ImageModelInfo {
+	return defaultPricer().ListImageModelsByProvider()
 }
 
 // GetPricing returns the pricing for a model, if known.
 // This is a convenience function using the package-level pricer.
 func GetPricing(model string) (ModelPricing, bool) {
-	ensureInitialized()
-	return defaultPricer.GetPricing(model)
+	return defaultPricer().GetPricing(model)
+}
+
+// GetModelInfo returns pricing and descriptive metadata for a model, if known.
+// This is a convenience function using the package-level pricer.
+func GetModelInfo(model string) (ModelInfo, bool) {
+	return defaultPricer().GetModelInfo(model)
+}
+
+// SearchModels returns models matching the filter, cheapest input price first.
+// This is a convenience function using the package-level pricer.
+func SearchModels(filter ModelFilter) []ModelInfo {
+	return defaultPricer().SearchModels(filter)
+}
+
+// SuggestAlternatives returns models that would price usage below model, cheapest first.
+// This is a convenience function using the package-level pricer.
+func SuggestAlternatives(model string, usage TokenUsage) []Alternative {
+	return defaultPricer().SuggestAlternatives(model, usage)
+}
+
+// NearMatches returns up to n known model names closest to an unknown model.
+// This is a convenience function using the package-level pricer.
+func NearMatches(model string, n int) []string {
+	return defaultPricer().NearMatches(model, n)
+}
+
+// VerifyInvariants checks the loaded catalog's cross-cutting invariants and returns the violations.
+// This is a convenience function using the package-level pricer.
+func VerifyInvariants() []InvariantViolation {
+	return defaultPricer().VerifyInvariants()
+}
+
+// ImageNearMatches returns up to n known image model names closest to an unknown one.
+// This is a convenience function using the package-level pricer.
+func ImageNearMatches(model string, n int) []string {
+	return defaultPricer().ImageNearMatches(model, n)
+}
+
+// CostPerThousandTokens returns a model's standard-tier prices per 1,000 tokens.
+// This is a convenience function using the package-level pricer.
+func CostPerThousandTokens(model string) (UnitRates, bool) {
+	return defaultPricer().CostPerThousandTokens(model)
+}
+
+// EffectiveBlendedRate returns what usage costs on a model per 1,000 tokens.
+// This is a convenience function using the package-level pricer.
+func EffectiveBlendedRate(model string, usage TokenUsage, opts *CalculateOptions) (float64, bool) {
+	return defaultPricer().EffectiveBlendedRate(model, usage, opts)
 }
 
 // ListProviders returns all loaded provider names.
 // This is a convenience function using the package-level pricer.
 func ListProviders() []string {
-	ensureInitialized()
-	return defaultPricer.ListProviders()
+	return defaultPricer().ListProviders()
 }
 
 // ModelCount returns the total number of models loaded.
 // This is a convenience function using the package-level pricer.
 func ModelCount() int {
-	ensureInitialized()
-	return defaultPricer.ModelCount()
+	return defaultPricer().ModelCount()
 }
 
 // ProviderCount returns the number of providers loaded.
 // This is a convenience function using the package-level pricer.
 func ProviderCount() int {
-	ensureInitialized()
-	return defaultPricer.ProviderCount()
+	return defaultPricer().ProviderCount()
+}
+
+// CatalogVersion returns the hash identifying the loaded pricing data.
+// This is a convenience function using the package-level pricer; see Pricer.Version.
+func CatalogVersion() string {
+	return defaultPricer().Version()
 }
 
 // DefaultPricer returns the package-level pricer instance.
 // Useful when you need the full Pricer API but don't want to manage initialization.
 func DefaultPricer() *Pricer {
-	ensureInitialized()
-	return defaultPricer
+	return defaultPricer()
 }
 
 // InitError returns any error that occurred during initialization
 // of the default pricer. Returns nil if initialization succeeded.
 // Call this to check if the package-level functions are working correctly.
 func InitError() error {
-	ensureInitialized()
-	return initErr
+	return ensureInitialized().err
 }
 
 // MustInit ensures the default pricer is initialized successfully.
 // It panics if initialization fails.
 // Useful for applications that cannot function without pricing data.
 func MustInit() {
-	ensureInitialized()
-	if initErr != nil {
-		panic(fmt.Sprintf("pricing_db: initialization failed: %v", initErr))
+	if err := ensureInitialized().err; err != nil {
+		panic(fmt.Sprintf("pricing_db: initialization failed: %v", err))
 	}
 }
 
@@ -131,31 +291,54 @@ func MustInit() {
 // This handles cached tokens, thinking tokens, tool use tokens, and grounding queries.
 // This is a convenience function using the package-level pricer.
 func CalculateGeminiCost(model string, metadata GeminiUsageMetadata, groundingQueries int) CostDetails {
-	ensureInitialized()
-	return defaultPricer.CalculateGeminiUsage(model, metadata, groundingQueries, nil)
+	return defaultPricer().CalculateGeminiUsage(model, metadata, groundingQueries, nil)
 }
 
 // CalculateGeminiCostWithOptions calculates the detailed cost for Gemini models with options.
 // Use opts.BatchMode = true to apply batch discount.
 // This is a convenience function using the package-level pricer.
 func CalculateGeminiCostWithOptions(model string, metadata GeminiUsageMetadata, groundingQueries int, opts *CalculateOptions) CostDetails {
-	ensureInitialized()
-	return defaultPricer.CalculateGeminiUsage(model, metadata, groundingQueries, opts)
+	return defaultPricer().CalculateGeminiUsage(model, metadata, groundingQueries, opts)
 }
 
 // CalculateCostWithOptions calculates cost for any model with batch/cache support.
 // This is a convenience function using the package-level pricer.
 func CalculateCostWithOptions(model string, inputTokens, outputTokens, cachedTokens int64, opts *CalculateOptions) CostDetails {
-	ensureInitialized()
-	return defaultPricer.CalculateWithOptions(model, inputTokens, outputTokens, cachedTokens, opts)
+	return defaultPricer().CalculateWithOptions(model, inputTokens, outputTokens, cachedTokens, opts)
+}
+
+// CalculateUsageCost calculates the detailed cost for a provider-neutral TokenUsage,
+// including multimodal image input tokens and per-image fees.
+// This is a convenience function using the package-level pricer.
+func CalculateUsageCost(model string, usage TokenUsage, opts *CalculateOptions) CostDetails {
+	return defaultPricer().CalculateUsage(model, usage, opts)
+}
+
+// CalculateRealtimeCost calculates the cost of a realtime (audio streaming) session.
+// This is a convenience function using the package-level pricer.
+func CalculateRealtimeCost(model string, usage RealtimeUsage) CostDetails {
+	return defaultPricer().CalculateRealtimeSession(model, usage)
+}
+
+// CalculateGeminiLiveCost calculates the cost of a Gemini Live session from its
+// server messages. See ParseGeminiLiveUsage for the accepted input.
+// This is a convenience function using the package-level pricer.
+func CalculateGeminiLiveCost(model string, data []byte) (CostDetails, error) {
+	return defaultPricer().CalculateGeminiLiveSession(model, data)
+}
+
+// EstimateCostWithReasoning computes a pre-flight cost estimate including the
+// expected thinking tokens for the given reasoning effort.
+// This is a convenience function using the package-level pricer.
+func EstimateCostWithReasoning(model string, inputTokens, outputTokens int64, effort ReasoningEffort, opts *CalculateOptions) CostDetails {
+	return defaultPricer().EstimateCostWithReasoning(model, inputTokens, outputTokens, effort, opts)
 }
 
 // CalculateBatchCost calculates cost in batch mode for any model.
 // Convenience wrapper that sets BatchMode=true.
 // This is a convenience function using the package-level pricer.
 func CalculateBatchCost(model string, inputTokens, outputTokens, cachedTokens int64) CostDetails {
-	ensureInitialized()
-	return defaultPricer.CalculateWithOptions(model, inputTokens, outputTokens, cachedTokens, &CalculateOptions{BatchMode: true})
+	return defaultPricer().CalculateWithOptions(model, inputTokens, outputTokens, cachedTokens, &CalculateOptions{BatchMode: true})
 }
 
 // ParseGeminiResponse parses a full Gemini API JSON response and calculates the cost.
@@ -184,6 +367,89 @@ func ParseGeminiResponseWithOptions(jsonData []byte, opts *CalculateOptions) (Co
 	return CalculateGeminiResponseCost(resp, opts), nil
 }
 
+// ParseGeminiResponseTolerant is ParseGeminiResponseWithOptions for truncated or
+// concatenated responses: when the document is malformed, the cost is recovered
+// from its last complete usageMetadata block with a WarningMalformedResponse
+// warning. See Pricer.CalculateResponseTolerant.
+// This is a convenience function using the package-level pricer.
+func ParseGeminiResponseTolerant(jsonData []byte, opts *CalculateOptions) (CostDetails, error) {
+	return defaultPricer().CalculateResponseTolerant("gemini", jsonData, "", opts)
+}
+
+// ParseOpenAIResponseTolerant parses an OpenAI chat completion response, which
+// may be truncated or concatenated, and calculates its cost from the response's
+// model and last complete usage block. See Pricer.CalculateResponseTolerant.
+// This is a convenience function using the package-level pricer.
+func ParseOpenAIResponseTolerant(jsonData []byte) (CostDetails, error) {
+	return defaultPricer().CalculateResponseTolerant("openai", jsonData, "", nil)
+}
+
+// ParseMulti prices back-to-back JSON responses, one CostDetails per document;
+// see Pricer.ParseMulti.
+// This is a convenience function using the package-level pricer.
+func ParseMulti(jsonData []byte) ([]CostDetails, error) {
+	return defaultPricer().ParseMulti(jsonData)
+}
+
+// CalculateResponseCost prices a provider's full JSON response; see Pricer.CalculateResponse.
+// This is a convenience function using the package-level pricer.
+func CalculateResponseCost(provider string, jsonData []byte, modelOverride string, opts *CalculateOptions) (CostDetails, error) {
+	return defaultPricer().CalculateResponse(provider, jsonData, modelOverride, opts)
+}
+
+// ParseMistralResponse parses a Mistral chat completion response and calculates
+// its cost from the response's model and usage.
+// See ParseGeminiResponse for error handling semantics.
+// This is a convenience function using the package-level pricer.
+func ParseMistralResponse(jsonData []byte) (CostDetails, error) {
+	return defaultPricer().CalculateResponse("mistral", jsonData, "", nil)
+}
+
+// ParseCohereResponse parses a Cohere chat response (v1 or v2) and calculates its
+// cost from the billed units. Cohere responses do not name the model, so it must
+// be passed in. See ParseGeminiResponse for error handling semantics.
+// This is a convenience function using the package-level pricer.
+func ParseCohereResponse(jsonData []byte, model string) (CostDetails, error) {
+	return defaultPricer().CalculateResponse("cohere", jsonData, model, nil)
+}
+
+// ParseOpenAIResponsesAPI parses an OpenAI Responses API response (object
+// "response") and calculates its cost from the response's model and usage,
+// including built-in web and file search tool calls.
+// See ParseGeminiResponse for error handling semantics.
+// This is a convenience function using the package-level pricer.
+func ParseOpenAIResponsesAPI(jsonData []byte) (CostDetails, error) {
+	return defaultPricer().CalculateResponse("openai-responses", jsonData, "", nil)
+}
+
+// ParseAnthropicResponse parses an Anthropic Messages API response and calculates
+// its cost from the response's model and usage, including web search fees.
+// See ParseGeminiResponse for error handling semantics.
+// This is a convenience function using the package-level pricer.
+func ParseAnthropicResponse(jsonData []byte) (CostDetails, error) {
+	return defaultPricer().CalculateResponse("anthropic", jsonData, "", nil)
+}
+
+// ParseAI21Response parses an AI21 (Jamba) chat completion response and calculates
+// its cost from the response's model and usage.
+// See ParseGeminiResponse for error handling semantics.
+// This is a convenience function using the package-level pricer.
+func ParseAI21Response(jsonData []byte) (CostDetails, error) {
+	return defaultPricer().CalculateResponse("ai21", jsonData, "", nil)
+}
+
+// ParseGeminiResponseCandidates parses a full Gemini API JSON response and returns
+// its cost broken down per candidate. See CalculateGeminiResponseCandidates for how
+// costs are attributed and ParseGeminiResponse for error handling semantics.
+// This is a convenience function using the package-level pricer.
+func ParseGeminiResponseCandidates(jsonData []byte, opts *CalculateOptions) (GeminiResponseCost, error) {
+	var resp GeminiResponse
+	if err := json.Unmarshal(jsonData, &resp); err != nil {
+		return GeminiResponseCost{}, fmt.Errorf("parse gemini response: %w", err)
+	}
+	return defaultPricer().CalculateGeminiResponseCandidates(resp, "", opts), nil
+}
+
 // CalculateGeminiResponseCost calculates cost from a parsed GeminiResponse struct.
 // It counts non-empty webSearchQueries across all candidates for grounding billing.
 // Uses modelVersion from the response. For model override, use CalculateGeminiResponseCostWithModel.
@@ -195,25 +461,57 @@ func CalculateGeminiResponseCost(resp GeminiResponse, opts *CalculateOptions) Co
 // If modelOverride is non-empty, it is used instead of resp.ModelVersion.
 // This is useful when the response doesn't include modelVersion or you want to use a different model.
 func CalculateGeminiResponseCostWithModel(resp GeminiResponse, modelOverride string, opts *CalculateOptions) CostDetails {
-	ensureInitialized()
-
-	// Count non-empty web search queries across all candidates
-	groundingQueries := 0
-	for _, candidate := range resp.Candidates {
-		if candidate.GroundingMetadata != nil {
-			for _, query := range candidate.GroundingMetadata.WebSearchQueries {
-				if query != "" {
-					groundingQueries++
-				}
-			}
-		}
-	}
+	return defaultPricer().CalculateGeminiResponse(resp, modelOverride, opts)
+}
 
-	// Use modelOverride if provided, otherwise use response's modelVersion
-	model := resp.ModelVersion
-	if modelOverride != "" {
-		model = modelOverride
-	}
+// CalculateEndpointCost calculates the USD cost of running a dedicated inference
+// instance (e.g., a Hugging Face Inference Endpoint) for the given hours.
+// Returns 0 for unknown instance types.
+// This is a convenience function using the package-level pricer.
+func CalculateEndpointCost(instanceType string, hours float64) float64 {
+	cost, _ := defaultPricer().CalculateEndpointHours(instanceType, hours)
+	return cost
+}
 
-	return defaultPricer.CalculateGeminiUsage(model, resp.UsageMetadata, groundingQueries, opts)
+// CalculateReplicateRunCost calculates the USD cost of a Replicate prediction
+// billed per second of hardware time; hardware may be empty to use the model's
+// default. Returns 0 for unknown hardware.
+// This is a convenience function using the package-level pricer.
+func CalculateReplicateRunCost(model string, seconds float64, hardware string) float64 {
+	cost, _ := defaultPricer().CalculateReplicateRun(model, seconds, hardware)
+	return cost
+}
+
+// CalculateRerankCost calculates the USD cost for rerank searches.
+// Returns 0 for unknown models.
+// This is a convenience function using the package-level pricer.
+func CalculateRerankCost(model string, searches int) float64 {
+	cost, _ := defaultPricer().CalculateRerank(model, searches)
+	return cost
+}
+
+// PriceStream prices a stream of JSON records (e.g., an NDJSON archive),
+// calling fn with the cost of each record in order.
+// This is a convenience function using the package-level pricer.
+func PriceStream(r io.Reader, format Format, fn func(CostDetails) error) error {
+	return defaultPricer().PriceStream(r, format, fn)
+}
+
+// CalculateJSON prices a JSON-encoded JSONRequest and returns a JSON-encoded JSONResult.
+// This is a convenience function using the package-level pricer.
+func CalculateJSON(request []byte) []byte {
+	return defaultPricer().CalculateJSON(request)
+}
+
+// EstimateTextCost estimates the cost of sending prompt to model and receiving
+// outputTokens tokens, counting prompt tokens with ApproxTokenCount.
+// This is a convenience function using the package-level pricer.
+func EstimateTextCost(model, prompt string, outputTokens int64, opts *CalculateOptions) CostDetails {
+	return defaultPricer().EstimateTextCost(model, prompt, outputTokens, opts)
+}
+
+// CountTokens estimates the tokens in text for model with ApproxTokenCount.
+// This is a convenience function using the package-level pricer.
+func CountTokens(model, text string) int64 {
+	return defaultPricer().CountTokens(model, text)
 }
@@ -1,6 +1,7 @@
 package pricing_db
 
 import (
+	"slices"
 	"strings"
 	"testing"
 	"testing/fstest"
@@ -146,6 +147,95 @@ func TestCalculateImage_PrefixMatch(t *testing.T) {
 	}
 }
 
+func TestCalculateImage_ExactMatchKeys(t *testing.T) {
+	fsys := fstest.MapFS{
+		"configs/test_pricing.json": &fstest.MapFile{Data: []byte(`{
+			"provider": "test",
+			"image_models": {
+				"img": {"price_per_image": 0.04},
+				"img-1024-hd": {"price_per_image": 0.08, "exact_match": true}
+			}
+		}`)},
+	}
+	p, err := NewPricerFromFS(fsys, "configs")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if cost, found := p.CalculateImage("img-1024-hd", 1); !found || !floatEquals(cost, 0.08) {
+		t.Errorf("expected exact key to price at 0.08, got %f (found=%v)", cost, found)
+	}
+	// Prefix matching skips the exact_match key and uses the family entry
+	if cost, found := p.CalculateImage("img-1024-hd-2025", 1); !found || !floatEquals(cost, 0.04) {
+		t.Errorf("expected img-1024-hd-2025 to price as img at 0.04, got %f (found=%v)", cost, found)
+	}
+}
+
+func TestCalculateImage_DefaultResolution(t *testing.T) {
+	p, err := NewPricer()
+	if err != nil {
+		t.Fatalf("NewPricer failed: %v", err)
+	}
+
+	tests := []struct {
+		model    string
+		expected float64
+	}{
+		{"nano-banana", 0.039},
+		{"nano-banana-pro", 0.134},
+		// Prefix matches reach the family default, not a sibling family
+		{"nano-banana-pro-preview", 0.134},
+		{"google/nano-banana-pro", 0.134},
+	}
+	for _, tc := range tests {
+		t.Run(tc.model, func(t *testing.T) {
+			cost, found := p.CalculateImage(tc.model, 1)
+			if !found {
+				t.Fatalf("expected %s to resolve", tc.model)
+			}
+			if !floatEquals(cost, tc.expected) {
+				t.Errorf("expected cost %f, got %f", tc.expected, cost)
+			}
+		})
+	}
+
+	pricing, ok := p.GetImagePricing("nano-banana-pro")
+	if !ok {
+		t.Fatal("expected GetImagePricing to find nano-banana-pro")
+	}
+	if pricing.DefaultResolution != "nano-banana-pro-1k" {
+		t.Errorf("expected DefaultResolution nano-banana-pro-1k, got %q", pricing.DefaultResolution)
+	}
+}
+
+func TestImageNearMatches(t *testing.T) {
+	fsys := fstest.MapFS{
+		"configs/test_pricing.json": &fstest.MapFile{Data: []byte(`{
+			"provider": "test",
+			"image_models": {
+				"render-pro-1k": {"price_per_image": 0.04, "exact_match": true},
+				"render-pro-2k": {"price_per_image": 0.08, "exact_match": true}
+			}
+		}`)},
+	}
+	p, err := NewPricerFromFS(fsys, "configs")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// An ambiguous family name is not found, with its resolutions as suggestions
+	if _, found := p.CalculateImage("render-pro", 1); found {
+		t.Error("expected render-pro to be not found")
+	}
+	got := p.ImageNearMatches("render-pro", 3)
+	if want := []string{"render-pro-1k", "render-pro-2k"}; !slices.Equal(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+	if got := p.ImageNearMatches("render-pro-1k", 3); got != nil {
+		t.Errorf("expected nil for a known image model, got %v", got)
+	}
+}
+
 func TestImagePricing_ProviderNamespacing(t *testing.T) {
 	p, err := NewPricer()
 	if err != nil {
@@ -196,6 +286,39 @@ func TestImagePricing_Validation(t *testing.T) {
 			}`,
 			errContains: "suspiciously high",
 		},
+		{
+			name: "default resolution with own price",
+			json: `{
+				"provider": "test",
+				"image_models": {
+					"img": {"price_per_image": 0.05, "default_resolution": "img-1k"},
+					"img-1k": {"price_per_image": 0.04}
+				}
+			}`,
+			errContains: "sets default_resolution and its own prices",
+		},
+		{
+			name: "default resolution to unknown model",
+			json: `{
+				"provider": "test",
+				"image_models": {
+					"img": {"default_resolution": "img-8k"}
+				}
+			}`,
+			errContains: "is not an image model of this provider",
+		},
+		{
+			name: "chained default resolution",
+			json: `{
+				"provider": "test",
+				"image_models": {
+					"img": {"default_resolution": "img-hd"},
+					"img-hd": {"default_resolution": "img-1k"},
+					"img-1k": {"price_per_image": 0.04}
+				}
+			}`,
+			errContains: "itself sets default_resolution",
+		},
 	}
 
 	for _, tc := range tests {
@@ -289,3 +412,244 @@ func TestImageModels_AllProviders(t *testing.T) {
 		})
 	}
 }
+
+func TestCalculateImageWithOptions_DallE3(t *testing.T) {
+	p, err := NewPricer()
+	if err != nil {
+		t.Fatalf("NewPricer failed: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		opts     ImageOptions
+		expected float64
+	}{
+		{"square standard", ImageOptions{Width: 1024, Height: 1024, Quality: "standard", Count: 1}, 0.04},
+		{"square hd", ImageOptions{Width: 1024, Height: 1024, Quality: "hd", Count: 2}, 0.16},
+		{"portrait standard", ImageOptions{Width: 1024, Height: 1792, Count: 1}, 0.08},
+		{"landscape hd", ImageOptions{Width: 1792, Height: 1024, Quality: "HD", Count: 1}, 0.12},
+		{"default quality", ImageOptions{Width: 1024, Height: 1024, Count: 3}, 0.12},
+		{"no size uses base price", ImageOptions{Count: 1}, 0.04},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			cost, found := p.CalculateImageWithOptions("dall-e-3", tc.opts)
+			if !found {
+				t.Fatal("expected dall-e-3 to be found")
+			}
+			if !floatEquals(cost, tc.expected) {
+				t.Errorf("expected cost %f, got %f", tc.expected, cost)
+			}
+		})
+	}
+}
+
+func TestCalculateImageWithOptions_NonExactSize(t *testing.T) {
+	p, err := NewPricer()
+	if err != nil {
+		t.Fatalf("NewPricer failed: %v", err)
+	}
+
+	// 300x300 rounds up to the 512x512 tier
+	cost, _ := p.CalculateImageWithOptions("dall-e-2", ImageOptions{Width: 300, Height: 300, Count: 1})
+	if !floatEquals(cost, 0.018) {
+		t.Errorf("expected 512x512 tier price 0.018, got %f", cost)
+	}
+
+	// Larger than every tier uses the largest tier
+	cost, _ = p.CalculateImageWithOptions("dall-e-2", ImageOptions{Width: 2048, Height: 2048, Count: 1})
+	if !floatEquals(cost, 0.02) {
+		t.Errorf("expected largest tier price 0.02, got %f", cost)
+	}
+}
+
+func TestCalculateImageWithOptions_NoResolutionsUsesBasePrice(t *testing.T) {
+	p, err := NewPricer()
+	if err != nil {
+		t.Fatalf("NewPricer failed: %v", err)
+	}
+
+	cost, found := p.CalculateImageWithOptions("nano-banana-1k", ImageOptions{Width: 1024, Height: 1024, Quality: "hd", Count: 10})
+	if !found {
+		t.Fatal("expected nano-banana-1k to be found")
+	}
+	if !floatEquals(cost, 0.39) {
+		t.Errorf("expected base price cost 0.39, got %f", cost)
+	}
+
+	cost, found = p.CalculateImageWithOptions("unknown-image-model", ImageOptions{Count: 1})
+	if found || cost != 0 {
+		t.Errorf("expected (0, false) for unknown model, got (%f, %v)", cost, found)
+	}
+}
+
+func TestCalculateImageWithOptions_PerMegapixel(t *testing.T) {
+	fsys := fstest.MapFS{
+		"configs/test_pricing.json": &fstest.MapFile{Data: []byte(`{
+			"provider": "test",
+			"image_models": {
+				"mp-model": {"mode": "per_megapixel", "price_per_megapixel": 0.04}
+			}
+		}`)},
+	}
+	p, err := NewPricerFromFS(fsys, "configs")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 2000x1000 = 2 MP * $0.04 * 3 images
+	cost, found := p.CalculateImageWithOptions("mp-model", ImageOptions{Width: 2000, Height: 1000, Count: 3})
+	if !found {
+		t.Fatal("expected to find mp-model")
+	}
+	if !floatEquals(cost, 0.24) {
+		t.Errorf("expected cost 0.24, got %f", cost)
+	}
+
+	// Without a size, 1024x1024 = 1.048576 MP
+	cost, _ = p.CalculateImage("mp-model", 1)
+	if !floatEquals(cost, 1.048576*0.04) {
+		t.Errorf("expected cost %f at 1024x1024, got %f", 1.048576*0.04, cost)
+	}
+}
+
+func TestCalculateImageWithOptions_PerStep(t *testing.T) {
+	p, err := NewPricer()
+	if err != nil {
+		t.Fatalf("NewPricer failed: %v", err)
+	}
+	model := "accounts/fireworks/models/stable-diffusion-xl-1024-v1-0"
+
+	// Default 30 steps * $0.00013
+	cost, found := p.CalculateImage(model, 1)
+	if !found {
+		t.Fatalf("expected to find %s", model)
+	}
+	if !floatEquals(cost, 0.0039) {
+		t.Errorf("expected cost 0.0039 at default steps, got %f", cost)
+	}
+
+	cost, _ = p.CalculateImageWithOptions(model, ImageOptions{Steps: 50, Count: 2})
+	if !floatEquals(cost, 0.013) {
+		t.Errorf("expected cost 0.013 for 2 images at 50 steps, got %f", cost)
+	}
+}
+
+func TestImagePricing_ModeValidation(t *testing.T) {
+	tests := []struct {
+		name        string
+		pricing     string
+		errContains string
+	}{
+		{"unknown mode", `{"mode": "per_token", "price_per_image": 0.04}`, "unknown mode"},
+		{"megapixel rate without mode", `{"price_per_megapixel": 0.04}`, "without their mode"},
+		{"megapixel mode without rate", `{"mode": "per_megapixel", "price_per_image": 0.04}`, "must set only price_per_megapixel"},
+		{"step mode without default steps", `{"mode": "per_step", "price_per_step": 0.001}`, "must set only price_per_step and default_steps"},
+		{"negative step price", `{"mode": "per_step", "price_per_step": -0.001, "default_steps": 20}`, "negative price_per_step"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			fsys := fstest.MapFS{
+				"configs/test_pricing.json": &fstest.MapFile{Data: []byte(`{
+					"provider": "test",
+					"image_models": {"bad-model": ` + tc.pricing + `}
+				}`)},
+			}
+			_, err := NewPricerFromFS(fsys, "configs")
+			if err == nil {
+				t.Fatalf("expected error for %s", tc.name)
+			}
+			if !strings.Contains(err.Error(), tc.errContains) {
+				t.Errorf("expected error containing %q, got: %v", tc.errContains, err)
+			}
+		})
+	}
+}
+
+func TestImagePricing_ResolutionValidation(t *testing.T) {
+	fsys := fstest.MapFS{
+		"configs/test_pricing.json": &fstest.MapFile{Data: []byte(`{
+			"provider": "test",
+			"image_models": {
+				"bad-model": {
+					"price_per_image": 0.05,
+					"resolutions": [{"width": 1024, "height": 1024, "price_per_image": -1}]
+				}
+			}
+		}`)},
+	}
+	_, err := NewPricerFromFS(fsys, "configs")
+	if err == nil {
+		t.Fatal("expected error for negative resolution price")
+	}
+	if !strings.Contains(err.Error(), "resolution 0") {
+		t.Errorf("expected error to identify the resolution tier, got: %v", err)
+	}
+}
+
+// =============================================================================
+// Image Catalog Listing Tests
+// =============================================================================
+
+func TestListImageModels(t *testing.T) {
+	fsys := fstest.MapFS{
+		"configs/alpha_pricing.json": &fstest.MapFile{Data: []byte(`{
+			"provider": "alpha",
+			"image_models": {
+				"render": {"price_per_image": 0.04, "resolutions": [{"width": 1024, "height": 1024, "price_per_image": 0.05}]},
+				"sketch": {"price_per_image": 0.01}
+			}
+		}`)},
+		"configs/beta_pricing.json": &fstest.MapFile{Data: []byte(`{
+			"provider": "beta",
+			"image_models": {"render": {"price_per_image": 0.03}},
+			"models": {"chat": {"input_per_million": 1.0, "output_per_million": 2.0}}
+		}`)},
+	}
+	p, err := NewPricerFromFS(fsys, "configs")
+	if err != nil {
+		t.Fatalf("NewPricerFromFS failed: %v", err)
+	}
+
+	var got []string
+	for _, m := range p.ListImageModels() {
+		got = append(got, m.Provider+"/"+m.Model)
+	}
+	want := []string{"alpha/render", "beta/render", "alpha/sketch"}
+	if !slices.Equal(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+
+	byProvider := p.ListImageModelsByProvider()
+	if len(byProvider) != 2 {
+		t.Errorf("expected 2 providers with image models, got %d", len(byProvider))
+	}
+	alpha := byProvider["alpha"]
+	if len(alpha) != 2 || alpha[0].Model != "render" || !floatEquals(alpha[0].PricePerImage, 0.04) {
+		t.Fatalf("unexpected alpha image models: %+v", alpha)
+	}
+
+	// Listed pricing is a copy
+	alpha[0].Resolutions[0].PricePerImage = 99
+	if pricing, _ := p.GetImagePricing("alpha/render"); !floatEquals(pricing.Resolutions[0].PricePerImage, 0.05) {
+		t.Errorf("expected mutation not to affect the catalog, got %+v", pricing.Resolutions)
+	}
+}
+
+func TestListImageModels_Embedded(t *testing.T) {
+	p, err := NewPricer()
+	if err != nil {
+		t.Fatalf("NewPricer failed: %v", err)
+	}
+	models := p.ListImageModels()
+	if len(models) == 0 {
+		t.Fatal("expected embedded image models")
+	}
+	for _, m := range models {
+		if m.Provider == "" || m.Model == "" {
+			t.Errorf("incomplete entry: %+v", m)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package pricing_db
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// Invariants checked by VerifyInvariants.
+const (
+	InvariantBatchDiscount = "batch_discount" // batch_multiplier <= 1: batch is no dearer than standard
+	InvariantCacheDiscount = "cache_discount" // cache read multipliers <= 1: cached input is no dearer than standard
+	InvariantTierOrder     = "tier_order"     // each tier's rates are >= the base rates and the tier below
+	InvariantNamespacedKey = "namespaced_key" // bare and "provider/model" keys price identically to the provider's entry
+)
+
+// InvariantViolation is a loaded model that breaks one of the invariants.
+type InvariantViolation struct {
+	Model     string // Model key, bare or "provider/model"
+	Invariant string // One of the Invariant* constants
+	Message   string
+}
+
+// String formats the violation as "<model>: <invariant>: <message>".
+func (v InvariantViolation) String() string {
+	return fmt.Sprintf("%s: %s: %s", v.Model, v.Invariant, v.Message)
+}
+
+// VerifyInvariants checks cross-cutting properties of the loaded catalog that
+// single-entry validation cannot: batch and cached prices at or below standard
+// (including cache profiles and price history), tier rates rising with their
+// thresholds as context tiers do, and every model key agreeing with its
+// provider's entry. It returns the violations sorted by model key, or nil.
+// Deployments can gate on an empty result after a load or Reload.
+func (p *Pricer) VerifyInvariants() []InvariantViolation {
+	c := p.load()
+
+	var violations []InvariantViolation
+	add := func(model, invariant, format string, args ...any) {
+		violations = append(violations, InvariantViolation{model, invariant, fmt.Sprintf(format, args...)})
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(c.models)) {
+		pricing := c.models[key]
+
+		if pricing.BatchMultiplier > 1 {
+			add(key, InvariantBatchDiscount, "batch_multiplier %g raises the batch price above standard", pricing.BatchMultiplier)
+		}
+		if pricing.CacheReadMultiplier > 1 {
+			add(key, InvariantCacheDiscount, "cache_read_multiplier %g raises the cached price above standard", pricing.CacheReadMultiplier)
+		}
+		for _, name := range slices.Sorted(maps.Keys(pricing.CacheProfiles)) {
+			if m := pricing.CacheProfiles[name].ReadMultiplier; m > 1 {
+				add(key, InvariantCacheDiscount, "cache profile %q read_multiplier %g raises the cached price above standard", name, m)
+			}
+		}
+
+		checkTierOrder(key, "", pricing, pricing.InputPerMillion, pricing.OutputPerMillion, pricing.Tiers, add)
+		for _, period := range pricing.PriceHistory {
+			checkTierOrder(key, fmt.Sprintf(" (price until %s)", period.Until), pricing, period.InputPerMillion, period.OutputPerMillion, period.Tiers, add)
+		}
+
+		provider := c.modelProviders[key]
+		bare := strings.TrimPrefix(key, provider+"/")
+		entry, ok := c.providers[provider].Models[bare]
+		switch {
+		case !ok:
+			add(key, InvariantNamespacedKey, "provider %q has no model %q", provider, bare)
+		case !reflect.DeepEqual(entry, pricing):
+			add(key, InvariantNamespacedKey, "pricing differs from provider %q entry %q", provider, bare)
+		}
+		if bare == key {
+			if _, ok := c.models[provider+"/"+key]; !ok {
+				add(key, InvariantNamespacedKey, "no namespaced key %q", provider+"/"+key)
+			}
+		}
+	}
+	return violations
+}
+
+// checkTierOrder reports tiers whose rates fall below the base rates or the
+// tier below them. Tiers are sorted by threshold at load. The order matters
+// under either tier_mode: a cheaper cliff tier makes a larger request cost
+// less, and a cheaper marginal tier prices the tokens above its threshold
+// below the ones under it. Messages name the mode and what the thresholds
+// count (tier_basis).
+func checkTierOrder(key, when string, pricing ModelPricing, input, output float64, tiers []PricingTier, add func(model, invariant, format string, args ...any)) {
+	mode := cmp.Or(pricing.TierMode, TierModeCliff)
+	basis := cmp.Or(pricing.TierBasis, TierBasisInput)
+	for _, tier := range tiers {
+		if tier.InputPerMillion < input || tier.OutputPerMillion < output {
+			add(key, InvariantTierOrder, "%s tier at %d %s tokens ($%g/$%g) is cheaper than the rates below it ($%g/$%g)%s",
+				mode, tier.ThresholdTokens, basis, tier.InputPerMillion, tier.OutputPerMillion, input, output, when)
+		}
+		input, output = tier.InputPerMillion, tier.OutputPerMillion
+	}
+}
@@ -0,0 +1,84 @@
+package pricing_db
+
+import (
+	"maps"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// =============================================================================
+// Catalog Invariant Tests
+// =============================================================================
+
+func TestVerifyInvariants_EmbeddedConfigs(t *testing.T) {
+	for _, v := range VerifyInvariants() {
+		t.Errorf("unexpected violation: %s", v)
+	}
+}
+
+func TestVerifyInvariants_TierOrder(t *testing.T) {
+	fsys := fstest.MapFS{
+		"configs/test_pricing.json": &fstest.MapFile{Data: []byte(`{
+			"provider": "test",
+			"models": {
+				"ok-model": {
+					"input_per_million": 1, "output_per_million": 2,
+					"tiers": [{"threshold_tokens": 200000, "input_per_million": 2, "output_per_million": 4}]
+				},
+				"cheap-tier": {
+					"input_per_million": 1, "output_per_million": 2,
+					"tiers": [
+						{"threshold_tokens": 500000, "input_per_million": 1.5, "output_per_million": 3},
+						{"threshold_tokens": 100000, "input_per_million": 2, "output_per_million": 4}
+					]
+				}
+			}
+		}`)},
+	}
+	p, err := NewPricerFromFS(fsys, "configs")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Sorted by threshold, the 500K tier is cheaper than the 100K tier below it;
+	// reported for both the bare and the namespaced key
+	violations := p.VerifyInvariants()
+	if len(violations) != 2 {
+		t.Fatalf("expected 2 violations, got %v", violations)
+	}
+	for i, model := range []string{"cheap-tier", "test/cheap-tier"} {
+		if v := violations[i]; v.Model != model || v.Invariant != InvariantTierOrder {
+			t.Errorf("expected tier_order violation for %s, got %s", model, v)
+		}
+	}
+	if msg := violations[0].Message; !strings.Contains(msg, "cliff tier at 500000 input tokens") {
+		t.Errorf("expected the tier mode and basis in the message, got %q", msg)
+	}
+}
+
+func TestVerifyInvariants_NamespacedKey(t *testing.T) {
+	fsys := fstest.MapFS{
+		"configs/test_pricing.json": &fstest.MapFile{Data: []byte(`{
+			"provider": "test",
+			"models": {"test-model": {"input_per_million": 1, "output_per_million": 2}}
+		}`)},
+	}
+	p, err := NewPricerFromFS(fsys, "configs")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Corrupt a bare key so it no longer matches its provider's entry
+	c := *p.cat.Load()
+	c.models = maps.Clone(c.models)
+	broken := c.models["test-model"]
+	broken.InputPerMillion *= 2
+	c.models["test-model"] = broken
+	p.cat.Store(&c)
+
+	violations := p.VerifyInvariants()
+	if len(violations) != 1 || violations[0].Model != "test-model" || violations[0].Invariant != InvariantNamespacedKey {
+		t.Errorf("expected a namespaced_key violation for test-model, got %v", violations)
+	}
+}
@@ -0,0 +1,69 @@
+package pricing_db
+
+import (
+	"iter"
+	"maps"
+	"slices"
+)
+
+// Provider is a read-only view of one loaded provider, yielded by
+// Pricer.Providers. It shares the catalog it came from, so it is cheap to
+// pass around and keeps describing that catalog after a Reload.
+type Provider struct {
+	name    string
+	pricing ProviderPricing
+}
+
+// Name returns the provider name, e.g. "openai".
+func (pv Provider) Name() string {
+	return pv.name
+}
+
+// Pricing returns the provider's full pricing data as a deep copy.
+func (pv Provider) Pricing() ProviderPricing {
+	return copyProviderPricing(pv.pricing)
+}
+
+// Models yields the provider's token-priced models in name order. Each
+// ModelInfo carries the bare model name and a deep copy of its pricing.
+func (pv Provider) Models() iter.Seq[ModelInfo] {
+	return func(yield func(ModelInfo) bool) {
+		for _, model := range slices.Sorted(maps.Keys(pv.pricing.Models)) {
+			info := ModelInfo{Model: model, Provider: pv.name, ModelPricing: copyModelPricing(pv.pricing.Models[model])}
+			if !yield(info) {
+				return
+			}
+		}
+	}
+}
+
+// Providers yields the loaded providers in name order. The sequence reads the
+// catalog current when Providers is called, so a concurrent Reload does not
+// change a traversal in progress.
+func (p *Pricer) Providers() iter.Seq[Provider] {
+	c := p.load()
+	return func(yield func(Provider) bool) {
+		for _, name := range slices.Sorted(maps.Keys(c.providers)) {
+			if !yield(Provider{name: name, pricing: c.providers[name]}) {
+				return
+			}
+		}
+	}
+}
+
+// Models yields every token-priced catalog key in name order, both bare names
+// and provider-namespaced "provider/model" keys, with the provider supplying
+// its pricing and a deep copy of that pricing. Like Providers, it reads the
+// catalog current when Models is called. Only the key list is copied when a
+// traversal starts; pricing is copied as each model is yielded.
+func (p *Pricer) Models() iter.Seq[ModelInfo] {
+	c := p.load()
+	return func(yield func(ModelInfo) bool) {
+		for _, key := range slices.Sorted(maps.Keys(c.models)) {
+			info := ModelInfo{Model: key, Provider: c.modelProviders[key], ModelPricing: copyModelPricing(c.models[key])}
+			if !yield(info) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package pricing_db
+
+import (
+	"slices"
+	"sort"
+	"testing"
+)
+
+// =============================================================================
+// Iterator Tests
+// =============================================================================
+
+func TestProviders_SortedAndComplete(t *testing.T) {
+	p, err := NewPricer()
+	if err != nil {
+		t.Fatalf("NewPricer failed: %v", err)
+	}
+
+	var names []string
+	for pv := range p.Providers() {
+		names = append(names, pv.Name())
+	}
+	if !slices.Equal(names, p.ListProviders()) {
+		t.Errorf("expected providers %v, got %v", p.ListProviders(), names)
+	}
+}
+
+func TestProviders_ModelsMatchProviderPricing(t *testing.T) {
+	p, err := NewPricer()
+	if err != nil {
+		t.Fatalf("NewPricer failed: %v", err)
+	}
+
+	for pv := range p.Providers() {
+		if pv.Name() != "openai" {
+			continue
+		}
+		var models []string
+		for info := range pv.Models() {
+			if info.Provider != "openai" {
+				t.Errorf("%s: expected provider openai, got %q", info.Model, info.Provider)
+			}
+			models = append(models, info.Model)
+		}
+		if !sort.StringsAreSorted(models) {
+			t.Error("expected models in name order")
+		}
+		if len(models) != len(pv.Pricing().Models) || !slices.Contains(models, "gpt-4o") {
+			t.Errorf("expected all %d openai models including gpt-4o, got %d", len(pv.Pricing().Models), len(models))
+		}
+		return
+	}
+	t.Fatal("openai provider not yielded")
+}
+
+func TestModels_SortedWithEarlyStop(t *testing.T) {
+	p, err := NewPricer()
+	if err != nil {
+		t.Fatalf("NewPricer failed: %v", err)
+	}
+
+	var keys []string
+	for info := range p.Models() {
+		keys = append(keys, info.Model)
+	}
+	if len(keys) != p.ModelCount() {
+		t.Errorf("expected %d models, got %d", p.ModelCount(), len(keys))
+	}
+	if !sort.StringsAreSorted(keys) {
+		t.Error("expected models in name order")
+	}
+
+	n := 0
+	for range p.Models() {
+		n++
+		if n == 3 {
+			break
+		}
+	}
+	if n != 3 {
+		t.Errorf("expected iteration to stop after 3 models, got %d", n)
+	}
+}
+
+func TestModels_StableAcrossReload(t *testing.T) {
+	p, err := NewPricerFromFS(reloadFS("1.0"), "configs")
+	if err != nil {
+		t.Fatalf("NewPricerFromFS failed: %v", err)
+	}
+
+	seq := p.Models()
+	if err := p.Reload(reloadFS("2.0"), "configs"); err != nil {
+		t.Fatalf("Reload failed: %v", err)
+	}
+	for info := range seq {
+		if info.Model == "m" && !floatEquals(info.InputPerMillion, 1.0) {
+			t.Errorf("expected the sequence to keep the catalog it was created from, got input $%f", info.InputPerMillion)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package pricing_db
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+// JSONRequest is the input of CalculateJSON. Exactly one of Response or Usage is
+// priced: Response is a provider's response (or its usage object) in the format
+// named by Provider, as accepted by CalculateResponse; Usage is a TokenUsage
+// encoded with its Go field names (e.g. {"PromptTokens": 1000}).
+type JSONRequest struct {
+	Model        string          `json:"model,omitempty"`    // Required with Usage; overrides the response's model
+	Provider     string          `json:"provider,omitempty"` // Required with Response, e.g. "openai" (see UsageProviders)
+	Response     json.RawMessage `json:"response,omitempty"`
+	Usage        *TokenUsage     `json:"usage,omitempty"`
+	BatchMode    bool            `json:"batch_mode,omitempty"`
+	CacheProfile string          `json:"cache_profile,omitempty"`
+}
+
+// JSONResult is the output of CalculateJSON: the CostDetails fields, plus Error
+// when the request could not be priced.
+type JSONResult struct {
+	CostDetails
+	Error string `json:"error,omitempty"`
+}
+
+// CalculateJSON prices a JSON-encoded JSONRequest and returns a JSON-encoded
+// JSONResult. It never panics or returns a Go error, so it can be exported
+// unchanged across WebAssembly or FFI boundaries (see cmd/pricing-wasm);
+// failures are reported in the result's "error" field.
+func (p *Pricer) CalculateJSON(request []byte) []byte {
+	var result JSONResult
+	details, err := p.calculateJSONRequest(request)
+	if err != nil {
+		result.Error = err.Error()
+	} else {
+		result.CostDetails = details
+	}
+	out, err := json.Marshal(result)
+	if err != nil {
+		// CostDetails holds only plain values, so this is unreachable in practice.
+		out, _ = json.Marshal(JSONResult{Error: err.Error()})
+	}
+	return out
+}
+
+// calculateJSONRequest decodes and prices a JSONRequest.
+func (p *Pricer) calculateJSONRequest(request []byte) (CostDetails, error) {
+	var req JSONRequest
+	if err := json.Unmarshal(request, &req); err != nil {
+		return CostDetails{}, err
+	}
+
+	var opts *CalculateOptions
+	if req.BatchMode || req.CacheProfile != "" {
+		opts = &CalculateOptions{BatchMode: req.BatchMode, CacheProfile: req.CacheProfile}
+	}
+
+	switch {
+	case len(req.Response) > 0 && req.Usage != nil:
+		return CostDetails{}, errors.New("request must set only one of response and usage")
+	case len(req.Response) > 0:
+		if req.Provider == "" {
+			return CostDetails{}, errors.New("provider is required with response")
+		}
+		return p.CalculateResponse(req.Provider, req.Response, req.Model, opts)
+	case req.Usage != nil:
+		if req.Model == "" {
+			return CostDetails{}, errors.New("model is required with usage")
+		}
+		return p.CalculateUsage(req.Model, *req.Usage, opts), nil
+	default:
+		return CostDetails{}, errors.New("request must set response or usage")
+	}
+}
@@ -0,0 +1,100 @@
+package pricing_db
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// =============================================================================
+// JSON Facade Tests
+// =============================================================================
+
+func decodeJSONResult(t *testing.T, out []byte) JSONResult {
+	t.Helper()
+	var result JSONResult
+	if err := json.Unmarshal(out, &result); err != nil {
+		t.Fatalf("result is not valid JSON: %v\n%s", err, out)
+	}
+	return result
+}
+
+func TestCalculateJSON_Usage(t *testing.T) {
+	p, err := NewPricer()
+	if err != nil {
+		t.Fatalf("NewPricer failed: %v", err)
+	}
+
+	req := `{"model": "gpt-4o", "usage": {"PromptTokens": 1000000, "CompletionTokens": 1000000}, "batch_mode": true}`
+	result := decodeJSONResult(t, p.CalculateJSON([]byte(req)))
+	if result.Error != "" {
+		t.Fatalf("unexpected error: %s", result.Error)
+	}
+
+	want := p.CalculateUsage("gpt-4o", TokenUsage{PromptTokens: 1_000_000, CompletionTokens: 1_000_000}, &CalculateOptions{BatchMode: true})
+	if !floatEquals(result.TotalCost, want.TotalCost) || !result.BatchMode {
+		t.Errorf("expected batch total %f, got %f (batch=%v)", want.TotalCost, result.TotalCost, result.BatchMode)
+	}
+}
+
+func TestCalculateJSON_Response(t *testing.T) {
+	p, err := NewPricer()
+	if err != nil {
+		t.Fatalf("NewPricer failed: %v", err)
+	}
+
+	req := `{"provider": "anthropic", "response": {"model": "claude-sonnet-4-5", "usage": {"input_tokens": 1000, "output_tokens": 500}}}`
+	result := decodeJSONResult(t, p.CalculateJSON([]byte(req)))
+	if result.Error != "" {
+		t.Fatalf("unexpected error: %s", result.Error)
+	}
+
+	want, err := p.CalculateResponse("anthropic", []byte(`{"model": "claude-sonnet-4-5", "usage": {"input_tokens": 1000, "output_tokens": 500}}`), "", nil)
+	if err != nil {
+		t.Fatalf("CalculateResponse failed: %v", err)
+	}
+	if want.Unknown || !floatEquals(result.TotalCost, want.TotalCost) {
+		t.Errorf("expected total %f, got %f (unknown=%v)", want.TotalCost, result.TotalCost, want.Unknown)
+	}
+}
+
+func TestCalculateJSON_UnknownModel(t *testing.T) {
+	p, err := NewPricer()
+	if err != nil {
+		t.Fatalf("NewPricer failed: %v", err)
+	}
+
+	result := decodeJSONResult(t, p.CalculateJSON([]byte(`{"model": "no-such-model", "usage": {"PromptTokens": 10}}`)))
+	if result.Error != "" || !result.Unknown {
+		t.Errorf("expected Unknown without error, got error=%q unknown=%v", result.Error, result.Unknown)
+	}
+}
+
+func TestCalculateJSON_Errors(t *testing.T) {
+	p, err := NewPricer()
+	if err != nil {
+		t.Fatalf("NewPricer failed: %v", err)
+	}
+
+	tests := []struct {
+		name, req, wantErr string
+	}{
+		{"invalid JSON", `{`, "unexpected end"},
+		{"empty request", `{}`, "must set response or usage"},
+		{"both inputs", `{"model": "gpt-4o", "provider": "openai", "response": {}, "usage": {}}`, "only one"},
+		{"usage without model", `{"usage": {"PromptTokens": 1}}`, "model is required"},
+		{"response without provider", `{"response": {}}`, "provider is required"},
+		{"unsupported provider", `{"provider": "acme", "response": {}}`, "acme"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := decodeJSONResult(t, p.CalculateJSON([]byte(tt.req)))
+			if !strings.Contains(result.Error, tt.wantErr) {
+				t.Errorf("expected error containing %q, got %q", tt.wantErr, result.Error)
+			}
+			if result.TotalCost != 0 {
+				t.Errorf("expected zero cost on error, got %f", result.TotalCost)
+			}
+		})
+	}
+}
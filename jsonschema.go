@@ -0,0 +1,135 @@
+package pricing_db
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+// SchemaID is the $id of the schema returned by GenerateSchema.
+const SchemaID = "https://github.com/ai8future/pricing_db/schema/pricing_file.json"
+
+// GenerateSchema returns a JSON Schema (draft 2020-12) describing the
+// "*_pricing.json" config format. The schema is derived from the Go types
+// the loader decodes into, so it always matches the fields this version
+// understands. Point an editor or CI validator at it to catch typos and
+// type errors in provider files before they reach NewPricerFromFS.
+//
+// The schema checks structure only; value rules such as price ceilings
+// and tier ordering are still enforced at load time.
+func GenerateSchema() ([]byte, error) {
+	g := &schemaGenerator{defs: make(map[string]any)}
+	root := g.structSchema(reflect.TypeFor[pricingFile]())
+	root["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+	root["$id"] = SchemaID
+	root["title"] = "pricing_db provider pricing file"
+	root["$defs"] = g.defs
+	return json.MarshalIndent(root, "", "  ")
+}
+
+// schemaEnums lists the allowed values of string types with a closed set.
+var schemaEnums = map[reflect.Type][]string{
+	reflect.TypeFor[TierBasis]():        {string(TierBasisInput), string(TierBasisOutput), string(TierBasisCombined)},
+	reflect.TypeFor[TierMode]():         {string(TierModeCliff), string(TierModeMarginal)},
+	reflect.TypeFor[BatchCacheRule]():   {string(BatchCacheStack), string(BatchCachePrecedence)},
+	reflect.TypeFor[ReasoningEffort]():  {string(ReasoningLow), string(ReasoningMedium), string(ReasoningHigh)},
+	reflect.TypeFor[Modality]():         {string(ModalityText), string(ModalityImage), string(ModalityAudio), string(ModalityVideo)},
+	reflect.TypeFor[ImagePricingMode](): {string(ImagePerImage), string(ImagePerMegapixel), string(ImagePerStep)},
+}
+
+// schemaFieldOverrides adds constraints to specific fields, keyed by
+// "<Go type name>.<json name>".
+var schemaFieldOverrides = map[string]map[string]any{
+	"pricingFile.schema_version":                {"minimum": 1, "maximum": CurrentSchemaVersion},
+	"pricingFile.billing_type":                  {"enum": []string{"token", "credit", "image", "instance", "self_hosted"}},
+	"pricingFile.default_cache_read_multiplier": {"minimum": 0, "maximum": 1},
+	"GroundingPricing.billing_model":            {"enum": []string{"per_query", "per_prompt"}},
+	"CacheProfile.read_multiplier":              {"minimum": 0, "maximum": 1},
+	"CacheProfile.write_multiplier":             {"minimum": 0},
+}
+
+// schemaGenerator builds schemas for Go types, collecting named
+// struct types into $defs so they are emitted once.
+type schemaGenerator struct {
+	defs map[string]any
+}
+
+// typeSchema returns the schema for t, referencing $defs for named structs.
+func (g *schemaGenerator) typeSchema(t reflect.Type) map[string]any {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if enum, ok := schemaEnums[t]; ok {
+		return map[string]any{"type": "string", "enum": enum}
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		name := t.Name()
+		if _, done := g.defs[name]; !done {
+			g.defs[name] = nil // reserve the name before recursing
+			g.defs[name] = g.structSchema(t)
+		}
+		return map[string]any{"$ref": "#/$defs/" + name}
+	case reflect.Map:
+		s := map[string]any{
+			"type":                 "object",
+			"additionalProperties": g.typeSchema(t.Elem()),
+		}
+		if enum, ok := schemaEnums[t.Key()]; ok {
+			s["propertyNames"] = map[string]any{"enum": enum}
+		}
+		return s
+	case reflect.Slice, reflect.Array:
+		return map[string]any{"type": "array", "items": g.typeSchema(t.Elem())}
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer", "minimum": 0}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number", "minimum": 0}
+	default:
+		return map[string]any{}
+	}
+}
+
+// structSchema returns an object schema for struct t. Fields without
+// omitempty are required; unknown properties are rejected so typos in
+// field names are caught.
+func (g *schemaGenerator) structSchema(t reflect.Type) map[string]any {
+	properties := make(map[string]any)
+	required := []string{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if name == "" {
+			name = field.Name
+		}
+
+		s := g.typeSchema(field.Type)
+		for k, v := range schemaFieldOverrides[t.Name()+"."+name] {
+			s[k] = v
+		}
+		properties[name] = s
+		if !strings.Contains(opts, "omitempty") {
+			required = append(required, name)
+		}
+	}
+
+	s := map[string]any{
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+	if len(required) > 0 {
+		s["required"] = required
+	}
+	return s
+}
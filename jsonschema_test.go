@@ -0,0 +1,162 @@
+package pricing_db
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// =============================================================================
+// JSON Schema Generation Tests
+// =============================================================================
+
+func TestGenerateSchema_Structure(t *testing.T) {
+	data, err := GenerateSchema()
+	if err != nil {
+		t.Fatalf("GenerateSchema failed: %v", err)
+	}
+
+	var schema map[string]any
+	if err := json.Unmarshal(data, &schema); err != nil {
+		t.Fatalf("schema is not valid JSON: %v", err)
+	}
+	if schema["$id"] != SchemaID {
+		t.Errorf("expected $id %q, got %v", SchemaID, schema["$id"])
+	}
+
+	defs := schema["$defs"].(map[string]any)
+	model, ok := defs["ModelPricing"].(map[string]any)
+	if !ok {
+		t.Fatal("expected ModelPricing in $defs")
+	}
+	required := model["required"].([]any)
+	if !slices.Contains(required, any("input_per_million")) || slices.Contains(required, any("tiers")) {
+		t.Errorf("unexpected ModelPricing required list: %v", required)
+	}
+	rule := model["properties"].(map[string]any)["batch_cache_rule"].(map[string]any)
+	if len(rule["enum"].([]any)) != 2 {
+		t.Errorf("expected batch_cache_rule enum, got %v", rule)
+	}
+}
+
+func TestGenerateSchema_Deterministic(t *testing.T) {
+	a, _ := GenerateSchema()
+	b, _ := GenerateSchema()
+	if string(a) != string(b) {
+		t.Error("expected identical schema output across calls")
+	}
+}
+
+func TestGenerateSchema_EmbeddedConfigsValidate(t *testing.T) {
+	data, err := GenerateSchema()
+	if err != nil {
+		t.Fatalf("GenerateSchema failed: %v", err)
+	}
+	var schema map[string]any
+	if err := json.Unmarshal(data, &schema); err != nil {
+		t.Fatalf("schema is not valid JSON: %v", err)
+	}
+
+	entries, err := ConfigFS.ReadDir("configs")
+	if err != nil {
+		t.Fatalf("ReadDir failed: %v", err)
+	}
+	for _, entry := range entries {
+		raw, _ := ConfigFS.ReadFile("configs/" + entry.Name())
+		var doc any
+		if err := json.Unmarshal(raw, &doc); err != nil {
+			t.Fatalf("%s: %v", entry.Name(), err)
+		}
+		for _, problem := range checkSchema(schema, schema, doc, "$") {
+			t.Errorf("%s: %s", entry.Name(), problem)
+		}
+	}
+}
+
+func TestGenerateSchema_RejectsTypos(t *testing.T) {
+	data, _ := GenerateSchema()
+	var schema map[string]any
+	_ = json.Unmarshal(data, &schema)
+
+	var doc any
+	_ = json.Unmarshal([]byte(`{
+		"schema_version": 2,
+		"models": {"m": {"input_per_milion": 1.0, "output_per_million": 2.0, "batch_cache_rule": "stacked"}}
+	}`), &doc)
+
+	problems := strings.Join(checkSchema(schema, schema, doc, "$"), "\n")
+	for _, want := range []string{"input_per_milion", "input_per_million", "stacked"} {
+		if !strings.Contains(problems, want) {
+			t.Errorf("expected a problem mentioning %q, got:\n%s", want, problems)
+		}
+	}
+}
+
+// checkSchema is a minimal validator for the keywords GenerateSchema emits.
+func checkSchema(root, s map[string]any, v any, path string) []string {
+	if ref, ok := s["$ref"].(string); ok {
+		def := root["$defs"].(map[string]any)[strings.TrimPrefix(ref, "#/$defs/")].(map[string]any)
+		return checkSchema(root, def, v, path)
+	}
+
+	var problems []string
+	if enum, ok := s["enum"].([]any); ok && !slices.Contains(enum, v) {
+		problems = append(problems, fmt.Sprintf("%s: %v not in %v", path, v, enum))
+	}
+	if min, ok := s["minimum"].(float64); ok {
+		if n, isNum := v.(float64); isNum && n < min {
+			problems = append(problems, fmt.Sprintf("%s: %v below minimum %v", path, n, min))
+		}
+	}
+
+	switch s["type"] {
+	case "object":
+		obj, ok := v.(map[string]any)
+		if !ok {
+			return append(problems, fmt.Sprintf("%s: expected object", path))
+		}
+		props, _ := s["properties"].(map[string]any)
+		required, _ := s["required"].([]any)
+		for _, r := range required {
+			if _, ok := obj[r.(string)]; !ok {
+				problems = append(problems, fmt.Sprintf("%s: missing required %s", path, r))
+			}
+		}
+		for k, val := range obj {
+			if names, ok := s["propertyNames"].(map[string]any); ok {
+				problems = append(problems, checkSchema(root, names, k, path+"."+k)...)
+			}
+			if ps, ok := props[k].(map[string]any); ok {
+				problems = append(problems, checkSchema(root, ps, val, path+"."+k)...)
+			} else if ap, ok := s["additionalProperties"].(map[string]any); ok {
+				problems = append(problems, checkSchema(root, ap, val, path+"."+k)...)
+			} else {
+				problems = append(problems, fmt.Sprintf("%s: unknown property %s", path, k))
+			}
+		}
+	case "array":
+		arr, ok := v.([]any)
+		if !ok {
+			return append(problems, fmt.Sprintf("%s: expected array", path))
+		}
+		for i, item := range arr {
+			problems = append(problems, checkSchema(root, s["items"].(map[string]any), item, fmt.Sprintf("%s[%d]", path, i))...)
+		}
+	case "string", "boolean", "number", "integer":
+		ok := false
+		switch v.(type) {
+		case string:
+			ok = s["type"] == "string"
+		case bool:
+			ok = s["type"] == "boolean"
+		case float64:
+			ok = s["type"] == "number" || (s["type"] == "integer" && v.(float64) == float64(int64(v.(float64))))
+		}
+		if !ok {
+			problems = append(problems, fmt.Sprintf("%s: expected %s, got %v", path, s["type"], v))
+		}
+	}
+	return problems
+}
@@ -0,0 +1,36 @@
+package pricing_db
+
+import "time"
+
+// LatencyCostEstimate pairs a request's projected cost with its projected latency,
+// so routers can trade one against the other from a single data source.
+type LatencyCostEstimate struct {
+	Cost CostDetails
+	// Latency is time to first token plus generation of all output and thinking
+	// tokens at the model's output_tokens_per_second.
+	Latency time.Duration
+	// LatencyKnown is false when the model has no output_tokens_per_second,
+	// in which case Latency is zero.
+	LatencyKnown bool
+}
+
+// EstimateLatencyAndCost projects the cost (as CalculateUsage) and wall-clock
+// latency of a request. Latency uses the model's typical throughput metadata and
+// ignores queueing and network time.
+func (p *Pricer) EstimateLatencyAndCost(model string, usage TokenUsage, opts *CalculateOptions) LatencyCostEstimate {
+	c := p.load()
+	rates, _ := c.lookupRates(model)
+
+	var est LatencyCostEstimate
+	c.calculateUsageInto(&est.Cost, rates, model, usage, opts)
+	if rates == nil || rates.pricing.OutputTokensPerSecond <= 0 {
+		return est
+	}
+
+	pricing := rates.pricing
+	generated := float64(max(usage.CompletionTokens, 0) + max(usage.ThinkingTokens, 0))
+	seconds := pricing.TimeToFirstTokenMS/1000 + generated/pricing.OutputTokensPerSecond
+	est.Latency = time.Duration(seconds * float64(time.Second))
+	est.LatencyKnown = true
+	return est
+}
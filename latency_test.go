@@ -0,0 +1,79 @@
+package pricing_db
+
+import (
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+// =============================================================================
+// Latency / Cost Tradeoff Tests
+// =============================================================================
+
+func TestEstimateLatencyAndCost(t *testing.T) {
+	fsys := fstest.MapFS{
+		"configs/fast_pricing.json": &fstest.MapFile{Data: []byte(`{
+			"provider": "fast",
+			"models": {
+				"fast-model": {"input_per_million": 1.0, "output_per_million": 2.0, "output_tokens_per_second": 500, "time_to_first_token_ms": 200},
+				"plain-model": {"input_per_million": 1.0, "output_per_million": 2.0}
+			}
+		}`)},
+	}
+	p, err := NewPricerFromFS(fsys, "configs")
+	if err != nil {
+		t.Fatalf("NewPricerFromFS failed: %v", err)
+	}
+
+	usage := TokenUsage{PromptTokens: 1000, CompletionTokens: 800, ThinkingTokens: 200}
+	est := p.EstimateLatencyAndCost("fast-model", usage, nil)
+	// 200ms + 1000 tokens / 500 tok/s = 2.2s
+	if !est.LatencyKnown || est.Latency != 2200*time.Millisecond {
+		t.Errorf("expected 2.2s latency, got %v (known=%v)", est.Latency, est.LatencyKnown)
+	}
+	if want := p.CalculateUsage("fast-model", usage, nil).TotalCost; !floatEquals(est.Cost.TotalCost, want) {
+		t.Errorf("expected cost $%f to match CalculateUsage, got $%f", want, est.Cost.TotalCost)
+	}
+
+	est = p.EstimateLatencyAndCost("plain-model", usage, nil)
+	if est.LatencyKnown || est.Latency != 0 || est.Cost.TotalCost == 0 {
+		t.Errorf("expected cost without latency, got %+v", est)
+	}
+
+	est = p.EstimateLatencyAndCost("unknown-model", usage, nil)
+	if !est.Cost.Unknown || est.LatencyKnown {
+		t.Errorf("expected unknown model without latency, got %+v", est)
+	}
+}
+
+func TestEstimateLatencyAndCost_Groq(t *testing.T) {
+	p, err := NewPricer()
+	if err != nil {
+		t.Fatalf("NewPricer failed: %v", err)
+	}
+	est := p.EstimateLatencyAndCost("llama-3.1-8b-instant", TokenUsage{PromptTokens: 100, CompletionTokens: 560}, nil)
+	if !est.LatencyKnown || est.Latency != time.Second {
+		t.Errorf("expected 1s for 560 tokens at 560 tok/s, got %v", est.Latency)
+	}
+
+	fast := p.SearchModels(ModelFilter{Providers: []string{"groq"}, MinTokensPerSecond: 550})
+	for _, m := range fast {
+		if m.OutputTokensPerSecond < 550 {
+			t.Errorf("%s: %v tok/s below filter", m.Model, m.OutputTokensPerSecond)
+		}
+	}
+	if len(fast) == 0 {
+		t.Error("expected fast groq models")
+	}
+}
+
+func TestNewPricerFromFS_NegativeThroughput(t *testing.T) {
+	fsys := fstest.MapFS{
+		"configs/bad_pricing.json": &fstest.MapFile{Data: []byte(`{
+			"models": {"bad-model": {"input_per_million": 1.0, "output_per_million": 1.0, "output_tokens_per_second": -5}}
+		}`)},
+	}
+	if _, err := NewPricerFromFS(fsys, "configs"); err == nil {
+		t.Error("expected error for negative throughput")
+	}
+}
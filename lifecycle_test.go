@@ -0,0 +1,104 @@
+package pricing_db
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// =============================================================================
+// Deprecation / Sunset Warning Tests
+// =============================================================================
+
+func hasWarningCode(warnings []Warning, code WarningCode) bool {
+	for _, w := range warnings {
+		if w.Code == code {
+			return true
+		}
+	}
+	return false
+}
+
+func TestDeprecatedModel_CalculateWarns(t *testing.T) {
+	p, err := NewPricer()
+	if err != nil {
+		t.Fatalf("NewPricer failed: %v", err)
+	}
+
+	cost := p.Calculate("claude-3-opus", 1000, 500)
+	if cost.Unknown {
+		t.Fatal("expected deprecated model to still be priced")
+	}
+	if !hasWarningCode(cost.WarningDetails, WarningDeprecatedModel) {
+		t.Fatalf("expected deprecated_model warning, got %v", cost.WarningDetails)
+	}
+	msg := cost.WarningDetails[0].Message
+	if !strings.Contains(msg, "2026-01-05") || !strings.Contains(msg, "claude-opus-4-5") {
+		t.Errorf("expected sunset date and replacement in warning, got %q", msg)
+	}
+
+	current := p.Calculate("claude-opus-4-5", 1000, 500)
+	if len(current.WarningDetails) != 0 {
+		t.Errorf("expected no warnings for current model, got %v", current.WarningDetails)
+	}
+}
+
+func TestDeprecatedModel_CalculateUsageWarns(t *testing.T) {
+	p, err := NewPricer()
+	if err != nil {
+		t.Fatalf("NewPricer failed: %v", err)
+	}
+
+	details := p.CalculateGeminiUsage("gemini-1.5-pro-002", GeminiUsageMetadata{PromptTokenCount: 1000}, 0, nil)
+	if !hasWarningCode(details.WarningDetails, WarningDeprecatedModel) {
+		t.Fatalf("expected deprecated_model warning, got %v", details.WarningDetails)
+	}
+	if len(details.Warnings) != len(details.WarningDetails) {
+		t.Errorf("Warnings (%d) and WarningDetails (%d) out of sync", len(details.Warnings), len(details.WarningDetails))
+	}
+	if details.Warnings[0] != details.WarningDetails[0].Message {
+		t.Errorf("expected matching messages, got %q vs %q", details.Warnings[0], details.WarningDetails[0].Message)
+	}
+}
+
+func TestDeprecatedModel_GetModelInfoReplacement(t *testing.T) {
+	info, ok := GetModelInfo("o1-mini")
+	if !ok {
+		t.Fatal("expected o1-mini to be found")
+	}
+	if !info.Deprecated || info.Replacement != "o4-mini" || info.SunsetDate != "2025-10-27" {
+		t.Errorf("unexpected lifecycle metadata: deprecated=%v sunset=%q replacement=%q", info.Deprecated, info.SunsetDate, info.Replacement)
+	}
+}
+
+func TestStructuredWarningCodes(t *testing.T) {
+	p, err := NewPricer()
+	if err != nil {
+		t.Fatalf("NewPricer failed: %v", err)
+	}
+
+	details := p.CalculateWithOptions("gpt-4o", 100, 100, 500, nil)
+	if !hasWarningCode(details.WarningDetails, WarningCachedTokensClamped) {
+		t.Errorf("expected cached_tokens_clamped warning, got %v", details.WarningDetails)
+	}
+
+	details = p.CalculateGeminiUsage("gemini-3-pro-preview", GeminiUsageMetadata{PromptTokenCount: 100}, 2, &CalculateOptions{BatchMode: true})
+	if !hasWarningCode(details.WarningDetails, WarningBatchGroundingExcluded) {
+		t.Errorf("expected batch_grounding_excluded warning, got %v", details.WarningDetails)
+	}
+}
+
+func TestSunsetDate_Validation(t *testing.T) {
+	fsys := fstest.MapFS{
+		"configs/test_pricing.json": &fstest.MapFile{Data: []byte(`{
+			"models": {"m": {"input_per_million": 1.0, "output_per_million": 1.0, "sunset_date": "2026-13-01"}}
+		}`)},
+	}
+	_, err := NewPricerFromFS(fsys, "configs")
+	if err == nil {
+		t.Fatal("expected error for invalid sunset_date")
+	}
+	if !strings.Contains(err.Error(), "sunset_date") {
+		t.Errorf("unexpected error message: %v", err)
+	}
+}
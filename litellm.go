@@ -0,0 +1,346 @@
+package pricing_db
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// LiteLLMSourceURL is where LiteLLM publishes model_prices_and_context_window.json.
+const LiteLLMSourceURL = "https://github.com/BerriAI/litellm/blob/main/model_prices_and_context_window.json"
+
+// liteLLMProviders maps LiteLLM's litellm_provider values to provider names in
+// this catalog. The first entry for a provider is the one ExportLiteLLM writes;
+// unlisted providers keep their LiteLLM name.
+var liteLLMProviders = []struct{ liteLLM, provider string }{
+	{"openai", "openai"},
+	{"text-completion-openai", "openai"},
+	{"anthropic", "anthropic"},
+	{"gemini", "google"},
+	{"xai", "xai"},
+	{"mistral", "mistral"},
+	{"deepseek", "deepseek"},
+	{"groq", "groq"},
+	{"together_ai", "together"},
+	{"fireworks_ai", "fireworks"},
+	{"cohere_chat", "cohere"},
+	{"cohere", "cohere"},
+	{"bedrock", "bedrock"},
+	{"bedrock_converse", "bedrock"},
+	{"perplexity", "perplexity"},
+	{"cerebras", "cerebras"},
+	{"deepinfra", "deepinfra"},
+	{"ai21", "ai21"},
+	{"watsonx", "watsonx"},
+	{"databricks", "databricks"},
+	{"nebius", "nebius"},
+	{"hyperbolic", "hyperbolic"},
+	{"huggingface", "huggingface"},
+	{"minimax", "minimax"},
+}
+
+// liteLLMTokenModes are the LiteLLM modes priced per token; other modes (image
+// generation, transcription, ...) are skipped on import.
+var liteLLMTokenModes = []string{"", "chat", "completion", "responses", "embedding"}
+
+// LiteLLMImport is the result of ImportLiteLLM.
+type LiteLLMImport struct {
+	// Providers holds the imported token-priced models, by provider name, ready
+	// to be written out as *_pricing.json configs.
+	Providers map[string]ProviderPricing
+	// Skipped lists entries that were not imported, as "key: reason", in key order.
+	Skipped []string
+}
+
+// liteLLMEntry is one model of a LiteLLM price file. Fields are kept raw so
+// tier keys ("input_cost_per_token_above_200k_tokens") can be discovered and
+// entries with non-numeric values (such as sample_spec) don't fail the file.
+type liteLLMEntry map[string]json.RawMessage
+
+// num returns the numeric value of key, if it is set to a number.
+func (e liteLLMEntry) num(key string) (float64, bool) {
+	var v float64
+	if raw, ok := e[key]; !ok || json.Unmarshal(raw, &v) != nil {
+		return 0, false
+	}
+	return v, true
+}
+
+func (e liteLLMEntry) str(key string) string {
+	var s string
+	json.Unmarshal(e[key], &s)
+	return s
+}
+
+func (e liteLLMEntry) flag(key string) bool {
+	var b bool
+	json.Unmarshal(e[key], &b)
+	return b
+}
+
+// ImportLiteLLM converts LiteLLM's model_prices_and_context_window.json into
+// provider pricing, to cross-check the catalog or bootstrap missing providers.
+// Per-token costs become per-million rates; cache read, batch and cache write
+// costs become multipliers of the input rate; "above_Nk_tokens" costs become
+// tiers. Models LiteLLM lists under a "provider/" prefix are imported by their
+// bare name. When several entries map to the same model, the first key wins.
+func ImportLiteLLM(r io.Reader) (LiteLLMImport, error) {
+	var entries map[string]liteLLMEntry
+	if err := json.NewDecoder(r).Decode(&entries); err != nil {
+		return LiteLLMImport{}, fmt.Errorf("parse LiteLLM prices: %w", err)
+	}
+	providerNames := make(map[string]string, len(liteLLMProviders))
+	for _, p := range liteLLMProviders {
+		providerNames[p.liteLLM] = p.provider
+	}
+
+	out := LiteLLMImport{Providers: make(map[string]ProviderPricing)}
+	for _, key := range slices.Sorted(maps.Keys(entries)) {
+		if key == "sample_spec" {
+			continue
+		}
+		e := entries[key]
+		if !slices.Contains(liteLLMTokenModes, e.str("mode")) {
+			out.Skipped = append(out.Skipped, fmt.Sprintf("%s: mode %q is not token-priced", key, e.str("mode")))
+			continue
+		}
+		source := e.str("litellm_provider")
+		if source == "" {
+			out.Skipped = append(out.Skipped, key+": no litellm_provider")
+			continue
+		}
+		if _, ok := e.num("input_cost_per_token"); !ok {
+			out.Skipped = append(out.Skipped, key+": no input_cost_per_token")
+			continue
+		}
+		provider := cmp.Or(providerNames[source], source)
+		model := strings.TrimPrefix(key, source+"/")
+
+		pp, ok := out.Providers[provider]
+		if !ok {
+			pp = ProviderPricing{
+				Provider:    provider,
+				BillingType: "token",
+				Models:      make(map[string]ModelPricing),
+				Metadata: PricingMetadata{
+					Updated:    timeNow().UTC().Format("2006-01-02"),
+					SourceURLs: []string{LiteLLMSourceURL},
+					Notes:      []string{"Imported from LiteLLM"},
+				},
+			}
+		}
+		if _, dup := pp.Models[model]; dup {
+			out.Skipped = append(out.Skipped, fmt.Sprintf("%s: duplicates %s model %q", key, provider, model))
+			continue
+		}
+		mp := e.modelPricing()
+		if err := validateModelPricing(model, mp, "litellm"); err != nil {
+			out.Skipped = append(out.Skipped, fmt.Sprintf("%s: %v", key, err))
+			continue
+		}
+		pp.Models[model] = mp
+		out.Providers[provider] = pp
+	}
+	return out, nil
+}
+
+// modelPricing converts the entry's per-token costs to a ModelPricing.
+func (e liteLLMEntry) modelPricing() ModelPricing {
+	input, _ := e.num("input_cost_per_token")
+	output, _ := e.num("output_cost_per_token")
+	mp := ModelPricing{
+		InputPerMillion:  perMillionRate(input),
+		OutputPerMillion: perMillionRate(output),
+	}
+	if v, ok := e.num("cache_read_input_token_cost"); ok && input > 0 {
+		mp.CacheReadMultiplier = roundMultiplier(v / input)
+	}
+	if v, ok := e.num("input_cost_per_token_batches"); ok && input > 0 {
+		mp.BatchMultiplier = roundMultiplier(v / input)
+	}
+	if v, ok := e.num("cache_creation_input_token_cost"); ok && input > 0 {
+		mp.CacheProfiles = map[string]CacheProfile{"5m": {WriteMultiplier: roundMultiplier(v / input)}}
+		mp.DefaultCacheProfile = "5m"
+		if v, ok := e.num("cache_creation_input_token_cost_above_1hr"); ok {
+			mp.CacheProfiles["1h"] = CacheProfile{WriteMultiplier: roundMultiplier(v / input)}
+		}
+	}
+	if v, ok := e.num("output_cost_per_reasoning_token"); ok {
+		mp.ThinkingPerMillion = perMillionRate(v)
+	}
+	if v, ok := e.num("input_cost_per_audio_token"); ok {
+		mp.AudioInputPerMillion = perMillionRate(v)
+	}
+	if v, ok := e.num("output_cost_per_audio_token"); ok {
+		mp.AudioOutputPerMillion = perMillionRate(v)
+	}
+	if v, ok := e.num("input_cost_per_image"); ok {
+		mp.PerInputImage = v
+	}
+
+	for key := range e {
+		k, ok := strings.CutPrefix(key, "input_cost_per_token_above_")
+		if !ok {
+			continue
+		}
+		k, ok = strings.CutSuffix(k, "k_tokens")
+		thousands, err := strconv.ParseInt(k, 10, 64)
+		if !ok || err != nil {
+			continue
+		}
+		tierInput, _ := e.num(key)
+		tierOutput, ok := e.num("output_cost_per_token_above_" + k + "k_tokens")
+		if !ok {
+			tierOutput = output
+		}
+		mp.Tiers = append(mp.Tiers, PricingTier{
+			ThresholdTokens:  thousands * 1000,
+			InputPerMillion:  perMillionRate(tierInput),
+			OutputPerMillion: perMillionRate(tierOutput),
+		})
+	}
+	slices.SortFunc(mp.Tiers, func(a, b PricingTier) int { return cmp.Compare(a.ThresholdTokens, b.ThresholdTokens) })
+
+	if v, ok := e.num("max_input_tokens"); ok {
+		mp.ContextWindow = int64(v)
+	}
+	if v, ok := e.num("max_output_tokens"); ok {
+		mp.MaxOutputTokens = int64(v)
+	}
+	mp.InputModalities = []Modality{ModalityText}
+	if e.flag("supports_vision") {
+		mp.InputModalities = append(mp.InputModalities, ModalityImage)
+	}
+	if e.flag("supports_audio_input") {
+		mp.InputModalities = append(mp.InputModalities, ModalityAudio)
+	}
+	mp.OutputModalities = []Modality{ModalityText}
+	if e.flag("supports_audio_output") {
+		mp.OutputModalities = append(mp.OutputModalities, ModalityAudio)
+	}
+	mp.SunsetDate = e.str("deprecation_date")
+	return mp
+}
+
+// perMillionRate converts a per-token cost to a per-million rate, rounding
+// away float noise (2.5e-06 * 1e6 = 2.4999999999999996).
+func perMillionRate(perToken float64) float64 {
+	return math.Round(perToken*TokensPerMillion*1e9) / 1e9
+}
+
+// roundMultiplier rounds a ratio of two per-token costs to six decimals.
+func roundMultiplier(v float64) float64 {
+	return math.Round(v*1e6) / 1e6
+}
+
+// ExportLiteLLM writes the catalog's token-priced models to w in LiteLLM's
+// model_prices_and_context_window.json format, to diff against LiteLLM's file.
+// A model is keyed by its bare name when its provider owns that name in the
+// catalog, and as "litellm_provider/model" otherwise.
+func (p *Pricer) ExportLiteLLM(w io.Writer) error {
+	c := p.load()
+	liteLLMNames := make(map[string]string, len(liteLLMProviders))
+	for _, lp := range slices.Backward(liteLLMProviders) {
+		liteLLMNames[lp.provider] = lp.liteLLM
+	}
+
+	out := make(map[string]map[string]any)
+	for provider, pp := range c.providers {
+		source := cmp.Or(liteLLMNames[provider], provider)
+		for model := range pp.Models {
+			r := c.rates[provider+"/"+model]
+			if r == nil {
+				continue
+			}
+			key := model
+			if c.modelProviders[model] != provider {
+				key = source + "/" + model
+			}
+			out[key] = liteLLMModel(source, r)
+		}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "    ")
+	if err := enc.Encode(out); err != nil {
+		return fmt.Errorf("export LiteLLM prices: %w", err)
+	}
+	return nil
+}
+
+// liteLLMTiers returns the tiers LiteLLM's above_Nk_tokens keys can express:
+// those of cliff tiers selected on input, where the whole prompt pays the
+// reached tier's rate. Marginal tiers and output- or combined-basis tiers have
+// no LiteLLM equivalent and are left out rather than exported with the wrong
+// meaning.
+func liteLLMTiers(mp ModelPricing) []PricingTier {
+	if cmp.Or(mp.TierMode, TierModeCliff) != TierModeCliff || cmp.Or(mp.TierBasis, TierBasisInput) != TierBasisInput {
+		return nil
+	}
+	return mp.Tiers
+}
+
+// liteLLMModel converts compiled rates to a LiteLLM entry.
+func liteLLMModel(source string, r *modelRates) map[string]any {
+	mp := r.pricing
+	input := mp.InputPerMillion / TokensPerMillion
+	e := map[string]any{
+		"litellm_provider":            source,
+		"mode":                        "chat",
+		"input_cost_per_token":        input,
+		"output_cost_per_token":       mp.OutputPerMillion / TokensPerMillion,
+		"cache_read_input_token_cost": input * r.cacheMultiplier,
+	}
+	if r.batchMultiplier != 1 {
+		e["input_cost_per_token_batches"] = input * r.batchMultiplier
+		e["output_cost_per_token_batches"] = mp.OutputPerMillion / TokensPerMillion * r.batchMultiplier
+	}
+	if profile, ok := mp.CacheProfiles[mp.DefaultCacheProfile]; ok {
+		e["cache_creation_input_token_cost"] = input * profile.WriteMultiplier
+	}
+	if profile, ok := mp.CacheProfiles["1h"]; ok && mp.DefaultCacheProfile != "1h" {
+		e["cache_creation_input_token_cost_above_1hr"] = input * profile.WriteMultiplier
+	}
+	optional := []struct {
+		key   string
+		value float64
+	}{
+		{"output_cost_per_reasoning_token", mp.ThinkingPerMillion / TokensPerMillion},
+		{"input_cost_per_audio_token", mp.AudioInputPerMillion / TokensPerMillion},
+		{"output_cost_per_audio_token", mp.AudioOutputPerMillion / TokensPerMillion},
+		{"input_cost_per_image", mp.PerInputImage},
+		{"max_input_tokens", float64(mp.ContextWindow)},
+		{"max_output_tokens", float64(mp.MaxOutputTokens)},
+	}
+	for _, o := range optional {
+		if o.value > 0 {
+			e[o.key] = o.value
+		}
+	}
+	for _, tier := range liteLLMTiers(mp) {
+		if tier.ThresholdTokens%1000 != 0 {
+			continue // LiteLLM only names tiers in whole thousands
+		}
+		k := strconv.FormatInt(tier.ThresholdTokens/1000, 10)
+		e["input_cost_per_token_above_"+k+"k_tokens"] = tier.InputPerMillion / TokensPerMillion
+		e["output_cost_per_token_above_"+k+"k_tokens"] = tier.OutputPerMillion / TokensPerMillion
+	}
+	if slices.Contains(mp.InputModalities, ModalityImage) {
+		e["supports_vision"] = true
+	}
+	if slices.Contains(mp.InputModalities, ModalityAudio) {
+		e["supports_audio_input"] = true
+	}
+	if slices.Contains(mp.OutputModalities, ModalityAudio) {
+		e["supports_audio_output"] = true
+	}
+	if mp.SunsetDate != "" {
+		e["deprecation_date"] = mp.SunsetDate
+	}
+	return e
+}
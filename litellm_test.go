@@ -0,0 +1,167 @@
+package pricing_db
+
+import (
+	"bytes"
+	"encoding/json"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// =============================================================================
+// LiteLLM Import/Export Tests
+// =============================================================================
+
+const liteLLMSample = `{
+	"sample_spec": {"max_tokens": "LEGACY parameter", "input_cost_per_token": 0.0, "litellm_provider": "one of https://docs.litellm.ai/docs/providers"},
+	"gpt-4o": {
+		"litellm_provider": "openai", "mode": "chat",
+		"max_input_tokens": 128000, "max_output_tokens": 16384,
+		"input_cost_per_token": 2.5e-06, "output_cost_per_token": 1e-05,
+		"cache_read_input_token_cost": 1.25e-06,
+		"input_cost_per_token_batches": 1.25e-06, "output_cost_per_token_batches": 5e-06,
+		"supports_vision": true
+	},
+	"openai/gpt-4o": {"litellm_provider": "openai", "mode": "chat", "input_cost_per_token": 5e-06, "output_cost_per_token": 2e-05},
+	"gemini/gemini-2.5-pro": {
+		"litellm_provider": "gemini", "mode": "chat",
+		"input_cost_per_token": 1.25e-06, "output_cost_per_token": 1e-05,
+		"input_cost_per_token_above_200k_tokens": 2.5e-06, "output_cost_per_token_above_200k_tokens": 1.5e-05,
+		"output_cost_per_reasoning_token": 1e-05
+	},
+	"claude-sonnet-4-5": {
+		"litellm_provider": "anthropic", "mode": "chat",
+		"input_cost_per_token": 3e-06, "output_cost_per_token": 1.5e-05,
+		"cache_creation_input_token_cost": 3.75e-06, "cache_creation_input_token_cost_above_1hr": 6e-06,
+		"deprecation_date": "2027-01-01"
+	},
+	"dall-e-3": {"litellm_provider": "openai", "mode": "image_generation", "output_cost_per_image": 0.04},
+	"bad-model": {"litellm_provider": "openai", "mode": "chat", "input_cost_per_token": -1e-06}
+}`
+
+func TestImportLiteLLM(t *testing.T) {
+	imp, err := ImportLiteLLM(strings.NewReader(liteLLMSample))
+	if err != nil {
+		t.Fatalf("ImportLiteLLM failed: %v", err)
+	}
+	if got := slices.Sorted(maps.Keys(imp.Providers)); !slices.Equal(got, []string{"anthropic", "google", "openai"}) {
+		t.Fatalf("unexpected providers %v", got)
+	}
+
+	gpt := imp.Providers["openai"].Models["gpt-4o"]
+	if gpt.InputPerMillion != 2.5 || gpt.OutputPerMillion != 10 || gpt.CacheReadMultiplier != 0.5 || gpt.BatchMultiplier != 0.5 {
+		t.Errorf("unexpected gpt-4o pricing %+v", gpt)
+	}
+	if gpt.ContextWindow != 128000 || gpt.MaxOutputTokens != 16384 || !slices.Contains(gpt.InputModalities, ModalityImage) {
+		t.Errorf("unexpected gpt-4o metadata %+v", gpt)
+	}
+
+	gemini := imp.Providers["google"].Models["gemini-2.5-pro"]
+	if len(gemini.Tiers) != 1 || gemini.Tiers[0] != (PricingTier{ThresholdTokens: 200_000, InputPerMillion: 2.5, OutputPerMillion: 15}) {
+		t.Errorf("unexpected gemini tiers %+v", gemini.Tiers)
+	}
+	if gemini.ThinkingPerMillion != 10 {
+		t.Errorf("expected thinking rate 10, got %f", gemini.ThinkingPerMillion)
+	}
+
+	claude := imp.Providers["anthropic"].Models["claude-sonnet-4-5"]
+	if claude.DefaultCacheProfile != "5m" || claude.CacheProfiles["5m"].WriteMultiplier != 1.25 || claude.CacheProfiles["1h"].WriteMultiplier != 2 {
+		t.Errorf("unexpected claude cache profiles %+v", claude.CacheProfiles)
+	}
+	if claude.SunsetDate != "2027-01-01" {
+		t.Errorf("expected deprecation date as sunset date, got %q", claude.SunsetDate)
+	}
+
+	for _, want := range []string{"bad-model: ", "dall-e-3: mode \"image_generation\"", "openai/gpt-4o: duplicates openai model \"gpt-4o\""} {
+		if !slices.ContainsFunc(imp.Skipped, func(s string) bool { return strings.HasPrefix(s, want) }) {
+			t.Errorf("expected a skipped entry starting %q, got %v", want, imp.Skipped)
+		}
+	}
+	if _, err := ImportLiteLLM(strings.NewReader(`[1, 2]`)); err == nil {
+		t.Error("expected an error for a non-object document")
+	}
+}
+
+func TestExportLiteLLM_TierModes(t *testing.T) {
+	p, err := NewPricerFromFS(fstest.MapFS{
+		"configs/test_pricing.json": &fstest.MapFile{Data: []byte(`{
+			"provider": "test",
+			"models": {
+				"cliff": {
+					"input_per_million": 1, "output_per_million": 4,
+					"tiers": [{"threshold_tokens": 200000, "input_per_million": 2, "output_per_million": 8}]
+				},
+				"marginal": {
+					"input_per_million": 1, "output_per_million": 4, "tier_mode": "marginal",
+					"tiers": [{"threshold_tokens": 200000, "input_per_million": 2, "output_per_million": 8}]
+				},
+				"output-tiered": {
+					"input_per_million": 1, "output_per_million": 4, "tier_basis": "output",
+					"tiers": [{"threshold_tokens": 32000, "input_per_million": 1, "output_per_million": 6}]
+				}
+			}
+		}`)},
+	}, "configs")
+	if err != nil {
+		t.Fatalf("NewPricerFromFS failed: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := p.ExportLiteLLM(&buf); err != nil {
+		t.Fatalf("ExportLiteLLM failed: %v", err)
+	}
+	var raw map[string]map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &raw); err != nil {
+		t.Fatalf("export is not valid JSON: %v", err)
+	}
+
+	if _, ok := raw["cliff"]["input_cost_per_token_above_200k_tokens"]; !ok {
+		t.Errorf("expected the cliff tier exported, got %v", raw["cliff"])
+	}
+	if _, ok := raw["marginal"]["input_cost_per_token_above_200k_tokens"]; ok {
+		t.Errorf("expected the marginal tier left out, got %v", raw["marginal"])
+	}
+	if _, ok := raw["output-tiered"]["output_cost_per_token_above_32k_tokens"]; ok {
+		t.Errorf("expected the output-basis tier left out, got %v", raw["output-tiered"])
+	}
+}
+
+func TestExportLiteLLM_RoundTrip(t *testing.T) {
+	p, err := NewPricer()
+	if err != nil {
+		t.Fatalf("NewPricer failed: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := p.ExportLiteLLM(&buf); err != nil {
+		t.Fatalf("ExportLiteLLM failed: %v", err)
+	}
+
+	var raw map[string]map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &raw); err != nil {
+		t.Fatalf("export is not valid JSON: %v", err)
+	}
+	if raw["gpt-4o"]["litellm_provider"] != "openai" {
+		t.Errorf("expected gpt-4o keyed by its bare name, got %v", raw["gpt-4o"])
+	}
+
+	imp, err := ImportLiteLLM(&buf)
+	if err != nil {
+		t.Fatalf("ImportLiteLLM failed: %v", err)
+	}
+	for _, key := range []string{"openai/gpt-4o", "anthropic/claude-sonnet-4-5", "google/gemini-2.5-pro"} {
+		provider, model, _ := strings.Cut(key, "/")
+		want, ok := p.GetPricing(key)
+		if !ok {
+			t.Fatalf("%s not in catalog", key)
+		}
+		got, ok := imp.Providers[provider].Models[model]
+		if !ok {
+			t.Errorf("%s not round-tripped; skipped: %v", key, imp.Skipped)
+			continue
+		}
+		if !floatEquals(got.InputPerMillion, want.InputPerMillion) || !floatEquals(got.OutputPerMillion, want.OutputPerMillion) || len(got.Tiers) != len(want.Tiers) {
+			t.Errorf("%s: expected %+v, got %+v", key, want, got)
+		}
+	}
+}
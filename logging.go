@@ -0,0 +1,52 @@
+package pricing_db
+
+import (
+	"context"
+	"log/slog"
+)
+
+// WithLogger sets a logger for diagnostic events: model collisions resolved
+// at load (Info), and per-call prefix matches, unknown models and calculation
+// warnings such as clamped or overflowing token counts (Debug). To receive
+// events as callbacks, pass a logger built on a custom slog.Handler. Per-call
+// events cost nothing unless the logger has Debug enabled.
+func WithLogger(logger *slog.Logger) Option {
+	return func(o *pricerOptions) {
+		o.logger = logger
+	}
+}
+
+// debugEnabled reports whether per-call debug events should be built and
+// logged. Call sites check it first so disabled logging allocates nothing.
+func (c *catalog) debugEnabled() bool {
+	return c.logger != nil && c.logger.Enabled(context.Background(), slog.LevelDebug)
+}
+
+// logCollisions logs, at Info, how each bare model name defined by several
+// providers was resolved.
+func logCollisions(logger *slog.Logger, collisions []ModelCollision) {
+	if logger == nil {
+		return
+	}
+	for _, col := range collisions {
+		logger.Info("pricing: model collision",
+			slog.String("model", col.Model),
+			slog.Any("providers", col.Providers),
+			slog.String("kept", col.Kept),
+		)
+	}
+}
+
+// logWarnings logs a calculation's warnings at Debug.
+func (c *catalog) logWarnings(model string, warnings []Warning) {
+	if len(warnings) == 0 || !c.debugEnabled() {
+		return
+	}
+	for _, w := range warnings {
+		c.logger.Debug("pricing: calculation warning",
+			slog.String("model", model),
+			slog.String("code", string(w.Code)),
+			slog.String("message", w.Message),
+		)
+	}
+}
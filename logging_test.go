@@ -0,0 +1,76 @@
+package pricing_db
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// =============================================================================
+// Logging Hook Tests
+// =============================================================================
+
+func newTestLogger(level slog.Level) (*slog.Logger, *bytes.Buffer) {
+	var buf bytes.Buffer
+	return slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: level})), &buf
+}
+
+func TestWithLogger_CallEvents(t *testing.T) {
+	logger, buf := newTestLogger(slog.LevelDebug)
+	p, err := NewPricerFromFS(reloadFS("1.0"), "configs", WithLogger(logger))
+	if err != nil {
+		t.Fatalf("NewPricerFromFS failed: %v", err)
+	}
+
+	p.Calculate("m-2025-01-01", 100, 100)
+	p.Calculate("mystery", 100, 100)
+	p.CalculateUsage("m", TokenUsage{PromptTokens: 10, CachedTokens: 50}, nil)
+
+	out := buf.String()
+	for _, want := range []string{
+		`msg="pricing: prefix match" model=m-2025-01-01 key=m`,
+		`msg="pricing: unknown model" model=mystery`,
+		`msg="pricing: calculation warning" model=m code=cached_tokens_clamped`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected log line containing %q, got:\n%s", want, out)
+		}
+	}
+}
+
+func TestWithLogger_DebugDisabled(t *testing.T) {
+	logger, buf := newTestLogger(slog.LevelInfo)
+	p, err := NewPricerFromFS(reloadFS("1.0"), "configs", WithLogger(logger))
+	if err != nil {
+		t.Fatalf("NewPricerFromFS failed: %v", err)
+	}
+
+	p.Calculate("mystery", 100, 100)
+	if buf.Len() != 0 {
+		t.Errorf("expected no per-call logging at Info, got:\n%s", buf.String())
+	}
+}
+
+func TestWithLogger_Collisions(t *testing.T) {
+	fsys := fstest.MapFS{
+		"configs/alpha_pricing.json": &fstest.MapFile{Data: []byte(`{
+			"provider": "alpha",
+			"models": {"shared": {"input_per_million": 1.0, "output_per_million": 1.0}}
+		}`)},
+		"configs/beta_pricing.json": &fstest.MapFile{Data: []byte(`{
+			"provider": "beta",
+			"models": {"shared": {"input_per_million": 2.0, "output_per_million": 2.0}}
+		}`)},
+	}
+	logger, buf := newTestLogger(slog.LevelInfo)
+	if _, err := NewPricerFromFS(fsys, "configs", WithLogger(logger)); err != nil {
+		t.Fatalf("NewPricerFromFS failed: %v", err)
+	}
+
+	want := `msg="pricing: model collision" model=shared providers="[alpha beta]" kept=alpha`
+	if !strings.Contains(buf.String(), want) {
+		t.Errorf("expected log line containing %q, got:\n%s", want, buf.String())
+	}
+}
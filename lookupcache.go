@@ -0,0 +1,171 @@
+package pricing_db
+
+import (
+	"container/list"
+	"log/slog"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// lookupCache is a bounded LRU of model names resolved by prefix matching
+// (e.g. "gpt-4o-2024-08-06" -> "gpt-4o"), so the handful of versioned names a
+// service actually sends skip the scan over every catalog key. Each cache
+// belongs to a single catalog, so a Reload starts with empty caches.
+type lookupCache struct {
+	mu       sync.Mutex
+	capacity int
+	entries  map[string]*list.Element // model name -> element holding a *lookupCacheEntry
+	order    *list.List               // most recently used first
+}
+
+// lookupCacheEntry is a cached model name and the catalog key it resolved to.
+type lookupCacheEntry struct {
+	model string
+	key   string
+}
+
+// newLookupCache returns a cache holding up to capacity names, or nil when
+// capacity is 0 (caching disabled).
+func newLookupCache(capacity int) *lookupCache {
+	if capacity <= 0 {
+		return nil
+	}
+	return &lookupCache{
+		capacity: capacity,
+		entries:  make(map[string]*list.Element, capacity),
+		order:    list.New(),
+	}
+}
+
+// get returns the catalog key cached for model, marking it recently used.
+// A nil cache never hits.
+func (l *lookupCache) get(model string) (string, bool) {
+	if l == nil {
+		return "", false
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	e, ok := l.entries[model]
+	if !ok {
+		return "", false
+	}
+	l.order.MoveToFront(e)
+	return e.Value.(*lookupCacheEntry).key, true
+}
+
+// add caches model's catalog key, evicting the least recently used name when
+// the cache is full. A nil cache ignores it.
+func (l *lookupCache) add(model, key string) {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if e, ok := l.entries[model]; ok {
+		e.Value.(*lookupCacheEntry).key = key
+		l.order.MoveToFront(e)
+		return
+	}
+	l.entries[model] = l.order.PushFront(&lookupCacheEntry{model: model, key: key})
+	if l.order.Len() > l.capacity {
+		oldest := l.order.Back()
+		l.order.Remove(oldest)
+		delete(l.entries, oldest.Value.(*lookupCacheEntry).model)
+	}
+}
+
+// len returns the number of cached names; 0 for a nil cache.
+func (l *lookupCache) len() int {
+	if l == nil {
+		return 0
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.order.Len()
+}
+
+// negativeCache is a bounded set of model names that matched no catalog key.
+// It is on by default and consulted before every prefix scan, so reads must
+// not lock: names live in a sync.Map, and a full cache is cleared rather than
+// evicting its least recently used name.
+type negativeCache struct {
+	capacity int64
+	size     atomic.Int64
+	names    sync.Map // model name -> struct{}
+}
+
+// newNegativeCache returns a cache holding up to capacity names, or nil when
+// capacity is 0 (caching disabled).
+func newNegativeCache(capacity int) *negativeCache {
+	if capacity <= 0 {
+		return nil
+	}
+	return &negativeCache{capacity: int64(capacity)}
+}
+
+// has reports whether model is cached as unknown. A nil cache never hits.
+func (n *negativeCache) has(model string) bool {
+	if n == nil {
+		return false
+	}
+	_, ok := n.names.Load(model)
+	return ok
+}
+
+// add caches model as unknown, first clearing the cache when it is full.
+// Under concurrent adds the bound is approximate. A nil cache ignores it.
+func (n *negativeCache) add(model string) {
+	if n == nil {
+		return
+	}
+	if n.size.Load() >= n.capacity {
+		n.names.Clear()
+		n.size.Store(0)
+	}
+	if _, loaded := n.names.LoadOrStore(model, struct{}{}); !loaded {
+		n.size.Add(1)
+	}
+}
+
+// len returns the number of cached names; 0 for a nil cache.
+func (n *negativeCache) len() int {
+	if n == nil {
+		return 0
+	}
+	count := 0
+	n.names.Range(func(_, _ any) bool {
+		count++
+		return true
+	})
+	return count
+}
+
+// prefixKey resolves model to a catalog key by prefix matching (longest key
+// first), consulting and filling the lookup cache when WithLookupCache is set
+// and the negative cache of unknown names (see WithNegativeCache).
+// cached reports whether the result, hit or miss, came from a cache.
+func (c *catalog) prefixKey(model string) (key string, cached, ok bool) {
+	if key, ok := c.resolved.get(model); ok {
+		return key, true, true
+	}
+	if c.unknown.has(model) {
+		return "", true, false
+	}
+	for _, key := range c.modelKeysSorted {
+		if strings.HasPrefix(model, key) && isValidPrefixMatch(model, key) {
+			c.resolved.add(model, key)
+			if c.debugEnabled() {
+				c.logger.Debug("pricing: prefix match", slog.String("model", model), slog.String("key", key))
+			}
+			return key, false, true
+		}
+	}
+	c.unknown.add(model)
+	if c.debugEnabled() {
+		c.logger.Debug("pricing: unknown model", slog.String("model", model))
+	}
+	return "", false, false
+}
@@ -0,0 +1,225 @@
+package pricing_db
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"testing/fstest"
+)
+
+// =============================================================================
+// Lookup Cache Tests
+// =============================================================================
+
+func TestLookupCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	l := newLookupCache(2)
+	l.add("a-1", "a")
+	l.add("b-1", "b")
+	l.get("a-1") // a-1 is now the most recently used
+	l.add("c-1", "c")
+
+	if _, ok := l.get("b-1"); ok {
+		t.Error("expected b-1 to be evicted")
+	}
+	for _, model := range []string{"a-1", "c-1"} {
+		if _, ok := l.get(model); !ok {
+			t.Errorf("expected %s to be cached", model)
+		}
+	}
+	if n := l.len(); n != 2 {
+		t.Errorf("expected 2 entries, got %d", n)
+	}
+}
+
+func TestLookupCache_NilIsDisabled(t *testing.T) {
+	var l *lookupCache
+	l.add("a-1", "a")
+	if _, ok := l.get("a-1"); ok {
+		t.Error("nil cache should never hit")
+	}
+	if newLookupCache(0) != nil {
+		t.Error("expected size 0 to disable the cache")
+	}
+}
+
+func TestWithLookupCache_PrefixMatches(t *testing.T) {
+	p, err := NewPricerFromFS(reloadFS("1.0"), "configs", WithLookupCache(8), WithLookupStats())
+	if err != nil {
+		t.Fatalf("NewPricerFromFS failed: %v", err)
+	}
+
+	for range 3 {
+		if cost := p.Calculate("m-2025-01-01", 1_000_000, 0); !floatEquals(cost.InputCost, 1.0) {
+			t.Fatalf("expected $1.00 input cost, got $%f", cost.InputCost)
+		}
+	}
+	p.Calculate("m", 100, 0)       // exact matches bypass the cache
+	p.Calculate("unknown", 100, 0) // misses are not cached
+
+	stats := p.Stats()
+	if stats.LookupCacheEntries != 1 {
+		t.Errorf("expected 1 cached name, got %d", stats.LookupCacheEntries)
+	}
+	want := LookupStats{ExactHits: 1, PrefixHits: 3, CacheHits: 2, Misses: 1}
+	if stats.Lookups == nil || *stats.Lookups != want {
+		t.Errorf("expected %+v, got %+v", want, stats.Lookups)
+	}
+
+	// GetPricing and GetModelInfo share the cached resolution
+	if _, ok := p.GetPricing("m-2025-01-01"); !ok {
+		t.Error("expected GetPricing to resolve the cached name")
+	}
+	if info, ok := p.GetModelInfo("m-2025-01-01"); !ok || info.Model != "m" {
+		t.Errorf("expected GetModelInfo to resolve to m, got %q (found=%v)", info.Model, ok)
+	}
+}
+
+func TestWithLookupCache_ReloadEmptiesCache(t *testing.T) {
+	p, err := NewPricerFromFS(reloadFS("1.0"), "configs", WithLookupCache(8))
+	if err != nil {
+		t.Fatalf("NewPricerFromFS failed: %v", err)
+	}
+	p.Calculate("m-2025-01-01", 1_000_000, 0)
+
+	if err := p.Reload(reloadFS("2.0"), "configs", WithLookupCache(8)); err != nil {
+		t.Fatalf("Reload failed: %v", err)
+	}
+	if n := p.Stats().LookupCacheEntries; n != 0 {
+		t.Errorf("expected an empty cache after reload, got %d entries", n)
+	}
+	if cost := p.Calculate("m-2025-01-01", 1_000_000, 0); !floatEquals(cost.InputCost, 2.0) {
+		t.Errorf("expected reloaded input cost $2.00, got $%f", cost.InputCost)
+	}
+}
+
+func TestWithLookupCache_NegativeSize(t *testing.T) {
+	if _, err := NewPricerFromFS(reloadFS("1.0"), "configs", WithLookupCache(-1)); err == nil {
+		t.Error("expected error for negative lookup cache size")
+	}
+}
+
+func TestWithLookupCache_Concurrent(t *testing.T) {
+	p, err := NewPricerFromFS(reloadFS("1.0"), "configs", WithLookupCache(4))
+	if err != nil {
+		t.Fatalf("NewPricerFromFS failed: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range 100 {
+				model := fmt.Sprintf("m-%d", (i+j)%10)
+				if cost := p.Calculate(model, 1_000_000, 0); !floatEquals(cost.InputCost, 1.0) {
+					t.Errorf("%s: expected $1.00 input cost, got $%f", model, cost.InputCost)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if n := p.Stats().LookupCacheEntries; n > 4 {
+		t.Errorf("cache exceeded its bound: %d entries", n)
+	}
+}
+
+func TestNegativeCache_RemembersUnknownModels(t *testing.T) {
+	p, err := NewPricerFromFS(reloadFS("1.0"), "configs", WithLookupStats())
+	if err != nil {
+		t.Fatalf("NewPricerFromFS failed: %v", err)
+	}
+
+	for range 3 {
+		if cost := p.Calculate("mystery", 100, 0); !cost.Unknown {
+			t.Fatalf("expected mystery to be unknown, got %+v", cost)
+		}
+	}
+
+	stats := p.Stats()
+	if stats.NegativeCacheEntries != 1 {
+		t.Errorf("expected 1 negative cache entry, got %d", stats.NegativeCacheEntries)
+	}
+	want := LookupStats{Misses: 3, NegativeHits: 2}
+	if stats.Lookups == nil || *stats.Lookups != want {
+		t.Errorf("expected %+v, got %+v", want, stats.Lookups)
+	}
+}
+
+func TestNegativeCache_ReloadInvalidates(t *testing.T) {
+	p, err := NewPricerFromFS(reloadFS("1.0"), "configs")
+	if err != nil {
+		t.Fatalf("NewPricerFromFS failed: %v", err)
+	}
+	if cost := p.Calculate("n-2025-01-01", 1_000_000, 0); !cost.Unknown {
+		t.Fatalf("expected n-2025-01-01 to be unknown, got %+v", cost)
+	}
+
+	// A reload that adds the model must not serve the stale miss
+	fsys := reloadFS("1.0")
+	fsys["configs/other_pricing.json"] = &fstest.MapFile{Data: []byte(`{
+		"models": {"n": {"input_per_million": 3.0, "output_per_million": 1.0}}
+	}`)}
+	if err := p.Reload(fsys, "configs"); err != nil {
+		t.Fatalf("Reload failed: %v", err)
+	}
+	if cost := p.Calculate("n-2025-01-01", 1_000_000, 0); cost.Unknown || !floatEquals(cost.InputCost, 3.0) {
+		t.Errorf("expected $3.00 input cost after reload, got %+v", cost)
+	}
+}
+
+func TestNegativeCache_Disabled(t *testing.T) {
+	p, err := NewPricerFromFS(reloadFS("1.0"), "configs", WithNegativeCache(0))
+	if err != nil {
+		t.Fatalf("NewPricerFromFS failed: %v", err)
+	}
+	p.Calculate("mystery", 100, 0)
+	if n := p.Stats().NegativeCacheEntries; n != 0 {
+		t.Errorf("expected no negative cache entries, got %d", n)
+	}
+
+	if _, err := NewPricerFromFS(reloadFS("1.0"), "configs", WithNegativeCache(-1)); err == nil {
+		t.Error("expected error for negative cache size")
+	}
+}
+
+func TestNegativeCache_Bounded(t *testing.T) {
+	p, err := NewPricerFromFS(reloadFS("1.0"), "configs", WithNegativeCache(4))
+	if err != nil {
+		t.Fatalf("NewPricerFromFS failed: %v", err)
+	}
+	for i := range 20 {
+		p.Calculate(fmt.Sprintf("mystery-%d", i), 100, 0)
+	}
+	if n := p.Stats().NegativeCacheEntries; n == 0 || n > 4 {
+		t.Errorf("expected the negative cache to hold 1 to 4 names, got %d", n)
+	}
+}
+
+func TestNegativeCache_Concurrent(t *testing.T) {
+	p, err := NewPricerFromFS(reloadFS("1.0"), "configs", WithNegativeCache(4))
+	if err != nil {
+		t.Fatalf("NewPricerFromFS failed: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range 100 {
+				if cost := p.Calculate(fmt.Sprintf("mystery-%d", (i+j)%10), 100, 0); !cost.Unknown {
+					t.Errorf("expected an unknown model, got %+v", cost)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Concurrent adds may each overshoot the bound by one.
+	if n := p.Stats().NegativeCacheEntries; n > 4+8 {
+		t.Errorf("cache exceeded its bound: %d entries", n)
+	}
+}
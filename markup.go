@@ -0,0 +1,134 @@
+package pricing_db
+
+import (
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+)
+
+// Markup is a reseller's uplift on top of provider cost. Percent and the
+// per-token uplifts add up; a zero Markup bills provider cost unchanged.
+type Markup struct {
+	Percent          float64 // Percentage of provider cost, e.g. 20 for +20%
+	InputPerMillion  float64 // Absolute uplift per million input tokens (USD)
+	OutputPerMillion float64 // Absolute uplift per million output and thinking tokens (USD)
+}
+
+// markupTable holds a catalog's markups: models by catalog key, all others
+// at the default.
+type markupTable struct {
+	all    Markup
+	models map[string]Markup
+}
+
+// WithMarkup marks up every model's cost by m. CostDetails then reports the
+// provider cost in TotalCost and RawTotal, the uplift in Markup, and the
+// customer price in BilledTotal. Per-model markups from WithModelMarkups take
+// precedence. Cost (from Calculate) is not marked up.
+func WithMarkup(m Markup) Option {
+	return func(o *pricerOptions) {
+		o.markup = &m
+	}
+}
+
+// WithModelMarkups sets markups for individual models, replacing the
+// WithMarkup default for them. Keys are catalog keys; a bare name also covers
+// its provider's "provider/model" key (and the other way round) unless that
+// key has its own entry, as with WithOverlay. Unknown keys are load errors.
+func WithModelMarkups(models map[string]Markup) Option {
+	return func(o *pricerOptions) {
+		if o.modelMarkups == nil {
+			o.modelMarkups = make(map[string]Markup, len(models))
+		}
+		maps.Copy(o.modelMarkups, models)
+	}
+}
+
+// validate reports a negative percentage or uplift.
+func (m Markup) validate(context string) error {
+	if m.Percent < 0 {
+		return fmt.Errorf("%s: markup percent %f must not be negative", context, m.Percent)
+	}
+	if m.InputPerMillion < 0 || m.OutputPerMillion < 0 {
+		return fmt.Errorf("%s: markup uplift must not be negative", context)
+	}
+	return nil
+}
+
+// newMarkupTable validates the markup options against c's models. It returns
+// nil when no markup is configured.
+func (c *catalog) newMarkupTable(all *Markup, models map[string]Markup) (*markupTable, error) {
+	if all == nil && len(models) == 0 {
+		return nil, nil
+	}
+	t := &markupTable{}
+	if all != nil {
+		if err := all.validate("WithMarkup"); err != nil {
+			return nil, err
+		}
+		t.all = *all
+	}
+	if len(models) > 0 {
+		t.models = make(map[string]Markup, len(models))
+	}
+	for _, key := range slices.Sorted(maps.Keys(models)) {
+		if _, ok := c.models[key]; !ok {
+			return nil, fmt.Errorf("markup: model %q not in catalog", key)
+		}
+		m := models[key]
+		if err := m.validate(fmt.Sprintf("markup: model %q", key)); err != nil {
+			return nil, err
+		}
+		t.models[key] = m
+		if alias, ok := c.overlayAlias(key); ok {
+			if _, set := models[alias]; !set {
+				t.models[alias] = m
+			}
+		}
+	}
+	return t, nil
+}
+
+// markupFor returns the markup for model, resolved like its pricing.
+func (c *catalog) markupFor(model string) (Markup, bool) {
+	if c.markup == nil {
+		return Markup{}, false
+	}
+	if len(c.markup.models) > 0 {
+		if key, ok := c.resolveModelKey(model); ok {
+			if m, ok := c.markup.models[key]; ok {
+				return m, true
+			}
+		}
+	}
+	return c.markup.all, true
+}
+
+// applyMarkup sets dst.Markup and dst.BilledTotal from dst.RawTotal and the
+// input and output (including thinking) token counts, then the tax line.
+func (c *catalog) applyMarkup(dst *CostDetails, model string, inputTokens, outputTokens int64) {
+	dst.Markup = 0
+	if m, ok := c.markupFor(model); ok {
+		dst.Markup = dst.RawTotal*m.Percent/100 +
+			float64(inputTokens)*m.InputPerMillion/TokensPerMillion +
+			float64(outputTokens)*m.OutputPerMillion/TokensPerMillion
+	}
+	dst.BilledTotal = c.rounding.round(dst.RawTotal + dst.Markup)
+	c.applyTax(dst)
+}
+
+// applyTax sets dst.Tax and dst.GrossTotal from dst.RawTotal and dst.Markup.
+func (c *catalog) applyTax(dst *CostDetails) {
+	net := dst.RawTotal + dst.Markup
+	dst.Tax = net * c.taxRate
+	dst.GrossTotal = c.rounding.round(net + dst.Tax)
+}
+
+// validateTaxRate reports a WithTaxRate rate outside [0, 1].
+func validateTaxRate(rate *float64) error {
+	if rate != nil && (*rate < 0 || *rate > 1 || math.IsNaN(*rate)) {
+		return fmt.Errorf("tax rate %f out of range (0-1)", *rate)
+	}
+	return nil
+}
@@ -0,0 +1,168 @@
+package pricing_db
+
+import (
+	"strings"
+	"testing"
+)
+
+// =============================================================================
+// Markup Tests
+// =============================================================================
+
+func TestMarkup_Percent(t *testing.T) {
+	p, err := NewPricerFromFS(overlayFS(), "configs", WithMarkup(Markup{Percent: 20}))
+	if err != nil {
+		t.Fatalf("NewPricerFromFS failed: %v", err)
+	}
+
+	// Provider cost: 1M * $10/M + 1M * $30/M = $40; billed at +20%
+	cost := p.CalculateUsage("large", TokenUsage{PromptTokens: 1_000_000, CompletionTokens: 1_000_000}, nil)
+	if !floatEquals(cost.TotalCost, 40.0) {
+		t.Errorf("expected provider cost $40.00, got $%f", cost.TotalCost)
+	}
+	if !floatEquals(cost.Markup, 8.0) {
+		t.Errorf("expected markup $8.00, got $%f", cost.Markup)
+	}
+	if !floatEquals(cost.BilledTotal, 48.0) {
+		t.Errorf("expected billed total $48.00, got $%f", cost.BilledTotal)
+	}
+}
+
+func TestMarkup_PerModelUplift(t *testing.T) {
+	p, err := NewPricerFromFS(overlayFS(), "configs",
+		WithMarkup(Markup{Percent: 10}),
+		WithModelMarkups(map[string]Markup{"small": {InputPerMillion: 0.5, OutputPerMillion: 1.0}}),
+	)
+	if err != nil {
+		t.Fatalf("NewPricerFromFS failed: %v", err)
+	}
+
+	// small: $1 + $2 provider cost, plus $0.50 + $1.00 uplift (thinking counts as output)
+	usage := TokenUsage{PromptTokens: 1_000_000, CompletionTokens: 500_000, ThinkingTokens: 500_000}
+	for _, model := range []string{"small", "acme/small", "small-2025-01-01"} {
+		cost := p.CalculateUsage(model, usage, nil)
+		if !floatEquals(cost.Markup, 1.5) || !floatEquals(cost.BilledTotal, 4.5) {
+			t.Errorf("%s: expected markup $1.50 and billed $4.50, got $%f and $%f", model, cost.Markup, cost.BilledTotal)
+		}
+	}
+
+	// Other models keep the default markup
+	cost := p.CalculateUsage("large", TokenUsage{PromptTokens: 1_000_000}, nil)
+	if !floatEquals(cost.Markup, 1.0) {
+		t.Errorf("expected default markup $1.00 on large, got $%f", cost.Markup)
+	}
+}
+
+func TestMarkup_NoneBillsProviderCost(t *testing.T) {
+	p, err := NewPricerFromFS(overlayFS(), "configs")
+	if err != nil {
+		t.Fatalf("NewPricerFromFS failed: %v", err)
+	}
+	cost := p.CalculateUsage("large", TokenUsage{PromptTokens: 1_000_000}, nil)
+	if cost.Markup != 0 || !floatEquals(cost.BilledTotal, cost.TotalCost) {
+		t.Errorf("expected billed total to equal provider cost, got %+v", cost)
+	}
+}
+
+func TestMarkup_CloneReplacesMarkups(t *testing.T) {
+	base, err := NewPricerFromFS(overlayFS(), "configs", WithMarkup(Markup{Percent: 10}))
+	if err != nil {
+		t.Fatalf("NewPricerFromFS failed: %v", err)
+	}
+	tenant, err := base.Clone(WithMarkup(Markup{Percent: 50}))
+	if err != nil {
+		t.Fatalf("Clone failed: %v", err)
+	}
+
+	usage := TokenUsage{PromptTokens: 1_000_000}
+	if cost := tenant.CalculateUsage("large", usage, nil); !floatEquals(cost.BilledTotal, 15.0) {
+		t.Errorf("expected tenant billed total $15.00, got $%f", cost.BilledTotal)
+	}
+	if cost := base.CalculateUsage("large", usage, nil); !floatEquals(cost.BilledTotal, 11.0) {
+		t.Errorf("expected base billed total $11.00, got $%f", cost.BilledTotal)
+	}
+}
+
+func TestMarkup_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		opt  Option
+		want string
+	}{
+		{"negative percent", WithMarkup(Markup{Percent: -5}), "must not be negative"},
+		{"negative uplift", WithModelMarkups(map[string]Markup{"small": {InputPerMillion: -1}}), "must not be negative"},
+		{"unknown model", WithModelMarkups(map[string]Markup{"missing": {Percent: 5}}), "not in catalog"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewPricerFromFS(overlayFS(), "configs", tt.opt)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+// =============================================================================
+// Tax Tests
+// =============================================================================
+
+func TestTaxRate_OnBilledTotal(t *testing.T) {
+	p, err := NewPricerFromFS(overlayFS(), "configs", WithMarkup(Markup{Percent: 25}), WithTaxRate(0.2))
+	if err != nil {
+		t.Fatalf("NewPricerFromFS failed: %v", err)
+	}
+
+	// Provider $10, billed $12.50, tax 20% of $12.50 = $2.50
+	cost := p.CalculateUsage("large", TokenUsage{PromptTokens: 1_000_000}, nil)
+	if !floatEquals(cost.BilledTotal, 12.5) {
+		t.Errorf("expected billed total $12.50, got $%f", cost.BilledTotal)
+	}
+	if !floatEquals(cost.Tax, 2.5) {
+		t.Errorf("expected tax $2.50, got $%f", cost.Tax)
+	}
+	if !floatEquals(cost.GrossTotal, 15.0) {
+		t.Errorf("expected gross total $15.00, got $%f", cost.GrossTotal)
+	}
+}
+
+func TestTaxRate_NoneGrossEqualsBilled(t *testing.T) {
+	p, err := NewPricerFromFS(overlayFS(), "configs")
+	if err != nil {
+		t.Fatalf("NewPricerFromFS failed: %v", err)
+	}
+	cost := p.CalculateUsage("large", TokenUsage{PromptTokens: 1_000_000}, nil)
+	if cost.Tax != 0 || !floatEquals(cost.GrossTotal, cost.BilledTotal) {
+		t.Errorf("expected no tax and gross equal to billed, got %+v", cost)
+	}
+}
+
+func TestTaxRate_Clone(t *testing.T) {
+	base, err := NewPricerFromFS(overlayFS(), "configs", WithTaxRate(0.2))
+	if err != nil {
+		t.Fatalf("NewPricerFromFS failed: %v", err)
+	}
+	exempt, err := base.Clone(WithTaxRate(0))
+	if err != nil {
+		t.Fatalf("Clone failed: %v", err)
+	}
+
+	usage := TokenUsage{PromptTokens: 1_000_000}
+	if cost := exempt.CalculateUsage("large", usage, nil); cost.Tax != 0 {
+		t.Errorf("expected no tax on the exempt clone, got $%f", cost.Tax)
+	}
+	if cost := base.CalculateUsage("large", usage, nil); !floatEquals(cost.Tax, 2.0) {
+		t.Errorf("expected base tax $2.00, got $%f", cost.Tax)
+	}
+	if _, err := base.Clone(WithTaxRate(1.5)); err == nil {
+		t.Error("expected error for tax rate above 1")
+	}
+}
+
+func TestTaxRate_OutOfRange(t *testing.T) {
+	for _, rate := range []float64{-0.1, 1.1} {
+		if _, err := NewPricerFromFS(overlayFS(), "configs", WithTaxRate(rate)); err == nil {
+			t.Errorf("expected error for tax rate %v", rate)
+		}
+	}
+}
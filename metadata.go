@@ -0,0 +1,102 @@
+package pricing_db
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+)
+
+// timeNow is the clock used for staleness and credit expiry checks; replaced in tests.
+var timeNow = time.Now
+
+// ProviderSource describes where a provider's pricing came from, for auditing
+// how current each provider's data is.
+type ProviderSource struct {
+	Provider string
+	File     string // Config file name, e.g. "openai_pricing.json"
+	Metadata PricingMetadata
+}
+
+// ProviderSources returns the source file and metadata (updated date, source
+// URLs, notes) of every loaded provider, sorted by provider name.
+// Slices are copied to prevent mutation of internal state.
+func (p *Pricer) ProviderSources() []ProviderSource {
+	c := p.load()
+
+	sources := make([]ProviderSource, 0, len(c.providers))
+	for name, pp := range c.providers {
+		meta := pp.Metadata
+		meta.SourceURLs = slices.Clone(meta.SourceURLs)
+		meta.Notes = slices.Clone(meta.Notes)
+		sources = append(sources, ProviderSource{
+			Provider: name,
+			File:     pp.SourceFile,
+			Metadata: meta,
+		})
+	}
+	sort.Slice(sources, func(i, j int) bool {
+		return sources[i].Provider < sources[j].Provider
+	})
+	return sources
+}
+
+// UpdatedTime parses Updated as a "YYYY-MM-DD" date at 00:00 UTC.
+// Returns false if Updated is empty or malformed.
+func (m PricingMetadata) UpdatedTime() (time.Time, bool) {
+	t, err := time.Parse("2006-01-02", m.Updated)
+	return t, err == nil
+}
+
+// StaleProviders returns the providers whose metadata.updated date is more than
+// maxAge ago, sorted by provider name. Providers without a valid updated date are
+// included, since their freshness cannot be established.
+func (p *Pricer) StaleProviders(maxAge time.Duration) []ProviderSource {
+	now := timeNow()
+	var stale []ProviderSource
+	for _, src := range p.ProviderSources() {
+		if updated, ok := src.Metadata.UpdatedTime(); ok && now.Sub(updated) <= maxAge {
+			continue
+		}
+		stale = append(stale, src)
+	}
+	return stale
+}
+
+// annotateProviders records each model's provider and its metadata.updated
+// date on the compiled rates, for staleness warnings.
+func (c *catalog) annotateProviders() {
+	for key, r := range c.rates {
+		c.annotateProvider(key, r)
+	}
+}
+
+// annotateProvider records the provider of the model at key, and its
+// metadata.updated date, on r and its price history.
+func (c *catalog) annotateProvider(key string, r *modelRates) {
+	provider := c.modelProviders[key]
+	updated, _ := c.providers[provider].Metadata.UpdatedTime()
+	for _, rr := range append([]*modelRates{r}, r.historyRates()...) {
+		rr.provider = provider
+		rr.updated = updated
+	}
+}
+
+// stalenessWarning returns a WarningStalePricing warning if the rates' provider
+// data was last updated more than maxAge before now.
+func (r *modelRates) stalenessWarning(maxAge time.Duration, now time.Time) (Warning, bool) {
+	if r.updated.IsZero() {
+		return Warning{
+			Code:    WarningStalePricing,
+			Message: fmt.Sprintf("pricing for provider %q has no valid updated date", r.provider),
+		}, true
+	}
+	age := now.Sub(r.updated)
+	if age <= maxAge {
+		return Warning{}, false
+	}
+	return Warning{
+		Code:    WarningStalePricing,
+		Message: fmt.Sprintf("pricing for provider %q was last updated %s (%d days ago)", r.provider, r.updated.Format("2006-01-02"), int(age.Hours()/24)),
+	}, true
+}
@@ -0,0 +1,177 @@
+package pricing_db
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+// =============================================================================
+// Provider Source Introspection Tests
+// =============================================================================
+
+func TestProviderSources(t *testing.T) {
+	p, err := NewPricer()
+	if err != nil {
+		t.Fatalf("NewPricer failed: %v", err)
+	}
+
+	sources := p.ProviderSources()
+	if len(sources) != p.ProviderCount() {
+		t.Fatalf("expected %d sources, got %d", p.ProviderCount(), len(sources))
+	}
+	for i, src := range sources {
+		if i > 0 && sources[i-1].Provider >= src.Provider {
+			t.Errorf("sources not sorted: %s before %s", sources[i-1].Provider, src.Provider)
+		}
+		if src.File == "" {
+			t.Errorf("%s: missing source file", src.Provider)
+		}
+		if src.Metadata.Updated == "" {
+			t.Errorf("%s: missing updated date", src.Provider)
+		}
+	}
+
+	var openai ProviderSource
+	for _, src := range sources {
+		if src.Provider == "openai" {
+			openai = src
+		}
+	}
+	if openai.File != "openai_pricing.json" {
+		t.Errorf("expected openai_pricing.json, got %q", openai.File)
+	}
+
+	// Returned slices are copies
+	if len(openai.Metadata.SourceURLs) > 0 {
+		openai.Metadata.SourceURLs[0] = "mutated"
+		for _, src := range p.ProviderSources() {
+			if src.Provider == "openai" && src.Metadata.SourceURLs[0] == "mutated" {
+				t.Error("ProviderSources should return copies of metadata slices")
+			}
+		}
+	}
+}
+
+func TestProviderSources_InferredProvider(t *testing.T) {
+	fsys := fstest.MapFS{
+		"configs/acme_pricing.json": &fstest.MapFile{Data: []byte(`{
+			"models": {"acme-1": {"input_per_million": 1.0, "output_per_million": 2.0}},
+			"metadata": {"updated": "2026-01-01", "notes": ["hand-maintained"]}
+		}`)},
+	}
+	p, err := NewPricerFromFS(fsys, "configs")
+	if err != nil {
+		t.Fatalf("NewPricerFromFS failed: %v", err)
+	}
+	sources := p.ProviderSources()
+	if len(sources) != 1 || sources[0].Provider != "acme" || sources[0].File != "acme_pricing.json" || sources[0].Metadata.Updated != "2026-01-01" {
+		t.Errorf("unexpected sources: %+v", sources)
+	}
+}
+
+// =============================================================================
+// Staleness Tests
+// =============================================================================
+
+func stalenessFS() fstest.MapFS {
+	return fstest.MapFS{
+		"configs/fresh_pricing.json": &fstest.MapFile{Data: []byte(`{
+			"provider": "fresh",
+			"models": {"fresh-1": {"input_per_million": 1.0, "output_per_million": 2.0}},
+			"metadata": {"updated": "2026-10-01"}
+		}`)},
+		"configs/old_pricing.json": &fstest.MapFile{Data: []byte(`{
+			"provider": "old",
+			"models": {"old-1": {"input_per_million": 1.0, "output_per_million": 2.0}},
+			"metadata": {"updated": "2026-01-01"}
+		}`)},
+		"configs/undated_pricing.json": &fstest.MapFile{Data: []byte(`{
+			"provider": "undated",
+			"models": {"undated-1": {"input_per_million": 1.0, "output_per_million": 2.0}}
+		}`)},
+	}
+}
+
+func stubNow(t *testing.T, now time.Time) {
+	t.Helper()
+	orig := timeNow
+	timeNow = func() time.Time { return now }
+	t.Cleanup(func() { timeNow = orig })
+}
+
+func TestPricingMetadata_UpdatedTime(t *testing.T) {
+	got, ok := PricingMetadata{Updated: "2026-03-15"}.UpdatedTime()
+	if !ok || !got.Equal(time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected 2026-03-15 UTC, got %v (ok=%v)", got, ok)
+	}
+	for _, s := range []string{"", "March 2026", "2026-13-01"} {
+		if _, ok := (PricingMetadata{Updated: s}).UpdatedTime(); ok {
+			t.Errorf("expected %q to be rejected", s)
+		}
+	}
+}
+
+func TestStaleProviders(t *testing.T) {
+	stubNow(t, time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC))
+	p, err := NewPricerFromFS(stalenessFS(), "configs")
+	if err != nil {
+		t.Fatalf("NewPricerFromFS failed: %v", err)
+	}
+
+	stale := p.StaleProviders(90 * 24 * time.Hour)
+	var names []string
+	for _, src := range stale {
+		names = append(names, src.Provider)
+	}
+	if strings.Join(names, ",") != "old,undated" {
+		t.Errorf("expected old,undated; got %v", names)
+	}
+}
+
+func TestWithStalenessWarning(t *testing.T) {
+	stubNow(t, time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC))
+	p, err := NewPricerFromFS(stalenessFS(), "configs", WithStalenessWarning(90*24*time.Hour))
+	if err != nil {
+		t.Fatalf("NewPricerFromFS failed: %v", err)
+	}
+	usage := TokenUsage{PromptTokens: 1000, CompletionTokens: 1000}
+
+	if cost := p.CalculateUsage("fresh-1", usage, nil); hasWarningCode(cost.WarningDetails, WarningStalePricing) {
+		t.Errorf("unexpected stale warning for fresh provider: %v", cost.Warnings)
+	}
+
+	cost := p.CalculateUsage("old-1", usage, nil)
+	if !hasWarningCode(cost.WarningDetails, WarningStalePricing) {
+		t.Fatalf("expected stale warning, got %v", cost.Warnings)
+	}
+	if !strings.Contains(strings.Join(cost.Warnings, "\n"), "2026-01-01") {
+		t.Errorf("expected warning to name the updated date, got %v", cost.Warnings)
+	}
+	if !floatEquals(cost.TotalCost, 0.003) {
+		t.Errorf("staleness should not change cost, got %f", cost.TotalCost)
+	}
+
+	if cost := p.CalculateUsage("undated-1", usage, nil); !hasWarningCode(cost.WarningDetails, WarningStalePricing) {
+		t.Errorf("expected stale warning for undated provider, got %v", cost.Warnings)
+	}
+}
+
+func TestWithStalenessWarning_DisabledByDefault(t *testing.T) {
+	stubNow(t, time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC))
+	p, err := NewPricerFromFS(stalenessFS(), "configs")
+	if err != nil {
+		t.Fatalf("NewPricerFromFS failed: %v", err)
+	}
+	cost := p.CalculateUsage("old-1", TokenUsage{PromptTokens: 1000}, nil)
+	if hasWarningCode(cost.WarningDetails, WarningStalePricing) {
+		t.Errorf("staleness warnings should be opt-in, got %v", cost.Warnings)
+	}
+}
+
+func TestWithStalenessWarning_Negative(t *testing.T) {
+	if _, err := NewPricerFromFS(stalenessFS(), "configs", WithStalenessWarning(-time.Hour)); err == nil {
+		t.Error("expected error for negative staleness threshold")
+	}
+}
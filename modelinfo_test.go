@@ -0,0 +1,115 @@
+package pricing_db
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// =============================================================================
+// Model Info Metadata Tests
+// =============================================================================
+
+func TestGetModelInfo(t *testing.T) {
+	p, err := NewPricer()
+	if err != nil {
+		t.Fatalf("NewPricer failed: %v", err)
+	}
+
+	info, ok := p.GetModelInfo("gpt-4o")
+	if !ok {
+		t.Fatal("expected gpt-4o to be found")
+	}
+	if info.Model != "gpt-4o" || info.Provider != "openai" {
+		t.Errorf("expected gpt-4o from openai, got %q from %q", info.Model, info.Provider)
+	}
+	if !floatEquals(info.InputPerMillion, 2.5) {
+		t.Errorf("expected input price 2.5, got %f", info.InputPerMillion)
+	}
+	if info.ContextWindow != 128000 {
+		t.Errorf("expected context window 128000, got %d", info.ContextWindow)
+	}
+	if info.MaxOutputTokens != 16384 {
+		t.Errorf("expected max output 16384, got %d", info.MaxOutputTokens)
+	}
+	if info.KnowledgeCutoff != "2023-10" {
+		t.Errorf("expected knowledge cutoff 2023-10, got %q", info.KnowledgeCutoff)
+	}
+	if !info.SupportsInput(ModalityImage) || info.SupportsInput(ModalityAudio) {
+		t.Errorf("unexpected input modalities: %v", info.InputModalities)
+	}
+	if !info.SupportsOutput(ModalityText) {
+		t.Errorf("expected text output, got %v", info.OutputModalities)
+	}
+}
+
+func TestGetModelInfo_PrefixMatchAndNamespaced(t *testing.T) {
+	p, err := NewPricer()
+	if err != nil {
+		t.Fatalf("NewPricer failed: %v", err)
+	}
+
+	info, ok := p.GetModelInfo("gemini-2.5-pro-preview-06-05")
+	if !ok {
+		t.Fatal("expected prefix match for gemini-2.5-pro-preview-06-05")
+	}
+	if info.Model != "gemini-2.5-pro" || info.Provider != "google" {
+		t.Errorf("expected gemini-2.5-pro from google, got %q from %q", info.Model, info.Provider)
+	}
+	if !info.SupportsInput(ModalityVideo) {
+		t.Error("expected gemini-2.5-pro to accept video input")
+	}
+
+	info, ok = p.GetModelInfo("anthropic/claude-sonnet-4-5")
+	if !ok || info.Provider != "anthropic" {
+		t.Errorf("expected namespaced lookup to resolve to anthropic, got %q (ok=%v)", info.Provider, ok)
+	}
+
+	if _, ok := GetModelInfo("unknown-model-xyz"); ok {
+		t.Error("expected unknown model to return false")
+	}
+}
+
+func TestGetModelInfo_ReturnsCopy(t *testing.T) {
+	p, err := NewPricer()
+	if err != nil {
+		t.Fatalf("NewPricer failed: %v", err)
+	}
+
+	info, _ := p.GetModelInfo("gpt-4o")
+	info.InputModalities[0] = ModalityVideo
+
+	again, _ := p.GetModelInfo("gpt-4o")
+	if again.InputModalities[0] != ModalityText {
+		t.Errorf("internal modalities were mutated: %v", again.InputModalities)
+	}
+}
+
+func TestModelInfo_Validation(t *testing.T) {
+	tests := []struct {
+		name        string
+		fields      string
+		errContains string
+	}{
+		{"bad cutoff", `"knowledge_cutoff": "Oct 2023"`, "knowledge_cutoff"},
+		{"bad modality", `"input_modalities": ["text", "smell"]`, "invalid modality"},
+		{"negative context", `"context_window": -1`, "context_window"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			fsys := fstest.MapFS{
+				"configs/test_pricing.json": &fstest.MapFile{Data: []byte(`{
+					"models": {"m": {"input_per_million": 1.0, "output_per_million": 1.0, ` + tc.fields + `}}
+				}`)},
+			}
+			_, err := NewPricerFromFS(fsys, "configs")
+			if err == nil {
+				t.Fatalf("expected error for %s", tc.name)
+			}
+			if !strings.Contains(err.Error(), tc.errContains) {
+				t.Errorf("expected error containing %q, got: %v", tc.errContains, err)
+			}
+		})
+	}
+}
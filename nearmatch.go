@@ -0,0 +1,116 @@
+package pricing_db
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+)
+
+// NearMatches returns up to n known model names closest to model by edit
+// distance, nearest first, to spot the config entry missing for an unknown
+// model (a typo, or a new release of a known family). Only names within
+// maxNearMatchDistance edits are returned; names qualified as "provider/model"
+// are considered only when model is qualified too. It returns nil for a model
+// that resolves, since it has no missing entry.
+func (p *Pricer) NearMatches(model string, n int) []string {
+	c := p.load()
+
+	if n <= 0 || model == "" {
+		return nil
+	}
+	if _, ok := c.resolveModelKey(model); ok {
+		return nil
+	}
+	return nearestKeys(model, n, c.models)
+}
+
+// ImageNearMatches is NearMatches for image models: up to n image model names
+// closest to model, such as the resolution-specific keys ("nano-banana-pro-2k")
+// of a family name that has no default resolution. It returns nil for an image
+// model that resolves.
+func (p *Pricer) ImageNearMatches(model string, n int) []string {
+	c := p.load()
+
+	if n <= 0 || model == "" {
+		return nil
+	}
+	if _, ok := c.imageModels[model]; ok {
+		return nil
+	}
+	if _, ok := c.findImagePricingByPrefix(model); ok {
+		return nil
+	}
+	return nearestKeys(model, n, c.imageModels)
+}
+
+// nearestKeys returns up to n keys of models within maxNearMatchDistance of
+// model, nearest first.
+func nearestKeys[V any](model string, n int, models map[string]V) []string {
+	target := strings.ToLower(model)
+	qualified := strings.Contains(target, "/")
+	limit := maxNearMatchDistance(target)
+
+	type match struct {
+		key      string
+		distance int
+	}
+	var matches []match
+	for key := range models {
+		if !qualified && strings.Contains(key, "/") {
+			continue
+		}
+		if d := editDistance(target, strings.ToLower(key), limit); d <= limit {
+			matches = append(matches, match{key, d})
+		}
+	}
+	slices.SortFunc(matches, func(a, b match) int {
+		if c := cmp.Compare(a.distance, b.distance); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.key, b.key)
+	})
+
+	names := make([]string, 0, min(n, len(matches)))
+	for _, m := range matches[:min(n, len(matches))] {
+		names = append(names, m.key)
+	}
+	return names
+}
+
+// maxNearMatchDistance is the largest edit distance NearMatches reports for
+// model: a third of its length, so "gpt-4o-mini-2" matches "gpt-4o-mini" but
+// short names do not match everything, and at least 2.
+func maxNearMatchDistance(model string) int {
+	return max(2, len([]rune(model))/3)
+}
+
+// editDistance returns the Levenshtein distance between a and b, or limit+1
+// once it is certain to exceed limit.
+func editDistance(a, b string, limit int) int {
+	ra, rb := []rune(a), []rune(b)
+	if diff := len(ra) - len(rb); diff > limit || -diff > limit {
+		return limit + 1
+	}
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		rowMin := curr[0]
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			rowMin = min(rowMin, curr[j])
+		}
+		if rowMin > limit {
+			return limit + 1
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
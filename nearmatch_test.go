@@ -0,0 +1,73 @@
+package pricing_db
+
+import (
+	"slices"
+	"testing"
+)
+
+// =============================================================================
+// NearMatches Tests
+// =============================================================================
+
+var nearMatchTestConfigs = map[string]string{
+	"acme_pricing.json": `{
+		"provider": "acme",
+		"models": {
+			"acme-large": {"input_per_million": 10.0, "output_per_million": 40.0},
+			"acme-lite": {"input_per_million": 1.0, "output_per_million": 4.0},
+			"acme-small": {"input_per_million": 1.0, "output_per_million": 4.0},
+			"zephyr": {"input_per_million": 1.0, "output_per_million": 4.0}
+		}
+	}`,
+}
+
+func TestNearMatches(t *testing.T) {
+	p := newTestPricer(t, nearMatchTestConfigs)
+
+	// Nearest first, ties by name; unqualified input skips "acme/..." keys
+	if got := p.NearMatches("acme-larg", 5); !slices.Equal(got, []string{"acme-large", "acme-lite"}) {
+		t.Errorf("expected acme-large then acme-lite, got %v", got)
+	}
+	if got := p.NearMatches("ACME-SMAL", 1); !slices.Equal(got, []string{"acme-small"}) {
+		t.Errorf("expected a case-insensitive match, got %v", got)
+	}
+	if got := p.NearMatches("acme/acme-lit", 1); !slices.Equal(got, []string{"acme/acme-lite"}) {
+		t.Errorf("expected the qualified key, got %v", got)
+	}
+	if got := p.NearMatches("completely-different", 5); len(got) != 0 {
+		t.Errorf("expected no matches beyond the distance limit, got %v", got)
+	}
+}
+
+func TestNearMatches_KnownModel(t *testing.T) {
+	p := newTestPricer(t, nearMatchTestConfigs)
+	if got := p.NearMatches("acme-large", 3); got != nil {
+		t.Errorf("expected nil for a known model, got %v", got)
+	}
+	// Prefix matches resolve, so they have no missing entry either
+	if got := p.NearMatches("acme-large-2026-01-01", 3); got != nil {
+		t.Errorf("expected nil for a prefix-matched model, got %v", got)
+	}
+	if got := p.NearMatches("acme-larg", 0); got != nil {
+		t.Errorf("expected nil for n=0, got %v", got)
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b  string
+		limit int
+		want  int
+	}{
+		{"kitten", "sitting", 5, 3},
+		{"", "abc", 5, 3},
+		{"same", "same", 0, 0},
+		{"abcdef", "uvwxyz", 2, 3}, // Capped at limit+1
+		{"a", "abcdef", 2, 3},      // Length difference alone exceeds the limit
+	}
+	for _, tt := range tests {
+		if got := editDistance(tt.a, tt.b, tt.limit); got != tt.want {
+			t.Errorf("editDistance(%q, %q, %d) = %d, want %d", tt.a, tt.b, tt.limit, got, tt.want)
+		}
+	}
+}
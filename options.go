@@ -0,0 +1,272 @@
+package pricing_db
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+// Option configures a Pricer created by NewPricer or NewPricerFromFS.
+type Option func(*pricerOptions)
+
+// pricerOptions holds settings applied while loading and using a Pricer.
+type pricerOptions struct {
+	decoders          map[string]DecodeFunc // file extension (".yaml") -> decoder
+	failOnCollision   bool
+	collisionPolicy   CollisionPolicy
+	priority          []string // provider priority for CollisionProviderPriority
+	rounding          RoundingPolicy
+	cacheDefault      float64                 // 0 = defaultCacheMultiplier
+	staleAfter        time.Duration           // 0 = no staleness warnings
+	tokenCounter      TokenCounter            // nil = ApproxTokenCount
+	stampTime         bool                    // set CostDetails.CalculatedAt
+	fallback          *ModelPricing           // nil = unknown models cost 0
+	familyFallback    bool                    // price unknown models as their closest family member
+	lookupStats       bool                    // count model lookups for Pricer.Stats
+	lookupCacheSize   int                     // prefix-match results to cache; 0 = no cache
+	negativeCacheSize int                     // unknown model names to cache; 0 = no cache
+	overlay           map[string]ModelPricing // model pricing replaced after loading (WithOverlay)
+	markup            *Markup                 // nil = no default markup
+	modelMarkups      map[string]Markup       // per-model markups (WithModelMarkups)
+	taxRate           *float64                // nil = no tax line
+	logger            *slog.Logger            // nil = no diagnostic logging
+	tracer            Tracer                  // nil = no tracing
+}
+
+// DecodeFunc decodes a config document into v, which is always a pointer to an
+// empty interface. The decoded tree (maps, slices, scalars) is re-encoded as JSON
+// and parsed with the JSON field names, so YAML/TOML files use the same keys as
+// the JSON format. yaml.Unmarshal and toml.Unmarshal have this signature.
+type DecodeFunc func(data []byte, v any) error
+
+// WithDecoder registers a decoder for additional config file extensions, so
+// NewPricerFromFS also loads files like "openai_pricing.yaml".
+// Extensions may be given with or without the leading dot.
+// JSON is always supported and cannot be overridden.
+func WithDecoder(decode DecodeFunc, exts ...string) Option {
+	return func(o *pricerOptions) {
+		if o.decoders == nil {
+			o.decoders = make(map[string]DecodeFunc)
+		}
+		for _, ext := range exts {
+			ext = strings.ToLower(ext)
+			if !strings.HasPrefix(ext, ".") {
+				ext = "." + ext
+			}
+			if ext == ".json" {
+				continue
+			}
+			o.decoders[ext] = decode
+		}
+	}
+}
+
+// WithFailOnCollision makes NewPricerFromFS return an error when a bare
+// model name is defined by more than one provider, instead of silently
+// keeping the first. Intended for config maintainers and CI checks;
+// see Pricer.Collisions for a non-fatal report.
+func WithFailOnCollision() Option {
+	return func(o *pricerOptions) {
+		o.failOnCollision = true
+	}
+}
+
+// WithCollisionPolicy sets how bare model names defined by several providers
+// are resolved (default CollisionKeepFirst). For CollisionProviderPriority,
+// pass the providers in order of preference, e.g.
+//
+//	WithCollisionPolicy(CollisionProviderPriority, "deepinfra", "together")
+//
+// The priority list is ignored by the other policies.
+func WithCollisionPolicy(policy CollisionPolicy, priority ...string) Option {
+	return func(o *pricerOptions) {
+		o.collisionPolicy = policy
+		o.priority = priority
+	}
+}
+
+// WithProviderPriority resolves bare model names defined by several providers
+// to the first provider in the list that defines them, matching the caller's
+// routing preference instead of alphabetical file order. Applies to token and
+// image models; providers not in the list are only used when no listed provider
+// defines the model, and unknown provider names are ignored.
+// Shorthand for WithCollisionPolicy(CollisionProviderPriority, providers...).
+func WithProviderPriority(providers []string) Option {
+	return WithCollisionPolicy(CollisionProviderPriority, providers...)
+}
+
+// WithRoundingPolicy sets the precision and rounding mode for cost totals
+// (default DefaultRoundingPolicy: 9 decimal places, half-up), so totals match
+// an invoicing system's rules. For example, to round up to whole cents:
+//
+//	WithRoundingPolicy(RoundingPolicy{Precision: 2, Mode: RoundCeiling})
+func WithRoundingPolicy(policy RoundingPolicy) Option {
+	return func(o *pricerOptions) {
+		o.rounding = policy
+	}
+}
+
+// WithDefaultCacheMultiplier overrides the global cache_read_multiplier fallback
+// (default 0.10) used for models whose config and provider set none.
+// Per-provider fallbacks come from default_cache_read_multiplier in the config.
+// The multiplier must be in (0, 1].
+func WithDefaultCacheMultiplier(multiplier float64) Option {
+	return func(o *pricerOptions) {
+		o.cacheDefault = multiplier
+	}
+}
+
+// WithStalenessWarning adds a WarningStalePricing warning to cost calculations
+// for models whose provider's metadata.updated date is older than maxAge, so
+// services notice when they are running on stale compiled-in prices.
+// Providers without a valid updated date are treated as stale.
+func WithStalenessWarning(maxAge time.Duration) Option {
+	return func(o *pricerOptions) {
+		o.staleAfter = maxAge
+	}
+}
+
+// WithTokenCounter sets the tokenizer used by CountTokens and EstimateTextCost,
+// replacing the ApproxTokenCount heuristic. Use it to plug in an exact tokenizer
+// (e.g., tiktoken for OpenAI models); fn must be safe for concurrent use.
+func WithTokenCounter(fn TokenCounter) Option {
+	return func(o *pricerOptions) {
+		o.tokenCounter = fn
+	}
+}
+
+// WithCalculationTimestamps sets CostDetails.CalculatedAt on every result, so
+// stored cost records show when, as well as under which PricingVersion, they
+// were priced. Off by default because it reads the clock on every calculation.
+func WithCalculationTimestamps() Option {
+	return func(o *pricerOptions) {
+		o.stampTime = true
+	}
+}
+
+// WithFallbackPricing prices token usage of unknown models at pricing instead
+// of 0, so cost dashboards show a conservative estimate when a new model ships
+// before the config update. Results are still flagged Unknown and carry a
+// WarningFallbackPricing warning; the lookup functions (GetPricing,
+// GetModelInfo, ...) still report the model as not found. Choose rates at or
+// above the most expensive model you expect to see.
+func WithFallbackPricing(pricing ModelPricing) Option {
+	return func(o *pricerOptions) {
+		pricing = copyModelPricing(pricing)
+		o.fallback = &pricing
+	}
+}
+
+// WithFamilyFallback prices an unknown versioned model as its closest known
+// family member, so estimates stay sane while providers churn through preview
+// names: "gemini-3.1-flash-exp" is priced as gemini-3-flash, the newest
+// gemini-*-flash at or below version 3.1 (or the oldest above it, if none).
+// Results are still flagged Unknown and carry a WarningFallbackApplied warning
+// naming the member used. It takes precedence over WithFallbackPricing.
+func WithFamilyFallback() Option {
+	return func(o *pricerOptions) {
+		o.familyFallback = true
+	}
+}
+
+// WithLookupStats counts model lookups (exact hits, prefix hits, misses) and
+// reports them in Pricer.Stats. Off by default because every lookup then
+// updates shared atomic counters. The counters carry over a Reload that also
+// passes WithLookupStats.
+func WithLookupStats() Option {
+	return func(o *pricerOptions) {
+		o.lookupStats = true
+	}
+}
+
+// WithLookupCache caches up to size model names resolved by prefix matching
+// (e.g. "gpt-4o-2024-08-06" -> "gpt-4o") in an LRU, so repeated versioned
+// names skip the scan over every catalog key. Exact matches and unknown models
+// are not cached. The cache is emptied by Reload; size 0 disables it.
+func WithLookupCache(size int) Option {
+	return func(o *pricerOptions) {
+		o.lookupCacheSize = size
+	}
+}
+
+// WithTaxRate adds a tax line to CostDetails at rate (e.g. 0.20 for 20% VAT):
+// Tax is charged on BilledTotal, and GrossTotal is the tax-inclusive amount
+// that downstream invoicing needs. The rate must be in [0, 1].
+func WithTaxRate(rate float64) Option {
+	return func(o *pricerOptions) {
+		o.taxRate = &rate
+	}
+}
+
+// defaultNegativeCacheSize is the number of unknown model names remembered
+// unless WithNegativeCache says otherwise.
+const defaultNegativeCacheSize = 256
+
+// WithNegativeCache sets how many unknown model names are remembered (default
+// 256), so a caller repeating a name that matches nothing does not pay a scan
+// over every catalog key on each call. Lookups read the cache without locking;
+// when it fills up it is emptied and starts over. Only token-priced model
+// lookups are cached. The cache is emptied by Reload; size 0 disables it.
+func WithNegativeCache(size int) Option {
+	return func(o *pricerOptions) {
+		o.negativeCacheSize = size
+	}
+}
+
+// newPricerOptions applies opts over the defaults.
+func newPricerOptions(opts []Option) *pricerOptions {
+	o := &pricerOptions{
+		collisionPolicy:   CollisionKeepFirst,
+		rounding:          DefaultRoundingPolicy,
+		negativeCacheSize: defaultNegativeCacheSize,
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(o)
+		}
+	}
+	return o
+}
+
+// pricingFileSuffix reports whether name is a loadable pricing config and
+// returns its "_pricing<ext>" suffix.
+func (o *pricerOptions) pricingFileSuffix(name string) (string, bool) {
+	if strings.HasSuffix(name, "_pricing.json") {
+		return "_pricing.json", true
+	}
+	for ext := range o.decoders {
+		if suffix := "_pricing" + ext; strings.HasSuffix(strings.ToLower(name), suffix) {
+			return name[len(name)-len(suffix):], true
+		}
+	}
+	return "", false
+}
+
+// decodePricingFile parses a config file with the decoder for ext.
+// Non-JSON documents are normalized through JSON so the pricingFile
+// field tags apply uniformly.
+func (o *pricerOptions) decodePricingFile(data []byte, ext string) (pricingFile, error) {
+	var file pricingFile
+	ext = strings.ToLower(ext)
+	if ext == ".json" {
+		err := json.Unmarshal(data, &file)
+		return file, err
+	}
+
+	decode, ok := o.decoders[ext]
+	if !ok {
+		return file, fmt.Errorf("no decoder registered for %q", ext)
+	}
+	var tree any
+	if err := decode(data, &tree); err != nil {
+		return file, err
+	}
+	normalized, err := json.Marshal(tree)
+	if err != nil {
+		return file, fmt.Errorf("normalize %s document: %w", ext, err)
+	}
+	err = json.Unmarshal(normalized, &file)
+	return file, err
+}
@@ -0,0 +1,234 @@
+package pricing_db
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// =============================================================================
+// Config Decoder Option Tests
+// =============================================================================
+
+// fakeDecode stands in for a YAML/TOML decoder: it parses JSON so the tests
+// exercise the decoder plumbing without pulling in a third-party parser.
+func fakeDecode(data []byte, v any) error {
+	return json.Unmarshal(data, v)
+}
+
+func TestNewPricerFromFS_WithDecoder(t *testing.T) {
+	fsys := fstest.MapFS{
+		"configs/openai_pricing.json": &fstest.MapFile{Data: []byte(`{
+			"provider": "openai",
+			"models": {"gpt-test": {"input_per_million": 1.0, "output_per_million": 2.0}}
+		}`)},
+		"configs/acme_pricing.yaml": &fstest.MapFile{Data: []byte(`{
+			"models": {"acme-large": {"input_per_million": 3.0, "output_per_million": 6.0}}
+		}`)},
+		"configs/other_pricing.TOML": &fstest.MapFile{Data: []byte(`{
+			"models": {"other-small": {"input_per_million": 0.5, "output_per_million": 1.0}}
+		}`)},
+	}
+
+	p, err := NewPricerFromFS(fsys, "configs", WithDecoder(fakeDecode, "yaml", ".toml"))
+	if err != nil {
+		t.Fatalf("NewPricerFromFS failed: %v", err)
+	}
+
+	if p.ProviderCount() != 3 {
+		t.Errorf("expected 3 providers, got %d", p.ProviderCount())
+	}
+	cost := p.Calculate("acme-large", 1_000_000, 1_000_000)
+	if !floatEquals(cost.TotalCost, 9.0) {
+		t.Errorf("expected acme-large total $9.00, got $%f", cost.TotalCost)
+	}
+	// Provider name is inferred from the filename even with an upper-case extension
+	if info, ok := p.GetModelInfo("other-small"); !ok || info.Provider != "other" {
+		t.Errorf("expected other-small from provider other, got %+v (ok=%v)", info, ok)
+	}
+}
+
+func TestNewPricerFromFS_UnregisteredExtensionIgnored(t *testing.T) {
+	fsys := fstest.MapFS{
+		"configs/openai_pricing.json": &fstest.MapFile{Data: []byte(`{
+			"models": {"gpt-test": {"input_per_million": 1.0, "output_per_million": 2.0}}
+		}`)},
+		"configs/acme_pricing.yaml": &fstest.MapFile{Data: []byte(`not: parsed`)},
+	}
+
+	p, err := NewPricerFromFS(fsys, "configs")
+	if err != nil {
+		t.Fatalf("NewPricerFromFS failed: %v", err)
+	}
+	if p.ProviderCount() != 1 {
+		t.Errorf("expected YAML file to be skipped without a decoder, got %d providers", p.ProviderCount())
+	}
+}
+
+func TestNewPricerFromFS_DecoderError(t *testing.T) {
+	fsys := fstest.MapFS{
+		"configs/acme_pricing.yaml": &fstest.MapFile{Data: []byte(`whatever`)},
+	}
+	failing := func(data []byte, v any) error { return errors.New("boom") }
+
+	_, err := NewPricerFromFS(fsys, "configs", WithDecoder(failing, ".yaml"))
+	if err == nil || !strings.Contains(err.Error(), "acme_pricing.yaml") {
+		t.Errorf("expected parse error naming the file, got %v", err)
+	}
+}
+
+func TestWithDecoder_CannotOverrideJSON(t *testing.T) {
+	fsys := fstest.MapFS{
+		"configs/openai_pricing.json": &fstest.MapFile{Data: []byte(`{
+			"models": {"gpt-test": {"input_per_million": 1.0, "output_per_million": 2.0}}
+		}`)},
+	}
+	failing := func(data []byte, v any) error { return errors.New("boom") }
+
+	if _, err := NewPricerFromFS(fsys, "configs", WithDecoder(failing, ".json")); err != nil {
+		t.Errorf("expected JSON decoding to be unaffected, got %v", err)
+	}
+}
+
+// =============================================================================
+// Default Cache Multiplier Tests
+// =============================================================================
+
+func cacheDefaultFS() fstest.MapFS {
+	return fstest.MapFS{
+		"configs/plain_pricing.json": &fstest.MapFile{Data: []byte(`{
+			"models": {"plain-model": {"input_per_million": 10.0, "output_per_million": 10.0}}
+		}`)},
+		"configs/halfoff_pricing.json": &fstest.MapFile{Data: []byte(`{
+			"default_cache_read_multiplier": 0.5,
+			"models": {
+				"halfoff-model": {"input_per_million": 10.0, "output_per_million": 10.0},
+				"explicit-model": {"input_per_million": 10.0, "output_per_million": 10.0, "cache_read_multiplier": 0.25}
+			}
+		}`)},
+	}
+}
+
+func TestDefaultCacheMultiplier_ProviderFallback(t *testing.T) {
+	p, err := NewPricerFromFS(cacheDefaultFS(), "configs")
+	if err != nil {
+		t.Fatalf("NewPricerFromFS failed: %v", err)
+	}
+
+	tests := []struct {
+		model    string
+		expected float64 // cost of 1M cached tokens at $10/M
+	}{
+		{"plain-model", 1.0},    // global default 10%
+		{"halfoff-model", 5.0},  // provider default 50%
+		{"explicit-model", 2.5}, // model setting wins
+	}
+	for _, tc := range tests {
+		cost := p.CalculateWithOptions(tc.model, 1_000_000, 0, 1_000_000, nil)
+		if !floatEquals(cost.CachedInputCost, tc.expected) {
+			t.Errorf("%s: CachedInputCost = %f, want %f", tc.model, cost.CachedInputCost, tc.expected)
+		}
+	}
+
+	pricing, _ := p.GetPricing("halfoff-model")
+	if pricing.CacheReadMultiplier != 0.5 {
+		t.Errorf("expected provider default filled into model pricing, got %f", pricing.CacheReadMultiplier)
+	}
+	meta, _ := p.GetProviderMetadata("halfoff")
+	if meta.DefaultCacheReadMultiplier != 0.5 {
+		t.Errorf("expected provider metadata to report default 0.5, got %f", meta.DefaultCacheReadMultiplier)
+	}
+}
+
+func TestWithDefaultCacheMultiplier(t *testing.T) {
+	p, err := NewPricerFromFS(cacheDefaultFS(), "configs", WithDefaultCacheMultiplier(0.25))
+	if err != nil {
+		t.Fatalf("NewPricerFromFS failed: %v", err)
+	}
+
+	if cost := p.CalculateWithOptions("plain-model", 1_000_000, 0, 1_000_000, nil); !floatEquals(cost.CachedInputCost, 2.5) {
+		t.Errorf("plain-model: CachedInputCost = %f, want 2.5 with global override", cost.CachedInputCost)
+	}
+	// Provider default still takes precedence over the global override
+	if cost := p.CalculateWithOptions("halfoff-model", 1_000_000, 0, 1_000_000, nil); !floatEquals(cost.CachedInputCost, 5.0) {
+		t.Errorf("halfoff-model: CachedInputCost = %f, want 5.0", cost.CachedInputCost)
+	}
+}
+
+func TestDefaultCacheMultiplier_OutOfRange(t *testing.T) {
+	if _, err := NewPricerFromFS(cacheDefaultFS(), "configs", WithDefaultCacheMultiplier(1.5)); err == nil {
+		t.Error("expected error for global default cache multiplier > 1")
+	}
+
+	fsys := fstest.MapFS{
+		"configs/bad_pricing.json": &fstest.MapFile{Data: []byte(`{
+			"default_cache_read_multiplier": -0.1,
+			"models": {"m": {"input_per_million": 1.0, "output_per_million": 1.0}}
+		}`)},
+	}
+	_, err := NewPricerFromFS(fsys, "configs")
+	if err == nil || !strings.Contains(err.Error(), "default_cache_read_multiplier") {
+		t.Errorf("expected default_cache_read_multiplier range error, got %v", err)
+	}
+}
+
+// =============================================================================
+// Fallback Pricing Tests
+// =============================================================================
+
+func TestWithFallbackPricing(t *testing.T) {
+	fallback := ModelPricing{InputPerMillion: 20.0, OutputPerMillion: 80.0, BatchMultiplier: 0.5}
+	p, err := NewPricerFromFS(cacheDefaultFS(), "configs", WithFallbackPricing(fallback))
+	if err != nil {
+		t.Fatalf("NewPricerFromFS failed: %v", err)
+	}
+
+	details := p.CalculateUsage("brand-new-model", TokenUsage{PromptTokens: 1_000_000, CompletionTokens: 1_000_000}, nil)
+	if !details.Unknown || !floatEquals(details.TotalCost, 100.0) {
+		t.Errorf("expected Unknown at fallback rates ($100), got %+v", details)
+	}
+	if len(details.WarningDetails) != 1 || details.WarningDetails[0].Code != WarningFallbackPricing {
+		t.Errorf("expected a fallback_pricing warning, got %+v", details.WarningDetails)
+	}
+	batch := p.CalculateUsage("brand-new-model", TokenUsage{PromptTokens: 1_000_000}, &CalculateOptions{BatchMode: true})
+	if !floatEquals(batch.TotalCost, 10.0) {
+		t.Errorf("expected the fallback batch discount, got %f", batch.TotalCost)
+	}
+
+	cost := p.Calculate("brand-new-model", 1_000_000, 0)
+	if !cost.Unknown || !floatEquals(cost.TotalCost, 20.0) || len(cost.WarningDetails) != 1 {
+		t.Errorf("expected Calculate to use fallback rates, got %+v", cost)
+	}
+	realtime := p.CalculateRealtimeSession("brand-new-model", RealtimeUsage{TextOutputTokens: 1_000_000})
+	if !realtime.Unknown || !floatEquals(realtime.TotalCost, 80.0) {
+		t.Errorf("expected the realtime session at fallback rates, got %+v", realtime)
+	}
+
+	// Known models and lookups are unaffected
+	if known := p.CalculateUsage("plain-model", TokenUsage{PromptTokens: 1_000_000}, nil); known.Unknown || !floatEquals(known.TotalCost, 10.0) {
+		t.Errorf("expected plain-model at its own rates, got %+v", known)
+	}
+	if _, ok := p.GetPricing("brand-new-model"); ok {
+		t.Error("expected GetPricing to report an unknown model as not found")
+	}
+}
+
+func TestWithFallbackPricing_Invalid(t *testing.T) {
+	_, err := NewPricerFromFS(cacheDefaultFS(), "configs", WithFallbackPricing(ModelPricing{InputPerMillion: -1}))
+	if err == nil || !strings.Contains(err.Error(), "WithFallbackPricing") {
+		t.Errorf("expected a fallback pricing validation error, got %v", err)
+	}
+}
+
+func TestWithoutFallbackPricing(t *testing.T) {
+	p, err := NewPricerFromFS(cacheDefaultFS(), "configs")
+	if err != nil {
+		t.Fatalf("NewPricerFromFS failed: %v", err)
+	}
+	details := p.CalculateUsage("brand-new-model", TokenUsage{PromptTokens: 1_000_000}, nil)
+	if !details.Unknown || details.TotalCost != 0 || len(details.Warnings) != 0 {
+		t.Errorf("expected an unpriced Unknown result, got %+v", details)
+	}
+}
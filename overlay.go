@@ -0,0 +1,142 @@
+package pricing_db
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// WithOverlay replaces the pricing of existing catalog models, e.g. to apply
+// a tenant's negotiated discount. Keys are catalog keys: a bare name such as
+// "gpt-4o" also overrides its provider's "openai/gpt-4o" entry (and the other
+// way round) unless the overlay sets that key too. Each entry replaces the
+// model's pricing as a whole, so start from GetPricing and change the rates
+// that differ. Unknown keys and invalid pricing are load errors.
+//
+// Use it with Clone to derive per-tenant pricers from one base catalog, or
+// with NewPricer and Reload for a pricer that always carries the overlay.
+func WithOverlay(overlay map[string]ModelPricing) Option {
+	return func(o *pricerOptions) {
+		if o.overlay == nil {
+			o.overlay = make(map[string]ModelPricing, len(overlay))
+		}
+		for key, pricing := range overlay {
+			o.overlay[key] = copyModelPricing(pricing)
+		}
+	}
+}
+
+// Clone returns a new Pricer sharing p's current catalog, with any WithOverlay
+// options applied on top and any WithMarkup, WithModelMarkups or WithTaxRate
+// options replacing p's settings. Catalogs are immutable, so the clone costs only the
+// overlaid entries and a copy of the model index maps; the pricing data, key
+// indexes and lookup caches are shared, while WithLookupStats counters start
+// at zero. Later Reloads of p or the clone do not affect the other. Other
+// options apply only when loading and are ignored.
+//
+// An overlaid clone reports a Version derived from p's version and the overlay.
+// Provider-level views (GetProviderMetadata, Providers) still show the base
+// catalog's rates.
+func (p *Pricer) Clone(opts ...Option) (*Pricer, error) {
+	c := p.load()
+	o := newPricerOptions(opts)
+	if len(o.overlay) > 0 {
+		var err error
+		if c, err = c.withOverlay(o.overlay); err != nil {
+			return nil, err
+		}
+	} else {
+		// Lookup counters are per Pricer
+		next := *c
+		next.lookups = newLookupCounters(c.lookups != nil)
+		c = &next
+	}
+	if o.markup != nil || len(o.modelMarkups) > 0 {
+		markup, err := c.newMarkupTable(o.markup, o.modelMarkups)
+		if err != nil {
+			return nil, err
+		}
+		c.markup = markup
+	}
+	if o.taxRate != nil {
+		if err := validateTaxRate(o.taxRate); err != nil {
+			return nil, err
+		}
+		c.taxRate = *o.taxRate
+	}
+	clone := &Pricer{}
+	clone.cat.Store(c)
+	return clone, nil
+}
+
+// withOverlay returns a copy of c with overlay's model pricing compiled in.
+func (c *catalog) withOverlay(overlay map[string]ModelPricing) (*catalog, error) {
+	entries := make(map[string]ModelPricing, len(overlay))
+	for _, key := range slices.Sorted(maps.Keys(overlay)) {
+		if _, ok := c.models[key]; !ok {
+			return nil, fmt.Errorf("overlay: model %q not in catalog", key)
+		}
+		pricing := copyModelPricing(overlay[key])
+		if err := validateModelPricing(key, pricing, "overlay"); err != nil {
+			return nil, err
+		}
+		sortTiers(pricing.Tiers)
+		if pricing.CacheReadMultiplier == 0 {
+			pricing.CacheReadMultiplier = c.providers[c.modelProviders[key]].DefaultCacheReadMultiplier
+		}
+		entries[key] = pricing
+		if alias, ok := c.overlayAlias(key); ok {
+			if _, set := overlay[alias]; !set {
+				entries[alias] = pricing
+			}
+		}
+	}
+
+	next := *c
+	next.models = maps.Clone(c.models)
+	next.rates = maps.Clone(c.rates)
+	for key, pricing := range entries {
+		r := compileRates(pricing, c.cacheDefault)
+		next.models[key] = pricing
+		next.rates[key] = r
+		next.resolveModelSurcharges(key, r)
+		next.annotateProvider(key, r)
+	}
+	next.lookups = newLookupCounters(c.lookups != nil)
+	next.version = overlayVersion(c.version, entries)
+	return &next, nil
+}
+
+// overlayAlias returns the other catalog key for the same provider entry:
+// "provider/model" for a bare name, or the bare name for a namespaced key
+// when the bare name resolves to that provider.
+func (c *catalog) overlayAlias(key string) (string, bool) {
+	provider := c.modelProviders[key]
+	if bare, ok := strings.CutPrefix(key, provider+"/"); ok {
+		if c.modelProviders[bare] == provider {
+			return bare, true
+		}
+		return "", false
+	}
+	alias := provider + "/" + key
+	if _, ok := c.models[alias]; ok {
+		return alias, true
+	}
+	return "", false
+}
+
+// overlayVersion derives a catalog version from the base version and the
+// overlaid entries.
+func overlayVersion(base string, entries map[string]ModelPricing) string {
+	data, err := json.Marshal(entries)
+	if err != nil {
+		// ModelPricing holds only plain values, so this is unreachable in practice.
+		return base
+	}
+	sum := sha256.Sum256(append([]byte(base+"\n"), data...))
+	return hex.EncodeToString(sum[:8])
+}
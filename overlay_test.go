@@ -0,0 +1,129 @@
+package pricing_db
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// =============================================================================
+// Clone / Overlay Tests
+// =============================================================================
+
+func overlayFS() fstest.MapFS {
+	return fstest.MapFS{
+		"configs/acme_pricing.json": &fstest.MapFile{Data: []byte(`{
+			"provider": "acme",
+			"default_cache_read_multiplier": 0.25,
+			"models": {
+				"large": {"input_per_million": 10.0, "output_per_million": 30.0, "batch_multiplier": 0.5},
+				"small": {"input_per_million": 1.0, "output_per_million": 2.0}
+			}
+		}`)},
+	}
+}
+
+func TestClone_OverlayOverridesRates(t *testing.T) {
+	base, err := NewPricerFromFS(overlayFS(), "configs")
+	if err != nil {
+		t.Fatalf("NewPricerFromFS failed: %v", err)
+	}
+
+	discounted, _ := base.GetPricing("large")
+	discounted.InputPerMillion = 8.0
+	discounted.CacheReadMultiplier = 0
+	tenant, err := base.Clone(WithOverlay(map[string]ModelPricing{"large": discounted}))
+	if err != nil {
+		t.Fatalf("Clone failed: %v", err)
+	}
+
+	if cost := tenant.Calculate("large", 1_000_000, 0); !floatEquals(cost.InputCost, 8.0) {
+		t.Errorf("expected tenant input cost $8.00, got $%f", cost.InputCost)
+	}
+	// The provider/model alias and prefix matches follow the overlay
+	if cost := tenant.Calculate("acme/large-2025-01-01", 1_000_000, 0); !floatEquals(cost.InputCost, 8.0) {
+		t.Errorf("expected aliased tenant input cost $8.00, got $%f", cost.InputCost)
+	}
+	// The provider's default cache multiplier still fills an unset one
+	if pricing, _ := tenant.GetPricing("large"); !floatEquals(pricing.CacheReadMultiplier, 0.25) {
+		t.Errorf("expected provider default cache multiplier 0.25, got %f", pricing.CacheReadMultiplier)
+	}
+	// Models outside the overlay and the base pricer are unchanged
+	if cost := tenant.Calculate("small", 1_000_000, 0); !floatEquals(cost.InputCost, 1.0) {
+		t.Errorf("expected small input cost $1.00, got $%f", cost.InputCost)
+	}
+	if cost := base.Calculate("large", 1_000_000, 0); !floatEquals(cost.InputCost, 10.0) {
+		t.Errorf("expected base input cost $10.00, got $%f", cost.InputCost)
+	}
+	if tenant.Version() == base.Version() {
+		t.Error("expected the overlaid clone to report a different version")
+	}
+}
+
+func TestClone_ExplicitAliasWins(t *testing.T) {
+	base, err := NewPricerFromFS(overlayFS(), "configs")
+	if err != nil {
+		t.Fatalf("NewPricerFromFS failed: %v", err)
+	}
+
+	tenant, err := base.Clone(WithOverlay(map[string]ModelPricing{
+		"large":      {InputPerMillion: 8.0, OutputPerMillion: 30.0},
+		"acme/large": {InputPerMillion: 9.0, OutputPerMillion: 30.0},
+	}))
+	if err != nil {
+		t.Fatalf("Clone failed: %v", err)
+	}
+	if cost := tenant.Calculate("acme/large", 1_000_000, 0); !floatEquals(cost.InputCost, 9.0) {
+		t.Errorf("expected explicit alias input cost $9.00, got $%f", cost.InputCost)
+	}
+}
+
+func TestClone_Errors(t *testing.T) {
+	base, err := NewPricerFromFS(overlayFS(), "configs")
+	if err != nil {
+		t.Fatalf("NewPricerFromFS failed: %v", err)
+	}
+
+	if _, err := base.Clone(WithOverlay(map[string]ModelPricing{"missing": {InputPerMillion: 1}})); err == nil || !strings.Contains(err.Error(), "not in catalog") {
+		t.Errorf("expected unknown model error, got %v", err)
+	}
+	if _, err := base.Clone(WithOverlay(map[string]ModelPricing{"large": {InputPerMillion: -1}})); err == nil {
+		t.Error("expected error for negative overlay price")
+	}
+}
+
+func TestClone_IndependentReload(t *testing.T) {
+	base, err := NewPricerFromFS(reloadFS("1.0"), "configs")
+	if err != nil {
+		t.Fatalf("NewPricerFromFS failed: %v", err)
+	}
+	clone, err := base.Clone()
+	if err != nil {
+		t.Fatalf("Clone failed: %v", err)
+	}
+	if clone.Version() != base.Version() {
+		t.Error("expected a plain clone to share the base version")
+	}
+
+	if err := base.Reload(reloadFS("2.0"), "configs"); err != nil {
+		t.Fatalf("Reload failed: %v", err)
+	}
+	if cost := clone.Calculate("m", 1_000_000, 0); !floatEquals(cost.InputCost, 1.0) {
+		t.Errorf("expected the clone to keep $1.00 after the base reloaded, got $%f", cost.InputCost)
+	}
+}
+
+func TestWithOverlay_AppliesOnLoad(t *testing.T) {
+	overlay := WithOverlay(map[string]ModelPricing{"small": {InputPerMillion: 0.5, OutputPerMillion: 2.0}})
+	p, err := NewPricerFromFS(overlayFS(), "configs", overlay)
+	if err != nil {
+		t.Fatalf("NewPricerFromFS failed: %v", err)
+	}
+	if cost := p.Calculate("small", 1_000_000, 0); !floatEquals(cost.InputCost, 0.5) {
+		t.Errorf("expected overlaid input cost $0.50, got $%f", cost.InputCost)
+	}
+
+	if _, err := NewPricerFromFS(overlayFS(), "configs", WithOverlay(map[string]ModelPricing{"missing": {}})); err == nil {
+		t.Error("expected load error for unknown overlay model")
+	}
+}
@@ -0,0 +1,183 @@
+package parquetio
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+var errTruncated = errors.New("truncated page data")
+
+// maxDeltaBlock bounds the values per DELTA_BINARY_PACKED block; writers use
+// 128 to a few thousand.
+const maxDeltaBlock = 1 << 20
+
+// readBits reads width bits (at most 64) starting at bit pos of b, least
+// significant bit first, as Parquet packs levels, indexes and deltas.
+func readBits(b []byte, pos uint64, width int) uint64 {
+	var v uint64
+	for i := 0; i < width; {
+		p := pos + uint64(i)
+		shift := int(p % 8)
+		take := min(8-shift, width-i)
+		v |= uint64(b[p/8]>>shift&(1<<take-1)) << i
+		i += take
+	}
+	return v
+}
+
+// decodeHybrid decodes n values of the RLE / bit-packing hybrid encoding used
+// for definition levels, dictionary indexes and RLE booleans.
+func decodeHybrid(b []byte, bitWidth, n int) ([]uint32, error) {
+	if bitWidth < 0 || bitWidth > 32 {
+		return nil, fmt.Errorf("invalid bit width %d", bitWidth)
+	}
+	// Runs may expand far beyond their bytes, so n (bounded by the caller) is
+	// not trusted for the initial allocation.
+	out := make([]uint32, 0, min(n, 8*len(b)))
+	byteWidth := (bitWidth + 7) / 8
+	for len(out) < n {
+		h, k := binary.Uvarint(b)
+		if k <= 0 {
+			return nil, errTruncated
+		}
+		b = b[k:]
+		remaining := uint64(n - len(out))
+		if h&1 == 0 {
+			// RLE run: a count, then the repeated value in byteWidth bytes.
+			if len(b) < byteWidth {
+				return nil, errTruncated
+			}
+			var v uint32
+			for i := range byteWidth {
+				v |= uint32(b[i]) << (8 * i)
+			}
+			b = b[byteWidth:]
+			for range min(h>>1, remaining) {
+				out = append(out, v)
+			}
+			continue
+		}
+		// Bit-packed run: groups of 8 values, bitWidth bytes per group.
+		groups := h >> 1
+		if groups > uint64(len(b)) && bitWidth > 0 {
+			return nil, errTruncated
+		}
+		size := groups * uint64(bitWidth)
+		if size > uint64(len(b)) {
+			return nil, errTruncated
+		}
+		for i := range min(min(groups, remaining)*8, remaining) {
+			out = append(out, uint32(readBits(b, i*uint64(bitWidth), bitWidth)))
+		}
+		b = b[size:]
+	}
+	return out, nil
+}
+
+// decodeDeltaBinaryPacked decodes n DELTA_BINARY_PACKED integers and returns
+// them with the bytes that follow.
+func decodeDeltaBinaryPacked(b []byte, n int) ([]int64, []byte, error) {
+	r := &thriftReader{b: b}
+	blockSize := r.uvarint()
+	miniblocks := r.uvarint()
+	total := r.uvarint()
+	first := r.i64()
+	if r.err != nil {
+		return nil, nil, errTruncated
+	}
+	if blockSize == 0 || blockSize > maxDeltaBlock || blockSize%128 != 0 || miniblocks == 0 || blockSize%miniblocks != 0 || (blockSize/miniblocks)%32 != 0 {
+		return nil, nil, fmt.Errorf("invalid delta block of %d values in %d miniblocks", blockSize, miniblocks)
+	}
+	if total != uint64(n) {
+		return nil, nil, fmt.Errorf("delta block holds %d values, want %d", total, n)
+	}
+	values := make([]int64, 0, min(n, 8*len(r.b)+1))
+	if n == 0 {
+		return values, r.b, nil
+	}
+	perMini := blockSize / miniblocks
+	values = append(values, first)
+	prev := first
+	for len(values) < n {
+		minDelta := r.i64()
+		if r.err != nil || uint64(len(r.b)) < miniblocks {
+			return nil, nil, errTruncated
+		}
+		widths := r.b[:miniblocks]
+		r.b = r.b[miniblocks:]
+		for _, w := range widths {
+			if len(values) >= n {
+				break
+			}
+			if w > 64 {
+				return nil, nil, fmt.Errorf("invalid delta bit width %d", w)
+			}
+			size := perMini * uint64(w) / 8
+			if size > uint64(len(r.b)) {
+				return nil, nil, errTruncated
+			}
+			for i := uint64(0); i < perMini && len(values) < n; i++ {
+				prev = int64(uint64(prev) + uint64(minDelta) + readBits(r.b, i*uint64(w), int(w)))
+				values = append(values, prev)
+			}
+			r.b = r.b[size:]
+		}
+	}
+	return values, r.b, nil
+}
+
+// decodeDeltaLengthByteArray decodes n DELTA_LENGTH_BYTE_ARRAY values.
+func decodeDeltaLengthByteArray(b []byte, n int) ([][]byte, error) {
+	lengths, b, err := decodeDeltaBinaryPacked(b, n)
+	if err != nil {
+		return nil, err
+	}
+	values := make([][]byte, n)
+	for i, l := range lengths {
+		if l < 0 || l > int64(len(b)) {
+			return nil, errTruncated
+		}
+		values[i], b = b[:l], b[l:]
+	}
+	return values, nil
+}
+
+// decodeDeltaByteArray decodes n DELTA_BYTE_ARRAY values: each shares a
+// prefix of the given length with the previous value.
+func decodeDeltaByteArray(b []byte, n int) ([][]byte, error) {
+	prefixes, b, err := decodeDeltaBinaryPacked(b, n)
+	if err != nil {
+		return nil, err
+	}
+	suffixes, err := decodeDeltaLengthByteArray(b, n)
+	if err != nil {
+		return nil, err
+	}
+	values := make([][]byte, n)
+	var prev []byte
+	for i, p := range prefixes {
+		if p < 0 || p > int64(len(prev)) {
+			return nil, fmt.Errorf("invalid delta prefix length %d", p)
+		}
+		v := make([]byte, 0, int(p)+len(suffixes[i]))
+		v = append(append(v, prev[:p]...), suffixes[i]...)
+		values[i], prev = v, v
+	}
+	return values, nil
+}
+
+// unsplitStreams reverses BYTE_STREAM_SPLIT for n values of width bytes,
+// returning them in PLAIN layout.
+func unsplitStreams(b []byte, n, width int) ([]byte, error) {
+	if len(b) < n*width {
+		return nil, errTruncated
+	}
+	out := make([]byte, n*width)
+	for i := range n {
+		for j := range width {
+			out[i*width+j] = b[j*n+i]
+		}
+	}
+	return out, nil
+}
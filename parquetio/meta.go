@@ -0,0 +1,308 @@
+package parquetio
+
+// Physical types.
+const (
+	typeBoolean   = 0
+	typeInt32     = 1
+	typeInt64     = 2
+	typeInt96     = 3
+	typeFloat     = 4
+	typeDouble    = 5
+	typeByteArray = 6
+	typeFixedLen  = 7
+)
+
+// Field repetition types.
+const (
+	repRequired = 0
+	repOptional = 1
+	repRepeated = 2
+)
+
+// Converted (legacy logical) types that change how a value reads.
+const (
+	convertedUTF8            = 0
+	convertedDecimal         = 5
+	convertedDate            = 6
+	convertedTimestampMillis = 9
+	convertedTimestampMicros = 10
+	convertedUint8           = 11
+	convertedUint64          = 14
+)
+
+// LogicalType union members, by field id.
+const (
+	logicalString    = 1
+	logicalDecimal   = 5
+	logicalDate      = 6
+	logicalTimestamp = 8
+	logicalInteger   = 10
+)
+
+// TimeUnit union members, by field id.
+const (
+	unitMillis = 1
+	unitMicros = 2
+	unitNanos  = 3
+)
+
+// Encodings.
+const (
+	encPlain                = 0
+	encPlainDictionary      = 2
+	encRLE                  = 3
+	encDeltaBinaryPacked    = 5
+	encDeltaLengthByteArray = 6
+	encDeltaByteArray       = 7
+	encRLEDictionary        = 8
+	encByteStreamSplit      = 9
+)
+
+// Compression codecs.
+const (
+	codecUncompressed = 0
+	codecSnappy       = 1
+	codecGzip         = 2
+	codecZstd         = 6
+)
+
+var codecNames = map[int32]string{3: "LZO", 4: "BROTLI", 5: "LZ4", 7: "LZ4_RAW"}
+
+// Page types.
+const (
+	pageData       = 0
+	pageDictionary = 2
+	pageDataV2     = 3
+)
+
+// fileMeta is the subset of FileMetaData the reader uses.
+type fileMeta struct {
+	schema    []schemaElement
+	numRows   int64
+	rowGroups []rowGroup
+}
+
+type schemaElement struct {
+	typ         int32 // Physical type; -1 for a group
+	typeLength  int32
+	repetition  int32
+	name        string
+	numChildren int32
+	converted   int32 // -1 when unset
+	scale       int32
+	logical     logicalType
+}
+
+// logicalType is a LogicalType annotation flattened to the fields the reader
+// uses.
+type logicalType struct {
+	kind     int16 // Union field id; 0 when unset
+	unit     int16 // TimeUnit field id for TIMESTAMP
+	scale    int32 // DECIMAL
+	unsigned bool  // INTEGER
+}
+
+type rowGroup struct {
+	numRows int64
+	columns []columnChunk
+}
+
+type columnChunk struct {
+	filePath string // Set when the chunk lives in another file
+	meta     columnMeta
+}
+
+type columnMeta struct {
+	codec           int32
+	numValues       int64
+	totalCompressed int64
+	dataPageOffset  int64
+	dictPageOffset  int64 // 0 when the chunk has no dictionary page
+}
+
+type pageHeader struct {
+	typ          int32
+	uncompressed int32
+	compressed   int32
+	numValues    int32
+	encoding     int32
+	// Data page v2 only.
+	numNulls     int32
+	defLength    int32
+	repLength    int32
+	isCompressed bool
+}
+
+func decodeFileMeta(b []byte) (*fileMeta, error) {
+	r := &thriftReader{b: b}
+	var m fileMeta
+	r.fields(func(id int16, typ byte) {
+		switch {
+		case id == 2 && typ == ctList:
+			r.structs(func() { m.schema = append(m.schema, r.schemaElement()) })
+		case id == 3 && typ == ctI64:
+			m.numRows = r.i64()
+		case id == 4 && typ == ctList:
+			r.structs(func() { m.rowGroups = append(m.rowGroups, r.rowGroup()) })
+		default:
+			r.skip(typ)
+		}
+	})
+	return &m, r.err
+}
+
+func (r *thriftReader) schemaElement() schemaElement {
+	e := schemaElement{typ: -1, converted: -1}
+	r.fields(func(id int16, typ byte) {
+		switch {
+		case id == 1 && typ == ctI32:
+			e.typ = r.i32()
+		case id == 2 && typ == ctI32:
+			e.typeLength = r.i32()
+		case id == 3 && typ == ctI32:
+			e.repetition = r.i32()
+		case id == 4 && typ == ctBinary:
+			e.name = string(r.binary())
+		case id == 5 && typ == ctI32:
+			e.numChildren = r.i32()
+		case id == 6 && typ == ctI32:
+			e.converted = r.i32()
+		case id == 7 && typ == ctI32:
+			e.scale = r.i32()
+		case id == 10 && typ == ctStruct:
+			e.logical = r.logicalType()
+		default:
+			r.skip(typ)
+		}
+	})
+	return e
+}
+
+func (r *thriftReader) logicalType() logicalType {
+	var lt logicalType
+	r.fields(func(id int16, typ byte) {
+		if typ != ctStruct {
+			r.skip(typ)
+			return
+		}
+		lt.kind = id
+		r.fields(func(fid int16, ftyp byte) {
+			switch {
+			case id == logicalDecimal && fid == 1 && ftyp == ctI32:
+				lt.scale = r.i32()
+			case id == logicalTimestamp && fid == 2 && ftyp == ctStruct:
+				r.fields(func(unit int16, utyp byte) {
+					lt.unit = unit
+					r.skip(utyp)
+				})
+			case id == logicalInteger && fid == 2 && (ftyp == ctTrue || ftyp == ctFalse):
+				lt.unsigned = ftyp == ctFalse
+			default:
+				r.skip(ftyp)
+			}
+		})
+	})
+	return lt
+}
+
+func (r *thriftReader) rowGroup() rowGroup {
+	var g rowGroup
+	r.fields(func(id int16, typ byte) {
+		switch {
+		case id == 1 && typ == ctList:
+			r.structs(func() { g.columns = append(g.columns, r.columnChunk()) })
+		case id == 3 && typ == ctI64:
+			g.numRows = r.i64()
+		default:
+			r.skip(typ)
+		}
+	})
+	return g
+}
+
+func (r *thriftReader) columnChunk() columnChunk {
+	var c columnChunk
+	r.fields(func(id int16, typ byte) {
+		switch {
+		case id == 1 && typ == ctBinary:
+			c.filePath = string(r.binary())
+		case id == 3 && typ == ctStruct:
+			c.meta = r.columnMeta()
+		default:
+			r.skip(typ)
+		}
+	})
+	return c
+}
+
+func (r *thriftReader) columnMeta() columnMeta {
+	var m columnMeta
+	r.fields(func(id int16, typ byte) {
+		switch {
+		case id == 4 && typ == ctI32:
+			m.codec = r.i32()
+		case id == 5 && typ == ctI64:
+			m.numValues = r.i64()
+		case id == 7 && typ == ctI64:
+			m.totalCompressed = r.i64()
+		case id == 9 && typ == ctI64:
+			m.dataPageOffset = r.i64()
+		case id == 11 && typ == ctI64:
+			m.dictPageOffset = r.i64()
+		default:
+			r.skip(typ)
+		}
+	})
+	return m
+}
+
+// decodePageHeader decodes the page header at the start of b and returns it
+// with its encoded length.
+func decodePageHeader(b []byte) (pageHeader, int, error) {
+	r := &thriftReader{b: b}
+	h := pageHeader{isCompressed: true}
+	r.fields(func(id int16, typ byte) {
+		switch {
+		case id == 1 && typ == ctI32:
+			h.typ = r.i32()
+		case id == 2 && typ == ctI32:
+			h.uncompressed = r.i32()
+		case id == 3 && typ == ctI32:
+			h.compressed = r.i32()
+		case (id == 5 || id == 7) && typ == ctStruct:
+			// DataPageHeader and DictionaryPageHeader share their first fields.
+			r.fields(func(fid int16, ftyp byte) {
+				switch {
+				case fid == 1 && ftyp == ctI32:
+					h.numValues = r.i32()
+				case fid == 2 && ftyp == ctI32:
+					h.encoding = r.i32()
+				default:
+					r.skip(ftyp)
+				}
+			})
+		case id == 8 && typ == ctStruct:
+			r.fields(func(fid int16, ftyp byte) {
+				switch {
+				case fid == 1 && ftyp == ctI32:
+					h.numValues = r.i32()
+				case fid == 2 && ftyp == ctI32:
+					h.numNulls = r.i32()
+				case fid == 4 && ftyp == ctI32:
+					h.encoding = r.i32()
+				case fid == 5 && ftyp == ctI32:
+					h.defLength = r.i32()
+				case fid == 6 && ftyp == ctI32:
+					h.repLength = r.i32()
+				case fid == 7 && (ftyp == ctTrue || ftyp == ctFalse):
+					h.isCompressed = ftyp == ctTrue
+				default:
+					r.skip(ftyp)
+				}
+			})
+		default:
+			r.skip(typ)
+		}
+	})
+	return h, len(b) - len(r.b), r.err
+}
@@ -0,0 +1,667 @@
+// Package parquetio reads and writes flat Parquet files, such as usage tables
+// exported from a warehouse, for pricing-cli's ingest and rates commands.
+//
+// The core pricing_db package depends only on the standard library and reads
+// and writes CSV. This package decodes a Parquet file into the same text
+// cells, so its columns map onto CSVColumns by name:
+//
+//	t, err := parquetio.ReadTable(f, size)
+//	for _, row := range t.Rows {
+//		fmt.Println(row[t.Index("model")])
+//	}
+//
+// Top-level required and optional columns are read; nested and repeated
+// columns are skipped. Pages may be PLAIN, dictionary, delta or
+// byte-stream-split encoded, in data page v1 or v2 format, and uncompressed or
+// compressed with snappy, gzip or zstd.
+//
+// WriteTable writes a Table back out as a single row group of PLAIN,
+// snappy-compressed pages.
+package parquetio
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"math/bits"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/klauspost/compress/snappy"
+	"github.com/klauspost/compress/zstd"
+)
+
+// Magic starts and ends every Parquet file.
+var Magic = []byte("PAR1")
+
+// Type is the kind of value a column holds.
+type Type int
+
+const (
+	String  Type = iota // Text, and timestamps, dates and decimals formatted as text
+	Int64               // Integers
+	Double              // Floating point numbers
+	Boolean             // "true" or "false"
+)
+
+// Column is a table column.
+type Column struct {
+	Name string
+	Type Type
+}
+
+// Table is a flat table with every value as text. Timestamps are RFC 3339 in
+// UTC, dates are "2006-01-02", and nulls are empty strings.
+type Table struct {
+	Columns []Column
+	Rows    [][]string
+}
+
+// Index returns the index of the column named name, or -1.
+func (t *Table) Index(name string) int {
+	for i, c := range t.Columns {
+		if c.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// ReadTable reads the Parquet file of size bytes in r.
+func ReadTable(r io.ReaderAt, size int64) (*Table, error) {
+	t, err := readTable(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("parquet: %w", err)
+	}
+	return t, nil
+}
+
+func readTable(r io.ReaderAt, size int64) (*Table, error) {
+	if size < int64(2*len(Magic)+4) {
+		return nil, errors.New("file too short")
+	}
+	tail := make([]byte, 4+len(Magic))
+	if _, err := r.ReadAt(tail, size-int64(len(tail))); err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(tail[4:], Magic) {
+		return nil, errors.New("missing PAR1 footer (encrypted footers are not supported)")
+	}
+	footerLen := int64(binary.LittleEndian.Uint32(tail))
+	if footerLen > size-int64(len(Magic)+len(tail)) {
+		return nil, errors.New("footer length exceeds file size")
+	}
+	footer := make([]byte, footerLen)
+	if _, err := r.ReadAt(footer, size-int64(len(tail))-footerLen); err != nil {
+		return nil, err
+	}
+	meta, err := decodeFileMeta(footer)
+	if err != nil {
+		return nil, fmt.Errorf("footer: %w", err)
+	}
+	leaves, err := meta.leaves()
+	if err != nil {
+		return nil, err
+	}
+
+	var rows int64
+	for _, g := range meta.rowGroups {
+		if g.numRows < 0 || g.numRows > meta.numRows-rows {
+			return nil, fmt.Errorf("row groups hold more than the file's %d rows", meta.numRows)
+		}
+		rows += g.numRows
+	}
+	if rows != meta.numRows {
+		return nil, fmt.Errorf("row groups hold %d rows, footer says %d", rows, meta.numRows)
+	}
+
+	t := &Table{}
+	for _, l := range leaves {
+		if l.flat {
+			t.Columns = append(t.Columns, Column{Name: l.name, Type: l.columnType()})
+		}
+	}
+	if len(t.Columns) == 0 {
+		// Nothing to read: without columns a row has no cells.
+		return t, nil
+	}
+	for _, g := range meta.rowGroups {
+		if len(g.columns) != len(leaves) {
+			return nil, fmt.Errorf("row group has %d columns, schema has %d", len(g.columns), len(leaves))
+		}
+		var columns [][]string
+		for i, l := range leaves {
+			if !l.flat {
+				continue
+			}
+			if n := g.columns[i].meta.numValues; n != g.numRows {
+				return nil, fmt.Errorf("column %s: %d values in a row group of %d rows", l.name, n, g.numRows)
+			}
+			values, err := l.readChunk(r, size, &g.columns[i])
+			if err != nil {
+				return nil, fmt.Errorf("column %s: %w", l.name, err)
+			}
+			if int64(len(values)) != g.numRows {
+				return nil, fmt.Errorf("column %s: %d values in a row group of %d rows", l.name, len(values), g.numRows)
+			}
+			columns = append(columns, values)
+		}
+		cells := make([]string, int(g.numRows)*len(columns))
+		for i := range int(g.numRows) {
+			row := cells[i*len(columns) : (i+1)*len(columns)]
+			for j, values := range columns {
+				row[j] = values[i]
+			}
+			t.Rows = append(t.Rows, row)
+		}
+	}
+	return t, nil
+}
+
+// leaf is a column of the schema.
+type leaf struct {
+	*schemaElement
+	name   string // Dotted path for nested columns
+	maxDef int
+	flat   bool // Top-level and not repeated, so one value per row
+}
+
+// leaves returns the schema's leaf columns in column-chunk order.
+func (m *fileMeta) leaves() ([]*leaf, error) {
+	if len(m.schema) == 0 {
+		return nil, errors.New("empty schema")
+	}
+	var leaves []*leaf
+	next := 1
+	var walk func(children int32, path string, maxDef int, repeated bool) error
+	walk = func(children int32, path string, maxDef int, repeated bool) error {
+		for range children {
+			if next >= len(m.schema) {
+				return errors.New("schema ends inside a group")
+			}
+			e := &m.schema[next]
+			next++
+			def, rep := maxDef, repeated
+			switch e.repetition {
+			case repOptional:
+				def++
+			case repRepeated:
+				def++
+				rep = true
+			}
+			name := e.name
+			if path != "" {
+				name = path + "." + name
+			}
+			if e.numChildren > 0 {
+				if err := walk(e.numChildren, name, def, rep); err != nil {
+					return err
+				}
+				continue
+			}
+			if e.typ < 0 {
+				return fmt.Errorf("column %s has no type", name)
+			}
+			leaves = append(leaves, &leaf{schemaElement: e, name: name, maxDef: def, flat: path == "" && !rep})
+		}
+		return nil
+	}
+	if err := walk(m.schema[0].numChildren, "", 0, false); err != nil {
+		return nil, err
+	}
+	return leaves, nil
+}
+
+// columnType returns the Type the column's text reads as.
+func (l *leaf) columnType() Type {
+	switch {
+	case l.typ == typeBoolean:
+		return Boolean
+	case l.typ == typeFloat || l.typ == typeDouble:
+		return Double
+	case (l.typ == typeInt32 || l.typ == typeInt64) && !l.isTime() && !l.isDecimal():
+		return Int64
+	}
+	return String
+}
+
+func (l *leaf) isDecimal() bool {
+	return l.logical.kind == logicalDecimal || l.converted == convertedDecimal
+}
+
+func (l *leaf) isTime() bool {
+	switch {
+	case l.logical.kind == logicalDate, l.logical.kind == logicalTimestamp:
+		return true
+	case l.converted == convertedDate, l.converted == convertedTimestampMillis, l.converted == convertedTimestampMicros:
+		return true
+	}
+	return false
+}
+
+func (l *leaf) unsigned() bool {
+	if l.logical.kind == logicalInteger {
+		return l.logical.unsigned
+	}
+	return l.converted >= convertedUint8 && l.converted <= convertedUint64
+}
+
+// readChunk reads a flat column's values in a row group.
+func (l *leaf) readChunk(r io.ReaderAt, size int64, c *columnChunk) ([]string, error) {
+	if c.filePath != "" {
+		return nil, fmt.Errorf("column data in external file %q is not supported", c.filePath)
+	}
+	m := &c.meta
+	start := m.dataPageOffset
+	if m.dictPageOffset > 0 && m.dictPageOffset < start {
+		start = m.dictPageOffset
+	}
+	if start < 0 || m.totalCompressed < 0 || m.totalCompressed > size-start {
+		return nil, errors.New("column chunk outside the file")
+	}
+	buf := make([]byte, m.totalCompressed)
+	if _, err := r.ReadAt(buf, start); err != nil {
+		return nil, err
+	}
+
+	var dict []string
+	var values []string
+	for len(buf) > 0 && int64(len(values)) < m.numValues {
+		h, n, err := decodePageHeader(buf)
+		if err != nil {
+			return nil, fmt.Errorf("page header: %w", err)
+		}
+		buf = buf[n:]
+		if h.compressed < 0 || int(h.compressed) > len(buf) || h.numValues < 0 {
+			return nil, errors.New("page extends past the column chunk")
+		}
+		if h.uncompressed < 0 || h.uncompressed > maxPageSize {
+			return nil, fmt.Errorf("page of %d bytes exceeds the %d-byte limit", h.uncompressed, maxPageSize)
+		}
+		// A data page cannot hold more values than the chunk has left; every
+		// allocation below is bounded by this or by the page's bytes.
+		if h.typ != pageDictionary && int64(h.numValues) > m.numValues-int64(len(values)) {
+			return nil, fmt.Errorf("page of %d values overruns the column chunk's %d", h.numValues, m.numValues)
+		}
+		page := buf[:h.compressed]
+		buf = buf[h.compressed:]
+
+		switch h.typ {
+		case pageDictionary:
+			data, err := decompress(m.codec, page, h.uncompressed)
+			if err != nil {
+				return nil, err
+			}
+			if dict, err = l.plain(data, int(h.numValues)); err != nil {
+				return nil, fmt.Errorf("dictionary page: %w", err)
+			}
+		case pageData:
+			data, err := decompress(m.codec, page, h.uncompressed)
+			if err != nil {
+				return nil, err
+			}
+			var defs []uint32
+			if l.maxDef > 0 {
+				if len(data) < 4 {
+					return nil, errTruncated
+				}
+				n := binary.LittleEndian.Uint32(data)
+				if uint64(n) > uint64(len(data)-4) {
+					return nil, errTruncated
+				}
+				if defs, err = decodeHybrid(data[4:4+n], bits.Len(uint(l.maxDef)), int(h.numValues)); err != nil {
+					return nil, fmt.Errorf("definition levels: %w", err)
+				}
+				data = data[4+n:]
+			}
+			if values, err = l.appendPage(values, h, defs, data, dict); err != nil {
+				return nil, err
+			}
+		case pageDataV2:
+			levels := int64(h.repLength) + int64(h.defLength)
+			if h.repLength != 0 || h.defLength < 0 || levels > int64(len(page)) {
+				return nil, errors.New("invalid data page v2 levels")
+			}
+			var defs []uint32
+			if l.maxDef > 0 {
+				if defs, err = decodeHybrid(page[:h.defLength], bits.Len(uint(l.maxDef)), int(h.numValues)); err != nil {
+					return nil, fmt.Errorf("definition levels: %w", err)
+				}
+			}
+			data := page[levels:]
+			if h.isCompressed {
+				if data, err = decompress(m.codec, data, h.uncompressed-int32(levels)); err != nil {
+					return nil, err
+				}
+			}
+			if values, err = l.appendPage(values, h, defs, data, dict); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return values, nil
+}
+
+// appendPage appends a data page's h.numValues values to values: nulls where
+// defs is below the column's maximum, and decoded data in between.
+func (l *leaf) appendPage(values []string, h pageHeader, defs []uint32, data []byte, dict []string) ([]string, error) {
+	present := int(h.numValues)
+	if defs != nil {
+		present = 0
+		for _, d := range defs {
+			if int(d) == l.maxDef {
+				present++
+			}
+		}
+	}
+	decoded, err := l.decode(h.encoding, data, present, dict)
+	if err != nil {
+		return nil, err
+	}
+	if defs == nil {
+		return append(values, decoded...), nil
+	}
+	for _, d := range defs {
+		if int(d) == l.maxDef {
+			values = append(values, decoded[0])
+			decoded = decoded[1:]
+		} else {
+			values = append(values, "")
+		}
+	}
+	return values, nil
+}
+
+// decode decodes n values of a data page.
+func (l *leaf) decode(encoding int32, data []byte, n int, dict []string) ([]string, error) {
+	switch encoding {
+	case encPlain:
+		return l.plain(data, n)
+	case encPlainDictionary, encRLEDictionary:
+		if dict == nil {
+			return nil, errors.New("dictionary-encoded page without a dictionary")
+		}
+		if len(data) == 0 {
+			return nil, errTruncated
+		}
+		indexes, err := decodeHybrid(data[1:], int(data[0]), n)
+		if err != nil {
+			return nil, err
+		}
+		values := make([]string, n)
+		for i, idx := range indexes {
+			if int(idx) >= len(dict) {
+				return nil, fmt.Errorf("dictionary index %d out of range", idx)
+			}
+			values[i] = dict[idx]
+		}
+		return values, nil
+	case encRLE:
+		if l.typ != typeBoolean || len(data) < 4 {
+			return nil, errors.New("invalid RLE-encoded values")
+		}
+		bools, err := decodeHybrid(data[4:], 1, n)
+		if err != nil {
+			return nil, err
+		}
+		values := make([]string, n)
+		for i, b := range bools {
+			values[i] = strconv.FormatBool(b != 0)
+		}
+		return values, nil
+	case encDeltaBinaryPacked:
+		if l.typ != typeInt32 && l.typ != typeInt64 {
+			break
+		}
+		ints, _, err := decodeDeltaBinaryPacked(data, n)
+		if err != nil {
+			return nil, err
+		}
+		values := make([]string, n)
+		for i, v := range ints {
+			if l.typ == typeInt32 {
+				v = l.widen(int32(v))
+			}
+			values[i] = l.intText(v)
+		}
+		return values, nil
+	case encDeltaLengthByteArray, encDeltaByteArray:
+		if l.typ != typeByteArray && l.typ != typeFixedLen {
+			break
+		}
+		decodeBytes := decodeDeltaLengthByteArray
+		if encoding == encDeltaByteArray {
+			decodeBytes = decodeDeltaByteArray
+		}
+		arrays, err := decodeBytes(data, n)
+		if err != nil {
+			return nil, err
+		}
+		values := make([]string, n)
+		for i, b := range arrays {
+			values[i] = l.bytesText(b)
+		}
+		return values, nil
+	case encByteStreamSplit:
+		width := l.width()
+		if width == 0 {
+			break
+		}
+		plain, err := unsplitStreams(data, n, width)
+		if err != nil {
+			return nil, err
+		}
+		return l.plain(plain, n)
+	}
+	return nil, fmt.Errorf("unsupported encoding %d for physical type %d", encoding, l.typ)
+}
+
+// width returns the byte width of the column's fixed-size values, or 0.
+func (l *leaf) width() int {
+	switch l.typ {
+	case typeInt32, typeFloat:
+		return 4
+	case typeInt64, typeDouble:
+		return 8
+	case typeInt96:
+		return 12
+	case typeFixedLen:
+		return int(l.typeLength)
+	}
+	return 0
+}
+
+// plain decodes n PLAIN-encoded values.
+func (l *leaf) plain(b []byte, n int) ([]string, error) {
+	// Check the page holds n values before allocating for them.
+	switch l.typ {
+	case typeBoolean:
+		if len(b) < (n+7)/8 {
+			return nil, errTruncated
+		}
+	case typeByteArray:
+		if len(b)/4 < n {
+			return nil, errTruncated
+		}
+	default:
+		if width := l.width(); width <= 0 || len(b)/width < n {
+			return nil, errTruncated
+		}
+	}
+	values := make([]string, n)
+	if l.typ == typeBoolean {
+		for i := range values {
+			values[i] = strconv.FormatBool(b[i/8]>>(i%8)&1 != 0)
+		}
+		return values, nil
+	}
+	if l.typ == typeByteArray {
+		for i := range values {
+			if len(b) < 4 {
+				return nil, errTruncated
+			}
+			size := binary.LittleEndian.Uint32(b)
+			if uint64(size) > uint64(len(b)-4) {
+				return nil, errTruncated
+			}
+			values[i] = l.bytesText(b[4 : 4+size])
+			b = b[4+size:]
+		}
+		return values, nil
+	}
+
+	width := l.width()
+	for i := range values {
+		v := b[i*width : (i+1)*width]
+		switch l.typ {
+		case typeInt32:
+			values[i] = l.intText(l.widen(int32(binary.LittleEndian.Uint32(v))))
+		case typeInt64:
+			values[i] = l.intText(int64(binary.LittleEndian.Uint64(v)))
+		case typeInt96:
+			values[i] = int96Text(v)
+		case typeFloat:
+			values[i] = strconv.FormatFloat(float64(math.Float32frombits(binary.LittleEndian.Uint32(v))), 'f', -1, 32)
+		case typeDouble:
+			values[i] = strconv.FormatFloat(math.Float64frombits(binary.LittleEndian.Uint64(v)), 'f', -1, 64)
+		case typeFixedLen:
+			values[i] = l.bytesText(v)
+		}
+	}
+	return values, nil
+}
+
+// widen widens an INT32 value, honoring unsigned annotations.
+func (l *leaf) widen(v int32) int64 {
+	if l.unsigned() {
+		return int64(uint32(v))
+	}
+	return int64(v)
+}
+
+// intText formats an INT32 or INT64 value according to its annotation.
+func (l *leaf) intText(v int64) string {
+	switch {
+	case l.logical.kind == logicalTimestamp:
+		switch l.logical.unit {
+		case unitMillis:
+			return timeText(time.UnixMilli(v))
+		case unitMicros:
+			return timeText(time.UnixMicro(v))
+		case unitNanos:
+			return timeText(time.Unix(0, v))
+		}
+	case l.converted == convertedTimestampMillis:
+		return timeText(time.UnixMilli(v))
+	case l.converted == convertedTimestampMicros:
+		return timeText(time.UnixMicro(v))
+	case l.logical.kind == logicalDate || l.converted == convertedDate:
+		return time.Unix(v*86400, 0).UTC().Format(time.DateOnly)
+	case l.isDecimal():
+		return decimalText(big.NewInt(v), l.decimalScale())
+	case l.unsigned() && l.typ == typeInt64:
+		return strconv.FormatUint(uint64(v), 10)
+	}
+	return strconv.FormatInt(v, 10)
+}
+
+// bytesText formats a BYTE_ARRAY or FIXED_LEN_BYTE_ARRAY value: decimals
+// from their big-endian two's complement bytes, anything else as text.
+func (l *leaf) bytesText(b []byte) string {
+	if !l.isDecimal() {
+		return string(b)
+	}
+	x := new(big.Int).SetBytes(b)
+	if len(b) > 0 && b[0]&0x80 != 0 {
+		x.Sub(x, new(big.Int).Lsh(big.NewInt(1), uint(8*len(b))))
+	}
+	return decimalText(x, l.decimalScale())
+}
+
+func (l *leaf) decimalScale() int32 {
+	if l.logical.kind == logicalDecimal {
+		return l.logical.scale
+	}
+	return l.scale
+}
+
+// decimalText formats the unscaled decimal x with scale digits after the
+// point.
+func decimalText(x *big.Int, scale int32) string {
+	if scale <= 0 {
+		return x.String()
+	}
+	digits := new(big.Int).Abs(x).String()
+	if pad := int(scale) + 1 - len(digits); pad > 0 {
+		digits = strings.Repeat("0", pad) + digits
+	}
+	point := len(digits) - int(scale)
+	text := digits[:point] + "." + digits[point:]
+	if x.Sign() < 0 {
+		return "-" + text
+	}
+	return text
+}
+
+// julianUnixEpoch is the Julian day number of 1970-01-01.
+const julianUnixEpoch = 2440588
+
+// int96Text formats a legacy INT96 timestamp: nanoseconds within the day,
+// then the Julian day.
+func int96Text(b []byte) string {
+	nanos := int64(binary.LittleEndian.Uint64(b))
+	day := int64(binary.LittleEndian.Uint32(b[8:])) - julianUnixEpoch
+	return timeText(time.Unix(day*86400, nanos))
+}
+
+func timeText(t time.Time) string {
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+// maxPageSize bounds a page's uncompressed size, so a corrupt header cannot
+// make the reader allocate gigabytes. Writers default to pages of about 1 MiB.
+const maxPageSize = 256 << 20
+
+// zstdDecoder is shared by all reads; DecodeAll is safe for concurrent use.
+var zstdDecoder = sync.OnceValues(func() (*zstd.Decoder, error) {
+	return zstd.NewReader(nil, zstd.WithDecoderConcurrency(0), zstd.WithDecoderMaxMemory(maxPageSize))
+})
+
+// decompress decompresses a page of size bytes compressed with codec.
+func decompress(codec int32, page []byte, size int32) ([]byte, error) {
+	if size < 0 {
+		return nil, errors.New("invalid page size")
+	}
+	switch codec {
+	case codecUncompressed:
+		return page, nil
+	case codecSnappy:
+		if n, err := snappy.DecodedLen(page); err != nil || n != int(size) {
+			return nil, errors.New("snappy page size does not match its header")
+		}
+		return snappy.Decode(nil, page)
+	case codecGzip:
+		zr, err := gzip.NewReader(bytes.NewReader(page))
+		if err != nil {
+			return nil, err
+		}
+		return io.ReadAll(io.LimitReader(zr, int64(size)))
+	case codecZstd:
+		dec, err := zstdDecoder()
+		if err != nil {
+			return nil, err
+		}
+		return dec.DecodeAll(page, nil)
+	}
+	if name, ok := codecNames[codec]; ok {
+		return nil, fmt.Errorf("unsupported compression codec %s", name)
+	}
+	return nil, fmt.Errorf("unknown compression codec %d", codec)
+}
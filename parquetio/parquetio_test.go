@@ -0,0 +1,770 @@
+package parquetio
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"io"
+	"math"
+	"math/bits"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/klauspost/compress/snappy"
+	"github.com/klauspost/compress/zstd"
+)
+
+// =============================================================================
+// Test File Builder
+// =============================================================================
+
+type testColumn struct {
+	name       string
+	typ        int32 // -1 for a group
+	repetition int32
+	children   int32
+	typeLength int32
+	converted  int32 // -1 for none
+	scale      int32
+	logical    func(w *thriftWriter) // Writes the LogicalType struct's fields
+	codec      int32
+	pages      [][]byte // Encoded pages, header included; nil for a group
+	numValues  int64
+	dict       bool // pages[0] is a dictionary page
+}
+
+func column(name string, typ, repetition int32) testColumn {
+	return testColumn{name: name, typ: typ, repetition: repetition, converted: -1}
+}
+
+// buildFile writes a Parquet file with one row group per entry of groups,
+// each holding the leaf columns' chunks in schema order.
+func buildFile(schema []testColumn, rowCounts []int64, groups [][]testColumn) []byte {
+	out := bytes.NewBuffer(append([]byte(nil), Magic...))
+	type chunkMeta struct {
+		col            testColumn
+		offset, dict   int64
+		size, valCount int64
+	}
+	var metas [][]chunkMeta
+	for _, g := range groups {
+		var ms []chunkMeta
+		for _, c := range g {
+			m := chunkMeta{col: c, offset: int64(out.Len()), valCount: c.numValues}
+			if c.dict {
+				m.dict = m.offset
+				m.offset += int64(len(c.pages[0]))
+			}
+			for _, p := range c.pages {
+				out.Write(p)
+				m.size += int64(len(p))
+			}
+			ms = append(ms, m)
+		}
+		metas = append(metas, ms)
+	}
+
+	w := &thriftWriter{}
+	w.begin()
+	w.i32(1, 1)
+	w.list(2, ctStruct, len(schema)+1)
+	w.begin()
+	top := len(schema)
+	for _, c := range schema {
+		top -= int(c.children) // Children of one-level groups
+	}
+	w.binary(4, "schema")
+	w.i32(5, int32(top))
+	w.end()
+	for _, c := range schema {
+		w.begin()
+		if c.typ >= 0 {
+			w.i32(1, c.typ)
+		}
+		if c.typeLength > 0 {
+			w.i32(2, c.typeLength)
+		}
+		w.i32(3, c.repetition)
+		w.binary(4, c.name)
+		if c.children > 0 {
+			w.i32(5, c.children)
+		}
+		if c.converted >= 0 {
+			w.i32(6, c.converted)
+		}
+		if c.scale > 0 {
+			w.i32(7, c.scale)
+			w.i32(8, 18)
+		}
+		if c.logical != nil {
+			w.structField(10)
+			c.logical(w)
+			w.end()
+		}
+		w.end()
+	}
+	var total int64
+	for _, n := range rowCounts {
+		total += n
+	}
+	w.i64(3, total)
+	w.list(4, ctStruct, len(metas))
+	for i, ms := range metas {
+		w.begin()
+		w.list(1, ctStruct, len(ms))
+		for _, m := range ms {
+			w.begin()
+			w.i64(2, m.offset)
+			w.structField(3)
+			w.i32(1, m.col.typ)
+			w.list(2, ctI32, 1)
+			w.buf = binary.AppendVarint(w.buf, encPlain)
+			w.list(3, ctBinary, 1)
+			w.buf = binary.AppendUvarint(w.buf, uint64(len(m.col.name)))
+			w.buf = append(w.buf, m.col.name...)
+			w.i32(4, m.col.codec)
+			w.i64(5, m.valCount)
+			w.i64(6, m.size)
+			w.i64(7, m.size)
+			w.i64(9, m.offset)
+			if m.col.dict {
+				w.i64(11, m.dict)
+			}
+			w.end()
+			w.end()
+		}
+		w.i64(2, 0)
+		w.i64(3, rowCounts[i])
+		w.end()
+	}
+	w.binary(6, "parquetio test")
+	w.end()
+
+	out.Write(w.buf)
+	out.Write(binary.LittleEndian.AppendUint32(nil, uint32(len(w.buf))))
+	out.Write(Magic)
+	return out.Bytes()
+}
+
+func compress(t *testing.T, codec int32, b []byte) []byte {
+	t.Helper()
+	switch codec {
+	case codecSnappy:
+		return snappy.Encode(nil, b)
+	case codecGzip:
+		var buf bytes.Buffer
+		zw := gzip.NewWriter(&buf)
+		zw.Write(b)
+		zw.Close()
+		return buf.Bytes()
+	case codecZstd:
+		enc, err := zstd.NewWriter(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer enc.Close()
+		return enc.EncodeAll(b, nil)
+	}
+	return b
+}
+
+// pageV1 encodes a data page (defs != nil for an optional column) or, with
+// dict set, a dictionary page.
+func pageV1(t *testing.T, codec int32, encoding int32, n int, defs []uint32, values []byte, dict bool) []byte {
+	var body []byte
+	if defs != nil {
+		levels := rleRuns(defs, 1)
+		body = binary.LittleEndian.AppendUint32(body, uint32(len(levels)))
+		body = append(body, levels...)
+	}
+	body = append(body, values...)
+	data := compress(t, codec, body)
+
+	w := &thriftWriter{}
+	w.begin()
+	if dict {
+		w.i32(1, pageDictionary)
+	} else {
+		w.i32(1, pageData)
+	}
+	w.i32(2, int32(len(body)))
+	w.i32(3, int32(len(data)))
+	if dict {
+		w.structField(7)
+		w.i32(1, int32(n))
+		w.i32(2, encPlain)
+	} else {
+		w.structField(5)
+		w.i32(1, int32(n))
+		w.i32(2, encoding)
+		w.i32(3, encRLE)
+		w.i32(4, encRLE)
+	}
+	w.end()
+	w.end()
+	return append(w.buf, data...)
+}
+
+// pageV2 encodes a data page v2 with its values compressed by codec.
+func pageV2(t *testing.T, codec int32, encoding int32, n int, defs []uint32, values []byte) []byte {
+	var levels []byte
+	nulls := 0
+	if defs != nil {
+		levels = rleRuns(defs, 1)
+		for _, d := range defs {
+			if d == 0 {
+				nulls++
+			}
+		}
+	}
+	data := compress(t, codec, values)
+
+	w := &thriftWriter{}
+	w.begin()
+	w.i32(1, pageDataV2)
+	w.i32(2, int32(len(levels)+len(values)))
+	w.i32(3, int32(len(levels)+len(data)))
+	w.structField(8)
+	w.i32(1, int32(n))
+	w.i32(2, int32(nulls))
+	w.i32(3, int32(n))
+	w.i32(4, encoding)
+	w.i32(5, int32(len(levels)))
+	w.i32(6, 0)
+	w.end()
+	w.end()
+	return append(append(w.buf, levels...), data...)
+}
+
+// rleRuns encodes values as RLE runs of the hybrid encoding.
+func rleRuns(values []uint32, bitWidth int) []byte {
+	var b []byte
+	for i := 0; i < len(values); {
+		j := i
+		for j < len(values) && values[j] == values[i] {
+			j++
+		}
+		b = binary.AppendUvarint(b, uint64(j-i)<<1)
+		for k := range (bitWidth + 7) / 8 {
+			b = append(b, byte(values[i]>>(8*k)))
+		}
+		i = j
+	}
+	return b
+}
+
+// bitPacked encodes values as one bit-packed run of the hybrid encoding.
+func bitPacked(values []uint32, bitWidth int) []byte {
+	groups := (len(values) + 7) / 8
+	b := binary.AppendUvarint(nil, uint64(groups)<<1|1)
+	packed := make([]byte, groups*bitWidth)
+	for i, v := range values {
+		for bit := range bitWidth {
+			if v>>bit&1 != 0 {
+				pos := i*bitWidth + bit
+				packed[pos/8] |= 1 << (pos % 8)
+			}
+		}
+	}
+	return append(b, packed...)
+}
+
+// deltaPacked encodes values with DELTA_BINARY_PACKED: 128-value blocks of
+// four miniblocks.
+func deltaPacked(values []int64) []byte {
+	b := binary.AppendUvarint(nil, 128)
+	b = binary.AppendUvarint(b, 4)
+	b = binary.AppendUvarint(b, uint64(len(values)))
+	if len(values) == 0 {
+		return binary.AppendVarint(b, 0)
+	}
+	b = binary.AppendVarint(b, values[0])
+	for start := 1; start < len(values); start += 128 {
+		block := make([]int64, 0, 128)
+		for i := start; i < min(start+128, len(values)); i++ {
+			block = append(block, values[i]-values[i-1])
+		}
+		minDelta := slices.Min(block)
+		b = binary.AppendVarint(b, minDelta)
+		var minis [][]uint32
+		var widths []byte
+		for m := range 4 {
+			var mini []uint32
+			width := 0
+			for i := m * 32; i < min((m+1)*32, len(block)); i++ {
+				v := uint32(block[i] - minDelta)
+				mini = append(mini, v)
+				width = max(width, bits.Len32(v))
+			}
+			minis = append(minis, mini)
+			widths = append(widths, byte(width))
+		}
+		b = append(b, widths...)
+		for m, mini := range minis {
+			if len(mini) == 0 {
+				break
+			}
+			packed := bitPacked(append(mini, make([]uint32, 32-len(mini))...), int(widths[m]))
+			b = append(b, packed[1:]...) // Miniblocks have no run header
+		}
+	}
+	return b
+}
+
+func plainInt64s(values ...int64) []byte {
+	var b []byte
+	for _, v := range values {
+		b = binary.LittleEndian.AppendUint64(b, uint64(v))
+	}
+	return b
+}
+
+func plainInt32s(values ...int32) []byte {
+	var b []byte
+	for _, v := range values {
+		b = binary.LittleEndian.AppendUint32(b, uint32(v))
+	}
+	return b
+}
+
+func plainDoubles(values ...float64) []byte {
+	var b []byte
+	for _, v := range values {
+		b = binary.LittleEndian.AppendUint64(b, math.Float64bits(v))
+	}
+	return b
+}
+
+func plainStrings(values ...string) []byte {
+	var b []byte
+	for _, v := range values {
+		b = binary.LittleEndian.AppendUint32(b, uint32(len(v)))
+		b = append(b, v...)
+	}
+	return b
+}
+
+// byteStreamSplit splits PLAIN values of width bytes into byte streams.
+func byteStreamSplit(plain []byte, width int) []byte {
+	n := len(plain) / width
+	out := make([]byte, len(plain))
+	for i := range n {
+		for j := range width {
+			out[j*n+i] = plain[i*width+j]
+		}
+	}
+	return out
+}
+
+func readTestTable(t *testing.T, file []byte) *Table {
+	t.Helper()
+	table, err := ReadTable(bytes.NewReader(file), int64(len(file)))
+	if err != nil {
+		t.Fatalf("ReadTable failed: %v", err)
+	}
+	return table
+}
+
+func checkRows(t *testing.T, table *Table, want [][]string) {
+	t.Helper()
+	if len(table.Rows) != len(want) {
+		t.Fatalf("expected %d rows, got %d: %q", len(want), len(table.Rows), table.Rows)
+	}
+	for i := range want {
+		if !slices.Equal(table.Rows[i], want[i]) {
+			t.Errorf("row %d: expected %q, got %q", i, want[i], table.Rows[i])
+		}
+	}
+}
+
+// =============================================================================
+// Encoding Tests
+// =============================================================================
+
+// TestDecodeHybrid_SpecExample decodes the bit-packing example in the Parquet
+// encodings spec: 0 to 7 at bit width 3.
+func TestDecodeHybrid_SpecExample(t *testing.T) {
+	got, err := decodeHybrid([]byte{0x03, 0x88, 0xc6, 0xfa}, 3, 8)
+	if err != nil || !slices.Equal(got, []uint32{0, 1, 2, 3, 4, 5, 6, 7}) {
+		t.Errorf("expected 0..7, got %v (%v)", got, err)
+	}
+	// An RLE run of five 4s, then a bit-packed run cut short at n.
+	got, err = decodeHybrid([]byte{0x0a, 0x04, 0x03, 0x88, 0xc6, 0xfa}, 3, 7)
+	if err != nil || !slices.Equal(got, []uint32{4, 4, 4, 4, 4, 0, 1}) {
+		t.Errorf("expected RLE then bit-packed values, got %v (%v)", got, err)
+	}
+	if _, err := decodeHybrid([]byte{0x03, 0x88}, 3, 8); err == nil {
+		t.Error("expected error for a truncated bit-packed run")
+	}
+}
+
+// TestDecodeDeltaBinaryPacked_SpecExample decodes the spec's first example,
+// 1 to 5: every delta is the minimum, so the miniblocks have bit width 0.
+func TestDecodeDeltaBinaryPacked_SpecExample(t *testing.T) {
+	data := []byte{0x80, 0x01, 0x04, 0x05, 0x02, 0x02, 0, 0, 0, 0, 0xff}
+	got, rest, err := decodeDeltaBinaryPacked(data, 5)
+	if err != nil || !slices.Equal(got, []int64{1, 2, 3, 4, 5}) {
+		t.Fatalf("expected 1..5, got %v (%v)", got, err)
+	}
+	if !bytes.Equal(rest, []byte{0xff}) {
+		t.Errorf("expected the trailing byte left over, got %x", rest)
+	}
+
+	values := []int64{7, 5, 3, 1, 2, 3, 4, 5, 1000, -1000}
+	for i := range 300 {
+		values = append(values, int64(i*i))
+	}
+	got, _, err = decodeDeltaBinaryPacked(deltaPacked(values), len(values))
+	if err != nil || !slices.Equal(got, values) {
+		t.Errorf("delta round trip failed: %v", err)
+	}
+}
+
+// TestDecodePageHeader decodes a hand-encoded v1 data page header.
+func TestDecodePageHeader(t *testing.T) {
+	data := []byte{
+		0x15, 0x00, // type: DATA_PAGE
+		0x15, 0x14, // uncompressed_page_size: 10
+		0x15, 0x14, // compressed_page_size: 10
+		0x2c,       // data_page_header
+		0x15, 0x06, // num_values: 3
+		0x15, 0x10, // encoding: RLE_DICTIONARY
+		0x15, 0x06, 0x15, 0x06, // level encodings: RLE
+		0x00, 0x00,
+		0xaa, // page data
+	}
+	h, n, err := decodePageHeader(data)
+	if err != nil || n != len(data)-1 {
+		t.Fatalf("expected %d header bytes, got %d (%v)", len(data)-1, n, err)
+	}
+	if h.typ != pageData || h.compressed != 10 || h.numValues != 3 || h.encoding != encRLEDictionary {
+		t.Errorf("unexpected header %+v", h)
+	}
+}
+
+// =============================================================================
+// ReadTable Tests
+// =============================================================================
+
+func TestReadTable_Plain(t *testing.T) {
+	model := column("model", typeByteArray, repOptional)
+	model.converted = 0 // UTF8
+	model.pages = [][]byte{pageV1(t, codecUncompressed, encPlain, 3, []uint32{1, 0, 1}, plainStrings("gpt-4o", "claude"), false)}
+	model.numValues = 3
+
+	input := column("input_tokens", typeInt64, repRequired)
+	input.pages = [][]byte{pageV1(t, codecUncompressed, encPlain, 3, nil, plainInt64s(1000, 0, 1<<40), false)}
+	input.numValues = 3
+
+	at := column("timestamp", typeInt64, repRequired)
+	at.logical = func(w *thriftWriter) {
+		w.structField(8) // TIMESTAMP
+		w.bool(1, true)
+		w.structField(2)
+		w.structField(2) // MICROS
+		w.end()
+		w.end()
+		w.end()
+	}
+	at.pages = [][]byte{pageV1(t, codecUncompressed, encPlain, 3, nil, plainInt64s(1789000000123456, 0, -1), false)}
+	at.numValues = 3
+
+	day := column("day", typeInt32, repRequired)
+	day.converted = convertedDate
+	day.pages = [][]byte{pageV1(t, codecUncompressed, encPlain, 3, nil, plainInt32s(20000, 0, -1), false)}
+	day.numValues = 3
+
+	rate := column("rate", typeDouble, repRequired)
+	rate.pages = [][]byte{pageV1(t, codecUncompressed, encPlain, 3, nil, plainDoubles(2.5, 0, 1e6), false)}
+	rate.numValues = 3
+
+	batch := column("batch_mode", typeBoolean, repRequired)
+	batch.pages = [][]byte{pageV1(t, codecUncompressed, encPlain, 3, nil, []byte{0b101}, false)}
+	batch.numValues = 3
+
+	cols := []testColumn{model, input, at, day, rate, batch}
+	table := readTestTable(t, buildFile(cols, []int64{3}, [][]testColumn{cols}))
+
+	wantColumns := []Column{{"model", String}, {"input_tokens", Int64}, {"timestamp", String}, {"day", String}, {"rate", Double}, {"batch_mode", Boolean}}
+	if !slices.Equal(table.Columns, wantColumns) {
+		t.Errorf("expected columns %v, got %v", wantColumns, table.Columns)
+	}
+	checkRows(t, table, [][]string{
+		{"gpt-4o", "1000", "2026-09-10T00:26:40.123456Z", "2024-10-04", "2.5", "true"},
+		{"", "0", "1970-01-01T00:00:00Z", "1970-01-01", "0", "false"},
+		{"claude", "1099511627776", "1969-12-31T23:59:59.999999Z", "1969-12-31", "1000000", "true"},
+	})
+	if table.Index("rate") != 4 || table.Index("missing") != -1 {
+		t.Errorf("unexpected Index results %d, %d", table.Index("rate"), table.Index("missing"))
+	}
+}
+
+func TestReadTable_DictionaryAndCodecs(t *testing.T) {
+	for _, codec := range []int32{codecSnappy, codecGzip, codecZstd} {
+		group := func(indexes []uint32) []testColumn {
+			model := column("model", typeByteArray, repRequired)
+			model.codec = codec
+			model.dict = true
+			data := append([]byte{2}, bitPacked(indexes, 2)...)
+			model.pages = [][]byte{
+				pageV1(t, codec, encPlain, 3, nil, plainStrings("a", "b", "c"), true),
+				pageV1(t, codec, encRLEDictionary, len(indexes), nil, data, false),
+			}
+			model.numValues = int64(len(indexes))
+			return []testColumn{model}
+		}
+		schema := []testColumn{column("model", typeByteArray, repRequired)}
+		file := buildFile(schema, []int64{4, 2}, [][]testColumn{group([]uint32{2, 0, 1, 2}), group([]uint32{1, 1})})
+		table := readTestTable(t, file)
+		checkRows(t, table, [][]string{{"c"}, {"a"}, {"b"}, {"c"}, {"b"}, {"b"}})
+	}
+}
+
+func TestReadTable_DataPageV2(t *testing.T) {
+	tokens := column("input_tokens", typeInt64, repOptional)
+	tokens.codec = codecZstd
+	tokens.pages = [][]byte{pageV2(t, codecZstd, encDeltaBinaryPacked, 4, []uint32{1, 1, 0, 1}, deltaPacked([]int64{500, 400, 900}))}
+	tokens.numValues = 4
+
+	model := column("model", typeByteArray, repRequired)
+	model.codec = codecZstd
+	lengths := deltaPacked([]int64{6, 6, 2, 6})
+	model.pages = [][]byte{pageV2(t, codecZstd, encDeltaLengthByteArray, 4, nil, append(lengths, "gpt-4ogpt-4oo3o1-pro"...))}
+	model.numValues = 4
+
+	family := column("family", typeByteArray, repRequired)
+	prefixes := deltaPacked([]int64{0, 6, 5, 0})
+	suffixes := append(deltaPacked([]int64{6, 5, 2, 2}), "gpt-4o-mini.1o3"...)
+	family.pages = [][]byte{pageV2(t, codecUncompressed, encDeltaByteArray, 4, nil, append(prefixes, suffixes...))}
+	family.numValues = 4
+
+	// Two pages, each split into byte streams on its own.
+	cost := column("cost", typeDouble, repRequired)
+	cost.pages = [][]byte{
+		pageV2(t, codecUncompressed, encByteStreamSplit, 1, nil, byteStreamSplit(plainDoubles(1.5), 8)),
+		pageV2(t, codecUncompressed, encByteStreamSplit, 3, nil, byteStreamSplit(plainDoubles(-2, 0.25, 8), 8)),
+	}
+	cost.numValues = 4
+
+	cols := []testColumn{tokens, model, family, cost}
+	table := readTestTable(t, buildFile(cols, []int64{4}, [][]testColumn{cols}))
+	checkRows(t, table, [][]string{
+		{"500", "gpt-4o", "gpt-4o", "1.5"},
+		{"400", "gpt-4o", "gpt-4o-mini", "-2"},
+		{"", "o3", "gpt-4.1", "0.25"},
+		{"900", "o1-pro", "o3", "8"},
+	})
+}
+
+func TestReadTable_DecimalsAndLegacyTypes(t *testing.T) {
+	price := column("price", typeFixedLen, repRequired)
+	price.typeLength = 4
+	price.converted = convertedDecimal
+	price.scale = 2
+	price.pages = [][]byte{pageV1(t, codecUncompressed, encPlain, 3, nil, []byte{0, 0, 0x30, 0x39, 0xff, 0xff, 0xff, 0xfb, 0, 0, 0, 0}, false)}
+	price.numValues = 3
+
+	tokens := column("tokens", typeInt64, repRequired)
+	tokens.logical = func(w *thriftWriter) {
+		w.structField(5) // DECIMAL(38, 9), as BigQuery exports NUMERIC
+		w.i32(1, 9)
+		w.i32(2, 38)
+		w.end()
+	}
+	tokens.pages = [][]byte{pageV1(t, codecUncompressed, encPlain, 3, nil, plainInt64s(1000_000000000, 5, -1), false)}
+	tokens.numValues = 3
+
+	at := column("at", typeInt96, repRequired)
+	var int96 []byte
+	for _, day := range []uint32{2440588, 2440589, 2440587} {
+		int96 = binary.LittleEndian.AppendUint64(int96, uint64(3600e9))
+		int96 = binary.LittleEndian.AppendUint32(int96, day)
+	}
+	at.pages = [][]byte{pageV1(t, codecUncompressed, encPlain, 3, nil, int96, false)}
+	at.numValues = 3
+
+	count := column("count", typeInt32, repRequired)
+	count.converted = 13 // UINT_32
+	count.pages = [][]byte{pageV1(t, codecUncompressed, encPlain, 3, nil, plainInt32s(-1, 1, 0), false)}
+	count.numValues = 3
+
+	cols := []testColumn{price, tokens, at, count}
+	table := readTestTable(t, buildFile(cols, []int64{3}, [][]testColumn{cols}))
+	checkRows(t, table, [][]string{
+		{"123.45", "1000.000000000", "1970-01-01T01:00:00Z", "4294967295"},
+		{"-0.05", "0.000000005", "1970-01-02T01:00:00Z", "1"},
+		{"0.00", "-0.000000001", "1969-12-31T01:00:00Z", "0"},
+	})
+}
+
+func TestReadTable_SkipsNestedColumns(t *testing.T) {
+	model := column("model", typeByteArray, repRequired)
+	model.pages = [][]byte{pageV1(t, codecUncompressed, encPlain, 1, nil, plainStrings("gpt-4o"), false)}
+	model.numValues = 1
+	group := column("meta", -1, repOptional)
+	group.children = 1
+	tag := column("tag", typeByteArray, repRepeated)
+	tag.pages = [][]byte{[]byte("not decoded")}
+	tags := column("tags", typeInt64, repRepeated)
+	tags.pages = [][]byte{[]byte("not decoded")}
+
+	schema := []testColumn{model, group, tag, tags}
+	table := readTestTable(t, buildFile(schema, []int64{1}, [][]testColumn{{model, tag, tags}}))
+	if len(table.Columns) != 1 || table.Columns[0].Name != "model" {
+		t.Errorf("expected only the flat model column, got %v", table.Columns)
+	}
+	checkRows(t, table, [][]string{{"gpt-4o"}})
+
+	// Without a flat column there is nothing to read, however many rows the
+	// footer claims.
+	schema = []testColumn{tags}
+	table = readTestTable(t, buildFile(schema, []int64{1 << 40}, [][]testColumn{{tags}}))
+	if len(table.Columns) != 0 || len(table.Rows) != 0 {
+		t.Errorf("expected an empty table, got %v with %d rows", table.Columns, len(table.Rows))
+	}
+}
+
+// The testdata files were written by Apache Arrow's Go Parquet writer, so
+// they check the reader against real files rather than the builder above.
+func TestReadTable_WriterFiles(t *testing.T) {
+	usage := [][]string{
+		{"gpt-4o", "1000000", "1000000", "", "2026-09-01T12:00:00Z"},
+		{"gpt-4o", "2000", "300", "1024", "2026-09-01T12:30:00.123456Z"},
+		{"claude-sonnet-4-5", "", "700", "", "2026-09-02T08:15:00Z"},
+		{"gpt-4o", "500", "", "", ""},
+		{"mystery-model", "10", "5", "0", "2026-09-03T23:59:59Z"},
+	}
+	usageColumns := []Column{
+		{"model", String}, {"input_tokens", Int64}, {"output_tokens", Int64},
+		{"cached_tokens", Int64}, {"timestamp", String},
+	}
+	for _, tt := range []struct {
+		file    string
+		columns []Column
+		rows    [][]string
+	}{
+		{"dictionary_snappy.parquet", usageColumns, usage}, // Dictionary pages, SNAPPY
+		{"v2_zstd_delta.parquet", usageColumns, usage},     // Data page v2, ZSTD, DELTA_* encodings
+		{"int96_gzip.parquet", usageColumns, usage},        // INT96 timestamps, GZIP
+		{"decimal_types.parquet", []Column{ // Decimals on INT32, INT64 and FIXED_LEN_BYTE_ARRAY
+			{"price", String}, {"rate", String}, {"total", String}, {"day", String},
+			{"ratio", Double}, {"cost", Double}, {"batch", Boolean},
+		}, [][]string{
+			{"19.99", "2.500000", "123456789012345678901.234", "2026-09-01", "0.5", "12.5", "true"},
+			{"-0.05", "0.000003", "-0.001", "2026-09-02", "0.1", "0.000003", "false"},
+			{"", "-1.234567", "", "", "", "", ""},
+		}},
+	} {
+		t.Run(tt.file, func(t *testing.T) {
+			file, err := os.ReadFile("testdata/" + tt.file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			table := readTestTable(t, file)
+			if !slices.Equal(table.Columns, tt.columns) {
+				t.Errorf("expected columns %v, got %v", tt.columns, table.Columns)
+			}
+			checkRows(t, table, tt.rows)
+		})
+	}
+}
+
+func TestReadTable_Errors(t *testing.T) {
+	model := column("model", typeByteArray, repRequired)
+	model.pages = [][]byte{pageV1(t, codecUncompressed, encPlain, 1, nil, plainStrings("gpt-4o"), false)}
+	model.numValues = 1
+	valid := buildFile([]testColumn{model}, []int64{1}, [][]testColumn{{model}})
+
+	lz4 := model
+	lz4.codec = 7
+	short := model
+	short.numValues = 2
+	// A page claiming a billion values must fail before anything is sized by
+	// that count, as must a PLAIN page too small for its values.
+	huge := model
+	huge.pages = [][]byte{pageV1(t, codecUncompressed, encPlain, 1<<30, nil, plainStrings("gpt-4o"), false)}
+	sparse := model
+	sparse.pages = [][]byte{pageV1(t, codecUncompressed, encPlain, 1000, nil, plainStrings("gpt-4o"), false)}
+	sparse.numValues = 1000
+
+	for name, file := range map[string][]byte{
+		"not parquet":      []byte("model,input_tokens\ngpt-4o,100\n"),
+		"truncated":        valid[:len(valid)-20],
+		"corrupt footer":   append(append(append([]byte{}, valid[:len(valid)-8]...), 0xff, 0xff, 0, 0), Magic...),
+		"LZ4_RAW":          buildFile([]testColumn{lz4}, []int64{1}, [][]testColumn{{lz4}}),
+		"row count":        buildFile([]testColumn{model}, []int64{2}, [][]testColumn{{model}}),
+		"missing values":   buildFile([]testColumn{short}, []int64{2}, [][]testColumn{{short}}),
+		"missing a column": buildFile([]testColumn{model, model}, []int64{1}, [][]testColumn{{model}}),
+		"footer rows":      buildFile([]testColumn{model}, []int64{1, 1}, [][]testColumn{{model}}),
+		"page values":      buildFile([]testColumn{huge}, []int64{1}, [][]testColumn{{huge}}),
+		"plain values":     buildFile([]testColumn{sparse}, []int64{1000}, [][]testColumn{{sparse}}),
+	} {
+		_, err := ReadTable(bytes.NewReader(file), int64(len(file)))
+		if err == nil || !strings.HasPrefix(err.Error(), "parquet: ") {
+			t.Errorf("%s: expected parquet error, got %v", name, err)
+		}
+	}
+}
+
+// =============================================================================
+// WriteTable Tests
+// =============================================================================
+
+func writeTestTable(t *testing.T, table *Table) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := WriteTable(&buf, table); err != nil {
+		t.Fatalf("WriteTable: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestWriteTable_RoundTrip(t *testing.T) {
+	in := &Table{
+		Columns: []Column{{"model", String}, {"tokens", Int64}, {"rate", Double}, {"batch", Boolean}},
+		Rows: [][]string{
+			{"gpt-4o", "1000000", "2.5", "true"},
+			{"", "", "", ""},
+			{"claude-sonnet-4", "-7", "0.000003", "false"},
+		},
+	}
+	out := readTestTable(t, writeTestTable(t, in))
+	if !slices.Equal(out.Columns, in.Columns) {
+		t.Errorf("columns %v, want %v", out.Columns, in.Columns)
+	}
+	checkRows(t, out, in.Rows)
+}
+
+func TestWriteTable_Pages(t *testing.T) {
+	in := &Table{Columns: []Column{{"n", Int64}, {"even", Boolean}}}
+	for i := range maxPageRows + 10 {
+		row := []string{strconv.Itoa(i), strconv.FormatBool(i%2 == 0)}
+		if i%3 == 0 {
+			row[0] = ""
+		}
+		in.Rows = append(in.Rows, row)
+	}
+	checkRows(t, readTestTable(t, writeTestTable(t, in)), in.Rows)
+
+	empty := readTestTable(t, writeTestTable(t, &Table{Columns: in.Columns}))
+	if !slices.Equal(empty.Columns, in.Columns) || len(empty.Rows) != 0 {
+		t.Errorf("expected an empty table with the columns, got %+v", empty)
+	}
+}
+
+func TestWriteTable_Errors(t *testing.T) {
+	for name, table := range map[string]*Table{
+		"no columns":  {},
+		"short row":   {Columns: []Column{{"a", String}, {"b", String}}, Rows: [][]string{{"x"}}},
+		"bad integer": {Columns: []Column{{"n", Int64}}, Rows: [][]string{{"1.5"}}},
+		"bad boolean": {Columns: []Column{{"b", Boolean}}, Rows: [][]string{{"maybe"}}},
+	} {
+		err := WriteTable(io.Discard, table)
+		if err == nil || !strings.HasPrefix(err.Error(), "parquet: ") {
+			t.Errorf("%s: expected parquet error, got %v", name, err)
+		}
+	}
+}
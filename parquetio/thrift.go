@@ -0,0 +1,267 @@
+package parquetio
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+)
+
+// Thrift compact protocol type codes, as used in field headers and list
+// headers. A boolean field carries its value in the type code.
+const (
+	ctTrue   = 1
+	ctFalse  = 2
+	ctByte   = 3
+	ctI16    = 4
+	ctI32    = 5
+	ctI64    = 6
+	ctDouble = 7
+	ctBinary = 8
+	ctList   = 9
+	ctSet    = 10
+	ctMap    = 11
+	ctStruct = 12
+)
+
+// maxThriftDepth bounds struct nesting, so a corrupt footer cannot recurse
+// without limit.
+const maxThriftDepth = 64
+
+var errThrift = errors.New("corrupt thrift metadata")
+
+// thriftReader decodes the Thrift compact protocol that Parquet metadata is
+// written in. The first error sticks; later reads return zero values.
+type thriftReader struct {
+	b     []byte
+	depth int
+	err   error
+}
+
+func (r *thriftReader) fail() {
+	if r.err == nil {
+		r.err = errThrift
+	}
+	r.b = nil
+}
+
+func (r *thriftReader) byte() byte {
+	if len(r.b) == 0 {
+		r.fail()
+		return 0
+	}
+	c := r.b[0]
+	r.b = r.b[1:]
+	return c
+}
+
+func (r *thriftReader) uvarint() uint64 {
+	v, n := binary.Uvarint(r.b)
+	if n <= 0 {
+		r.fail()
+		return 0
+	}
+	r.b = r.b[n:]
+	return v
+}
+
+// i64 reads a zigzag varint, the compact encoding of i16, i32 and i64.
+func (r *thriftReader) i64() int64 {
+	v, n := binary.Varint(r.b)
+	if n <= 0 {
+		r.fail()
+		return 0
+	}
+	r.b = r.b[n:]
+	return v
+}
+
+func (r *thriftReader) i32() int32 {
+	v := r.i64()
+	if v < math.MinInt32 || v > math.MaxInt32 {
+		r.fail()
+		return 0
+	}
+	return int32(v)
+}
+
+func (r *thriftReader) binary() []byte {
+	n := r.uvarint()
+	if n > uint64(len(r.b)) {
+		r.fail()
+		return nil
+	}
+	v := r.b[:n]
+	r.b = r.b[n:]
+	return v
+}
+
+// fields calls fn with the id and type of each field of a struct; fn must
+// read or skip the value. A boolean field's value is typ == ctTrue.
+func (r *thriftReader) fields(fn func(id int16, typ byte)) {
+	if r.depth++; r.depth > maxThriftDepth {
+		r.fail()
+	}
+	defer func() { r.depth-- }()
+	var id int16
+	for r.err == nil {
+		h := r.byte()
+		if h == 0 {
+			return
+		}
+		if delta := int16(h >> 4); delta != 0 {
+			id += delta
+		} else {
+			id = int16(r.i64())
+		}
+		fn(id, h&0x0f)
+	}
+}
+
+// list reads a list or set header and calls fn with the element type once
+// per element.
+func (r *thriftReader) list(fn func(elem byte)) {
+	h := r.byte()
+	n := uint64(h >> 4)
+	if n == 15 {
+		n = r.uvarint()
+	}
+	// Every element takes at least one byte.
+	if n > uint64(len(r.b)) {
+		r.fail()
+	}
+	for i := uint64(0); i < n && r.err == nil; i++ {
+		fn(h & 0x0f)
+	}
+}
+
+// structs reads a list of structs, calling fn to read each one.
+func (r *thriftReader) structs(fn func()) {
+	r.list(func(elem byte) {
+		if elem != ctStruct {
+			r.fail()
+			return
+		}
+		fn()
+	})
+}
+
+// skip skips a value of type typ in a struct field.
+func (r *thriftReader) skip(typ byte) {
+	switch typ {
+	case ctTrue, ctFalse:
+	case ctByte:
+		r.byte()
+	case ctI16, ctI32, ctI64:
+		r.i64()
+	case ctDouble:
+		if len(r.b) < 8 {
+			r.fail()
+			return
+		}
+		r.b = r.b[8:]
+	case ctBinary:
+		r.binary()
+	case ctList, ctSet:
+		r.list(r.skipElem)
+	case ctMap:
+		n := r.uvarint()
+		if n == 0 {
+			return
+		}
+		kv := r.byte()
+		if n > uint64(len(r.b)) {
+			r.fail()
+		}
+		for i := uint64(0); i < n && r.err == nil; i++ {
+			r.skipElem(kv >> 4)
+			r.skipElem(kv & 0x0f)
+		}
+	case ctStruct:
+		r.fields(func(_ int16, typ byte) { r.skip(typ) })
+	default:
+		r.fail()
+	}
+}
+
+// skipElem skips a list, set or map element; booleans there take a byte.
+func (r *thriftReader) skipElem(typ byte) {
+	if typ == ctTrue || typ == ctFalse {
+		r.byte()
+		return
+	}
+	r.skip(typ)
+}
+
+// thriftWriter writes the Thrift compact protocol, for the page headers and
+// footer WriteTable emits. Callers open each struct with begin (or
+// structField) and close it with end; list elements follow list.
+type thriftWriter struct {
+	buf  []byte
+	last []int16 // Last field id of each open struct
+}
+
+func (w *thriftWriter) field(id int16, typ byte) {
+	last := &w.last[len(w.last)-1]
+	if delta := id - *last; delta > 0 && delta <= 15 {
+		w.buf = append(w.buf, byte(delta)<<4|typ)
+	} else {
+		w.buf = append(w.buf, typ)
+		w.buf = binary.AppendVarint(w.buf, int64(id))
+	}
+	*last = id
+}
+
+func (w *thriftWriter) begin() { w.last = append(w.last, 0) }
+
+func (w *thriftWriter) end() {
+	w.buf = append(w.buf, 0)
+	w.last = w.last[:len(w.last)-1]
+}
+
+func (w *thriftWriter) i32(id int16, v int32) {
+	w.field(id, ctI32)
+	w.buf = binary.AppendVarint(w.buf, int64(v))
+}
+
+func (w *thriftWriter) i64(id int16, v int64) {
+	w.field(id, ctI64)
+	w.buf = binary.AppendVarint(w.buf, v)
+}
+
+func (w *thriftWriter) bool(id int16, v bool) {
+	if v {
+		w.field(id, ctTrue)
+	} else {
+		w.field(id, ctFalse)
+	}
+}
+
+func (w *thriftWriter) binary(id int16, v string) {
+	w.field(id, ctBinary)
+	w.elemBinary(v)
+}
+
+func (w *thriftWriter) structField(id int16) {
+	w.field(id, ctStruct)
+	w.begin()
+}
+
+// list writes a list field header; the n elements follow.
+func (w *thriftWriter) list(id int16, elem byte, n int) {
+	w.field(id, ctList)
+	if n < 15 {
+		w.buf = append(w.buf, byte(n)<<4|elem)
+	} else {
+		w.buf = append(w.buf, 0xf0|elem)
+		w.buf = binary.AppendUvarint(w.buf, uint64(n))
+	}
+}
+
+// elemI32 writes an i32 list element.
+func (w *thriftWriter) elemI32(v int32) { w.buf = binary.AppendVarint(w.buf, int64(v)) }
+
+// elemBinary writes a binary (string) list element.
+func (w *thriftWriter) elemBinary(v string) {
+	w.buf = binary.AppendUvarint(w.buf, uint64(len(v)))
+	w.buf = append(w.buf, v...)
+}
@@ -0,0 +1,245 @@
+package parquetio
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+
+	"github.com/klauspost/compress/snappy"
+)
+
+// maxPageRows bounds the rows in each data page WriteTable emits, keeping
+// pages small enough for readers that buffer a page at a time.
+const maxPageRows = 1 << 16
+
+// WriteTable writes t to w as a Parquet file with one row group, or none when
+// t has no rows. Every column is optional: an empty cell is written as null,
+// and any other cell must parse as the column's Type. String columns are UTF-8
+// BYTE_ARRAY; Int64, Double and Boolean columns use the matching physical
+// type. Pages are PLAIN encoded and snappy compressed, which every warehouse
+// loads.
+func WriteTable(w io.Writer, t *Table) error {
+	if err := writeTable(w, t); err != nil {
+		return fmt.Errorf("parquet: %w", err)
+	}
+	return nil
+}
+
+func writeTable(w io.Writer, t *Table) error {
+	if len(t.Columns) == 0 {
+		return errors.New("table has no columns")
+	}
+	for i, row := range t.Rows {
+		if len(row) != len(t.Columns) {
+			return fmt.Errorf("row %d has %d cells, table has %d columns", i+1, len(row), len(t.Columns))
+		}
+	}
+
+	out := bytes.NewBuffer(append([]byte(nil), Magic...))
+	chunks := make([]columnChunkInfo, len(t.Columns))
+	for i, c := range t.Columns {
+		chunk, err := writeChunk(out, t, i)
+		if err != nil {
+			return fmt.Errorf("column %s: %w", c.Name, err)
+		}
+		chunks[i] = chunk
+	}
+	footer := encodeFooter(t, chunks)
+	out.Write(footer)
+	out.Write(binary.LittleEndian.AppendUint32(nil, uint32(len(footer))))
+	out.Write(Magic)
+	_, err := w.Write(out.Bytes())
+	return err
+}
+
+// columnChunkInfo records where writeChunk put a column's pages.
+type columnChunkInfo struct {
+	offset           int64
+	uncompressedSize int64
+	compressedSize   int64
+}
+
+// writeChunk appends column col's data pages to out.
+func writeChunk(out *bytes.Buffer, t *Table, col int) (columnChunkInfo, error) {
+	info := columnChunkInfo{offset: int64(out.Len())}
+	typ := t.Columns[col].Type
+	for start := 0; start < len(t.Rows); start += maxPageRows {
+		rows := t.Rows[start:min(start+maxPageRows, len(t.Rows))]
+		defs := make([]bool, len(rows))
+		var values []byte
+		var bools []bool
+		for i, row := range rows {
+			cell := row[col]
+			if cell == "" {
+				continue
+			}
+			defs[i] = true
+			var err error
+			switch typ {
+			case String:
+				values = binary.LittleEndian.AppendUint32(values, uint32(len(cell)))
+				values = append(values, cell...)
+			case Int64:
+				var v int64
+				v, err = strconv.ParseInt(cell, 10, 64)
+				values = binary.LittleEndian.AppendUint64(values, uint64(v))
+			case Double:
+				var v float64
+				v, err = strconv.ParseFloat(cell, 64)
+				values = binary.LittleEndian.AppendUint64(values, math.Float64bits(v))
+			case Boolean:
+				var v bool
+				v, err = strconv.ParseBool(cell)
+				bools = append(bools, v)
+			default:
+				return info, fmt.Errorf("unknown type %d", typ)
+			}
+			if err != nil {
+				return info, fmt.Errorf("row %d: %w", start+i+1, err)
+			}
+		}
+		if typ == Boolean {
+			values = packBools(bools)
+		}
+
+		levels := encodeLevels(defs)
+		body := binary.LittleEndian.AppendUint32(nil, uint32(len(levels)))
+		body = append(append(body, levels...), values...)
+		page := snappy.Encode(nil, body)
+		if len(body) > math.MaxInt32 || len(page) > math.MaxInt32 {
+			return info, errors.New("page exceeds 2 GiB")
+		}
+
+		h := &thriftWriter{}
+		h.begin()
+		h.i32(1, pageData)
+		h.i32(2, int32(len(body)))
+		h.i32(3, int32(len(page)))
+		h.structField(5)
+		h.i32(1, int32(len(rows)))
+		h.i32(2, encPlain)
+		h.i32(3, encRLE)
+		h.i32(4, encRLE)
+		h.end()
+		h.end()
+		out.Write(h.buf)
+		out.Write(page)
+		info.uncompressedSize += int64(len(h.buf) + len(body))
+		info.compressedSize += int64(len(h.buf) + len(page))
+	}
+	return info, nil
+}
+
+// encodeLevels encodes definition levels of bit width 1 as RLE runs.
+func encodeLevels(defs []bool) []byte {
+	var b []byte
+	for i := 0; i < len(defs); {
+		j := i + 1
+		for j < len(defs) && defs[j] == defs[i] {
+			j++
+		}
+		b = binary.AppendUvarint(b, uint64(j-i)<<1)
+		if defs[i] {
+			b = append(b, 1)
+		} else {
+			b = append(b, 0)
+		}
+		i = j
+	}
+	return b
+}
+
+// packBools bit-packs PLAIN booleans, least significant bit first.
+func packBools(values []bool) []byte {
+	b := make([]byte, (len(values)+7)/8)
+	for i, v := range values {
+		if v {
+			b[i/8] |= 1 << (i % 8)
+		}
+	}
+	return b
+}
+
+// physicalType returns the physical type WriteTable stores typ as.
+func physicalType(typ Type) int32 {
+	switch typ {
+	case Int64:
+		return typeInt64
+	case Double:
+		return typeDouble
+	case Boolean:
+		return typeBoolean
+	}
+	return typeByteArray
+}
+
+// encodeFooter encodes the FileMetaData for t's row group; a table without
+// rows gets none.
+func encodeFooter(t *Table, chunks []columnChunkInfo) []byte {
+	w := &thriftWriter{}
+	w.begin()
+	w.i32(1, 1) // Format version
+	w.list(2, ctStruct, len(t.Columns)+1)
+	w.begin()
+	w.binary(4, "schema")
+	w.i32(5, int32(len(t.Columns)))
+	w.end()
+	for _, c := range t.Columns {
+		w.begin()
+		w.i32(1, physicalType(c.Type))
+		w.i32(3, repOptional)
+		w.binary(4, c.Name)
+		if c.Type == String {
+			w.i32(6, convertedUTF8)
+			w.structField(10)
+			w.structField(logicalString)
+			w.end()
+			w.end()
+		}
+		w.end()
+	}
+	w.i64(3, int64(len(t.Rows)))
+
+	if len(t.Rows) == 0 {
+		w.list(4, ctStruct, 0)
+		w.binary(6, "pricing_db parquetio")
+		w.end()
+		return w.buf
+	}
+	var total int64
+	for _, c := range chunks {
+		total += c.uncompressedSize
+	}
+	w.list(4, ctStruct, 1)
+	w.begin()
+	w.list(1, ctStruct, len(t.Columns))
+	for i, c := range t.Columns {
+		chunk := chunks[i]
+		w.begin()
+		w.i64(2, chunk.offset)
+		w.structField(3)
+		w.i32(1, physicalType(c.Type))
+		w.list(2, ctI32, 2)
+		w.elemI32(encPlain)
+		w.elemI32(encRLE)
+		w.list(3, ctBinary, 1)
+		w.elemBinary(c.Name)
+		w.i32(4, codecSnappy)
+		w.i64(5, int64(len(t.Rows)))
+		w.i64(6, chunk.uncompressedSize)
+		w.i64(7, chunk.compressedSize)
+		w.i64(9, chunk.offset)
+		w.end()
+		w.end()
+	}
+	w.i64(2, total)
+	w.i64(3, int64(len(t.Rows)))
+	w.end()
+	w.binary(6, "pricing_db parquetio")
+	w.end()
+	return w.buf
+}
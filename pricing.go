@@ -1,17 +1,24 @@
 package pricing_db
 
 import (
-	"encoding/json"
+	"cmp"
+	"context"
 	"fmt"
 	"io/fs"
+	"log/slog"
+	"maps"
 	"math"
+	"path"
+	"slices"
 	"sort"
 	"strings"
-	"sync"
+	"sync/atomic"
+	"time"
 )
 
 // defaultCacheMultiplier is the default discount rate for cached tokens (10%)
-// when no explicit cache_read_multiplier is configured.
+// when neither the model nor its provider configures a cache_read_multiplier
+// and WithDefaultCacheMultiplier is not given.
 const defaultCacheMultiplier = 0.10
 
 // TokensPerMillion is the divisor for per-million token pricing calculations.
@@ -21,7 +28,7 @@ const TokensPerMillion = 1_000_000.0
 // 9 decimal places = nano-cents, sufficient for very low per-request costs.
 const costPrecision = 9
 
-// queriesPerThousand is the divisor for per-thousand grounding query pricing.
+// queriesPerThousand is the divisor for per-thousand grounding query and search source pricing.
 const queriesPerThousand = 1000.0
 
 // addInt64Safe adds two int64 values with overflow protection.
@@ -45,35 +52,108 @@ func roundToPrecision(value float64, precision int) float64 {
 }
 
 // Pricer calculates costs across all providers.
-// Thread-safe with RWMutex for concurrent access.
+// Thread-safe without locks: reads go through an immutable catalog
+// loaded from an atomic pointer, and Reload swaps in a new one.
+//
+// Create a Pricer with NewPricer, NewPricerFromFS or another constructor.
+// A zero Pricer has an empty catalog: every model is unknown and lookups
+// find nothing, until Reload loads pricing into it.
 type Pricer struct {
-	models               map[string]ModelPricing
-	modelKeysSorted      []string // sorted by length descending for prefix matching
-	imageModels          map[string]ImageModelPricing
-	imageModelKeysSorted []string // sorted by length descending for prefix matching
-	grounding            map[string]GroundingPricing
-	groundingKeys        []string // sorted by length descending for prefix matching
-	credits              map[string]*CreditPricing
-	providers            map[string]ProviderPricing
-	mu                   sync.RWMutex
+	cat atomic.Pointer[catalog]
+}
+
+// emptyCatalog backs a zero Pricer.
+var emptyCatalog = buildPricer(&catalog{}).cat.Load()
+
+// load returns the current catalog, or emptyCatalog for a zero Pricer.
+func (p *Pricer) load() *catalog {
+	if c := p.cat.Load(); c != nil {
+		return c
+	}
+	return emptyCatalog
+}
+
+// catalog is the merged pricing data behind a Pricer. It is never
+// modified after construction, so readers need no synchronization.
+type catalog struct {
+	models                map[string]ModelPricing
+	modelProviders        map[string]string      // model key -> provider supplying its pricing
+	modelKeysSorted       []string               // sorted by length descending for prefix matching
+	rates                 map[string]*modelRates // precompiled rates, keyed like models
+	imageModels           map[string]ImageModelPricing
+	imageModelKeysSorted  []string // sorted by length descending for prefix matching
+	grounding             map[string]GroundingPricing
+	groundingKeys         []string // sorted by length descending for prefix matching
+	credits               map[string]*CreditPricing
+	rerankModels          map[string]RerankPricing
+	rerankModelKeysSorted []string // sorted by length descending for prefix matching
+	instances             map[string]InstancePricing
+	providers             map[string]ProviderPricing
+	rounding              rounder                      // applied to cost totals
+	cacheDefault          float64                      // cache_read_multiplier for models and providers without one
+	selfHosted            map[string]SelfHostedPricing // keyed like models, for self-hosted models only
+	staleAfter            time.Duration                // warn when a provider's metadata is older; 0 = never
+	tokenCounter          TokenCounter                 // nil = ApproxTokenCount
+	version               string                       // see Pricer.Version
+	stampTime             bool                         // set CostDetails.CalculatedAt
+	fallback              *modelRates                  // rates for unknown models (WithFallbackPricing); nil = price them at 0
+	familyFallback        bool                         // price unknown models as their closest family member
+	families              []familyMember               // versioned model keys, when familyFallback
+	lookups               *lookupCounters              // lookup hit/miss counters (WithLookupStats); nil = not counted
+	resolved              *lookupCache                 // prefix-match results (WithLookupCache); nil = not cached
+	markup                *markupTable                 // reseller markups (WithMarkup); nil = bill provider cost
+	taxRate               float64                      // tax on the billed total (WithTaxRate); 0 = no tax
+	logger                *slog.Logger                 // diagnostic events (WithLogger); nil = none
+	tracer                Tracer                       // calculation spans (WithTracer); nil = none
+	unknown               *negativeCache               // model names with no match (WithNegativeCache); nil = not cached
+}
+
+// EmbeddedConfigFS returns the embedded pricing configuration filesystem.
+// This provides a read-only accessor that cannot be reassigned.
+// Prefer this over direct ConfigFS access in new code.
+func EmbeddedConfigFS() fs.FS {
+	return ConfigFS
 }
 
 // NewPricer creates a new Pricer from embedded configs.
 // Uses go:embed for compiled-in pricing data.
-func NewPricer() (*Pricer, error) {
-	return NewPricerFromFS(ConfigFS, "configs")
+func NewPricer(opts ...Option) (*Pricer, error) {
+	return NewPricerFromFS(ConfigFS, "configs", opts...)
 }
 
 // NewPricerFromFS creates a Pricer from a custom filesystem.
 // Useful for testing or loading from external sources.
-func NewPricerFromFS(fsys fs.FS, dir string) (*Pricer, error) {
+// Files named "*_pricing.json" are loaded; other formats such as
+// "*_pricing.yaml" are loaded when a decoder is registered with WithDecoder.
+func NewPricerFromFS(fsys fs.FS, dir string, opts ...Option) (*Pricer, error) {
+	return LoadContext(context.Background(), fsys, dir, opts...)
+}
+
+// LoadContext is like NewPricerFromFS but stops with ctx.Err() once ctx is done,
+// including while the config directory or a config file is being read. Use it
+// with a deadline when fsys is backed by a network or other slow source so
+// startup cannot hang on it. A read blocked past the deadline is abandoned,
+// not interrupted: it finishes in the background and its result is dropped.
+func LoadContext(ctx context.Context, fsys fs.FS, dir string, opts ...Option) (*Pricer, error) {
+	o := newPricerOptions(opts)
+	if !validCollisionPolicy(o.collisionPolicy) {
+		return nil, fmt.Errorf("unknown collision policy %q", o.collisionPolicy)
+	}
+	fallback, err := o.validate()
+	if err != nil {
+		return nil, err
+	}
+
 	models := make(map[string]ModelPricing)
+	modelProviders := make(map[string]string)
 	imageModels := make(map[string]ImageModelPricing)
 	grounding := make(map[string]GroundingPricing)
 	credits := make(map[string]*CreditPricing)
+	rerankModels := make(map[string]RerankPricing)
+	instances := make(map[string]InstancePricing)
 	providers := make(map[string]ProviderPricing)
 
-	entries, err := fs.ReadDir(fsys, dir)
+	entries, err := readContext(ctx, func() ([]fs.DirEntry, error) { return fs.ReadDir(fsys, dir) })
 	if err != nil {
 		return nil, fmt.Errorf("read config dir: %w", err)
 	}
@@ -85,25 +165,46 @@ func NewPricerFromFS(fsys fs.FS, dir string) (*Pricer, error) {
 	})
 
 	for _, entry := range entries {
-		if entry.IsDir() || !strings.HasSuffix(entry.Name(), "_pricing.json") {
+		if entry.IsDir() {
+			continue
+		}
+		suffix, ok := o.pricingFileSuffix(entry.Name())
+		if !ok {
 			continue
 		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 
-		path := dir + "/" + entry.Name()
-		data, err := fs.ReadFile(fsys, path)
+		name := path.Join(dir, entry.Name())
+		data, err := readContext(ctx, func() ([]byte, error) { return fs.ReadFile(fsys, name) })
 		if err != nil {
 			return nil, fmt.Errorf("read %s: %w", entry.Name(), err)
 		}
 
-		var file pricingFile
-		if err := json.Unmarshal(data, &file); err != nil {
+		file, err := o.decodePricingFile(data, strings.TrimPrefix(suffix, "_pricing"))
+		if err != nil {
 			return nil, fmt.Errorf("parse %s: %w", entry.Name(), err)
 		}
+		if err := migratePricingFile(&file, entry.Name()); err != nil {
+			return nil, err
+		}
 
-		// Infer provider name from filename if not in JSON
+		// Infer provider name from filename if not in the file
 		providerName := file.Provider
 		if providerName == "" {
-			providerName = strings.TrimSuffix(entry.Name(), "_pricing.json")
+			providerName = strings.TrimSuffix(entry.Name(), suffix)
+		}
+
+		if err := addSelfHostedModels(&file, entry.Name()); err != nil {
+			return nil, err
+		}
+		if err := resolveImageDefaults(file.ImageModels, entry.Name()); err != nil {
+			return nil, err
+		}
+
+		if d := file.DefaultCacheReadMultiplier; d < 0 || d > 1.0 {
+			return nil, fmt.Errorf("%s: default_cache_read_multiplier %f out of range (0-1)", entry.Name(), d)
 		}
 
 		providers[providerName] = ProviderPricing{
@@ -112,9 +213,17 @@ func NewPricerFromFS(fsys fs.FS, dir string) (*Pricer, error) {
 			Models:            file.Models,
 			ImageModels:       file.ImageModels,
 			Grounding:         file.Grounding,
+			Surcharges:        file.Surcharges,
+			SelfHostedModels:  file.SelfHostedModels,
 			CreditPricing:     file.CreditPricing,
 			SubscriptionTiers: file.SubscriptionTiers,
+			RerankModels:      file.RerankModels,
+			InstanceTypes:     file.InstanceTypes,
+			ModelHardware:     file.ModelHardware,
 			Metadata:          file.Metadata,
+
+			DefaultCacheReadMultiplier: file.DefaultCacheReadMultiplier,
+			SourceFile:                 entry.Name(),
 		}
 
 		// Merge models into flat lookup (with validation)
@@ -124,17 +233,23 @@ func NewPricerFromFS(fsys fs.FS, dir string) (*Pricer, error) {
 				return nil, err
 			}
 			// Ensure tiers are sorted by threshold ascending for correct calculation logic
-			if len(pricing.Tiers) > 1 {
-				sort.Slice(pricing.Tiers, func(i, j int) bool {
-					return pricing.Tiers[i].ThresholdTokens < pricing.Tiers[j].ThresholdTokens
-				})
+			sortTiers(pricing.Tiers)
+			for _, period := range pricing.PriceHistory {
+				sortTiers(period.Tiers)
+			}
+			// Fill in the provider's cache discount (shared with providers[providerName].Models)
+			if pricing.CacheReadMultiplier == 0 && file.DefaultCacheReadMultiplier > 0 {
+				pricing.CacheReadMultiplier = file.DefaultCacheReadMultiplier
+				file.Models[model] = pricing
 			}
 			// Only add if not already present (keep first occurrence)
 			if _, exists := models[model]; !exists {
 				models[model] = pricing
+				modelProviders[model] = providerName
 			}
 			// Also add provider-namespaced key for disambiguation (always unique per provider)
 			models[providerName+"/"+model] = pricing
+			modelProviders[providerName+"/"+model] = providerName
 		}
 
 		// Merge grounding pricing (with validation)
@@ -149,6 +264,10 @@ func NewPricerFromFS(fsys fs.FS, dir string) (*Pricer, error) {
 			}
 		}
 
+		if err := validateSurcharges(file.Surcharges, "provider", entry.Name()); err != nil {
+			return nil, err
+		}
+
 		// Store credit pricing (with validation)
 		if file.CreditPricing != nil {
 			if err := validateCreditPricing(file.CreditPricing, entry.Name()); err != nil {
@@ -170,27 +289,216 @@ func NewPricerFromFS(fsys fs.FS, dir string) (*Pricer, error) {
 			// Also add provider-namespaced key for disambiguation (always unique per provider)
 			imageModels[providerName+"/"+model] = pricing
 		}
+
+		// Merge rerank models into flat lookup (with validation)
+		// Keep first occurrence for duplicates (files are processed alphabetically)
+		for model, pricing := range file.RerankModels {
+			if err := validateRerankPricing(model, pricing, entry.Name()); err != nil {
+				return nil, err
+			}
+			if _, exists := rerankModels[model]; !exists {
+				rerankModels[model] = pricing
+			}
+			rerankModels[providerName+"/"+model] = pricing
+		}
+
+		// Merge instance types into flat lookup (with validation)
+		// Keep first occurrence for duplicates (files are processed alphabetically).
+		// Replicate's hardware SKUs ("cpu", "gpu-t4") are generic names billed
+		// per second, so they are registered only under "replicate/<sku>".
+		for instanceType, pricing := range file.InstanceTypes {
+			if err := validateInstancePricing(instanceType, pricing, entry.Name()); err != nil {
+				return nil, err
+			}
+			if _, exists := instances[instanceType]; !exists && providerName != replicateProvider {
+				instances[instanceType] = pricing
+			}
+			instances[providerName+"/"+instanceType] = pricing
+		}
+		if err := validateModelHardware(file.ModelHardware, file.InstanceTypes, entry.Name()); err != nil {
+			return nil, err
+		}
 	}
 
 	if len(providers) == 0 {
 		return nil, fmt.Errorf("no pricing files found in %s", dir)
 	}
 
-	// Build sorted keys for deterministic prefix matching (longest first)
-	modelKeys := sortedKeysByLengthDesc(models)
-	imageModelKeys := sortedKeysByLengthDesc(imageModels)
-	groundingKeys := sortedKeysByLengthDesc(grounding)
+	if o.collisionPolicy == CollisionProviderPriority {
+		preferImageProviders(imageModels, providers, o.priority)
+	}
+	resolveCollisions(models, modelProviders, providers, o.collisionPolicy, o.priority)
+
+	if o.failOnCollision {
+		if collisions := findCollisions(providers, modelProviders); len(collisions) > 0 {
+			return nil, collisionError(collisions)
+		}
+	}
+	if o.logger != nil {
+		logCollisions(o.logger, findCollisions(providers, modelProviders))
+	}
+
+	return o.newPricer(&catalog{
+		models:         models,
+		modelProviders: modelProviders,
+		imageModels:    imageModels,
+		grounding:      grounding,
+		credits:        credits,
+		rerankModels:   rerankModels,
+		instances:      instances,
+		providers:      providers,
+	}, fallback)
+}
+
+// validate checks the options that apply to every catalog, however it is
+// loaded, and compiles the WithFallbackPricing rates.
+func (o *pricerOptions) validate() (*modelRates, error) {
+	if err := o.rounding.validate(); err != nil {
+		return nil, err
+	}
+	if o.cacheDefault < 0 || o.cacheDefault > 1.0 {
+		return nil, fmt.Errorf("default cache multiplier %f out of range (0-1)", o.cacheDefault)
+	}
+	if o.staleAfter < 0 {
+		return nil, fmt.Errorf("staleness threshold %v must not be negative", o.staleAfter)
+	}
+	if o.lookupCacheSize < 0 {
+		return nil, fmt.Errorf("lookup cache size %d must not be negative", o.lookupCacheSize)
+	}
+	if o.negativeCacheSize < 0 {
+		return nil, fmt.Errorf("negative cache size %d must not be negative", o.negativeCacheSize)
+	}
+	if err := validateTaxRate(o.taxRate); err != nil {
+		return nil, err
+	}
+	if o.fallback == nil {
+		return nil, nil
+	}
+	if err := validateModelPricing("fallback", *o.fallback, "WithFallbackPricing"); err != nil {
+		return nil, err
+	}
+	sortTiers(o.fallback.Tiers)
+	return compileRates(*o.fallback, cmp.Or(o.cacheDefault, defaultCacheMultiplier)), nil
+}
+
+// newPricer applies the options to a catalog holding only merged pricing
+// data, then indexes it (see buildPricer) and applies any overlay, markup and
+// tax rate. fallback is the result of validate.
+func (o *pricerOptions) newPricer(c *catalog, fallback *modelRates) (*Pricer, error) {
+	c.rounding = newRounder(o.rounding)
+	c.cacheDefault = o.cacheDefault
+	c.staleAfter = o.staleAfter
+	c.tokenCounter = o.tokenCounter
+	c.stampTime = o.stampTime
+	c.fallback = fallback
+	c.familyFallback = o.familyFallback
+	c.lookups = newLookupCounters(o.lookupStats)
+	c.resolved = newLookupCache(o.lookupCacheSize)
+	c.logger = o.logger
+	c.tracer = o.tracer
+	c.unknown = newNegativeCache(o.negativeCacheSize)
+
+	p := buildPricer(c)
+	if len(o.overlay) > 0 {
+		var err error
+		if c, err = c.withOverlay(o.overlay); err != nil {
+			return nil, err
+		}
+	}
+	if err := c.validateBatchFeatures(); err != nil {
+		return nil, err
+	}
+	markup, err := c.newMarkupTable(o.markup, o.modelMarkups)
+	if err != nil {
+		return nil, err
+	}
+	c.markup = markup
+	if o.taxRate != nil {
+		c.taxRate = *o.taxRate
+	}
+	p.cat.Store(c)
+	return p, nil
+}
+
+// sortTiers sorts tiers by threshold ascending, as required by selectTier.
+func sortTiers(tiers []PricingTier) {
+	sort.Slice(tiers, func(i, j int) bool {
+		return tiers[i].ThresholdTokens < tiers[j].ThresholdTokens
+	})
+}
+
+// buildPricer wraps a catalog of merged lookup maps in a Pricer, building the
+// sorted key indexes used for deterministic prefix matching (longest first)
+// and the precompiled rate table.
+func buildPricer(c *catalog) *Pricer {
+	if c.rounding.scale == 0 {
+		c.rounding = newRounder(DefaultRoundingPolicy)
+	}
+	if c.cacheDefault == 0 {
+		c.cacheDefault = defaultCacheMultiplier
+	}
+	c.modelKeysSorted = sortedKeysByLengthDesc(c.models)
+	c.groundingKeys = sortedKeysByLengthDesc(c.grounding)
+	c.rates = compileRateTable(c.models, c.cacheDefault)
+	if c.familyFallback {
+		c.families = indexFamilies(c.models)
+	}
+	c.resolveSurcharges()
+	c.annotateProviders()
+	c.indexSelfHosted()
+	c.imageModelKeysSorted = imagePrefixKeys(c.imageModels)
+	c.rerankModelKeysSorted = sortedKeysByLengthDesc(c.rerankModels)
+	c.version = catalogVersion(c)
+
+	p := &Pricer{}
+	p.cat.Store(c)
+	return p
+}
+
+// readContext runs read and returns its result, or ctx.Err() as soon as ctx is
+// done. A read still running then is left to finish on its own.
+func readContext[T any](ctx context.Context, read func() (T, error)) (T, error) {
+	if ctx.Done() == nil {
+		return read()
+	}
+	type result struct {
+		v   T
+		err error
+	}
+	done := make(chan result, 1)
+	go func() {
+		v, err := read()
+		done <- result{v, err}
+	}()
+	select {
+	case r := <-done:
+		return r.v, r.err
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	}
+}
+
+// Reload rebuilds the catalog from fsys and swaps it in atomically.
+// Calls already in progress finish against the previous catalog; later
+// calls see the new one. On error the current catalog is kept.
+func (p *Pricer) Reload(fsys fs.FS, dir string, opts ...Option) error {
+	return p.ReloadContext(context.Background(), fsys, dir, opts...)
+}
 
-	return &Pricer{
-		models:               models,
-		modelKeysSorted:      modelKeys,
-		imageModels:          imageModels,
-		imageModelKeysSorted: imageModelKeys,
-		grounding:            grounding,
-		groundingKeys:        groundingKeys,
-		credits:              credits,
-		providers:            providers,
-	}, nil
+// ReloadContext is like Reload but gives up with ctx.Err() once ctx is done,
+// keeping the current catalog.
+func (p *Pricer) ReloadContext(ctx context.Context, fsys fs.FS, dir string, opts ...Option) error {
+	next, err := LoadContext(ctx, fsys, dir, opts...)
+	if err != nil {
+		return err
+	}
+	c := next.cat.Load()
+	if prev := p.load().lookups; prev != nil && c.lookups != nil {
+		c.lookups = prev
+	}
+	p.cat.Store(c)
+	return nil
 }
 
 // Calculate computes the cost for token-based models.
@@ -198,9 +506,23 @@ func NewPricerFromFS(fsys fs.FS, dir string) (*Pricer, error) {
 // versioned model names (e.g., "gpt-4o-2024-08-06" matches "gpt-4o").
 // The longest matching prefix is used for deterministic results.
 func (p *Pricer) Calculate(model string, inputTokens, outputTokens int64) Cost {
-	p.mu.RLock()
-	defer p.mu.RUnlock()
+	c := p.load()
+	return c.calculateTraced(context.Background(), model, inputTokens, outputTokens)
+}
 
+// calculateTraced is calculate inside a "pricing.Calculate" span parented by ctx.
+func (c *catalog) calculateTraced(ctx context.Context, model string, inputTokens, outputTokens int64) Cost {
+	var span Span
+	if c.tracer != nil {
+		span = c.startSpan(ctx, "pricing.Calculate", model, slog.Int64("input_tokens", inputTokens), slog.Int64("output_tokens", outputTokens))
+	}
+	cost := c.calculate(model, inputTokens, outputTokens)
+	endSpan(span, cost.TotalCost, cost.Unknown)
+	return cost
+}
+
+// calculate implements Calculate against c.
+func (c *catalog) calculate(model string, inputTokens, outputTokens int64) Cost {
 	// Early return for empty model string
 	if model == "" {
 		return Cost{Model: model, InputTokens: inputTokens, OutputTokens: outputTokens, Unknown: true}
@@ -214,33 +536,100 @@ func (p *Pricer) Calculate(model string, inputTokens, outputTokens int64) Cost {
 		outputTokens = 0
 	}
 
-	pricing, ok := p.models[model]
+	// Exact match first, then prefix match for versioned models
+	rates, ok := c.lookupRates(model)
+	var fallback Warning
 	if !ok {
-		// Try prefix match for versioned models
-		pricing, ok = p.findPricingByPrefix(model)
-		if !ok {
+		var found bool
+		rates, fallback, found = c.fallbackRates(model)
+		if !found {
 			return Cost{Model: model, InputTokens: inputTokens, OutputTokens: outputTokens, Unknown: true}
 		}
 	}
+	pricing := rates.pricing
 
-	inputCost := float64(inputTokens) * pricing.InputPerMillion / TokensPerMillion
+	used := inputTokens > 0 || outputTokens > 0
+	billedInput, inputRaised := billableInputTokens(pricing, inputTokens, used)
+	inputCost := float64(billedInput) * pricing.InputPerMillion / TokensPerMillion
 	outputCost := float64(outputTokens) * pricing.OutputPerMillion / TokensPerMillion
+	minimumCharge := minimumChargeFor(pricing, inputCost+outputCost, used)
+
+	cost := Cost{
+		Model:         model,
+		InputTokens:   inputTokens,
+		OutputTokens:  outputTokens,
+		InputCost:     inputCost,
+		OutputCost:    outputCost,
+		MinimumCharge: minimumCharge,
+		RawTotal:      inputCost + outputCost + minimumCharge,
+		Unknown:       !ok,
+	}
+	cost.TotalCost = c.rounding.round(cost.RawTotal)
+	if !ok {
+		cost.WarningDetails = append(cost.WarningDetails, fallback)
+	}
+	if w, ok := deprecationWarning(model, pricing); ok {
+		cost.WarningDetails = append(cost.WarningDetails, w)
+	}
+	if w, ok := minimumBilledWarning(pricing, inputRaised, minimumCharge); ok {
+		cost.WarningDetails = append(cost.WarningDetails, w)
+	}
+	return cost
+}
+
+// billableInputTokens raises inputTokens to the model's min_input_tokens for a
+// request that used any tokens, reporting whether the minimum applied.
+func billableInputTokens(pricing ModelPricing, inputTokens int64, used bool) (int64, bool) {
+	if used && inputTokens < pricing.MinInputTokens {
+		return pricing.MinInputTokens, true
+	}
+	return inputTokens, false
+}
+
+// minimumChargeFor returns the top-up needed to bring a request's token cost
+// up to the model's min_billable_usd, or 0 if the minimum does not apply.
+func minimumChargeFor(pricing ModelPricing, tokenCost float64, used bool) float64 {
+	if !used || tokenCost >= pricing.MinBillableUSD {
+		return 0
+	}
+	return pricing.MinBillableUSD - tokenCost
+}
+
+// minimumBilledWarning returns a WarningMinimumBilled warning describing which
+// per-request minimums raised the bill, if any.
+func minimumBilledWarning(pricing ModelPricing, inputRaised bool, minimumCharge float64) (Warning, bool) {
+	var parts []string
+	if inputRaised {
+		parts = append(parts, fmt.Sprintf("input billed at minimum of %d tokens", pricing.MinInputTokens))
+	}
+	if minimumCharge > 0 {
+		parts = append(parts, fmt.Sprintf("charge raised to minimum of $%g", pricing.MinBillableUSD))
+	}
+	if len(parts) == 0 {
+		return Warning{}, false
+	}
+	return Warning{Code: WarningMinimumBilled, Message: strings.Join(parts, "; ")}, true
+}
 
-	return Cost{
-		Model:        model,
-		InputTokens:  inputTokens,
-		OutputTokens: outputTokens,
-		InputCost:    inputCost,
-		OutputCost:   outputCost,
-		TotalCost:    roundToPrecision(inputCost+outputCost, costPrecision),
+// resolveModelKey returns the catalog key a model name resolves to,
+// using an exact match first and then prefix matching.
+func (c *catalog) resolveModelKey(model string) (string, bool) {
+	if _, ok := c.models[model]; ok {
+		return model, true
 	}
+	key, _, ok := c.prefixKey(model)
+	return key, ok
 }
 
 // findPricingByPrefix finds pricing for models with version suffixes.
 // E.g., "gpt-4o-2024-08-06" matches "gpt-4o"
 // Uses sorted keys (longest first) for deterministic matching.
-func (p *Pricer) findPricingByPrefix(model string) (ModelPricing, bool) {
-	return findByPrefix(model, p.modelKeysSorted, p.models)
+func (c *catalog) findPricingByPrefix(model string) (ModelPricing, bool) {
+	key, _, ok := c.prefixKey(model)
+	if !ok {
+		return ModelPricing{}, false
+	}
+	return c.models[key], true
 }
 
 // CalculateGrounding computes the cost for Google grounding/search.
@@ -252,47 +641,71 @@ func (p *Pricer) CalculateGrounding(model string, queryCount int) float64 {
 		return 0
 	}
 
-	p.mu.RLock()
-	defer p.mu.RUnlock()
-
-	// Find matching rate by prefix (longest match first)
-	for _, prefix := range p.groundingKeys {
-		if strings.HasPrefix(model, prefix) && isValidPrefixMatch(model, prefix) {
-			pricing := p.grounding[prefix]
-			return float64(queryCount) * pricing.PerThousandQueries / queriesPerThousand
-		}
+	pricing, ok := p.load().groundingPricing(model)
+	if !ok {
+		return 0 // Unknown model, no grounding cost
 	}
+	return float64(queryCount) * pricing.PerThousandQueries / queriesPerThousand
+}
+
+// GetGroundingPricing returns the grounding (search) pricing that applies to a
+// model, matched by prefix like CalculateGrounding, so dashboards can show it
+// alongside token pricing.
+func (p *Pricer) GetGroundingPricing(model string) (GroundingPricing, bool) {
+	return p.load().groundingPricing(model)
+}
+
+// ListGroundingPrefixes returns the model prefixes with grounding pricing
+// (e.g. "gemini-3"), in alphabetical order.
+func (p *Pricer) ListGroundingPrefixes() []string {
+	c := p.load()
+	prefixes := slices.Clone(c.groundingKeys)
+	sort.Strings(prefixes)
+	return prefixes
+}
 
-	return 0 // Unknown model, no grounding cost
+// groundingPricing finds grounding pricing by prefix (longest match first).
+func (c *catalog) groundingPricing(model string) (GroundingPricing, bool) {
+	return findByPrefix(model, c.groundingKeys, c.grounding)
+}
+
+// CalculateSearch computes the cost of live web search for providers that bill
+// per source consulted (e.g., xAI Live Search), independent of the model used,
+// at the provider's SurchargeSearch surcharge. Returns false if the provider
+// has none. To bill search with a request, use TokenUsage.Surcharges.
+func (p *Pricer) CalculateSearch(provider string, sources int) (float64, bool) {
+	c := p.load()
+
+	s, ok := c.providers[provider].Surcharges[SurchargeSearch]
+	if !ok {
+		return 0, false
+	}
+	if sources <= 0 {
+		return 0, true
+	}
+	return c.rounding.round(float64(sources) * s.PricePerUnit), true
 }
 
 // CalculateCredit computes the credit cost for credit-based providers.
-// Multiplier should be one of: "base", "js_rendering", "premium_proxy", "js_premium"
+// Multiplier is any name configured under the provider's credit_pricing.multipliers
+// (e.g. "js_rendering", "premium_proxy"); "base" or "" selects the base cost.
 // Returns base cost if the multiplier is unknown or zero (unconfigured).
 func (p *Pricer) CalculateCredit(provider, multiplier string) int {
-	p.mu.RLock()
-	defer p.mu.RUnlock()
+	c := p.load()
 
-	credit, ok := p.credits[provider]
+	credit, ok := c.credits[provider]
 	if !ok {
 		return 0
 	}
+	return creditsPerRequest(credit, multiplier)
+}
 
+// creditsPerRequest applies a named multiplier to the base credit cost.
+func creditsPerRequest(credit *CreditPricing, multiplier string) int {
 	base := credit.BaseCostPerRequest
 
-	var mult int
-	switch multiplier {
-	case "js_rendering":
-		mult = credit.Multipliers.JSRendering
-	case "premium_proxy":
-		mult = credit.Multipliers.PremiumProxy
-	case "js_premium":
-		mult = credit.Multipliers.JSPremium
-	default:
-		return base
-	}
-
-	// Return base cost if multiplier is unconfigured (zero)
+	// Return base cost if multiplier is unknown or unconfigured (zero)
+	mult := credit.Multipliers[multiplier]
 	if mult == 0 {
 		return base
 	}
@@ -308,16 +721,17 @@ func (p *Pricer) CalculateCredit(provider, multiplier string) int {
 // CalculateImage computes the cost for image generation models.
 // If an exact model match is not found, prefix matching is used to support
 // versioned model names. The longest matching prefix is used for deterministic results.
+// Per-megapixel models are priced at 1024x1024 and per-step models at their
+// default_steps; use CalculateImageWithOptions for the actual request.
 // Returns the total cost and a boolean indicating if the model was found.
 func (p *Pricer) CalculateImage(model string, imageCount int) (float64, bool) {
-	p.mu.RLock()
-	defer p.mu.RUnlock()
+	c := p.load()
 
 	// First check if model exists
-	pricing, ok := p.imageModels[model]
+	pricing, ok := c.imageModels[model]
 	if !ok {
 		// Try prefix match for versioned models
-		pricing, ok = p.findImagePricingByPrefix(model)
+		pricing, ok = c.findImagePricingByPrefix(model)
 		if !ok {
 			return 0, false
 		}
@@ -328,26 +742,279 @@ func (p *Pricer) CalculateImage(model string, imageCount int) (float64, bool) {
 		return 0, true
 	}
 
-	cost := float64(imageCount) * pricing.PricePerImage
-	return roundToPrecision(cost, costPrecision), true
+	rate := pricing.PricePerImage
+	if r, ok := unitImageRate(pricing, ImageOptions{}); ok {
+		rate = r
+	}
+	cost := float64(imageCount) * rate
+	return c.rounding.round(cost), true
+}
+
+// CalculateRerank computes the cost of rerank requests, billed per search
+// (one query against up to the provider's document limit, e.g. 100 documents for Cohere).
+// Prefix matching applies as for token models.
+// Returns the total cost and a boolean indicating if the model was found.
+func (p *Pricer) CalculateRerank(model string, searches int) (float64, bool) {
+	c := p.load()
+
+	pricing, ok := c.rerankPricing(model)
+	if !ok {
+		return 0, false
+	}
+	if searches <= 0 {
+		return 0, true
+	}
+	cost := float64(searches) * pricing.PerThousandSearches / queriesPerThousand
+	return c.rounding.round(cost), true
+}
+
+// CalculateEndpointHours computes the cost of running a dedicated inference
+// instance (e.g., a Hugging Face Inference Endpoint) for the given hours.
+// Per-second instance types are billed at 3600 times their per-second rate.
+// Instance types match exactly, optionally provider-namespaced
+// ("hf-endpoints/nvidia-a10g-x1"). Hours may be fractional; endpoints bill by
+// the minute while running. Returns false for unknown instance types.
+func (p *Pricer) CalculateEndpointHours(instanceType string, hours float64) (float64, bool) {
+	c := p.load()
+
+	pricing, ok := c.instances[instanceType]
+	if !ok {
+		return 0, false
+	}
+	if hours <= 0 {
+		return 0, true
+	}
+	return c.rounding.round(hours * pricing.hourlyRate()), true
+}
+
+// GetInstancePricing returns the pricing for a dedicated instance type, if known.
+func (p *Pricer) GetInstancePricing(instanceType string) (InstancePricing, bool) {
+	pricing, ok := p.load().instances[instanceType]
+	return pricing, ok
+}
+
+// GetRerankPricing returns the pricing for a rerank model, if known.
+func (p *Pricer) GetRerankPricing(model string) (RerankPricing, bool) {
+	return p.load().rerankPricing(model)
+}
+
+// rerankPricing finds rerank pricing by exact match, then by prefix match.
+func (c *catalog) rerankPricing(model string) (RerankPricing, bool) {
+	if pricing, ok := c.rerankModels[model]; ok {
+		return pricing, true
+	}
+	return findByPrefix(model, c.rerankModelKeysSorted, c.rerankModels)
+}
+
+// CalculateImageWithOptions computes the cost for image generation using the actual
+// request parameters (width, height, quality) instead of synthetic size-specific keys.
+// The resolution tier is chosen by exact size match (either orientation), otherwise the
+// smallest tier covering the requested pixel area, otherwise the largest tier.
+// Falls back to PricePerImage when the model has no tiers for the requested quality.
+// Per-megapixel models are priced by Width x Height (1024x1024 when unset) and
+// per-step models by Steps (the model's default_steps when unset).
+// Returns the total cost and a boolean indicating if the model was found.
+func (p *Pricer) CalculateImageWithOptions(model string, opts ImageOptions) (float64, bool) {
+	c := p.load()
+
+	pricing, ok := c.imageModels[model]
+	if !ok {
+		pricing, ok = c.findImagePricingByPrefix(model)
+		if !ok {
+			return 0, false
+		}
+	}
+
+	if opts.Count <= 0 {
+		return 0, true
+	}
+
+	rate, ok := unitImageRate(pricing, opts)
+	if !ok {
+		rate = selectImageRate(pricing, opts)
+	}
+	cost := float64(opts.Count) * rate
+	return c.rounding.round(cost), true
+}
+
+// defaultImageSide is the width and height assumed for ImagePerMegapixel
+// models when a request does not give its size.
+const defaultImageSide = 1024
+
+// pixelsPerMegapixel is the divisor for per-megapixel image pricing.
+const pixelsPerMegapixel = 1_000_000.0
+
+// unitImageRate returns the per-image price of an ImagePerMegapixel or
+// ImagePerStep model for the request, and false for per-image models.
+func unitImageRate(pricing ImageModelPricing, opts ImageOptions) (float64, bool) {
+	switch pricing.Mode {
+	case ImagePerMegapixel:
+		width, height := opts.Width, opts.Height
+		if width <= 0 || height <= 0 {
+			width, height = defaultImageSide, defaultImageSide
+		}
+		return float64(width) * float64(height) / pixelsPerMegapixel * pricing.PricePerMegapixel, true
+	case ImagePerStep:
+		steps := opts.Steps
+		if steps <= 0 {
+			steps = pricing.DefaultSteps
+		}
+		return float64(steps) * pricing.PricePerStep, true
+	}
+	return 0, false
+}
+
+// selectImageRate returns the per-image price for the requested size and quality.
+func selectImageRate(pricing ImageModelPricing, opts ImageOptions) float64 {
+	quality := opts.Quality
+	if quality == "" {
+		quality = "standard"
+	}
+
+	var candidates []ImageResolutionPricing
+	for _, res := range pricing.Resolutions {
+		if res.Quality == "" || strings.EqualFold(res.Quality, quality) {
+			candidates = append(candidates, res)
+		}
+	}
+	if len(candidates) == 0 {
+		return pricing.PricePerImage
+	}
+
+	// Exact size match, allowing portrait/landscape orientation
+	for _, res := range candidates {
+		if (res.Width == opts.Width && res.Height == opts.Height) ||
+			(res.Width == opts.Height && res.Height == opts.Width) {
+			return res.PricePerImage
+		}
+	}
+
+	// No size requested: use the base price if configured, otherwise the smallest tier
+	requested := int64(opts.Width) * int64(opts.Height)
+	if requested <= 0 && pricing.PricePerImage > 0 {
+		return pricing.PricePerImage
+	}
+
+	// Smallest tier covering the requested area; largest tier if none cover it
+	var best, largest *ImageResolutionPricing
+	for i := range candidates {
+		res := &candidates[i]
+		area := int64(res.Width) * int64(res.Height)
+		if largest == nil || area > int64(largest.Width)*int64(largest.Height) {
+			largest = res
+		}
+		if area >= requested && (best == nil || area < int64(best.Width)*int64(best.Height)) {
+			best = res
+		}
+	}
+	if best != nil {
+		return best.PricePerImage
+	}
+	return largest.PricePerImage
 }
 
 // findImagePricingByPrefix finds pricing for image models with version suffixes.
-// Uses sorted keys (longest first) for deterministic matching.
-func (p *Pricer) findImagePricingByPrefix(model string) (ImageModelPricing, bool) {
-	return findByPrefix(model, p.imageModelKeysSorted, p.imageModels)
+// Uses sorted keys (longest first) for deterministic matching; keys marked
+// exact_match are not candidates.
+func (c *catalog) findImagePricingByPrefix(model string) (ImageModelPricing, bool) {
+	return findByPrefix(model, c.imageModelKeysSorted, c.imageModels)
+}
+
+// imagePrefixKeys returns the image model keys eligible for prefix matching,
+// sorted by length descending.
+func imagePrefixKeys(models map[string]ImageModelPricing) []string {
+	return slices.DeleteFunc(sortedKeysByLengthDesc(models), func(key string) bool {
+		return models[key].ExactMatch
+	})
+}
+
+// resolveImageDefaults replaces each image model that sets default_resolution
+// with the prices of the model it names, so lookups of a bare family name
+// ("nano-banana-pro") resolve to a documented resolution rather than whichever
+// key prefix matching finds.
+func resolveImageDefaults(models map[string]ImageModelPricing, filename string) error {
+	for _, name := range slices.Sorted(maps.Keys(models)) {
+		pricing := models[name]
+		if pricing.DefaultResolution == "" {
+			continue
+		}
+		if pricing.PricePerImage != 0 || len(pricing.Resolutions) > 0 {
+			return fmt.Errorf("%s: image model %q sets default_resolution and its own prices", filename, name)
+		}
+		target, ok := models[pricing.DefaultResolution]
+		if !ok {
+			return fmt.Errorf("%s: image model %q default_resolution %q is not an image model of this provider", filename, name, pricing.DefaultResolution)
+		}
+		if target.DefaultResolution != "" {
+			return fmt.Errorf("%s: image model %q default_resolution %q itself sets default_resolution", filename, name, pricing.DefaultResolution)
+		}
+		target.ExactMatch = pricing.ExactMatch
+		target.DefaultResolution = pricing.DefaultResolution
+		models[name] = target
+	}
+	return nil
 }
 
 // GetImagePricing returns the pricing for an image model, if known.
+// For a model priced at a default resolution, DefaultResolution names it.
+// The returned pricing is a deep copy.
 func (p *Pricer) GetImagePricing(model string) (ImageModelPricing, bool) {
-	p.mu.RLock()
-	defer p.mu.RUnlock()
+	c := p.load()
 
-	pricing, ok := p.imageModels[model]
-	if ok {
-		return pricing, true
+	pricing, ok := c.imageModels[model]
+	if !ok {
+		pricing, ok = c.findImagePricingByPrefix(model)
 	}
-	return p.findImagePricingByPrefix(model)
+	if !ok {
+		return ImageModelPricing{}, false
+	}
+	return copyImageModelPricing(pricing), true
+}
+
+// ListImageModels returns every provider's image models as a price list,
+// sorted by model name and then provider. A model offered by several
+// providers is listed once for each. Pricing is deep-copied.
+func (p *Pricer) ListImageModels() []ImageModelInfo {
+	c := p.load()
+
+	var models []ImageModelInfo
+	for provider, pp := range c.providers {
+		models = appendImageModels(models, provider, pp.ImageModels)
+	}
+	sort.Slice(models, func(i, j int) bool {
+		if models[i].Model != models[j].Model {
+			return models[i].Model < models[j].Model
+		}
+		return models[i].Provider < models[j].Provider
+	})
+	return models
+}
+
+// ListImageModelsByProvider returns the image models of each provider that
+// has any, keyed by provider and sorted by model name. Pricing is deep-copied.
+func (p *Pricer) ListImageModelsByProvider() map[string][]ImageModelInfo {
+	c := p.load()
+
+	byProvider := make(map[string][]ImageModelInfo)
+	for provider, pp := range c.providers {
+		if len(pp.ImageModels) == 0 {
+			continue
+		}
+		models := appendImageModels(nil, provider, pp.ImageModels)
+		sort.Slice(models, func(i, j int) bool {
+			return models[i].Model < models[j].Model
+		})
+		byProvider[provider] = models
+	}
+	return byProvider
+}
+
+// appendImageModels appends a provider's image models to dst, deep-copied.
+func appendImageModels(dst []ImageModelInfo, provider string, models map[string]ImageModelPricing) []ImageModelInfo {
+	for model, pricing := range models {
+		dst = append(dst, ImageModelInfo{Model: model, Provider: provider, ImageModelPricing: copyImageModelPricing(pricing)})
+	}
+	return dst
 }
 
 // CalculateGeminiUsage computes detailed cost for Gemini models using the full usage metadata.
@@ -358,12 +1025,13 @@ func (p *Pricer) GetImagePricing(model string) (ImageModelPricing, bool) {
 //   - Standard Input = Total Input - cachedContentTokenCount
 //   - Cached Input = cachedContentTokenCount (charged at cache_read_multiplier rate)
 //   - Output = candidatesTokenCount
-//   - Thinking = thoughtsTokenCount (charged at OUTPUT rate)
+//   - Thinking = thoughtsTokenCount (charged at thinking_per_million, else OUTPUT rate)
 //
 // Batch mode behavior:
 //   - For "stack" rule: cache and batch discounts multiply (Anthropic/OpenAI)
 //   - For "cache_precedence" rule: cached tokens use cache rate only, batch applies to non-cached (Gemini)
-//   - Grounding is excluded in batch mode if batch_grounding_ok is false
+//   - Grounding is excluded in batch mode if batch_grounding_ok (or
+//     batch_features["grounding"]) is false
 //
 // IMPORTANT: Grounding in batch mode
 //
@@ -379,184 +1047,420 @@ func (p *Pricer) CalculateGeminiUsage(
 	groundingQueries int,
 	opts *CalculateOptions,
 ) CostDetails {
-	p.mu.RLock()
-	defer p.mu.RUnlock()
-
-	pricing, ok := p.models[model]
-	if !ok {
-		pricing, ok = p.findPricingByPrefix(model)
-		if !ok {
-			return CostDetails{Unknown: true}
-		}
+	details := p.CalculateUsage(model, geminiTokenUsage(metadata, groundingQueries), opts)
+	if w, ok := metadata.totalMismatchWarning(); ok {
+		addWarning(&details, w.Code, w.Message)
 	}
+	return details
+}
 
-	batchMode := opts != nil && opts.BatchMode
-	var warnings []string
+// usageMismatchTolerance is the relative difference between a reported total and
+// the sum of its components above which a WarningUsageMismatch is raised.
+const usageMismatchTolerance = 0.01
+
+// totalMismatchWarning flags a totalTokenCount that differs materially from
+// prompt + candidates + tool use + thoughts, which points at a metering bug.
+// Cached tokens are part of the prompt count and are not added again.
+func (m GeminiUsageMetadata) totalMismatchWarning() (Warning, bool) {
+	if m.TotalTokenCount <= 0 {
+		return Warning{}, false
+	}
+	sum := m.PromptTokenCount + m.CandidatesTokenCount + m.ToolUsePromptTokenCount + m.ThoughtsTokenCount
+	diff := m.TotalTokenCount - sum
+	if float64(max(diff, -diff)) <= usageMismatchTolerance*float64(m.TotalTokenCount) {
+		return Warning{}, false
+	}
+	return Warning{
+		Code:    WarningUsageMismatch,
+		Message: fmt.Sprintf("totalTokenCount (%d) differs from sum of component counts (%d)", m.TotalTokenCount, sum),
+	}, true
+}
 
-	// Calculate total input tokens with overflow protection
-	totalInputTokens, overflowed := addInt64Safe(metadata.PromptTokenCount, metadata.ToolUsePromptTokenCount)
-	if overflowed {
-		warnings = append(warnings, "token count overflow detected - using clamped value")
+// geminiTokenUsage maps Gemini usage metadata onto the provider-neutral TokenUsage.
+// Per-modality details route image and audio tokens to their own rates.
+func geminiTokenUsage(metadata GeminiUsageMetadata, groundingQueries int) TokenUsage {
+	prompt := geminiModalityTokens(metadata.PromptTokensDetails)
+	output := geminiModalityTokens(metadata.CandidatesTokensDetails)
+	return TokenUsage{
+		PromptTokens:      metadata.PromptTokenCount,
+		CompletionTokens:  metadata.CandidatesTokenCount,
+		CachedTokens:      metadata.CachedContentTokenCount,
+		ThinkingTokens:    metadata.ThoughtsTokenCount,
+		ToolUseTokens:     metadata.ToolUsePromptTokenCount,
+		GroundingQueries:  groundingQueries,
+		ImageInputTokens:  prompt.Image,
+		AudioInputTokens:  prompt.Audio,
+		AudioOutputTokens: output.Audio,
+		PromptModalities:  prompt,
+		OutputModalities:  output,
 	}
+}
 
-	// Clamp cached tokens to not exceed total input (invalid input, but handle gracefully)
-	cachedContentTokens := metadata.CachedContentTokenCount
-	if cachedContentTokens > totalInputTokens {
-		cachedContentTokens = totalInputTokens
+// geminiModalityTokens sums a Gemini per-modality breakdown, counting document
+// tokens as text. Video tokens are billed at the input rate.
+func geminiModalityTokens(details []GeminiModalityTokenCount) ModalityTokens {
+	var m ModalityTokens
+	for _, d := range details {
+		switch strings.ToUpper(d.Modality) {
+		case "TEXT", "DOCUMENT":
+			m.Text += d.TokenCount
+		case "IMAGE":
+			m.Image += d.TokenCount
+		case "AUDIO":
+			m.Audio += d.TokenCount
+		case "VIDEO":
+			m.Video += d.TokenCount
+		}
 	}
+	return m
+}
 
-	// Select appropriate tier based on total input
-	inputRate, outputRate := p.selectTierLocked(pricing, totalInputTokens)
-
-	// Calculate batch/cache costs using shared helper
-	costs := calculateBatchCacheCosts(pricing, totalInputTokens, cachedContentTokens, inputRate, batchMode)
-	standardInputCost := costs.standardInputCost
-	cachedInputCost := costs.cachedInputCost
-	batchMultiplier := costs.batchMultiplier
-
-	// Calculate output cost
-	outputCost := float64(metadata.CandidatesTokenCount) * outputRate / TokensPerMillion * batchMultiplier
-
-	// Calculate thinking cost (charged at OUTPUT rate)
-	thinkingCost := float64(metadata.ThoughtsTokenCount) * outputRate / TokensPerMillion * batchMultiplier
-
-	// Calculate grounding cost
-	// In batch mode, check if grounding is supported
-	var groundingCost float64
-	if groundingQueries > 0 {
-		if batchMode && !pricing.BatchGroundingOK {
-			// Grounding not supported in batch mode - exclude cost and warn
-			warnings = append(warnings, "grounding/search not supported in batch mode - cost excluded")
-		} else {
-			groundingCost = p.calculateGroundingLocked(model, groundingQueries)
-		}
+// CalculateGeminiResponse calculates cost from a parsed GeminiResponse.
+// It counts non-empty webSearchQueries across all candidates for grounding billing.
+// If modelOverride is non-empty, it is used instead of resp.ModelVersion.
+func (p *Pricer) CalculateGeminiResponse(resp GeminiResponse, modelOverride string, opts *CalculateOptions) CostDetails {
+	// Count non-empty web search queries across all candidates
+	groundingQueries := 0
+	for _, candidate := range resp.Candidates {
+		groundingQueries += candidate.webSearchQueryCount()
 	}
 
-	// Determine tier name
-	tierApplied := determineTierName(pricing, totalInputTokens)
+	return p.CalculateGeminiUsage(resp.model(modelOverride), resp.UsageMetadata, groundingQueries, opts)
+}
 
-	// Calculate batch discount amount (for reporting)
-	// Note: for cache_precedence, the discount only applies to non-cached tokens
-	var batchDiscount float64
-	if batchMultiplier < 1.0 {
-		if pricing.BatchCacheRule == BatchCachePrecedence {
-			// Only standard input, output, and thinking got batch discount
-			fullCost := (standardInputCost + outputCost + thinkingCost) / batchMultiplier
-			batchDiscount = fullCost - (standardInputCost + outputCost + thinkingCost)
-		} else {
-			// All token costs got batch discount
-			fullCost := (standardInputCost + cachedInputCost + outputCost + thinkingCost) / batchMultiplier
-			batchDiscount = fullCost - (standardInputCost + cachedInputCost + outputCost + thinkingCost)
-		}
+// model returns modelOverride if provided, otherwise the response's modelVersion.
+func (r GeminiResponse) model(modelOverride string) string {
+	if modelOverride != "" {
+		return modelOverride
 	}
+	return r.ModelVersion
+}
 
-	totalCost := roundToPrecision(standardInputCost+cachedInputCost+outputCost+thinkingCost+groundingCost, costPrecision)
-
-	return CostDetails{
-		StandardInputCost: standardInputCost,
-		CachedInputCost:   cachedInputCost,
-		OutputCost:        outputCost,
-		ThinkingCost:      thinkingCost,
-		GroundingCost:     groundingCost,
-		TierApplied:       tierApplied,
-		BatchDiscount:     batchDiscount,
-		TotalCost:         totalCost,
-		BatchMode:         batchMode,
-		Warnings:          warnings,
+// webSearchQueryCount returns the number of non-empty grounding search queries.
+func (c GeminiCandidate) webSearchQueryCount() int {
+	if c.GroundingMetadata == nil {
+		return 0
+	}
+	n := 0
+	for _, query := range c.GroundingMetadata.WebSearchQueries {
+		if query != "" {
+			n++
+		}
 	}
+	return n
 }
 
 // CalculateWithOptions computes cost for any model with options like batch mode.
 // This is a generic version that handles cached tokens for any provider.
 func (p *Pricer) CalculateWithOptions(model string, inputTokens, outputTokens, cachedTokens int64, opts *CalculateOptions) CostDetails {
-	p.mu.RLock()
-	defer p.mu.RUnlock()
+	return p.CalculateUsage(model, TokenUsage{
+		PromptTokens:     inputTokens,
+		CompletionTokens: outputTokens,
+		CachedTokens:     cachedTokens,
+	}, opts)
+}
 
-	// Clamp negative tokens to 0
-	if inputTokens < 0 {
-		inputTokens = 0
-	}
-	if outputTokens < 0 {
-		outputTokens = 0
-	}
-	if cachedTokens < 0 {
-		cachedTokens = 0
+// CalculateUsage computes detailed cost from a provider-neutral TokenUsage.
+// It is the shared implementation behind CalculateGeminiUsage and CalculateWithOptions;
+// see CalculateGeminiUsage for the token math and batch mode behavior.
+//
+// Multimodal prompts:
+//   - ImageInputTokens are a subset of PromptTokens. When the model configures
+//     image_input_per_million they are billed at that rate, otherwise at the input rate.
+//   - ImageCount is charged per_input_image per image when configured.
+//   - Both are reported in ImageInputCost and are subject to the batch discount.
+func (p *Pricer) CalculateUsage(model string, usage TokenUsage, opts *CalculateOptions) CostDetails {
+	var details CostDetails
+	p.CalculateUsageInto(&details, model, usage, opts)
+	return details
+}
+
+// CalculateUsageInto is CalculateUsage writing into dst, for high-QPS callers
+// that want to avoid per-call allocations. dst is overwritten, but the backing
+// arrays of dst.Warnings and dst.WarningDetails are reused, so a dst kept across
+// calls prices known models without allocating; check len(dst.Warnings) rather
+// than dst.Warnings == nil. (Warnings whose message embeds values, such as
+// deprecation or clamped-token warnings, still format a string.)
+func (p *Pricer) CalculateUsageInto(dst *CostDetails, model string, usage TokenUsage, opts *CalculateOptions) {
+	c := p.load()
+	c.calculateUsageTraced(context.Background(), dst, model, usage, opts)
+}
+
+// calculateUsageTraced resolves model and prices usage into dst inside a
+// "pricing.CalculateUsage" span parented by ctx.
+func (c *catalog) calculateUsageTraced(ctx context.Context, dst *CostDetails, model string, usage TokenUsage, opts *CalculateOptions) {
+	var span Span
+	if c.tracer != nil {
+		span = c.startSpan(ctx, "pricing.CalculateUsage", model,
+			slog.Int64("input_tokens", usage.PromptTokens),
+			slog.Int64("output_tokens", usage.CompletionTokens),
+			slog.Int64("cached_tokens", usage.CachedTokens),
+			slog.Int64("thinking_tokens", usage.ThinkingTokens),
+			slog.Bool("batch_mode", opts != nil && opts.BatchMode),
+		)
+	}
+	rates, _ := c.lookupRates(model)
+	c.calculateUsageInto(dst, rates, model, usage, opts)
+	endSpan(span, dst.TotalCost, dst.Unknown)
+}
+
+// calculateUsageInto implements CalculateUsageInto for already-resolved rates
+// (nil for an unknown model), so batch callers can resolve each model once.
+func (c *catalog) calculateUsageInto(dst *CostDetails, rates *modelRates, model string, usage TokenUsage, opts *CalculateOptions) {
+	*dst = CostDetails{
+		Warnings:       dst.Warnings[:0],
+		WarningDetails: dst.WarningDetails[:0],
 	}
+	c.stamp(dst)
 
-	pricing, ok := p.models[model]
-	if !ok {
-		pricing, ok = p.findPricingByPrefix(model)
+	unknown := rates == nil
+	if unknown {
+		fallback, w, ok := c.fallbackRates(model)
 		if !ok {
-			return CostDetails{Unknown: true}
+			dst.Unknown = true
+			return
 		}
+		rates = fallback
+		addWarning(dst, w.Code, w.Message)
 	}
+	pricing := rates.pricing
 
+	usage = clampUsage(usage)
 	batchMode := opts != nil && opts.BatchMode
-	var warnings []string
+	if w, ok := deprecationWarning(model, pricing); ok {
+		addWarning(dst, w.Code, w.Message)
+	}
+	if c.staleAfter > 0 {
+		if w, ok := rates.stalenessWarning(c.staleAfter, timeNow()); ok {
+			addWarning(dst, w.Code, w.Message)
+		}
+	}
+
+	// Calculate total input tokens with overflow protection
+	totalInputTokens, overflowed := addInt64Safe(usage.PromptTokens, usage.ToolUseTokens)
+	if overflowed {
+		addWarning(dst, WarningTokenOverflow, "token count overflow detected - using clamped value")
+	}
 
 	// Clamp cached tokens to not exceed total input (invalid input, but handle gracefully)
-	clampedCachedTokens := cachedTokens
-	if clampedCachedTokens > inputTokens {
-		clampedCachedTokens = inputTokens
-		warnings = append(warnings, fmt.Sprintf("cached tokens (%d) exceed input tokens (%d) - clamped", cachedTokens, inputTokens))
+	cachedTokens := usage.CachedTokens
+	if cachedTokens > totalInputTokens {
+		cachedTokens = totalInputTokens
+		addWarning(dst, WarningCachedTokensClamped, fmt.Sprintf("cached tokens (%d) exceed input tokens (%d) - clamped", usage.CachedTokens, totalInputTokens))
+	}
+
+	// Image tokens are only split out when the model has a distinct image rate.
+	// They can't exceed the non-cached portion of the input.
+	var imageTokens int64
+	if pricing.ImageInputPerMillion > 0 {
+		imageTokens = min(usage.ImageInputTokens, totalInputTokens-cachedTokens)
+	}
+	// Audio tokens likewise, from what remains after cached and image tokens
+	var audioInputTokens int64
+	if pricing.AudioInputPerMillion > 0 {
+		audioInputTokens = min(usage.AudioInputTokens, totalInputTokens-cachedTokens-imageTokens)
+	}
+
+	// Resolve the cache profile (named read/write multipliers, e.g. per cache TTL)
+	cacheMultiplier, writeMultiplier := rates.cacheMultiplier, 0.0
+	profileName := pricing.DefaultCacheProfile
+	if opts != nil && opts.CacheProfile != "" {
+		profileName = opts.CacheProfile
+	}
+	if profileName != "" {
+		if profile, ok := pricing.CacheProfiles[profileName]; ok {
+			if profile.ReadMultiplier > 0 {
+				cacheMultiplier = profile.ReadMultiplier
+			}
+			writeMultiplier = profile.WriteMultiplier
+		} else {
+			addWarning(dst, WarningCacheProfileMissing, fmt.Sprintf("model %q has no cache profile %q - cache writes billed at input rate", model, profileName))
+		}
+	}
+	// Cache writes are only split out when the profile prices them
+	var cacheWriteTokens int64
+	if writeMultiplier > 0 {
+		cacheWriteTokens = min(usage.CacheWriteTokens, totalInputTokens-cachedTokens-imageTokens-audioInputTokens)
 	}
 
-	// Select appropriate tier based on total input
-	inputRate, outputRate := p.selectTierLocked(pricing, inputTokens)
+	// Short prompts are billed up to min_input_tokens; the padding is standard input
+	used := totalInputTokens > 0 || usage.CompletionTokens > 0 || usage.ThinkingTokens > 0 || usage.ImageCount > 0
+	billedInputTokens, inputRaised := billableInputTokens(pricing, totalInputTokens, used)
 
-	// Calculate batch/cache costs using shared helper
-	costs := calculateBatchCacheCosts(pricing, inputTokens, clampedCachedTokens, inputRate, batchMode)
+	// Select appropriate tier based on total input (or output, per tier_basis).
+	// Under tier_mode marginal the cached prompt prefix fills the lowest bands,
+	// followed by the rest of the input and any min_input_tokens padding.
+	tier := rates.tier(rates.tierTokens(totalInputTokens, usage.CompletionTokens, usage.ThinkingTokens))
+	cachedRate := rates.inputRateBetween(tier, 0, cachedTokens)
+	inputRate := rates.inputRateBetween(tier, cachedTokens, billedInputTokens)
+	outputRate := tier.outputPerMillion
+
+	// Calculate batch/cache costs using shared helper (image, audio and cache-write tokens are billed separately)
+	costs := calculateBatchCacheCosts(rates, billedInputTokens-imageTokens-audioInputTokens-cacheWriteTokens, cachedTokens, inputRate, cachedRate, cacheMultiplier, batchMode)
 	standardInputCost := costs.standardInputCost
 	cachedInputCost := costs.cachedInputCost
 	batchMultiplier := costs.batchMultiplier
 
-	// Calculate output cost
-	outputCost := float64(outputTokens) * outputRate / TokensPerMillion * batchMultiplier
+	// Calculate cache write cost; like cache reads, batch does not apply under cache_precedence
+	cacheWriteCost := float64(cacheWriteTokens) * inputRate * writeMultiplier / TokensPerMillion
+	if !rates.cachePrecedence {
+		cacheWriteCost *= batchMultiplier
+	}
+
+	// Calculate image input cost (image tokens plus per-image fees)
+	imageInputCost := (float64(imageTokens)*pricing.ImageInputPerMillion/TokensPerMillion +
+		float64(usage.ImageCount)*pricing.PerInputImage) * batchMultiplier
+
+	// Calculate audio input cost
+	audioInputCost := float64(audioInputTokens) * pricing.AudioInputPerMillion / TokensPerMillion * batchMultiplier
+
+	// Calculate output cost, splitting out audio output when the model has a distinct rate
+	var audioOutputTokens int64
+	if pricing.AudioOutputPerMillion > 0 {
+		audioOutputTokens = min(usage.AudioOutputTokens, usage.CompletionTokens)
+	}
+	outputCost := float64(usage.CompletionTokens-audioOutputTokens) * outputRate / TokensPerMillion * batchMultiplier
+	audioOutputCost := float64(audioOutputTokens) * pricing.AudioOutputPerMillion / TokensPerMillion * batchMultiplier
+
+	// Calculate thinking cost (charged at OUTPUT rate unless thinking_per_million is set)
+	thinkingRate := outputRate
+	if pricing.ThinkingPerMillion > 0 {
+		thinkingRate = pricing.ThinkingPerMillion
+	}
+	thinkingCost := float64(usage.ThinkingTokens) * thinkingRate / TokensPerMillion * batchMultiplier
+
+	// Calculate grounding cost (the "grounding" surcharge)
+	// In batch mode, check if grounding is supported
+	var groundingCost float64
+	if usage.GroundingQueries > 0 {
+		if batchMode && !groundingBatchOK(pricing) {
+			// Grounding not supported in batch mode - exclude cost and warn
+			addWarning(dst, WarningBatchGroundingExcluded, "grounding/search not supported in batch mode - cost excluded")
+		} else if s, ok := rates.surcharges[SurchargeGrounding]; ok {
+			groundingCost = float64(usage.GroundingQueries) * s.PricePerUnit
+		}
+	}
+
+	// Calculate other per-unit surcharges (search, citations, ...)
+	// GroundingQueries is shorthand for Surcharges["grounding"]; when both are
+	// set only GroundingQueries is billed so the queries are not charged twice.
+	surcharges := usage.Surcharges
+	if usage.GroundingQueries > 0 && surcharges[SurchargeGrounding] > 0 {
+		addWarning(dst, WarningUsageMismatch, fmt.Sprintf("grounding queries set in both GroundingQueries (%d) and Surcharges[%q] (%d) - billing GroundingQueries only",
+			usage.GroundingQueries, SurchargeGrounding, surcharges[SurchargeGrounding]))
+		surcharges = maps.Clone(surcharges)
+		delete(surcharges, SurchargeGrounding)
+	}
+	surchargeCost := rates.surchargeCost(dst, model, surcharges, batchMode)
 
-	// Determine tier name
-	tierApplied := determineTierName(pricing, inputTokens)
+	// Tier name is preformatted at load time
+	tierApplied := tier.name
 
-	// Calculate batch discount
+	// Calculate batch discount amount (for reporting)
+	// Note: for cache_precedence, the discount only applies to non-cached tokens
 	var batchDiscount float64
 	if batchMultiplier < 1.0 {
-		if pricing.BatchCacheRule == BatchCachePrecedence {
-			fullCost := (standardInputCost + outputCost) / batchMultiplier
-			batchDiscount = fullCost - (standardInputCost + outputCost)
+		if rates.cachePrecedence {
+			// Only standard input, output, thinking, image and audio got batch discount
+			discounted := standardInputCost + outputCost + thinkingCost + imageInputCost + audioInputCost + audioOutputCost
+			batchDiscount = discounted/batchMultiplier - discounted
 		} else {
-			fullCost := (standardInputCost + cachedInputCost + outputCost) / batchMultiplier
-			batchDiscount = fullCost - (standardInputCost + cachedInputCost + outputCost)
+			// All token costs got batch discount
+			discounted := standardInputCost + cachedInputCost + cacheWriteCost + outputCost + thinkingCost + imageInputCost + audioInputCost + audioOutputCost
+			batchDiscount = discounted/batchMultiplier - discounted
 		}
 	}
 
-	totalCost := roundToPrecision(standardInputCost+cachedInputCost+outputCost, costPrecision)
+	// Per-request minimum applies to token charges; grounding and surcharges are billed separately
+	tokenCost := standardInputCost + cachedInputCost + cacheWriteCost + outputCost + thinkingCost + imageInputCost + audioInputCost + audioOutputCost
+	minimumCharge := minimumChargeFor(pricing, tokenCost, used)
+	if w, ok := minimumBilledWarning(pricing, inputRaised, minimumCharge); ok {
+		addWarning(dst, w.Code, w.Message)
+	}
+
+	rawTotal := tokenCost + groundingCost + surchargeCost + minimumCharge
+
+	dst.StandardInputCost = standardInputCost
+	dst.CachedInputCost = cachedInputCost
+	dst.CacheWriteCost = cacheWriteCost
+	dst.ImageInputCost = imageInputCost
+	dst.AudioInputCost = audioInputCost
+	dst.OutputCost = outputCost
+	dst.AudioOutputCost = audioOutputCost
+	dst.ThinkingCost = thinkingCost
+	dst.GroundingCost = groundingCost
+	dst.SurchargeCost = surchargeCost
+	dst.TierApplied = tierApplied
+	dst.BatchDiscount = batchDiscount
+	dst.MinimumCharge = minimumCharge
+	dst.TotalCost = c.rounding.round(rawTotal)
+	dst.RawTotal = rawTotal
+	outputTokens, _ := addInt64Safe(usage.CompletionTokens, usage.ThinkingTokens)
+	c.applyMarkup(dst, model, totalInputTokens, outputTokens)
+	c.logWarnings(model, dst.WarningDetails)
+	dst.BatchMode = batchMode
+	dst.Unknown = unknown
+	dst.PromptModalities = usage.PromptModalities
+	dst.OutputModalities = usage.OutputModalities
+}
 
-	return CostDetails{
-		StandardInputCost: standardInputCost,
-		CachedInputCost:   cachedInputCost,
-		OutputCost:        outputCost,
-		TierApplied:       tierApplied,
-		BatchDiscount:     batchDiscount,
-		TotalCost:         totalCost,
-		BatchMode:         batchMode,
-		Warnings:          warnings,
+// deprecationWarning returns a WarningDeprecatedModel warning if the model is
+// deprecated or has a sunset date, mentioning the replacement when configured.
+func deprecationWarning(model string, pricing ModelPricing) (Warning, bool) {
+	if !pricing.Deprecated && pricing.SunsetDate == "" {
+		return Warning{}, false
+	}
+	msg := fmt.Sprintf("model %q is deprecated", model)
+	if pricing.SunsetDate != "" {
+		msg += fmt.Sprintf(" (sunset %s)", pricing.SunsetDate)
+	}
+	if pricing.Replacement != "" {
+		msg += fmt.Sprintf(" - migrate to %q", pricing.Replacement)
 	}
+	return Warning{Code: WarningDeprecatedModel, Message: msg}, true
 }
 
-// selectTierLocked returns the appropriate input/output rates based on token count.
-// Must be called with p.mu held (read or write).
-func (p *Pricer) selectTierLocked(pricing ModelPricing, totalInputTokens int64) (inputRate, outputRate float64) {
-	inputRate = pricing.InputPerMillion
-	outputRate = pricing.OutputPerMillion
+// warningMessages returns the human-readable messages of structured warnings.
+// Returns nil for no warnings so CostDetails.Warnings stays nil as before.
+func warningMessages(warnings []Warning) []string {
+	if len(warnings) == 0 {
+		return nil
+	}
+	msgs := make([]string, len(warnings))
+	for i, w := range warnings {
+		msgs[i] = w.Message
+	}
+	return msgs
+}
 
-	// Check tiers (assumes tiers are sorted by threshold ascending)
-	for _, tier := range pricing.Tiers {
-		if totalInputTokens >= tier.ThresholdTokens {
-			inputRate = tier.InputPerMillion
-			outputRate = tier.OutputPerMillion
-		}
+// stamp records the catalog version, and the time if enabled, on dst.
+func (c *catalog) stamp(dst *CostDetails) {
+	dst.PricingVersion = c.version
+	if c.stampTime {
+		dst.CalculatedAt = timeNow().UTC()
 	}
+}
+
+// addWarning appends a warning to dst in both structured and string form.
+func addWarning(dst *CostDetails, code WarningCode, message string) {
+	dst.Warnings = append(dst.Warnings, message)
+	dst.WarningDetails = append(dst.WarningDetails, Warning{Code: code, Message: message})
+}
 
-	return inputRate, outputRate
+// clampUsage clamps negative token and count fields to 0.
+func clampUsage(usage TokenUsage) TokenUsage {
+	usage.PromptTokens = max(usage.PromptTokens, 0)
+	usage.CompletionTokens = max(usage.CompletionTokens, 0)
+	usage.CachedTokens = max(usage.CachedTokens, 0)
+	usage.ThinkingTokens = max(usage.ThinkingTokens, 0)
+	usage.ToolUseTokens = max(usage.ToolUseTokens, 0)
+	usage.GroundingQueries = max(usage.GroundingQueries, 0)
+	usage.ImageInputTokens = max(usage.ImageInputTokens, 0)
+	usage.ImageCount = max(usage.ImageCount, 0)
+	usage.AudioInputTokens = max(usage.AudioInputTokens, 0)
+	usage.AudioOutputTokens = max(usage.AudioOutputTokens, 0)
+	usage.CacheWriteTokens = max(usage.CacheWriteTokens, 0)
+	return usage
 }
 
 // batchCacheCosts holds the results of batch/cache cost calculations.
@@ -573,36 +1477,30 @@ type batchCacheCosts struct {
 //   - "stack": cache_mult * batch_mult (e.g., Anthropic: 10% * 50% = 5%)
 //   - "cache_precedence": cache_mult only, batch doesn't apply (e.g., Gemini: always 10%)
 func calculateBatchCacheCosts(
-	pricing ModelPricing,
+	rates *modelRates,
 	totalInputTokens, cachedTokens int64,
-	inputRate float64,
+	inputRate, cachedRate, cacheMultiplier float64,
 	batchMode bool,
 ) batchCacheCosts {
-	// Determine batch multiplier
+	// Batch multiplier is resolved at load time; cacheMultiplier may come from a cache profile
 	batchMultiplier := 1.0
-	if batchMode && pricing.BatchMultiplier > 0 {
-		batchMultiplier = pricing.BatchMultiplier
+	if batchMode {
+		batchMultiplier = rates.batchMultiplier
 	}
 
 	// Calculate standard input cost (non-cached tokens)
 	standardInputTokens := totalInputTokens - cachedTokens
 	standardInputCost := float64(standardInputTokens) * inputRate / TokensPerMillion * batchMultiplier
 
-	// Determine cache multiplier
-	cacheMultiplier := pricing.CacheReadMultiplier
-	if cacheMultiplier == 0 && cachedTokens > 0 {
-		cacheMultiplier = defaultCacheMultiplier
-	}
-
 	// Calculate cached input cost based on batch_cache_rule
 	var cachedInputCost float64
 	if cachedTokens > 0 {
-		if pricing.BatchCacheRule == BatchCachePrecedence {
+		if rates.cachePrecedence {
 			// Cache takes precedence: cached tokens always get cache rate, no batch discount
-			cachedInputCost = float64(cachedTokens) * inputRate * cacheMultiplier / TokensPerMillion
+			cachedInputCost = float64(cachedTokens) * cachedRate * cacheMultiplier / TokensPerMillion
 		} else {
 			// Stack (default): cache and batch discounts multiply
-			cachedInputCost = float64(cachedTokens) * inputRate * cacheMultiplier / TokensPerMillion * batchMultiplier
+			cachedInputCost = float64(cachedTokens) * cachedRate * cacheMultiplier / TokensPerMillion * batchMultiplier
 		}
 	}
 
@@ -636,37 +1534,44 @@ func determineTierName(pricing ModelPricing, totalTokens int64) string {
 	return tierName
 }
 
-// calculateGroundingLocked calculates grounding cost. Must be called with p.mu held.
-func (p *Pricer) calculateGroundingLocked(model string, queryCount int) float64 {
-	if queryCount <= 0 {
-		return 0
-	}
+// GetPricing returns the pricing for a model, if known. The returned pricing
+// is a deep copy: the catalog is shared by concurrent calculations and must
+// not change under them.
+func (p *Pricer) GetPricing(model string) (ModelPricing, bool) {
+	c := p.load()
 
-	if pricing, found := findByPrefix(model, p.groundingKeys, p.grounding); found {
-		return float64(queryCount) * pricing.PerThousandQueries / queriesPerThousand
+	pricing, ok := c.models[model]
+	if !ok {
+		pricing, ok = c.findPricingByPrefix(model)
 	}
-
-	return 0
+	if !ok {
+		return ModelPricing{}, false
+	}
+	return copyModelPricing(pricing), true
 }
 
-// GetPricing returns the pricing for a model, if known.
-func (p *Pricer) GetPricing(model string) (ModelPricing, bool) {
-	p.mu.RLock()
-	defer p.mu.RUnlock()
+// GetModelInfo returns pricing and descriptive metadata (context window, max output,
+// knowledge cutoff, modalities) for a model, using the same exact/prefix resolution
+// as Calculate. The returned ModelInfo is a deep copy.
+func (p *Pricer) GetModelInfo(model string) (ModelInfo, bool) {
+	c := p.load()
 
-	pricing, ok := p.models[model]
-	if ok {
-		return pricing, true
+	key, ok := c.resolveModelKey(model)
+	if !ok {
+		return ModelInfo{}, false
 	}
-	return p.findPricingByPrefix(model)
+	return ModelInfo{
+		Model:        key,
+		Provider:     c.modelProviders[key],
+		ModelPricing: copyModelPricing(c.models[key]),
+	}, true
 }
 
 // GetProviderMetadata returns metadata for a provider.
 // Returns a deep copy to prevent mutation of internal state.
 func (p *Pricer) GetProviderMetadata(provider string) (ProviderPricing, bool) {
-	p.mu.RLock()
-	defer p.mu.RUnlock()
-	pp, ok := p.providers[provider]
+	c := p.load()
+	pp, ok := c.providers[provider]
 	if !ok {
 		return ProviderPricing{}, false
 	}
@@ -675,10 +1580,9 @@ func (p *Pricer) GetProviderMetadata(provider string) (ProviderPricing, bool) {
 
 // ListProviders returns all loaded provider names in alphabetical order.
 func (p *Pricer) ListProviders() []string {
-	p.mu.RLock()
-	defer p.mu.RUnlock()
-	names := make([]string, 0, len(p.providers))
-	for name := range p.providers {
+	c := p.load()
+	names := make([]string, 0, len(c.providers))
+	for name := range c.providers {
 		names = append(names, name)
 	}
 	sort.Strings(names)
@@ -687,16 +1591,14 @@ func (p *Pricer) ListProviders() []string {
 
 // ModelCount returns the total number of models loaded.
 func (p *Pricer) ModelCount() int {
-	p.mu.RLock()
-	defer p.mu.RUnlock()
-	return len(p.models)
+	c := p.load()
+	return len(c.models)
 }
 
 // ProviderCount returns the number of providers loaded.
 func (p *Pricer) ProviderCount() int {
-	p.mu.RLock()
-	defer p.mu.RUnlock()
-	return len(p.providers)
+	c := p.load()
+	return len(c.providers)
 }
 
 // isValidPrefixMatch ensures prefix match ends at a valid boundary.
@@ -771,6 +1673,33 @@ func validateModelPricing(model string, pricing ModelPricing, filename string) e
 	if err := validateMaxReasonable(pricing.OutputPerMillion, "output price", maxReasonablePrice, context, filename); err != nil {
 		return err
 	}
+	if err := validateNonNegative(pricing.ImageInputPerMillion, "image input price", context, filename); err != nil {
+		return err
+	}
+	if err := validateMaxReasonable(pricing.ImageInputPerMillion, "image input price", maxReasonablePrice, context, filename); err != nil {
+		return err
+	}
+	if err := validateNonNegative(pricing.PerInputImage, "per-image input price", context, filename); err != nil {
+		return err
+	}
+	if err := validateNonNegative(pricing.AudioInputPerMillion, "audio input price", context, filename); err != nil {
+		return err
+	}
+	if err := validateMaxReasonable(pricing.AudioInputPerMillion, "audio input price", maxReasonablePrice, context, filename); err != nil {
+		return err
+	}
+	if err := validateNonNegative(pricing.AudioOutputPerMillion, "audio output price", context, filename); err != nil {
+		return err
+	}
+	if err := validateMaxReasonable(pricing.AudioOutputPerMillion, "audio output price", maxReasonablePrice, context, filename); err != nil {
+		return err
+	}
+	if err := validateNonNegative(pricing.ThinkingPerMillion, "thinking price", context, filename); err != nil {
+		return err
+	}
+	if err := validateMaxReasonable(pricing.ThinkingPerMillion, "thinking price", maxReasonablePrice, context, filename); err != nil {
+		return err
+	}
 	if err := validateNonNegative(pricing.BatchMultiplier, "batch multiplier", context, filename); err != nil {
 		return err
 	}
@@ -791,6 +1720,65 @@ func validateModelPricing(model string, pricing ModelPricing, filename string) e
 		pricing.BatchCacheRule != BatchCachePrecedence {
 		return fmt.Errorf("%s: model %q has invalid batch_cache_rule %q (must be %q or %q)", filename, model, pricing.BatchCacheRule, BatchCacheStack, BatchCachePrecedence)
 	}
+	if err := validateCacheProfiles(model, pricing, filename); err != nil {
+		return err
+	}
+	if err := validateSurcharges(pricing.Surcharges, fmt.Sprintf("model %q", model), filename); err != nil {
+		return err
+	}
+	if pricing.MinInputTokens < 0 {
+		return fmt.Errorf("%s: model %q has negative min_input_tokens: %d", filename, model, pricing.MinInputTokens)
+	}
+	if err := validateNonNegative(pricing.MinBillableUSD, "minimum billable amount", context, filename); err != nil {
+		return err
+	}
+	// Validate descriptive metadata
+	if pricing.ContextWindow < 0 || pricing.MaxOutputTokens < 0 {
+		return fmt.Errorf("%s: model %q has negative context_window or max_output_tokens", filename, model)
+	}
+	if pricing.OutputTokensPerSecond < 0 || pricing.TimeToFirstTokenMS < 0 {
+		return fmt.Errorf("%s: model %q has negative output_tokens_per_second or time_to_first_token_ms", filename, model)
+	}
+	if pricing.KnowledgeCutoff != "" && !isValidCutoffDate(pricing.KnowledgeCutoff) {
+		return fmt.Errorf("%s: model %q has invalid knowledge_cutoff %q (must be YYYY-MM or YYYY-MM-DD)", filename, model, pricing.KnowledgeCutoff)
+	}
+	if pricing.SunsetDate != "" {
+		if _, err := time.Parse("2006-01-02", pricing.SunsetDate); err != nil {
+			return fmt.Errorf("%s: model %q has invalid sunset_date %q (must be YYYY-MM-DD)", filename, model, pricing.SunsetDate)
+		}
+	}
+	if err := validatePriceHistory(model, pricing.PriceHistory, maxReasonablePrice, filename); err != nil {
+		return err
+	}
+	for _, m := range append(slices.Clone(pricing.InputModalities), pricing.OutputModalities...) {
+		if m != ModalityText && m != ModalityImage && m != ModalityAudio && m != ModalityVideo {
+			return fmt.Errorf("%s: model %q has invalid modality %q", filename, model, m)
+		}
+	}
+	// Validate reasoning heuristics
+	for effort, ratio := range pricing.ThinkingRatios {
+		if effort != ReasoningLow && effort != ReasoningMedium && effort != ReasoningHigh {
+			return fmt.Errorf("%s: model %q has invalid thinking_ratios effort %q (must be %q, %q, or %q)", filename, model, effort, ReasoningLow, ReasoningMedium, ReasoningHigh)
+		}
+		if err := validateNonNegative(ratio, "thinking ratio", context, filename); err != nil {
+			return err
+		}
+	}
+	if pricing.TierMode != "" && pricing.TierMode != TierModeCliff && pricing.TierMode != TierModeMarginal {
+		return fmt.Errorf("%s: model %q has invalid tier_mode %q (must be %q or %q)", filename, model, pricing.TierMode, TierModeCliff, TierModeMarginal)
+	}
+	switch pricing.TierBasis {
+	case "", TierBasisInput:
+	case TierBasisOutput, TierBasisCombined:
+		if pricing.TierMode == TierModeMarginal {
+			return fmt.Errorf("%s: model %q has tier_mode %q, which requires tier_basis %q", filename, model, TierModeMarginal, TierBasisInput)
+		}
+	default:
+		return fmt.Errorf("%s: model %q has invalid tier_basis %q (must be %q, %q, or %q)", filename, model, pricing.TierBasis, TierBasisInput, TierBasisOutput, TierBasisCombined)
+	}
+	if _, ok := pricing.BatchFeatures[""]; ok {
+		return fmt.Errorf("%s: model %q has a batch_features entry with an empty name", filename, model)
+	}
 	// Validate tier thresholds and prices
 	for i, tier := range pricing.Tiers {
 		tierContext := fmt.Sprintf("model %q tier %d", model, i)
@@ -813,6 +1801,54 @@ func validateModelPricing(model string, pricing ModelPricing, filename string) e
 	return nil
 }
 
+// validatePriceHistory checks that price_history dates are valid and ascending
+// and that historical prices are sane.
+func validatePriceHistory(model string, history []PricePeriod, maxPrice float64, filename string) error {
+	var prev time.Time
+	for i, period := range history {
+		until, err := time.Parse("2006-01-02", period.Until)
+		if err != nil {
+			return fmt.Errorf("%s: model %q price_history %d has invalid until %q (must be YYYY-MM-DD)", filename, model, i, period.Until)
+		}
+		if i > 0 && !until.After(prev) {
+			return fmt.Errorf("%s: model %q price_history must be in ascending until order (%q)", filename, model, period.Until)
+		}
+		prev = until
+
+		periodContext := fmt.Sprintf("model %q price_history %d", model, i)
+		for _, price := range []float64{period.InputPerMillion, period.OutputPerMillion} {
+			if err := validateNonNegative(price, "price", periodContext, filename); err != nil {
+				return err
+			}
+			if err := validateMaxReasonable(price, "price", maxPrice, periodContext, filename); err != nil {
+				return err
+			}
+		}
+		for j, tier := range period.Tiers {
+			if tier.ThresholdTokens < 0 || tier.InputPerMillion < 0 || tier.OutputPerMillion < 0 {
+				return fmt.Errorf("%s: %s tier %d has negative values", filename, periodContext, j)
+			}
+			tierContext := fmt.Sprintf("%s tier %d", periodContext, j)
+			if err := validateMaxReasonable(tier.InputPerMillion, "input price", maxPrice, tierContext, filename); err != nil {
+				return err
+			}
+			if err := validateMaxReasonable(tier.OutputPerMillion, "output price", maxPrice, tierContext, filename); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// isValidCutoffDate reports whether s is a "YYYY-MM" or "YYYY-MM-DD" date.
+func isValidCutoffDate(s string) bool {
+	if _, err := time.Parse("2006-01", s); err == nil {
+		return true
+	}
+	_, err := time.Parse("2006-01-02", s)
+	return err == nil
+}
+
 // validateGroundingPricing checks for invalid grounding pricing values.
 func validateGroundingPricing(prefix string, pricing GroundingPricing, filename string) error {
 	context := fmt.Sprintf("grounding prefix %q", prefix)
@@ -831,14 +1867,36 @@ func validateCreditPricing(pricing *CreditPricing, filename string) error {
 	if pricing.BaseCostPerRequest < 0 {
 		return fmt.Errorf("%s: credit pricing has negative base cost: %d", filename, pricing.BaseCostPerRequest)
 	}
-	if pricing.Multipliers.JSRendering < 0 {
-		return fmt.Errorf("%s: credit pricing has negative js_rendering multiplier: %d", filename, pricing.Multipliers.JSRendering)
+	names := make([]string, 0, len(pricing.Multipliers))
+	for name := range pricing.Multipliers {
+		names = append(names, name)
 	}
-	if pricing.Multipliers.PremiumProxy < 0 {
-		return fmt.Errorf("%s: credit pricing has negative premium_proxy multiplier: %d", filename, pricing.Multipliers.PremiumProxy)
+	sort.Strings(names)
+	for _, name := range names {
+		if name == "" {
+			return fmt.Errorf("%s: credit pricing has empty multiplier name", filename)
+		}
+		if mult := pricing.Multipliers[name]; mult < 0 {
+			return fmt.Errorf("%s: credit pricing has negative %s multiplier: %d", filename, name, mult)
+		}
 	}
-	if pricing.Multipliers.JSPremium < 0 {
-		return fmt.Errorf("%s: credit pricing has negative js_premium multiplier: %d", filename, pricing.Multipliers.JSPremium)
+	return nil
+}
+
+// validateCacheProfiles checks cache profile multipliers and that the default profile exists.
+func validateCacheProfiles(model string, pricing ModelPricing, filename string) error {
+	for name, profile := range pricing.CacheProfiles {
+		if profile.ReadMultiplier < 0 || profile.ReadMultiplier > 1.0 {
+			return fmt.Errorf("%s: model %q cache profile %q has read_multiplier %f out of range (0-1)", filename, model, name, profile.ReadMultiplier)
+		}
+		if profile.WriteMultiplier < 0 {
+			return fmt.Errorf("%s: model %q cache profile %q has negative write_multiplier: %f", filename, model, name, profile.WriteMultiplier)
+		}
+	}
+	if pricing.DefaultCacheProfile != "" {
+		if _, ok := pricing.CacheProfiles[pricing.DefaultCacheProfile]; !ok {
+			return fmt.Errorf("%s: model %q default_cache_profile %q is not defined in cache_profiles", filename, model, pricing.DefaultCacheProfile)
+		}
 	}
 	return nil
 }
@@ -854,9 +1912,150 @@ func validateImagePricing(model string, pricing ImageModelPricing, filename stri
 	if err := validateMaxReasonable(pricing.PricePerImage, "price", maxReasonablePrice, context, filename); err != nil {
 		return err
 	}
+	if err := validateImageMode(pricing, context, filename); err != nil {
+		return err
+	}
+	for i, res := range pricing.Resolutions {
+		resContext := fmt.Sprintf("image model %q resolution %d", model, i)
+		if res.Width < 0 || res.Height < 0 {
+			return fmt.Errorf("%s: %s has negative dimensions: %dx%d", filename, resContext, res.Width, res.Height)
+		}
+		if err := validateNonNegative(res.PricePerImage, "price", resContext, filename); err != nil {
+			return err
+		}
+		if err := validateMaxReasonable(res.PricePerImage, "price", maxReasonablePrice, resContext, filename); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateImageMode checks that an image model sets the rates its mode bills,
+// and no rates of another mode.
+func validateImageMode(pricing ImageModelPricing, context, filename string) error {
+	if err := validateNonNegative(pricing.PricePerMegapixel, "price_per_megapixel", context, filename); err != nil {
+		return err
+	}
+	if err := validateNonNegative(pricing.PricePerStep, "price_per_step", context, filename); err != nil {
+		return err
+	}
+	if pricing.DefaultSteps < 0 {
+		return fmt.Errorf("%s: %s has negative default_steps: %d", filename, context, pricing.DefaultSteps)
+	}
+
+	perImage := pricing.PricePerImage > 0 || len(pricing.Resolutions) > 0
+	perMegapixel := pricing.PricePerMegapixel > 0
+	perStep := pricing.PricePerStep > 0 || pricing.DefaultSteps > 0
+	switch pricing.Mode {
+	case "", ImagePerImage:
+		if perMegapixel || perStep {
+			return fmt.Errorf("%s: %s sets per-megapixel or per-step rates without their mode", filename, context)
+		}
+	case ImagePerMegapixel:
+		if !perMegapixel || perImage || perStep {
+			return fmt.Errorf("%s: %s with mode %q must set only price_per_megapixel", filename, context, pricing.Mode)
+		}
+	case ImagePerStep:
+		if pricing.PricePerStep <= 0 || pricing.DefaultSteps <= 0 || perImage || perMegapixel {
+			return fmt.Errorf("%s: %s with mode %q must set only price_per_step and default_steps", filename, context, pricing.Mode)
+		}
+	default:
+		return fmt.Errorf("%s: %s has unknown mode %q", filename, context, pricing.Mode)
+	}
+	return nil
+}
+
+// validateRerankPricing validates rerank model pricing.
+func validateRerankPricing(model string, pricing RerankPricing, filename string) error {
+	context := fmt.Sprintf("rerank model %q", model)
+	const maxReasonablePrice = 1000.0
+
+	if err := validateNonNegative(pricing.PerThousandSearches, "price", context, filename); err != nil {
+		return err
+	}
+	return validateMaxReasonable(pricing.PerThousandSearches, "price", maxReasonablePrice, context, filename)
+}
+
+// validateInstancePricing validates dedicated instance pricing.
+func validateInstancePricing(instanceType string, pricing InstancePricing, filename string) error {
+	context := fmt.Sprintf("instance type %q", instanceType)
+	const maxReasonablePrice = 1000.0 // USD per hour
+
+	if err := validateNonNegative(pricing.HourlyUSD, "hourly price", context, filename); err != nil {
+		return err
+	}
+	if err := validateNonNegative(pricing.PerSecondUSD, "per-second price", context, filename); err != nil {
+		return err
+	}
+	if pricing.HourlyUSD > 0 && pricing.PerSecondUSD > 0 {
+		return fmt.Errorf("%s: %s sets both hourly_usd and per_second_usd", filename, context)
+	}
+	if pricing.AcceleratorCount < 0 {
+		return fmt.Errorf("%s: %s has negative accelerator_count: %d", filename, context, pricing.AcceleratorCount)
+	}
+	return validateMaxReasonable(pricing.hourlyRate(), "hourly price", maxReasonablePrice, context, filename)
+}
+
+// validateModelHardware checks that each model_hardware entry names an
+// instance type of the same file billed per second.
+func validateModelHardware(hardware map[string]string, instances map[string]InstancePricing, filename string) error {
+	for _, model := range slices.Sorted(maps.Keys(hardware)) {
+		instance, ok := instances[hardware[model]]
+		if !ok {
+			return fmt.Errorf("%s: model_hardware %q names unknown instance type %q", filename, model, hardware[model])
+		}
+		if instance.PerSecondUSD == 0 {
+			return fmt.Errorf("%s: model_hardware %q names instance type %q without per_second_usd", filename, model, hardware[model])
+		}
+	}
 	return nil
 }
 
+// copyModelPricing returns a deep copy of ModelPricing.
+// Slices and maps are copied to prevent mutation of internal state.
+func copyModelPricing(mp ModelPricing) ModelPricing {
+	if len(mp.Tiers) > 0 {
+		mp.Tiers = append([]PricingTier(nil), mp.Tiers...)
+	}
+	if len(mp.PriceHistory) > 0 {
+		history := make([]PricePeriod, len(mp.PriceHistory))
+		for i, period := range mp.PriceHistory {
+			if len(period.Tiers) > 0 {
+				period.Tiers = append([]PricingTier(nil), period.Tiers...)
+			}
+			history[i] = period
+		}
+		mp.PriceHistory = history
+	}
+	if mp.ThinkingRatios != nil {
+		ratios := make(map[ReasoningEffort]float64, len(mp.ThinkingRatios))
+		for k, v := range mp.ThinkingRatios {
+			ratios[k] = v
+		}
+		mp.ThinkingRatios = ratios
+	}
+	if mp.Surcharges != nil {
+		mp.Surcharges = maps.Clone(mp.Surcharges)
+	}
+	if mp.BatchFeatures != nil {
+		mp.BatchFeatures = maps.Clone(mp.BatchFeatures)
+	}
+	if mp.CacheProfiles != nil {
+		profiles := make(map[string]CacheProfile, len(mp.CacheProfiles))
+		for k, v := range mp.CacheProfiles {
+			profiles[k] = v
+		}
+		mp.CacheProfiles = profiles
+	}
+	if len(mp.InputModalities) > 0 {
+		mp.InputModalities = append([]Modality(nil), mp.InputModalities...)
+	}
+	if len(mp.OutputModalities) > 0 {
+		mp.OutputModalities = append([]Modality(nil), mp.OutputModalities...)
+	}
+	return mp
+}
+
 // copyProviderPricing returns a deep copy of ProviderPricing.
 // Prevents callers from mutating internal state.
 func copyProviderPricing(pp ProviderPricing) ProviderPricing {
@@ -865,13 +2064,7 @@ func copyProviderPricing(pp ProviderPricing) ProviderPricing {
 	if pp.Models != nil {
 		result.Models = make(map[string]ModelPricing, len(pp.Models))
 		for k, v := range pp.Models {
-			copied := v
-			// Deep copy Tiers slice to prevent mutation of internal state
-			if len(v.Tiers) > 0 {
-				copied.Tiers = make([]PricingTier, len(v.Tiers))
-				copy(copied.Tiers, v.Tiers)
-			}
-			result.Models[k] = copied
+			result.Models[k] = copyModelPricing(v)
 		}
 	}
 
@@ -885,7 +2078,7 @@ func copyProviderPricing(pp ProviderPricing) ProviderPricing {
 	if pp.ImageModels != nil {
 		result.ImageModels = make(map[string]ImageModelPricing, len(pp.ImageModels))
 		for k, v := range pp.ImageModels {
-			result.ImageModels[k] = v
+			result.ImageModels[k] = copyImageModelPricing(v)
 		}
 	}
 
@@ -896,9 +2089,28 @@ func copyProviderPricing(pp ProviderPricing) ProviderPricing {
 		}
 	}
 
+	if pp.Surcharges != nil {
+		result.Surcharges = maps.Clone(pp.Surcharges)
+	}
+
+	if pp.RerankModels != nil {
+		result.RerankModels = maps.Clone(pp.RerankModels)
+	}
+
+	if pp.InstanceTypes != nil {
+		result.InstanceTypes = maps.Clone(pp.InstanceTypes)
+	}
+
+	if pp.SelfHostedModels != nil {
+		result.SelfHostedModels = maps.Clone(pp.SelfHostedModels)
+	}
+
+	if pp.ModelHardware != nil {
+		result.ModelHardware = maps.Clone(pp.ModelHardware)
+	}
+
 	if pp.CreditPricing != nil {
-		cp := *pp.CreditPricing
-		result.CreditPricing = &cp
+		result.CreditPricing = copyCreditPricing(pp.CreditPricing)
 	}
 
 	if len(pp.Metadata.SourceURLs) > 0 {
@@ -911,3 +2123,18 @@ func copyProviderPricing(pp ProviderPricing) ProviderPricing {
 
 	return result
 }
+
+// copyImageModelPricing creates a deep copy of image model pricing, including its resolutions.
+func copyImageModelPricing(pricing ImageModelPricing) ImageModelPricing {
+	if len(pricing.Resolutions) > 0 {
+		pricing.Resolutions = append([]ImageResolutionPricing(nil), pricing.Resolutions...)
+	}
+	return pricing
+}
+
+// copyCreditPricing creates a deep copy of credit pricing, including its multipliers.
+func copyCreditPricing(cp *CreditPricing) *CreditPricing {
+	result := *cp
+	result.Multipliers = maps.Clone(cp.Multipliers)
+	return &result
+}
@@ -1,8 +1,12 @@
 package pricing_db
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"math"
+	"slices"
+	"sort"
 	"strings"
 	"sync"
 	"testing"
@@ -15,6 +19,21 @@ func floatEquals(a, b float64) bool {
 	return math.Abs(a-b) < floatEpsilon
 }
 
+// newTestPricer loads a Pricer from files, which maps config file names
+// (e.g. "acme_pricing.json") to their JSON.
+func newTestPricer(t *testing.T, files map[string]string) *Pricer {
+	t.Helper()
+	fsys := fstest.MapFS{}
+	for name, data := range files {
+		fsys["configs/"+name] = &fstest.MapFile{Data: []byte(data)}
+	}
+	p, err := NewPricerFromFS(fsys, "configs")
+	if err != nil {
+		t.Fatalf("NewPricerFromFS failed: %v", err)
+	}
+	return p
+}
+
 func TestNewPricer(t *testing.T) {
 	p, err := NewPricer()
 	if err != nil {
@@ -462,6 +481,77 @@ func TestCalculateGrounding_UnknownModel(t *testing.T) {
 	}
 }
 
+func TestGetGroundingPricing(t *testing.T) {
+	p, err := NewPricer()
+	if err != nil {
+		t.Fatalf("NewPricer failed: %v", err)
+	}
+
+	pricing, ok := p.GetGroundingPricing("gemini-3-pro-preview")
+	if !ok {
+		t.Fatal("expected grounding pricing for gemini-3-pro-preview")
+	}
+	if !floatEquals(pricing.PerThousandQueries, 14.0) || pricing.BillingModel != "per_query" {
+		t.Errorf("expected $14/1K per_query, got %+v", pricing)
+	}
+	if _, ok := p.GetGroundingPricing("gpt-4o"); ok {
+		t.Error("expected no grounding pricing for gpt-4o")
+	}
+}
+
+func TestListGroundingPrefixes(t *testing.T) {
+	p, err := NewPricer()
+	if err != nil {
+		t.Fatalf("NewPricer failed: %v", err)
+	}
+
+	prefixes := p.ListGroundingPrefixes()
+	if !slices.Contains(prefixes, "gemini-3") || !slices.Contains(prefixes, "gemini-2.5") {
+		t.Errorf("expected gemini-3 and gemini-2.5 prefixes, got %v", prefixes)
+	}
+	if !sort.StringsAreSorted(prefixes) {
+		t.Errorf("expected prefixes in alphabetical order, got %v", prefixes)
+	}
+	for _, prefix := range prefixes {
+		if _, ok := p.GetGroundingPricing(prefix); !ok {
+			t.Errorf("listed prefix %q has no grounding pricing", prefix)
+		}
+	}
+}
+
+// =============================================================================
+// CalculateSearch Tests
+// =============================================================================
+
+func TestCalculateSearch(t *testing.T) {
+	p, err := NewPricer()
+	if err != nil {
+		t.Fatalf("NewPricer failed: %v", err)
+	}
+
+	// xAI Live Search: $25 per 1,000 sources
+	cost, ok := p.CalculateSearch("xai", 12)
+	if !ok {
+		t.Fatal("expected xai search pricing")
+	}
+	if !floatEquals(cost, 0.3) {
+		t.Errorf("expected $0.30 for 12 sources, got $%f", cost)
+	}
+
+	if cost, ok = p.CalculateSearch("xai", 0); !ok || cost != 0 {
+		t.Errorf("expected (0, true) for zero sources, got (%f, %v)", cost, ok)
+	}
+	if _, ok = p.CalculateSearch("anthropic", 5); ok {
+		t.Error("expected no search pricing for anthropic")
+	}
+	if got := CalculateSearchCost("xai", 4); !floatEquals(got, 0.1) {
+		t.Errorf("CalculateSearchCost: expected $0.10, got $%f", got)
+	}
+	if got := CalculateSurchargeCost("grok-4", SurchargeSearch, 4); !floatEquals(got, 0.1) {
+		t.Errorf("CalculateSurchargeCost: expected $0.10, got $%f", got)
+	}
+}
+
 // =============================================================================
 // CalculateCredit Edge Cases
 // =============================================================================
@@ -558,6 +648,44 @@ func TestCalculateCredit_JSPremiumMultiplier(t *testing.T) {
 	}
 }
 
+func TestCalculateCredit_CustomMultiplierNames(t *testing.T) {
+	// ScrapingBee-style scheme: multiplier names are free-form config keys
+	fsys := fstest.MapFS{
+		"configs/scrapingbee_pricing.json": &fstest.MapFile{Data: []byte(`{
+			"provider": "scrapingbee",
+			"billing_type": "credit",
+			"credit_pricing": {
+				"base_cost_per_request": 1,
+				"multipliers": {
+					"render_js": 5,
+					"premium_proxy": 10,
+					"stealth_proxy": 75
+				}
+			}
+		}`)},
+	}
+	p, err := NewPricerFromFS(fsys, "configs")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		multiplier string
+		expected   int
+	}{
+		{"render_js", 5},
+		{"premium_proxy", 10},
+		{"stealth_proxy", 75},
+		{"js_rendering", 1}, // not configured for this provider
+		{"", 1},
+	}
+	for _, tt := range tests {
+		if got := p.CalculateCredit("scrapingbee", tt.multiplier); got != tt.expected {
+			t.Errorf("CalculateCredit(scrapingbee, %q) = %d, want %d", tt.multiplier, got, tt.expected)
+		}
+	}
+}
+
 func TestCalculateCredit_OverflowProtection(t *testing.T) {
 	// Test that overflow returns base cost instead of corrupted value
 	// Use values that fit in int but overflow when multiplied
@@ -630,6 +758,60 @@ func TestDefaultPricer(t *testing.T) {
 	}
 }
 
+func TestInit(t *testing.T) {
+	if err := Init(context.Background()); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := Init(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled for canceled context, got: %v", err)
+	}
+}
+
+func TestSetDefaultPricer(t *testing.T) {
+	orig := DefaultPricer()
+	t.Cleanup(func() { SetDefaultPricer(orig) })
+
+	fsys := fstest.MapFS{
+		"configs/custom_pricing.json": &fstest.MapFile{Data: []byte(`{
+			"provider": "custom",
+			"models": {"custom-model": {"input_per_million": 1.0, "output_per_million": 2.0}}
+		}`)},
+	}
+	custom, err := NewPricerFromFS(fsys, "configs")
+	if err != nil {
+		t.Fatalf("NewPricerFromFS failed: %v", err)
+	}
+
+	SetDefaultPricer(custom)
+	if DefaultPricer() != custom {
+		t.Error("expected DefaultPricer to return the injected pricer")
+	}
+	if cost := CalculateCost("custom-model", 1_000_000, 1_000_000); !floatEquals(cost, 3.0) {
+		t.Errorf("expected package-level functions to use injected pricer, got cost %f", cost)
+	}
+	if cost := CalculateCost("gpt-4o", 1000, 500); cost != 0 {
+		t.Errorf("expected embedded models to be absent from injected pricer, got cost %f", cost)
+	}
+	if err := InitError(); err != nil {
+		t.Errorf("expected nil InitError after SetDefaultPricer, got: %v", err)
+	}
+	if err := Init(context.Background()); err != nil {
+		t.Errorf("expected nil Init error after SetDefaultPricer, got: %v", err)
+	}
+}
+
+func TestSetDefaultPricer_NilPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected SetDefaultPricer(nil) to panic")
+		}
+	}()
+	SetDefaultPricer(nil)
+}
+
 func TestPackageLevelGetPricing(t *testing.T) {
 	// Test known model
 	pricing, ok := GetPricing("gpt-4o")
@@ -912,6 +1094,54 @@ func TestThinkingTokens(t *testing.T) {
 	}
 }
 
+func TestThinkingTokens_DistinctRate(t *testing.T) {
+	fsys := fstest.MapFS{
+		"configs/test_pricing.json": &fstest.MapFile{Data: []byte(`{
+			"models": {
+				"reasoner": {"input_per_million": 1.0, "output_per_million": 4.0, "thinking_per_million": 2.0},
+				"plain": {"input_per_million": 1.0, "output_per_million": 4.0}
+			}
+		}`)},
+	}
+	p, err := NewPricerFromFS(fsys, "configs")
+	if err != nil {
+		t.Fatalf("NewPricerFromFS failed: %v", err)
+	}
+
+	usage := TokenUsage{PromptTokens: 1000, CompletionTokens: 1000, ThinkingTokens: 1_000_000}
+
+	// Thinking: 1M * $2/M = $2, output stays at $4/M
+	cost := p.CalculateUsage("reasoner", usage, nil)
+	if !floatEquals(cost.ThinkingCost, 2.0) {
+		t.Errorf("expected thinking cost 2.0, got %f", cost.ThinkingCost)
+	}
+	if !floatEquals(cost.OutputCost, 0.004) {
+		t.Errorf("expected output cost 0.004, got %f", cost.OutputCost)
+	}
+
+	// Without thinking_per_million, thinking falls back to the output rate
+	cost = p.CalculateUsage("plain", usage, nil)
+	if !floatEquals(cost.ThinkingCost, 4.0) {
+		t.Errorf("expected thinking cost 4.0, got %f", cost.ThinkingCost)
+	}
+
+	gemini := p.CalculateGeminiUsage("reasoner", GeminiUsageMetadata{PromptTokenCount: 1000, ThoughtsTokenCount: 1_000_000}, 0, nil)
+	if !floatEquals(gemini.ThinkingCost, 2.0) {
+		t.Errorf("expected Gemini thinking cost 2.0, got %f", gemini.ThinkingCost)
+	}
+}
+
+func TestNewPricerFromFS_NegativeThinkingPrice(t *testing.T) {
+	fsys := fstest.MapFS{
+		"configs/bad_pricing.json": &fstest.MapFile{Data: []byte(`{
+			"models": {"bad-model": {"input_per_million": 1.0, "output_per_million": 1.0, "thinking_per_million": -1}}
+		}`)},
+	}
+	if _, err := NewPricerFromFS(fsys, "configs"); err == nil {
+		t.Error("expected error for negative thinking price")
+	}
+}
+
 // =============================================================================
 // Tool Use Token Tests
 // =============================================================================
@@ -2024,6 +2254,9 @@ func TestCopyProviderPricing_NilMaps(t *testing.T) {
 	if copied.SubscriptionTiers != nil {
 		t.Error("expected SubscriptionTiers to be nil")
 	}
+	if copied.RerankModels != nil {
+		t.Error("expected RerankModels to be nil")
+	}
 	if copied.CreditPricing != nil {
 		t.Error("expected CreditPricing to be nil")
 	}
@@ -2067,10 +2300,13 @@ func TestDeepCopy_SubscriptionTiers(t *testing.T) {
 			"pro":   {Credits: 10000, PriceUSD: 29.99},
 			"elite": {Credits: 100000, PriceUSD: 99.99},
 		},
+		RerankModels: map[string]RerankPricing{
+			"rerank-v3.5": {PerThousandSearches: 2.0},
+		},
 		CreditPricing: &CreditPricing{
 			BaseCostPerRequest: 100,
-			Multipliers: CreditMultiplier{
-				JSRendering: 200,
+			Multipliers: map[string]int{
+				"js_rendering": 200,
 			},
 		},
 	}
@@ -2087,11 +2323,20 @@ func TestDeepCopy_SubscriptionTiers(t *testing.T) {
 		t.Error("copy should be independent of original - SubscriptionTiers mutation propagated")
 	}
 
+	delete(original.RerankModels, "rerank-v3.5")
+	if copied.RerankModels["rerank-v3.5"].PerThousandSearches != 2.0 {
+		t.Error("copy should be independent of original - RerankModels mutation propagated")
+	}
+
 	// Verify CreditPricing deep copy
 	original.CreditPricing.BaseCostPerRequest = 999
 	if copied.CreditPricing.BaseCostPerRequest != 100 {
 		t.Error("copy should be independent of original - CreditPricing mutation propagated")
 	}
+	original.CreditPricing.Multipliers["js_rendering"] = 1
+	if copied.CreditPricing.Multipliers["js_rendering"] != 200 {
+		t.Error("copy should be independent of original - Multipliers mutation propagated")
+	}
 }
 
 func TestCalculateGeminiUsage_DefaultCacheMultiplier(t *testing.T) {
@@ -2299,6 +2544,41 @@ func TestTiersDeepCopy(t *testing.T) {
 	}
 }
 
+func TestGetPricing_ReturnsCopy(t *testing.T) {
+	p, err := NewPricer()
+	if err != nil {
+		t.Fatalf("NewPricer failed: %v", err)
+	}
+	usage := TokenUsage{PromptTokens: 1_000_000, CacheWriteTokens: 1_000_000}
+	opts := &CalculateOptions{CacheProfile: "1h"}
+	before := p.CalculateUsage("claude-sonnet-4-5", usage, opts).CacheWriteCost
+	if before == 0 {
+		t.Fatal("expected a 1h cache write cost")
+	}
+
+	// Exact and prefix matches both hand out copies.
+	for _, model := range []string{"claude-sonnet-4-5", "claude-sonnet-4-5-20250929"} {
+		mp, ok := p.GetPricing(model)
+		if !ok {
+			t.Fatalf("expected to find %s", model)
+		}
+		mp.CacheProfiles["1h"] = CacheProfile{WriteMultiplier: 100}
+	}
+	if after := p.CalculateUsage("claude-sonnet-4-5", usage, opts).CacheWriteCost; after != before {
+		t.Errorf("mutating GetPricing's result changed the 1h cache write cost from $%f to $%f", before, after)
+	}
+
+	img, ok := p.GetImagePricing("dall-e-3")
+	if !ok || len(img.Resolutions) == 0 {
+		t.Fatal("expected dall-e-3 with resolutions")
+	}
+	price := img.Resolutions[0].PricePerImage
+	img.Resolutions[0].PricePerImage = 99
+	if again, _ := p.GetImagePricing("dall-e-3"); again.Resolutions[0].PricePerImage != price {
+		t.Errorf("mutating GetImagePricing's result changed the internal resolution price to %f", again.Resolutions[0].PricePerImage)
+	}
+}
+
 func TestDetermineTierName_NonThousandMultiples(t *testing.T) {
 	tests := []struct {
 		threshold int64
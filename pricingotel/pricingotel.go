@@ -0,0 +1,80 @@
+// Package pricingotel traces pricing_db calculations with OpenTelemetry.
+//
+// The core pricing_db package depends only on the standard library and exposes
+// a small Tracer hook. Importing this package adapts an OpenTelemetry tracer
+// to it:
+//
+//	pricer, err := pricing_db.NewPricer(pricingotel.WithTracer(otel.Tracer("pricing")))
+//	cost := pricer.CalculateUsageContext(ctx, model, usage, nil)
+//
+// Each traced call becomes a span named after the method, e.g.
+// "pricing.CalculateUsage", with the model and token counts as attributes and
+// the total cost and unknown-model flag recorded when it ends.
+package pricingotel
+
+import (
+	"context"
+	"log/slog"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	pricing_db "github.com/ai8future/pricing_db"
+)
+
+// WithTracer traces the pricer's calculations as spans of tracer.
+func WithTracer(tracer trace.Tracer) pricing_db.Option {
+	return pricing_db.WithTracer(Tracer(tracer))
+}
+
+// Tracer adapts an OpenTelemetry tracer to pricing_db.Tracer.
+func Tracer(tracer trace.Tracer) pricing_db.Tracer {
+	return otelTracer{tracer: tracer}
+}
+
+type otelTracer struct {
+	tracer trace.Tracer
+}
+
+// StartSpan starts an internal span as a child of any span in ctx.
+func (t otelTracer) StartSpan(ctx context.Context, name string, attrs ...slog.Attr) pricing_db.Span {
+	_, span := t.tracer.Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindInternal),
+		trace.WithAttributes(attributes(attrs)...),
+	)
+	return otelSpan{span: span}
+}
+
+type otelSpan struct {
+	span trace.Span
+}
+
+// End records the result attributes and ends the span.
+func (s otelSpan) End(attrs ...slog.Attr) {
+	s.span.SetAttributes(attributes(attrs)...)
+	s.span.End()
+}
+
+// attributes converts slog attributes to OpenTelemetry attributes, prefixing
+// keys with "pricing.". Kinds without an OpenTelemetry equivalent are
+// recorded as strings.
+func attributes(attrs []slog.Attr) []attribute.KeyValue {
+	kvs := make([]attribute.KeyValue, 0, len(attrs))
+	for _, a := range attrs {
+		key := "pricing." + a.Key
+		v := a.Value.Resolve()
+		switch v.Kind() {
+		case slog.KindString:
+			kvs = append(kvs, attribute.String(key, v.String()))
+		case slog.KindInt64:
+			kvs = append(kvs, attribute.Int64(key, v.Int64()))
+		case slog.KindFloat64:
+			kvs = append(kvs, attribute.Float64(key, v.Float64()))
+		case slog.KindBool:
+			kvs = append(kvs, attribute.Bool(key, v.Bool()))
+		default:
+			kvs = append(kvs, attribute.String(key, v.String()))
+		}
+	}
+	return kvs
+}
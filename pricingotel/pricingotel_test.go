@@ -0,0 +1,88 @@
+package pricingotel
+
+import (
+	"context"
+	"testing"
+	"testing/fstest"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+
+	pricing_db "github.com/ai8future/pricing_db"
+)
+
+const config = `{
+	"provider": "acme",
+	"models": {"acme-large": {"input_per_million": 3.0, "output_per_million": 15.0}}
+}`
+
+type recordedSpan struct {
+	noop.Span
+	name   string
+	parent trace.SpanContext
+	attrs  map[attribute.Key]attribute.Value
+	ended  bool
+}
+
+func (s *recordedSpan) SetAttributes(kvs ...attribute.KeyValue) {
+	for _, kv := range kvs {
+		s.attrs[kv.Key] = kv.Value
+	}
+}
+
+func (s *recordedSpan) End(...trace.SpanEndOption) {
+	s.ended = true
+}
+
+type recordingTracer struct {
+	noop.Tracer
+	spans []*recordedSpan
+}
+
+func (t *recordingTracer) Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	cfg := trace.NewSpanStartConfig(opts...)
+	s := &recordedSpan{name: name, parent: trace.SpanContextFromContext(ctx), attrs: make(map[attribute.Key]attribute.Value)}
+	s.SetAttributes(cfg.Attributes()...)
+	t.spans = append(t.spans, s)
+	return trace.ContextWithSpan(ctx, s), s
+}
+
+func TestWithTracer(t *testing.T) {
+	fsys := fstest.MapFS{"configs/acme_pricing.json": &fstest.MapFile{Data: []byte(config)}}
+	tracer := &recordingTracer{}
+	pricer, err := pricing_db.NewPricerFromFS(fsys, "configs", WithTracer(tracer))
+	if err != nil {
+		t.Fatalf("NewPricerFromFS failed: %v", err)
+	}
+
+	parent := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: trace.TraceID{1},
+		SpanID:  trace.SpanID{2},
+	})
+	ctx := trace.ContextWithSpanContext(context.Background(), parent)
+	cost := pricer.CalculateUsageContext(ctx, "acme-large", pricing_db.TokenUsage{PromptTokens: 1_000_000}, nil)
+
+	if len(tracer.spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(tracer.spans))
+	}
+	s := tracer.spans[0]
+	if s.name != "pricing.CalculateUsage" || !s.ended {
+		t.Errorf("expected ended pricing.CalculateUsage span, got %q (ended %v)", s.name, s.ended)
+	}
+	if s.parent.TraceID() != parent.TraceID() {
+		t.Errorf("expected span in trace %s, got %s", parent.TraceID(), s.parent.TraceID())
+	}
+	if got := s.attrs["pricing.model"].AsString(); got != "acme-large" {
+		t.Errorf("expected pricing.model acme-large, got %q", got)
+	}
+	if got := s.attrs["pricing.input_tokens"].AsInt64(); got != 1_000_000 {
+		t.Errorf("expected pricing.input_tokens 1000000, got %d", got)
+	}
+	if got := s.attrs["pricing.total_cost"].AsFloat64(); got != cost.TotalCost {
+		t.Errorf("expected pricing.total_cost %f, got %f", cost.TotalCost, got)
+	}
+	if s.attrs["pricing.unknown"].AsBool() {
+		t.Error("expected pricing.unknown false")
+	}
+}
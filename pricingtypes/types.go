@@ -0,0 +1,333 @@
+// Package pricingtypes defines the request and result types of pricing_db
+// (TokenUsage, CalculateOptions, CostDetails, Cost, ModelPricing, and the types
+// they use) without the embedded pricing configs. Services that only pass these
+// values around can import this package instead of pricing_db to keep the config
+// data out of their binaries. pricing_db re-exports every type and constant here
+// under the same name, so values are interchangeable between the two packages.
+package pricingtypes
+
+import (
+	"fmt"
+	"time"
+)
+
+// BatchCacheRule defines how batch and cache discounts interact
+type BatchCacheRule string
+
+const (
+	// BatchCacheStack means discounts multiply: cached_batch = cache_mult * batch_mult
+	// Used by Anthropic and OpenAI: e.g., 10% cache * 50% batch = 5% of standard
+	BatchCacheStack BatchCacheRule = "stack"
+
+	// BatchCachePrecedence means cache discount takes precedence, batch doesn't apply to cached tokens
+	// Used by Gemini: cached tokens get 10% rate regardless of batch mode
+	BatchCachePrecedence BatchCacheRule = "cache_precedence"
+)
+
+// TierMode defines how a model's tier rates apply once a threshold is crossed
+type TierMode string
+
+const (
+	// TierModeCliff bills every input and output token at the rates of the tier
+	// the request's input reaches. It is the default.
+	TierModeCliff TierMode = "cliff"
+
+	// TierModeMarginal bills each tier's input rate only on the input tokens
+	// above its threshold, like tax brackets. Cached tokens fill the lowest
+	// bands, then uncached input, then min_input_tokens padding. Output and
+	// thinking tokens are not banded and bill at the output rate of the tier
+	// reached. It requires TierBasisInput.
+	TierModeMarginal TierMode = "marginal"
+)
+
+// TierBasis defines which token count a model's tier thresholds are compared against
+type TierBasis string
+
+const (
+	// TierBasisInput selects tiers by prompt size (input plus tool-use tokens). It is the default.
+	TierBasisInput TierBasis = "input"
+
+	// TierBasisOutput selects tiers by completion size (output plus thinking tokens)
+	TierBasisOutput TierBasis = "output"
+
+	// TierBasisCombined selects tiers by input and output tokens together
+	TierBasisCombined TierBasis = "combined"
+)
+
+// ReasoningEffort is the reasoning effort level requested from a thinking model.
+type ReasoningEffort string
+
+const (
+	ReasoningLow    ReasoningEffort = "low"
+	ReasoningMedium ReasoningEffort = "medium"
+	ReasoningHigh   ReasoningEffort = "high"
+)
+
+// Modality is an input or output modality supported by a model.
+type Modality string
+
+const (
+	ModalityText  Modality = "text"
+	ModalityImage Modality = "image"
+	ModalityAudio Modality = "audio"
+	ModalityVideo Modality = "video"
+)
+
+// ModelPricing holds per-token costs for a model (in USD per million tokens)
+type ModelPricing struct {
+	InputPerMillion     float64        `json:"input_per_million"`
+	OutputPerMillion    float64        `json:"output_per_million"`
+	Tiers               []PricingTier  `json:"tiers,omitempty"`
+	TierMode            TierMode       `json:"tier_mode,omitempty"`  // How Tiers apply; empty means TierModeCliff
+	TierBasis           TierBasis      `json:"tier_basis,omitempty"` // What Tiers thresholds count; empty means TierBasisInput
+	CacheReadMultiplier float64        `json:"cache_read_multiplier,omitempty"`
+	BatchMultiplier     float64        `json:"batch_multiplier,omitempty"`
+	BatchCacheRule      BatchCacheRule `json:"batch_cache_rule,omitempty"`
+	// AudioInputPerMillion is the per-million rate for audio input tokens.
+	// Used by CalculateRealtimeSession; when zero, audio input is billed at the input rate.
+	AudioInputPerMillion float64 `json:"audio_input_per_million,omitempty"`
+	// AudioOutputPerMillion is the per-million rate for audio output tokens.
+	// Used by CalculateRealtimeSession; when zero, audio output is billed at the output rate.
+	AudioOutputPerMillion float64 `json:"audio_output_per_million,omitempty"`
+	// ThinkingPerMillion is the per-million rate for thinking (reasoning) tokens.
+	// When zero, thinking tokens are billed at the output rate of the tier applied.
+	ThinkingPerMillion float64 `json:"thinking_per_million,omitempty"`
+	BatchGroundingOK   bool    `json:"batch_grounding_ok,omitempty"` // false = grounding not supported in batch; see also BatchFeatures
+	// ImageInputPerMillion is the per-million rate for image input tokens in multimodal prompts.
+	// When zero, image tokens are billed at the standard input rate.
+	ImageInputPerMillion float64 `json:"image_input_per_million,omitempty"`
+	// PerInputImage is a flat USD fee charged per input image, on top of any token charges.
+	PerInputImage float64 `json:"per_input_image,omitempty"`
+	// CacheProfiles prices named prompt-cache variants, e.g. Anthropic's "5m" and "1h"
+	// cache TTLs, selected with CalculateOptions.CacheProfile.
+	CacheProfiles map[string]CacheProfile `json:"cache_profiles,omitempty"`
+	// DefaultCacheProfile is the profile used when CalculateOptions.CacheProfile is empty.
+	DefaultCacheProfile string `json:"default_cache_profile,omitempty"`
+	// Surcharges are named per-unit fees billed on top of token costs (e.g., citations),
+	// overriding provider-level surcharges of the same name.
+	Surcharges map[string]Surcharge `json:"surcharges,omitempty"`
+	// BatchFeatures sets, per surcharge name ("grounding", "web_search",
+	// "code_execution", ...), whether the feature is available in batch mode for
+	// this model, overriding the surcharge's batch_ok (and BatchGroundingOK for
+	// grounding). Unavailable features are excluded from batch costs with a warning.
+	BatchFeatures map[string]bool `json:"batch_features,omitempty"`
+	// MinInputTokens is the smallest input token count billed for a request that
+	// uses any tokens; shorter prompts are billed as if they had this many.
+	MinInputTokens int64 `json:"min_input_tokens,omitempty"`
+	// MinBillableUSD is the smallest token charge billed per request; cheaper
+	// requests are topped up to this amount (reported as MinimumCharge).
+	MinBillableUSD float64 `json:"min_billable_usd,omitempty"`
+	// ThinkingRatios is a pre-flight heuristic: expected thinking tokens per visible
+	// output token at each reasoning effort. Used by EstimateThinkingTokens only.
+	ThinkingRatios map[ReasoningEffort]float64 `json:"thinking_ratios,omitempty"`
+
+	// Descriptive metadata (optional, not used in cost calculations)
+	ContextWindow    int64      `json:"context_window,omitempty"`    // Max total tokens per request
+	MaxOutputTokens  int64      `json:"max_output_tokens,omitempty"` // Max tokens per response
+	KnowledgeCutoff  string     `json:"knowledge_cutoff,omitempty"`  // "YYYY-MM" or "YYYY-MM-DD"
+	InputModalities  []Modality `json:"input_modalities,omitempty"`
+	OutputModalities []Modality `json:"output_modalities,omitempty"`
+
+	// Throughput metadata for latency estimates (EstimateLatencyAndCost); not used in cost calculations
+	OutputTokensPerSecond float64 `json:"output_tokens_per_second,omitempty"` // Typical decode speed
+	TimeToFirstTokenMS    float64 `json:"time_to_first_token_ms,omitempty"`   // Typical time to first token
+
+	// PriceHistory lists the model's earlier prices, oldest first, for pricing
+	// historical usage with CalculateAt. The top-level price applies from the
+	// last entry's Until date.
+	PriceHistory []PricePeriod `json:"price_history,omitempty"`
+
+	// Lifecycle metadata: pricing a deprecated model adds a WarningDeprecatedModel warning
+	Deprecated  bool   `json:"deprecated,omitempty"`
+	SunsetDate  string `json:"sunset_date,omitempty"` // "YYYY-MM-DD" the model is retired
+	Replacement string `json:"replacement,omitempty"` // Suggested model to migrate to
+}
+
+// PricingTier defines pricing for a specific token threshold (e.g., >200K tokens)
+type PricingTier struct {
+	ThresholdTokens  int64   `json:"threshold_tokens"`
+	InputPerMillion  float64 `json:"input_per_million"`
+	OutputPerMillion float64 `json:"output_per_million"`
+}
+
+// PricePeriod is an earlier price of a model, in effect until Until (exclusive).
+// Until is a "YYYY-MM-DD" date at 00:00 UTC, matching provider billing.
+type PricePeriod struct {
+	Until            string        `json:"until"`
+	InputPerMillion  float64       `json:"input_per_million"`
+	OutputPerMillion float64       `json:"output_per_million"`
+	Tiers            []PricingTier `json:"tiers,omitempty"`
+}
+
+// Surcharge is a per-unit fee billed on top of token costs, such as web search,
+// citations, or safety filtering. Surcharges are declared by name on a model or
+// provider and billed from TokenUsage.Surcharges.
+type Surcharge struct {
+	Unit         string  `json:"unit"`               // What is counted, e.g. "query", "source", "request"
+	PricePerUnit float64 `json:"price_per_unit"`     // USD per unit
+	BatchOK      bool    `json:"batch_ok,omitempty"` // false = not available in batch mode; excluded with a warning
+}
+
+// Built-in surcharge names. Grounding config entries are exposed as the
+// grounding surcharge; providers declare the others under surcharges.
+const (
+	SurchargeGrounding  = "grounding"   // Google grounding, per query
+	SurchargeSearch     = "search"      // Provider live search (e.g. xAI), per source
+	SurchargeWebSearch  = "web_search"  // Server-side web search tool (Anthropic, OpenAI Responses API), per call
+	SurchargeFileSearch = "file_search" // OpenAI Responses API file search tool, per call
+)
+
+// CacheProfile holds the cache multipliers of one prompt-cache variant,
+// applied to the input rate.
+type CacheProfile struct {
+	ReadMultiplier  float64 `json:"read_multiplier,omitempty"` // 0 = model's cache_read_multiplier
+	WriteMultiplier float64 `json:"write_multiplier"`          // Cache-write tokens, e.g. 1.25 for Anthropic's 5-minute TTL
+}
+
+// WarningCode identifies the kind of a structured Warning.
+type WarningCode string
+
+const (
+	WarningTokenOverflow             WarningCode = "token_overflow"
+	WarningCachedTokensClamped       WarningCode = "cached_tokens_clamped"
+	WarningBatchGroundingExcluded    WarningCode = "batch_grounding_excluded"
+	WarningAudioRateMissing          WarningCode = "audio_rate_missing"
+	WarningReasoningHeuristicMissing WarningCode = "reasoning_heuristic_missing"
+	WarningDeprecatedModel           WarningCode = "deprecated_model"
+	WarningMinimumBilled             WarningCode = "minimum_billed"
+	WarningUsageMismatch             WarningCode = "usage_mismatch"
+	WarningCacheProfileMissing       WarningCode = "cache_profile_missing"
+	WarningSurchargeUnknown          WarningCode = "surcharge_unknown"
+	WarningBatchSurchargeExcluded    WarningCode = "batch_surcharge_excluded"
+	WarningStalePricing              WarningCode = "stale_pricing"
+	WarningFallbackPricing           WarningCode = "fallback_pricing"
+	WarningFallbackApplied           WarningCode = "fallback_applied"
+	WarningCreditLow                 WarningCode = "credit_low"
+	WarningCreditExpiring            WarningCode = "credit_expiring"
+	WarningCreditExhausted           WarningCode = "credit_exhausted"
+	WarningMalformedResponse         WarningCode = "malformed_response"
+)
+
+// Warning is a structured warning attached to a cost calculation.
+// Code is stable for programmatic handling; Message is human-readable.
+type Warning struct {
+	Code    WarningCode
+	Message string
+}
+
+// Cost represents the calculated cost breakdown for token-based pricing
+type Cost struct {
+	Model          string
+	InputTokens    int64
+	OutputTokens   int64
+	InputCost      float64
+	OutputCost     float64
+	MinimumCharge  float64   // Top-up to the model's min_billable_usd, included in TotalCost
+	TotalCost      float64   // RawTotal rounded by the Pricer's RoundingPolicy
+	RawTotal       float64   // Unrounded sum of the cost components
+	Unknown        bool      // true if model not found in pricing data
+	WarningDetails []Warning // e.g., deprecated model
+}
+
+// TokenUsage holds a provider-neutral token breakdown for a single request.
+// Pass it to Pricer.CalculateUsage; provider-specific structs such as
+// GeminiUsageMetadata are converted to TokenUsage internally.
+type TokenUsage struct {
+	PromptTokens     int64 // Standard input tokens
+	CompletionTokens int64 // Standard output tokens
+	CachedTokens     int64 // Tokens served from cache (subset of input)
+	ThinkingTokens   int64 // Charged at OUTPUT rate
+	ToolUseTokens    int64 // Added to input (Gemini toolUsePromptTokenCount)
+	GroundingQueries int   // Google search queries (shorthand for Surcharges["grounding"])
+	ImageInputTokens int64 // Image tokens in the prompt (subset of input)
+	ImageCount       int   // Number of input images, for per-image fees
+	// CacheWriteTokens are tokens written to the prompt cache (subset of input), billed
+	// at the cache profile's write multiplier, or at the input rate without a profile.
+	CacheWriteTokens int64
+	// AudioInputTokens are audio tokens in the prompt (subset of input), billed at
+	// audio_input_per_million when the model has one, otherwise at the input rate.
+	AudioInputTokens int64
+	// AudioOutputTokens are audio tokens in the output (subset of completion), billed at
+	// audio_output_per_million when the model has one, otherwise at the output rate.
+	AudioOutputTokens int64
+	// Surcharges counts units of named per-unit fees (e.g., "search" sources),
+	// priced by the model's or provider's surcharges.
+	Surcharges map[string]int64
+
+	// PromptModalities and OutputModalities are the provider's optional per-modality
+	// token split (e.g., Gemini promptTokensDetails). They are copied to CostDetails
+	// for observability; billing uses the fields above.
+	PromptModalities ModalityTokens
+	OutputModalities ModalityTokens
+}
+
+// ModalityTokens counts tokens by modality.
+type ModalityTokens struct {
+	Text  int64
+	Image int64
+	Audio int64
+	Video int64
+}
+
+// CostDetails provides detailed cost breakdown for complex calculations
+type CostDetails struct {
+	StandardInputCost float64
+	CachedInputCost   float64
+	CacheWriteCost    float64 // Cache-write tokens priced by the cache profile
+	ImageInputCost    float64 // Image input tokens and per-image fees
+	AudioInputCost    float64 // Non-cached audio input tokens
+	OutputCost        float64
+	AudioOutputCost   float64 // Audio output tokens
+	ThinkingCost      float64
+	GroundingCost     float64
+	SurchargeCost     float64 // Named per-unit fees from TokenUsage.Surcharges
+	TierApplied       string
+	BatchDiscount     float64
+	MinimumCharge     float64        // Top-up to the model's min_billable_usd, included in TotalCost
+	TotalCost         float64        // RawTotal rounded by the Pricer's RoundingPolicy
+	RawTotal          float64        // Unrounded sum of the cost components; sum these and round once when aggregating
+	Markup            float64        // Reseller uplift on RawTotal (WithMarkup); 0 without a markup
+	BilledTotal       float64        // Customer price: RawTotal + Markup, rounded like TotalCost
+	Tax               float64        // Tax on RawTotal + Markup at the WithTaxRate rate; 0 without a tax rate
+	GrossTotal        float64        // Tax-inclusive customer price: RawTotal + Markup + Tax, rounded like TotalCost
+	BatchMode         bool           // Whether batch pricing was applied
+	Warnings          []string       // Human-readable warnings (messages of WarningDetails)
+	WarningDetails    []Warning      // Structured warnings, in the same order as Warnings
+	Unknown           bool           // Whether the model was not found
+	PromptModalities  ModalityTokens // Per-modality prompt tokens, when the usage reported them
+	OutputModalities  ModalityTokens // Per-modality output tokens, when the usage reported them
+	// PricingVersion is the Version of the pricing catalog that produced this result,
+	// so a recorded cost can be reproduced from the same snapshot.
+	PricingVersion string
+	// CalculatedAt is when the cost was calculated (UTC). It is zero unless the
+	// Pricer was created with WithCalculationTimestamps.
+	CalculatedAt time.Time `json:",omitzero"`
+}
+
+// RealtimeUsage holds the token breakdown for a realtime (audio streaming) session,
+// such as OpenAI Realtime or Gemini Live. Text and audio are counted separately
+// because they are billed at very different rates. Cached counts are subsets of
+// the corresponding input counts.
+type RealtimeUsage struct {
+	TextInputTokens        int64
+	AudioInputTokens       int64
+	CachedTextInputTokens  int64
+	CachedAudioInputTokens int64
+	TextOutputTokens       int64
+	AudioOutputTokens      int64
+}
+
+// CalculateOptions provides options for cost calculations
+type CalculateOptions struct {
+	BatchMode    bool   // Apply batch discount (typically 50%)
+	CacheProfile string // Named cache profile (e.g., "1h"); empty uses the model's default_cache_profile
+}
+
+// Format returns a human-readable cost breakdown
+func (c Cost) Format() string {
+	if c.Unknown {
+		return fmt.Sprintf("Cost: unknown (model %q not in pricing data)", c.Model)
+	}
+	return fmt.Sprintf("Input: $%.4f (%d tokens) | Output: $%.4f (%d tokens) | Total: $%.4f",
+		c.InputCost, c.InputTokens, c.OutputCost, c.OutputTokens, c.TotalCost)
+}
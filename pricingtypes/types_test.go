@@ -0,0 +1,35 @@
+package pricingtypes
+
+import (
+	"go/build"
+	"strings"
+	"testing"
+)
+
+// TestNoEmbeddedData guards the point of this package: it must not import
+// pricing_db (or anything else that would link the embedded configs).
+func TestNoEmbeddedData(t *testing.T) {
+	pkg, err := build.ImportDir(".", 0)
+	if err != nil {
+		t.Fatalf("ImportDir failed: %v", err)
+	}
+	for _, imp := range pkg.Imports {
+		if strings.Contains(imp, ".") || imp == "embed" {
+			t.Errorf("pricingtypes should depend only on the standard library without embed, imports %q", imp)
+		}
+	}
+	if len(pkg.EmbedPatterns) > 0 {
+		t.Errorf("pricingtypes should not embed files, has %v", pkg.EmbedPatterns)
+	}
+}
+
+func TestCostFormat(t *testing.T) {
+	c := Cost{Model: "m", InputTokens: 1000, OutputTokens: 500, InputCost: 0.001, OutputCost: 0.002, TotalCost: 0.003}
+	want := "Input: $0.0010 (1000 tokens) | Output: $0.0020 (500 tokens) | Total: $0.0030"
+	if got := c.Format(); got != want {
+		t.Errorf("Format() = %q, want %q", got, want)
+	}
+	if got := (Cost{Model: "m", Unknown: true}).Format(); !strings.Contains(got, "unknown") {
+		t.Errorf("expected unknown cost format, got %q", got)
+	}
+}
@@ -0,0 +1,208 @@
+package pricing_db
+
+import (
+	"math"
+	"time"
+)
+
+// modelRates is a model's pricing resolved at load time, so the hot path is a
+// map lookup plus a few multiplications instead of re-deriving multipliers and
+// formatting tier names on every call.
+type modelRates struct {
+	pricing         ModelPricing
+	tiers           []tierRates // tiers[0] is the standard rate; thresholds ascending
+	batchMultiplier float64     // Applied in batch mode (1.0 when the model has no batch discount)
+	cacheMultiplier float64     // cache_read_multiplier, or the catalog default when unset
+	cachePrecedence bool        // Batch discount does not apply to cached tokens
+	marginalTiers   bool        // tier_mode marginal: tier input rates apply only above their thresholds
+	tierBasis       TierBasis   // Token count tiers are selected on; empty means input
+	// surcharges are the per-unit fees that apply to the model, by name (see resolveSurcharges)
+	surcharges map[string]Surcharge
+	history    []historicalRates // price_history, oldest first
+	provider   string            // Provider supplying the pricing
+	updated    time.Time         // Provider's metadata.updated date; zero if unset or invalid
+}
+
+// historicalRates are the compiled rates in effect before until.
+type historicalRates struct {
+	until time.Time
+	rates *modelRates
+}
+
+// tierRates holds the rates and display name of a single pricing tier.
+type tierRates struct {
+	thresholdTokens  int64
+	name             string // "standard", ">200K", ...
+	inputPerMillion  float64
+	outputPerMillion float64
+}
+
+// compileRates resolves pricing into a modelRates, using cacheDefault when the
+// model has no cache_read_multiplier. pricing.Tiers must be sorted.
+func compileRates(pricing ModelPricing, cacheDefault float64) *modelRates {
+	r := &modelRates{
+		pricing:         pricing,
+		batchMultiplier: 1.0,
+		cacheMultiplier: pricing.CacheReadMultiplier,
+		cachePrecedence: pricing.BatchCacheRule == BatchCachePrecedence,
+		marginalTiers:   pricing.TierMode == TierModeMarginal,
+		tierBasis:       pricing.TierBasis,
+	}
+	if pricing.BatchMultiplier > 0 {
+		r.batchMultiplier = pricing.BatchMultiplier
+	}
+	if r.cacheMultiplier == 0 {
+		r.cacheMultiplier = cacheDefault
+	}
+
+	r.tiers = make([]tierRates, 0, len(pricing.Tiers)+1)
+	r.tiers = append(r.tiers, tierRates{
+		thresholdTokens:  math.MinInt64,
+		name:             "standard",
+		inputPerMillion:  pricing.InputPerMillion,
+		outputPerMillion: pricing.OutputPerMillion,
+	})
+	for _, tier := range pricing.Tiers {
+		r.tiers = append(r.tiers, tierRates{
+			thresholdTokens:  tier.ThresholdTokens,
+			name:             tierName(pricing, tier.ThresholdTokens),
+			inputPerMillion:  tier.InputPerMillion,
+			outputPerMillion: tier.OutputPerMillion,
+		})
+	}
+
+	for _, period := range pricing.PriceHistory {
+		until, err := time.Parse("2006-01-02", period.Until)
+		if err != nil {
+			continue // rejected by validation
+		}
+		past := pricing
+		past.InputPerMillion = period.InputPerMillion
+		past.OutputPerMillion = period.OutputPerMillion
+		past.Tiers = period.Tiers
+		past.PriceHistory = nil
+		r.history = append(r.history, historicalRates{until: until, rates: compileRates(past, cacheDefault)})
+	}
+	return r
+}
+
+// historyRates returns the compiled rates of each price_history entry.
+func (r *modelRates) historyRates() []*modelRates {
+	rates := make([]*modelRates, len(r.history))
+	for i, h := range r.history {
+		rates[i] = h.rates
+	}
+	return rates
+}
+
+// at returns the rates in effect at t: the first history entry t falls before,
+// or the current rates. A zero t, or nil r, is returned unchanged.
+func (r *modelRates) at(t time.Time) *modelRates {
+	if r == nil || t.IsZero() {
+		return r
+	}
+	for _, h := range r.history {
+		if t.Before(h.until) {
+			return h.rates
+		}
+	}
+	return r
+}
+
+// compileRateTable compiles every model in the catalog, keyed like models.
+func compileRateTable(models map[string]ModelPricing, cacheDefault float64) map[string]*modelRates {
+	rates := make(map[string]*modelRates, len(models))
+	for key, pricing := range models {
+		rates[key] = compileRates(pricing, cacheDefault)
+	}
+	return rates
+}
+
+// tierName is determineTierName, suffixed with the tier_basis when tiers are
+// not selected on input (">32K output").
+func tierName(pricing ModelPricing, threshold int64) string {
+	name := determineTierName(pricing, threshold)
+	if pricing.TierBasis != "" && pricing.TierBasis != TierBasisInput {
+		name += " " + string(pricing.TierBasis)
+	}
+	return name
+}
+
+// tierTokens returns the token count tiers are selected on under the model's
+// tier_basis: input, output plus thinking, or all of them.
+func (r *modelRates) tierTokens(input, output, thinking int64) int64 {
+	switch r.tierBasis {
+	case TierBasisOutput:
+		total, _ := addInt64Safe(output, thinking)
+		return total
+	case TierBasisCombined:
+		total, _ := addInt64Safe(input, output)
+		total, _ = addInt64Safe(total, thinking)
+		return total
+	}
+	return input
+}
+
+// tier returns the highest tier whose threshold tokens reaches.
+func (r *modelRates) tier(tokens int64) *tierRates {
+	for i := len(r.tiers) - 1; i > 0; i-- {
+		if tokens >= r.tiers[i].thresholdTokens {
+			return &r.tiers[i]
+		}
+	}
+	return &r.tiers[0]
+}
+
+// inputRate returns the per-million input rate for a request reaching tier
+// with totalInputTokens of input; see inputRateBetween.
+func (r *modelRates) inputRate(tier *tierRates, totalInputTokens int64) float64 {
+	return r.inputRateBetween(tier, 0, totalInputTokens)
+}
+
+// inputRateBetween returns the per-million input rate of the tokens at
+// positions [from, to) of a request's input, for a request reaching tier.
+// Under tier_mode marginal the input fills the tier bands in order and the rate
+// is the average of each tier's rate over the part of its band in the range, so
+// only the tokens above a threshold pay that tier's rate. Otherwise every input
+// token pays the reached tier's rate. Output and thinking tokens are not
+// banded: in both modes they pay the reached tier's output rate.
+func (r *modelRates) inputRateBetween(tier *tierRates, from, to int64) float64 {
+	if !r.marginalTiers || to <= from {
+		return tier.inputPerMillion
+	}
+	var cost float64
+	for i := range r.tiers {
+		start := max(r.tiers[i].thresholdTokens, from)
+		end := to
+		if i+1 < len(r.tiers) {
+			end = min(end, r.tiers[i+1].thresholdTokens)
+		}
+		if end > start {
+			cost += float64(end-start) * r.tiers[i].inputPerMillion
+		}
+	}
+	return cost / float64(to-from)
+}
+
+// lookupRates finds compiled rates by exact match, then by prefix match.
+// The result is counted in the lookup stats when WithLookupStats is set.
+func (c *catalog) lookupRates(model string) (*modelRates, bool) {
+	if r, ok := c.rates[model]; ok {
+		c.lookups.record(lookupExact)
+		return r, true
+	}
+	key, cached, ok := c.prefixKey(model)
+	switch {
+	case !ok && cached:
+		c.lookups.record(lookupMissCached)
+		return nil, false
+	case !ok:
+		c.lookups.record(lookupMiss)
+		return nil, false
+	case cached:
+		c.lookups.record(lookupCached)
+	default:
+		c.lookups.record(lookupPrefix)
+	}
+	return c.rates[key], true
+}
@@ -0,0 +1,329 @@
+package pricing_db
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// =============================================================================
+// Precompiled Rate Tests
+// =============================================================================
+
+func TestCompileRates_Multipliers(t *testing.T) {
+	r := compileRates(ModelPricing{InputPerMillion: 1.0, OutputPerMillion: 2.0}, defaultCacheMultiplier)
+	if r.batchMultiplier != 1.0 || r.cacheMultiplier != defaultCacheMultiplier || r.cachePrecedence {
+		t.Errorf("unexpected defaults: %+v", r)
+	}
+
+	r = compileRates(ModelPricing{
+		InputPerMillion:     1.0,
+		OutputPerMillion:    2.0,
+		BatchMultiplier:     0.5,
+		CacheReadMultiplier: 0.25,
+		BatchCacheRule:      BatchCachePrecedence,
+	}, defaultCacheMultiplier)
+	if r.batchMultiplier != 0.5 || r.cacheMultiplier != 0.25 || !r.cachePrecedence {
+		t.Errorf("unexpected compiled multipliers: %+v", r)
+	}
+}
+
+func TestCompileRates_TierSelection(t *testing.T) {
+	r := compileRates(ModelPricing{
+		InputPerMillion:  1.0,
+		OutputPerMillion: 2.0,
+		Tiers: []PricingTier{
+			{ThresholdTokens: 128000, InputPerMillion: 2.0, OutputPerMillion: 4.0},
+			{ThresholdTokens: 200500, InputPerMillion: 3.0, OutputPerMillion: 6.0},
+		},
+	}, defaultCacheMultiplier)
+
+	tests := []struct {
+		tokens    int64
+		wantName  string
+		wantInput float64
+	}{
+		{0, "standard", 1.0},
+		{127999, "standard", 1.0},
+		{128000, ">128K", 2.0},
+		{200499, ">128K", 2.0},
+		{200500, ">200.5K", 3.0},
+		{10_000_000, ">200.5K", 3.0},
+	}
+	for _, tt := range tests {
+		tier := r.tier(tt.tokens)
+		if tier.name != tt.wantName || tier.inputPerMillion != tt.wantInput {
+			t.Errorf("tier(%d) = %q at $%.1f, want %q at $%.1f", tt.tokens, tier.name, tier.inputPerMillion, tt.wantName, tt.wantInput)
+		}
+	}
+}
+
+func TestCompileRates_MarginalInputRate(t *testing.T) {
+	r := compileRates(ModelPricing{
+		InputPerMillion:  1.0,
+		OutputPerMillion: 2.0,
+		TierMode:         TierModeMarginal,
+		Tiers: []PricingTier{
+			{ThresholdTokens: 100_000, InputPerMillion: 2.0, OutputPerMillion: 4.0},
+			{ThresholdTokens: 200_000, InputPerMillion: 4.0, OutputPerMillion: 8.0},
+		},
+	}, defaultCacheMultiplier)
+
+	tests := []struct {
+		tokens int64
+		want   float64
+	}{
+		{0, 1.0},
+		{50_000, 1.0},
+		{100_000, 1.0},     // the threshold token itself starts the next band
+		{150_000, 4.0 / 3}, // 100K at $1 + 50K at $2
+		{400_000, 2.75},    // 100K at $1 + 100K at $2 + 200K at $4
+	}
+	for _, tt := range tests {
+		if got := r.inputRate(r.tier(tt.tokens), tt.tokens); !floatEquals(got, tt.want) {
+			t.Errorf("inputRate(%d) = %f, want %f", tt.tokens, got, tt.want)
+		}
+	}
+}
+
+func TestCalculateUsage_MarginalTiers(t *testing.T) {
+	fsys := fstest.MapFS{
+		"configs/test_pricing.json": &fstest.MapFile{Data: []byte(`{
+			"provider": "test",
+			"models": {
+				"cliff-model": {
+					"input_per_million": 1, "output_per_million": 4,
+					"tiers": [{"threshold_tokens": 200000, "input_per_million": 2, "output_per_million": 8}]
+				},
+				"marginal-model": {
+					"input_per_million": 1, "output_per_million": 4, "tier_mode": "marginal",
+					"tiers": [{"threshold_tokens": 200000, "input_per_million": 2, "output_per_million": 8}]
+				}
+			}
+		}`)},
+	}
+	p, err := NewPricerFromFS(fsys, "configs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	usage := TokenUsage{PromptTokens: 300_000, CompletionTokens: 1_000_000}
+
+	// Cliff: all 300K input at $2, output at $8
+	cliff := p.CalculateUsage("cliff-model", usage, nil)
+	if !floatEquals(cliff.StandardInputCost, 0.6) || !floatEquals(cliff.OutputCost, 8.0) {
+		t.Errorf("cliff: expected input $0.60 and output $8, got %+v", cliff)
+	}
+
+	// Marginal: 200K at $1 + 100K at $2; output still at the reached tier's $8
+	marginal := p.CalculateUsage("marginal-model", usage, nil)
+	if !floatEquals(marginal.StandardInputCost, 0.4) || !floatEquals(marginal.OutputCost, 8.0) {
+		t.Errorf("marginal: expected input $0.40 and output $8, got %+v", marginal)
+	}
+	if marginal.TierApplied != ">200K" {
+		t.Errorf("expected tier >200K, got %q", marginal.TierApplied)
+	}
+}
+
+func TestCalculateUsage_MarginalTiersCachedAndPadded(t *testing.T) {
+	fsys := fstest.MapFS{
+		"configs/test_pricing.json": &fstest.MapFile{Data: []byte(`{
+			"provider": "test",
+			"models": {
+				"marginal-model": {
+					"input_per_million": 1, "output_per_million": 4, "tier_mode": "marginal",
+					"cache_read_multiplier": 0.5,
+					"tiers": [{"threshold_tokens": 200000, "input_per_million": 2, "output_per_million": 8}]
+				},
+				"marginal-min": {
+					"input_per_million": 1, "output_per_million": 4, "tier_mode": "marginal",
+					"min_input_tokens": 2000,
+					"tiers": [{"threshold_tokens": 1000, "input_per_million": 2, "output_per_million": 8}]
+				}
+			}
+		}`)},
+	}
+	p, err := NewPricerFromFS(fsys, "configs")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The cached prefix fills the bands first: 200K at $1 + 50K at $2, at 50%.
+	// The uncached 50K lie above the threshold, at $2.
+	cached := p.CalculateUsage("marginal-model", TokenUsage{PromptTokens: 300_000, CachedTokens: 250_000}, nil)
+	if !floatEquals(cached.CachedInputCost, 0.15) || !floatEquals(cached.StandardInputCost, 0.1) {
+		t.Errorf("expected cached $0.15 and standard $0.10, got cached $%f and standard $%f", cached.CachedInputCost, cached.StandardInputCost)
+	}
+
+	// 500 tokens padded to 2000: 1000 at $1 + 1000 at $2
+	padded := p.CalculateUsage("marginal-min", TokenUsage{PromptTokens: 500}, nil)
+	if !floatEquals(padded.StandardInputCost, 0.003) {
+		t.Errorf("expected padded input $0.003, got $%f", padded.StandardInputCost)
+	}
+}
+
+func TestCalculateUsage_TierBasis(t *testing.T) {
+	fsys := fstest.MapFS{
+		"configs/test_pricing.json": &fstest.MapFile{Data: []byte(`{
+			"provider": "test",
+			"models": {
+				"output-tiered": {
+					"input_per_million": 1, "output_per_million": 4, "tier_basis": "output",
+					"tiers": [{"threshold_tokens": 32000, "input_per_million": 1, "output_per_million": 6}]
+				},
+				"combined-tiered": {
+					"input_per_million": 1, "output_per_million": 4, "tier_basis": "combined",
+					"tiers": [{"threshold_tokens": 100000, "input_per_million": 2, "output_per_million": 8}]
+				}
+			}
+		}`)},
+	}
+	p, err := NewPricerFromFS(fsys, "configs")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		model      string
+		usage      TokenUsage
+		wantTier   string
+		wantOutput float64
+	}{
+		// A large prompt does not reach an output-based tier
+		{"output-tiered", TokenUsage{PromptTokens: 500_000, CompletionTokens: 10_000}, "standard", 0.04},
+		// Output plus thinking tokens do
+		{"output-tiered", TokenUsage{PromptTokens: 1000, CompletionTokens: 20_000, ThinkingTokens: 20_000}, ">32K output", 0.12},
+		{"combined-tiered", TokenUsage{PromptTokens: 60_000, CompletionTokens: 40_000}, ">100K combined", 0.32},
+		{"combined-tiered", TokenUsage{PromptTokens: 60_000, CompletionTokens: 30_000}, "standard", 0.12},
+	}
+	for _, tt := range tests {
+		cost := p.CalculateUsage(tt.model, tt.usage, nil)
+		if cost.TierApplied != tt.wantTier || !floatEquals(cost.OutputCost, tt.wantOutput) {
+			t.Errorf("%s %+v: got tier %q output $%f, want %q $%f", tt.model, tt.usage, cost.TierApplied, cost.OutputCost, tt.wantTier, tt.wantOutput)
+		}
+	}
+}
+
+func TestNewPricerFromFS_InvalidTierBasis(t *testing.T) {
+	for body, want := range map[string]string{
+		`"tier_basis": "prompt"`:                          "invalid tier_basis",
+		`"tier_basis": "output", "tier_mode": "marginal"`: "requires tier_basis",
+	} {
+		fsys := fstest.MapFS{
+			"configs/test_pricing.json": &fstest.MapFile{Data: []byte(`{
+				"models": {"m": {"input_per_million": 1, "output_per_million": 1, ` + body + `}}
+			}`)},
+		}
+		if _, err := NewPricerFromFS(fsys, "configs"); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%s: expected error containing %q, got %v", body, want, err)
+		}
+	}
+}
+
+func TestNewPricerFromFS_InvalidTierMode(t *testing.T) {
+	fsys := fstest.MapFS{
+		"configs/test_pricing.json": &fstest.MapFile{Data: []byte(`{
+			"models": {"m": {"input_per_million": 1, "output_per_million": 1, "tier_mode": "stepped"}}
+		}`)},
+	}
+	if _, err := NewPricerFromFS(fsys, "configs"); err == nil || !strings.Contains(err.Error(), "invalid tier_mode") {
+		t.Errorf("expected invalid tier_mode error, got %v", err)
+	}
+}
+
+func TestCompileRateTable_CoversCatalog(t *testing.T) {
+	p, err := NewPricer()
+	if err != nil {
+		t.Fatalf("NewPricer failed: %v", err)
+	}
+	c := p.cat.Load()
+
+	if len(c.rates) != len(c.models) {
+		t.Fatalf("rate table has %d entries, catalog has %d models", len(c.rates), len(c.models))
+	}
+	for key, pricing := range c.models {
+		r := c.rates[key]
+		if r == nil || r.pricing.InputPerMillion != pricing.InputPerMillion || len(r.tiers) != len(pricing.Tiers)+1 {
+			t.Errorf("%s: compiled rates do not match pricing", key)
+		}
+	}
+}
+
+func TestCalculateUsage_TieredAllocationFree(t *testing.T) {
+	p, err := NewPricer()
+	if err != nil {
+		t.Fatalf("NewPricer failed: %v", err)
+	}
+
+	allocs := testing.AllocsPerRun(100, func() {
+		_ = p.CalculateWithOptions("gemini-2.5-pro", 300000, 5000, 0, nil)
+	})
+	if allocs != 0 {
+		t.Errorf("expected tiered calculation without allocations, got %.0f allocs", allocs)
+	}
+}
+
+// =============================================================================
+// Allocation-Free Path Tests
+// =============================================================================
+
+func TestCalculateUsageInto_MatchesCalculateUsage(t *testing.T) {
+	p, err := NewPricer()
+	if err != nil {
+		t.Fatalf("NewPricer failed: %v", err)
+	}
+
+	usage := TokenUsage{PromptTokens: 50000, CompletionTokens: 10000, CachedTokens: 20000, GroundingQueries: 2}
+	opts := &CalculateOptions{BatchMode: true}
+
+	var dst CostDetails
+	for _, model := range []string{"gemini-2.5-flash", "claude-3-opus", "gpt-4o", "unknown-model"} {
+		want := p.CalculateUsage(model, usage, opts)
+		p.CalculateUsageInto(&dst, model, usage, opts)
+		if dst.TotalCost != want.TotalCost || dst.TierApplied != want.TierApplied || dst.Unknown != want.Unknown {
+			t.Errorf("%s: CalculateUsageInto = %+v, want %+v", model, dst, want)
+		}
+		if len(dst.WarningDetails) != len(want.WarningDetails) || len(dst.Warnings) != len(want.Warnings) {
+			t.Errorf("%s: warnings %v, want %v", model, dst.Warnings, want.Warnings)
+		}
+	}
+}
+
+func TestCalculateUsageInto_ResetsPreviousResult(t *testing.T) {
+	p, err := NewPricer()
+	if err != nil {
+		t.Fatalf("NewPricer failed: %v", err)
+	}
+
+	var dst CostDetails
+	p.CalculateUsageInto(&dst, "gemini-2.5-flash", TokenUsage{PromptTokens: 1000, GroundingQueries: 1}, &CalculateOptions{BatchMode: true})
+	if len(dst.Warnings) != 1 {
+		t.Fatalf("expected batch grounding warning, got %v", dst.Warnings)
+	}
+
+	p.CalculateUsageInto(&dst, "gemini-2.5-flash", TokenUsage{PromptTokens: 1000}, nil)
+	if len(dst.Warnings) != 0 || len(dst.WarningDetails) != 0 || dst.BatchMode {
+		t.Errorf("expected previous warnings and flags cleared, got %+v", dst)
+	}
+}
+
+func TestCalculateUsageInto_AllocationFree(t *testing.T) {
+	p, err := NewPricer()
+	if err != nil {
+		t.Fatalf("NewPricer failed: %v", err)
+	}
+
+	// Batch mode with grounding produces a warning on every call
+	usage := TokenUsage{PromptTokens: 50000, CompletionTokens: 10000, CachedTokens: 20000, GroundingQueries: 10}
+	opts := &CalculateOptions{BatchMode: true}
+
+	var dst CostDetails
+	allocs := testing.AllocsPerRun(100, func() {
+		p.CalculateUsageInto(&dst, "gemini-2.5-flash", usage, opts)
+	})
+	if allocs != 0 {
+		t.Errorf("expected reused dst to avoid allocations, got %.0f allocs", allocs)
+	}
+	if len(dst.WarningDetails) != 1 || dst.WarningDetails[0].Code != WarningBatchGroundingExcluded {
+		t.Errorf("expected batch grounding warning, got %+v", dst.WarningDetails)
+	}
+}
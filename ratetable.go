@@ -0,0 +1,150 @@
+package pricing_db
+
+import (
+	"cmp"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strconv"
+)
+
+// RateTableFormat is an output format for Pricer.ExportRateTable.
+type RateTableFormat string
+
+const (
+	RateTableCSV    RateTableFormat = "csv"    // Header row, then one row per rate
+	RateTableNDJSON RateTableFormat = "ndjson" // One JSON object per line, as loaded by BigQuery and most warehouses
+)
+
+// RateRow is one rate in the flat price table returned by RateTable, for
+// joining usage tables against prices in SQL.
+type RateRow struct {
+	Provider string  `json:"provider"`
+	Model    string  `json:"model"` // Model name as configured by the provider
+	Unit     string  `json:"unit"`  // What the rate prices, e.g. "input_per_million" or "web_search_per_call"
+	Rate     float64 `json:"rate"`  // USD per unit
+	Tier     string  `json:"tier"`  // Pricing tier: "standard", ">200K", ...
+	// TierMode is how the model's tier input rates apply: "cliff" (every input
+	// token at the reached tier's rate) or "marginal" (each rate only on the
+	// input tokens within its band). Empty for models without tiers.
+	TierMode string `json:"tier_mode"`
+	// EffectiveDate is the "YYYY-MM-DD" date the rate took effect, from the
+	// model's price_history; empty when it predates the recorded history.
+	EffectiveDate string `json:"effective_date"`
+	// EffectiveUntil is the date the rate was superseded (exclusive); empty for
+	// current rates.
+	EffectiveUntil string `json:"effective_until"`
+}
+
+// rateTableColumns is the CSV header written by ExportRateTable.
+var rateTableColumns = []string{"provider", "model", "unit", "rate", "tier", "tier_mode", "effective_date", "effective_until"}
+
+// RateTable flattens the token-priced models into one row per rate: input and
+// output rates per tier, the derived cached-input and batch rates, the
+// thinking, audio and image rates a model sets, and its surcharges, for the
+// current prices and every price_history period. Rows are ordered by provider,
+// model, then effective date; provider-namespaced keys are not repeated.
+func (p *Pricer) RateTable() []RateRow {
+	c := p.load()
+	var rows []RateRow
+	for _, provider := range slices.Sorted(maps.Keys(c.providers)) {
+		for _, model := range slices.Sorted(maps.Keys(c.providers[provider].Models)) {
+			r := c.rates[provider+"/"+model]
+			if r == nil {
+				continue
+			}
+			var from string
+			for _, h := range r.history {
+				until := h.until.Format("2006-01-02")
+				rows = appendRateRows(rows, RateRow{Provider: provider, Model: model, EffectiveDate: from, EffectiveUntil: until}, h.rates)
+				from = until
+			}
+			rows = appendRateRows(rows, RateRow{Provider: provider, Model: model, EffectiveDate: from}, r)
+		}
+	}
+	return rows
+}
+
+// appendRateRows appends a row per rate in r, each a copy of base with the
+// unit, rate and tier set.
+func appendRateRows(rows []RateRow, base RateRow, r *modelRates) []RateRow {
+	if len(r.tiers) > 1 {
+		base.TierMode = string(cmp.Or(r.pricing.TierMode, TierModeCliff))
+	}
+	add := func(unit string, rate float64, tier string) {
+		row := base
+		row.Unit, row.Rate, row.Tier = unit, rate, tier
+		rows = append(rows, row)
+	}
+	for _, t := range r.tiers {
+		add("input_per_million", t.inputPerMillion, t.name)
+		add("output_per_million", t.outputPerMillion, t.name)
+		add("cached_input_per_million", t.inputPerMillion*r.cacheMultiplier, t.name)
+		if r.batchMultiplier != 1 {
+			add("batch_input_per_million", t.inputPerMillion*r.batchMultiplier, t.name)
+			add("batch_output_per_million", t.outputPerMillion*r.batchMultiplier, t.name)
+		}
+	}
+	standard := r.tiers[0].name
+	mp := r.pricing
+	for _, rate := range []struct {
+		unit string
+		rate float64
+	}{
+		{"thinking_per_million", mp.ThinkingPerMillion},
+		{"audio_input_per_million", mp.AudioInputPerMillion},
+		{"audio_output_per_million", mp.AudioOutputPerMillion},
+		{"image_input_per_million", mp.ImageInputPerMillion},
+		{"per_input_image", mp.PerInputImage},
+	} {
+		if rate.rate > 0 {
+			add(rate.unit, rate.rate, standard)
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(r.surcharges)) {
+		s := r.surcharges[name]
+		add(name+"_per_"+s.Unit, s.PricePerUnit, standard)
+	}
+	return rows
+}
+
+// ExportRateTable writes RateTable to w as CSV or NDJSON, ready to load into
+// a warehouse table. The parquetio package writes the rows as Parquet, as
+// `pricing-cli rates -format parquet` does, without adding a dependency here.
+func (p *Pricer) ExportRateTable(w io.Writer, format RateTableFormat) error {
+	rows := p.RateTable()
+	switch format {
+	case RateTableCSV:
+		cw := csv.NewWriter(w)
+		cw.Write(rateTableColumns)
+		for _, row := range rows {
+			cw.Write([]string{
+				row.Provider,
+				row.Model,
+				row.Unit,
+				strconv.FormatFloat(row.Rate, 'f', -1, 64),
+				row.Tier,
+				row.TierMode,
+				row.EffectiveDate,
+				row.EffectiveUntil,
+			})
+		}
+		cw.Flush()
+		if err := cw.Error(); err != nil {
+			return fmt.Errorf("export rate table: %w", err)
+		}
+	case RateTableNDJSON:
+		enc := json.NewEncoder(w)
+		for _, row := range rows {
+			if err := enc.Encode(row); err != nil {
+				return fmt.Errorf("export rate table: %w", err)
+			}
+		}
+	default:
+		return fmt.Errorf("export rate table: unknown format %q (want csv or ndjson)", format)
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+package pricing_db
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// =============================================================================
+// Rate Table Tests
+// =============================================================================
+
+// findRate returns the row for model, unit and tier in the given period.
+func findRate(rows []RateRow, model, unit, tier, from string) (RateRow, bool) {
+	for _, row := range rows {
+		if row.Model == model && row.Unit == unit && row.Tier == tier && row.EffectiveDate == from {
+			return row, true
+		}
+	}
+	return RateRow{}, false
+}
+
+func TestRateTable_PriceHistory(t *testing.T) {
+	rows := newTestPricer(t, historyTestConfigs).RateTable()
+
+	tests := []struct {
+		from, until string
+		input       float64
+	}{
+		{"", "2026-01-01", 4.0},
+		{"2026-01-01", "2026-06-01", 3.0},
+		{"2026-06-01", "", 2.0},
+	}
+	for _, tt := range tests {
+		row, ok := findRate(rows, "hist-model", "input_per_million", "standard", tt.from)
+		if !ok {
+			t.Fatalf("no input rate effective from %q in %+v", tt.from, rows)
+		}
+		if row.Provider != "history" || row.EffectiveUntil != tt.until || !floatEquals(row.Rate, tt.input) {
+			t.Errorf("from %q: unexpected row %+v", tt.from, row)
+		}
+	}
+	for _, row := range rows {
+		if strings.Contains(row.Model, "/") {
+			t.Errorf("provider-namespaced key repeated: %+v", row)
+		}
+	}
+}
+
+func TestRateTable_DerivedRates(t *testing.T) {
+	p, err := NewPricerFromFS(overlayFS(), "configs")
+	if err != nil {
+		t.Fatalf("NewPricerFromFS failed: %v", err)
+	}
+	rows := p.RateTable()
+
+	tests := []struct {
+		model, unit string
+		want        float64
+	}{
+		{"large", "output_per_million", 30},
+		{"large", "batch_output_per_million", 15},
+		{"small", "cached_input_per_million", 0.25},
+	}
+	for _, tt := range tests {
+		row, ok := findRate(rows, tt.model, tt.unit, "standard", "")
+		if !ok || !floatEquals(row.Rate, tt.want) {
+			t.Errorf("%s %s: expected %f, got %+v (found %v)", tt.model, tt.unit, tt.want, row, ok)
+		}
+	}
+	if _, ok := findRate(rows, "small", "batch_input_per_million", "standard", ""); ok {
+		t.Error("expected no batch rate for a model without a batch discount")
+	}
+}
+
+func TestRateTable_TierMode(t *testing.T) {
+	p, err := NewPricerFromFS(fstest.MapFS{
+		"configs/test_pricing.json": &fstest.MapFile{Data: []byte(`{
+			"provider": "test",
+			"models": {
+				"flat": {"input_per_million": 1, "output_per_million": 4},
+				"cliff": {
+					"input_per_million": 1, "output_per_million": 4,
+					"tiers": [{"threshold_tokens": 200000, "input_per_million": 2, "output_per_million": 8}]
+				},
+				"marginal": {
+					"input_per_million": 1, "output_per_million": 4, "tier_mode": "marginal",
+					"tiers": [{"threshold_tokens": 200000, "input_per_million": 2, "output_per_million": 8}]
+				}
+			}
+		}`)},
+	}, "configs")
+	if err != nil {
+		t.Fatalf("NewPricerFromFS failed: %v", err)
+	}
+	rows := p.RateTable()
+
+	for model, want := range map[string]string{"flat": "", "cliff": "cliff", "marginal": "marginal"} {
+		row, ok := findRate(rows, model, "input_per_million", "standard", "")
+		if !ok || row.TierMode != want {
+			t.Errorf("%s: expected tier_mode %q, got %+v (found %v)", model, want, row, ok)
+		}
+	}
+}
+
+func TestExportRateTable(t *testing.T) {
+	p := newTestPricer(t, historyTestConfigs)
+	rows := p.RateTable()
+
+	var csvOut bytes.Buffer
+	if err := p.ExportRateTable(&csvOut, RateTableCSV); err != nil {
+		t.Fatalf("ExportRateTable(csv) failed: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(csvOut.String()), "\n")
+	if lines[0] != "provider,model,unit,rate,tier,tier_mode,effective_date,effective_until" || len(lines) != len(rows)+1 {
+		t.Errorf("unexpected CSV:\n%s", csvOut.String())
+	}
+	if lines[1] != "history,hist-model,input_per_million,4,standard,,,2026-01-01" {
+		t.Errorf("unexpected first CSV row %q", lines[1])
+	}
+
+	var ndjson bytes.Buffer
+	if err := p.ExportRateTable(&ndjson, RateTableNDJSON); err != nil {
+		t.Fatalf("ExportRateTable(ndjson) failed: %v", err)
+	}
+	dec := json.NewDecoder(&ndjson)
+	for i := range rows {
+		var row RateRow
+		if err := dec.Decode(&row); err != nil {
+			t.Fatalf("line %d: %v", i+1, err)
+		}
+		if row != rows[i] {
+			t.Errorf("line %d: expected %+v, got %+v", i+1, rows[i], row)
+		}
+	}
+
+	if err := p.ExportRateTable(&ndjson, "parquet"); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+}
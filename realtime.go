@@ -0,0 +1,220 @@
+package pricing_db
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+)
+
+// CalculateRealtimeSession computes the cost of a realtime (audio streaming) session
+// that mixes text and audio tokens, such as OpenAI Realtime or Gemini Live.
+//
+// Token math:
+//   - Text input is billed at input_per_million, audio input at audio_input_per_million
+//   - Text output is billed at output_per_million, audio output at audio_output_per_million
+//   - Cached text and audio input get cache_read_multiplier on their own rate
+//     and are reported together in CachedInputCost
+//
+// When an audio rate is not configured the text rate is used and a warning is added,
+// since audio is typically several times more expensive than text.
+func (p *Pricer) CalculateRealtimeSession(model string, usage RealtimeUsage) CostDetails {
+	c := p.load()
+	return c.calculateRealtimeSessionTraced(context.Background(), model, usage)
+}
+
+// calculateRealtimeSessionTraced is calculateRealtimeSession inside a
+// "pricing.CalculateRealtimeSession" span parented by ctx.
+func (c *catalog) calculateRealtimeSessionTraced(ctx context.Context, model string, usage RealtimeUsage) CostDetails {
+	var span Span
+	if c.tracer != nil {
+		span = c.startSpan(ctx, "pricing.CalculateRealtimeSession", model,
+			slog.Int64("input_tokens", usage.TextInputTokens+usage.AudioInputTokens),
+			slog.Int64("output_tokens", usage.TextOutputTokens+usage.AudioOutputTokens),
+			slog.Int64("audio_input_tokens", usage.AudioInputTokens),
+			slog.Int64("audio_output_tokens", usage.AudioOutputTokens),
+		)
+	}
+	details := c.calculateRealtimeSession(model, usage)
+	endSpan(span, details.TotalCost, details.Unknown)
+	return details
+}
+
+// calculateRealtimeSession implements CalculateRealtimeSession against c.
+func (c *catalog) calculateRealtimeSession(model string, usage RealtimeUsage) CostDetails {
+	pricing, ok := c.models[model]
+	if !ok {
+		pricing, ok = c.findPricingByPrefix(model)
+	}
+	var warnings []Warning
+	if !ok {
+		rates, w, found := c.fallbackRates(model)
+		if !found {
+			details := CostDetails{Unknown: true}
+			c.stamp(&details)
+			return details
+		}
+		pricing = rates.pricing
+		warnings = append(warnings, w)
+	}
+
+	usage = clampRealtimeUsage(usage)
+	if w, ok := deprecationWarning(model, pricing); ok {
+		warnings = append(warnings, w)
+	}
+
+	audioInputRate := pricing.AudioInputPerMillion
+	if audioInputRate == 0 {
+		audioInputRate = pricing.InputPerMillion
+		if usage.AudioInputTokens > 0 {
+			warnings = append(warnings, Warning{Code: WarningAudioRateMissing, Message: fmt.Sprintf("model %q has no audio input rate - billed at text input rate", model)})
+		}
+	}
+	audioOutputRate := pricing.AudioOutputPerMillion
+	if audioOutputRate == 0 {
+		audioOutputRate = pricing.OutputPerMillion
+		if usage.AudioOutputTokens > 0 {
+			warnings = append(warnings, Warning{Code: WarningAudioRateMissing, Message: fmt.Sprintf("model %q has no audio output rate - billed at text output rate", model)})
+		}
+	}
+
+	cacheMultiplier := pricing.CacheReadMultiplier
+	if cacheMultiplier == 0 {
+		cacheMultiplier = c.cacheDefault
+	}
+
+	// Clamp cached counts to their totals (invalid input, but handle gracefully)
+	cachedText := min(usage.CachedTextInputTokens, usage.TextInputTokens)
+	cachedAudio := min(usage.CachedAudioInputTokens, usage.AudioInputTokens)
+
+	standardInputCost := float64(usage.TextInputTokens-cachedText) * pricing.InputPerMillion / TokensPerMillion
+	audioInputCost := float64(usage.AudioInputTokens-cachedAudio) * audioInputRate / TokensPerMillion
+	cachedInputCost := (float64(cachedText)*pricing.InputPerMillion + float64(cachedAudio)*audioInputRate) * cacheMultiplier / TokensPerMillion
+	outputCost := float64(usage.TextOutputTokens) * pricing.OutputPerMillion / TokensPerMillion
+	audioOutputCost := float64(usage.AudioOutputTokens) * audioOutputRate / TokensPerMillion
+
+	rawTotal := standardInputCost + audioInputCost + cachedInputCost + outputCost + audioOutputCost
+
+	details := CostDetails{
+		StandardInputCost: standardInputCost,
+		CachedInputCost:   cachedInputCost,
+		AudioInputCost:    audioInputCost,
+		OutputCost:        outputCost,
+		AudioOutputCost:   audioOutputCost,
+		TierApplied:       "standard",
+		TotalCost:         c.rounding.round(rawTotal),
+		RawTotal:          rawTotal,
+		Unknown:           !ok,
+		Warnings:          warningMessages(warnings),
+		WarningDetails:    warnings,
+	}
+	c.stamp(&details)
+	c.logWarnings(model, warnings)
+	c.applyMarkup(&details, model, usage.TextInputTokens+usage.AudioInputTokens, usage.TextOutputTokens+usage.AudioOutputTokens)
+	return details
+}
+
+// clampRealtimeUsage clamps negative token counts to 0.
+func clampRealtimeUsage(usage RealtimeUsage) RealtimeUsage {
+	usage.TextInputTokens = max(usage.TextInputTokens, 0)
+	usage.AudioInputTokens = max(usage.AudioInputTokens, 0)
+	usage.CachedTextInputTokens = max(usage.CachedTextInputTokens, 0)
+	usage.CachedAudioInputTokens = max(usage.CachedAudioInputTokens, 0)
+	usage.TextOutputTokens = max(usage.TextOutputTokens, 0)
+	usage.AudioOutputTokens = max(usage.AudioOutputTokens, 0)
+	return usage
+}
+
+// ParseGeminiLiveUsage sums the usageMetadata of a Gemini Live API
+// (bidiGenerateContent) session into a RealtimeUsage for CalculateRealtimeSession.
+// data holds the session's server messages as a JSON array or as a sequence of
+// objects (e.g. NDJSON); each message may be a full server message or its bare
+// usageMetadata. Live sessions report usage per turn, so every message's counts
+// are added; messages without usage contribute nothing.
+//
+// Field mapping:
+//   - promptTokenCount: AUDIO entries of promptTokensDetails become
+//     AudioInputTokens, the rest TextInputTokens (all of it when there is no
+//     breakdown). toolUsePromptTokenCount is added to TextInputTokens.
+//   - cachedContentTokenCount: AUDIO entries of cacheTokensDetails become
+//     CachedAudioInputTokens, the rest CachedTextInputTokens.
+//   - responseTokenCount: AUDIO entries of responseTokensDetails become
+//     AudioOutputTokens, the rest TextOutputTokens. thoughtsTokenCount is added
+//     to TextOutputTokens.
+//
+// Image and video tokens are counted as text, since realtime pricing has no
+// separate rate for them. A malformed message returns an error naming its
+// 1-based index.
+func ParseGeminiLiveUsage(data []byte) (RealtimeUsage, error) {
+	var usage RealtimeUsage
+	add := func(index int, raw json.RawMessage) error {
+		raw, err := unwrapUsage(raw, "usageMetadata")
+		if err != nil {
+			return fmt.Errorf("message %d: %w", index, err)
+		}
+		var m GeminiLiveUsageMetadata
+		if err := json.Unmarshal(raw, &m); err != nil {
+			return fmt.Errorf("message %d: %w", index, err)
+		}
+		addGeminiLiveUsage(&usage, m)
+		return nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	index := 0
+	for {
+		var raw json.RawMessage
+		err := dec.Decode(&raw)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return RealtimeUsage{}, fmt.Errorf("message %d: %w", index+1, err)
+		}
+		if raw[0] != '[' {
+			index++
+			if err := add(index, raw); err != nil {
+				return RealtimeUsage{}, err
+			}
+			continue
+		}
+		var messages []json.RawMessage
+		if err := json.Unmarshal(raw, &messages); err != nil {
+			return RealtimeUsage{}, fmt.Errorf("message %d: %w", index+1, err)
+		}
+		for _, msg := range messages {
+			index++
+			if err := add(index, msg); err != nil {
+				return RealtimeUsage{}, err
+			}
+		}
+	}
+	return usage, nil
+}
+
+// addGeminiLiveUsage adds one turn's usageMetadata to usage.
+func addGeminiLiveUsage(usage *RealtimeUsage, m GeminiLiveUsageMetadata) {
+	promptAudio := min(geminiModalityTokens(m.PromptTokensDetails).Audio, m.PromptTokenCount)
+	cachedAudio := min(geminiModalityTokens(m.CacheTokensDetails).Audio, m.CachedContentTokenCount)
+	responseAudio := min(geminiModalityTokens(m.ResponseTokensDetails).Audio, m.ResponseTokenCount)
+
+	usage.AudioInputTokens += promptAudio
+	usage.TextInputTokens += m.PromptTokenCount - promptAudio + m.ToolUsePromptTokenCount
+	usage.CachedAudioInputTokens += cachedAudio
+	usage.CachedTextInputTokens += m.CachedContentTokenCount - cachedAudio
+	usage.AudioOutputTokens += responseAudio
+	usage.TextOutputTokens += m.ResponseTokenCount - responseAudio + m.ThoughtsTokenCount
+}
+
+// CalculateGeminiLiveSession parses a Gemini Live session's server messages with
+// ParseGeminiLiveUsage and prices the totals with CalculateRealtimeSession.
+func (p *Pricer) CalculateGeminiLiveSession(model string, data []byte) (CostDetails, error) {
+	usage, err := ParseGeminiLiveUsage(data)
+	if err != nil {
+		return CostDetails{}, fmt.Errorf("parse gemini live usage: %w", err)
+	}
+	return p.CalculateRealtimeSession(model, usage), nil
+}
@@ -0,0 +1,190 @@
+package pricing_db
+
+import (
+	"strings"
+	"testing"
+)
+
+// =============================================================================
+// Realtime Session Pricing Tests
+// =============================================================================
+
+func TestCalculateRealtimeSession(t *testing.T) {
+	p, err := NewPricer()
+	if err != nil {
+		t.Fatalf("NewPricer failed: %v", err)
+	}
+
+	// gpt-realtime: text $4/$16, audio $32/$64, cache 10%
+	cost := p.CalculateRealtimeSession("gpt-realtime", RealtimeUsage{
+		TextInputTokens:        100_000,
+		AudioInputTokens:       200_000,
+		CachedTextInputTokens:  50_000,
+		CachedAudioInputTokens: 100_000,
+		TextOutputTokens:       10_000,
+		AudioOutputTokens:      50_000,
+	})
+
+	if cost.Unknown {
+		t.Fatal("expected gpt-realtime to be known")
+	}
+	// Text input: 50K * $4/M = $0.20
+	if !floatEquals(cost.StandardInputCost, 0.2) {
+		t.Errorf("expected standard input cost 0.2, got %f", cost.StandardInputCost)
+	}
+	// Audio input: 100K * $32/M = $3.20
+	if !floatEquals(cost.AudioInputCost, 3.2) {
+		t.Errorf("expected audio input cost 3.2, got %f", cost.AudioInputCost)
+	}
+	// Cached: (50K * $4 + 100K * $32) / 1M * 10% = $0.34
+	if !floatEquals(cost.CachedInputCost, 0.34) {
+		t.Errorf("expected cached input cost 0.34, got %f", cost.CachedInputCost)
+	}
+	// Text output: 10K * $16/M = $0.16
+	if !floatEquals(cost.OutputCost, 0.16) {
+		t.Errorf("expected output cost 0.16, got %f", cost.OutputCost)
+	}
+	// Audio output: 50K * $64/M = $3.20
+	if !floatEquals(cost.AudioOutputCost, 3.2) {
+		t.Errorf("expected audio output cost 3.2, got %f", cost.AudioOutputCost)
+	}
+	if !floatEquals(cost.TotalCost, 0.2+3.2+0.34+0.16+3.2) {
+		t.Errorf("expected total cost 7.1, got %f", cost.TotalCost)
+	}
+	if len(cost.Warnings) != 0 {
+		t.Errorf("expected no warnings, got %v", cost.Warnings)
+	}
+}
+
+func TestCalculateRealtimeSession_GeminiLive(t *testing.T) {
+	p, err := NewPricer()
+	if err != nil {
+		t.Fatalf("NewPricer failed: %v", err)
+	}
+
+	cost := p.CalculateRealtimeSession("gemini-live-2.5-flash-preview", RealtimeUsage{
+		AudioInputTokens:  1_000_000,
+		AudioOutputTokens: 1_000_000,
+	})
+	// $3 audio in + $12 audio out
+	if !floatEquals(cost.TotalCost, 15.0) {
+		t.Errorf("expected total cost 15.0, got %f", cost.TotalCost)
+	}
+}
+
+func TestCalculateRealtimeSession_NoAudioRateWarns(t *testing.T) {
+	p, err := NewPricer()
+	if err != nil {
+		t.Fatalf("NewPricer failed: %v", err)
+	}
+
+	cost := p.CalculateRealtimeSession("gpt-4o-mini", RealtimeUsage{AudioInputTokens: 1000, AudioOutputTokens: 1000})
+	if len(cost.Warnings) != 2 {
+		t.Fatalf("expected 2 warnings for missing audio rates, got %v", cost.Warnings)
+	}
+	if !strings.Contains(cost.Warnings[0], "audio input rate") {
+		t.Errorf("unexpected warning: %s", cost.Warnings[0])
+	}
+	// Billed at text rates: 1000 * $0.15/M + 1000 * $0.60/M
+	if !floatEquals(cost.TotalCost, 0.00075) {
+		t.Errorf("expected total cost 0.00075, got %f", cost.TotalCost)
+	}
+}
+
+func TestCalculateRealtimeSession_ClampsInvalidCounts(t *testing.T) {
+	p, err := NewPricer()
+	if err != nil {
+		t.Fatalf("NewPricer failed: %v", err)
+	}
+
+	cost := p.CalculateRealtimeSession("gpt-realtime", RealtimeUsage{
+		TextInputTokens:        -100,
+		AudioInputTokens:       1000,
+		CachedAudioInputTokens: 5000,
+	})
+	if cost.StandardInputCost != 0 || cost.AudioInputCost != 0 {
+		t.Errorf("expected no non-cached input cost, got standard=%f audio=%f", cost.StandardInputCost, cost.AudioInputCost)
+	}
+	// 1000 cached audio * $32/M * 10%
+	if !floatEquals(cost.CachedInputCost, 0.0032) {
+		t.Errorf("expected cached input cost 0.0032, got %f", cost.CachedInputCost)
+	}
+}
+
+func TestCalculateRealtimeSession_UnknownModel(t *testing.T) {
+	cost := CalculateRealtimeCost("unknown-realtime-model", RealtimeUsage{AudioInputTokens: 1000})
+	if !cost.Unknown {
+		t.Error("expected Unknown=true for unknown model")
+	}
+}
+
+// =============================================================================
+// Gemini Live Usage Tests
+// =============================================================================
+
+func TestParseGeminiLiveUsage_AggregatesTurns(t *testing.T) {
+	session := `{"serverContent":{"modelTurn":{"parts":[]}}}
+{"usageMetadata":{"promptTokenCount":1000,"responseTokenCount":500,"totalTokenCount":1500,
+  "promptTokensDetails":[{"modality":"AUDIO","tokenCount":800},{"modality":"TEXT","tokenCount":200}],
+  "responseTokensDetails":[{"modality":"AUDIO","tokenCount":500}]},"serverContent":{"turnComplete":true}}
+{"usageMetadata":{"promptTokenCount":2000,"cachedContentTokenCount":1000,"responseTokenCount":300,
+  "toolUsePromptTokenCount":50,"thoughtsTokenCount":20,
+  "promptTokensDetails":[{"modality":"AUDIO","tokenCount":1500},{"modality":"TEXT","tokenCount":500}],
+  "cacheTokensDetails":[{"modality":"AUDIO","tokenCount":600},{"modality":"TEXT","tokenCount":400}],
+  "responseTokensDetails":[{"modality":"AUDIO","tokenCount":200},{"modality":"TEXT","tokenCount":100}]}}`
+
+	usage, err := ParseGeminiLiveUsage([]byte(session))
+	if err != nil {
+		t.Fatalf("ParseGeminiLiveUsage failed: %v", err)
+	}
+	want := RealtimeUsage{
+		TextInputTokens:        200 + 500 + 50,
+		AudioInputTokens:       800 + 1500,
+		CachedTextInputTokens:  400,
+		CachedAudioInputTokens: 600,
+		TextOutputTokens:       100 + 20,
+		AudioOutputTokens:      500 + 200,
+	}
+	if usage != want {
+		t.Errorf("expected %+v, got %+v", want, usage)
+	}
+}
+
+func TestParseGeminiLiveUsage_ArrayAndBareMetadata(t *testing.T) {
+	usage, err := ParseGeminiLiveUsage([]byte(`[{"promptTokenCount":100,"responseTokenCount":40},{"usageMetadata":{"promptTokenCount":50}}]`))
+	if err != nil {
+		t.Fatalf("ParseGeminiLiveUsage failed: %v", err)
+	}
+	// Without modality details everything counts as text
+	want := RealtimeUsage{TextInputTokens: 150, TextOutputTokens: 40}
+	if usage != want {
+		t.Errorf("expected %+v, got %+v", want, usage)
+	}
+}
+
+func TestParseGeminiLiveUsage_MalformedMessage(t *testing.T) {
+	_, err := ParseGeminiLiveUsage([]byte(`{"usageMetadata":{"promptTokenCount":1}}
+{"usageMetadata":{"promptTokenCount":"many"}}`))
+	if err == nil || !strings.Contains(err.Error(), "message 2") {
+		t.Errorf("expected error naming message 2, got %v", err)
+	}
+}
+
+func TestCalculateGeminiLiveSession(t *testing.T) {
+	session := `{"usageMetadata":{"promptTokenCount":1000000,"responseTokenCount":1000000,
+  "promptTokensDetails":[{"modality":"AUDIO","tokenCount":1000000}],
+  "responseTokensDetails":[{"modality":"AUDIO","tokenCount":1000000}]}}`
+
+	cost, err := CalculateGeminiLiveCost("gemini-live-2.5-flash-preview", []byte(session))
+	if err != nil {
+		t.Fatalf("CalculateGeminiLiveCost failed: %v", err)
+	}
+	// $3 audio in + $12 audio out
+	if !floatEquals(cost.TotalCost, 15.0) {
+		t.Errorf("expected total cost 15.0, got %f", cost.TotalCost)
+	}
+
+	if _, err := CalculateGeminiLiveCost("gemini-live-2.5-flash-preview", []byte(`{"usageMetadata":`)); err == nil {
+		t.Error("expected error for truncated session")
+	}
+}
@@ -0,0 +1,55 @@
+package pricing_db
+
+import (
+	"fmt"
+	"math"
+)
+
+// EstimateThinkingTokens estimates how many thinking tokens a reasoning model will
+// spend for the given effort, based on the model's thinking_ratios heuristic
+// (thinking tokens per visible output token).
+// Returns (0, false) if the model is unknown or has no heuristic for the effort.
+func (p *Pricer) EstimateThinkingTokens(model string, effort ReasoningEffort, outputTokens int64) (int64, bool) {
+	c := p.load()
+
+	pricing, ok := c.models[model]
+	if !ok {
+		pricing, ok = c.findPricingByPrefix(model)
+		if !ok {
+			return 0, false
+		}
+	}
+
+	ratio, ok := pricing.ThinkingRatios[effort]
+	if !ok {
+		return 0, false
+	}
+	if outputTokens <= 0 {
+		return 0, true
+	}
+
+	estimate := math.Ceil(float64(outputTokens) * ratio)
+	if estimate >= math.MaxInt64 {
+		return math.MaxInt64, true
+	}
+	return int64(estimate), true
+}
+
+// EstimateCostWithReasoning computes a pre-flight cost estimate that includes the
+// expected thinking token spend for the given reasoning effort.
+// If the model has no heuristic for the effort, thinking tokens are omitted and a
+// warning is added so callers know the estimate is a lower bound.
+func (p *Pricer) EstimateCostWithReasoning(model string, inputTokens, outputTokens int64, effort ReasoningEffort, opts *CalculateOptions) CostDetails {
+	thinkingTokens, ok := p.EstimateThinkingTokens(model, effort, outputTokens)
+
+	details := p.CalculateUsage(model, TokenUsage{
+		PromptTokens:     inputTokens,
+		CompletionTokens: outputTokens,
+		ThinkingTokens:   thinkingTokens,
+	}, opts)
+
+	if !ok && !details.Unknown {
+		addWarning(&details, WarningReasoningHeuristicMissing, fmt.Sprintf("no %s reasoning heuristic for model %q - thinking tokens not estimated", effort, model))
+	}
+	return details
+}
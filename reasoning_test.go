@@ -0,0 +1,114 @@
+package pricing_db
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// =============================================================================
+// Reasoning Effort Estimation Tests
+// =============================================================================
+
+func TestEstimateThinkingTokens(t *testing.T) {
+	p, err := NewPricer()
+	if err != nil {
+		t.Fatalf("NewPricer failed: %v", err)
+	}
+
+	tests := []struct {
+		model    string
+		effort   ReasoningEffort
+		output   int64
+		expected int64
+		found    bool
+	}{
+		{"o3", ReasoningLow, 1000, 1000, true},
+		{"o3", ReasoningMedium, 1000, 3000, true},
+		{"o3", ReasoningHigh, 1000, 8000, true},
+		{"o3-2025-04-16", ReasoningHigh, 500, 4000, true}, // prefix match
+		{"gemini-2.5-flash", ReasoningMedium, 1001, 1502, true},
+		{"gpt-4o", ReasoningHigh, 1000, 0, false}, // no heuristic
+		{"unknown-model", ReasoningHigh, 1000, 0, false},
+		{"o3", ReasoningEffort("extreme"), 1000, 0, false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.model+"/"+string(tc.effort), func(t *testing.T) {
+			tokens, found := p.EstimateThinkingTokens(tc.model, tc.effort, tc.output)
+			if found != tc.found {
+				t.Errorf("expected found=%v, got %v", tc.found, found)
+			}
+			if tokens != tc.expected {
+				t.Errorf("expected %d thinking tokens, got %d", tc.expected, tokens)
+			}
+		})
+	}
+}
+
+func TestEstimateCostWithReasoning(t *testing.T) {
+	p, err := NewPricer()
+	if err != nil {
+		t.Fatalf("NewPricer failed: %v", err)
+	}
+
+	// o3: $2 input, $8 output per million; high effort = 8x output as thinking
+	details := p.EstimateCostWithReasoning("o3", 10_000, 1_000, ReasoningHigh, nil)
+	if details.Unknown {
+		t.Fatal("expected o3 to be known")
+	}
+	expectedThinking := 8_000 * 8.0 / TokensPerMillion
+	if !floatEquals(details.ThinkingCost, expectedThinking) {
+		t.Errorf("expected thinking cost %f, got %f", expectedThinking, details.ThinkingCost)
+	}
+	if len(details.Warnings) != 0 {
+		t.Errorf("expected no warnings, got %v", details.Warnings)
+	}
+
+	low := p.EstimateCostWithReasoning("o3", 10_000, 1_000, ReasoningLow, nil)
+	if low.TotalCost >= details.TotalCost {
+		t.Errorf("expected low effort ($%f) to cost less than high effort ($%f)", low.TotalCost, details.TotalCost)
+	}
+}
+
+func TestEstimateCostWithReasoning_NoHeuristicWarns(t *testing.T) {
+	details := EstimateCostWithReasoning("gpt-4o", 1000, 1000, ReasoningMedium, nil)
+	if details.ThinkingCost != 0 {
+		t.Errorf("expected no thinking cost, got %f", details.ThinkingCost)
+	}
+	if len(details.Warnings) != 1 || !strings.Contains(details.Warnings[0], "reasoning heuristic") {
+		t.Errorf("expected reasoning heuristic warning, got %v", details.Warnings)
+	}
+}
+
+func TestThinkingRatios_Validation(t *testing.T) {
+	fsys := fstest.MapFS{
+		"configs/test_pricing.json": &fstest.MapFile{Data: []byte(`{
+			"models": {
+				"bad-model": {"input_per_million": 1.0, "output_per_million": 1.0, "thinking_ratios": {"maximum": 2.0}}
+			}
+		}`)},
+	}
+	_, err := NewPricerFromFS(fsys, "configs")
+	if err == nil {
+		t.Fatal("expected error for invalid reasoning effort key")
+	}
+	if !strings.Contains(err.Error(), "thinking_ratios") {
+		t.Errorf("unexpected error message: %v", err)
+	}
+}
+
+func TestThinkingRatios_DeepCopy(t *testing.T) {
+	p, err := NewPricer()
+	if err != nil {
+		t.Fatalf("NewPricer failed: %v", err)
+	}
+
+	meta, _ := p.GetProviderMetadata("openai")
+	meta.Models["o3"].ThinkingRatios[ReasoningHigh] = 999
+
+	tokens, _ := p.EstimateThinkingTokens("o3", ReasoningHigh, 1)
+	if tokens != 8 {
+		t.Errorf("internal thinking ratios were mutated: got %d tokens", tokens)
+	}
+}
@@ -0,0 +1,188 @@
+package pricing_db
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"strings"
+	"sync"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+// =============================================================================
+// Reload / Lock-Free Read Tests
+// =============================================================================
+
+func reloadFS(inputRate string) fstest.MapFS {
+	return fstest.MapFS{
+		"configs/test_pricing.json": &fstest.MapFile{Data: []byte(`{
+			"models": {"m": {"input_per_million": ` + inputRate + `, "output_per_million": 1.0}}
+		}`)},
+	}
+}
+
+func TestReload_SwapsCatalog(t *testing.T) {
+	p, err := NewPricerFromFS(reloadFS("1.0"), "configs")
+	if err != nil {
+		t.Fatalf("NewPricerFromFS failed: %v", err)
+	}
+
+	if err := p.Reload(reloadFS("2.0"), "configs"); err != nil {
+		t.Fatalf("Reload failed: %v", err)
+	}
+	if cost := p.Calculate("m", 1_000_000, 0); !floatEquals(cost.InputCost, 2.0) {
+		t.Errorf("expected reloaded input cost $2.00, got $%f", cost.InputCost)
+	}
+}
+
+func TestReload_ErrorKeepsCatalog(t *testing.T) {
+	p, err := NewPricerFromFS(reloadFS("1.0"), "configs")
+	if err != nil {
+		t.Fatalf("NewPricerFromFS failed: %v", err)
+	}
+
+	if err := p.Reload(reloadFS("-5.0"), "configs"); err == nil {
+		t.Fatal("expected Reload to reject invalid config")
+	}
+	if cost := p.Calculate("m", 1_000_000, 0); !floatEquals(cost.InputCost, 1.0) {
+		t.Errorf("expected previous catalog to remain ($1.00), got $%f", cost.InputCost)
+	}
+}
+
+func TestReload_ConcurrentReaders(t *testing.T) {
+	p, err := NewPricerFromFS(reloadFS("1.0"), "configs")
+	if err != nil {
+		t.Fatalf("NewPricerFromFS failed: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 500; j++ {
+				cost := p.Calculate("m", 1_000_000, 0)
+				// Every call sees one complete catalog, never a mix
+				if !floatEquals(cost.InputCost, 1.0) && !floatEquals(cost.InputCost, 2.0) {
+					t.Errorf("unexpected input cost $%f", cost.InputCost)
+					return
+				}
+			}
+		}()
+	}
+	for i := 0; i < 20; i++ {
+		rate := "1.0"
+		if i%2 == 0 {
+			rate = "2.0"
+		}
+		if err := p.Reload(reloadFS(rate), "configs"); err != nil {
+			t.Errorf("Reload failed: %v", err)
+		}
+	}
+	wg.Wait()
+}
+
+// cancelOnOpenFS cancels a context the first time a pricing file is opened,
+// simulating a deadline expiring partway through a slow load.
+type cancelOnOpenFS struct {
+	fs.FS
+	cancel context.CancelFunc
+}
+
+func (c cancelOnOpenFS) Open(name string) (fs.File, error) {
+	if strings.HasSuffix(name, ".json") {
+		c.cancel()
+	}
+	return c.FS.Open(name)
+}
+
+func TestLoadContext_CanceledMidLoad(t *testing.T) {
+	fsys := reloadFS("1.0")
+	fsys["configs/zzz_pricing.json"] = &fstest.MapFile{Data: []byte(`{
+		"models": {"z": {"input_per_million": 1.0, "output_per_million": 1.0}}
+	}`)}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	_, err := LoadContext(ctx, cancelOnOpenFS{FS: fsys, cancel: cancel}, "configs")
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got: %v", err)
+	}
+}
+
+// blockingFS blocks opening pricing files until release is closed, simulating
+// a network filesystem that stops responding mid-read.
+type blockingFS struct {
+	fs.FS
+	release chan struct{}
+}
+
+func (b blockingFS) Open(name string) (fs.File, error) {
+	if strings.HasSuffix(name, ".json") {
+		<-b.release
+	}
+	return b.FS.Open(name)
+}
+
+func TestLoadContext_BlockedRead(t *testing.T) {
+	fsys := blockingFS{FS: reloadFS("1.0"), release: make(chan struct{})}
+	defer close(fsys.release)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err := LoadContext(ctx, fsys, "configs")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected context.DeadlineExceeded, got: %v", err)
+	}
+}
+
+func TestReloadContext_CanceledKeepsCatalog(t *testing.T) {
+	p, err := NewPricerFromFS(reloadFS("1.0"), "configs")
+	if err != nil {
+		t.Fatalf("NewPricerFromFS failed: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 0)
+	defer cancel()
+	if err := p.ReloadContext(ctx, reloadFS("2.0"), "configs"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected context.DeadlineExceeded, got: %v", err)
+	}
+	if cost := p.Calculate("m", 1_000_000, 0); !floatEquals(cost.InputCost, 1.0) {
+		t.Errorf("expected original input cost $1.00 after canceled reload, got $%f", cost.InputCost)
+	}
+
+	if err := p.ReloadContext(context.Background(), reloadFS("2.0"), "configs"); err != nil {
+		t.Fatalf("ReloadContext failed: %v", err)
+	}
+	if cost := p.Calculate("m", 1_000_000, 0); !floatEquals(cost.InputCost, 2.0) {
+		t.Errorf("expected reloaded input cost $2.00, got $%f", cost.InputCost)
+	}
+}
+
+func TestZeroPricer(t *testing.T) {
+	var p Pricer
+	if cost := p.Calculate("gpt-4o", 1000, 1000); !cost.Unknown {
+		t.Errorf("expected an unknown model from a zero Pricer, got %+v", cost)
+	}
+	if cost := p.CalculateUsage("gpt-4o", TokenUsage{PromptTokens: 1000}, nil); !cost.Unknown || cost.TotalCost != 0 {
+		t.Errorf("expected an unknown model from a zero Pricer, got %+v", cost)
+	}
+	if _, ok := p.GetPricing("gpt-4o"); ok {
+		t.Error("expected no pricing from a zero Pricer")
+	}
+	if _, ok := p.CalculateImage("dall-e-3", 1); ok {
+		t.Error("expected no image pricing from a zero Pricer")
+	}
+	if p.ModelCount() != 0 || len(p.ListProviders()) != 0 || p.Version() == "" {
+		t.Errorf("unexpected zero Pricer catalog: %d models, version %q", p.ModelCount(), p.Version())
+	}
+
+	if err := p.Reload(reloadFS("1.0"), "configs"); err != nil {
+		t.Fatalf("Reload failed: %v", err)
+	}
+	if cost := p.Calculate("m", 1_000_000, 0); cost.Unknown || cost.TotalCost == 0 {
+		t.Errorf("expected Reload to load pricing into a zero Pricer, got %+v", cost)
+	}
+}
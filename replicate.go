@@ -0,0 +1,43 @@
+package pricing_db
+
+// replicateProvider is the provider whose instance types and model_hardware
+// CalculateReplicateRun reads.
+const replicateProvider = "replicate"
+
+// secondsPerHour converts per-second instance rates to hourly ones.
+const secondsPerHour = 3600.0
+
+// hourlyRate returns the instance's price per hour, from PerSecondUSD when set.
+func (i InstancePricing) hourlyRate() float64 {
+	if i.PerSecondUSD > 0 {
+		return i.PerSecondUSD * secondsPerHour
+	}
+	return i.HourlyUSD
+}
+
+// CalculateReplicateRun computes the cost of a Replicate prediction billed by
+// the second of hardware time, as most community models are. hardware is a
+// Replicate hardware SKU such as "gpu-l40s" or "gpu-a100-large"; when empty,
+// the model's default from the replicate config's model_hardware is used.
+// seconds is the prediction's predict_time metric and may be fractional.
+//
+// Returns false when the hardware is unknown or not billed per second, or
+// when hardware is empty and the model has no default.
+func (p *Pricer) CalculateReplicateRun(model string, seconds float64, hardware string) (float64, bool) {
+	c := p.load()
+
+	if hardware == "" {
+		hardware = c.providers[replicateProvider].ModelHardware[model]
+		if hardware == "" {
+			return 0, false
+		}
+	}
+	pricing, ok := c.instances[replicateProvider+"/"+hardware]
+	if !ok || pricing.PerSecondUSD == 0 {
+		return 0, false
+	}
+	if seconds <= 0 {
+		return 0, true
+	}
+	return c.rounding.round(seconds * pricing.PerSecondUSD), true
+}
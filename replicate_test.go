@@ -0,0 +1,76 @@
+package pricing_db
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// =============================================================================
+// Replicate Per-Second Hardware Pricing Tests
+// =============================================================================
+
+func TestCalculateReplicateRun(t *testing.T) {
+	p, err := NewPricer()
+	if err != nil {
+		t.Fatalf("NewPricer failed: %v", err)
+	}
+
+	tests := []struct {
+		model     string
+		seconds   float64
+		hardware  string
+		wantCost  float64
+		wantFound bool
+	}{
+		{"someone/custom-model", 100, "gpu-a100-large", 0.14, true},
+		{"someone/custom-model", 2.5, "gpu-h100-8x", 0.0305, true},
+		{"stability-ai/sdxl", 10, "", 0.00975, true}, // model's default hardware
+		{"stability-ai/sdxl", 10, "gpu-t4", 0.00225, true},
+		{"stability-ai/sdxl", 0, "", 0, true},
+		{"someone/custom-model", 10, "", 0, false}, // no default hardware
+		{"someone/custom-model", 10, "gpu-b200", 0, false},
+		{"someone/custom-model", 10, "nvidia-a10g-x1", 0, false}, // hourly HF endpoint, not Replicate
+	}
+	for _, tt := range tests {
+		cost, found := p.CalculateReplicateRun(tt.model, tt.seconds, tt.hardware)
+		if found != tt.wantFound || !floatEquals(cost, tt.wantCost) {
+			t.Errorf("CalculateReplicateRun(%q, %v, %q) = $%f, %v; want $%f, %v", tt.model, tt.seconds, tt.hardware, cost, found, tt.wantCost, tt.wantFound)
+		}
+	}
+
+	if got := CalculateReplicateRunCost("stability-ai/sdxl", 100, ""); !floatEquals(got, 0.0975) {
+		t.Errorf("CalculateReplicateRunCost: expected $0.0975, got $%f", got)
+	}
+	// Per-second hardware also prices by the hour
+	if cost, _ := p.CalculateEndpointHours("replicate/gpu-l40s", 1); !floatEquals(cost, 3.51) {
+		t.Errorf("expected $3.51 for an hour of gpu-l40s, got $%f", cost)
+	}
+	// Replicate SKUs are not registered under their bare, generic names
+	if _, found := p.GetInstancePricing("cpu"); found {
+		t.Error("expected bare \"cpu\" not to resolve to Replicate hardware")
+	}
+	if _, found := p.CalculateEndpointHours("gpu-l40s", 1); found {
+		t.Error("expected bare \"gpu-l40s\" not to resolve to Replicate hardware")
+	}
+}
+
+func TestNewPricerFromFS_InvalidModelHardware(t *testing.T) {
+	tests := []struct {
+		body        string
+		errContains string
+	}{
+		{`"instance_types": {"gpu": {"hourly_usd": 1, "per_second_usd": 0.001}}`, "sets both"},
+		{`"instance_types": {"gpu": {"per_second_usd": 0.001}}, "model_hardware": {"m": "gpu-x"}`, "unknown instance type"},
+		{`"instance_types": {"gpu": {"hourly_usd": 1}}, "model_hardware": {"m": "gpu"}`, "without per_second_usd"},
+	}
+	for _, tt := range tests {
+		fsys := fstest.MapFS{
+			"configs/replicate_pricing.json": &fstest.MapFile{Data: []byte(`{` + tt.body + `}`)},
+		}
+		_, err := NewPricerFromFS(fsys, "configs")
+		if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+			t.Errorf("expected error containing %q for %s, got %v", tt.errContains, tt.body, err)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package pricing_db
+
+import (
+	"cmp"
+	"math"
+	"slices"
+	"time"
+)
+
+// RepricedRecord is one UsageRecord's cost under the original and new catalogs.
+type RepricedRecord struct {
+	Original CostDetails
+	Repriced CostDetails
+}
+
+// RepriceSummary totals one model's records in a RepriceReport.
+type RepriceSummary struct {
+	Model        string
+	Requests     int
+	OriginalCost float64 // Sum of original costs (rounded once, like RawTotal sums)
+	RepricedCost float64 // Sum of repriced costs
+	Delta        float64 // RepricedCost - OriginalCost
+	Unknown      int     // Records the new catalog could not price (counted as 0)
+}
+
+// RepriceReport compares recorded usage under two pricing catalogs.
+type RepriceReport struct {
+	FromVersion   string           // Version of the original catalog
+	ToVersion     string           // Version of the new catalog
+	Records       []RepricedRecord // In the order of the input records
+	Models        []RepriceSummary // Largest absolute delta first
+	OriginalTotal float64
+	RepricedTotal float64
+	Delta         float64 // RepricedTotal - OriginalTotal
+}
+
+// Reprice recomputes the cost of previously recorded usage under another price
+// sheet, answering "what would last month cost under the new prices?".
+//
+// Each record's original cost is priced by p as CalculateBatchUsage would, so
+// records with At set use p's price history. The repriced cost uses to's current
+// rates, ignoring At. A nil to reprices at p's current rates, showing the effect
+// of price changes recorded in p's history.
+func (p *Pricer) Reprice(records []UsageRecord, to *Pricer) RepriceReport {
+	if to == nil {
+		to = p
+	}
+	from, target := p.load(), to.load()
+
+	original := make([]CostDetails, len(records))
+	from.calculateBatch(records, original)
+	current := make([]UsageRecord, len(records))
+	for i, rec := range records {
+		rec.At = time.Time{}
+		current[i] = rec
+	}
+	repriced := make([]CostDetails, len(records))
+	target.calculateBatch(current, repriced)
+
+	report := RepriceReport{
+		FromVersion: from.version,
+		ToVersion:   target.version,
+		Records:     make([]RepricedRecord, len(records)),
+	}
+	for i := range records {
+		report.Records[i] = RepricedRecord{Original: original[i], Repriced: repriced[i]}
+	}
+	byModel, originalRaw, repricedRaw := sumByModel(records, original, repriced)
+	report.Models = make([]RepriceSummary, 0, len(byModel))
+	for model, sums := range byModel {
+		report.Models = append(report.Models, RepriceSummary{
+			Model:        model,
+			Requests:     sums.requests,
+			OriginalCost: from.rounding.round(sums.a),
+			RepricedCost: target.rounding.round(sums.b),
+			Delta:        target.rounding.round(sums.b - sums.a),
+			Unknown:      sums.unknownB,
+		})
+	}
+	slices.SortFunc(report.Models, func(a, b RepriceSummary) int {
+		if c := cmp.Compare(math.Abs(b.Delta), math.Abs(a.Delta)); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Model, b.Model)
+	})
+	report.OriginalTotal = from.rounding.round(originalRaw)
+	report.RepricedTotal = target.rounding.round(repricedRaw)
+	report.Delta = target.rounding.round(repricedRaw - originalRaw)
+	return report
+}
+
+// costSums accumulates one model's costs under two calculations being compared.
+type costSums struct {
+	requests int
+	a, b     float64 // Unrounded totals (RawTotal sums)
+	unknownB int     // Results in b priced as Unknown
+}
+
+// sumByModel sums parallel results a and b of records by model, returning the
+// per-model sums and the unrounded grand totals.
+func sumByModel(records []UsageRecord, a, b []CostDetails) (map[string]*costSums, float64, float64) {
+	byModel := make(map[string]*costSums)
+	var totalA, totalB float64
+	for i, rec := range records {
+		sums := byModel[rec.Model]
+		if sums == nil {
+			sums = &costSums{}
+			byModel[rec.Model] = sums
+		}
+		sums.requests++
+		if b[i].Unknown {
+			sums.unknownB++
+		}
+		sums.a += a[i].RawTotal
+		sums.b += b[i].RawTotal
+		totalA += a[i].RawTotal
+		totalB += b[i].RawTotal
+	}
+	return byModel, totalA, totalB
+}
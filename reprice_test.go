@@ -0,0 +1,80 @@
+package pricing_db
+
+import (
+	"testing"
+	"time"
+)
+
+// =============================================================================
+// Reprice Tests
+// =============================================================================
+
+func TestReprice_NewPriceSheet(t *testing.T) {
+	from, err := NewPricerFromFS(versionTestFS("2.0"), "configs")
+	if err != nil {
+		t.Fatalf("NewPricerFromFS failed: %v", err)
+	}
+	to, err := NewPricerFromFS(versionTestFS("4.0"), "configs")
+	if err != nil {
+		t.Fatalf("NewPricerFromFS failed: %v", err)
+	}
+
+	records := []UsageRecord{
+		{Model: "acme-1", Usage: TokenUsage{PromptTokens: 1_000_000, CompletionTokens: 1_000_000}},
+		{Model: "acme-1", Usage: TokenUsage{CompletionTokens: 500_000}},
+		{Model: "no-such-model", Usage: TokenUsage{PromptTokens: 1000}},
+	}
+	report := from.Reprice(records, to)
+
+	if report.FromVersion != from.Version() || report.ToVersion != to.Version() {
+		t.Errorf("unexpected versions %q -> %q", report.FromVersion, report.ToVersion)
+	}
+	if len(report.Records) != 3 {
+		t.Fatalf("expected 3 records, got %d", len(report.Records))
+	}
+	if r := report.Records[0]; r.Original.TotalCost != 3.0 || r.Repriced.TotalCost != 5.0 {
+		t.Errorf("expected $3 -> $5, got $%f -> $%f", r.Original.TotalCost, r.Repriced.TotalCost)
+	}
+
+	if len(report.Models) != 2 {
+		t.Fatalf("expected 2 models, got %+v", report.Models)
+	}
+	acme := report.Models[0]
+	if acme.Model != "acme-1" || acme.Requests != 2 || acme.OriginalCost != 4.0 || acme.RepricedCost != 7.0 || acme.Delta != 3.0 {
+		t.Errorf("unexpected acme-1 summary %+v", acme)
+	}
+	if unknown := report.Models[1]; unknown.Model != "no-such-model" || unknown.Unknown != 1 || unknown.Delta != 0 {
+		t.Errorf("unexpected unknown-model summary %+v", unknown)
+	}
+	if report.OriginalTotal != 4.0 || report.RepricedTotal != 7.0 || report.Delta != 3.0 {
+		t.Errorf("unexpected totals %f -> %f (%f)", report.OriginalTotal, report.RepricedTotal, report.Delta)
+	}
+}
+
+func TestReprice_HistoryToCurrentRates(t *testing.T) {
+	p := newTestPricer(t, historyTestConfigs)
+	records := []UsageRecord{
+		{Model: "hist-model", Usage: TokenUsage{PromptTokens: 1_000_000}, At: time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)},
+		{Model: "hist-model", Usage: TokenUsage{PromptTokens: 1_000_000}, At: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	// A nil target reprices at the same catalog's current rates
+	report := p.Reprice(records, nil)
+	if report.OriginalTotal != 7.0 || report.RepricedTotal != 4.0 || report.Delta != -3.0 {
+		t.Errorf("expected $7 -> $4, got %f -> %f (%f)", report.OriginalTotal, report.RepricedTotal, report.Delta)
+	}
+	if report.FromVersion != report.ToVersion {
+		t.Errorf("expected one catalog version, got %q and %q", report.FromVersion, report.ToVersion)
+	}
+	// Input records are not modified
+	if records[0].At.IsZero() {
+		t.Error("Reprice modified the input records")
+	}
+}
+
+func TestReprice_Empty(t *testing.T) {
+	report := defaultPricer().Reprice(nil, nil)
+	if len(report.Records) != 0 || len(report.Models) != 0 || report.Delta != 0 {
+		t.Errorf("expected an empty report, got %+v", report)
+	}
+}
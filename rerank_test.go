@@ -0,0 +1,94 @@
+package pricing_db
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// =============================================================================
+// Rerank / Cohere Pricing Tests
+// =============================================================================
+
+func TestCalculateRerank(t *testing.T) {
+	p, err := NewPricer()
+	if err != nil {
+		t.Fatalf("NewPricer failed: %v", err)
+	}
+
+	tests := []struct {
+		model     string
+		searches  int
+		wantCost  float64
+		wantFound bool
+	}{
+		{"rerank-v3.5", 1000, 2.0, true},
+		{"rerank-v3.5", 1, 0.002, true},
+		{"cohere/rerank-english-v3.0", 500, 1.0, true},
+		{"rerank-multilingual-v3.0-2024", 1000, 2.0, true}, // prefix match
+		{"rerank-v3.5", 0, 0, true},
+		{"rerank-v3.5", -5, 0, true},
+		{"rerank-unknown", 1000, 0, false},
+	}
+	for _, tt := range tests {
+		cost, found := p.CalculateRerank(tt.model, tt.searches)
+		if found != tt.wantFound || !floatEquals(cost, tt.wantCost) {
+			t.Errorf("CalculateRerank(%q, %d) = $%f, %v; want $%f, %v", tt.model, tt.searches, cost, found, tt.wantCost, tt.wantFound)
+		}
+	}
+
+	if got := CalculateRerankCost("rerank-v3.5", 2000); !floatEquals(got, 4.0) {
+		t.Errorf("CalculateRerankCost = $%f, want $4.00", got)
+	}
+}
+
+func TestCohereEmbedAndCommandModels(t *testing.T) {
+	p, err := NewPricer()
+	if err != nil {
+		t.Fatalf("NewPricer failed: %v", err)
+	}
+
+	// Embed models bill input only
+	cost := p.Calculate("embed-v4.0", 1_000_000, 0)
+	if cost.Unknown || !floatEquals(cost.TotalCost, 0.12) {
+		t.Errorf("expected embed-v4.0 $0.12 per 1M tokens, got %+v", cost)
+	}
+
+	// command-r7b must not fall back to the "command" prefix
+	pricing, ok := p.GetPricing("command-r7b-12-2024")
+	if !ok || pricing.InputPerMillion != 0.0375 {
+		t.Errorf("expected command-r7b-12-2024 at $0.0375 input, got %+v", pricing)
+	}
+}
+
+func TestRerankPricing_Validation(t *testing.T) {
+	fsys := fstest.MapFS{
+		"configs/acme_pricing.json": &fstest.MapFile{Data: []byte(`{
+			"rerank_models": {"acme-rerank": {"per_thousand_searches": -1.0}}
+		}`)},
+	}
+	_, err := NewPricerFromFS(fsys, "configs")
+	if err == nil || !strings.Contains(err.Error(), "rerank model") {
+		t.Errorf("expected rerank validation error, got %v", err)
+	}
+}
+
+func TestRerankPricing_SnapshotRoundTrip(t *testing.T) {
+	p, err := NewPricer()
+	if err != nil {
+		t.Fatalf("NewPricer failed: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := p.Export(&buf); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	loaded, err := NewPricerFromSnapshot(&buf)
+	if err != nil {
+		t.Fatalf("NewPricerFromSnapshot failed: %v", err)
+	}
+	if cost, ok := loaded.CalculateRerank("rerank-v3.5", 1000); !ok || !floatEquals(cost, 2.0) {
+		t.Errorf("expected rerank pricing to survive round trip, got $%f (found=%v)", cost, ok)
+	}
+}
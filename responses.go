@@ -0,0 +1,126 @@
+package pricing_db
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// CalculateResponse prices a provider's full JSON response: usage is mapped as in
+// UsageFromJSON and the model is read from the response's top-level "model"
+// field. If modelOverride is non-empty it is used instead, which is required for
+// providers whose responses omit the model (e.g., Cohere).
+//
+// Returns an error for malformed JSON or an unsupported provider, and
+// CostDetails{Unknown: true} for a missing or unknown model.
+func (p *Pricer) CalculateResponse(provider string, jsonData []byte, modelOverride string, opts *CalculateOptions) (CostDetails, error) {
+	usage, err := UsageFromJSON(provider, jsonData)
+	if err != nil {
+		return CostDetails{}, err
+	}
+
+	model := modelOverride
+	if model == "" {
+		var resp struct {
+			Model string `json:"model"`
+		}
+		if err := json.Unmarshal(jsonData, &resp); err != nil {
+			return CostDetails{}, fmt.Errorf("parse %s response: %w", provider, err)
+		}
+		model = resp.Model
+	}
+	return p.CalculateUsage(model, usage, opts), nil
+}
+
+// DetectResponseProvider guesses which provider's response format jsonData is in,
+// from fields distinctive to each, and returns the usage format name to pass to
+// CalculateResponse or UsageFromJSON: "gemini", "openai" (including OpenAI-compatible
+// providers), "openai-responses", "anthropic", "bedrock", or "cohere". Returns false if jsonData is not
+// a JSON object or matches no known format.
+func DetectResponseProvider(jsonData []byte) (string, bool) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(jsonData, &fields); err != nil {
+		return "", false
+	}
+	if _, ok := fields["usageMetadata"]; ok {
+		return "gemini", true
+	}
+	if _, ok := fields["candidates"]; ok {
+		return "gemini", true
+	}
+	if _, ok := fields["meta"]; ok {
+		if hasAnyField(fields["meta"], "billed_units", "tokens") {
+			return "cohere", true
+		}
+	}
+
+	usage := fields["usage"]
+	switch {
+	case string(fields["object"]) == `"response"`, hasAnyField(usage, "input_tokens_details", "output_tokens_details"):
+		return "openai-responses", true
+	case hasAnyField(usage, "billed_units"):
+		return "cohere", true
+	case hasAnyField(usage, "prompt_tokens", "completion_tokens"):
+		return "openai", true
+	case hasAnyField(usage, "input_tokens", "output_tokens"):
+		return "anthropic", true
+	case hasAnyField(usage, "inputTokens", "outputTokens"):
+		return "bedrock", true
+	}
+	return "", false
+}
+
+// hasAnyField reports whether raw is a JSON object with at least one of keys.
+func hasAnyField(raw json.RawMessage, keys ...string) bool {
+	var fields map[string]json.RawMessage
+	if len(raw) == 0 || json.Unmarshal(raw, &fields) != nil {
+		return false
+	}
+	for _, key := range keys {
+		if _, ok := fields[key]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// cohereUsage is Cohere's usage object: "usage" in Chat v2, "meta" in v1.
+type cohereUsage struct {
+	BilledUnits *cohereTokens `json:"billed_units"`
+	Tokens      *cohereTokens `json:"tokens"`
+}
+
+// cohereTokens counts are JSON numbers that may be encoded as floats.
+type cohereTokens struct {
+	InputTokens  float64 `json:"input_tokens"`
+	OutputTokens float64 `json:"output_tokens"`
+}
+
+func parseCohereUsage(raw []byte) (TokenUsage, error) {
+	var resp struct {
+		Usage *cohereUsage `json:"usage"`
+		Meta  *cohereUsage `json:"meta"`
+		cohereUsage
+	}
+	if err := json.Unmarshal(raw, &resp); err != nil {
+		return TokenUsage{}, err
+	}
+
+	u := resp.cohereUsage
+	if resp.Usage != nil {
+		u = *resp.Usage
+	} else if resp.Meta != nil {
+		u = *resp.Meta
+	}
+	// Billed units are what Cohere invoices; raw token counts include the chat template
+	t := u.BilledUnits
+	if t == nil {
+		t = u.Tokens
+	}
+	if t == nil {
+		return TokenUsage{}, nil
+	}
+	return TokenUsage{
+		PromptTokens:     int64(t.InputTokens),
+		CompletionTokens: int64(t.OutputTokens),
+	}, nil
+}
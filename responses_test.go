@@ -0,0 +1,192 @@
+package pricing_db
+
+import "testing"
+
+// =============================================================================
+// Vendor Response Parser Tests
+// =============================================================================
+
+func TestParseMistralResponse(t *testing.T) {
+	resp := []byte(`{
+		"id": "cmpl-1",
+		"object": "chat.completion",
+		"model": "mistral-large-latest",
+		"choices": [{"index": 0, "message": {"role": "assistant", "content": "hi"}, "finish_reason": "stop"}],
+		"usage": {"prompt_tokens": 1000, "completion_tokens": 500, "total_tokens": 1500}
+	}`)
+	cost, err := ParseMistralResponse(resp)
+	if err != nil {
+		t.Fatalf("ParseMistralResponse failed: %v", err)
+	}
+	if cost.Unknown || !floatEquals(cost.TotalCost, 0.005) {
+		t.Errorf("expected $0.005, got $%f (unknown=%v)", cost.TotalCost, cost.Unknown)
+	}
+}
+
+func TestParseCohereResponse(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+	}{
+		{"chat v2", `{
+			"id": "c-1",
+			"finish_reason": "COMPLETE",
+			"message": {"role": "assistant", "content": [{"type": "text", "text": "hi"}]},
+			"usage": {"billed_units": {"input_tokens": 1000, "output_tokens": 200}, "tokens": {"input_tokens": 1070, "output_tokens": 200}}
+		}`},
+		{"v1 meta", `{
+			"text": "hi",
+			"meta": {"billed_units": {"input_tokens": 1000.0, "output_tokens": 200.0}}
+		}`},
+		{"tokens only", `{"usage": {"tokens": {"input_tokens": 1000, "output_tokens": 200}}}`},
+	}
+	for _, tt := range tests {
+		cost, err := ParseCohereResponse([]byte(tt.raw), "command-r-plus")
+		if err != nil {
+			t.Fatalf("%s: ParseCohereResponse failed: %v", tt.name, err)
+		}
+		if !floatEquals(cost.TotalCost, 0.0045) {
+			t.Errorf("%s: expected $0.0045, got $%f", tt.name, cost.TotalCost)
+		}
+	}
+
+	// Without a model the cost is unknown, not an error
+	cost, err := ParseCohereResponse([]byte(tests[0].raw), "")
+	if err != nil || !cost.Unknown {
+		t.Errorf("expected Unknown without a model, got %+v, %v", cost, err)
+	}
+}
+
+func TestParseAI21Response(t *testing.T) {
+	resp := []byte(`{
+		"id": "chat-1",
+		"model": "jamba-mini",
+		"choices": [{"index": 0, "message": {"role": "assistant", "content": "hi"}}],
+		"usage": {"prompt_tokens": 10000, "completion_tokens": 5000, "total_tokens": 15000}
+	}`)
+	cost, err := ParseAI21Response(resp)
+	if err != nil {
+		t.Fatalf("ParseAI21Response failed: %v", err)
+	}
+	if !floatEquals(cost.TotalCost, 0.004) {
+		t.Errorf("expected $0.004, got $%f", cost.TotalCost)
+	}
+}
+
+func TestParseOpenAIResponsesAPI(t *testing.T) {
+	resp := []byte(`{
+		"id": "resp_1",
+		"object": "response",
+		"model": "gpt-4o",
+		"output": [
+			{"type": "web_search_call", "id": "ws_1", "status": "completed"},
+			{"type": "web_search_call", "id": "ws_2", "status": "completed"},
+			{"type": "file_search_call", "id": "fs_1", "status": "completed"},
+			{"type": "message", "role": "assistant", "content": [{"type": "output_text", "text": "hi"}]}
+		],
+		"usage": {"input_tokens": 100000, "input_tokens_details": {"cached_tokens": 40000},
+			"output_tokens": 10000, "output_tokens_details": {"reasoning_tokens": 0}, "total_tokens": 110000}
+	}`)
+	cost, err := ParseOpenAIResponsesAPI(resp)
+	if err != nil {
+		t.Fatalf("ParseOpenAIResponsesAPI failed: %v", err)
+	}
+	// gpt-4o: 60K input at $2.50/M, 40K cached at half price, 10K output at $10/M;
+	// two web searches at $0.01 and one file search at $0.0025
+	if !floatEquals(cost.StandardInputCost, 0.15) || !floatEquals(cost.CachedInputCost, 0.05) ||
+		!floatEquals(cost.OutputCost, 0.1) || !floatEquals(cost.SurchargeCost, 0.0225) {
+		t.Errorf("unexpected cost %+v", cost)
+	}
+
+	// Detected and priced by CalculateResponse like the other formats
+	if provider, _ := DetectResponseProvider(resp); provider != "openai-responses" {
+		t.Errorf("expected openai-responses, got %q", provider)
+	}
+}
+
+func TestParseAnthropicResponse(t *testing.T) {
+	resp := []byte(`{
+		"id": "msg_1",
+		"type": "message",
+		"model": "claude-sonnet-4-5",
+		"content": [{"type": "text", "text": "hi"}],
+		"usage": {"input_tokens": 100000, "output_tokens": 10000,
+			"server_tool_use": {"web_search_requests": 3, "web_fetch_requests": 2}}
+	}`)
+	cost, err := ParseAnthropicResponse(resp)
+	if err != nil {
+		t.Fatalf("ParseAnthropicResponse failed: %v", err)
+	}
+	// $0.30 input + $0.15 output + 3 searches at $0.01; fetches are free
+	if !floatEquals(cost.SurchargeCost, 0.03) || !floatEquals(cost.TotalCost, 0.48) {
+		t.Errorf("expected $0.03 of search fees in $0.48, got %+v", cost)
+	}
+	if len(cost.Warnings) != 0 {
+		t.Errorf("expected no warnings, got %v", cost.Warnings)
+	}
+
+	// Web search is available in batch mode
+	batch := defaultPricer().CalculateAnthropicUsage("claude-sonnet-4-5", AnthropicUsage{
+		InputTokens:   100000,
+		ServerToolUse: &AnthropicServerToolUse{WebSearchRequests: 1},
+	}, &CalculateOptions{BatchMode: true})
+	if !floatEquals(batch.SurchargeCost, 0.01) || len(batch.Warnings) != 0 {
+		t.Errorf("expected the search fee in batch mode, got %+v", batch)
+	}
+}
+
+func TestCalculateResponse_Errors(t *testing.T) {
+	p, err := NewPricer()
+	if err != nil {
+		t.Fatalf("NewPricer failed: %v", err)
+	}
+
+	if _, err := p.CalculateResponse("mistral", []byte(`{not json`), "", nil); err == nil {
+		t.Error("expected error for malformed JSON")
+	}
+	if _, err := p.CalculateResponse("nonexistent", []byte(`{}`), "", nil); err == nil {
+		t.Error("expected error for unsupported provider")
+	}
+	cost, err := p.CalculateResponse("mistral", []byte(`{"model": "unknown-model-xyz", "usage": {"prompt_tokens": 10}}`), "", nil)
+	if err != nil || !cost.Unknown {
+		t.Errorf("expected Unknown for unknown model, got %+v, %v", cost, err)
+	}
+
+	// modelOverride wins over the response's model
+	cost, err = p.CalculateResponse("ai21", []byte(`{"model": "unknown-model-xyz", "usage": {"prompt_tokens": 1000000}}`), "jamba-large", nil)
+	if err != nil || !floatEquals(cost.TotalCost, 2.0) {
+		t.Errorf("expected $2.00 with override, got $%f, %v", cost.TotalCost, err)
+	}
+}
+
+func TestDetectResponseProvider(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		want string
+	}{
+		{"gemini", `{"candidates": [], "usageMetadata": {"promptTokenCount": 10}, "modelVersion": "gemini-2.5-flash"}`, "gemini"},
+		{"gemini usage only", `{"usageMetadata": {"promptTokenCount": 10}}`, "gemini"},
+		{"openai", `{"object": "chat.completion", "model": "gpt-4o", "usage": {"prompt_tokens": 10, "completion_tokens": 5}}`, "openai"},
+		{"openai responses", `{"object": "response", "model": "gpt-4o", "usage": {"input_tokens": 10, "output_tokens": 5}}`, "openai-responses"},
+		{"openai responses usage", `{"usage": {"input_tokens": 10, "input_tokens_details": {"cached_tokens": 0}}}`, "openai-responses"},
+		{"anthropic", `{"type": "message", "model": "claude-sonnet-4-5", "usage": {"input_tokens": 10, "output_tokens": 5}}`, "anthropic"},
+		{"bedrock", `{"output": {}, "usage": {"inputTokens": 10, "outputTokens": 5}}`, "bedrock"},
+		{"cohere v2", `{"id": "x", "usage": {"billed_units": {"input_tokens": 10}, "tokens": {"input_tokens": 12}}}`, "cohere"},
+		{"cohere v1", `{"text": "hi", "meta": {"billed_units": {"input_tokens": 10}}}`, "cohere"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := DetectResponseProvider([]byte(tt.raw))
+			if !ok || got != tt.want {
+				t.Errorf("expected %q, got %q (ok=%v)", tt.want, got, ok)
+			}
+		})
+	}
+
+	for _, raw := range []string{`{not json`, `[]`, `{}`, `{"usage": {"foo": 1}}`, `{"usage": 5}`} {
+		if got, ok := DetectResponseProvider([]byte(raw)); ok {
+			t.Errorf("expected no match for %s, got %q", raw, got)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package pricing_db
+
+import (
+	"fmt"
+	"math"
+)
+
+// RoundingMode selects how cost totals are rounded to the policy's precision.
+type RoundingMode string
+
+const (
+	// RoundHalfUp rounds halves away from zero (costs are never negative,
+	// so this is "up"). The default.
+	RoundHalfUp RoundingMode = "half_up"
+
+	// RoundHalfEven rounds halves to the nearest even digit (banker's rounding).
+	RoundHalfEven RoundingMode = "half_even"
+
+	// RoundCeiling rounds any fraction up, so totals never under-estimate an invoice.
+	RoundCeiling RoundingMode = "ceiling"
+)
+
+// maxRoundingPrecision bounds RoundingPolicy.Precision; float64 cannot
+// represent more significant decimal places for typical cost magnitudes.
+const maxRoundingPrecision = 15
+
+// RoundingPolicy controls how a Pricer rounds cost totals: the number of
+// decimal places kept and the rounding mode. Cost components stay unrounded.
+type RoundingPolicy struct {
+	Precision int          // Decimal places kept (0-15)
+	Mode      RoundingMode // How the last kept place is rounded
+}
+
+// DefaultRoundingPolicy is used when no WithRoundingPolicy option is given.
+var DefaultRoundingPolicy = RoundingPolicy{Precision: costPrecision, Mode: RoundHalfUp}
+
+// validate reports an unknown mode or out-of-range precision.
+func (r RoundingPolicy) validate() error {
+	if r.Precision < 0 || r.Precision > maxRoundingPrecision {
+		return fmt.Errorf("rounding precision %d out of range (0-%d)", r.Precision, maxRoundingPrecision)
+	}
+	switch r.Mode {
+	case RoundHalfUp, RoundHalfEven, RoundCeiling:
+		return nil
+	}
+	return fmt.Errorf("unknown rounding mode %q", r.Mode)
+}
+
+// Round applies the policy to value, e.g. to round a sum of RawTotal values
+// once at the end of an aggregation instead of summing rounded totals.
+// An invalid policy (see WithRoundingPolicy) falls back to DefaultRoundingPolicy.
+func (r RoundingPolicy) Round(value float64) float64 {
+	if r.validate() != nil {
+		r = DefaultRoundingPolicy
+	}
+	return newRounder(r).round(value)
+}
+
+// rounder is a validated RoundingPolicy with its scale precomputed.
+// The zero value is unset; buildPricer replaces it with the default.
+type rounder struct {
+	policy RoundingPolicy
+	scale  float64 // 10^Precision
+}
+
+func newRounder(policy RoundingPolicy) rounder {
+	return rounder{policy: policy, scale: math.Pow10(policy.Precision)}
+}
+
+// round applies the policy to value.
+func (r rounder) round(value float64) float64 {
+	scaled := value * r.scale
+	switch r.policy.Mode {
+	case RoundHalfEven:
+		return math.RoundToEven(scaled) / r.scale
+	case RoundCeiling:
+		// Snap values within float error of a whole unit first, so that
+		// e.g. 0.1+0.2 does not round up to the next unit.
+		if nearest := math.Round(scaled); math.Abs(scaled-nearest) < 1e-6 {
+			return nearest / r.scale
+		}
+		return math.Ceil(scaled) / r.scale
+	default:
+		return math.Round(scaled) / r.scale
+	}
+}
+
+// RoundingPolicy returns the rounding policy applied to this Pricer's cost totals.
+func (p *Pricer) RoundingPolicy() RoundingPolicy {
+	return p.load().rounding.policy
+}
@@ -0,0 +1,148 @@
+package pricing_db
+
+import (
+	"strings"
+	"testing"
+)
+
+// =============================================================================
+// Rounding Policy Tests
+// =============================================================================
+
+func TestRounder_Modes(t *testing.T) {
+	tests := []struct {
+		mode      RoundingMode
+		precision int
+		value     float64
+		expected  float64
+	}{
+		{RoundHalfUp, 2, 0.125, 0.13},
+		{RoundHalfEven, 2, 0.125, 0.12},
+		{RoundHalfEven, 2, 0.375, 0.38},
+		{RoundCeiling, 2, 0.121, 0.13},
+		{RoundCeiling, 2, 0.12, 0.12},
+		{RoundCeiling, 1, 0.1 + 0.2, 0.3}, // float error must not round up
+		{RoundHalfUp, 0, 2.5, 3},
+		{RoundHalfEven, 0, 2.5, 2},
+	}
+	for _, tc := range tests {
+		r := newRounder(RoundingPolicy{Precision: tc.precision, Mode: tc.mode})
+		if got := r.round(tc.value); !floatEquals(got, tc.expected) {
+			t.Errorf("%s/%d round(%v) = %v, want %v", tc.mode, tc.precision, tc.value, got, tc.expected)
+		}
+	}
+}
+
+func TestWithRoundingPolicy(t *testing.T) {
+	p, err := NewPricer(WithRoundingPolicy(RoundingPolicy{Precision: 2, Mode: RoundCeiling}))
+	if err != nil {
+		t.Fatalf("NewPricer failed: %v", err)
+	}
+
+	// gpt-4o: $0.0075 rounds up to a whole cent
+	if cost := p.Calculate("gpt-4o", 1000, 500); !floatEquals(cost.TotalCost, 0.01) {
+		t.Errorf("Calculate total = %f, want 0.01", cost.TotalCost)
+	}
+	details := p.CalculateUsage("gpt-4o", TokenUsage{PromptTokens: 1000, CompletionTokens: 500}, nil)
+	if !floatEquals(details.TotalCost, 0.01) {
+		t.Errorf("CalculateUsage total = %f, want 0.01", details.TotalCost)
+	}
+	// Components stay unrounded
+	if !floatEquals(details.StandardInputCost, 0.0025) {
+		t.Errorf("StandardInputCost = %f, want unrounded 0.0025", details.StandardInputCost)
+	}
+	if got := p.RoundingPolicy(); got.Precision != 2 || got.Mode != RoundCeiling {
+		t.Errorf("RoundingPolicy() = %+v, want precision 2 ceiling", got)
+	}
+}
+
+func TestRoundingPolicy_Default(t *testing.T) {
+	p, err := NewPricer()
+	if err != nil {
+		t.Fatalf("NewPricer failed: %v", err)
+	}
+	if got := p.RoundingPolicy(); got != DefaultRoundingPolicy {
+		t.Errorf("RoundingPolicy() = %+v, want %+v", got, DefaultRoundingPolicy)
+	}
+}
+
+func TestWithRoundingPolicy_Invalid(t *testing.T) {
+	tests := []struct {
+		policy      RoundingPolicy
+		errContains string
+	}{
+		{RoundingPolicy{Precision: -1, Mode: RoundHalfUp}, "out of range"},
+		{RoundingPolicy{Precision: 16, Mode: RoundHalfUp}, "out of range"},
+		{RoundingPolicy{Precision: 2, Mode: "floor"}, "unknown rounding mode"},
+	}
+	for _, tc := range tests {
+		_, err := NewPricer(WithRoundingPolicy(tc.policy))
+		if err == nil || !strings.Contains(err.Error(), tc.errContains) {
+			t.Errorf("policy %+v: expected error containing %q, got %v", tc.policy, tc.errContains, err)
+		}
+	}
+}
+
+// =============================================================================
+// Raw (Unrounded) Total Tests
+// =============================================================================
+
+func TestRawTotal_MatchesComponents(t *testing.T) {
+	p, err := NewPricer(WithRoundingPolicy(RoundingPolicy{Precision: 2, Mode: RoundHalfUp}))
+	if err != nil {
+		t.Fatalf("NewPricer failed: %v", err)
+	}
+
+	d := p.CalculateUsage("gemini-2.5-pro", TokenUsage{
+		PromptTokens:     12345,
+		CachedTokens:     2000,
+		CompletionTokens: 678,
+		ThinkingTokens:   90,
+	}, nil)
+	sum := d.StandardInputCost + d.CachedInputCost + d.ImageInputCost + d.OutputCost +
+		d.ThinkingCost + d.GroundingCost + d.MinimumCharge
+	if d.RawTotal != sum {
+		t.Errorf("RawTotal = %v, want exact component sum %v", d.RawTotal, sum)
+	}
+	if d.TotalCost != p.RoundingPolicy().Round(d.RawTotal) {
+		t.Errorf("TotalCost = %v, want RawTotal rounded (%v)", d.TotalCost, p.RoundingPolicy().Round(d.RawTotal))
+	}
+
+	c := p.Calculate("gpt-4o", 1000, 500)
+	if c.RawTotal != c.InputCost+c.OutputCost || !floatEquals(c.TotalCost, 0.01) {
+		t.Errorf("Calculate RawTotal/TotalCost = %v/%v, want %v/0.01", c.RawTotal, c.TotalCost, c.InputCost+c.OutputCost)
+	}
+
+	r := p.CalculateRealtimeSession("gpt-4o-realtime-preview", RealtimeUsage{TextInputTokens: 1000, AudioOutputTokens: 500})
+	if !r.Unknown && r.RawTotal != r.StandardInputCost+r.AudioInputCost+r.CachedInputCost+r.OutputCost+r.AudioOutputCost {
+		t.Errorf("realtime RawTotal = %v does not match its components", r.RawTotal)
+	}
+}
+
+func TestRoundingPolicy_RoundAggregate(t *testing.T) {
+	p, err := NewPricer(WithRoundingPolicy(RoundingPolicy{Precision: 2, Mode: RoundHalfUp}))
+	if err != nil {
+		t.Fatalf("NewPricer failed: %v", err)
+	}
+
+	// Ten $0.0075 requests: rounding each gives $0.10, rounding the sum gives $0.08
+	var rounded, raw float64
+	for range 10 {
+		c := p.Calculate("gpt-4o", 1000, 500)
+		rounded += c.TotalCost
+		raw += c.RawTotal
+	}
+	if !floatEquals(rounded, 0.10) {
+		t.Errorf("sum of rounded totals = %v, want 0.10", rounded)
+	}
+	if got := p.RoundingPolicy().Round(raw); !floatEquals(got, 0.08) {
+		t.Errorf("rounded sum of raw totals = %v, want 0.08", got)
+	}
+}
+
+func TestRoundingPolicy_RoundInvalidFallsBack(t *testing.T) {
+	invalid := RoundingPolicy{Precision: -3, Mode: "floor"}
+	if got := invalid.Round(0.1234567891234); !floatEquals(got, DefaultRoundingPolicy.Round(0.1234567891234)) {
+		t.Errorf("invalid policy Round = %v, want default rounding", got)
+	}
+}
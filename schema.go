@@ -0,0 +1,73 @@
+package pricing_db
+
+import "fmt"
+
+// CurrentSchemaVersion is the newest config schema_version this package understands.
+//
+// Version history:
+//   - 1: original format (files without a schema_version field). An empty
+//     batch_cache_rule means "stack" and an empty grounding billing_model
+//     means "per_query".
+//   - 2: batch_cache_rule is required on models with a batch_multiplier, and
+//     billing_model is required on grounding entries. Implicit defaults are
+//     no longer assumed, so a forgotten field fails loudly instead of silently
+//     picking the v1 default.
+//
+// Older files are migrated in memory on load; files newer than
+// CurrentSchemaVersion are rejected.
+const CurrentSchemaVersion = 2
+
+// schemaMigrations[v] upgrades a decoded file from version v to v+1.
+var schemaMigrations = map[int]func(*pricingFile){
+	1: migrateV1ToV2,
+}
+
+// migratePricingFile upgrades file in place to CurrentSchemaVersion
+// and checks the requirements of the current schema.
+func migratePricingFile(file *pricingFile, filename string) error {
+	version := file.SchemaVersion
+	if version == 0 {
+		version = 1
+	}
+	if version < 1 || version > CurrentSchemaVersion {
+		return fmt.Errorf("%s: unsupported schema_version %d (supported: 1-%d)", filename, file.SchemaVersion, CurrentSchemaVersion)
+	}
+
+	for ; version < CurrentSchemaVersion; version++ {
+		schemaMigrations[version](file)
+	}
+	file.SchemaVersion = CurrentSchemaVersion
+
+	return validateSchemaV2(file, filename)
+}
+
+// migrateV1ToV2 makes the v1 implicit defaults explicit.
+func migrateV1ToV2(file *pricingFile) {
+	for model, pricing := range file.Models {
+		if pricing.BatchMultiplier > 0 && pricing.BatchCacheRule == "" {
+			pricing.BatchCacheRule = BatchCacheStack
+			file.Models[model] = pricing
+		}
+	}
+	for prefix, pricing := range file.Grounding {
+		if pricing.BillingModel == "" {
+			pricing.BillingModel = "per_query"
+			file.Grounding[prefix] = pricing
+		}
+	}
+}
+
+// validateSchemaV2 rejects fields that v2 requires to be explicit.
+func validateSchemaV2(file *pricingFile, filename string) error {
+	for model, pricing := range file.Models {
+		if pricing.BatchMultiplier > 0 && pricing.BatchCacheRule == "" {
+			return fmt.Errorf("%s: model %q sets batch_multiplier without batch_cache_rule (required since schema_version 2)", filename, model)
+		}
+	}
+	for prefix, pricing := range file.Grounding {
+		if pricing.BillingModel == "" {
+			return fmt.Errorf("%s: grounding prefix %q is missing billing_model (required since schema_version 2)", filename, prefix)
+		}
+	}
+	return nil
+}
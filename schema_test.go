@@ -0,0 +1,99 @@
+package pricing_db
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// =============================================================================
+// Schema Version / Migration Tests
+// =============================================================================
+
+func TestSchemaVersion_EmbeddedConfigsAreCurrent(t *testing.T) {
+	entries, err := ConfigFS.ReadDir("configs")
+	if err != nil {
+		t.Fatalf("ReadDir failed: %v", err)
+	}
+	o := newPricerOptions(nil)
+	for _, entry := range entries {
+		data, err := ConfigFS.ReadFile("configs/" + entry.Name())
+		if err != nil {
+			t.Fatalf("ReadFile failed: %v", err)
+		}
+		file, err := o.decodePricingFile(data, ".json")
+		if err != nil {
+			t.Fatalf("%s: %v", entry.Name(), err)
+		}
+		if file.SchemaVersion != CurrentSchemaVersion {
+			t.Errorf("%s: schema_version %d, want %d", entry.Name(), file.SchemaVersion, CurrentSchemaVersion)
+		}
+	}
+}
+
+func TestSchemaVersion_V1Migrated(t *testing.T) {
+	// No schema_version: v1 defaults are made explicit on load
+	fsys := fstest.MapFS{
+		"configs/test_pricing.json": &fstest.MapFile{Data: []byte(`{
+			"models": {"m": {"input_per_million": 1.0, "output_per_million": 2.0, "batch_multiplier": 0.5}},
+			"grounding": {"m": {"per_thousand_queries": 35.0}}
+		}`)},
+	}
+
+	p, err := NewPricerFromFS(fsys, "configs")
+	if err != nil {
+		t.Fatalf("NewPricerFromFS failed: %v", err)
+	}
+	pricing, _ := p.GetPricing("m")
+	if pricing.BatchCacheRule != BatchCacheStack {
+		t.Errorf("expected migrated batch_cache_rule %q, got %q", BatchCacheStack, pricing.BatchCacheRule)
+	}
+	meta, _ := p.GetProviderMetadata("test")
+	if meta.Grounding["m"].BillingModel != "per_query" {
+		t.Errorf("expected migrated billing_model per_query, got %q", meta.Grounding["m"].BillingModel)
+	}
+}
+
+func TestSchemaVersion_V2RequiresExplicitFields(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  string
+		wantErr string
+	}{
+		{
+			name: "batch without rule",
+			config: `{"schema_version": 2,
+				"models": {"m": {"input_per_million": 1.0, "output_per_million": 2.0, "batch_multiplier": 0.5}}}`,
+			wantErr: "batch_cache_rule",
+		},
+		{
+			name: "grounding without billing model",
+			config: `{"schema_version": 2,
+				"models": {"m": {"input_per_million": 1.0, "output_per_million": 2.0}},
+				"grounding": {"m": {"per_thousand_queries": 35.0}}}`,
+			wantErr: "billing_model",
+		},
+		{
+			name:    "future version",
+			config:  `{"schema_version": 3, "models": {}}`,
+			wantErr: "unsupported schema_version 3",
+		},
+		{
+			name:    "negative version",
+			config:  `{"schema_version": -1, "models": {}}`,
+			wantErr: "unsupported schema_version -1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fsys := fstest.MapFS{
+				"configs/test_pricing.json": &fstest.MapFile{Data: []byte(tt.config)},
+			}
+			_, err := NewPricerFromFS(fsys, "configs")
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+// genembed writes embed_slim.go, which embeds only the listed providers' configs
+// when pricing_db is built with the pricing_slim tag:
+//
+//	go run ./scripts/genembed -providers openai,anthropic
+//	go build -tags pricing_slim ./...
+//
+// Run it from the repository root. Builds without the tag embed every provider.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+func main() {
+	providers := flag.String("providers", "", "Comma-separated providers to embed (required), e.g. openai,anthropic")
+	dir := flag.String("dir", "configs", "Directory containing <provider>_pricing.json files")
+	out := flag.String("o", "embed_slim.go", "Output file")
+	flag.Parse()
+
+	names, err := parseProviders(*providers, *dir)
+	if err != nil {
+		log.Fatalf("genembed: %v", err)
+	}
+	src, err := render(names, *dir)
+	if err != nil {
+		log.Fatalf("genembed: %v", err)
+	}
+	if err := os.WriteFile(*out, src, 0o644); err != nil {
+		log.Fatalf("genembed: %v", err)
+	}
+}
+
+// parseProviders splits list into sorted, de-duplicated provider names and checks
+// that each has a JSON config in dir.
+func parseProviders(list, dir string) ([]string, error) {
+	var names []string
+	for _, name := range strings.Split(list, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(dir, name+"_pricing.json")); err != nil {
+			return nil, fmt.Errorf("provider %q: %w", name, err)
+		}
+		names = append(names, name)
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("no providers given (-providers)")
+	}
+	slices.Sort(names)
+	return slices.Compact(names), nil
+}
+
+// render returns the gofmt'd source of embed_slim.go for the given providers.
+func render(names []string, dir string) ([]byte, error) {
+	patterns := make([]string, len(names))
+	for i, name := range names {
+		patterns[i] = filepath.ToSlash(filepath.Join(dir, name+"_pricing.json"))
+	}
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated by genembed -providers %s; DO NOT EDIT.\n\n", strings.Join(names, ","))
+	b.WriteString("//go:build pricing_slim\n\n")
+	b.WriteString("package pricing_db\n\n")
+	b.WriteString("import \"embed\"\n\n")
+	b.WriteString("// ConfigFS contains the embedded pricing configuration files. This pricing_slim\n")
+	b.WriteString("// build embeds only the providers listed below; regenerate with scripts/genembed.\n")
+	b.WriteString("//\n")
+	fmt.Fprintf(&b, "//go:embed %s\n", strings.Join(patterns, " "))
+	b.WriteString("var ConfigFS embed.FS\n")
+	return format.Source(b.Bytes())
+}
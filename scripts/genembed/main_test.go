@@ -0,0 +1,50 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseProviders(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"openai", "anthropic"} {
+		if err := os.WriteFile(filepath.Join(dir, name+"_pricing.json"), []byte("{}"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	names, err := parseProviders(" openai,anthropic,,openai ", dir)
+	if err != nil {
+		t.Fatalf("parseProviders failed: %v", err)
+	}
+	if strings.Join(names, ",") != "anthropic,openai" {
+		t.Errorf("expected sorted, de-duplicated providers, got %v", names)
+	}
+
+	if _, err := parseProviders("openai,nope", dir); err == nil {
+		t.Error("expected error for provider without a config")
+	}
+	if _, err := parseProviders("", dir); err == nil {
+		t.Error("expected error for empty provider list")
+	}
+}
+
+func TestRender(t *testing.T) {
+	src, err := render([]string{"anthropic", "openai"}, "configs")
+	if err != nil {
+		t.Fatalf("render failed: %v", err)
+	}
+
+	out := string(src)
+	for _, want := range []string{
+		"//go:build pricing_slim",
+		"//go:embed configs/anthropic_pricing.json configs/openai_pricing.json",
+		"var ConfigFS embed.FS",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package pricing_db
+
+import (
+	"slices"
+	"sort"
+)
+
+// ModelFilter describes constraints for SearchModels. Zero values mean "no constraint".
+type ModelFilter struct {
+	MaxInputPerMillion  float64    // Maximum base input price (USD per million tokens)
+	MaxOutputPerMillion float64    // Maximum base output price (USD per million tokens)
+	Providers           []string   // Restrict to these providers
+	SupportsBatch       bool       // Require a batch discount (batch_multiplier < 1)
+	SupportsCaching     bool       // Require an explicit cache_read_multiplier
+	HasTiers            bool       // Require tiered pricing
+	MinContextWindow    int64      // Require at least this context window
+	MinTokensPerSecond  float64    // Require at least this output_tokens_per_second
+	InputModalities     []Modality // Require all of these input modalities
+	ExcludeDeprecated   bool       // Skip deprecated or sunset models
+}
+
+// SearchModels returns the token-priced models matching filter, cheapest input
+// price first (ties broken by provider, then model name). Each provider's entry is
+// returned once under its bare model name with Provider set; use
+// Provider + "/" + Model for an unambiguous lookup key.
+func (p *Pricer) SearchModels(filter ModelFilter) []ModelInfo {
+	c := p.load()
+
+	var results []ModelInfo
+	for providerName, pp := range c.providers {
+		if len(filter.Providers) > 0 && !slices.Contains(filter.Providers, providerName) {
+			continue
+		}
+		for model, pricing := range pp.Models {
+			if !filter.matches(pricing) {
+				continue
+			}
+			results = append(results, ModelInfo{
+				Model:        model,
+				Provider:     providerName,
+				ModelPricing: copyModelPricing(pricing),
+			})
+		}
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].InputPerMillion != results[j].InputPerMillion {
+			return results[i].InputPerMillion < results[j].InputPerMillion
+		}
+		if results[i].Provider != results[j].Provider {
+			return results[i].Provider < results[j].Provider
+		}
+		return results[i].Model < results[j].Model
+	})
+	return results
+}
+
+// matches reports whether pricing satisfies every constraint in the filter.
+func (f ModelFilter) matches(pricing ModelPricing) bool {
+	if f.MaxInputPerMillion > 0 && pricing.InputPerMillion > f.MaxInputPerMillion {
+		return false
+	}
+	if f.MaxOutputPerMillion > 0 && pricing.OutputPerMillion > f.MaxOutputPerMillion {
+		return false
+	}
+	if f.SupportsBatch && (pricing.BatchMultiplier <= 0 || pricing.BatchMultiplier >= 1) {
+		return false
+	}
+	if f.SupportsCaching && pricing.CacheReadMultiplier <= 0 {
+		return false
+	}
+	if f.HasTiers && len(pricing.Tiers) == 0 {
+		return false
+	}
+	if f.MinContextWindow > 0 && pricing.ContextWindow < f.MinContextWindow {
+		return false
+	}
+	if f.MinTokensPerSecond > 0 && pricing.OutputTokensPerSecond < f.MinTokensPerSecond {
+		return false
+	}
+	for _, m := range f.InputModalities {
+		if !slices.Contains(pricing.InputModalities, m) {
+			return false
+		}
+	}
+	if f.ExcludeDeprecated && (pricing.Deprecated || pricing.SunsetDate != "") {
+		return false
+	}
+	return true
+}
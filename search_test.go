@@ -0,0 +1,95 @@
+package pricing_db
+
+import (
+	"testing"
+)
+
+// =============================================================================
+// Model Search Tests
+// =============================================================================
+
+func TestSearchModels_CheapestBatchUnderBudget(t *testing.T) {
+	p, err := NewPricer()
+	if err != nil {
+		t.Fatalf("NewPricer failed: %v", err)
+	}
+
+	results := p.SearchModels(ModelFilter{MaxInputPerMillion: 1.0, SupportsBatch: true})
+	if len(results) == 0 {
+		t.Fatal("expected at least one batch-capable model under $1/M input")
+	}
+	for i, r := range results {
+		if r.InputPerMillion > 1.0 {
+			t.Errorf("%s/%s exceeds max input price: %f", r.Provider, r.Model, r.InputPerMillion)
+		}
+		if r.BatchMultiplier <= 0 || r.BatchMultiplier >= 1 {
+			t.Errorf("%s/%s has no batch discount", r.Provider, r.Model)
+		}
+		if i > 0 && results[i-1].InputPerMillion > r.InputPerMillion {
+			t.Errorf("results not sorted by input price at index %d", i)
+		}
+	}
+}
+
+func TestSearchModels_ProviderAndCapabilities(t *testing.T) {
+	p, err := NewPricer()
+	if err != nil {
+		t.Fatalf("NewPricer failed: %v", err)
+	}
+
+	results := p.SearchModels(ModelFilter{
+		Providers:       []string{"google"},
+		HasTiers:        true,
+		SupportsCaching: true,
+	})
+	if len(results) == 0 {
+		t.Fatal("expected tiered Google models with caching")
+	}
+	for _, r := range results {
+		if r.Provider != "google" {
+			t.Errorf("unexpected provider %q", r.Provider)
+		}
+		if len(r.Tiers) == 0 {
+			t.Errorf("%s has no tiers", r.Model)
+		}
+	}
+
+	results = SearchModels(ModelFilter{InputModalities: []Modality{ModalityVideo}, MinContextWindow: 1_000_000})
+	for _, r := range results {
+		if !r.SupportsInput(ModalityVideo) || r.ContextWindow < 1_000_000 {
+			t.Errorf("%s/%s does not satisfy modality/context constraints", r.Provider, r.Model)
+		}
+	}
+	if len(results) == 0 {
+		t.Error("expected Gemini models with video input and 1M context")
+	}
+}
+
+func TestSearchModels_ExcludeDeprecated(t *testing.T) {
+	p, err := NewPricer()
+	if err != nil {
+		t.Fatalf("NewPricer failed: %v", err)
+	}
+
+	all := p.SearchModels(ModelFilter{Providers: []string{"anthropic"}})
+	current := p.SearchModels(ModelFilter{Providers: []string{"anthropic"}, ExcludeDeprecated: true})
+	if len(current) >= len(all) {
+		t.Errorf("expected deprecated models to be excluded: %d current vs %d total", len(current), len(all))
+	}
+	for _, r := range current {
+		if r.Deprecated {
+			t.Errorf("deprecated model %s returned", r.Model)
+		}
+	}
+}
+
+func TestSearchModels_NoMatches(t *testing.T) {
+	p, err := NewPricer()
+	if err != nil {
+		t.Fatalf("NewPricer failed: %v", err)
+	}
+
+	if results := p.SearchModels(ModelFilter{Providers: []string{"no-such-provider"}}); len(results) != 0 {
+		t.Errorf("expected no results, got %d", len(results))
+	}
+}
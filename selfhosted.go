@@ -0,0 +1,143 @@
+package pricing_db
+
+import (
+	"fmt"
+	"sort"
+)
+
+// hoursPerMonth is the average number of hours in a month (365.25 * 24 / 12).
+const hoursPerMonth = 730.5
+
+// HourlyCostUSD returns the replica's cost per hour: GPU rental, plus amortized
+// hardware, plus electricity.
+func (s SelfHostedPricing) HourlyCostUSD() float64 {
+	gpus := max(s.GPUCount, 1)
+	hourly := s.GPUHourUSD * float64(gpus)
+	if s.AmortizationMonths > 0 {
+		hourly += s.HardwareUSD / (float64(s.AmortizationMonths) * hoursPerMonth)
+	}
+	hourly += s.PowerWatts / 1000 * s.ElectricityUSDPerKWh
+	return hourly
+}
+
+// modelPricing derives per-million token rates from the hourly cost: a million
+// input tokens occupy the replica for 1e6/InputTokensPerSecond seconds, and
+// likewise for output, so a request costs its prefill plus decode time.
+func (s SelfHostedPricing) modelPricing() ModelPricing {
+	perSecond := s.HourlyCostUSD() / 3600
+	return ModelPricing{
+		InputPerMillion:  perSecond * TokensPerMillion / s.InputTokensPerSecond,
+		OutputPerMillion: perSecond * TokensPerMillion / s.OutputTokensPerSecond,
+	}
+}
+
+// GetSelfHostedPricing returns the hardware and throughput configuration of a
+// self-hosted model, using the same exact/prefix resolution as Calculate.
+// Returns false for API-priced and unknown models.
+func (p *Pricer) GetSelfHostedPricing(model string) (SelfHostedPricing, bool) {
+	c := p.load()
+
+	key, ok := c.resolveModelKey(model)
+	if !ok {
+		return SelfHostedPricing{}, false
+	}
+	s, ok := c.selfHosted[key]
+	return s, ok
+}
+
+// CostComparison is one model's cost for a shared usage profile.
+type CostComparison struct {
+	Model      string // Model as requested
+	Provider   string // Provider whose pricing was used; empty if unknown
+	SelfHosted bool   // Priced from self_hosted_models
+	Cost       CostDetails
+}
+
+// CompareUsage prices the same usage on each model, cheapest first, so
+// self-hosted models can be compared with API spend. Unknown models sort last.
+func (p *Pricer) CompareUsage(usage TokenUsage, models []string, opts *CalculateOptions) []CostComparison {
+	c := p.load()
+
+	results := make([]CostComparison, len(models))
+	for i, model := range models {
+		rates, _ := c.lookupRates(model)
+		results[i].Model = model
+		c.calculateUsageInto(&results[i].Cost, rates, model, usage, opts)
+		if key, ok := c.resolveModelKey(model); ok {
+			results[i].Provider = c.modelProviders[key]
+			_, results[i].SelfHosted = c.selfHosted[key]
+		}
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Cost.Unknown != results[j].Cost.Unknown {
+			return !results[i].Cost.Unknown
+		}
+		return results[i].Cost.RawTotal < results[j].Cost.RawTotal
+	})
+	return results
+}
+
+// addSelfHostedModels validates a file's self-hosted models and adds their
+// derived token pricing to file.Models.
+func addSelfHostedModels(file *pricingFile, filename string) error {
+	if len(file.SelfHostedModels) == 0 {
+		return nil
+	}
+	if file.Models == nil {
+		file.Models = make(map[string]ModelPricing, len(file.SelfHostedModels))
+	}
+	for model, s := range file.SelfHostedModels {
+		if err := validateSelfHostedPricing(model, s, filename); err != nil {
+			return err
+		}
+		if _, exists := file.Models[model]; exists {
+			return fmt.Errorf("%s: model %q is defined in both models and self_hosted_models", filename, model)
+		}
+		file.Models[model] = s.modelPricing()
+	}
+	return nil
+}
+
+// indexSelfHosted maps each catalog key served by a self-hosted model to its configuration.
+func (c *catalog) indexSelfHosted() {
+	c.selfHosted = make(map[string]SelfHostedPricing)
+	for provider, pp := range c.providers {
+		for model, s := range pp.SelfHostedModels {
+			c.selfHosted[provider+"/"+model] = s
+			if c.modelProviders[model] == provider {
+				c.selfHosted[model] = s
+			}
+		}
+	}
+}
+
+// validateSelfHostedPricing checks for missing throughput and invalid cost inputs.
+func validateSelfHostedPricing(model string, s SelfHostedPricing, filename string) error {
+	context := fmt.Sprintf("self-hosted model %q", model)
+	for _, v := range []struct {
+		field string
+		value float64
+	}{
+		{"gpu_hour_usd", s.GPUHourUSD},
+		{"hardware_usd", s.HardwareUSD},
+		{"power_watts", s.PowerWatts},
+		{"electricity_usd_per_kwh", s.ElectricityUSDPerKWh},
+	} {
+		if err := validateNonNegative(v.value, v.field, context, filename); err != nil {
+			return err
+		}
+	}
+	if s.GPUCount < 0 || s.AmortizationMonths < 0 {
+		return fmt.Errorf("%s: %s has negative gpu_count or amortization_months", filename, context)
+	}
+	if s.HardwareUSD > 0 && s.AmortizationMonths == 0 {
+		return fmt.Errorf("%s: %s has hardware_usd but no amortization_months", filename, context)
+	}
+	if s.InputTokensPerSecond <= 0 || s.OutputTokensPerSecond <= 0 {
+		return fmt.Errorf("%s: %s must have positive input_tokens_per_second and output_tokens_per_second", filename, context)
+	}
+	if s.HourlyCostUSD() <= 0 {
+		return fmt.Errorf("%s: %s has no hourly cost (set gpu_hour_usd, hardware_usd, or power_watts)", filename, context)
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package pricing_db
+
+import (
+	"testing"
+	"testing/fstest"
+)
+
+// =============================================================================
+// Self-Hosted Model Tests
+// =============================================================================
+
+var selfHostedTestConfigs = map[string]string{
+	"local_pricing.json": `{
+		"provider": "local",
+		"billing_type": "self_hosted",
+		"self_hosted_models": {
+			"llama-3.1-8b": {"gpu_hour_usd": 1.8, "input_tokens_per_second": 10000, "output_tokens_per_second": 500},
+			"llama-owned": {
+				"hardware_usd": 21915, "amortization_months": 12,
+				"power_watts": 500, "electricity_usd_per_kwh": 0.2,
+				"input_tokens_per_second": 10000, "output_tokens_per_second": 1000
+			}
+		}
+	}`,
+	"api_pricing.json": `{
+		"provider": "api",
+		"models": {"api-model": {"input_per_million": 0.15, "output_per_million": 0.6}}
+	}`,
+}
+
+func TestSelfHostedPricing_HourlyCost(t *testing.T) {
+	tests := []struct {
+		name string
+		s    SelfHostedPricing
+		want float64
+	}{
+		{"rented", SelfHostedPricing{GPUHourUSD: 1.8}, 1.8},
+		{"multi-gpu", SelfHostedPricing{GPUHourUSD: 2.0, GPUCount: 4}, 8.0},
+		{"owned", SelfHostedPricing{HardwareUSD: 21915, AmortizationMonths: 12, PowerWatts: 500, ElectricityUSDPerKWh: 0.2}, 2.6},
+	}
+	for _, tc := range tests {
+		if got := tc.s.HourlyCostUSD(); !floatEquals(got, tc.want) {
+			t.Errorf("%s: expected $%f/hour, got $%f", tc.name, tc.want, got)
+		}
+	}
+}
+
+func TestCalculate_SelfHosted(t *testing.T) {
+	p := newTestPricer(t, selfHostedTestConfigs)
+
+	// $1.80/hour = $0.0005/s; 10,000 input tok/s -> $0.05/M, 500 output tok/s -> $1.00/M
+	pricing, ok := p.GetPricing("llama-3.1-8b")
+	if !ok {
+		t.Fatal("expected derived pricing for self-hosted model")
+	}
+	if !floatEquals(pricing.InputPerMillion, 0.05) || !floatEquals(pricing.OutputPerMillion, 1.0) {
+		t.Errorf("expected $0.05/$1.00 per M, got $%f/$%f", pricing.InputPerMillion, pricing.OutputPerMillion)
+	}
+	// One hour of output at full throughput costs one hour of the GPU
+	cost := p.Calculate("llama-3.1-8b", 0, 500*3600)
+	if !floatEquals(cost.TotalCost, 1.8) {
+		t.Errorf("expected $1.80 for an hour of decode, got $%f", cost.TotalCost)
+	}
+
+	sh, ok := p.GetSelfHostedPricing("local/llama-owned")
+	if !ok || sh.AmortizationMonths != 12 {
+		t.Errorf("expected self-hosted config for local/llama-owned, got %+v, %v", sh, ok)
+	}
+	if _, ok := p.GetSelfHostedPricing("api-model"); ok {
+		t.Error("expected API model not to be self-hosted")
+	}
+}
+
+func TestCompareUsage(t *testing.T) {
+	p := newTestPricer(t, selfHostedTestConfigs)
+
+	usage := TokenUsage{PromptTokens: 1_000_000, CompletionTokens: 1_000_000}
+	results := p.CompareUsage(usage, []string{"llama-3.1-8b", "unknown-model", "api-model", "llama-owned"}, nil)
+	if len(results) != 4 {
+		t.Fatalf("expected 4 results, got %d", len(results))
+	}
+	// api-model $0.75, llama-owned ~$0.794, llama-3.1-8b $1.05, unknown last
+	wantOrder := []string{"api-model", "llama-owned", "llama-3.1-8b", "unknown-model"}
+	for i, want := range wantOrder {
+		if results[i].Model != want {
+			t.Errorf("position %d: expected %s, got %s", i, want, results[i].Model)
+		}
+	}
+	if !results[1].SelfHosted || results[1].Provider != "local" || results[0].SelfHosted {
+		t.Errorf("unexpected provider/self-hosted flags: %+v / %+v", results[0], results[1])
+	}
+	if !results[3].Cost.Unknown || results[3].Provider != "" {
+		t.Errorf("expected unknown model last without a provider, got %+v", results[3])
+	}
+}
+
+func TestNewPricerFromFS_InvalidSelfHosted(t *testing.T) {
+	tests := map[string]string{
+		"no throughput":        `{"gpu_hour_usd": 1.0, "input_tokens_per_second": 100}`,
+		"no cost":              `{"input_tokens_per_second": 100, "output_tokens_per_second": 100}`,
+		"negative gpu price":   `{"gpu_hour_usd": -1, "input_tokens_per_second": 100, "output_tokens_per_second": 100}`,
+		"hardware without age": `{"hardware_usd": 1000, "input_tokens_per_second": 100, "output_tokens_per_second": 100}`,
+	}
+	for name, model := range tests {
+		fsys := fstest.MapFS{
+			"configs/bad_pricing.json": &fstest.MapFile{Data: []byte(`{"self_hosted_models": {"m": ` + model + `}}`)},
+		}
+		if _, err := NewPricerFromFS(fsys, "configs"); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+
+	// A model may not be both API-priced and self-hosted in one file
+	fsys := fstest.MapFS{
+		"configs/bad_pricing.json": &fstest.MapFile{Data: []byte(`{
+			"models": {"m": {"input_per_million": 1, "output_per_million": 1}},
+			"self_hosted_models": {"m": {"gpu_hour_usd": 1, "input_tokens_per_second": 100, "output_tokens_per_second": 100}}
+		}`)},
+	}
+	if _, err := NewPricerFromFS(fsys, "configs"); err == nil {
+		t.Error("expected error for duplicate model")
+	}
+}
@@ -0,0 +1,123 @@
+package pricing_db
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+)
+
+// Scenario is a what-if change to recorded usage, evaluated by Simulate.
+type Scenario struct {
+	// CachedFraction is the fraction (0-1) of each request's input tokens served
+	// from the prompt cache. Requests that recorded more cached tokens keep them;
+	// 0 leaves caching as recorded. Cache write costs are not simulated.
+	CachedFraction float64
+	// Batch moves requests to batch mode when the model has a batch discount and
+	// the request uses nothing batch mode excludes (grounding or surcharges
+	// without batch support). Other requests stay as recorded.
+	Batch bool
+}
+
+// SimulationSummary totals one model's records in a SimulationReport.
+type SimulationSummary struct {
+	Model         string
+	Requests      int
+	MovedToBatch  int     // Requests the scenario moved to batch mode
+	BaselineCost  float64 // Cost as recorded
+	SimulatedCost float64 // Cost under the scenario
+	Savings       float64 // BaselineCost - SimulatedCost
+}
+
+// SimulationReport is the outcome of a Scenario over recorded usage.
+type SimulationReport struct {
+	Scenario       Scenario
+	Models         []SimulationSummary // Largest savings first
+	BaselineTotal  float64
+	SimulatedTotal float64
+	Savings        float64 // BaselineTotal - SimulatedTotal
+}
+
+// Simulate prices records as recorded and under scenario s, reporting the
+// savings per model so optimization work can be prioritized, e.g. "what if 60%
+// of input were cached?" or "what if eligible traffic moved to batch?". Both
+// costs use the rates in effect at each record's At.
+func (p *Pricer) Simulate(records []UsageRecord, s Scenario) (SimulationReport, error) {
+	if s.CachedFraction < 0 || s.CachedFraction > 1 {
+		return SimulationReport{}, fmt.Errorf("cached fraction %g out of range (0-1)", s.CachedFraction)
+	}
+	c := p.load()
+
+	baseline := make([]CostDetails, len(records))
+	simulated := make([]CostDetails, len(records))
+	moved := make(map[string]int)
+	resolved := make(map[string]*modelRates)
+	for i, rec := range records {
+		rates, seen := resolved[rec.Model]
+		if !seen {
+			rates, _ = c.lookupRates(rec.Model)
+			resolved[rec.Model] = rates
+		}
+		rates = rates.at(rec.At)
+		c.calculateUsageInto(&baseline[i], rates, rec.Model, rec.Usage, rec.Options)
+
+		usage := rec.Usage
+		if s.CachedFraction > 0 {
+			input, _ := addInt64Safe(usage.PromptTokens, usage.ToolUseTokens)
+			usage.CachedTokens = max(usage.CachedTokens, int64(float64(input)*s.CachedFraction))
+		}
+		c.calculateUsageInto(&simulated[i], rates, rec.Model, usage, rec.Options)
+		if s.Batch && rates != nil && rates.batchMultiplier < 1 && !simulated[i].BatchMode {
+			var batched CostDetails
+			c.calculateUsageInto(&batched, rates, rec.Model, usage, withBatchMode(rec.Options))
+			if !hasBatchExclusion(batched) {
+				simulated[i] = batched
+				moved[rec.Model]++
+			}
+		}
+	}
+
+	report := SimulationReport{Scenario: s}
+	byModel, baselineRaw, simulatedRaw := sumByModel(records, baseline, simulated)
+	report.Models = make([]SimulationSummary, 0, len(byModel))
+	for model, sums := range byModel {
+		report.Models = append(report.Models, SimulationSummary{
+			Model:         model,
+			Requests:      sums.requests,
+			MovedToBatch:  moved[model],
+			BaselineCost:  c.rounding.round(sums.a),
+			SimulatedCost: c.rounding.round(sums.b),
+			Savings:       c.rounding.round(sums.a - sums.b),
+		})
+	}
+	slices.SortFunc(report.Models, func(a, b SimulationSummary) int {
+		if c := cmp.Compare(b.Savings, a.Savings); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Model, b.Model)
+	})
+	report.BaselineTotal = c.rounding.round(baselineRaw)
+	report.SimulatedTotal = c.rounding.round(simulatedRaw)
+	report.Savings = c.rounding.round(baselineRaw - simulatedRaw)
+	return report, nil
+}
+
+// withBatchMode returns a copy of opts with BatchMode set.
+func withBatchMode(opts *CalculateOptions) *CalculateOptions {
+	batched := CalculateOptions{}
+	if opts != nil {
+		batched = *opts
+	}
+	batched.BatchMode = true
+	return &batched
+}
+
+// hasBatchExclusion reports whether batch mode dropped part of d's cost because
+// the request uses a feature the provider's batch API does not support.
+func hasBatchExclusion(d CostDetails) bool {
+	for _, w := range d.WarningDetails {
+		if w.Code == WarningBatchGroundingExcluded || w.Code == WarningBatchSurchargeExcluded {
+			return true
+		}
+	}
+	return false
+}
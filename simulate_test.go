@@ -0,0 +1,90 @@
+package pricing_db
+
+import (
+	"testing"
+)
+
+// =============================================================================
+// Simulate Tests
+// =============================================================================
+
+var simulateTestConfigs = map[string]string{
+	"acme_pricing.json": `{
+		"provider": "acme",
+		"models": {
+			"acme-batch": {
+				"input_per_million": 1.0,
+				"output_per_million": 2.0,
+				"cache_read_multiplier": 0.1,
+				"batch_multiplier": 0.5,
+				"surcharges": {"citations": {"unit": "citation", "price_per_unit": 0.001}}
+			},
+			"acme-online": {"input_per_million": 1.0, "output_per_million": 2.0, "cache_read_multiplier": 0.1}
+		}
+	}`,
+}
+
+func TestSimulate_CachedFraction(t *testing.T) {
+	p := newTestPricer(t, simulateTestConfigs)
+	records := []UsageRecord{
+		{Model: "acme-online", Usage: TokenUsage{PromptTokens: 1_000_000}},
+		// Already caches more than the scenario; unchanged
+		{Model: "acme-batch", Usage: TokenUsage{PromptTokens: 1_000_000, CachedTokens: 900_000}},
+	}
+	report, err := p.Simulate(records, Scenario{CachedFraction: 0.6})
+	if err != nil {
+		t.Fatalf("Simulate failed: %v", err)
+	}
+
+	// acme-online: $1.00 -> 0.4 * $1 + 0.6 * $0.1 = $0.46
+	top := report.Models[0]
+	if top.Model != "acme-online" || top.BaselineCost != 1.0 || !floatEquals(top.SimulatedCost, 0.46) || !floatEquals(top.Savings, 0.54) {
+		t.Errorf("unexpected acme-online summary %+v", top)
+	}
+	if other := report.Models[1]; other.Savings != 0 {
+		t.Errorf("expected no savings for a model already caching more, got %+v", other)
+	}
+	if !floatEquals(report.Savings, 0.54) || !floatEquals(report.BaselineTotal-report.SimulatedTotal, report.Savings) {
+		t.Errorf("unexpected totals %+v", report)
+	}
+}
+
+func TestSimulate_Batch(t *testing.T) {
+	p := newTestPricer(t, simulateTestConfigs)
+	records := []UsageRecord{
+		{Model: "acme-batch", Usage: TokenUsage{PromptTokens: 1_000_000, CompletionTokens: 1_000_000}},
+		{Model: "acme-batch", Usage: TokenUsage{PromptTokens: 1_000_000}, Options: &CalculateOptions{BatchMode: true}},
+		// Citations are not available in batch mode, so this request stays online
+		{Model: "acme-batch", Usage: TokenUsage{PromptTokens: 1_000_000, Surcharges: map[string]int64{"citations": 10}}},
+		// No batch discount
+		{Model: "acme-online", Usage: TokenUsage{PromptTokens: 1_000_000}},
+	}
+	report, err := p.Simulate(records, Scenario{Batch: true})
+	if err != nil {
+		t.Fatalf("Simulate failed: %v", err)
+	}
+
+	batch := report.Models[0]
+	if batch.Model != "acme-batch" || batch.Requests != 3 || batch.MovedToBatch != 1 || !floatEquals(batch.Savings, 1.5) {
+		t.Errorf("unexpected acme-batch summary %+v", batch)
+	}
+	if online := report.Models[1]; online.MovedToBatch != 0 || online.Savings != 0 {
+		t.Errorf("expected acme-online unchanged, got %+v", online)
+	}
+	if report.Scenario != (Scenario{Batch: true}) {
+		t.Errorf("expected the scenario in the report, got %+v", report.Scenario)
+	}
+
+	// The input records are not modified
+	if records[0].Options != nil {
+		t.Error("Simulate modified the input records")
+	}
+}
+
+func TestSimulate_InvalidFraction(t *testing.T) {
+	for _, fraction := range []float64{-0.1, 1.5} {
+		if _, err := defaultPricer().Simulate(nil, Scenario{CachedFraction: fraction}); err == nil {
+			t.Errorf("expected error for cached fraction %g", fraction)
+		}
+	}
+}
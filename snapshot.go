@@ -0,0 +1,160 @@
+package pricing_db
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// SnapshotFormat identifies the JSON document layout written by Pricer.Export.
+const SnapshotFormat = "pricing_db.snapshot/v1"
+
+// Snapshot is the fully merged, validated pricing catalog as a single JSON document.
+// Unlike the per-provider config files it includes inferred provider names,
+// provider-namespaced keys ("openai/gpt-4o"), and the result of collision resolution,
+// so other systems see exactly what the Pricer uses.
+type Snapshot struct {
+	Format         string                       `json:"format"`
+	Providers      map[string]ProviderPricing   `json:"providers"`
+	Models         map[string]ModelPricing      `json:"models"`
+	ModelProviders map[string]string            `json:"model_providers"`
+	ImageModels    map[string]ImageModelPricing `json:"image_models"`
+	Grounding      map[string]GroundingPricing  `json:"grounding"`
+	CreditPricing  map[string]*CreditPricing    `json:"credit_pricing"`
+	RerankModels   map[string]RerankPricing     `json:"rerank_models,omitempty"`
+	InstanceTypes  map[string]InstancePricing   `json:"instance_types,omitempty"`
+	// RoundingPolicy and DefaultCacheMultiplier are the exporting Pricer's
+	// settings, restored by NewPricerFromSnapshot so it prices identically.
+	// Older snapshots omit them and load with the defaults.
+	RoundingPolicy         *RoundingPolicy `json:"rounding_policy,omitempty"`
+	DefaultCacheMultiplier float64         `json:"default_cache_multiplier,omitempty"`
+}
+
+// Export writes the merged pricing catalog to w as one indented JSON document.
+// Map keys are sorted by encoding/json, so output is deterministic.
+// Load it back with NewPricerFromSnapshot.
+func (p *Pricer) Export(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(p.load().snapshot()); err != nil {
+		return fmt.Errorf("export snapshot: %w", err)
+	}
+	return nil
+}
+
+// snapshot returns the catalog's pricing data as a Snapshot sharing its maps.
+func (c *catalog) snapshot() Snapshot {
+	return Snapshot{
+		Format:         SnapshotFormat,
+		Providers:      c.providers,
+		Models:         c.models,
+		ModelProviders: c.modelProviders,
+		ImageModels:    c.imageModels,
+		Grounding:      c.grounding,
+		CreditPricing:  c.credits,
+		RerankModels:   c.rerankModels,
+		InstanceTypes:  c.instances,
+
+		RoundingPolicy:         &c.rounding.policy,
+		DefaultCacheMultiplier: c.cacheDefault,
+	}
+}
+
+// NewPricerFromSnapshot creates a Pricer from a JSON document written by Export.
+// All entries are validated with the same rules as NewPricerFromFS. The
+// snapshot's rounding policy and default cache multiplier are restored unless
+// opts set them; opts apply as with NewPricerFromFS, except the collision and
+// decoder options, which only affect loading config files.
+func NewPricerFromSnapshot(r io.Reader, opts ...Option) (*Pricer, error) {
+	var snap Snapshot
+	if err := json.NewDecoder(r).Decode(&snap); err != nil {
+		return nil, fmt.Errorf("parse snapshot: %w", err)
+	}
+	if snap.Format != SnapshotFormat {
+		return nil, fmt.Errorf("unsupported snapshot format %q (want %q)", snap.Format, SnapshotFormat)
+	}
+	if len(snap.Providers) == 0 {
+		return nil, fmt.Errorf("snapshot contains no providers")
+	}
+
+	var saved []Option
+	if snap.RoundingPolicy != nil {
+		saved = append(saved, WithRoundingPolicy(*snap.RoundingPolicy))
+	}
+	if snap.DefaultCacheMultiplier != 0 {
+		saved = append(saved, WithDefaultCacheMultiplier(snap.DefaultCacheMultiplier))
+	}
+	o := newPricerOptions(append(saved, opts...))
+	fallback, err := o.validate()
+	if err != nil {
+		return nil, fmt.Errorf("snapshot: %w", err)
+	}
+
+	const source = "snapshot"
+	for model, pricing := range snap.Models {
+		if err := validateModelPricing(model, pricing, source); err != nil {
+			return nil, err
+		}
+		sortTiers(pricing.Tiers)
+		for _, period := range pricing.PriceHistory {
+			sortTiers(period.Tiers)
+		}
+	}
+	for model, pricing := range snap.ImageModels {
+		if err := validateImagePricing(model, pricing, source); err != nil {
+			return nil, err
+		}
+	}
+	for prefix, pricing := range snap.Grounding {
+		if err := validateGroundingPricing(prefix, pricing, source); err != nil {
+			return nil, err
+		}
+	}
+	for model, pricing := range snap.RerankModels {
+		if err := validateRerankPricing(model, pricing, source); err != nil {
+			return nil, err
+		}
+	}
+	for instanceType, pricing := range snap.InstanceTypes {
+		if err := validateInstancePricing(instanceType, pricing, source); err != nil {
+			return nil, err
+		}
+	}
+	for provider, pp := range snap.Providers {
+		if err := validateSurcharges(pp.Surcharges, fmt.Sprintf("provider %q", provider), source); err != nil {
+			return nil, err
+		}
+		for model, sh := range pp.SelfHostedModels {
+			if err := validateSelfHostedPricing(model, sh, source); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for provider, pricing := range snap.CreditPricing {
+		if pricing == nil {
+			return nil, fmt.Errorf("%s: provider %q has null credit pricing", source, provider)
+		}
+		if err := validateCreditPricing(pricing, source); err != nil {
+			return nil, err
+		}
+	}
+
+	return o.newPricer(&catalog{
+		models:         nonNilMap(snap.Models),
+		modelProviders: nonNilMap(snap.ModelProviders),
+		imageModels:    nonNilMap(snap.ImageModels),
+		grounding:      nonNilMap(snap.Grounding),
+		credits:        nonNilMap(snap.CreditPricing),
+		rerankModels:   nonNilMap(snap.RerankModels),
+		instances:      nonNilMap(snap.InstanceTypes),
+		providers:      snap.Providers,
+	}, fallback)
+}
+
+// nonNilMap returns m, or an empty map if m is nil.
+func nonNilMap[V any](m map[string]V) map[string]V {
+	if m == nil {
+		return make(map[string]V)
+	}
+	return m
+}
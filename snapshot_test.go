@@ -0,0 +1,148 @@
+package pricing_db
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// =============================================================================
+// Snapshot Export / Import Tests
+// =============================================================================
+
+func TestExport_RoundTrip(t *testing.T) {
+	p, err := NewPricer()
+	if err != nil {
+		t.Fatalf("NewPricer failed: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := p.Export(&buf); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+
+	loaded, err := NewPricerFromSnapshot(&buf)
+	if err != nil {
+		t.Fatalf("NewPricerFromSnapshot failed: %v", err)
+	}
+
+	if loaded.ModelCount() != p.ModelCount() {
+		t.Errorf("model count mismatch: %d vs %d", loaded.ModelCount(), p.ModelCount())
+	}
+	if loaded.ProviderCount() != p.ProviderCount() {
+		t.Errorf("provider count mismatch: %d vs %d", loaded.ProviderCount(), p.ProviderCount())
+	}
+
+	// Calculations must be identical, including prefix matches and namespaced keys
+	for _, model := range []string{"gpt-4o", "gpt-4o-2024-08-06", "openai/gpt-4o", "gemini-2.5-pro"} {
+		a := p.CalculateWithOptions(model, 300000, 1000, 5000, nil)
+		b := loaded.CalculateWithOptions(model, 300000, 1000, 5000, nil)
+		if !floatEquals(a.TotalCost, b.TotalCost) || a.TierApplied != b.TierApplied {
+			t.Errorf("%s: loaded pricer differs ($%f %s vs $%f %s)", model, b.TotalCost, b.TierApplied, a.TotalCost, a.TierApplied)
+		}
+	}
+	if a, b := p.CalculateGrounding("gemini-3-pro-preview", 3), loaded.CalculateGrounding("gemini-3-pro-preview", 3); !floatEquals(a, b) {
+		t.Errorf("grounding mismatch: %f vs %f", b, a)
+	}
+	if a, b := p.CalculateCredit("scrapedo", "js_premium"), loaded.CalculateCredit("scrapedo", "js_premium"); a != b {
+		t.Errorf("credit mismatch: %d vs %d", b, a)
+	}
+	if info, ok := loaded.GetModelInfo("deepseek-ai/DeepSeek-V3"); !ok || info.Provider == "" {
+		t.Errorf("expected model provider to survive round trip, got %+v", info)
+	}
+}
+
+func TestExport_RestoresSettings(t *testing.T) {
+	policy := RoundingPolicy{Precision: 2, Mode: RoundCeiling}
+	p, err := NewPricerFromFS(overlayFS(), "configs", WithRoundingPolicy(policy), WithDefaultCacheMultiplier(0.5))
+	if err != nil {
+		t.Fatalf("NewPricerFromFS failed: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := p.Export(&buf); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	data := buf.Bytes()
+
+	loaded, err := NewPricerFromSnapshot(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("NewPricerFromSnapshot failed: %v", err)
+	}
+	if loaded.RoundingPolicy() != policy {
+		t.Errorf("expected rounding policy %+v restored, got %+v", policy, loaded.RoundingPolicy())
+	}
+	if loaded.Version() != p.Version() {
+		t.Errorf("expected version %s, got %s", p.Version(), loaded.Version())
+	}
+	usage := TokenUsage{PromptTokens: 1234, CachedTokens: 1000}
+	if a, b := p.CalculateUsage("small", usage, nil), loaded.CalculateUsage("small", usage, nil); a.TotalCost != b.TotalCost || a.CachedInputCost != b.CachedInputCost {
+		t.Errorf("restored pricer differs: %+v vs %+v", b, a)
+	}
+
+	// Options given to NewPricerFromSnapshot take precedence.
+	loaded, err = NewPricerFromSnapshot(bytes.NewReader(data), WithRoundingPolicy(DefaultRoundingPolicy), WithTaxRate(0.2))
+	if err != nil {
+		t.Fatalf("NewPricerFromSnapshot with options failed: %v", err)
+	}
+	if loaded.RoundingPolicy() != DefaultRoundingPolicy {
+		t.Errorf("expected WithRoundingPolicy to override the snapshot, got %+v", loaded.RoundingPolicy())
+	}
+	if cost := loaded.CalculateUsage("small", TokenUsage{PromptTokens: 1_000_000}, nil); !floatEquals(cost.Tax, 0.2) {
+		t.Errorf("expected WithTaxRate to apply, got tax %f", cost.Tax)
+	}
+	if _, err := NewPricerFromSnapshot(bytes.NewReader(data), WithDefaultCacheMultiplier(2)); err == nil {
+		t.Error("expected an invalid option to be rejected")
+	}
+}
+
+func TestExport_IncludesNamespacedKeys(t *testing.T) {
+	p, err := NewPricer()
+	if err != nil {
+		t.Fatalf("NewPricer failed: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := p.Export(&buf); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+
+	var snap Snapshot
+	if err := json.Unmarshal(buf.Bytes(), &snap); err != nil {
+		t.Fatalf("snapshot is not valid JSON: %v", err)
+	}
+	if snap.Format != SnapshotFormat {
+		t.Errorf("expected format %q, got %q", SnapshotFormat, snap.Format)
+	}
+	if _, ok := snap.Models["anthropic/claude-opus-4-5"]; !ok {
+		t.Error("expected namespaced key anthropic/claude-opus-4-5 in snapshot")
+	}
+	if snap.ModelProviders["gpt-4o"] != "openai" {
+		t.Errorf("expected gpt-4o provider openai, got %q", snap.ModelProviders["gpt-4o"])
+	}
+}
+
+func TestNewPricerFromSnapshot_Errors(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        string
+		errContains string
+	}{
+		{"invalid json", `{bad`, "parse snapshot"},
+		{"wrong format", `{"format": "other/v9", "providers": {"x": {}}}`, "unsupported snapshot format"},
+		{"no providers", `{"format": "pricing_db.snapshot/v1"}`, "no providers"},
+		{"invalid model", `{"format": "pricing_db.snapshot/v1", "providers": {"x": {}}, "models": {"m": {"input_per_million": -1}}}`, "negative input price"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := NewPricerFromSnapshot(strings.NewReader(tc.data))
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			if !strings.Contains(err.Error(), tc.errContains) {
+				t.Errorf("expected error containing %q, got: %v", tc.errContains, err)
+			}
+		})
+	}
+}
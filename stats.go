@@ -0,0 +1,201 @@
+package pricing_db
+
+import (
+	"reflect"
+	"sync/atomic"
+)
+
+// Stats is a point-in-time summary of a Pricer's catalog, for monitoring the
+// pricing subsystem inside a long-running service.
+type Stats struct {
+	Models        int // Token-priced model keys, bare and provider-namespaced
+	Providers     int
+	ImageModels   int
+	RerankModels  int
+	InstanceTypes int
+
+	// PrefixIndexKeys is the number of keys scanned by prefix matching across
+	// the model, image, rerank and grounding indexes. Unknown names cost a scan
+	// of the whole index, so this bounds the worst-case lookup.
+	PrefixIndexKeys int
+
+	// MemoryBytes is a rough estimate of the heap held by the catalog: map
+	// entries, slices and strings, counting shared maps and slices once. It is
+	// meant for trend monitoring, not exact accounting.
+	MemoryBytes int64
+
+	// LookupCacheEntries is the number of names held by the WithLookupCache LRU.
+	LookupCacheEntries int
+
+	// NegativeCacheEntries is the number of unknown names held by the negative cache.
+	NegativeCacheEntries int
+
+	// Lookups holds model lookup counters since the Pricer was created; nil
+	// unless the Pricer was built with WithLookupStats.
+	Lookups *LookupStats
+}
+
+// LookupStats counts model lookups by how they were resolved. A lookup is
+// one model name resolved for a cost calculation.
+type LookupStats struct {
+	ExactHits  uint64 // Resolved by exact key
+	PrefixHits uint64 // Resolved by prefix match (e.g. dated model versions)
+	CacheHits  uint64 // Prefix hits served by the WithLookupCache LRU, included in PrefixHits
+	Misses     uint64 // Unknown models, including those priced by a fallback
+
+	// NegativeHits are misses answered by the negative cache, included in Misses.
+	NegativeHits uint64
+}
+
+// lookupCounters are the live counters behind LookupStats.
+type lookupCounters struct {
+	exact  atomic.Uint64
+	prefix atomic.Uint64
+	cached atomic.Uint64
+	misses atomic.Uint64
+	negHit atomic.Uint64
+}
+
+// newLookupCounters returns counters when enabled, or nil.
+func newLookupCounters(enabled bool) *lookupCounters {
+	if !enabled {
+		return nil
+	}
+	return &lookupCounters{}
+}
+
+// lookupResult identifies how a model name was resolved.
+type lookupResult int
+
+const (
+	lookupExact lookupResult = iota
+	lookupPrefix
+	lookupCached // prefix match served by the lookup cache
+	lookupMiss
+	lookupMissCached // miss served by the negative cache
+)
+
+// record counts one lookup. It is a no-op on a nil receiver, so callers need
+// not check whether counting is enabled.
+func (l *lookupCounters) record(result lookupResult) {
+	if l == nil {
+		return
+	}
+	switch result {
+	case lookupExact:
+		l.exact.Add(1)
+	case lookupPrefix:
+		l.prefix.Add(1)
+	case lookupCached:
+		l.prefix.Add(1)
+		l.cached.Add(1)
+	case lookupMissCached:
+		l.misses.Add(1)
+		l.negHit.Add(1)
+	default:
+		l.misses.Add(1)
+	}
+}
+
+// snapshot returns the current counter values, or nil when counting is disabled.
+func (l *lookupCounters) snapshot() *LookupStats {
+	if l == nil {
+		return nil
+	}
+	return &LookupStats{
+		ExactHits:    l.exact.Load(),
+		PrefixHits:   l.prefix.Load(),
+		CacheHits:    l.cached.Load(),
+		Misses:       l.misses.Load(),
+		NegativeHits: l.negHit.Load(),
+	}
+}
+
+// Stats returns catalog counts, the prefix index size, a memory estimate and,
+// with WithLookupStats, the lookup hit/miss counters. The memory estimate walks
+// the catalog, so call it at monitoring intervals rather than per request.
+func (p *Pricer) Stats() Stats {
+	c := p.load()
+
+	// The lookup caches change under concurrent calls, so they are left out
+	// of the memory walk.
+	static := *c
+	static.resolved, static.unknown = nil, nil
+
+	return Stats{
+		Models:               len(c.models),
+		Providers:            len(c.providers),
+		ImageModels:          len(c.imageModels),
+		RerankModels:         len(c.rerankModels),
+		InstanceTypes:        len(c.instances),
+		PrefixIndexKeys:      len(c.modelKeysSorted) + len(c.imageModelKeysSorted) + len(c.rerankModelKeysSorted) + len(c.groundingKeys),
+		MemoryBytes:          estimateSize(reflect.ValueOf(&static).Elem(), make(map[uintptr]bool)),
+		LookupCacheEntries:   c.resolved.len(),
+		NegativeCacheEntries: c.unknown.len(),
+		Lookups:              c.lookups.snapshot(),
+	}
+}
+
+// mapEntryOverhead approximates the per-entry bookkeeping of a Go map
+// (hash bits, bucket slack) on top of the key and value sizes.
+const mapEntryOverhead = 16
+
+// estimateSize returns the inline size of v plus the heap it references.
+// Maps, slices and pointers already in seen are not counted again.
+func estimateSize(v reflect.Value, seen map[uintptr]bool) int64 {
+	return int64(v.Type().Size()) + referencedSize(v, seen)
+}
+
+// referencedSize returns the heap referenced by v, excluding v itself.
+func referencedSize(v reflect.Value, seen map[uintptr]bool) int64 {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() || seen[v.Pointer()] {
+			return 0
+		}
+		seen[v.Pointer()] = true
+		return estimateSize(v.Elem(), seen)
+	case reflect.Interface:
+		if v.IsNil() {
+			return 0
+		}
+		return estimateSize(v.Elem(), seen)
+	case reflect.String:
+		return int64(v.Len())
+	case reflect.Slice:
+		if v.IsNil() || seen[v.Pointer()] {
+			return 0
+		}
+		seen[v.Pointer()] = true
+		size := int64(v.Cap()) * int64(v.Type().Elem().Size())
+		for i := range v.Len() {
+			size += referencedSize(v.Index(i), seen)
+		}
+		return size
+	case reflect.Map:
+		if v.IsNil() || seen[v.Pointer()] {
+			return 0
+		}
+		seen[v.Pointer()] = true
+		entry := int64(v.Type().Key().Size()+v.Type().Elem().Size()) + mapEntryOverhead
+		size := int64(v.Len()) * entry
+		iter := v.MapRange()
+		for iter.Next() {
+			size += referencedSize(iter.Key(), seen) + referencedSize(iter.Value(), seen)
+		}
+		return size
+	case reflect.Struct:
+		var size int64
+		for i := range v.NumField() {
+			size += referencedSize(v.Field(i), seen)
+		}
+		return size
+	case reflect.Array:
+		var size int64
+		for i := range v.Len() {
+			size += referencedSize(v.Index(i), seen)
+		}
+		return size
+	}
+	return 0
+}
@@ -0,0 +1,84 @@
+package pricing_db
+
+import "testing"
+
+// =============================================================================
+// Stats Tests
+// =============================================================================
+
+func TestStats_Counts(t *testing.T) {
+	p, err := NewPricer()
+	if err != nil {
+		t.Fatalf("NewPricer failed: %v", err)
+	}
+
+	stats := p.Stats()
+	if stats.Models != p.ModelCount() {
+		t.Errorf("expected %d models, got %d", p.ModelCount(), stats.Models)
+	}
+	if stats.Providers != p.ProviderCount() {
+		t.Errorf("expected %d providers, got %d", p.ProviderCount(), stats.Providers)
+	}
+	if stats.ImageModels == 0 || stats.InstanceTypes == 0 {
+		t.Errorf("expected image models and instance types, got %+v", stats)
+	}
+	if stats.PrefixIndexKeys < stats.Models {
+		t.Errorf("prefix index (%d keys) should cover all %d models", stats.PrefixIndexKeys, stats.Models)
+	}
+	if stats.MemoryBytes <= 0 {
+		t.Errorf("expected a positive memory estimate, got %d", stats.MemoryBytes)
+	}
+	if stats.Lookups != nil {
+		t.Errorf("expected nil lookup stats without WithLookupStats, got %+v", stats.Lookups)
+	}
+}
+
+func TestStats_MemoryGrowsWithCatalog(t *testing.T) {
+	small, err := NewPricerFromFS(reloadFS("1.0"), "configs")
+	if err != nil {
+		t.Fatalf("NewPricerFromFS failed: %v", err)
+	}
+	full, err := NewPricer()
+	if err != nil {
+		t.Fatalf("NewPricer failed: %v", err)
+	}
+
+	if s, f := small.Stats().MemoryBytes, full.Stats().MemoryBytes; s >= f {
+		t.Errorf("expected the one-model catalog (%d bytes) to be smaller than the embedded one (%d bytes)", s, f)
+	}
+}
+
+func TestStats_LookupCounters(t *testing.T) {
+	p, err := NewPricerFromFS(reloadFS("1.0"), "configs", WithLookupStats())
+	if err != nil {
+		t.Fatalf("NewPricerFromFS failed: %v", err)
+	}
+
+	p.Calculate("m", 100, 100)
+	p.Calculate("m-2025-01-01", 100, 100)
+	p.CalculateUsage("m", TokenUsage{PromptTokens: 100}, nil)
+	p.Calculate("unknown", 100, 100)
+
+	want := LookupStats{ExactHits: 2, PrefixHits: 1, Misses: 1}
+	if got := p.Stats().Lookups; got == nil || *got != want {
+		t.Fatalf("expected %+v, got %+v", want, got)
+	}
+
+	// Counters survive a reload that keeps WithLookupStats
+	if err := p.Reload(reloadFS("2.0"), "configs", WithLookupStats()); err != nil {
+		t.Fatalf("Reload failed: %v", err)
+	}
+	p.Calculate("m", 100, 100)
+	want.ExactHits++
+	if got := p.Stats().Lookups; got == nil || *got != want {
+		t.Errorf("after reload expected %+v, got %+v", want, got)
+	}
+
+	// Reloading without the option turns counting off
+	if err := p.Reload(reloadFS("2.0"), "configs"); err != nil {
+		t.Fatalf("Reload failed: %v", err)
+	}
+	if got := p.Stats().Lookups; got != nil {
+		t.Errorf("expected nil lookup stats after reload without WithLookupStats, got %+v", got)
+	}
+}
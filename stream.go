@@ -0,0 +1,122 @@
+package pricing_db
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Format identifies the record layout read by PriceStream.
+type Format string
+
+const (
+	// FormatGemini reads Gemini generateContent responses (GeminiResponse).
+	FormatGemini Format = "gemini"
+)
+
+// PriceStream prices a stream of JSON records, such as an NDJSON/JSONL log
+// archive, calling fn with the cost of each record in order. Records are
+// decoded one at a time, so memory use does not grow with the input size.
+// Records may be separated by newlines or any JSON whitespace. Gzip-compressed
+// input is detected from its header and decompressed transparently; wrap zstd
+// archives with decompress.NewReader.
+//
+// Returns nil at end of input. A malformed record stops the stream with an
+// error naming its 1-based index; an error from fn stops it and is returned as is.
+func (p *Pricer) PriceStream(r io.Reader, format Format, fn func(CostDetails) error) error {
+	return p.PriceStreamWithOptions(r, format, nil, fn)
+}
+
+// PriceStreamWithOptions is PriceStream with calculation options (e.g., batch mode)
+// applied to every record.
+func (p *Pricer) PriceStreamWithOptions(r io.Reader, format Format, opts *CalculateOptions, fn func(CostDetails) error) error {
+	var price func(*json.Decoder) (CostDetails, error)
+	switch format {
+	case FormatGemini:
+		price = func(dec *json.Decoder) (CostDetails, error) {
+			var resp GeminiResponse
+			if err := dec.Decode(&resp); err != nil {
+				return CostDetails{}, err
+			}
+			return p.CalculateGeminiResponse(resp, "", opts), nil
+		}
+	default:
+		return fmt.Errorf("unsupported stream format %q", format)
+	}
+
+	r, err := gunzipIfCompressed(r)
+	if err != nil {
+		return fmt.Errorf("decompress stream: %w", err)
+	}
+	dec := json.NewDecoder(r)
+	for record := 1; ; record++ {
+		details, err := price(dec)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("record %d: %w", record, err)
+		}
+		if err := fn(details); err != nil {
+			return err
+		}
+	}
+}
+
+// gunzipIfCompressed returns a reader of r's decompressed content if r starts
+// with a gzip header, and of r unchanged otherwise.
+func gunzipIfCompressed(r io.Reader) (io.Reader, error) {
+	br := bufio.NewReader(r)
+	if head, _ := br.Peek(2); bytes.Equal(head, []byte{0x1f, 0x8b}) {
+		return gzip.NewReader(br)
+	}
+	return br, nil
+}
+
+// ParseMulti prices back-to-back JSON responses, such as the concatenated
+// documents some log shippers write, returning one CostDetails per document
+// in order. Each document's format is detected with DetectResponseProvider, so
+// responses from different providers may be mixed; Gemini responses are priced
+// as by CalculateGeminiResponse, the rest as by CalculateResponse.
+//
+// A malformed or unrecognized document stops parsing with an error naming its
+// 1-based index. Use CalculateResponseTolerant for a single truncated document.
+func (p *Pricer) ParseMulti(jsonData []byte) ([]CostDetails, error) {
+	var results []CostDetails
+	dec := json.NewDecoder(bytes.NewReader(jsonData))
+	for doc := 1; ; doc++ {
+		var raw json.RawMessage
+		err := dec.Decode(&raw)
+		if errors.Is(err, io.EOF) {
+			return results, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("document %d: %w", doc, err)
+		}
+		details, err := p.priceDocument(raw)
+		if err != nil {
+			return nil, fmt.Errorf("document %d: %w", doc, err)
+		}
+		results = append(results, details)
+	}
+}
+
+// priceDocument prices one response of any detected format.
+func (p *Pricer) priceDocument(raw json.RawMessage) (CostDetails, error) {
+	provider, ok := DetectResponseProvider(raw)
+	if !ok {
+		return CostDetails{}, errors.New("unrecognized response format")
+	}
+	if provider == "gemini" {
+		var resp GeminiResponse
+		if err := json.Unmarshal(raw, &resp); err != nil {
+			return CostDetails{}, err
+		}
+		return p.CalculateGeminiResponse(resp, "", nil), nil
+	}
+	return p.CalculateResponse(provider, raw, "", nil)
+}
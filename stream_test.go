@@ -0,0 +1,187 @@
+package pricing_db
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// =============================================================================
+// Streaming Pricer Tests
+// =============================================================================
+
+const geminiNDJSON = `{"modelVersion":"gemini-2.5-flash","usageMetadata":{"promptTokenCount":1000000,"candidatesTokenCount":0}}
+{"modelVersion":"gemini-2.5-flash","usageMetadata":{"promptTokenCount":0,"candidatesTokenCount":1000000}}
+
+{"modelVersion":"unknown-model","usageMetadata":{"promptTokenCount":10}}
+`
+
+func TestPriceStream_Gemini(t *testing.T) {
+	p, err := NewPricer()
+	if err != nil {
+		t.Fatalf("NewPricer failed: %v", err)
+	}
+
+	var got []CostDetails
+	err = p.PriceStream(strings.NewReader(geminiNDJSON), FormatGemini, func(d CostDetails) error {
+		got = append(got, d)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("PriceStream failed: %v", err)
+	}
+	if len(got) != 3 {
+		t.Fatalf("expected 3 records, got %d", len(got))
+	}
+
+	pricing, _ := p.GetPricing("gemini-2.5-flash")
+	if !floatEquals(got[0].TotalCost, pricing.InputPerMillion) || !floatEquals(got[1].TotalCost, pricing.OutputPerMillion) {
+		t.Errorf("unexpected costs: $%f, $%f", got[0].TotalCost, got[1].TotalCost)
+	}
+	if !got[2].Unknown {
+		t.Error("expected third record to be unknown")
+	}
+}
+
+func TestPriceStreamWithOptions_BatchMode(t *testing.T) {
+	p, err := NewPricer()
+	if err != nil {
+		t.Fatalf("NewPricer failed: %v", err)
+	}
+
+	var batchTotal, total float64
+	_ = p.PriceStream(strings.NewReader(geminiNDJSON), FormatGemini, func(d CostDetails) error {
+		total += d.TotalCost
+		return nil
+	})
+	_ = p.PriceStreamWithOptions(strings.NewReader(geminiNDJSON), FormatGemini, &CalculateOptions{BatchMode: true}, func(d CostDetails) error {
+		batchTotal += d.TotalCost
+		return nil
+	})
+	if batchTotal >= total {
+		t.Errorf("expected batch total ($%f) below standard total ($%f)", batchTotal, total)
+	}
+}
+
+func TestPriceStream_Errors(t *testing.T) {
+	p, err := NewPricer()
+	if err != nil {
+		t.Fatalf("NewPricer failed: %v", err)
+	}
+	noop := func(CostDetails) error { return nil }
+
+	err = p.PriceStream(strings.NewReader(`{"modelVersion":"gemini-2.5-flash"}
+{not json}`), FormatGemini, noop)
+	if err == nil || !strings.Contains(err.Error(), "record 2") {
+		t.Errorf("expected error for record 2, got %v", err)
+	}
+
+	err = p.PriceStream(strings.NewReader(`{"modelVersion":"gemini-2.5-fl`), FormatGemini, noop)
+	if err == nil || !strings.Contains(err.Error(), "record 1") {
+		t.Errorf("expected error for truncated record 1, got %v", err)
+	}
+
+	if err := p.PriceStream(strings.NewReader(""), "csv", noop); err == nil || !strings.Contains(err.Error(), "unsupported stream format") {
+		t.Errorf("expected unsupported format error, got %v", err)
+	}
+
+	stop := errors.New("stop")
+	calls := 0
+	err = p.PriceStream(strings.NewReader(geminiNDJSON), FormatGemini, func(CostDetails) error {
+		calls++
+		return stop
+	})
+	if !errors.Is(err, stop) || calls != 1 {
+		t.Errorf("expected callback error after 1 call, got %v after %d calls", err, calls)
+	}
+}
+
+func TestPriceStream_Empty(t *testing.T) {
+	calls := 0
+	if err := PriceStream(strings.NewReader("\n\n"), FormatGemini, func(CostDetails) error { calls++; return nil }); err != nil {
+		t.Errorf("expected nil error for empty stream, got %v", err)
+	}
+	if calls != 0 {
+		t.Errorf("expected no records, got %d", calls)
+	}
+}
+
+func TestParseMulti_MixedProviders(t *testing.T) {
+	p, err := NewPricer()
+	if err != nil {
+		t.Fatalf("NewPricer failed: %v", err)
+	}
+
+	data := []byte(`{"modelVersion":"gemini-2.5-flash","usageMetadata":{"promptTokenCount":1000000}}{"model":"gpt-4o","usage":{"prompt_tokens":1000000,"completion_tokens":0}}
+{"model":"claude-sonnet-4-5","usage":{"input_tokens":0,"output_tokens":1000000}}`)
+	got, err := p.ParseMulti(data)
+	if err != nil {
+		t.Fatalf("ParseMulti failed: %v", err)
+	}
+	if len(got) != 3 {
+		t.Fatalf("expected 3 documents, got %d", len(got))
+	}
+
+	for i, want := range []struct {
+		model string
+		rate  func(ModelPricing) float64
+	}{
+		{"gemini-2.5-flash", func(m ModelPricing) float64 { return m.InputPerMillion }},
+		{"gpt-4o", func(m ModelPricing) float64 { return m.InputPerMillion }},
+		{"claude-sonnet-4-5", func(m ModelPricing) float64 { return m.OutputPerMillion }},
+	} {
+		pricing, _ := p.GetPricing(want.model)
+		if got[i].Unknown || !floatEquals(got[i].TotalCost, want.rate(pricing)) {
+			t.Errorf("document %d: expected $%f for %s, got $%f (unknown=%v)", i+1, want.rate(pricing), want.model, got[i].TotalCost, got[i].Unknown)
+		}
+	}
+}
+
+func TestParseMulti_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{"malformed", `{"model":"gpt-4o","usage":{"prompt_tokens":1}}{not json}`, "document 2"},
+		{"truncated", `{"model":"gpt-4o","usage":{"prompt_tokens":1`, "document 1"},
+		{"unrecognized", `{"model":"gpt-4o","usage":{"prompt_tokens":1}} {"hello":"world"}`, "document 2: unrecognized response format"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseMulti([]byte(tt.data))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestParseMulti_Empty(t *testing.T) {
+	got, err := ParseMulti([]byte(" \n"))
+	if err != nil || len(got) != 0 {
+		t.Errorf("expected no documents and no error, got %d, %v", len(got), err)
+	}
+}
+
+func TestPriceStream_Gzip(t *testing.T) {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	w.Write([]byte(geminiNDJSON))
+	w.Close()
+
+	calls := 0
+	if err := PriceStream(&buf, FormatGemini, func(CostDetails) error { calls++; return nil }); err != nil {
+		t.Fatalf("PriceStream failed: %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("expected 3 records from gzip stream, got %d", calls)
+	}
+
+	err := PriceStream(strings.NewReader("\x1f\x8bnot gzip"), FormatGemini, func(CostDetails) error { return nil })
+	if err == nil || !strings.Contains(err.Error(), "decompress stream") {
+		t.Errorf("expected decompress error, got %v", err)
+	}
+}
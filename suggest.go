@@ -0,0 +1,121 @@
+package pricing_db
+
+import (
+	"cmp"
+	"slices"
+)
+
+// Alternative is a cheaper model suggested by SuggestAlternatives.
+type Alternative struct {
+	Model          string // Catalog model name; Provider + "/" + Model is an unambiguous key
+	Provider       string
+	SameProvider   bool        // From the original model's provider
+	Cost           CostDetails // The usage priced on this model
+	Savings        float64     // Original TotalCost - Cost.TotalCost
+	SavingsPercent float64     // Savings as a percentage of the original TotalCost
+}
+
+// SuggestAlternatives returns models that would price usage below model,
+// cheapest first, for "consider gemini-2.5-flash-lite" hints in dev tooling.
+// It returns nil for an unknown model.
+//
+// Every suggestion fits the request: its context window and max output (when
+// known) hold the usage, it declares any image or audio input the usage
+// contains, it prices output when the usage has any, and it is not deprecated.
+// Models from the original's provider need nothing more. Models from other
+// providers must also match the original's capability metadata: a context
+// window at least as large and every declared input modality. Cross-provider
+// suggestions therefore require context_window on both models.
+func (p *Pricer) SuggestAlternatives(model string, usage TokenUsage) []Alternative {
+	c := p.load()
+
+	key, ok := c.resolveModelKey(model)
+	if !ok {
+		return nil
+	}
+	rates, _ := c.lookupRates(model)
+	var original CostDetails
+	c.calculateUsageInto(&original, rates, model, usage, nil)
+	if original.RawTotal <= 0 {
+		return nil
+	}
+	provider := c.modelProviders[key]
+	current := c.models[key]
+
+	var alternatives []Alternative
+	for providerName, pp := range c.providers {
+		sameProvider := providerName == provider
+		for candidate, pricing := range pp.Models {
+			if sameProvider && candidate == key {
+				continue
+			}
+			if !fitsUsage(pricing, usage) || (!sameProvider && !matchesCapabilities(pricing, current)) {
+				continue
+			}
+			candidateRates, ok := c.lookupRates(providerName + "/" + candidate)
+			if !ok {
+				continue
+			}
+			alt := Alternative{Model: candidate, Provider: providerName, SameProvider: sameProvider}
+			c.calculateUsageInto(&alt.Cost, candidateRates, candidate, usage, nil)
+			if alt.Cost.RawTotal >= original.RawTotal {
+				continue
+			}
+			alt.Savings = c.rounding.round(original.RawTotal - alt.Cost.RawTotal)
+			alt.SavingsPercent = (original.RawTotal - alt.Cost.RawTotal) / original.RawTotal * 100
+			alternatives = append(alternatives, alt)
+		}
+	}
+
+	slices.SortFunc(alternatives, func(a, b Alternative) int {
+		if c := cmp.Compare(a.Cost.RawTotal, b.Cost.RawTotal); c != 0 {
+			return c
+		}
+		if c := cmp.Compare(a.Provider, b.Provider); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Model, b.Model)
+	})
+	return alternatives
+}
+
+// fitsUsage reports whether a model with pricing can serve usage: it is not
+// deprecated, its known limits hold the request, it declares any image or audio
+// input used, and it prices output if the request produces any.
+func fitsUsage(pricing ModelPricing, usage TokenUsage) bool {
+	if pricing.Deprecated || pricing.SunsetDate != "" {
+		return false
+	}
+	input, _ := addInt64Safe(usage.PromptTokens, usage.ToolUseTokens)
+	output, _ := addInt64Safe(usage.CompletionTokens, usage.ThinkingTokens)
+	if total, _ := addInt64Safe(input, output); pricing.ContextWindow > 0 && total > pricing.ContextWindow {
+		return false
+	}
+	if pricing.MaxOutputTokens > 0 && output > pricing.MaxOutputTokens {
+		return false
+	}
+	if output > 0 && pricing.OutputPerMillion <= 0 {
+		return false // e.g., embedding models
+	}
+	if (usage.ImageInputTokens > 0 || usage.ImageCount > 0) && !slices.Contains(pricing.InputModalities, ModalityImage) {
+		return false
+	}
+	if usage.AudioInputTokens > 0 && !slices.Contains(pricing.InputModalities, ModalityAudio) {
+		return false
+	}
+	return true
+}
+
+// matchesCapabilities reports whether candidate's capability metadata covers
+// original's: a context window at least as large and every input modality.
+func matchesCapabilities(candidate, original ModelPricing) bool {
+	if original.ContextWindow <= 0 || candidate.ContextWindow < original.ContextWindow {
+		return false
+	}
+	for _, m := range original.InputModalities {
+		if !slices.Contains(candidate.InputModalities, m) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,87 @@
+package pricing_db
+
+import (
+	"testing"
+)
+
+// =============================================================================
+// SuggestAlternatives Tests
+// =============================================================================
+
+var suggestTestConfigs = map[string]string{
+	"acme_pricing.json": `{
+		"provider": "acme",
+		"models": {
+			"acme-large": {"input_per_million": 10.0, "output_per_million": 40.0, "context_window": 200000, "input_modalities": ["text", "image"]},
+			"acme-small": {"input_per_million": 1.0, "output_per_million": 4.0, "context_window": 8000},
+			"acme-old": {"input_per_million": 0.5, "output_per_million": 1.0, "deprecated": true},
+			"acme-embed": {"input_per_million": 0.1, "output_per_million": 0}
+		}
+	}`,
+	"other_pricing.json": `{
+		"provider": "other",
+		"models": {
+			"other-vision": {"input_per_million": 2.0, "output_per_million": 8.0, "context_window": 400000, "input_modalities": ["text", "image"]},
+			"other-text": {"input_per_million": 0.2, "output_per_million": 0.8, "context_window": 400000},
+			"other-unknown": {"input_per_million": 0.1, "output_per_million": 0.1}
+		}
+	}`,
+}
+
+func TestSuggestAlternatives(t *testing.T) {
+	p := newTestPricer(t, suggestTestConfigs)
+	usage := TokenUsage{PromptTokens: 1000, CompletionTokens: 500}
+	alts := p.SuggestAlternatives("acme-large", usage)
+
+	// acme-small (same provider) and other-vision (matching capabilities), cheapest first.
+	// acme-old is deprecated, acme-embed prices no output, other-text lacks image input,
+	// and other-unknown has no capability metadata.
+	if len(alts) != 2 {
+		t.Fatalf("expected 2 alternatives, got %+v", alts)
+	}
+	if alts[0].Model != "acme-small" || !alts[0].SameProvider || alts[1].Model != "other-vision" || alts[1].Provider != "other" || alts[1].SameProvider {
+		t.Errorf("unexpected alternatives %+v", alts)
+	}
+
+	original := p.CalculateUsage("acme-large", usage, nil)
+	small := alts[0]
+	if !floatEquals(small.Savings, original.TotalCost-small.Cost.TotalCost) || !floatEquals(small.SavingsPercent, 90) {
+		t.Errorf("expected 90%% savings, got %+v", small)
+	}
+}
+
+func TestSuggestAlternatives_FitsUsage(t *testing.T) {
+	p := newTestPricer(t, suggestTestConfigs)
+
+	// Too large for acme-small's 8K context window
+	alts := p.SuggestAlternatives("acme-large", TokenUsage{PromptTokens: 10000, CompletionTokens: 500})
+	if len(alts) != 1 || alts[0].Model != "other-vision" {
+		t.Errorf("expected only other-vision for a 10K-token request, got %+v", alts)
+	}
+
+	// Input-only usage can move to a model without output pricing
+	alts = p.SuggestAlternatives("acme-small", TokenUsage{PromptTokens: 1000})
+	if len(alts) != 2 || alts[0].Model != "acme-embed" || alts[1].Model != "other-text" {
+		t.Errorf("expected acme-embed then other-text for input-only usage, got %+v", alts)
+	}
+
+	// Image input requires a model declaring image input
+	alts = p.SuggestAlternatives("acme-large", TokenUsage{PromptTokens: 1000, CompletionTokens: 100, ImageCount: 1})
+	if len(alts) != 1 || alts[0].Model != "other-vision" {
+		t.Errorf("expected only other-vision for image input, got %+v", alts)
+	}
+}
+
+func TestSuggestAlternatives_NoSuggestions(t *testing.T) {
+	p := newTestPricer(t, suggestTestConfigs)
+	if alts := p.SuggestAlternatives("no-such-model", TokenUsage{PromptTokens: 1000}); alts != nil {
+		t.Errorf("expected nil for an unknown model, got %+v", alts)
+	}
+	if alts := p.SuggestAlternatives("acme-large", TokenUsage{}); alts != nil {
+		t.Errorf("expected nil for zero-cost usage, got %+v", alts)
+	}
+	// Its provider has nothing cheaper, and without a context window it has no cross-provider matches
+	if alts := p.SuggestAlternatives("other-unknown", TokenUsage{PromptTokens: 1000, CompletionTokens: 1000}); len(alts) != 0 {
+		t.Errorf("expected no alternatives to the cheapest model, got %+v", alts)
+	}
+}
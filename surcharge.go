@@ -0,0 +1,163 @@
+package pricing_db
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// CalculateSurcharge computes the USD cost of units of a named surcharge for a model,
+// using the same exact/prefix resolution as Calculate. Batch eligibility is not
+// applied here; see CalculateUsage. Returns false if the model is unknown or has
+// no surcharge with that name.
+func (p *Pricer) CalculateSurcharge(model, name string, units int64) (float64, bool) {
+	c := p.load()
+
+	rates, ok := c.lookupRates(model)
+	if !ok {
+		return 0, false
+	}
+	s, ok := rates.surcharges[name]
+	if !ok {
+		return 0, false
+	}
+	if units <= 0 {
+		return 0, true
+	}
+	return c.rounding.round(float64(units) * s.PricePerUnit), true
+}
+
+// ModelSurcharges returns every surcharge that applies to a model, keyed by name:
+// grounding entries plus provider- and model-level surcharges.
+// The returned map is a copy.
+func (p *Pricer) ModelSurcharges(model string) (map[string]Surcharge, bool) {
+	c := p.load()
+
+	rates, ok := c.lookupRates(model)
+	if !ok {
+		return nil, false
+	}
+	return maps.Clone(rates.surcharges), true
+}
+
+// resolveSurcharges attaches each model's surcharges to its compiled rates.
+// Later sources override earlier ones by name: grounding (by model prefix),
+// provider surcharges, then model surcharges.
+// The model's batch_features then set batch eligibility by name.
+// groundingKeys must already be sorted.
+func (c *catalog) resolveSurcharges() {
+	for key, r := range c.rates {
+		c.resolveModelSurcharges(key, r)
+	}
+}
+
+// resolveModelSurcharges attaches the surcharges of the model at key to r
+// and its price history; see resolveSurcharges.
+func (c *catalog) resolveModelSurcharges(key string, r *modelRates) {
+	provider := c.modelProviders[key]
+	pp := c.providers[provider]
+
+	var out map[string]Surcharge
+	add := func(name string, s Surcharge) {
+		if out == nil {
+			out = make(map[string]Surcharge)
+		}
+		out[name] = s
+	}
+	if g, ok := findByPrefix(strings.TrimPrefix(key, provider+"/"), c.groundingKeys, c.grounding); ok {
+		add(SurchargeGrounding, Surcharge{
+			Unit:         "query",
+			PricePerUnit: g.PerThousandQueries / queriesPerThousand,
+			BatchOK:      groundingBatchOK(r.pricing),
+		})
+	}
+	for name, s := range pp.Surcharges {
+		add(name, s)
+	}
+	for name, s := range r.pricing.Surcharges {
+		add(name, s)
+	}
+	for name, ok := range r.pricing.BatchFeatures {
+		if s, exists := out[name]; exists {
+			s.BatchOK = ok
+			out[name] = s
+		}
+	}
+	r.surcharges = out
+	for _, h := range r.history {
+		h.rates.surcharges = out
+	}
+}
+
+// validateBatchFeatures checks, once surcharges are resolved, that every
+// batch_features key names a surcharge its model has, so a misspelled feature
+// ("web_serach") fails the load instead of being ignored.
+func (c *catalog) validateBatchFeatures() error {
+	for _, key := range slices.Sorted(maps.Keys(c.rates)) {
+		r := c.rates[key]
+		for _, name := range slices.Sorted(maps.Keys(r.pricing.BatchFeatures)) {
+			if _, ok := r.surcharges[name]; !ok {
+				known := "none"
+				if len(r.surcharges) > 0 {
+					known = strings.Join(slices.Sorted(maps.Keys(r.surcharges)), ", ")
+				}
+				return fmt.Errorf("model %q (provider %s): batch_features names unknown surcharge %q (has: %s)", key, r.provider, name, known)
+			}
+		}
+	}
+	return nil
+}
+
+// surchargeCost bills named surcharge units against the model's surcharges.
+// Names the model does not price, and fees not available in batch mode, are
+// excluded with a warning.
+func (r *modelRates) surchargeCost(dst *CostDetails, model string, units map[string]int64, batchMode bool) float64 {
+	if len(units) == 0 {
+		return 0
+	}
+
+	var total float64
+	for _, name := range slices.Sorted(maps.Keys(units)) {
+		n := units[name]
+		if n <= 0 {
+			continue
+		}
+		s, ok := r.surcharges[name]
+		switch {
+		case !ok:
+			addWarning(dst, WarningSurchargeUnknown, fmt.Sprintf("model %q has no %q surcharge - not billed", model, name))
+		case batchMode && !s.BatchOK:
+			addWarning(dst, WarningBatchSurchargeExcluded, fmt.Sprintf("%s surcharge not supported in batch mode - cost excluded", name))
+		default:
+			total += float64(n) * s.PricePerUnit
+		}
+	}
+	return total
+}
+
+// groundingBatchOK reports whether grounding is available in batch mode for a
+// model: its batch_features entry, else batch_grounding_ok.
+func groundingBatchOK(pricing ModelPricing) bool {
+	if ok, set := pricing.BatchFeatures[SurchargeGrounding]; set {
+		return ok
+	}
+	return pricing.BatchGroundingOK
+}
+
+// validateSurcharges checks for unnamed surcharges, missing units and negative prices.
+func validateSurcharges(surcharges map[string]Surcharge, context, filename string) error {
+	for _, name := range slices.Sorted(maps.Keys(surcharges)) {
+		s := surcharges[name]
+		if name == "" {
+			return fmt.Errorf("%s: %s has a surcharge with an empty name", filename, context)
+		}
+		if s.Unit == "" {
+			return fmt.Errorf("%s: %s surcharge %q has no unit", filename, context, name)
+		}
+		if err := validateNonNegative(s.PricePerUnit, "price_per_unit", fmt.Sprintf("%s surcharge %q", context, name), filename); err != nil {
+			return err
+		}
+	}
+	return nil
+}
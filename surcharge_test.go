@@ -0,0 +1,236 @@
+package pricing_db
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// =============================================================================
+// Surcharge Tests
+// =============================================================================
+
+var surchargeTestConfigs = map[string]string{
+	"acme_pricing.json": `{
+		"provider": "acme",
+		"models": {
+			"acme-1": {
+				"input_per_million": 1.0,
+				"output_per_million": 2.0,
+				"batch_multiplier": 0.5,
+				"surcharges": {"safety": {"unit": "request", "price_per_unit": 0.01, "batch_ok": true}}
+			},
+			"acme-2": {
+				"input_per_million": 1.0,
+				"output_per_million": 2.0,
+				"surcharges": {"citations": {"unit": "citation", "price_per_unit": 0.001}}
+			}
+		},
+		"surcharges": {
+			"search": {"unit": "source", "price_per_unit": 0.01},
+			"citations": {"unit": "citation", "price_per_unit": 0.002}
+		}
+	}`,
+}
+
+func TestModelSurcharges_Resolution(t *testing.T) {
+	p := newTestPricer(t, surchargeTestConfigs)
+
+	got, ok := p.ModelSurcharges("acme-1")
+	if !ok {
+		t.Fatal("expected acme-1 to be found")
+	}
+	if len(got) != 3 {
+		t.Errorf("expected search, citations and safety surcharges, got %v", got)
+	}
+	if got[SurchargeSearch].Unit != "source" || !floatEquals(got[SurchargeSearch].PricePerUnit, 0.01) {
+		t.Errorf("expected the provider's per-source search surcharge, got %+v", got[SurchargeSearch])
+	}
+	if !floatEquals(got["citations"].PricePerUnit, 0.002) {
+		t.Errorf("expected provider citations price $0.002, got $%f", got["citations"].PricePerUnit)
+	}
+
+	// Model-level surcharges override provider-level ones by name
+	got, _ = p.ModelSurcharges("acme-2")
+	if !floatEquals(got["citations"].PricePerUnit, 0.001) {
+		t.Errorf("expected model citations price $0.001, got $%f", got["citations"].PricePerUnit)
+	}
+
+	// Returned map is a copy
+	got["citations"] = Surcharge{Unit: "citation", PricePerUnit: 99}
+	if again, _ := p.ModelSurcharges("acme-2"); !floatEquals(again["citations"].PricePerUnit, 0.001) {
+		t.Error("ModelSurcharges should return a copy")
+	}
+
+	if _, ok := p.ModelSurcharges("unknown-model"); ok {
+		t.Error("expected unknown model to report false")
+	}
+}
+
+func TestCalculateUsage_Surcharges(t *testing.T) {
+	p := newTestPricer(t, surchargeTestConfigs)
+
+	usage := TokenUsage{
+		PromptTokens:     1000,
+		CompletionTokens: 500,
+		Surcharges:       map[string]int64{"citations": 5, "safety": 1, "search": 20},
+	}
+	details := p.CalculateUsage("acme-1", usage, nil)
+	// 5*0.002 + 1*0.01 + 20*0.01 = 0.22
+	if !floatEquals(details.SurchargeCost, 0.22) {
+		t.Errorf("expected surcharge cost $0.22, got $%f", details.SurchargeCost)
+	}
+	if !floatEquals(details.TotalCost, 0.222) {
+		t.Errorf("expected total $0.222, got $%f", details.TotalCost)
+	}
+	if len(details.WarningDetails) != 0 {
+		t.Errorf("expected no warnings, got %v", details.Warnings)
+	}
+
+	// Batch mode bills only batch-eligible surcharges, undiscounted
+	details = p.CalculateUsage("acme-1", usage, &CalculateOptions{BatchMode: true})
+	if !floatEquals(details.SurchargeCost, 0.01) {
+		t.Errorf("expected only the safety surcharge ($0.01) in batch, got $%f", details.SurchargeCost)
+	}
+	if !hasWarningCode(details.WarningDetails, WarningBatchSurchargeExcluded) {
+		t.Errorf("expected batch_surcharge_excluded warning, got %v", details.Warnings)
+	}
+
+	// Unknown surcharge names are not billed
+	details = p.CalculateUsage("acme-2", TokenUsage{Surcharges: map[string]int64{"safety": 3, "citations": -1}}, nil)
+	if details.SurchargeCost != 0 {
+		t.Errorf("expected no surcharge cost, got $%f", details.SurchargeCost)
+	}
+	if !hasWarningCode(details.WarningDetails, WarningSurchargeUnknown) || len(details.WarningDetails) != 1 {
+		t.Errorf("expected a single surcharge_unknown warning, got %v", details.Warnings)
+	}
+}
+
+func TestCalculateSurcharge(t *testing.T) {
+	p, err := NewPricer()
+	if err != nil {
+		t.Fatalf("NewPricer failed: %v", err)
+	}
+
+	// Grounding is exposed as a surcharge and agrees with CalculateGrounding
+	cost, ok := p.CalculateSurcharge("gemini-3-pro-preview", SurchargeGrounding, 5)
+	if !ok {
+		t.Fatal("expected grounding surcharge for gemini-3-pro-preview")
+	}
+	if want := p.CalculateGrounding("gemini-3-pro-preview", 5); !floatEquals(cost, want) {
+		t.Errorf("expected $%f, got $%f", want, cost)
+	}
+	if cost, ok = p.CalculateSurcharge("google/gemini-3-pro-preview", SurchargeGrounding, 5); !ok || cost == 0 {
+		t.Errorf("expected grounding for provider-namespaced key, got (%f, %v)", cost, ok)
+	}
+
+	// xAI Live Search via the provider's search surcharge
+	if cost, ok = p.CalculateSurcharge("grok-4", SurchargeSearch, 12); !ok || !floatEquals(cost, 0.3) {
+		t.Errorf("expected (0.30, true) for grok-4 search, got (%f, %v)", cost, ok)
+	}
+
+	if _, ok = p.CalculateSurcharge("gpt-4o", SurchargeGrounding, 1); ok {
+		t.Error("expected no grounding surcharge for gpt-4o")
+	}
+	if _, ok = p.CalculateSurcharge("unknown-model", SurchargeSearch, 1); ok {
+		t.Error("expected unknown model to report false")
+	}
+}
+
+func TestCalculateUsage_GroundingSurchargeName(t *testing.T) {
+	p, err := NewPricer()
+	if err != nil {
+		t.Fatalf("NewPricer failed: %v", err)
+	}
+
+	viaField := p.CalculateUsage("gemini-3-pro-preview", TokenUsage{GroundingQueries: 4}, nil)
+	viaName := p.CalculateUsage("gemini-3-pro-preview", TokenUsage{Surcharges: map[string]int64{SurchargeGrounding: 4}}, nil)
+	if !floatEquals(viaField.GroundingCost, viaName.SurchargeCost) || !floatEquals(viaField.TotalCost, viaName.TotalCost) {
+		t.Errorf("GroundingQueries ($%f) and Surcharges[grounding] ($%f) should price the same", viaField.TotalCost, viaName.TotalCost)
+	}
+
+	both := p.CalculateUsage("gemini-3-pro-preview", TokenUsage{GroundingQueries: 4, Surcharges: map[string]int64{SurchargeGrounding: 4}}, nil)
+	if !floatEquals(both.TotalCost, viaField.TotalCost) || both.SurchargeCost != 0 {
+		t.Errorf("grounding set both ways should bill once: expected $%f, got $%f (surcharge $%f)", viaField.TotalCost, both.TotalCost, both.SurchargeCost)
+	}
+	if !hasWarningCode(both.WarningDetails, WarningUsageMismatch) {
+		t.Errorf("expected %s warning, got %+v", WarningUsageMismatch, both.Warnings)
+	}
+}
+
+func TestNewPricerFromFS_UnknownBatchFeature(t *testing.T) {
+	fsys := fstest.MapFS{
+		"configs/acme_pricing.json": &fstest.MapFile{Data: []byte(`{
+			"provider": "acme",
+			"models": {
+				"acme-batch": {
+					"input_per_million": 1.0,
+					"output_per_million": 2.0,
+					"batch_features": {"web_serach": false}
+				}
+			},
+			"surcharges": {"web_search": {"unit": "request", "price_per_unit": 0.01}}
+		}`)},
+	}
+	_, err := NewPricerFromFS(fsys, "configs")
+	if err == nil || !strings.Contains(err.Error(), `unknown surcharge "web_serach" (has: web_search)`) {
+		t.Errorf("expected unknown batch_features error, got %v", err)
+	}
+}
+
+func TestCalculateUsage_BatchFeatures(t *testing.T) {
+	p := newTestPricer(t, map[string]string{
+		"acme_pricing.json": `{
+			"provider": "acme",
+			"models": {
+				"acme-batch": {
+					"input_per_million": 1.0,
+					"output_per_million": 2.0,
+					"batch_multiplier": 0.5,
+					"batch_features": {"grounding": true, "web_search": false, "code_execution": true},
+					"surcharges": {"code_execution": {"unit": "session", "price_per_unit": 0.03}}
+				}
+			},
+			"grounding": {"acme-batch": {"per_thousand_queries": 35.0, "billing_model": "per_query"}},
+			"surcharges": {"web_search": {"unit": "request", "price_per_unit": 0.01, "batch_ok": true}}
+		}`,
+	})
+
+	surcharges, _ := p.ModelSurcharges("acme-batch")
+	if !surcharges[SurchargeGrounding].BatchOK || surcharges[SurchargeWebSearch].BatchOK || !surcharges["code_execution"].BatchOK {
+		t.Errorf("expected batch_features to set batch eligibility, got %+v", surcharges)
+	}
+
+	usage := TokenUsage{
+		GroundingQueries: 10,
+		Surcharges:       map[string]int64{SurchargeWebSearch: 5, "code_execution": 2},
+	}
+	details := p.CalculateUsage("acme-batch", usage, &CalculateOptions{BatchMode: true})
+	// Grounding (10 * $0.035) and code execution (2 * $0.03) are batch-eligible; web search is not
+	if !floatEquals(details.GroundingCost, 0.35) {
+		t.Errorf("expected grounding cost $0.35 in batch, got $%f", details.GroundingCost)
+	}
+	if !floatEquals(details.SurchargeCost, 0.06) {
+		t.Errorf("expected only code execution ($0.06) in batch, got $%f", details.SurchargeCost)
+	}
+	if len(details.WarningDetails) != 1 || details.WarningDetails[0].Code != WarningBatchSurchargeExcluded ||
+		!strings.Contains(details.WarningDetails[0].Message, SurchargeWebSearch) {
+		t.Errorf("expected a single web_search batch exclusion warning, got %v", details.Warnings)
+	}
+}
+
+func TestNewPricerFromFS_InvalidSurcharges(t *testing.T) {
+	tests := map[string]string{
+		"negative model price": `"models": {"m": {"input_per_million": 1, "output_per_million": 1, "surcharges": {"x": {"unit": "request", "price_per_unit": -1}}}}`,
+		"missing unit":         `"models": {"m": {"input_per_million": 1, "output_per_million": 1, "surcharges": {"x": {"price_per_unit": 1}}}}`,
+		"empty provider name":  `"models": {"m": {"input_per_million": 1, "output_per_million": 1}}, "surcharges": {"": {"unit": "request", "price_per_unit": 1}}`,
+	}
+	for name, body := range tests {
+		fsys := fstest.MapFS{
+			"configs/bad_pricing.json": &fstest.MapFile{Data: []byte(`{` + body + `}`)},
+		}
+		if _, err := NewPricerFromFS(fsys, "configs"); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package pricing_db
+
+import "unicode/utf8"
+
+// TokenCounter returns the number of tokens text encodes to for model.
+// See WithTokenCounter.
+type TokenCounter func(model, text string) int64
+
+// ApproxTokenCount estimates the tokens in text without a tokenizer: one token per
+// 4 ASCII bytes (the usual rule of thumb for English with BPE tokenizers) plus one
+// per non-ASCII character, since CJK and other scripts tokenize far more densely.
+// Expect roughly ±25% against real tokenizers; use WithTokenCounter for exact counts.
+func ApproxTokenCount(text string) int64 {
+	var ascii, other int64
+	for _, r := range text {
+		if r < utf8.RuneSelf {
+			ascii++
+		} else {
+			other++
+		}
+	}
+	return (ascii+3)/4 + other
+}
+
+// CountTokens returns the tokens in text for model, using the Pricer's
+// TokenCounter or ApproxTokenCount if none was set.
+func (p *Pricer) CountTokens(model, text string) int64 {
+	return p.load().countTokens(model, text)
+}
+
+func (c *catalog) countTokens(model, text string) int64 {
+	if c.tokenCounter != nil {
+		return max(c.tokenCounter(model, text), 0)
+	}
+	return ApproxTokenCount(text)
+}
+
+// EstimateTextCost estimates the cost of sending prompt to model and receiving
+// outputTokens tokens, as a pre-flight check before running a job. Prompt tokens
+// are counted with CountTokens; the result is priced like CalculateUsage.
+func (p *Pricer) EstimateTextCost(model, prompt string, outputTokens int64, opts *CalculateOptions) CostDetails {
+	c := p.load()
+	rates, _ := c.lookupRates(model)
+
+	var details CostDetails
+	c.calculateUsageInto(&details, rates, model, TokenUsage{
+		PromptTokens:     c.countTokens(model, prompt),
+		CompletionTokens: outputTokens,
+	}, opts)
+	return details
+}
@@ -0,0 +1,76 @@
+package pricing_db
+
+import (
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// =============================================================================
+// Token Estimation Tests
+// =============================================================================
+
+func TestApproxTokenCount(t *testing.T) {
+	tests := []struct {
+		text string
+		want int64
+	}{
+		{"", 0},
+		{"a", 1},
+		{"abcd", 1},
+		{"abcde", 2},
+		{strings.Repeat("x", 4000), 1000},
+		{"日本語", 3},
+		{"hi 日本", 3}, // 3 ASCII bytes -> 1, plus 2 non-ASCII
+	}
+	for _, tt := range tests {
+		if got := ApproxTokenCount(tt.text); got != tt.want {
+			t.Errorf("ApproxTokenCount(%q) = %d, want %d", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestEstimateTextCost(t *testing.T) {
+	p, err := NewPricer()
+	if err != nil {
+		t.Fatalf("NewPricer failed: %v", err)
+	}
+
+	prompt := strings.Repeat("x", 4_000_000) // ~1M tokens
+	got := p.EstimateTextCost("gpt-4o", prompt, 1_000_000, nil)
+	want := p.CalculateUsage("gpt-4o", TokenUsage{PromptTokens: 1_000_000, CompletionTokens: 1_000_000}, nil)
+	if got.Unknown || !floatEquals(got.TotalCost, want.TotalCost) {
+		t.Errorf("expected $%f, got $%f (unknown=%v)", want.TotalCost, got.TotalCost, got.Unknown)
+	}
+
+	if got := p.EstimateTextCost("no-such-model", "hello", 10, nil); !got.Unknown {
+		t.Error("expected Unknown for unknown model")
+	}
+}
+
+func TestWithTokenCounter(t *testing.T) {
+	var calls atomic.Int64
+	counter := func(model, text string) int64 {
+		calls.Add(1)
+		if model != "gpt-4o" {
+			t.Errorf("counter got model %q", model)
+		}
+		return int64(len(strings.Fields(text))) * 1_000_000
+	}
+	p, err := NewPricerFromFS(EmbeddedConfigFS(), "configs", WithTokenCounter(counter))
+	if err != nil {
+		t.Fatalf("NewPricerFromFS failed: %v", err)
+	}
+
+	if got := p.CountTokens("gpt-4o", "one two"); got != 2_000_000 {
+		t.Errorf("expected custom count 2000000, got %d", got)
+	}
+	got := p.EstimateTextCost("gpt-4o", "one", 0, nil)
+	want := p.CalculateUsage("gpt-4o", TokenUsage{PromptTokens: 1_000_000}, nil)
+	if !floatEquals(got.TotalCost, want.TotalCost) {
+		t.Errorf("expected $%f, got $%f", want.TotalCost, got.TotalCost)
+	}
+	if calls.Load() != 2 {
+		t.Errorf("expected 2 counter calls, got %d", calls.Load())
+	}
+}
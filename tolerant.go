@@ -0,0 +1,106 @@
+package pricing_db
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// CalculateResponseTolerant is CalculateResponse for responses taken from
+// real-world logs, which may be truncated or several documents run together.
+// Well-formed responses are priced exactly as by CalculateResponse (Gemini
+// responses as by CalculateGeminiResponse, including grounding). Otherwise the
+// document is scanned for its usage block ("usageMetadata" for gemini/google,
+// "usage" for the others) and its model name, and the last complete usage block
+// is priced with a WarningMalformedResponse warning: streamed chunks carry
+// cumulative usage, so the last one is the total. Fields outside the usage
+// block, such as Gemini grounding queries or Responses API tool calls, are not
+// recovered.
+//
+// Returns an error only for an unsupported provider or when no complete usage
+// block can be found.
+func (p *Pricer) CalculateResponseTolerant(provider string, jsonData []byte, modelOverride string, opts *CalculateOptions) (CostDetails, error) {
+	provider = strings.ToLower(provider)
+	if _, ok := usageParsers[provider]; !ok {
+		return CostDetails{}, fmt.Errorf("no usage mapping for provider %q", provider)
+	}
+
+	usageKey, modelKey := "usage", "model"
+	if provider == "gemini" || provider == "google" {
+		usageKey, modelKey = "usageMetadata", "modelVersion"
+		var resp GeminiResponse
+		if err := json.Unmarshal(jsonData, &resp); err == nil {
+			return p.CalculateGeminiResponse(resp, modelOverride, opts), nil
+		}
+	} else if json.Valid(jsonData) {
+		return p.CalculateResponse(provider, jsonData, modelOverride, opts)
+	}
+
+	raw, ok := scanLastObject(jsonData, usageKey)
+	if !ok {
+		return CostDetails{}, fmt.Errorf("parse %s response: no complete %q block in malformed document", provider, usageKey)
+	}
+	usage, err := UsageFromJSON(provider, raw)
+	if err != nil {
+		return CostDetails{}, err
+	}
+	model := modelOverride
+	if model == "" {
+		model = scanFirstString(jsonData, modelKey)
+	}
+
+	details := p.CalculateUsage(model, usage, opts)
+	addWarning(&details, WarningMalformedResponse,
+		fmt.Sprintf("malformed %s response - cost recovered from its %q block", provider, usageKey))
+	return details, nil
+}
+
+// scanValues calls fn with the decoder positioned at each value of a "key"
+// member in data, which need not be valid JSON, until fn returns false.
+func scanValues(data []byte, key string, fn func(*json.Decoder) bool) {
+	needle := []byte(`"` + key + `"`)
+	for i := 0; ; {
+		j := bytes.Index(data[i:], needle)
+		if j < 0 {
+			return
+		}
+		i += j + len(needle)
+		rest := bytes.TrimLeft(data[i:], " \t\r\n")
+		if len(rest) == 0 || rest[0] != ':' {
+			continue // the key's text used as a value, not a member name
+		}
+		if !fn(json.NewDecoder(bytes.NewReader(rest[1:]))) {
+			return
+		}
+	}
+}
+
+// scanLastObject returns the last complete JSON object that is the value of a
+// "key" member in data.
+func scanLastObject(data []byte, key string) (json.RawMessage, bool) {
+	var last json.RawMessage
+	scanValues(data, key, func(dec *json.Decoder) bool {
+		var raw json.RawMessage
+		if dec.Decode(&raw) == nil && len(raw) > 0 && raw[0] == '{' {
+			last = raw
+		}
+		return true
+	})
+	return last, last != nil
+}
+
+// scanFirstString returns the first complete string value of a "key" member
+// in data, or "" if there is none.
+func scanFirstString(data []byte, key string) string {
+	var value string
+	scanValues(data, key, func(dec *json.Decoder) bool {
+		var s string
+		if dec.Decode(&s) == nil {
+			value = s
+			return false
+		}
+		return true
+	})
+	return value
+}
@@ -0,0 +1,134 @@
+package pricing_db
+
+import (
+	"testing"
+)
+
+// =============================================================================
+// Tolerant Response Parser Tests
+// =============================================================================
+
+func newTolerantPricer(t *testing.T) *Pricer {
+	t.Helper()
+	p, err := NewPricerFromFS(overlayFS(), "configs")
+	if err != nil {
+		t.Fatalf("NewPricerFromFS failed: %v", err)
+	}
+	return p
+}
+
+func TestCalculateResponseTolerant_WellFormed(t *testing.T) {
+	p := newTolerantPricer(t)
+	resp := []byte(`{"model": "large", "usage": {"prompt_tokens": 1000000, "completion_tokens": 100000}}`)
+
+	cost, err := p.CalculateResponseTolerant("openai", resp, "", nil)
+	if err != nil {
+		t.Fatalf("CalculateResponseTolerant failed: %v", err)
+	}
+	if !floatEquals(cost.TotalCost, 13.0) {
+		t.Errorf("expected $13.00, got $%f", cost.TotalCost)
+	}
+	if hasWarningCode(cost.WarningDetails, WarningMalformedResponse) {
+		t.Error("expected no malformed_response warning for a well-formed response")
+	}
+}
+
+func TestCalculateResponseTolerant_Truncated(t *testing.T) {
+	p := newTolerantPricer(t)
+	// Usage arrives before the document is cut off mid-choices
+	resp := []byte(`{"id": "x", "model": "large", "usage": {"prompt_tokens": 1000000, "completion_tokens": 100000}, "choices": [{"message": {"content": "he`)
+
+	cost, err := p.CalculateResponseTolerant("openai", resp, "", nil)
+	if err != nil {
+		t.Fatalf("CalculateResponseTolerant failed: %v", err)
+	}
+	if cost.Unknown || !floatEquals(cost.TotalCost, 13.0) {
+		t.Errorf("expected $13.00, got $%f (unknown=%v)", cost.TotalCost, cost.Unknown)
+	}
+	if !hasWarningCode(cost.WarningDetails, WarningMalformedResponse) {
+		t.Errorf("expected malformed_response warning, got %v", cost.WarningDetails)
+	}
+}
+
+func TestCalculateResponseTolerant_ConcatenatedGemini(t *testing.T) {
+	p := newTolerantPricer(t)
+	// Streamed chunks carry cumulative usage; the last complete block wins,
+	// and a truncated trailing block is ignored.
+	resp := []byte(`{"modelVersion": "small", "usageMetadata": {"promptTokenCount": 1000000, "candidatesTokenCount": 10}}` +
+		`{"modelVersion": "small", "usageMetadata": {"promptTokenCount": 1000000, "candidatesTokenCount": 500000}}` +
+		`{"modelVersion": "small", "usageMetadata": {"promptTokenCount": 10`)
+
+	cost, err := p.CalculateResponseTolerant("gemini", resp, "", nil)
+	if err != nil {
+		t.Fatalf("CalculateResponseTolerant failed: %v", err)
+	}
+	if !floatEquals(cost.TotalCost, 2.0) {
+		t.Errorf("expected $2.00, got $%f", cost.TotalCost)
+	}
+	if !hasWarningCode(cost.WarningDetails, WarningMalformedResponse) {
+		t.Errorf("expected malformed_response warning, got %v", cost.WarningDetails)
+	}
+}
+
+func TestCalculateResponseTolerant_ModelOverride(t *testing.T) {
+	p := newTolerantPricer(t)
+	resp := []byte(`{"usage": {"prompt_tokens": 1000000}} trailing garbage`)
+
+	cost, err := p.CalculateResponseTolerant("openai", resp, "small", nil)
+	if err != nil {
+		t.Fatalf("CalculateResponseTolerant failed: %v", err)
+	}
+	if cost.Unknown || !floatEquals(cost.TotalCost, 1.0) {
+		t.Errorf("expected $1.00, got $%f (unknown=%v)", cost.TotalCost, cost.Unknown)
+	}
+}
+
+func TestCalculateResponseTolerant_Errors(t *testing.T) {
+	p := newTolerantPricer(t)
+	tests := []struct {
+		name     string
+		provider string
+		data     string
+	}{
+		{"unknown provider", "nope", `{}`},
+		{"no usage", "openai", `{"model": "large", "choices": [`},
+		{"usage truncated", "openai", `{"model": "large", "usage": {"prompt_tokens": 10`},
+		{"usage as value", "openai", `{"note": "usage": 5`},
+		{"usage not object", "openai", `{"usage": 5, "x":`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := p.CalculateResponseTolerant(tt.provider, []byte(tt.data), "", nil); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestParseOpenAIResponseTolerant(t *testing.T) {
+	resp := []byte(`{"model": "gpt-4o", "usage": {"prompt_tokens": 1000, "completion_tokens": 100}}{"model": "gpt-4o"`)
+	cost, err := ParseOpenAIResponseTolerant(resp)
+	if err != nil {
+		t.Fatalf("ParseOpenAIResponseTolerant failed: %v", err)
+	}
+	if cost.Unknown || cost.TotalCost <= 0 {
+		t.Errorf("expected a priced gpt-4o cost, got $%f (unknown=%v)", cost.TotalCost, cost.Unknown)
+	}
+}
+
+func FuzzCalculateResponseTolerant(f *testing.F) {
+	f.Add("openai", []byte(`{"model": "large", "usage": {"prompt_tokens": 10, "completion_tokens": 5}}`))
+	f.Add("gemini", []byte(`{"modelVersion": "small", "usageMetadata": {"promptTokenCount": 10}}{"usageMetadata": {`))
+	f.Add("anthropic", []byte(`{"model": "large", "usage": {"input_tokens": 10`))
+	p, err := NewPricerFromFS(overlayFS(), "configs")
+	if err != nil {
+		f.Fatalf("NewPricerFromFS failed: %v", err)
+	}
+
+	f.Fuzz(func(t *testing.T, provider string, data []byte) {
+		cost, err := p.CalculateResponseTolerant(provider, data, "", nil)
+		if err == nil && cost.TotalCost < 0 {
+			t.Errorf("negative cost %f", cost.TotalCost)
+		}
+	})
+}
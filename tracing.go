@@ -0,0 +1,103 @@
+package pricing_db
+
+import (
+	"context"
+	"log/slog"
+)
+
+// Tracer starts a span around each token-cost calculation. The core package
+// has no tracing dependency; pricingotel adapts an OpenTelemetry tracer.
+type Tracer interface {
+	// StartSpan starts a span named name (e.g. "pricing.CalculateUsage") as a
+	// child of any span in ctx.
+	StartSpan(ctx context.Context, name string, attrs ...slog.Attr) Span
+}
+
+// Span is a span started by a Tracer.
+type Span interface {
+	// End records the calculation's result attributes and ends the span.
+	End(attrs ...slog.Attr)
+}
+
+// WithTracer traces Calculate, CalculateUsage (and the Calculate*, Parse*
+// and CalculateResponse methods built on it), CalculateRealtimeSession and
+// CalculateBatchUsage (and CalculateBatchUsageParallel). Spans carry the model
+// and token counts, and end with the total cost and whether the model was
+// unknown. The CalculateContext, CalculateUsageContext,
+// CalculateRealtimeSessionContext and CalculateBatchUsageContext variants
+// parent their span on the span in ctx; the others start from
+// context.Background.
+//
+// Not traced: CalculateAt, and the flat-rate lookups (CalculateImage,
+// CalculateImageWithOptions, CalculateGrounding, CalculateSearch,
+// CalculateSurcharge, CalculateRerank, CalculateCredit, CalculateEndpointHours
+// and CalculateReplicateRun), which are a map read and a multiply.
+func WithTracer(t Tracer) Option {
+	return func(o *pricerOptions) {
+		o.tracer = t
+	}
+}
+
+// CalculateContext is Calculate with its span a child of the span in ctx.
+func (p *Pricer) CalculateContext(ctx context.Context, model string, inputTokens, outputTokens int64) Cost {
+	return p.load().calculateTraced(ctx, model, inputTokens, outputTokens)
+}
+
+// CalculateUsageContext is CalculateUsage with its span a child of the span
+// in ctx, so calculations show up inside request traces.
+func (p *Pricer) CalculateUsageContext(ctx context.Context, model string, usage TokenUsage, opts *CalculateOptions) CostDetails {
+	var details CostDetails
+	p.load().calculateUsageTraced(ctx, &details, model, usage, opts)
+	return details
+}
+
+// CalculateRealtimeSessionContext is CalculateRealtimeSession with its span a
+// child of the span in ctx.
+func (p *Pricer) CalculateRealtimeSessionContext(ctx context.Context, model string, usage RealtimeUsage) CostDetails {
+	return p.load().calculateRealtimeSessionTraced(ctx, model, usage)
+}
+
+// CalculateBatchUsageContext is CalculateBatchUsage with its span a child of
+// the span in ctx.
+func (p *Pricer) CalculateBatchUsageContext(ctx context.Context, records []UsageRecord) []CostDetails {
+	return p.load().calculateBatchTraced(ctx, records)
+}
+
+// startSpan starts a span for a calculation on model. Callers check c.tracer
+// first, so untraced calls do not build the attributes.
+func (c *catalog) startSpan(ctx context.Context, name, model string, attrs ...slog.Attr) Span {
+	return c.tracer.StartSpan(ctx, name, append([]slog.Attr{slog.String("model", model)}, attrs...)...)
+}
+
+// endSpan ends span, if any, with a calculation's result.
+func endSpan(span Span, totalCost float64, unknown bool) {
+	if span != nil {
+		span.End(slog.Float64("total_cost", totalCost), slog.Bool("unknown", unknown))
+	}
+}
+
+// startBatchSpan starts a span for a CalculateBatchUsage call, or returns nil
+// without a tracer.
+func (c *catalog) startBatchSpan(ctx context.Context, records int) Span {
+	if c.tracer == nil {
+		return nil
+	}
+	return c.tracer.StartSpan(ctx, "pricing.CalculateBatchUsage", slog.Int("records", records))
+}
+
+// endBatchSpan ends span, if any, with the batch's summed cost and the number
+// of unknown models.
+func endBatchSpan(span Span, results []CostDetails) {
+	if span == nil {
+		return
+	}
+	var total float64
+	unknown := 0
+	for _, r := range results {
+		total += r.TotalCost
+		if r.Unknown {
+			unknown++
+		}
+	}
+	span.End(slog.Float64("total_cost", total), slog.Int("unknown", unknown))
+}
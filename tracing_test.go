@@ -0,0 +1,154 @@
+package pricing_db
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"testing"
+)
+
+// =============================================================================
+// Tracing Tests
+// =============================================================================
+
+type recordedSpan struct {
+	ctx   context.Context
+	name  string
+	attrs map[string]slog.Value
+	ended bool
+}
+
+type recordingTracer struct {
+	mu    sync.Mutex
+	spans []*recordedSpan
+}
+
+func (t *recordingTracer) StartSpan(ctx context.Context, name string, attrs ...slog.Attr) Span {
+	s := &recordedSpan{ctx: ctx, name: name, attrs: make(map[string]slog.Value)}
+	for _, a := range attrs {
+		s.attrs[a.Key] = a.Value
+	}
+	t.mu.Lock()
+	t.spans = append(t.spans, s)
+	t.mu.Unlock()
+	return s
+}
+
+func (s *recordedSpan) End(attrs ...slog.Attr) {
+	for _, a := range attrs {
+		s.attrs[a.Key] = a.Value
+	}
+	s.ended = true
+}
+
+func newTracedPricer(t *testing.T) (*Pricer, *recordingTracer) {
+	t.Helper()
+	tracer := &recordingTracer{}
+	p, err := NewPricerFromFS(reloadFS("1.0"), "configs", WithTracer(tracer))
+	if err != nil {
+		t.Fatalf("NewPricerFromFS failed: %v", err)
+	}
+	return p, tracer
+}
+
+func TestWithTracer_CalculateUsage(t *testing.T) {
+	p, tracer := newTracedPricer(t)
+	cost := p.CalculateUsage("m", TokenUsage{PromptTokens: 1000, CompletionTokens: 500}, &CalculateOptions{BatchMode: true})
+
+	if len(tracer.spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(tracer.spans))
+	}
+	s := tracer.spans[0]
+	if s.name != "pricing.CalculateUsage" || !s.ended {
+		t.Errorf("expected ended pricing.CalculateUsage span, got %q (ended %v)", s.name, s.ended)
+	}
+	if got := s.attrs["model"].String(); got != "m" {
+		t.Errorf("expected model m, got %q", got)
+	}
+	if got := s.attrs["input_tokens"].Int64(); got != 1000 {
+		t.Errorf("expected input_tokens 1000, got %d", got)
+	}
+	if !s.attrs["batch_mode"].Bool() {
+		t.Error("expected batch_mode true")
+	}
+	if got := s.attrs["total_cost"].Float64(); !floatEquals(got, cost.TotalCost) {
+		t.Errorf("expected total_cost %f, got %f", cost.TotalCost, got)
+	}
+}
+
+func TestWithTracer_SpanPerCall(t *testing.T) {
+	p, tracer := newTracedPricer(t)
+	p.Calculate("mystery", 10, 10)
+	p.CalculateRealtimeSession("m", RealtimeUsage{TextInputTokens: 10, AudioOutputTokens: 5})
+	p.CalculateBatchUsage([]UsageRecord{{Model: "m"}, {Model: "mystery"}})
+
+	want := []string{"pricing.Calculate", "pricing.CalculateRealtimeSession", "pricing.CalculateBatchUsage"}
+	if len(tracer.spans) != len(want) {
+		t.Fatalf("expected %d spans, got %d", len(want), len(tracer.spans))
+	}
+	for i, name := range want {
+		if tracer.spans[i].name != name {
+			t.Errorf("span %d: expected %s, got %s", i, name, tracer.spans[i].name)
+		}
+	}
+	if !tracer.spans[0].attrs["unknown"].Bool() {
+		t.Error("expected unknown model to be flagged on the Calculate span")
+	}
+	if got := tracer.spans[1].attrs["output_tokens"].Int64(); got != 5 {
+		t.Errorf("expected realtime output_tokens 5, got %d", got)
+	}
+	batch := tracer.spans[2].attrs
+	if batch["records"].Int64() != 2 || batch["unknown"].Int64() != 1 {
+		t.Errorf("expected batch records=2 unknown=1, got records=%d unknown=%d", batch["records"].Int64(), batch["unknown"].Int64())
+	}
+}
+
+func TestContextVariants(t *testing.T) {
+	p, tracer := newTracedPricer(t)
+	type ctxKey struct{}
+	ctx := context.WithValue(context.Background(), ctxKey{}, "request")
+
+	p.CalculateContext(ctx, "m", 10, 10)
+	p.CalculateUsageContext(ctx, "m", TokenUsage{PromptTokens: 10}, nil)
+	p.CalculateRealtimeSessionContext(ctx, "m", RealtimeUsage{TextInputTokens: 10})
+	p.CalculateBatchUsageContext(ctx, []UsageRecord{{Model: "m", Usage: TokenUsage{PromptTokens: 10}}})
+
+	want := []string{"pricing.Calculate", "pricing.CalculateUsage", "pricing.CalculateRealtimeSession", "pricing.CalculateBatchUsage"}
+	if len(tracer.spans) != len(want) {
+		t.Fatalf("expected %d spans, got %d", len(want), len(tracer.spans))
+	}
+	for i, s := range tracer.spans {
+		if s.name != want[i] || !s.ended {
+			t.Errorf("span %d: expected ended %s, got %q (ended %v)", i, want[i], s.name, s.ended)
+		}
+		if got := s.ctx.Value(ctxKey{}); got != "request" {
+			t.Errorf("span %d: expected span started from request context, got %v", i, got)
+		}
+	}
+}
+
+func TestCalculateUsageContext_FollowsReload(t *testing.T) {
+	p, _ := newTracedPricer(t)
+	if err := p.Reload(reloadFS("2.0"), "configs"); err != nil {
+		t.Fatalf("Reload failed: %v", err)
+	}
+	cost := p.CalculateUsageContext(context.Background(), "m", TokenUsage{PromptTokens: 1_000_000}, nil)
+	if !floatEquals(cost.TotalCost, 2.0) {
+		t.Errorf("expected reloaded rate 2.0, got %f", cost.TotalCost)
+	}
+}
+
+func TestCalculate_BackgroundSpanContext(t *testing.T) {
+	p, tracer := newTracedPricer(t)
+	p.Calculate("m", 10, 10)
+	p.CalculateBatchUsage([]UsageRecord{{Model: "m", Usage: TokenUsage{PromptTokens: 10}}})
+
+	if len(tracer.spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(tracer.spans))
+	}
+	for i, s := range tracer.spans {
+		if s.ctx != context.Background() {
+			t.Errorf("span %d (%s): expected a background parent context, got %v", i, s.name, s.ctx)
+		}
+	}
+}
@@ -3,45 +3,117 @@
 // and Google grounding costs. Configuration is embedded via go:embed for portability.
 //
 // Thread Safety: The Pricer type is safe for concurrent use by multiple goroutines.
-// All public methods use a read-write mutex to protect internal state.
+// Reads are lock-free: each call loads an immutable catalog through an atomic
+// pointer, and Pricer.Reload swaps in a new catalog.
 package pricing_db
 
-import "fmt"
+import (
+	"slices"
 
-// BatchCacheRule defines how batch and cache discounts interact
-type BatchCacheRule string
+	"github.com/ai8future/pricing_db/pricingtypes"
+)
 
-const (
-	// BatchCacheStack means discounts multiply: cached_batch = cache_mult * batch_mult
-	// Used by Anthropic and OpenAI: e.g., 10% cache * 50% batch = 5% of standard
-	BatchCacheStack BatchCacheRule = "stack"
+// Request and result types live in the pricingtypes package, which has no embedded
+// config data; they are aliased here so both import paths name the same types.
+type (
+	BatchCacheRule   = pricingtypes.BatchCacheRule
+	TierMode         = pricingtypes.TierMode
+	TierBasis        = pricingtypes.TierBasis
+	ReasoningEffort  = pricingtypes.ReasoningEffort
+	Modality         = pricingtypes.Modality
+	ModelPricing     = pricingtypes.ModelPricing
+	PricingTier      = pricingtypes.PricingTier
+	PricePeriod      = pricingtypes.PricePeriod
+	Surcharge        = pricingtypes.Surcharge
+	CacheProfile     = pricingtypes.CacheProfile
+	WarningCode      = pricingtypes.WarningCode
+	Warning          = pricingtypes.Warning
+	Cost             = pricingtypes.Cost
+	TokenUsage       = pricingtypes.TokenUsage
+	ModalityTokens   = pricingtypes.ModalityTokens
+	CostDetails      = pricingtypes.CostDetails
+	RealtimeUsage    = pricingtypes.RealtimeUsage
+	CalculateOptions = pricingtypes.CalculateOptions
+)
 
-	// BatchCachePrecedence means cache discount takes precedence, batch doesn't apply to cached tokens
-	// Used by Gemini: cached tokens get 10% rate regardless of batch mode
-	BatchCachePrecedence BatchCacheRule = "cache_precedence"
+const (
+	BatchCacheStack                  = pricingtypes.BatchCacheStack
+	BatchCachePrecedence             = pricingtypes.BatchCachePrecedence
+	TierModeCliff                    = pricingtypes.TierModeCliff
+	TierModeMarginal                 = pricingtypes.TierModeMarginal
+	TierBasisInput                   = pricingtypes.TierBasisInput
+	TierBasisOutput                  = pricingtypes.TierBasisOutput
+	TierBasisCombined                = pricingtypes.TierBasisCombined
+	ReasoningLow                     = pricingtypes.ReasoningLow
+	ReasoningMedium                  = pricingtypes.ReasoningMedium
+	ReasoningHigh                    = pricingtypes.ReasoningHigh
+	ModalityText                     = pricingtypes.ModalityText
+	ModalityImage                    = pricingtypes.ModalityImage
+	ModalityAudio                    = pricingtypes.ModalityAudio
+	ModalityVideo                    = pricingtypes.ModalityVideo
+	SurchargeGrounding               = pricingtypes.SurchargeGrounding
+	SurchargeSearch                  = pricingtypes.SurchargeSearch
+	SurchargeWebSearch               = pricingtypes.SurchargeWebSearch
+	SurchargeFileSearch              = pricingtypes.SurchargeFileSearch
+	WarningTokenOverflow             = pricingtypes.WarningTokenOverflow
+	WarningCachedTokensClamped       = pricingtypes.WarningCachedTokensClamped
+	WarningBatchGroundingExcluded    = pricingtypes.WarningBatchGroundingExcluded
+	WarningAudioRateMissing          = pricingtypes.WarningAudioRateMissing
+	WarningReasoningHeuristicMissing = pricingtypes.WarningReasoningHeuristicMissing
+	WarningDeprecatedModel           = pricingtypes.WarningDeprecatedModel
+	WarningMinimumBilled             = pricingtypes.WarningMinimumBilled
+	WarningUsageMismatch             = pricingtypes.WarningUsageMismatch
+	WarningCacheProfileMissing       = pricingtypes.WarningCacheProfileMissing
+	WarningSurchargeUnknown          = pricingtypes.WarningSurchargeUnknown
+	WarningBatchSurchargeExcluded    = pricingtypes.WarningBatchSurchargeExcluded
+	WarningStalePricing              = pricingtypes.WarningStalePricing
+	WarningFallbackPricing           = pricingtypes.WarningFallbackPricing
+	WarningFallbackApplied           = pricingtypes.WarningFallbackApplied
+	WarningCreditLow                 = pricingtypes.WarningCreditLow
+	WarningCreditExpiring            = pricingtypes.WarningCreditExpiring
+	WarningCreditExhausted           = pricingtypes.WarningCreditExhausted
+	WarningMalformedResponse         = pricingtypes.WarningMalformedResponse
 )
 
-// ModelPricing holds per-token costs for a model (in USD per million tokens)
-type ModelPricing struct {
-	InputPerMillion     float64        `json:"input_per_million"`
-	OutputPerMillion    float64        `json:"output_per_million"`
-	Tiers               []PricingTier  `json:"tiers,omitempty"`
-	CacheReadMultiplier float64        `json:"cache_read_multiplier,omitempty"`
-	BatchMultiplier     float64        `json:"batch_multiplier,omitempty"`
-	BatchCacheRule      BatchCacheRule `json:"batch_cache_rule,omitempty"`
-	// AudioInputPerMillion is metadata-only: the per-million rate for audio input tokens.
-	// This value is NOT used in cost calculations by this library. Callers needing audio
-	// pricing should use this value directly with their own audio token counts.
-	// Stored for reference and future API expansion.
-	AudioInputPerMillion float64 `json:"audio_input_per_million,omitempty"`
-	BatchGroundingOK     bool    `json:"batch_grounding_ok,omitempty"` // false = grounding not supported in batch
-}
-
-// PricingTier defines pricing for a specific token threshold (e.g., >200K tokens)
-type PricingTier struct {
-	ThresholdTokens  int64   `json:"threshold_tokens"`
-	InputPerMillion  float64 `json:"input_per_million"`
-	OutputPerMillion float64 `json:"output_per_million"`
+// ModelInfo describes a model's pricing and capabilities, as returned by GetModelInfo.
+// ModelPricing is embedded so price and metadata fields can be read side by side
+// (e.g., info.InputPerMillion, info.ContextWindow).
+type ModelInfo struct {
+	Model    string // Catalog key the lookup resolved to (differs from the request on prefix match)
+	Provider string // Provider whose pricing the key resolves to
+	ModelPricing
+}
+
+// ImageModelInfo is an image model's pricing and provider, as listed by
+// ListImageModels and ListImageModelsByProvider.
+type ImageModelInfo struct {
+	Model    string // Model name as configured by the provider, e.g. "dall-e-3-1024"
+	Provider string
+	ImageModelPricing
+}
+
+// SupportsInput reports whether the model accepts the given input modality.
+func (mi ModelInfo) SupportsInput(m Modality) bool {
+	return slices.Contains(mi.InputModalities, m)
+}
+
+// SupportsOutput reports whether the model can produce the given output modality.
+func (mi ModelInfo) SupportsOutput(m Modality) bool {
+	return slices.Contains(mi.OutputModalities, m)
+}
+
+// SelfHostedPricing describes a model served on your own hardware (e.g., Ollama or
+// vLLM). The replica's hourly cost is spread over its sustained throughput to derive
+// per-million token rates, so self-hosted usage is priced like API usage.
+type SelfHostedPricing struct {
+	GPUHourUSD            float64 `json:"gpu_hour_usd,omitempty"`            // Rental or reserved price per GPU-hour
+	GPUCount              int     `json:"gpu_count,omitempty"`               // GPUs per replica (default 1)
+	HardwareUSD           float64 `json:"hardware_usd,omitempty"`            // Purchase price of owned hardware
+	AmortizationMonths    int     `json:"amortization_months,omitempty"`     // Months to amortize HardwareUSD over
+	PowerWatts            float64 `json:"power_watts,omitempty"`             // Average power draw of the replica
+	ElectricityUSDPerKWh  float64 `json:"electricity_usd_per_kwh,omitempty"` // Electricity price
+	InputTokensPerSecond  float64 `json:"input_tokens_per_second"`           // Sustained prefill throughput
+	OutputTokensPerSecond float64 `json:"output_tokens_per_second"`          // Sustained decode throughput
 }
 
 // GroundingPricing holds cost per 1000 queries for Google grounding
@@ -50,70 +122,113 @@ type GroundingPricing struct {
 	BillingModel       string  `json:"billing_model"` // "per_query" or "per_prompt"
 }
 
-// CreditMultiplier defines multipliers for credit-based pricing
+// CreditMultiplier is the fixed set of multipliers CreditPricing held before
+// Multipliers became a map keyed by name.
+//
+// Deprecated: Use CreditPricing.Multipliers; Map converts existing values.
 type CreditMultiplier struct {
 	JSRendering  int `json:"js_rendering,omitempty"`
 	PremiumProxy int `json:"premium_proxy,omitempty"`
 	JSPremium    int `json:"js_premium,omitempty"`
 }
 
+// Map returns m as a CreditPricing.Multipliers map, omitting zero multipliers
+// as the JSON encoding does.
+func (m CreditMultiplier) Map() map[string]int {
+	out := make(map[string]int, 3)
+	if m.JSRendering != 0 {
+		out["js_rendering"] = m.JSRendering
+	}
+	if m.PremiumProxy != 0 {
+		out["premium_proxy"] = m.PremiumProxy
+	}
+	if m.JSPremium != 0 {
+		out["js_premium"] = m.JSPremium
+	}
+	return out
+}
+
 // CreditPricing holds credit-based pricing info for non-AI providers
 type CreditPricing struct {
-	BaseCostPerRequest int              `json:"base_cost_per_request"`
-	Multipliers        CreditMultiplier `json:"multipliers,omitempty"`
+	BaseCostPerRequest int `json:"base_cost_per_request"`
+	// Multipliers maps a request feature name (e.g. "js_rendering", "premium_proxy")
+	// to the factor applied to BaseCostPerRequest when that feature is used.
+	Multipliers map[string]int `json:"multipliers,omitempty"`
 }
 
+// ImagePricingMode selects how an image model is billed.
+type ImagePricingMode string
+
+const (
+	// ImagePerImage bills PricePerImage (or a resolution tier) per image. It is the default.
+	ImagePerImage ImagePricingMode = "per_image"
+	// ImagePerMegapixel bills PricePerMegapixel per million output pixels.
+	ImagePerMegapixel ImagePricingMode = "per_megapixel"
+	// ImagePerStep bills PricePerStep per diffusion step.
+	ImagePerStep ImagePricingMode = "per_step"
+)
+
 // ImageModelPricing holds per-image costs for image generation models (in USD per image)
 type ImageModelPricing struct {
+	// Mode is how the model is billed; empty means ImagePerImage.
+	Mode          ImagePricingMode `json:"mode,omitempty"`
+	PricePerImage float64          `json:"price_per_image,omitempty"` // Omitted by entries that set DefaultResolution or another mode
+	// Resolutions optionally prices by output size and quality from a single model key.
+	// PricePerImage is used when no resolution tier applies.
+	Resolutions []ImageResolutionPricing `json:"resolutions,omitempty"`
+	// PricePerMegapixel is the ImagePerMegapixel rate (USD per 1,000,000 pixels).
+	PricePerMegapixel float64 `json:"price_per_megapixel,omitempty"`
+	// PricePerStep and DefaultSteps are the ImagePerStep rate and the step
+	// count billed when a request does not set ImageOptions.Steps.
+	PricePerStep float64 `json:"price_per_step,omitempty"`
+	DefaultSteps int     `json:"default_steps,omitempty"`
+	// ExactMatch keeps prefix matching from resolving other names to this key,
+	// e.g. "dall-e-3-1024-hd", so it is priced only when requested by name.
+	ExactMatch bool `json:"exact_match,omitempty"`
+	// DefaultResolution names the image model of the same provider this key is
+	// priced as, e.g. "nano-banana-pro" at "nano-banana-pro-1k". Such an entry
+	// sets no prices of its own; the loaded entry carries the target's prices.
+	DefaultResolution string `json:"default_resolution,omitempty"`
+}
+
+// ImageResolutionPricing defines the per-image price for a specific size and quality.
+// An empty Quality matches any requested quality.
+type ImageResolutionPricing struct {
+	Width         int     `json:"width"`
+	Height        int     `json:"height"`
+	Quality       string  `json:"quality,omitempty"` // e.g., "standard", "hd"
 	PricePerImage float64 `json:"price_per_image"`
 }
 
-// SubscriptionTier defines a subscription plan
-type SubscriptionTier struct {
-	Credits  int     `json:"credits"`
-	PriceUSD float64 `json:"price_usd"`
+// ImageOptions describes an image generation request for CalculateImageWithOptions.
+type ImageOptions struct {
+	Width   int
+	Height  int
+	Quality string // Defaults to "standard" when empty
+	Count   int
+	Steps   int // Diffusion steps for ImagePerStep models; the model's DefaultSteps when 0
 }
 
-// Cost represents the calculated cost breakdown for token-based pricing
-type Cost struct {
-	Model        string
-	InputTokens  int64
-	OutputTokens int64
-	InputCost    float64
-	OutputCost   float64
-	TotalCost    float64
-	Unknown      bool // true if model not found in pricing data
+// RerankPricing holds the cost of rerank models, billed per search (USD per 1000 searches).
+type RerankPricing struct {
+	PerThousandSearches float64 `json:"per_thousand_searches"`
 }
 
-// TokenUsage holds detailed token breakdown for complex calculations.
-// This struct is defined for future API expansion to support a unified interface
-// across providers.
-//
-// TODO: Currently unused. Provider-specific structs like GeminiUsageMetadata are
-// used directly. TokenUsage may be used in future versions to provide a
-// normalized view of token usage across all providers.
-type TokenUsage struct {
-	PromptTokens     int64 // Standard input tokens
-	CompletionTokens int64 // Standard output tokens
-	CachedTokens     int64 // Tokens served from cache (subset of input)
-	ThinkingTokens   int64 // Charged at OUTPUT rate
-	ToolUseTokens    int64 // Part of input (already in PromptTokens for Google)
-	GroundingQueries int   // Google search queries
-}
-
-// CostDetails provides detailed cost breakdown for complex calculations
-type CostDetails struct {
-	StandardInputCost float64
-	CachedInputCost   float64
-	OutputCost        float64
-	ThinkingCost      float64
-	GroundingCost     float64
-	TierApplied       string
-	BatchDiscount     float64
-	TotalCost         float64
-	BatchMode         bool     // Whether batch pricing was applied
-	Warnings          []string // Warnings about unsupported features in batch mode
-	Unknown           bool     // Whether the model was not found
+// InstancePricing holds the hourly price of a dedicated inference instance
+// (e.g., a Hugging Face Inference Endpoint), billed while it runs regardless of traffic.
+// Hardware billed by the second, such as Replicate's, sets PerSecondUSD instead.
+type InstancePricing struct {
+	HourlyUSD        float64 `json:"hourly_usd,omitempty"`
+	PerSecondUSD     float64 `json:"per_second_usd,omitempty"`
+	Accelerator      string  `json:"accelerator,omitempty"`       // e.g., "nvidia-a10g"
+	AcceleratorCount int     `json:"accelerator_count,omitempty"` // Accelerators (or vCPUs) per instance
+	Vendor           string  `json:"vendor,omitempty"`            // Cloud the instance runs on, e.g. "aws"
+}
+
+// SubscriptionTier defines a subscription plan
+type SubscriptionTier struct {
+	Credits  int     `json:"credits"`
+	PriceUSD float64 `json:"price_usd"`
 }
 
 // GeminiUsageMetadata matches the usage_metadata structure from Gemini API responses
@@ -123,11 +238,18 @@ type GeminiUsageMetadata struct {
 	CachedContentTokenCount int64 `json:"cachedContentTokenCount,omitempty"`
 	ToolUsePromptTokenCount int64 `json:"toolUsePromptTokenCount,omitempty"`
 	ThoughtsTokenCount      int64 `json:"thoughtsTokenCount,omitempty"`
+	// TotalTokenCount is the provider's own total, used only to flag metering anomalies
+	TotalTokenCount int64 `json:"totalTokenCount,omitempty"`
+
+	// Per-modality breakdowns (TEXT/IMAGE/AUDIO/VIDEO) reported by newer responses
+	PromptTokensDetails     []GeminiModalityTokenCount `json:"promptTokensDetails,omitempty"`
+	CandidatesTokensDetails []GeminiModalityTokenCount `json:"candidatesTokensDetails,omitempty"`
 }
 
-// CalculateOptions provides options for cost calculations
-type CalculateOptions struct {
-	BatchMode bool // Apply batch discount (typically 50%)
+// GeminiModalityTokenCount is one entry of a Gemini per-modality token breakdown.
+type GeminiModalityTokenCount struct {
+	Modality   string `json:"modality"` // "TEXT", "IMAGE", "AUDIO", "VIDEO", "DOCUMENT"
+	TokenCount int64  `json:"tokenCount"`
 }
 
 // GeminiResponse represents a full Gemini API response.
@@ -143,6 +265,8 @@ type GeminiCandidate struct {
 	Content           GeminiContent            `json:"content"`
 	FinishReason      string                   `json:"finishReason"`
 	GroundingMetadata *GeminiGroundingMetadata `json:"groundingMetadata,omitempty"`
+	Index             int                      `json:"index,omitempty"`
+	TokenCount        int64                    `json:"tokenCount,omitempty"` // Output tokens for this candidate (newer responses)
 }
 
 // GeminiContent represents the content of a Gemini response.
@@ -162,13 +286,39 @@ type GeminiGroundingMetadata struct {
 	WebSearchQueries []string `json:"webSearchQueries,omitempty"`
 }
 
-// Format returns a human-readable cost breakdown
-func (c Cost) Format() string {
-	if c.Unknown {
-		return fmt.Sprintf("Cost: unknown (model %q not in pricing data)", c.Model)
-	}
-	return fmt.Sprintf("Input: $%.4f (%d tokens) | Output: $%.4f (%d tokens) | Total: $%.4f",
-		c.InputCost, c.InputTokens, c.OutputCost, c.OutputTokens, c.TotalCost)
+// AnthropicUsage matches the usage object of an Anthropic Messages API response.
+// InputTokens already include the system prompt, tool definitions, and the
+// tool-use system prompt Anthropic adds when tools are present, so they need no
+// separate accounting; cache reads and writes are reported separately.
+type AnthropicUsage struct {
+	InputTokens              int64                   `json:"input_tokens"`
+	OutputTokens             int64                   `json:"output_tokens"`
+	CacheReadInputTokens     int64                   `json:"cache_read_input_tokens,omitempty"`
+	CacheCreationInputTokens int64                   `json:"cache_creation_input_tokens,omitempty"`
+	ServerToolUse            *AnthropicServerToolUse `json:"server_tool_use,omitempty"`
+}
+
+// AnthropicServerToolUse counts server-side tool calls Anthropic bills per
+// request on top of tokens.
+type AnthropicServerToolUse struct {
+	WebSearchRequests int64 `json:"web_search_requests"` // Billed as the "web_search" surcharge
+	WebFetchRequests  int64 `json:"web_fetch_requests"`  // No per-request fee; fetched content is billed as input tokens
+}
+
+// GeminiLiveUsageMetadata matches the usageMetadata of a Gemini Live API
+// (bidiGenerateContent) server message. Unlike generateContent, output is
+// reported as responseTokenCount, and cached tokens get their own modality split.
+type GeminiLiveUsageMetadata struct {
+	PromptTokenCount        int64 `json:"promptTokenCount"`
+	CachedContentTokenCount int64 `json:"cachedContentTokenCount,omitempty"` // Included in PromptTokenCount
+	ResponseTokenCount      int64 `json:"responseTokenCount"`
+	ToolUsePromptTokenCount int64 `json:"toolUsePromptTokenCount,omitempty"`
+	ThoughtsTokenCount      int64 `json:"thoughtsTokenCount,omitempty"`
+	TotalTokenCount         int64 `json:"totalTokenCount,omitempty"`
+
+	PromptTokensDetails   []GeminiModalityTokenCount `json:"promptTokensDetails,omitempty"`
+	CacheTokensDetails    []GeminiModalityTokenCount `json:"cacheTokensDetails,omitempty"`
+	ResponseTokensDetails []GeminiModalityTokenCount `json:"responseTokensDetails,omitempty"`
 }
 
 // PricingMetadata contains source and update information for pricing data.
@@ -180,27 +330,45 @@ type PricingMetadata struct {
 }
 
 // ProviderPricing holds all pricing data for a single provider.
-// Supports token-based (Models), grounding (Grounding), credit-based (CreditPricing),
-// and image-based (ImageModels) pricing.
+// Supports token-based (Models), grounding (Grounding), surcharges (Surcharges), credit-based (CreditPricing),
+// image-based (ImageModels), per-search rerank (RerankModels), and per-hour instance (InstanceTypes) pricing.
 type ProviderPricing struct {
 	Provider          string                       `json:"provider"`
-	BillingType       string                       `json:"billing_type,omitempty"` // "token", "credit", or "image"
+	BillingType       string                       `json:"billing_type,omitempty"` // "token", "credit", "image", "instance", or "self_hosted"
 	Models            map[string]ModelPricing      `json:"models,omitempty"`
 	ImageModels       map[string]ImageModelPricing `json:"image_models,omitempty"`
 	Grounding         map[string]GroundingPricing  `json:"grounding,omitempty"`
+	Surcharges        map[string]Surcharge         `json:"surcharges,omitempty"`         // Apply to all of the provider's models
+	SelfHostedModels  map[string]SelfHostedPricing `json:"self_hosted_models,omitempty"` // Also in Models, as derived token rates
 	CreditPricing     *CreditPricing               `json:"credit_pricing,omitempty"`
 	SubscriptionTiers map[string]SubscriptionTier  `json:"subscription_tiers,omitempty"`
+	RerankModels      map[string]RerankPricing     `json:"rerank_models,omitempty"`
+	InstanceTypes     map[string]InstancePricing   `json:"instance_types,omitempty"`
+	ModelHardware     map[string]string            `json:"model_hardware,omitempty"` // Model -> default instance type, for per-second runs
 	Metadata          PricingMetadata              `json:"metadata,omitempty"`
+	// DefaultCacheReadMultiplier is applied to this provider's models that omit
+	// cache_read_multiplier (already filled into Models).
+	DefaultCacheReadMultiplier float64 `json:"default_cache_read_multiplier,omitempty"`
+	// SourceFile is the config file the provider was loaded from (e.g., "openai_pricing.json").
+	SourceFile string `json:"source_file,omitempty"`
 }
 
 // pricingFile represents the JSON structure (supports all formats)
 type pricingFile struct {
+	SchemaVersion     int                          `json:"schema_version,omitempty"` // 0 = v1; see CurrentSchemaVersion
 	Provider          string                       `json:"provider,omitempty"`
 	BillingType       string                       `json:"billing_type,omitempty"`
 	Models            map[string]ModelPricing      `json:"models,omitempty"`
 	ImageModels       map[string]ImageModelPricing `json:"image_models,omitempty"`
 	Grounding         map[string]GroundingPricing  `json:"grounding,omitempty"`
+	Surcharges        map[string]Surcharge         `json:"surcharges,omitempty"`         // Apply to all of the provider's models
+	SelfHostedModels  map[string]SelfHostedPricing `json:"self_hosted_models,omitempty"` // Also in Models, as derived token rates
 	CreditPricing     *CreditPricing               `json:"credit_pricing,omitempty"`
 	SubscriptionTiers map[string]SubscriptionTier  `json:"subscription_tiers,omitempty"`
+	RerankModels      map[string]RerankPricing     `json:"rerank_models,omitempty"`
+	InstanceTypes     map[string]InstancePricing   `json:"instance_types,omitempty"`
+	ModelHardware     map[string]string            `json:"model_hardware,omitempty"` // Model -> default instance type, for per-second runs
 	Metadata          PricingMetadata              `json:"metadata,omitempty"`
+	// DefaultCacheReadMultiplier applies to models in this file without cache_read_multiplier
+	DefaultCacheReadMultiplier float64 `json:"default_cache_read_multiplier,omitempty"`
 }
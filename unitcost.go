@@ -0,0 +1,94 @@
+package pricing_db
+
+// tokensPerThousand is the divisor for per-1,000-token unit costs.
+const tokensPerThousand = 1000
+
+// UnitRates are a model's standard-tier prices in USD per 1,000 tokens.
+type UnitRates struct {
+	Input       float64
+	CachedInput float64 // Input served from the prompt cache
+	Output      float64
+}
+
+// Blended returns the price per 1,000 tokens of a traffic mix in which
+// inputShare (0-1) of the tokens are uncached input and the rest output.
+// For the common 3:1 input-to-output blend, use Blended(0.75).
+func (r UnitRates) Blended(inputShare float64) float64 {
+	return r.Input*inputShare + r.Output*(1-inputShare)
+}
+
+// UnitEconomics normalizes the cost of a set of requests.
+type UnitEconomics struct {
+	Requests              int
+	Tokens                int64   // Input, output, and thinking tokens
+	TotalCost             float64 // Unknown models count as 0
+	CostPerRequest        float64
+	CostPerThousandTokens float64 // Blended over all Tokens
+}
+
+// CostPerThousandTokens returns model's standard-tier (below any tier
+// threshold) input, cached input, and output prices per 1,000 tokens.
+func (p *Pricer) CostPerThousandTokens(model string) (UnitRates, bool) {
+	c := p.load()
+
+	rates, ok := c.lookupRates(model)
+	if !ok {
+		return UnitRates{}, false
+	}
+	standard := rates.tiers[0]
+	input := standard.inputPerMillion / TokensPerMillion * tokensPerThousand
+	return UnitRates{
+		Input:       input,
+		CachedInput: input * rates.cacheMultiplier,
+		Output:      standard.outputPerMillion / TokensPerMillion * tokensPerThousand,
+	}, true
+}
+
+// EffectiveBlendedRate returns what usage actually costs on model per 1,000
+// tokens (input, output, and thinking), after tiers, caching, and batch
+// discounts. It is 0 for usage without tokens and false for an unknown model.
+func (p *Pricer) EffectiveBlendedRate(model string, usage TokenUsage, opts *CalculateOptions) (float64, bool) {
+	details := p.CalculateUsage(model, usage, opts)
+	if details.Unknown {
+		return 0, false
+	}
+	return perThousand(details.RawTotal, usageTokens(usage)), true
+}
+
+// UnitEconomics prices records as CalculateBatchUsage does and returns their
+// cost per request and per 1,000 tokens.
+func (p *Pricer) UnitEconomics(records []UsageRecord) UnitEconomics {
+	c := p.load()
+
+	results := make([]CostDetails, len(records))
+	c.calculateBatch(records, results)
+	u := UnitEconomics{Requests: len(records)}
+	var raw float64
+	for i, rec := range records {
+		raw += results[i].RawTotal
+		u.Tokens, _ = addInt64Safe(u.Tokens, usageTokens(rec.Usage))
+	}
+	u.TotalCost = c.rounding.round(raw)
+	if u.Requests > 0 {
+		u.CostPerRequest = raw / float64(u.Requests)
+	}
+	u.CostPerThousandTokens = perThousand(raw, u.Tokens)
+	return u
+}
+
+// usageTokens returns usage's billed token count: input (including tool use),
+// output, and thinking tokens.
+func usageTokens(usage TokenUsage) int64 {
+	input, _ := addInt64Safe(usage.PromptTokens, usage.ToolUseTokens)
+	output, _ := addInt64Safe(usage.CompletionTokens, usage.ThinkingTokens)
+	total, _ := addInt64Safe(input, output)
+	return total
+}
+
+// perThousand returns cost per 1,000 of tokens, or 0 without tokens.
+func perThousand(cost float64, tokens int64) float64 {
+	if tokens <= 0 {
+		return 0
+	}
+	return cost / float64(tokens) * tokensPerThousand
+}
@@ -0,0 +1,84 @@
+package pricing_db
+
+import (
+	"testing"
+)
+
+// =============================================================================
+// Unit Cost Tests
+// =============================================================================
+
+var unitCostTestConfigs = map[string]string{
+	"acme_pricing.json": `{
+		"provider": "acme",
+		"models": {
+			"acme-1": {
+				"input_per_million": 2.0,
+				"output_per_million": 8.0,
+				"cache_read_multiplier": 0.25,
+				"batch_multiplier": 0.5,
+				"tiers": [{"threshold_tokens": 200000, "input_per_million": 4.0, "output_per_million": 16.0}]
+			}
+		}
+	}`,
+}
+
+func TestCostPerThousandTokens(t *testing.T) {
+	p := newTestPricer(t, unitCostTestConfigs)
+	rates, ok := p.CostPerThousandTokens("acme-1")
+	if !ok {
+		t.Fatal("expected acme-1 to be known")
+	}
+	if !floatEquals(rates.Input, 0.002) || !floatEquals(rates.CachedInput, 0.0005) || !floatEquals(rates.Output, 0.008) {
+		t.Errorf("unexpected standard-tier rates %+v", rates)
+	}
+	// 3:1 input-to-output blend
+	if blended := rates.Blended(0.75); !floatEquals(blended, 0.0035) {
+		t.Errorf("expected blended $0.0035/1K, got %f", blended)
+	}
+
+	if _, ok := p.CostPerThousandTokens("no-such-model"); ok {
+		t.Error("expected false for an unknown model")
+	}
+}
+
+func TestEffectiveBlendedRate(t *testing.T) {
+	p := newTestPricer(t, unitCostTestConfigs)
+	usage := TokenUsage{PromptTokens: 3000, CompletionTokens: 1000}
+
+	// ($0.006 + $0.008) over 4K tokens
+	rate, ok := p.EffectiveBlendedRate("acme-1", usage, nil)
+	if !ok || !floatEquals(rate, 0.0035) {
+		t.Errorf("expected $0.0035/1K, got %f (%v)", rate, ok)
+	}
+	// Discounts lower the effective rate
+	if batch, _ := p.EffectiveBlendedRate("acme-1", usage, &CalculateOptions{BatchMode: true}); !floatEquals(batch, rate/2) {
+		t.Errorf("expected batch to halve the rate, got %f", batch)
+	}
+	if zero, ok := p.EffectiveBlendedRate("acme-1", TokenUsage{}, nil); !ok || zero != 0 {
+		t.Errorf("expected 0 for no tokens, got %f (%v)", zero, ok)
+	}
+	if _, ok := p.EffectiveBlendedRate("no-such-model", usage, nil); ok {
+		t.Error("expected false for an unknown model")
+	}
+}
+
+func TestUnitEconomics(t *testing.T) {
+	p := newTestPricer(t, unitCostTestConfigs)
+	u := p.UnitEconomics([]UsageRecord{
+		{Model: "acme-1", Usage: TokenUsage{PromptTokens: 3000, CompletionTokens: 1000}},
+		{Model: "acme-1", Usage: TokenUsage{PromptTokens: 1000, CompletionTokens: 1000, ThinkingTokens: 1000}},
+		{Model: "no-such-model", Usage: TokenUsage{PromptTokens: 1000}},
+	})
+	// $0.014 + $0.018 over 3 requests and 8K tokens
+	if u.Requests != 3 || u.Tokens != 8000 || !floatEquals(u.TotalCost, 0.032) {
+		t.Fatalf("unexpected totals %+v", u)
+	}
+	if !floatEquals(u.CostPerRequest, 0.032/3) || !floatEquals(u.CostPerThousandTokens, 0.004) {
+		t.Errorf("unexpected unit costs %+v", u)
+	}
+
+	if empty := p.UnitEconomics(nil); empty != (UnitEconomics{}) {
+		t.Errorf("expected zero UnitEconomics, got %+v", empty)
+	}
+}
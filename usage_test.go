@@ -0,0 +1,453 @@
+package pricing_db
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// =============================================================================
+// CalculateUsage / Multimodal Input Tests
+// =============================================================================
+
+var multimodalTestConfigs = map[string]string{
+	"vision_pricing.json": `{
+		"provider": "vision",
+		"models": {
+			"vision-model": {
+				"input_per_million": 2.0,
+				"output_per_million": 8.0,
+				"image_input_per_million": 4.0,
+				"per_input_image": 0.001,
+				"batch_multiplier": 0.5
+			},
+			"text-rate-model": {
+				"input_per_million": 2.0,
+				"output_per_million": 8.0
+			}
+		}
+	}`,
+}
+
+func TestCalculateUsage_ImageInputTokens(t *testing.T) {
+	p := newTestPricer(t, multimodalTestConfigs)
+
+	cost := p.CalculateUsage("vision-model", TokenUsage{
+		PromptTokens:     1_000_000,
+		CompletionTokens: 100_000,
+		ImageInputTokens: 250_000,
+		ImageCount:       10,
+	}, nil)
+
+	// Text input: 750K * $2/M = $1.50
+	if !floatEquals(cost.StandardInputCost, 1.5) {
+		t.Errorf("expected standard input cost 1.5, got %f", cost.StandardInputCost)
+	}
+	// Image input: 250K * $4/M + 10 * $0.001 = $1.01
+	if !floatEquals(cost.ImageInputCost, 1.01) {
+		t.Errorf("expected image input cost 1.01, got %f", cost.ImageInputCost)
+	}
+	// Output: 100K * $8/M = $0.80
+	if !floatEquals(cost.TotalCost, 1.5+1.01+0.8) {
+		t.Errorf("expected total cost 3.31, got %f", cost.TotalCost)
+	}
+}
+
+func TestCalculateUsage_ImageTokensWithoutImageRate(t *testing.T) {
+	p := newTestPricer(t, multimodalTestConfigs)
+
+	// Without image_input_per_million, image tokens are billed as regular input
+	withImages := p.CalculateUsage("text-rate-model", TokenUsage{PromptTokens: 1000, ImageInputTokens: 500, ImageCount: 2}, nil)
+	withoutImages := p.CalculateUsage("text-rate-model", TokenUsage{PromptTokens: 1000}, nil)
+
+	if withImages.ImageInputCost != 0 {
+		t.Errorf("expected no image input cost, got %f", withImages.ImageInputCost)
+	}
+	if !floatEquals(withImages.TotalCost, withoutImages.TotalCost) {
+		t.Errorf("expected image tokens at input rate: got $%f, expected $%f", withImages.TotalCost, withoutImages.TotalCost)
+	}
+}
+
+func TestCalculateUsage_ImageTokensClampedToInput(t *testing.T) {
+	p := newTestPricer(t, multimodalTestConfigs)
+
+	cost := p.CalculateUsage("vision-model", TokenUsage{PromptTokens: 1000, ImageInputTokens: 5000}, nil)
+
+	if cost.StandardInputCost != 0 {
+		t.Errorf("expected all input billed as image tokens, got standard cost %f", cost.StandardInputCost)
+	}
+	// 1000 * $4/M = $0.004
+	if !floatEquals(cost.ImageInputCost, 0.004) {
+		t.Errorf("expected image input cost 0.004, got %f", cost.ImageInputCost)
+	}
+}
+
+func TestCalculateUsage_ImageInputBatchDiscount(t *testing.T) {
+	p := newTestPricer(t, multimodalTestConfigs)
+
+	usage := TokenUsage{PromptTokens: 1_000_000, ImageInputTokens: 1_000_000, ImageCount: 10}
+	standard := p.CalculateUsage("vision-model", usage, nil)
+	batch := p.CalculateUsage("vision-model", usage, &CalculateOptions{BatchMode: true})
+
+	if !floatEquals(batch.ImageInputCost, standard.ImageInputCost*0.5) {
+		t.Errorf("expected batch image cost %f, got %f", standard.ImageInputCost*0.5, batch.ImageInputCost)
+	}
+	if !floatEquals(batch.BatchDiscount, standard.TotalCost-batch.TotalCost) {
+		t.Errorf("expected batch discount %f, got %f", standard.TotalCost-batch.TotalCost, batch.BatchDiscount)
+	}
+}
+
+func TestCalculateUsage_MatchesCalculateWithOptions(t *testing.T) {
+	p, err := NewPricer()
+	if err != nil {
+		t.Fatalf("NewPricer failed: %v", err)
+	}
+
+	usage := p.CalculateUsage("claude-sonnet-4-5", TokenUsage{PromptTokens: 10000, CompletionTokens: 2000, CachedTokens: 4000}, nil)
+	legacy := p.CalculateWithOptions("claude-sonnet-4-5", 10000, 2000, 4000, nil)
+
+	if !floatEquals(usage.TotalCost, legacy.TotalCost) {
+		t.Errorf("CalculateUsage ($%f) and CalculateWithOptions ($%f) disagree", usage.TotalCost, legacy.TotalCost)
+	}
+}
+
+func TestCalculateUsage_UnknownModel(t *testing.T) {
+	p := newTestPricer(t, multimodalTestConfigs)
+
+	cost := p.CalculateUsage("unknown-model", TokenUsage{PromptTokens: 1000, ImageCount: 3}, nil)
+	if !cost.Unknown {
+		t.Error("expected Unknown=true for unknown model")
+	}
+}
+
+func TestNewPricerFromFS_NegativeImageInputPrice(t *testing.T) {
+	fsys := fstest.MapFS{
+		"configs/bad_pricing.json": &fstest.MapFile{Data: []byte(`{
+			"models": {
+				"bad-model": {"input_per_million": 1.0, "output_per_million": 1.0, "per_input_image": -0.01}
+			}
+		}`)},
+	}
+	_, err := NewPricerFromFS(fsys, "configs")
+	if err == nil {
+		t.Fatal("expected error for negative per_input_image")
+	}
+	if !strings.Contains(err.Error(), "per-image input price") {
+		t.Errorf("unexpected error message: %v", err)
+	}
+}
+
+func TestCalculateUsageCost_PackageLevel(t *testing.T) {
+	cost := CalculateUsageCost("gpt-4o", TokenUsage{PromptTokens: 1000, CompletionTokens: 500}, nil)
+	if cost.Unknown {
+		t.Fatal("expected gpt-4o to be known")
+	}
+	if !floatEquals(cost.TotalCost, 0.0075) {
+		t.Errorf("expected total cost 0.0075, got %f", cost.TotalCost)
+	}
+}
+
+// =============================================================================
+// Per-Request Minimum Billing Tests
+// =============================================================================
+
+var minimumTestConfigs = map[string]string{
+	"minimum_pricing.json": `{
+		"provider": "minimum",
+		"models": {
+			"min-tokens": {"input_per_million": 1.0, "output_per_million": 2.0, "min_input_tokens": 1000},
+			"min-usd": {"input_per_million": 1.0, "output_per_million": 2.0, "min_billable_usd": 0.01}
+		}
+	}`,
+}
+
+func TestCalculate_MinInputTokens(t *testing.T) {
+	p := newTestPricer(t, minimumTestConfigs)
+
+	// 10 input tokens billed as 1000
+	cost := p.Calculate("min-tokens", 10, 100)
+	if cost.InputTokens != 10 {
+		t.Errorf("InputTokens should report actual usage, got %d", cost.InputTokens)
+	}
+	if !floatEquals(cost.InputCost, 0.001) {
+		t.Errorf("expected input billed at minimum ($0.001), got $%f", cost.InputCost)
+	}
+	if !floatEquals(cost.TotalCost, 0.0012) {
+		t.Errorf("expected total $0.0012, got $%f", cost.TotalCost)
+	}
+	if !hasWarningCode(cost.WarningDetails, WarningMinimumBilled) {
+		t.Error("expected minimum_billed warning")
+	}
+
+	// Above the minimum: billed as used, no warning
+	cost = p.Calculate("min-tokens", 5000, 0)
+	if !floatEquals(cost.InputCost, 0.005) || len(cost.WarningDetails) != 0 {
+		t.Errorf("expected unadjusted $0.005 without warnings, got $%f %v", cost.InputCost, cost.WarningDetails)
+	}
+
+	// Empty request: nothing billed
+	if cost = p.Calculate("min-tokens", 0, 0); cost.TotalCost != 0 {
+		t.Errorf("expected zero cost for empty request, got $%f", cost.TotalCost)
+	}
+}
+
+func TestCalculate_MinBillableUSD(t *testing.T) {
+	p := newTestPricer(t, minimumTestConfigs)
+
+	cost := p.Calculate("min-usd", 1000, 500) // $0.002 of tokens
+	if !floatEquals(cost.MinimumCharge, 0.008) {
+		t.Errorf("expected minimum charge $0.008, got $%f", cost.MinimumCharge)
+	}
+	if !floatEquals(cost.TotalCost, 0.01) {
+		t.Errorf("expected total raised to $0.01, got $%f", cost.TotalCost)
+	}
+
+	cost = p.Calculate("min-usd", 10_000_000, 0) // $10 of tokens
+	if cost.MinimumCharge != 0 || !floatEquals(cost.TotalCost, 10.0) {
+		t.Errorf("expected no minimum charge above the floor, got $%f (total $%f)", cost.MinimumCharge, cost.TotalCost)
+	}
+}
+
+func TestCalculateUsage_Minimums(t *testing.T) {
+	p := newTestPricer(t, minimumTestConfigs)
+
+	// Cached tokens keep their discount; padding is billed at the standard rate
+	details := p.CalculateUsage("min-tokens", TokenUsage{PromptTokens: 200, CachedTokens: 100}, nil)
+	expectedStandard := 900 * 1.0 / 1_000_000
+	if !floatEquals(details.StandardInputCost, expectedStandard) {
+		t.Errorf("expected standard input $%f, got $%f", expectedStandard, details.StandardInputCost)
+	}
+	if !hasWarningCode(details.WarningDetails, WarningMinimumBilled) {
+		t.Error("expected minimum_billed warning")
+	}
+
+	details = p.CalculateUsage("min-usd", TokenUsage{PromptTokens: 1000, CompletionTokens: 500}, nil)
+	if !floatEquals(details.MinimumCharge, 0.008) || !floatEquals(details.TotalCost, 0.01) {
+		t.Errorf("expected $0.008 minimum charge and $0.01 total, got $%f / $%f", details.MinimumCharge, details.TotalCost)
+	}
+}
+
+func TestNewPricerFromFS_NegativeMinimums(t *testing.T) {
+	for _, field := range []string{`"min_input_tokens": -1`, `"min_billable_usd": -0.01`} {
+		fsys := fstest.MapFS{
+			"configs/bad_pricing.json": &fstest.MapFile{Data: []byte(`{
+				"models": {"bad-model": {"input_per_million": 1.0, "output_per_million": 1.0, ` + field + `}}
+			}`)},
+		}
+		if _, err := NewPricerFromFS(fsys, "configs"); err == nil {
+			t.Errorf("expected error for %s", field)
+		}
+	}
+}
+
+// =============================================================================
+// Gemini Modality Breakdown Tests
+// =============================================================================
+
+func TestParseGeminiResponse_ModalityDetails(t *testing.T) {
+	resp := []byte(`{
+		"modelVersion": "gemini-2.5-flash",
+		"usageMetadata": {
+			"promptTokenCount": 1000,
+			"candidatesTokenCount": 200,
+			"promptTokensDetails": [
+				{"modality": "TEXT", "tokenCount": 500},
+				{"modality": "AUDIO", "tokenCount": 400},
+				{"modality": "VIDEO", "tokenCount": 100}
+			],
+			"candidatesTokensDetails": [{"modality": "TEXT", "tokenCount": 200}]
+		}
+	}`)
+	cost, err := ParseGeminiResponse(resp)
+	if err != nil {
+		t.Fatalf("ParseGeminiResponse failed: %v", err)
+	}
+
+	// gemini-2.5-flash: $0.30/M input, $1.00/M audio input, $2.50/M output
+	if !floatEquals(cost.StandardInputCost, 600*0.30/1_000_000) {
+		t.Errorf("StandardInputCost = %v, want text+video at input rate", cost.StandardInputCost)
+	}
+	if !floatEquals(cost.AudioInputCost, 400*1.0/1_000_000) {
+		t.Errorf("AudioInputCost = %v, want audio at audio rate", cost.AudioInputCost)
+	}
+	want := ModalityTokens{Text: 500, Audio: 400, Video: 100}
+	if cost.PromptModalities != want {
+		t.Errorf("PromptModalities = %+v, want %+v", cost.PromptModalities, want)
+	}
+	if cost.OutputModalities.Text != 200 {
+		t.Errorf("OutputModalities.Text = %d, want 200", cost.OutputModalities.Text)
+	}
+	if !floatEquals(cost.RawTotal, cost.StandardInputCost+cost.AudioInputCost+cost.OutputCost) {
+		t.Errorf("RawTotal = %v does not include audio input cost", cost.RawTotal)
+	}
+}
+
+func TestCalculateUsage_AudioTokensWithoutAudioRate(t *testing.T) {
+	p := newTestPricer(t, multimodalTestConfigs)
+
+	// text-rate-model has no audio rates: audio is billed as ordinary tokens
+	withAudio := p.CalculateUsage("text-rate-model", TokenUsage{
+		PromptTokens: 1000, AudioInputTokens: 400, CompletionTokens: 100, AudioOutputTokens: 50,
+	}, nil)
+	plain := p.CalculateUsage("text-rate-model", TokenUsage{PromptTokens: 1000, CompletionTokens: 100}, nil)
+	if withAudio.TotalCost != plain.TotalCost || withAudio.AudioInputCost != 0 || withAudio.AudioOutputCost != 0 {
+		t.Errorf("expected audio billed at text rates, got %+v vs %+v", withAudio, plain)
+	}
+}
+
+func TestUsageFromJSON_GeminiModalities(t *testing.T) {
+	usage, err := UsageFromJSON("gemini", []byte(`{"usageMetadata": {
+		"promptTokenCount": 300,
+		"promptTokensDetails": [{"modality": "IMAGE", "tokenCount": 258}, {"modality": "TEXT", "tokenCount": 42}]
+	}}`))
+	if err != nil {
+		t.Fatalf("UsageFromJSON failed: %v", err)
+	}
+	if usage.ImageInputTokens != 258 || usage.PromptModalities.Text != 42 {
+		t.Errorf("expected image/text split 258/42, got %d/%d", usage.ImageInputTokens, usage.PromptModalities.Text)
+	}
+}
+
+// =============================================================================
+// Usage Consistency Warning Tests
+// =============================================================================
+
+func TestCalculateGeminiUsage_TotalMismatchWarning(t *testing.T) {
+	p, err := NewPricer()
+	if err != nil {
+		t.Fatalf("NewPricer failed: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		metadata GeminiUsageMetadata
+		wantWarn bool
+	}{
+		{"consistent", GeminiUsageMetadata{PromptTokenCount: 1000, CandidatesTokenCount: 200, ThoughtsTokenCount: 300, TotalTokenCount: 1500}, false},
+		{"cached is part of prompt", GeminiUsageMetadata{PromptTokenCount: 1000, CachedContentTokenCount: 800, CandidatesTokenCount: 200, TotalTokenCount: 1200}, false},
+		{"within tolerance", GeminiUsageMetadata{PromptTokenCount: 10000, CandidatesTokenCount: 50, TotalTokenCount: 10100}, false},
+		{"no total reported", GeminiUsageMetadata{PromptTokenCount: 1000, CandidatesTokenCount: 200}, false},
+		{"total too high", GeminiUsageMetadata{PromptTokenCount: 1000, CandidatesTokenCount: 200, TotalTokenCount: 5000}, true},
+		{"total too low", GeminiUsageMetadata{PromptTokenCount: 1000, CandidatesTokenCount: 200, ThoughtsTokenCount: 800, TotalTokenCount: 1200}, true},
+	}
+	for _, tc := range tests {
+		cost := p.CalculateGeminiUsage("gemini-2.5-flash", tc.metadata, 0, nil)
+		if got := hasWarningCode(cost.WarningDetails, WarningUsageMismatch); got != tc.wantWarn {
+			t.Errorf("%s: usage_mismatch warning = %v, want %v (%v)", tc.name, got, tc.wantWarn, cost.Warnings)
+		}
+	}
+}
+
+func TestParseGeminiResponse_TotalMismatchWarning(t *testing.T) {
+	resp := []byte(`{
+		"modelVersion": "gemini-2.5-flash",
+		"candidates": [{"tokenCount": 200}],
+		"usageMetadata": {"promptTokenCount": 1000, "candidatesTokenCount": 200, "totalTokenCount": 9999}
+	}`)
+	cost, err := ParseGeminiResponse(resp)
+	if err != nil {
+		t.Fatalf("ParseGeminiResponse failed: %v", err)
+	}
+	if !hasWarningCode(cost.WarningDetails, WarningUsageMismatch) {
+		t.Errorf("expected usage_mismatch warning, got %v", cost.Warnings)
+	}
+
+	breakdown, err := ParseGeminiResponseCandidates(resp, nil)
+	if err != nil {
+		t.Fatalf("ParseGeminiResponseCandidates failed: %v", err)
+	}
+	if !hasWarningCode(breakdown.Total.WarningDetails, WarningUsageMismatch) {
+		t.Errorf("expected usage_mismatch warning on candidate breakdown total, got %v", breakdown.Total.Warnings)
+	}
+}
+
+// =============================================================================
+// Cache Profile Tests
+// =============================================================================
+
+func TestCalculateUsage_CacheProfiles(t *testing.T) {
+	p, err := NewPricer()
+	if err != nil {
+		t.Fatalf("NewPricer failed: %v", err)
+	}
+	usage := TokenUsage{PromptTokens: 10000, CachedTokens: 2000, CacheWriteTokens: 3000, CompletionTokens: 1000}
+
+	// Default profile (5m): writes at 1.25x input
+	details := p.CalculateUsage("claude-opus-4-5", usage, nil)
+	if !floatEquals(details.StandardInputCost, 0.025) {
+		t.Errorf("expected standard input $0.025, got $%f", details.StandardInputCost)
+	}
+	if !floatEquals(details.CacheWriteCost, 0.01875) {
+		t.Errorf("expected 5m cache write $0.01875, got $%f", details.CacheWriteCost)
+	}
+	if !floatEquals(details.TotalCost, 0.06975) {
+		t.Errorf("expected total $0.06975, got $%f", details.TotalCost)
+	}
+
+	// 1h profile: writes at 2x input
+	details = p.CalculateUsage("claude-opus-4-5", usage, &CalculateOptions{CacheProfile: "1h"})
+	if !floatEquals(details.CacheWriteCost, 0.03) {
+		t.Errorf("expected 1h cache write $0.03, got $%f", details.CacheWriteCost)
+	}
+	if !floatEquals(details.TotalCost, 0.081) {
+		t.Errorf("expected total $0.081, got $%f", details.TotalCost)
+	}
+
+	// Unknown profile: writes fall back to the standard input rate with a warning
+	details = p.CalculateUsage("claude-opus-4-5", usage, &CalculateOptions{CacheProfile: "24h"})
+	if details.CacheWriteCost != 0 || !floatEquals(details.StandardInputCost, 0.04) {
+		t.Errorf("expected writes billed as standard input ($0.04), got standard $%f write $%f", details.StandardInputCost, details.CacheWriteCost)
+	}
+	if !hasWarningCode(details.WarningDetails, WarningCacheProfileMissing) {
+		t.Errorf("expected cache_profile_missing warning, got %v", details.Warnings)
+	}
+
+	// Batch mode discounts cache writes along with everything else (stack rule)
+	details = p.CalculateUsage("claude-opus-4-5", usage, &CalculateOptions{BatchMode: true})
+	if !floatEquals(details.CacheWriteCost, 0.009375) {
+		t.Errorf("expected batch cache write $0.009375, got $%f", details.CacheWriteCost)
+	}
+}
+
+func TestCalculateUsage_CacheProfileReadMultiplier(t *testing.T) {
+	p := newTestPricer(t, map[string]string{
+		"profile_pricing.json": `{
+			"provider": "profile",
+			"models": {
+				"profile-model": {
+					"input_per_million": 10.0,
+					"output_per_million": 10.0,
+					"cache_read_multiplier": 0.5,
+					"cache_profiles": {"long": {"read_multiplier": 0.1, "write_multiplier": 1.0}}
+				}
+			}
+		}`,
+	})
+	usage := TokenUsage{PromptTokens: 1_000_000, CachedTokens: 1_000_000}
+
+	// No profile selected and no default: model cache_read_multiplier applies
+	if details := p.CalculateUsage("profile-model", usage, nil); !floatEquals(details.CachedInputCost, 5.0) {
+		t.Errorf("expected cached input $5.00, got $%f", details.CachedInputCost)
+	}
+	details := p.CalculateUsage("profile-model", usage, &CalculateOptions{CacheProfile: "long"})
+	if !floatEquals(details.CachedInputCost, 1.0) {
+		t.Errorf("expected profile read multiplier to give $1.00, got $%f", details.CachedInputCost)
+	}
+}
+
+func TestNewPricerFromFS_InvalidCacheProfiles(t *testing.T) {
+	for _, field := range []string{
+		`"cache_profiles": {"x": {"read_multiplier": 1.5, "write_multiplier": 1.0}}`,
+		`"cache_profiles": {"x": {"write_multiplier": -1}}`,
+		`"default_cache_profile": "missing"`,
+	} {
+		fsys := fstest.MapFS{
+			"configs/bad_pricing.json": &fstest.MapFile{Data: []byte(`{
+				"models": {"bad-model": {"input_per_million": 1.0, "output_per_million": 1.0, ` + field + `}}
+			}`)},
+		}
+		if _, err := NewPricerFromFS(fsys, "configs"); err == nil {
+			t.Errorf("expected error for %s", field)
+		}
+	}
+}
@@ -0,0 +1,191 @@
+package pricing_db
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CSVColumns names the header columns ReadUsageCSV maps to UsageRecord fields.
+// An empty name uses the default shown; a default column missing from the
+// file reads as zero, while a column named here must be present.
+type CSVColumns struct {
+	Model          string // "model" (required)
+	InputTokens    string // "input_tokens"
+	OutputTokens   string // "output_tokens"
+	CachedTokens   string // "cached_tokens"
+	ThinkingTokens string // "thinking_tokens"
+	Timestamp      string // "timestamp"; RFC 3339, "2006-01-02 15:04:05", a date, or Unix seconds
+	BatchMode      string // "batch_mode"; true/false or 1/0
+}
+
+// csvField is one mapped column: its header name and whether it was set
+// explicitly.
+type csvField struct {
+	name     string
+	explicit bool
+}
+
+// Indexes of the mapped columns in ReadUsageCSV.
+const (
+	csvModel = iota
+	csvInputTokens
+	csvOutputTokens
+	csvCachedTokens
+	csvThinkingTokens
+	csvTimestamp
+	csvBatchMode
+)
+
+func newCSVField(name, def string) csvField {
+	if name == "" {
+		return csvField{name: def}
+	}
+	return csvField{name: name, explicit: true}
+}
+
+// ReadUsageCSV reads usage records exported from a warehouse as CSV, one
+// request per row after a header row, for CalculateBatchUsage or Reprice.
+// Token counts may be written as integers or integral floats ("1000.0");
+// empty cells read as zero. Errors name the 1-based line of the offending row.
+func ReadUsageCSV(r io.Reader, cols CSVColumns) ([]UsageRecord, error) {
+	cr := csv.NewReader(r)
+	cr.ReuseRecord = true
+	header, err := cr.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, errors.New("usage CSV: missing header row")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("usage CSV: %w", err)
+	}
+	index := make(map[string]int, len(header))
+	for i, name := range header {
+		index[strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))] = i
+	}
+	column := func(f csvField, required bool) (int, error) {
+		if i, ok := index[f.name]; ok {
+			return i, nil
+		}
+		if required || f.explicit {
+			return -1, fmt.Errorf("usage CSV: no %q column", f.name)
+		}
+		return -1, nil
+	}
+
+	fields := []struct {
+		field    csvField
+		required bool
+		col      int
+	}{
+		{field: newCSVField(cols.Model, "model"), required: true},
+		{field: newCSVField(cols.InputTokens, "input_tokens")},
+		{field: newCSVField(cols.OutputTokens, "output_tokens")},
+		{field: newCSVField(cols.CachedTokens, "cached_tokens")},
+		{field: newCSVField(cols.ThinkingTokens, "thinking_tokens")},
+		{field: newCSVField(cols.Timestamp, "timestamp")},
+		{field: newCSVField(cols.BatchMode, "batch_mode")},
+	}
+	for i := range fields {
+		if fields[i].col, err = column(fields[i].field, fields[i].required); err != nil {
+			return nil, err
+		}
+	}
+	cell := func(row []string, i int) string {
+		col := fields[i].col
+		if col < 0 || col >= len(row) {
+			return ""
+		}
+		return strings.TrimSpace(row[col])
+	}
+
+	var records []UsageRecord
+	for {
+		row, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			return records, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("usage CSV: %w", err)
+		}
+		line, _ := cr.FieldPos(0)
+
+		rec := UsageRecord{Model: cell(row, csvModel)}
+		if rec.Model == "" {
+			return nil, fmt.Errorf("usage CSV line %d: model is required", line)
+		}
+		counts := []struct {
+			col int
+			dst *int64
+		}{
+			{csvInputTokens, &rec.Usage.PromptTokens},
+			{csvOutputTokens, &rec.Usage.CompletionTokens},
+			{csvCachedTokens, &rec.Usage.CachedTokens},
+			{csvThinkingTokens, &rec.Usage.ThinkingTokens},
+		}
+		for _, c := range counts {
+			if *c.dst, err = parseCSVTokens(cell(row, c.col)); err != nil {
+				return nil, fmt.Errorf("usage CSV line %d: %s: %w", line, fields[c.col].field.name, err)
+			}
+		}
+		if rec.At, err = parseCSVTime(cell(row, csvTimestamp)); err != nil {
+			return nil, fmt.Errorf("usage CSV line %d: %s: %w", line, fields[csvTimestamp].field.name, err)
+		}
+		if v := cell(row, csvBatchMode); v != "" {
+			batch, err := strconv.ParseBool(v)
+			if err != nil {
+				return nil, fmt.Errorf("usage CSV line %d: %s: %w", line, fields[csvBatchMode].field.name, err)
+			}
+			if batch {
+				rec.Options = &CalculateOptions{BatchMode: true}
+			}
+		}
+		records = append(records, rec)
+	}
+}
+
+// parseCSVTokens parses a token count written as an integer or an integral float.
+func parseCSVTokens(s string) (int64, error) {
+	if s == "" {
+		return 0, nil
+	}
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return n, nil
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil || f != math.Trunc(f) || math.Abs(f) > math.MaxInt64 {
+		return 0, fmt.Errorf("invalid token count %q", s)
+	}
+	return int64(f), nil
+}
+
+// csvTimeLayouts are the timestamp layouts ReadUsageCSV accepts, besides Unix
+// seconds. Layouts without a zone are read as UTC.
+var csvTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999 MST",
+	time.DateOnly,
+}
+
+// parseCSVTime parses a warehouse timestamp; empty means the zero time.
+func parseCSVTime(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if secs, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return time.Unix(secs, 0).UTC(), nil
+	}
+	for _, layout := range csvTimeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid timestamp %q", s)
+}
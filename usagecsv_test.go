@@ -0,0 +1,109 @@
+package pricing_db
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// =============================================================================
+// ReadUsageCSV Tests
+// =============================================================================
+
+func TestReadUsageCSV_DefaultColumns(t *testing.T) {
+	input := "\ufeffmodel,input_tokens,output_tokens,cached_tokens,timestamp,batch_mode\n" +
+		"gpt-4o,1000,500,200,2026-01-15T10:00:00Z,false\n" +
+		"gpt-4o-mini,2000.0,,,2026-01-15 10:30:00,1\n" +
+		"claude-sonnet-4,10,20,0,1767225600,\n"
+	records, err := ReadUsageCSV(strings.NewReader(input), CSVColumns{})
+	if err != nil {
+		t.Fatalf("ReadUsageCSV failed: %v", err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("expected 3 records, got %d", len(records))
+	}
+
+	first := records[0]
+	if first.Model != "gpt-4o" || first.Usage.PromptTokens != 1000 || first.Usage.CompletionTokens != 500 || first.Usage.CachedTokens != 200 {
+		t.Errorf("unexpected first record %+v", first)
+	}
+	if !first.At.Equal(time.Date(2026, 1, 15, 10, 0, 0, 0, time.UTC)) || first.Options != nil {
+		t.Errorf("unexpected first record time/options %v %+v", first.At, first.Options)
+	}
+
+	second := records[1]
+	if second.Usage.PromptTokens != 2000 || second.Usage.CompletionTokens != 0 {
+		t.Errorf("expected float and empty token cells to parse, got %+v", second.Usage)
+	}
+	if second.Options == nil || !second.Options.BatchMode {
+		t.Error("expected batch_mode=1 to set BatchMode")
+	}
+	if !second.At.Equal(time.Date(2026, 1, 15, 10, 30, 0, 0, time.UTC)) {
+		t.Errorf("expected zone-less timestamp read as UTC, got %v", second.At)
+	}
+
+	if !records[2].At.Equal(time.Unix(1767225600, 0)) {
+		t.Errorf("expected Unix seconds timestamp, got %v", records[2].At)
+	}
+}
+
+func TestReadUsageCSV_MappedColumns(t *testing.T) {
+	input := "model_name,prompt,completion,day\n" +
+		"gpt-4o,100,50,2026-02-01\n"
+	cols := CSVColumns{Model: "model_name", InputTokens: "prompt", OutputTokens: "completion", Timestamp: "day"}
+	records, err := ReadUsageCSV(strings.NewReader(input), cols)
+	if err != nil {
+		t.Fatalf("ReadUsageCSV failed: %v", err)
+	}
+	if len(records) != 1 || records[0].Usage.PromptTokens != 100 || records[0].Usage.CompletionTokens != 50 {
+		t.Fatalf("unexpected records %+v", records)
+	}
+	if !records[0].At.Equal(time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected date-only timestamp, got %v", records[0].At)
+	}
+
+	// A column named explicitly must be present.
+	if _, err := ReadUsageCSV(strings.NewReader(input), CSVColumns{Model: "model_name", CachedTokens: "cache"}); err == nil || !strings.Contains(err.Error(), `"cache"`) {
+		t.Errorf("expected missing mapped column error, got %v", err)
+	}
+}
+
+func TestReadUsageCSV_Errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"empty", "", "missing header row"},
+		{"no model column", "input_tokens\n10\n", `no "model" column`},
+		{"empty model", "model,input_tokens\n,10\n", "line 2: model is required"},
+		{"bad tokens", "model,input_tokens\ngpt-4o,1.5\n", "line 2: input_tokens: invalid token count"},
+		{"bad timestamp", "model,timestamp\ngpt-4o,yesterday\n", "line 2: timestamp: invalid timestamp"},
+		{"bad batch mode", "model,batch_mode\ngpt-4o,maybe\n", "line 2: batch_mode"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ReadUsageCSV(strings.NewReader(tt.input), CSVColumns{})
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestReadUsageCSV_PricesWithBatch(t *testing.T) {
+	p, err := NewPricer()
+	if err != nil {
+		t.Fatalf("NewPricer failed: %v", err)
+	}
+	input := "model,input_tokens,output_tokens\ngpt-4o,1000,500\n"
+	records, err := ReadUsageCSV(strings.NewReader(input), CSVColumns{})
+	if err != nil {
+		t.Fatalf("ReadUsageCSV failed: %v", err)
+	}
+	got := p.CalculateBatchUsage(records)[0]
+	want := p.CalculateUsage("gpt-4o", TokenUsage{PromptTokens: 1000, CompletionTokens: 500}, nil)
+	if got.Unknown || !floatEquals(got.TotalCost, want.TotalCost) {
+		t.Errorf("expected %f, got %+v", want.TotalCost, got)
+	}
+}
@@ -0,0 +1,251 @@
+package pricing_db
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// usageParsers maps a provider name to the parser for its usage JSON.
+var usageParsers = map[string]func([]byte) (TokenUsage, error){
+	"openai":           parseOpenAIUsage,
+	"openai-responses": parseOpenAIResponsesUsage,
+	"anthropic":        parseAnthropicUsage,
+	"google":           parseGeminiUsage,
+	"gemini":           parseGeminiUsage,
+	"bedrock":          parseBedrockUsage,
+	"cohere":           parseCohereUsage,
+}
+
+// openAICompatibleProviders report usage with OpenAI's field names.
+var openAICompatibleProviders = []string{
+	"ai21", "baseten", "cerebras", "databricks", "deepinfra", "deepseek", "fireworks",
+	"groq", "huggingface", "hyperbolic", "minimax", "mistral", "nebius",
+	"perplexity", "predibase", "together", "upstage", "xai",
+}
+
+func init() {
+	for _, provider := range openAICompatibleProviders {
+		usageParsers[provider] = parseOpenAIUsage
+	}
+}
+
+// UsageProviders returns the provider names accepted by UsageFromJSON, sorted.
+func UsageProviders() []string {
+	names := make([]string, 0, len(usageParsers))
+	for name := range usageParsers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// UsageFromJSON normalizes a provider's usage JSON into a TokenUsage for
+// CalculateUsage. raw may be the usage object itself or a full response
+// containing it ("usage" or "usageMetadata").
+//
+// Field mapping:
+//   - openai (and OpenAI-compatible providers): prompt_tokens, completion_tokens,
+//     prompt_tokens_details.cached_tokens (or DeepSeek's prompt_cache_hit_tokens),
+//     completion_tokens_details.reasoning_tokens. Reasoning tokens are moved from
+//     CompletionTokens to ThinkingTokens, billed at thinking_per_million (or the output rate).
+//     prompt_tokens_details.audio_tokens and completion_tokens_details.audio_tokens
+//     become AudioInputTokens and AudioOutputTokens, billed at the model's audio
+//     rates (or its text rates when it has none).
+//   - openai-responses (OpenAI Responses API): input_tokens, output_tokens,
+//     input_tokens_details.cached_tokens, output_tokens_details.reasoning_tokens,
+//     with reasoning moved to ThinkingTokens as for openai. In a full response,
+//     web_search_call and file_search_call output items are billed as the
+//     "web_search" and "file_search" surcharges.
+//   - anthropic: input_tokens, output_tokens, cache_read_input_tokens,
+//     cache_creation_input_tokens, server_tool_use.web_search_requests. Anthropic
+//     counts cache reads and writes separately from input_tokens, so they are
+//     added back into PromptTokens; writes are also reported as CacheWriteTokens.
+//     Web search requests are billed as the "web_search" surcharge.
+//   - google/gemini: promptTokenCount, candidatesTokenCount, cachedContentTokenCount,
+//     toolUsePromptTokenCount, thoughtsTokenCount.
+//   - bedrock (Converse API): inputTokens, outputTokens, cacheReadInputTokens,
+//     cacheWriteInputTokens, with the same cache handling as anthropic.
+//   - cohere: billed_units.input_tokens and output_tokens (falling back to tokens),
+//     under "usage" (Chat v2) or "meta" (v1).
+//
+// Cache writes are billed by the model's cache profile (see CalculateOptions.CacheProfile),
+// or at the standard input rate when it has none.
+func UsageFromJSON(provider string, raw []byte) (TokenUsage, error) {
+	parse, ok := usageParsers[strings.ToLower(provider)]
+	if !ok {
+		return TokenUsage{}, fmt.Errorf("no usage mapping for provider %q", provider)
+	}
+	usage, err := parse(raw)
+	if err != nil {
+		return TokenUsage{}, fmt.Errorf("parse %s usage: %w", provider, err)
+	}
+	return usage, nil
+}
+
+// unwrapUsage returns the object under key if raw is a response wrapping it,
+// otherwise raw itself.
+func unwrapUsage(raw []byte, key string) ([]byte, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, err
+	}
+	if inner, ok := fields[key]; ok {
+		return inner, nil
+	}
+	return raw, nil
+}
+
+func parseOpenAIUsage(raw []byte) (TokenUsage, error) {
+	raw, err := unwrapUsage(raw, "usage")
+	if err != nil {
+		return TokenUsage{}, err
+	}
+	var u struct {
+		PromptTokens         int64 `json:"prompt_tokens"`
+		CompletionTokens     int64 `json:"completion_tokens"`
+		PromptCacheHitTokens int64 `json:"prompt_cache_hit_tokens"`
+		PromptTokensDetails  struct {
+			CachedTokens int64 `json:"cached_tokens"`
+			AudioTokens  int64 `json:"audio_tokens"`
+		} `json:"prompt_tokens_details"`
+		CompletionTokensDetails struct {
+			ReasoningTokens int64 `json:"reasoning_tokens"`
+			AudioTokens     int64 `json:"audio_tokens"`
+		} `json:"completion_tokens_details"`
+	}
+	if err := json.Unmarshal(raw, &u); err != nil {
+		return TokenUsage{}, err
+	}
+
+	reasoning := min(u.CompletionTokensDetails.ReasoningTokens, u.CompletionTokens)
+	return TokenUsage{
+		PromptTokens:      u.PromptTokens,
+		CompletionTokens:  u.CompletionTokens - reasoning,
+		CachedTokens:      max(u.PromptTokensDetails.CachedTokens, u.PromptCacheHitTokens),
+		ThinkingTokens:    reasoning,
+		AudioInputTokens:  u.PromptTokensDetails.AudioTokens,
+		AudioOutputTokens: min(u.CompletionTokensDetails.AudioTokens, u.CompletionTokens-reasoning),
+	}, nil
+}
+
+// responsesToolSurcharges maps OpenAI Responses API output item types of
+// per-call built-in tools to their surcharge names.
+var responsesToolSurcharges = map[string]string{
+	"web_search_call":  SurchargeWebSearch,
+	"file_search_call": SurchargeFileSearch,
+}
+
+func parseOpenAIResponsesUsage(raw []byte) (TokenUsage, error) {
+	var resp struct {
+		Usage  json.RawMessage `json:"usage"`
+		Output []struct {
+			Type string `json:"type"`
+		} `json:"output"`
+	}
+	if err := json.Unmarshal(raw, &resp); err != nil {
+		return TokenUsage{}, err
+	}
+	if resp.Usage != nil {
+		raw = resp.Usage
+	}
+	var u struct {
+		InputTokens        int64 `json:"input_tokens"`
+		OutputTokens       int64 `json:"output_tokens"`
+		InputTokensDetails struct {
+			CachedTokens int64 `json:"cached_tokens"`
+		} `json:"input_tokens_details"`
+		OutputTokensDetails struct {
+			ReasoningTokens int64 `json:"reasoning_tokens"`
+		} `json:"output_tokens_details"`
+	}
+	if err := json.Unmarshal(raw, &u); err != nil {
+		return TokenUsage{}, err
+	}
+
+	reasoning := min(u.OutputTokensDetails.ReasoningTokens, u.OutputTokens)
+	usage := TokenUsage{
+		PromptTokens:     u.InputTokens,
+		CompletionTokens: u.OutputTokens - reasoning,
+		CachedTokens:     u.InputTokensDetails.CachedTokens,
+		ThinkingTokens:   reasoning,
+	}
+	for _, item := range resp.Output {
+		if name, ok := responsesToolSurcharges[item.Type]; ok {
+			if usage.Surcharges == nil {
+				usage.Surcharges = make(map[string]int64)
+			}
+			usage.Surcharges[name]++
+		}
+	}
+	return usage, nil
+}
+
+func parseAnthropicUsage(raw []byte) (TokenUsage, error) {
+	raw, err := unwrapUsage(raw, "usage")
+	if err != nil {
+		return TokenUsage{}, err
+	}
+	var u AnthropicUsage
+	if err := json.Unmarshal(raw, &u); err != nil {
+		return TokenUsage{}, err
+	}
+	return u.TokenUsage(), nil
+}
+
+// TokenUsage converts u for CalculateUsage. Anthropic counts cache reads and
+// writes separately from input_tokens, so they are added back into
+// PromptTokens; web search requests become the SurchargeWebSearch surcharge.
+func (u AnthropicUsage) TokenUsage() TokenUsage {
+	usage := TokenUsage{
+		PromptTokens:     u.InputTokens + u.CacheReadInputTokens + u.CacheCreationInputTokens,
+		CompletionTokens: u.OutputTokens,
+		CachedTokens:     u.CacheReadInputTokens,
+		CacheWriteTokens: u.CacheCreationInputTokens,
+	}
+	if u.ServerToolUse != nil && u.ServerToolUse.WebSearchRequests > 0 {
+		usage.Surcharges = map[string]int64{SurchargeWebSearch: u.ServerToolUse.WebSearchRequests}
+	}
+	return usage
+}
+
+// CalculateAnthropicUsage prices an Anthropic usage object, including server
+// tool fees such as web search requests (in SurchargeCost).
+func (p *Pricer) CalculateAnthropicUsage(model string, usage AnthropicUsage, opts *CalculateOptions) CostDetails {
+	return p.CalculateUsage(model, usage.TokenUsage(), opts)
+}
+
+func parseGeminiUsage(raw []byte) (TokenUsage, error) {
+	raw, err := unwrapUsage(raw, "usageMetadata")
+	if err != nil {
+		return TokenUsage{}, err
+	}
+	var u GeminiUsageMetadata
+	if err := json.Unmarshal(raw, &u); err != nil {
+		return TokenUsage{}, err
+	}
+	return geminiTokenUsage(u, 0), nil
+}
+
+func parseBedrockUsage(raw []byte) (TokenUsage, error) {
+	raw, err := unwrapUsage(raw, "usage")
+	if err != nil {
+		return TokenUsage{}, err
+	}
+	var u struct {
+		InputTokens           int64 `json:"inputTokens"`
+		OutputTokens          int64 `json:"outputTokens"`
+		CacheReadInputTokens  int64 `json:"cacheReadInputTokens"`
+		CacheWriteInputTokens int64 `json:"cacheWriteInputTokens"`
+	}
+	if err := json.Unmarshal(raw, &u); err != nil {
+		return TokenUsage{}, err
+	}
+	return TokenUsage{
+		PromptTokens:     u.InputTokens + u.CacheReadInputTokens + u.CacheWriteInputTokens,
+		CompletionTokens: u.OutputTokens,
+		CachedTokens:     u.CacheReadInputTokens,
+		CacheWriteTokens: u.CacheWriteInputTokens,
+	}, nil
+}
@@ -0,0 +1,160 @@
+package pricing_db
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// =============================================================================
+// Provider Usage JSON Normalization Tests
+// =============================================================================
+
+func TestUsageFromJSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		provider string
+		raw      string
+		want     TokenUsage
+	}{
+		{
+			name:     "openai usage object",
+			provider: "openai",
+			raw: `{"prompt_tokens": 1200, "completion_tokens": 500,
+				"prompt_tokens_details": {"cached_tokens": 1024},
+				"completion_tokens_details": {"reasoning_tokens": 300}}`,
+			want: TokenUsage{PromptTokens: 1200, CompletionTokens: 200, CachedTokens: 1024, ThinkingTokens: 300},
+		},
+		{
+			name:     "openai full response",
+			provider: "OpenAI",
+			raw:      `{"id": "chatcmpl-1", "model": "gpt-4o", "usage": {"prompt_tokens": 10, "completion_tokens": 5}}`,
+			want:     TokenUsage{PromptTokens: 10, CompletionTokens: 5},
+		},
+		{
+			name:     "openai audio",
+			provider: "openai",
+			raw: `{"prompt_tokens": 1200, "completion_tokens": 800,
+				"prompt_tokens_details": {"cached_tokens": 0, "audio_tokens": 1000},
+				"completion_tokens_details": {"reasoning_tokens": 0, "audio_tokens": 700}}`,
+			want: TokenUsage{PromptTokens: 1200, CompletionTokens: 800, AudioInputTokens: 1000, AudioOutputTokens: 700},
+		},
+		{
+			name:     "openai responses usage object",
+			provider: "openai-responses",
+			raw: `{"input_tokens": 1200, "output_tokens": 500,
+				"input_tokens_details": {"cached_tokens": 1024}, "output_tokens_details": {"reasoning_tokens": 300}}`,
+			want: TokenUsage{PromptTokens: 1200, CompletionTokens: 200, CachedTokens: 1024, ThinkingTokens: 300},
+		},
+		{
+			name:     "openai responses tool calls",
+			provider: "openai-responses",
+			raw: `{"output": [{"type": "web_search_call"}, {"type": "reasoning"}, {"type": "file_search_call"}, {"type": "web_search_call"}],
+				"usage": {"input_tokens": 10, "output_tokens": 5}}`,
+			want: TokenUsage{PromptTokens: 10, CompletionTokens: 5, Surcharges: map[string]int64{SurchargeWebSearch: 2, SurchargeFileSearch: 1}},
+		},
+		{
+			name:     "deepseek cache hit alias",
+			provider: "deepseek",
+			raw:      `{"prompt_tokens": 100, "completion_tokens": 10, "prompt_cache_hit_tokens": 64, "prompt_cache_miss_tokens": 36}`,
+			want:     TokenUsage{PromptTokens: 100, CompletionTokens: 10, CachedTokens: 64},
+		},
+		{
+			name:     "anthropic message",
+			provider: "anthropic",
+			raw: `{"type": "message", "usage": {"input_tokens": 50, "output_tokens": 400,
+				"cache_read_input_tokens": 2000, "cache_creation_input_tokens": 300}}`,
+			want: TokenUsage{PromptTokens: 2350, CompletionTokens: 400, CachedTokens: 2000, CacheWriteTokens: 300},
+		},
+		{
+			name:     "anthropic server tools",
+			provider: "anthropic",
+			raw:      `{"input_tokens": 50, "output_tokens": 10, "server_tool_use": {"web_search_requests": 2, "web_fetch_requests": 1}}`,
+			want:     TokenUsage{PromptTokens: 50, CompletionTokens: 10, Surcharges: map[string]int64{SurchargeWebSearch: 2}},
+		},
+		{
+			name:     "gemini response",
+			provider: "google",
+			raw: `{"modelVersion": "gemini-2.5-pro", "usageMetadata": {"promptTokenCount": 427, "candidatesTokenCount": 486,
+				"cachedContentTokenCount": 280, "toolUsePromptTokenCount": 1399, "thoughtsTokenCount": 478}}`,
+			want: TokenUsage{PromptTokens: 427, CompletionTokens: 486, CachedTokens: 280, ThinkingTokens: 478, ToolUseTokens: 1399},
+		},
+		{
+			name:     "bedrock converse",
+			provider: "bedrock",
+			raw:      `{"usage": {"inputTokens": 20, "outputTokens": 30, "cacheReadInputTokens": 100, "cacheWriteInputTokens": 5}}`,
+			want:     TokenUsage{PromptTokens: 125, CompletionTokens: 30, CachedTokens: 100, CacheWriteTokens: 5},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := UsageFromJSON(tt.provider, []byte(tt.raw))
+			if err != nil {
+				t.Fatalf("UsageFromJSON failed: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUsageFromJSON_Errors(t *testing.T) {
+	if _, err := UsageFromJSON("acme", []byte(`{}`)); err == nil || !strings.Contains(err.Error(), "no usage mapping") {
+		t.Errorf("expected unknown provider error, got %v", err)
+	}
+	if _, err := UsageFromJSON("openai", []byte(`{"usage": `)); err == nil {
+		t.Error("expected error for malformed JSON")
+	}
+	if _, err := UsageFromJSON("anthropic", []byte(`{"usage": {"input_tokens": "many"}}`)); err == nil {
+		t.Error("expected error for wrong field type")
+	}
+}
+
+func TestUsageFromJSON_OpenAIAudioRates(t *testing.T) {
+	raw := []byte(`{"model": "gpt-4o-audio-preview", "usage": {"prompt_tokens": 1200, "completion_tokens": 800,
+		"prompt_tokens_details": {"audio_tokens": 1000}, "completion_tokens_details": {"audio_tokens": 700}}}`)
+	details, err := defaultPricer().CalculateResponse("openai", raw, "", nil)
+	if err != nil {
+		t.Fatalf("CalculateResponse failed: %v", err)
+	}
+	// Text: 200 in at $2.50/M, 100 out at $10/M; audio: 1000 in at $40/M, 700 out at $80/M
+	if !floatEquals(details.AudioInputCost, 0.04) || !floatEquals(details.AudioOutputCost, 0.056) ||
+		!floatEquals(details.StandardInputCost, 0.0005) || !floatEquals(details.OutputCost, 0.001) {
+		t.Errorf("expected audio tokens at audio rates, got %+v", details)
+	}
+}
+
+func TestUsageFromJSON_PricesLikeNativeAPI(t *testing.T) {
+	p, err := NewPricer()
+	if err != nil {
+		t.Fatalf("NewPricer failed: %v", err)
+	}
+
+	raw := []byte(`{"usageMetadata": {"promptTokenCount": 5000, "candidatesTokenCount": 800, "cachedContentTokenCount": 1000, "thoughtsTokenCount": 200}}`)
+	usage, err := UsageFromJSON("gemini", raw)
+	if err != nil {
+		t.Fatalf("UsageFromJSON failed: %v", err)
+	}
+	got := p.CalculateUsage("gemini-2.5-flash", usage, nil)
+	want := p.CalculateGeminiUsage("gemini-2.5-flash", GeminiUsageMetadata{
+		PromptTokenCount: 5000, CandidatesTokenCount: 800, CachedContentTokenCount: 1000, ThoughtsTokenCount: 200,
+	}, 0, nil)
+	if got.TotalCost != want.TotalCost {
+		t.Errorf("expected $%f, got $%f", want.TotalCost, got.TotalCost)
+	}
+}
+
+func TestUsageProviders(t *testing.T) {
+	providers := UsageProviders()
+	for _, want := range []string{"anthropic", "bedrock", "google", "openai", "together"} {
+		if !slices.Contains(providers, want) {
+			t.Errorf("expected %q in UsageProviders()", want)
+		}
+	}
+	if !slices.IsSorted(providers) {
+		t.Error("expected UsageProviders() to be sorted")
+	}
+}
@@ -255,6 +255,30 @@ func TestNewPricerFromFS_NegativeCreditMultipliers(t *testing.T) {
 			}`,
 			errContains: "negative js_premium",
 		},
+		{
+			name: "negative custom multiplier",
+			json: `{
+				"provider": "test",
+				"billing_type": "credit",
+				"credit_pricing": {
+					"base_cost_per_request": 1,
+					"multipliers": {"stealth_proxy": -75}
+				}
+			}`,
+			errContains: "negative stealth_proxy",
+		},
+		{
+			name: "empty multiplier name",
+			json: `{
+				"provider": "test",
+				"billing_type": "credit",
+				"credit_pricing": {
+					"base_cost_per_request": 1,
+					"multipliers": {"": 5}
+				}
+			}`,
+			errContains: "empty multiplier name",
+		},
 	}
 
 	for _, tc := range tests {
@@ -0,0 +1,33 @@
+package pricing_db
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+)
+
+// Version returns a hash identifying the loaded pricing data, e.g.
+// "9b46c6b84e685c43". It is computed from the merged catalog (as written by
+// Export), so it is the same for every Pricer holding the same prices, whether
+// loaded from configs or a snapshot, and changes when any price, model, or
+// provider entry does. The settings a snapshot carries, WithRoundingPolicy and
+// WithDefaultCacheMultiplier, change computed costs and are included, so equal
+// versions price usage identically.
+//
+// Log it alongside cost figures to record which pricing snapshot produced
+// them, or use it as an HTTP ETag for responses derived from the catalog.
+func (p *Pricer) Version() string {
+	return p.load().version
+}
+
+// catalogVersion hashes the catalog's snapshot. encoding/json sorts map keys,
+// so the encoding, and therefore the hash, is deterministic.
+func catalogVersion(c *catalog) string {
+	data, err := json.Marshal(c.snapshot())
+	if err != nil {
+		// The catalog holds only plain values, so this is unreachable in practice.
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8])
+}
@@ -0,0 +1,158 @@
+package pricing_db
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+// =============================================================================
+// Catalog Version Tests
+// =============================================================================
+
+func versionTestFS(outputPrice string) fstest.MapFS {
+	return fstest.MapFS{
+		"configs/acme_pricing.json": &fstest.MapFile{Data: []byte(`{
+			"models": {"acme-1": {"input_per_million": 1.0, "output_per_million": ` + outputPrice + `}}
+		}`)},
+	}
+}
+
+func TestVersion_Deterministic(t *testing.T) {
+	a, err := NewPricer()
+	if err != nil {
+		t.Fatalf("NewPricer failed: %v", err)
+	}
+	b, err := NewPricer()
+	if err != nil {
+		t.Fatalf("NewPricer failed: %v", err)
+	}
+	if a.Version() != b.Version() || len(a.Version()) != 16 {
+		t.Errorf("expected equal 16-digit versions, got %q and %q", a.Version(), b.Version())
+	}
+	if CatalogVersion() != a.Version() {
+		t.Errorf("CatalogVersion: expected %q, got %q", a.Version(), CatalogVersion())
+	}
+
+	// Options that do not change costs leave the version alone
+	c, err := NewPricer(WithLookupCache(8))
+	if err != nil {
+		t.Fatalf("NewPricer failed: %v", err)
+	}
+	if c.Version() != a.Version() {
+		t.Errorf("expected WithLookupCache to leave the version unchanged, got %q vs %q", c.Version(), a.Version())
+	}
+
+	// Settings that change computed costs are part of it
+	for name, opt := range map[string]Option{
+		"WithDefaultCacheMultiplier": WithDefaultCacheMultiplier(0.5),
+		"WithRoundingPolicy":         WithRoundingPolicy(RoundingPolicy{Mode: RoundHalfUp, Precision: 2}),
+	} {
+		d, err := NewPricer(opt)
+		if err != nil {
+			t.Fatalf("NewPricer(%s) failed: %v", name, err)
+		}
+		if d.Version() == a.Version() {
+			t.Errorf("expected %s to change the version", name)
+		}
+	}
+}
+
+func TestVersion_SnapshotRoundTrip(t *testing.T) {
+	p, err := NewPricer()
+	if err != nil {
+		t.Fatalf("NewPricer failed: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := p.Export(&buf); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	loaded, err := NewPricerFromSnapshot(&buf)
+	if err != nil {
+		t.Fatalf("NewPricerFromSnapshot failed: %v", err)
+	}
+	if loaded.Version() != p.Version() {
+		t.Errorf("expected snapshot to keep version %q, got %q", p.Version(), loaded.Version())
+	}
+}
+
+func TestVersion_ChangesWithPrices(t *testing.T) {
+	p, err := NewPricerFromFS(versionTestFS("2.0"), "configs")
+	if err != nil {
+		t.Fatalf("NewPricerFromFS failed: %v", err)
+	}
+	before := p.Version()
+
+	if err := p.Reload(versionTestFS("2.0"), "configs"); err != nil {
+		t.Fatalf("Reload failed: %v", err)
+	}
+	if p.Version() != before {
+		t.Errorf("reloading identical configs changed the version: %q -> %q", before, p.Version())
+	}
+
+	if err := p.Reload(versionTestFS("3.0"), "configs"); err != nil {
+		t.Fatalf("Reload failed: %v", err)
+	}
+	if p.Version() == before {
+		t.Error("expected a new version after a price change")
+	}
+}
+
+func TestCostDetails_PricingVersion(t *testing.T) {
+	p, err := NewPricer()
+	if err != nil {
+		t.Fatalf("NewPricer failed: %v", err)
+	}
+	gemini := GeminiResponse{
+		ModelVersion:  "gemini-2.5-flash",
+		Candidates:    []GeminiCandidate{{}, {}},
+		UsageMetadata: GeminiUsageMetadata{PromptTokenCount: 1000, CandidatesTokenCount: 500},
+	}
+	results := map[string]CostDetails{
+		"CalculateUsage":           p.CalculateUsage("gpt-4o", TokenUsage{PromptTokens: 1000}, nil),
+		"unknown model":            p.CalculateUsage("no-such-model", TokenUsage{PromptTokens: 1000}, nil),
+		"CalculateRealtimeSession": p.CalculateRealtimeSession("gpt-4o", RealtimeUsage{TextInputTokens: 1000}),
+		"Gemini candidate":         p.CalculateGeminiResponseCandidates(gemini, "", nil).Candidates[1],
+		"EstimateTextCost":         p.EstimateTextCost("gpt-4o", "hello", 10, nil),
+	}
+	for name, d := range results {
+		if d.PricingVersion != p.Version() {
+			t.Errorf("%s: expected PricingVersion %q, got %q", name, p.Version(), d.PricingVersion)
+		}
+		if !d.CalculatedAt.IsZero() {
+			t.Errorf("%s: expected no timestamp by default, got %v", name, d.CalculatedAt)
+		}
+	}
+
+	data, err := json.Marshal(results["CalculateUsage"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "CalculatedAt") {
+		t.Errorf("expected zero CalculatedAt to be omitted from JSON, got %s", data)
+	}
+}
+
+func TestWithCalculationTimestamps(t *testing.T) {
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.FixedZone("EST", -5*3600))
+	stubNow(t, now)
+
+	p, err := NewPricerFromFS(versionTestFS("2.0"), "configs", WithCalculationTimestamps())
+	if err != nil {
+		t.Fatalf("NewPricerFromFS failed: %v", err)
+	}
+	for _, d := range []CostDetails{
+		p.CalculateUsage("acme-1", TokenUsage{PromptTokens: 1000}, nil),
+		p.CalculateUsage("no-such-model", TokenUsage{PromptTokens: 1000}, nil),
+	} {
+		if !d.CalculatedAt.Equal(now) || d.CalculatedAt.Location() != time.UTC {
+			t.Errorf("expected CalculatedAt %v in UTC, got %v", now, d.CalculatedAt)
+		}
+		if d.PricingVersion != p.Version() {
+			t.Errorf("expected PricingVersion %q, got %q", p.Version(), d.PricingVersion)
+		}
+	}
+}